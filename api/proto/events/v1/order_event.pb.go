@@ -0,0 +1,601 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.4
+// source: api/proto/events/v1/order_event.proto
+
+package eventsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// OrderEvent is the protobuf wire form of messaging.OrderEvent.
+type OrderEvent struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	EventId         string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	EventType       string                 `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	OrderId         string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	CustomerId      string                 `protobuf:"bytes,4,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	Status          string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	OldStatus       string                 `protobuf:"bytes,6,opt,name=old_status,json=oldStatus,proto3" json:"old_status,omitempty"`
+	NewStatus       string                 `protobuf:"bytes,7,opt,name=new_status,json=newStatus,proto3" json:"new_status,omitempty"`
+	Total           float64                `protobuf:"fixed64,8,opt,name=total,proto3" json:"total,omitempty"`
+	Version         int64                  `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`
+	Items           []*OrderLine           `protobuf:"bytes,10,rep,name=items,proto3" json:"items,omitempty"`
+	ShippingAddress *Address               `protobuf:"bytes,11,opt,name=shipping_address,json=shippingAddress,proto3" json:"shipping_address,omitempty"`
+	OccurredAt      *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	CancelReason    string                 `protobuf:"bytes,13,opt,name=cancel_reason,json=cancelReason,proto3" json:"cancel_reason,omitempty"`
+	Replayed        bool                   `protobuf:"varint,14,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	SchemaVersion   int32                  `protobuf:"varint,15,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	Currency        string                 `protobuf:"bytes,16,opt,name=currency,proto3" json:"currency,omitempty"`
+	TotalMinor      int64                  `protobuf:"varint,17,opt,name=total_minor,json=totalMinor,proto3" json:"total_minor,omitempty"`
+	CorrelationId   string                 `protobuf:"bytes,18,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	ChangedFields   []string               `protobuf:"bytes,19,rep,name=changed_fields,json=changedFields,proto3" json:"changed_fields,omitempty"`
+	Seq             int64                  `protobuf:"varint,20,opt,name=seq,proto3" json:"seq,omitempty"`
+	Metadata        map[string]string      `protobuf:"bytes,21,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CausationId     string                 `protobuf:"bytes,22,opt,name=causation_id,json=causationId,proto3" json:"causation_id,omitempty"`
+	Changes         map[string]*ChangePair `protobuf:"bytes,23,rep,name=changes,proto3" json:"changes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *OrderEvent) Reset() {
+	*x = OrderEvent{}
+	mi := &file_api_proto_events_v1_order_event_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderEvent) ProtoMessage() {}
+
+func (x *OrderEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_events_v1_order_event_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderEvent.ProtoReflect.Descriptor instead.
+func (*OrderEvent) Descriptor() ([]byte, []int) {
+	return file_api_proto_events_v1_order_event_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OrderEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *OrderEvent) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *OrderEvent) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderEvent) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *OrderEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *OrderEvent) GetOldStatus() string {
+	if x != nil {
+		return x.OldStatus
+	}
+	return ""
+}
+
+func (x *OrderEvent) GetNewStatus() string {
+	if x != nil {
+		return x.NewStatus
+	}
+	return ""
+}
+
+func (x *OrderEvent) GetTotal() float64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *OrderEvent) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *OrderEvent) GetItems() []*OrderLine {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *OrderEvent) GetShippingAddress() *Address {
+	if x != nil {
+		return x.ShippingAddress
+	}
+	return nil
+}
+
+func (x *OrderEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *OrderEvent) GetCancelReason() string {
+	if x != nil {
+		return x.CancelReason
+	}
+	return ""
+}
+
+func (x *OrderEvent) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+func (x *OrderEvent) GetSchemaVersion() int32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *OrderEvent) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *OrderEvent) GetTotalMinor() int64 {
+	if x != nil {
+		return x.TotalMinor
+	}
+	return 0
+}
+
+func (x *OrderEvent) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *OrderEvent) GetChangedFields() []string {
+	if x != nil {
+		return x.ChangedFields
+	}
+	return nil
+}
+
+func (x *OrderEvent) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *OrderEvent) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *OrderEvent) GetCausationId() string {
+	if x != nil {
+		return x.CausationId
+	}
+	return ""
+}
+
+func (x *OrderEvent) GetChanges() map[string]*ChangePair {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+// OrderLine is a line item carried in an OrderEvent.
+type OrderLine struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Sku            string                 `protobuf:"bytes,1,opt,name=sku,proto3" json:"sku,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Quantity       int64                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	UnitPrice      float64                `protobuf:"fixed64,4,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	Subtotal       float64                `protobuf:"fixed64,5,opt,name=subtotal,proto3" json:"subtotal,omitempty"`
+	UnitPriceMinor int64                  `protobuf:"varint,6,opt,name=unit_price_minor,json=unitPriceMinor,proto3" json:"unit_price_minor,omitempty"`
+	SubtotalMinor  int64                  `protobuf:"varint,7,opt,name=subtotal_minor,json=subtotalMinor,proto3" json:"subtotal_minor,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *OrderLine) Reset() {
+	*x = OrderLine{}
+	mi := &file_api_proto_events_v1_order_event_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderLine) ProtoMessage() {}
+
+func (x *OrderLine) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_events_v1_order_event_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderLine.ProtoReflect.Descriptor instead.
+func (*OrderLine) Descriptor() ([]byte, []int) {
+	return file_api_proto_events_v1_order_event_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OrderLine) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *OrderLine) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OrderLine) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderLine) GetUnitPrice() float64 {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return 0
+}
+
+func (x *OrderLine) GetSubtotal() float64 {
+	if x != nil {
+		return x.Subtotal
+	}
+	return 0
+}
+
+func (x *OrderLine) GetUnitPriceMinor() int64 {
+	if x != nil {
+		return x.UnitPriceMinor
+	}
+	return 0
+}
+
+func (x *OrderLine) GetSubtotalMinor() int64 {
+	if x != nil {
+		return x.SubtotalMinor
+	}
+	return 0
+}
+
+// Address is a postal address carried in an OrderEvent.
+type Address struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Line1         string                 `protobuf:"bytes,1,opt,name=line1,proto3" json:"line1,omitempty"`
+	Line2         string                 `protobuf:"bytes,2,opt,name=line2,proto3" json:"line2,omitempty"`
+	City          string                 `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"`
+	Region        string                 `protobuf:"bytes,4,opt,name=region,proto3" json:"region,omitempty"`
+	PostalCode    string                 `protobuf:"bytes,5,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	Country       string                 `protobuf:"bytes,6,opt,name=country,proto3" json:"country,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_api_proto_events_v1_order_event_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_events_v1_order_event_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_api_proto_events_v1_order_event_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Address) GetLine1() string {
+	if x != nil {
+		return x.Line1
+	}
+	return ""
+}
+
+func (x *Address) GetLine2() string {
+	if x != nil {
+		return x.Line2
+	}
+	return ""
+}
+
+func (x *Address) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Address) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *Address) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *Address) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+// ChangePair is a field's value before and after an order.updated, each as
+// the JSON text of the field in the JSON envelope.
+type ChangePair struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Old           string                 `protobuf:"bytes,1,opt,name=old,proto3" json:"old,omitempty"`
+	New           string                 `protobuf:"bytes,2,opt,name=new,proto3" json:"new,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePair) Reset() {
+	*x = ChangePair{}
+	mi := &file_api_proto_events_v1_order_event_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePair) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePair) ProtoMessage() {}
+
+func (x *ChangePair) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_events_v1_order_event_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePair.ProtoReflect.Descriptor instead.
+func (*ChangePair) Descriptor() ([]byte, []int) {
+	return file_api_proto_events_v1_order_event_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ChangePair) GetOld() string {
+	if x != nil {
+		return x.Old
+	}
+	return ""
+}
+
+func (x *ChangePair) GetNew() string {
+	if x != nil {
+		return x.New
+	}
+	return ""
+}
+
+var File_api_proto_events_v1_order_event_proto protoreflect.FileDescriptor
+
+const file_api_proto_events_v1_order_event_proto_rawDesc = "" +
+	"\n" +
+	"%api/proto/events/v1/order_event.proto\x12\tevents.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe7\a\n" +
+	"\n" +
+	"OrderEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tR\teventType\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x1f\n" +
+	"\vcustomer_id\x18\x04 \x01(\tR\n" +
+	"customerId\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"old_status\x18\x06 \x01(\tR\toldStatus\x12\x1d\n" +
+	"\n" +
+	"new_status\x18\a \x01(\tR\tnewStatus\x12\x14\n" +
+	"\x05total\x18\b \x01(\x01R\x05total\x12\x18\n" +
+	"\aversion\x18\t \x01(\x03R\aversion\x12*\n" +
+	"\x05items\x18\n" +
+	" \x03(\v2\x14.events.v1.OrderLineR\x05items\x12=\n" +
+	"\x10shipping_address\x18\v \x01(\v2\x12.events.v1.AddressR\x0fshippingAddress\x12;\n" +
+	"\voccurred_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12#\n" +
+	"\rcancel_reason\x18\r \x01(\tR\fcancelReason\x12\x1a\n" +
+	"\breplayed\x18\x0e \x01(\bR\breplayed\x12%\n" +
+	"\x0eschema_version\x18\x0f \x01(\x05R\rschemaVersion\x12\x1a\n" +
+	"\bcurrency\x18\x10 \x01(\tR\bcurrency\x12\x1f\n" +
+	"\vtotal_minor\x18\x11 \x01(\x03R\n" +
+	"totalMinor\x12%\n" +
+	"\x0ecorrelation_id\x18\x12 \x01(\tR\rcorrelationId\x12%\n" +
+	"\x0echanged_fields\x18\x13 \x03(\tR\rchangedFields\x12\x10\n" +
+	"\x03seq\x18\x14 \x01(\x03R\x03seq\x12?\n" +
+	"\bmetadata\x18\x15 \x03(\v2#.events.v1.OrderEvent.MetadataEntryR\bmetadata\x12!\n" +
+	"\fcausation_id\x18\x16 \x01(\tR\vcausationId\x12<\n" +
+	"\achanges\x18\x17 \x03(\v2\".events.v1.OrderEvent.ChangesEntryR\achanges\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aQ\n" +
+	"\fChangesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12+\n" +
+	"\x05value\x18\x02 \x01(\v2\x15.events.v1.ChangePairR\x05value:\x028\x01\"\xd9\x01\n" +
+	"\tOrderLine\x12\x10\n" +
+	"\x03sku\x18\x01 \x01(\tR\x03sku\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x03R\bquantity\x12\x1d\n" +
+	"\n" +
+	"unit_price\x18\x04 \x01(\x01R\tunitPrice\x12\x1a\n" +
+	"\bsubtotal\x18\x05 \x01(\x01R\bsubtotal\x12(\n" +
+	"\x10unit_price_minor\x18\x06 \x01(\x03R\x0eunitPriceMinor\x12%\n" +
+	"\x0esubtotal_minor\x18\a \x01(\x03R\rsubtotalMinor\"\x9c\x01\n" +
+	"\aAddress\x12\x14\n" +
+	"\x05line1\x18\x01 \x01(\tR\x05line1\x12\x14\n" +
+	"\x05line2\x18\x02 \x01(\tR\x05line2\x12\x12\n" +
+	"\x04city\x18\x03 \x01(\tR\x04city\x12\x16\n" +
+	"\x06region\x18\x04 \x01(\tR\x06region\x12\x1f\n" +
+	"\vpostal_code\x18\x05 \x01(\tR\n" +
+	"postalCode\x12\x18\n" +
+	"\acountry\x18\x06 \x01(\tR\acountry\"0\n" +
+	"\n" +
+	"ChangePair\x12\x10\n" +
+	"\x03old\x18\x01 \x01(\tR\x03old\x12\x10\n" +
+	"\x03new\x18\x02 \x01(\tR\x03newBKZIgithub.com/sridharn-code-sandbox/go-ordersvc/api/proto/events/v1;eventsv1b\x06proto3"
+
+var (
+	file_api_proto_events_v1_order_event_proto_rawDescOnce sync.Once
+	file_api_proto_events_v1_order_event_proto_rawDescData []byte
+)
+
+func file_api_proto_events_v1_order_event_proto_rawDescGZIP() []byte {
+	file_api_proto_events_v1_order_event_proto_rawDescOnce.Do(func() {
+		file_api_proto_events_v1_order_event_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_events_v1_order_event_proto_rawDesc), len(file_api_proto_events_v1_order_event_proto_rawDesc)))
+	})
+	return file_api_proto_events_v1_order_event_proto_rawDescData
+}
+
+var file_api_proto_events_v1_order_event_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_api_proto_events_v1_order_event_proto_goTypes = []any{
+	(*OrderEvent)(nil),            // 0: events.v1.OrderEvent
+	(*OrderLine)(nil),             // 1: events.v1.OrderLine
+	(*Address)(nil),               // 2: events.v1.Address
+	(*ChangePair)(nil),            // 3: events.v1.ChangePair
+	nil,                           // 4: events.v1.OrderEvent.MetadataEntry
+	nil,                           // 5: events.v1.OrderEvent.ChangesEntry
+	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+}
+var file_api_proto_events_v1_order_event_proto_depIdxs = []int32{
+	1, // 0: events.v1.OrderEvent.items:type_name -> events.v1.OrderLine
+	2, // 1: events.v1.OrderEvent.shipping_address:type_name -> events.v1.Address
+	6, // 2: events.v1.OrderEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	4, // 3: events.v1.OrderEvent.metadata:type_name -> events.v1.OrderEvent.MetadataEntry
+	5, // 4: events.v1.OrderEvent.changes:type_name -> events.v1.OrderEvent.ChangesEntry
+	3, // 5: events.v1.OrderEvent.ChangesEntry.value:type_name -> events.v1.ChangePair
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_events_v1_order_event_proto_init() }
+func file_api_proto_events_v1_order_event_proto_init() {
+	if File_api_proto_events_v1_order_event_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_events_v1_order_event_proto_rawDesc), len(file_api_proto_events_v1_order_event_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_events_v1_order_event_proto_goTypes,
+		DependencyIndexes: file_api_proto_events_v1_order_event_proto_depIdxs,
+		MessageInfos:      file_api_proto_events_v1_order_event_proto_msgTypes,
+	}.Build()
+	File_api_proto_events_v1_order_event_proto = out.File
+	file_api_proto_events_v1_order_event_proto_goTypes = nil
+	file_api_proto_events_v1_order_event_proto_depIdxs = nil
+}
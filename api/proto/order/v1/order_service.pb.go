@@ -22,6 +22,186 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type CreateOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CustomerId    string                 `protobuf:"bytes,1,opt,name=customer_id,json=customerId,proto3" json:"customer_id,omitempty"`
+	Items         []*CreateOrderItem     `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrderRequest) Reset() {
+	*x = CreateOrderRequest{}
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrderRequest) ProtoMessage() {}
+
+func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrderRequest.ProtoReflect.Descriptor instead.
+func (*CreateOrderRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateOrderRequest) GetCustomerId() string {
+	if x != nil {
+		return x.CustomerId
+	}
+	return ""
+}
+
+func (x *CreateOrderRequest) GetItems() []*CreateOrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *CreateOrderRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type CreateOrderItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	Currency      string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrderItem) Reset() {
+	*x = CreateOrderItem{}
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrderItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrderItem) ProtoMessage() {}
+
+func (x *CreateOrderItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrderItem.ProtoReflect.Descriptor instead.
+func (*CreateOrderItem) Descriptor() ([]byte, []int) {
+	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateOrderItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *CreateOrderItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateOrderItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *CreateOrderItem) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *CreateOrderItem) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type CreateOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrderResponse) Reset() {
+	*x = CreateOrderResponse{}
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrderResponse) ProtoMessage() {}
+
+func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrderResponse.ProtoReflect.Descriptor instead.
+func (*CreateOrderResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
 type GetOrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
@@ -31,7 +211,7 @@ type GetOrderRequest struct {
 
 func (x *GetOrderRequest) Reset() {
 	*x = GetOrderRequest{}
-	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[0]
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -43,7 +223,7 @@ func (x *GetOrderRequest) String() string {
 func (*GetOrderRequest) ProtoMessage() {}
 
 func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[0]
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -56,7 +236,7 @@ func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
 func (*GetOrderRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{0}
+	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *GetOrderRequest) GetOrderId() string {
@@ -75,7 +255,7 @@ type GetOrderResponse struct {
 
 func (x *GetOrderResponse) Reset() {
 	*x = GetOrderResponse{}
-	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[1]
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -87,7 +267,7 @@ func (x *GetOrderResponse) String() string {
 func (*GetOrderResponse) ProtoMessage() {}
 
 func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[1]
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -100,7 +280,7 @@ func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrderResponse.ProtoReflect.Descriptor instead.
 func (*GetOrderResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{1}
+	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetOrderResponse) GetOrder() *Order {
@@ -122,7 +302,7 @@ type ListOrdersRequest struct {
 
 func (x *ListOrdersRequest) Reset() {
 	*x = ListOrdersRequest{}
-	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[2]
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -134,7 +314,7 @@ func (x *ListOrdersRequest) String() string {
 func (*ListOrdersRequest) ProtoMessage() {}
 
 func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[2]
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -147,7 +327,7 @@ func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
 func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{2}
+	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ListOrdersRequest) GetPage() int32 {
@@ -191,7 +371,7 @@ type ListOrdersResponse struct {
 
 func (x *ListOrdersResponse) Reset() {
 	*x = ListOrdersResponse{}
-	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[3]
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -203,7 +383,7 @@ func (x *ListOrdersResponse) String() string {
 func (*ListOrdersResponse) ProtoMessage() {}
 
 func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[3]
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -216,7 +396,7 @@ func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
 func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{3}
+	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ListOrdersResponse) GetOrders() []*Order {
@@ -254,6 +434,102 @@ func (x *ListOrdersResponse) GetTotalPages() int32 {
 	return 0
 }
 
+type UpdateStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateStatusRequest) Reset() {
+	*x = UpdateStatusRequest{}
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateStatusRequest) ProtoMessage() {}
+
+func (x *UpdateStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateStatusRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateStatusRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *UpdateStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type UpdateStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateStatusResponse) Reset() {
+	*x = UpdateStatusResponse{}
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateStatusResponse) ProtoMessage() {}
+
+func (x *UpdateStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateStatusResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateStatusResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
 type WatchOrdersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Statuses      []string               `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
@@ -263,7 +539,7 @@ type WatchOrdersRequest struct {
 
 func (x *WatchOrdersRequest) Reset() {
 	*x = WatchOrdersRequest{}
-	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[4]
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -275,7 +551,7 @@ func (x *WatchOrdersRequest) String() string {
 func (*WatchOrdersRequest) ProtoMessage() {}
 
 func (x *WatchOrdersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[4]
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -288,7 +564,7 @@ func (x *WatchOrdersRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WatchOrdersRequest.ProtoReflect.Descriptor instead.
 func (*WatchOrdersRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{4}
+	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *WatchOrdersRequest) GetStatuses() []string {
@@ -308,13 +584,14 @@ type Order struct {
 	Version       int32                  `protobuf:"varint,6,opt,name=version,proto3" json:"version,omitempty"`
 	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Currency      string                 `protobuf:"bytes,9,opt,name=currency,proto3" json:"currency,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Order) Reset() {
 	*x = Order{}
-	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[5]
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -326,7 +603,7 @@ func (x *Order) String() string {
 func (*Order) ProtoMessage() {}
 
 func (x *Order) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[5]
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -339,7 +616,7 @@ func (x *Order) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Order.ProtoReflect.Descriptor instead.
 func (*Order) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{5}
+	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *Order) GetId() string {
@@ -398,6 +675,13 @@ func (x *Order) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Order) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
 type OrderItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -412,7 +696,7 @@ type OrderItem struct {
 
 func (x *OrderItem) Reset() {
 	*x = OrderItem{}
-	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[6]
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -424,7 +708,7 @@ func (x *OrderItem) String() string {
 func (*OrderItem) ProtoMessage() {}
 
 func (x *OrderItem) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[6]
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -437,7 +721,7 @@ func (x *OrderItem) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OrderItem.ProtoReflect.Descriptor instead.
 func (*OrderItem) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{6}
+	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *OrderItem) GetId() string {
@@ -493,13 +777,14 @@ type OrderEvent struct {
 	Total         float64                `protobuf:"fixed64,7,opt,name=total,proto3" json:"total,omitempty"`
 	Version       int32                  `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
 	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	Currency      string                 `protobuf:"bytes,10,opt,name=currency,proto3" json:"currency,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *OrderEvent) Reset() {
 	*x = OrderEvent{}
-	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[7]
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -511,7 +796,7 @@ func (x *OrderEvent) String() string {
 func (*OrderEvent) ProtoMessage() {}
 
 func (x *OrderEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[7]
+	mi := &file_api_proto_order_v1_order_service_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -524,7 +809,7 @@ func (x *OrderEvent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OrderEvent.ProtoReflect.Descriptor instead.
 func (*OrderEvent) Descriptor() ([]byte, []int) {
-	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{7}
+	return file_api_proto_order_v1_order_service_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *OrderEvent) GetEventType() string {
@@ -590,11 +875,32 @@ func (x *OrderEvent) GetOccurredAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *OrderEvent) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
 var File_api_proto_order_v1_order_service_proto protoreflect.FileDescriptor
 
 const file_api_proto_order_v1_order_service_proto_rawDesc = "" +
 	"\n" +
-	"&api/proto/order/v1/order_service.proto\x12\border.v1\x1a\x1fgoogle/protobuf/timestamp.proto\",\n" +
+	"&api/proto/order/v1/order_service.proto\x12\border.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x82\x01\n" +
+	"\x12CreateOrderRequest\x12\x1f\n" +
+	"\vcustomer_id\x18\x01 \x01(\tR\n" +
+	"customerId\x12/\n" +
+	"\x05items\x18\x02 \x03(\v2\x19.order.v1.CreateOrderItemR\x05items\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\"\x92\x01\n" +
+	"\x0fCreateOrderItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\"<\n" +
+	"\x13CreateOrderResponse\x12%\n" +
+	"\x05order\x18\x01 \x01(\v2\x0f.order.v1.OrderR\x05order\",\n" +
 	"\x0fGetOrderRequest\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\"9\n" +
 	"\x10GetOrderResponse\x12%\n" +
@@ -612,9 +918,14 @@ const file_api_proto_order_v1_order_service_proto_rawDesc = "" +
 	"\vtotal_count\x18\x04 \x01(\x03R\n" +
 	"totalCount\x12\x1f\n" +
 	"\vtotal_pages\x18\x05 \x01(\x05R\n" +
-	"totalPages\"0\n" +
+	"totalPages\"H\n" +
+	"\x13UpdateStatusRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"=\n" +
+	"\x14UpdateStatusResponse\x12%\n" +
+	"\x05order\x18\x01 \x01(\v2\x0f.order.v1.OrderR\x05order\"0\n" +
 	"\x12WatchOrdersRequest\x12\x1a\n" +
-	"\bstatuses\x18\x01 \x03(\tR\bstatuses\"\xa1\x02\n" +
+	"\bstatuses\x18\x01 \x03(\tR\bstatuses\"\xbd\x02\n" +
 	"\x05Order\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1f\n" +
 	"\vcustomer_id\x18\x02 \x01(\tR\n" +
@@ -626,7 +937,8 @@ const file_api_proto_order_v1_order_service_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x9c\x01\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1a\n" +
+	"\bcurrency\x18\t \x01(\tR\bcurrency\"\x9c\x01\n" +
 	"\tOrderItem\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
 	"\n" +
@@ -634,7 +946,7 @@ const file_api_proto_order_v1_order_service_proto_rawDesc = "" +
 	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1a\n" +
 	"\bquantity\x18\x04 \x01(\x05R\bquantity\x12\x14\n" +
 	"\x05price\x18\x05 \x01(\x01R\x05price\x12\x1a\n" +
-	"\bsubtotal\x18\x06 \x01(\x01R\bsubtotal\"\xaa\x02\n" +
+	"\bsubtotal\x18\x06 \x01(\x01R\bsubtotal\"\xc6\x02\n" +
 	"\n" +
 	"OrderEvent\x12\x1d\n" +
 	"\n" +
@@ -650,11 +962,15 @@ const file_api_proto_order_v1_order_service_proto_rawDesc = "" +
 	"\x05total\x18\a \x01(\x01R\x05total\x12\x18\n" +
 	"\aversion\x18\b \x01(\x05R\aversion\x12;\n" +
 	"\voccurred_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
-	"occurredAt2\xdf\x01\n" +
-	"\fOrderService\x12A\n" +
+	"occurredAt\x12\x1a\n" +
+	"\bcurrency\x18\n" +
+	" \x01(\tR\bcurrency2\xfa\x02\n" +
+	"\fOrderService\x12J\n" +
+	"\vCreateOrder\x12\x1c.order.v1.CreateOrderRequest\x1a\x1d.order.v1.CreateOrderResponse\x12A\n" +
 	"\bGetOrder\x12\x19.order.v1.GetOrderRequest\x1a\x1a.order.v1.GetOrderResponse\x12G\n" +
 	"\n" +
-	"ListOrders\x12\x1b.order.v1.ListOrdersRequest\x1a\x1c.order.v1.ListOrdersResponse\x12C\n" +
+	"ListOrders\x12\x1b.order.v1.ListOrdersRequest\x1a\x1c.order.v1.ListOrdersResponse\x12M\n" +
+	"\fUpdateStatus\x12\x1d.order.v1.UpdateStatusRequest\x1a\x1e.order.v1.UpdateStatusResponse\x12C\n" +
 	"\vWatchOrders\x12\x1c.order.v1.WatchOrdersRequest\x1a\x14.order.v1.OrderEvent0\x01BIZGgithub.com/sridharn-code-sandbox/go-ordersvc/api/proto/order/v1;orderv1b\x06proto3"
 
 var (
@@ -669,36 +985,48 @@ func file_api_proto_order_v1_order_service_proto_rawDescGZIP() []byte {
 	return file_api_proto_order_v1_order_service_proto_rawDescData
 }
 
-var file_api_proto_order_v1_order_service_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_api_proto_order_v1_order_service_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_api_proto_order_v1_order_service_proto_goTypes = []any{
-	(*GetOrderRequest)(nil),       // 0: order.v1.GetOrderRequest
-	(*GetOrderResponse)(nil),      // 1: order.v1.GetOrderResponse
-	(*ListOrdersRequest)(nil),     // 2: order.v1.ListOrdersRequest
-	(*ListOrdersResponse)(nil),    // 3: order.v1.ListOrdersResponse
-	(*WatchOrdersRequest)(nil),    // 4: order.v1.WatchOrdersRequest
-	(*Order)(nil),                 // 5: order.v1.Order
-	(*OrderItem)(nil),             // 6: order.v1.OrderItem
-	(*OrderEvent)(nil),            // 7: order.v1.OrderEvent
-	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+	(*CreateOrderRequest)(nil),    // 0: order.v1.CreateOrderRequest
+	(*CreateOrderItem)(nil),       // 1: order.v1.CreateOrderItem
+	(*CreateOrderResponse)(nil),   // 2: order.v1.CreateOrderResponse
+	(*GetOrderRequest)(nil),       // 3: order.v1.GetOrderRequest
+	(*GetOrderResponse)(nil),      // 4: order.v1.GetOrderResponse
+	(*ListOrdersRequest)(nil),     // 5: order.v1.ListOrdersRequest
+	(*ListOrdersResponse)(nil),    // 6: order.v1.ListOrdersResponse
+	(*UpdateStatusRequest)(nil),   // 7: order.v1.UpdateStatusRequest
+	(*UpdateStatusResponse)(nil),  // 8: order.v1.UpdateStatusResponse
+	(*WatchOrdersRequest)(nil),    // 9: order.v1.WatchOrdersRequest
+	(*Order)(nil),                 // 10: order.v1.Order
+	(*OrderItem)(nil),             // 11: order.v1.OrderItem
+	(*OrderEvent)(nil),            // 12: order.v1.OrderEvent
+	(*timestamppb.Timestamp)(nil), // 13: google.protobuf.Timestamp
 }
 var file_api_proto_order_v1_order_service_proto_depIdxs = []int32{
-	5, // 0: order.v1.GetOrderResponse.order:type_name -> order.v1.Order
-	5, // 1: order.v1.ListOrdersResponse.orders:type_name -> order.v1.Order
-	6, // 2: order.v1.Order.items:type_name -> order.v1.OrderItem
-	8, // 3: order.v1.Order.created_at:type_name -> google.protobuf.Timestamp
-	8, // 4: order.v1.Order.updated_at:type_name -> google.protobuf.Timestamp
-	8, // 5: order.v1.OrderEvent.occurred_at:type_name -> google.protobuf.Timestamp
-	0, // 6: order.v1.OrderService.GetOrder:input_type -> order.v1.GetOrderRequest
-	2, // 7: order.v1.OrderService.ListOrders:input_type -> order.v1.ListOrdersRequest
-	4, // 8: order.v1.OrderService.WatchOrders:input_type -> order.v1.WatchOrdersRequest
-	1, // 9: order.v1.OrderService.GetOrder:output_type -> order.v1.GetOrderResponse
-	3, // 10: order.v1.OrderService.ListOrders:output_type -> order.v1.ListOrdersResponse
-	7, // 11: order.v1.OrderService.WatchOrders:output_type -> order.v1.OrderEvent
-	9, // [9:12] is the sub-list for method output_type
-	6, // [6:9] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	1,  // 0: order.v1.CreateOrderRequest.items:type_name -> order.v1.CreateOrderItem
+	10, // 1: order.v1.CreateOrderResponse.order:type_name -> order.v1.Order
+	10, // 2: order.v1.GetOrderResponse.order:type_name -> order.v1.Order
+	10, // 3: order.v1.ListOrdersResponse.orders:type_name -> order.v1.Order
+	10, // 4: order.v1.UpdateStatusResponse.order:type_name -> order.v1.Order
+	11, // 5: order.v1.Order.items:type_name -> order.v1.OrderItem
+	13, // 6: order.v1.Order.created_at:type_name -> google.protobuf.Timestamp
+	13, // 7: order.v1.Order.updated_at:type_name -> google.protobuf.Timestamp
+	13, // 8: order.v1.OrderEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	0,  // 9: order.v1.OrderService.CreateOrder:input_type -> order.v1.CreateOrderRequest
+	3,  // 10: order.v1.OrderService.GetOrder:input_type -> order.v1.GetOrderRequest
+	5,  // 11: order.v1.OrderService.ListOrders:input_type -> order.v1.ListOrdersRequest
+	7,  // 12: order.v1.OrderService.UpdateStatus:input_type -> order.v1.UpdateStatusRequest
+	9,  // 13: order.v1.OrderService.WatchOrders:input_type -> order.v1.WatchOrdersRequest
+	2,  // 14: order.v1.OrderService.CreateOrder:output_type -> order.v1.CreateOrderResponse
+	4,  // 15: order.v1.OrderService.GetOrder:output_type -> order.v1.GetOrderResponse
+	6,  // 16: order.v1.OrderService.ListOrders:output_type -> order.v1.ListOrdersResponse
+	8,  // 17: order.v1.OrderService.UpdateStatus:output_type -> order.v1.UpdateStatusResponse
+	12, // 18: order.v1.OrderService.WatchOrders:output_type -> order.v1.OrderEvent
+	14, // [14:19] is the sub-list for method output_type
+	9,  // [9:14] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_order_v1_order_service_proto_init() }
@@ -712,7 +1040,7 @@ func file_api_proto_order_v1_order_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_order_v1_order_service_proto_rawDesc), len(file_api_proto_order_v1_order_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -16,23 +16,41 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/cache/redis"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/config"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
 	grpcHandler "github.com/sridharn-code-sandbox/go-ordersvc/internal/handler/grpc"
 	httpHandler "github.com/sridharn-code-sandbox/go-ordersvc/internal/handler/http"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/breaker"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/bus"
 	kafkapub "github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/kafka"
-	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/noop"
+	msgmetrics "github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/metrics"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/multi"
+	msgotel "github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/otel"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/outbox"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/ratelimit"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/middleware"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository/postgres"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository/replica"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/service"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
 )
 
@@ -52,8 +70,15 @@ type Server struct {
 	cfg         *config.Config
 	logger      *slog.Logger
 	dbPool      *pgxpool.Pool
+	replicaPool *pgxpool.Pool
 	redisCloser func() error
-	kafkaCloser func() error
+	kafkaCloser func(context.Context) error
+	relay       *outbox.Relay
+	expiry      *service.ExpiryWorker
+	metricsAgg  *service.MetricsAggregator
+	workersCtx  context.Context // Cancelled to stop the relay, expiry worker and metrics rollup
+	stopWorkers context.CancelFunc
+	workers     sync.WaitGroup
 }
 
 // NewServer creates a new server instance
@@ -64,38 +89,35 @@ func NewServer(cfg *config.Config) *Server {
 	}))
 	slog.SetDefault(logger)
 
-	// Initialize PostgreSQL connection pool
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
-		cfg.Database.User,
-		cfg.Database.Password,
-		cfg.Database.Host,
-		cfg.Database.Port,
-		cfg.Database.Database,
-		cfg.Database.SSLMode,
-	)
-
-	poolCfg, err := pgxpool.ParseConfig(dsn)
-	if err != nil {
-		logger.Error("failed to parse database config", slog.String("error", err.Error()))
+	if !domain.ValidCurrency(cfg.App.BaseCurrency) {
+		logger.Error("invalid base currency", slog.String("currency", cfg.App.BaseCurrency))
 		os.Exit(1)
 	}
-	poolCfg.MaxConns = safeInt32(cfg.Database.MaxOpenConns)
-	poolCfg.MinConns = safeInt32(cfg.Database.MaxIdleConns)
-	poolCfg.MaxConnLifetime = cfg.Database.ConnMaxLifetime
-	poolCfg.MaxConnIdleTime = cfg.Database.ConnMaxIdleTime
-
-	dbPool, err := pgxpool.NewWithConfig(context.Background(), poolCfg)
+	ids, err := domain.ParseIDGenerator(cfg.App.IDStrategy)
 	if err != nil {
-		logger.Error("failed to create database pool", slog.String("error", err.Error()))
+		logger.Error("invalid order ID strategy", slog.String("strategy", cfg.App.IDStrategy), slog.String("error", err.Error()))
 		os.Exit(1)
 	}
 
-	if err := dbPool.Ping(context.Background()); err != nil {
-		logger.Error("failed to ping database", slog.String("error", err.Error()))
+	// Initialize PostgreSQL connection pool
+	dbPool, err := openDBPool(cfg.Database, cfg.Database.Host, cfg.Database.Port)
+	if err != nil {
+		logger.Error("failed to connect to PostgreSQL", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
 	logger.Info("connected to PostgreSQL", slog.String("host", cfg.Database.Host), slog.Int("port", cfg.Database.Port))
 
+	var replicaPool *pgxpool.Pool
+	if cfg.Database.ReplicaHost != "" {
+		replicaPool, err = openDBPool(cfg.Database, cfg.Database.ReplicaHost, cfg.Database.ReplicaPort)
+		if err != nil {
+			logger.Error("failed to connect to PostgreSQL replica", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		logger.Info("connected to PostgreSQL replica", slog.String("host", cfg.Database.ReplicaHost),
+			slog.Int("port", cfg.Database.ReplicaPort))
+	}
+
 	// Initialize Redis client
 	redisClient, err := redis.NewClient(redis.Config{
 		Host:     cfg.Redis.Host,
@@ -109,32 +131,199 @@ func NewServer(cfg *config.Config) *Server {
 	}
 	logger.Info("connected to Redis", slog.String("host", cfg.Redis.Host), slog.Int("port", cfg.Redis.Port))
 
+	// Trace publishes and propagate W3C trace context in Kafka headers.
+	// Spans are exported by whatever TracerProvider is registered globally.
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	// Publish and outbox metrics, exposed on /metrics
+	pipelineMetrics, err := msgmetrics.NewPrometheus(prometheus.DefaultRegisterer)
+	if err != nil {
+		logger.Error("failed to register publish metrics", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Committed events also go to an in-process bus, which streams them to
+	// HTTP clients of GET /api/v1/orders/stream
+	eventBus := bus.New()
+
 	// Initialize event publisher
 	var publisher service.EventPublisher
-	var kafkaCloser func() error
+	var tracedByKafka bool
+	var kafkaCloser func(context.Context) error
+	var kafkaChecker httpHandler.HealthChecker
+	var relay *outbox.Relay
+	var metricsPub messaging.MetricsPublisher
+	var serviceOpts []service.Option
 	if len(cfg.Kafka.Brokers) > 0 && cfg.Kafka.Brokers[0] != "" {
-		kp := kafkapub.NewPublisher(cfg.Kafka.Brokers, cfg.Kafka.Topic)
-		publisher = kp
+		compression, err := kafkapub.ParseCompression(cfg.Kafka.Compression)
+		if err != nil {
+			logger.Error("invalid KAFKA_COMPRESSION", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		acks, err := kafkapub.ParseAcks(cfg.Kafka.Acks)
+		if err != nil {
+			logger.Error("invalid KAFKA_ACKS", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		authOpts, err := kafkaAuthOptions(cfg.Kafka)
+		if err != nil {
+			logger.Error("invalid Kafka TLS or SASL settings", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		// Startup waits, when configured, for any one broker to answer, so
+		// the service can start while part of the cluster is down
+		if cfg.Kafka.StartupAttempts > 0 {
+			authOpts = append(authOpts, kafkapub.WithStartupRetry(cfg.Kafka.StartupAttempts, cfg.Kafka.StartupRetryDelay))
+		}
+		kp, err := kafkapub.New(cfg.Kafka.Brokers, cfg.Kafka.Topic, append(authOpts,
+			kafkapub.WithCompression(compression),
+			kafkapub.WithAcks(acks),
+			kafkapub.WithRetry(cfg.Kafka.RetryMaxAttempts, cfg.Kafka.RetryBaseDelay),
+			kafkapub.WithWriteTimeout(cfg.Kafka.WriteTimeout),
+			kafkapub.WithDeadLetter(cfg.Kafka.DeadLetterTopic),
+			kafkapub.WithDeadLetterFile(cfg.Kafka.DeadLetterFile),
+			kafkapub.WithMetricsTopic(cfg.Kafka.MetricsTopic),
+			kafkapub.WithTracer(otel.Tracer("github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/kafka")),
+			kafkapub.WithMetrics(pipelineMetrics),
+			kafkapub.WithLogger(logger))...)
+		if errors.Is(err, kafkapub.ErrNoBrokersReachable) {
+			logger.Error("no Kafka broker reachable", slog.Any("brokers", cfg.Kafka.Brokers),
+				slog.Int("attempts", cfg.Kafka.StartupAttempts), slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		if err != nil {
+			logger.Error("invalid Kafka publisher settings", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		metricsPub = kp
+		// The breaker fails publishes fast while Kafka is down, instead of
+		// each paying the retries and write timeout
+		var kafkaPub kafkaSender = kp
+		if cfg.Kafka.BreakerThreshold > 0 {
+			cb := breaker.Wrap(kp, breaker.Settings{FailureThreshold: cfg.Kafka.BreakerThreshold, Cooldown: cfg.Kafka.BreakerCooldown})
+			if err := msgmetrics.RegisterBreaker(prometheus.DefaultRegisterer, cb); err != nil {
+				logger.Error("failed to register circuit breaker metrics", slog.String("error", err.Error()))
+				os.Exit(1)
+			}
+			kafkaPub = cb
+			logger.Info("Kafka circuit breaker enabled", slog.Int("threshold", cfg.Kafka.BreakerThreshold),
+				slog.Duration("cooldown", cfg.Kafka.BreakerCooldown))
+		}
+		// The limiter keeps a burst of writes from flooding the shared
+		// cluster; limited publishes wait rather than fail
+		if cfg.Kafka.PublishRate > 0 {
+			limiter := rate.NewLimiter(rate.Limit(cfg.Kafka.PublishRate), max(cfg.Kafka.PublishBurst, 1))
+			kafkaPub = ratelimit.Wrap(kafkaPub, limiter).(kafkaSender)
+			logger.Info("Kafka publish rate limit enabled", slog.Float64("rate", cfg.Kafka.PublishRate),
+				slog.Int("burst", cfg.Kafka.PublishBurst))
+		}
+		publisher = multi.New(kafkaPub, eventBus)
+		tracedByKafka = true
 		kafkaCloser = kp.Close
-		logger.Info("Kafka publisher initialized", slog.Any("brokers", cfg.Kafka.Brokers), slog.String("topic", cfg.Kafka.Topic))
+		kafkaChecker = kp
+		logger.Info("Kafka publisher initialized", slog.Any("brokers", cfg.Kafka.Brokers), slog.String("topic", cfg.Kafka.Topic),
+			slog.String("compression", compression.String()), slog.String("acks", acks.String()),
+			slog.Bool("tls", cfg.Kafka.TLSEnabled), slog.String("sasl", cfg.Kafka.SASLMechanism))
+
+		if cfg.Kafka.OutboxEnabled {
+			outboxStore := postgres.NewOutboxStore(dbPool)
+			publisher = outbox.NewPublisher(outboxStore)
+			tracedByKafka = false
+			// The bus is fed by the relay, so it only sees committed events
+			relay = outbox.NewRelay(outboxStore, streamingSender{Sender: kafkaPub, bus: eventBus}, cfg.Kafka.OutboxPollInterval)
+			relay.SetMetrics(pipelineMetrics)
+			relay.SetLogger(logger)
+			serviceOpts = append(serviceOpts, service.WithTransactor(postgres.NewTransactor(dbPool)))
+			logger.Info("transactional outbox enabled")
+		}
 	} else {
-		publisher = noop.Publisher{}
-		logger.Info("Kafka not configured, using no-op publisher")
+		publisher = eventBus
+		logger.Info("Kafka not configured, publishing to in-process subscribers only")
+	}
+
+	// The Kafka publisher traces its own writes; other publishers (outbox,
+	// in-process bus) get a span from the decorator
+	if !tracedByKafka {
+		publisher = msgotel.Wrap(publisher, otel.Tracer("github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"))
 	}
 
 	// Create repository and cache
-	repo := postgres.NewOrderRepository(dbPool)
+	repoOpts := []postgres.Option{
+		postgres.WithBaseCurrency(cfg.App.BaseCurrency),
+		postgres.WithIDGenerator(ids),
+	}
+	repo := postgres.NewOrderRepository(dbPool, repoOpts...)
+	if replicaPool != nil {
+		// Listings read from the replica; gets and writes stay on the primary
+		repo = replica.Split(repo, postgres.NewOrderRepository(replicaPool, repoOpts...))
+	}
 	orderCache := redis.NewOrderCache(redisClient)
+	serviceOpts = append(serviceOpts,
+		service.WithIdempotencyStore(redis.NewIdempotencyStore(redisClient), cfg.Cache.IdempotencyTTL),
+		service.WithBaseCurrency(cfg.App.BaseCurrency),
+		service.WithIDGenerator(ids),
+		service.WithLogger(logger))
+	if cfg.App.MaxOpenOrders > 0 {
+		serviceOpts = append(serviceOpts, service.WithOrderQuota(cfg.App.MaxOpenOrders, postgres.NewTransactor(dbPool)))
+		logger.Info("order quota enabled", slog.Int("max_open_per_customer", cfg.App.MaxOpenOrders))
+	}
 
 	// Create service
-	orderService := service.NewOrderService(repo, orderCache, publisher)
+	orderService := service.NewOrderService(repo, orderCache, publisher, serviceOpts...)
+
+	var expiry *service.ExpiryWorker
+	if cfg.Expiry.TTL > 0 {
+		expiry = service.NewExpiryWorker(repo, postgres.NewTransactor(dbPool), orderCache, publisher,
+			cfg.Expiry.TTL, cfg.Expiry.ScanInterval, service.WithExpiryBatchSize(cfg.Expiry.BatchSize),
+			service.WithExpiryLogger(logger))
+		logger.Info("order expiry enabled", slog.Duration("ttl", cfg.Expiry.TTL))
+	}
+
+	var metricsAgg *service.MetricsAggregator
+	if cfg.Metrics.Interval > 0 {
+		if metricsPub == nil {
+			logger.Error("ORDER_METRICS_INTERVAL needs Kafka to publish the rollups to")
+			os.Exit(1)
+		}
+		metricsAgg = service.NewMetricsAggregator(
+			postgres.NewOrderMetricsStore(dbPool, postgres.WithBaseCurrency(cfg.App.BaseCurrency)),
+			postgres.NewTransactor(dbPool), metricsPub, cfg.Metrics.Interval, service.WithMetricsLogger(logger))
+		logger.Info("order metrics rollup enabled", slog.Duration("interval", cfg.Metrics.Interval),
+			slog.String("topic", cfg.Kafka.MetricsTopic))
+	}
 
 	// Create HTTP handlers
-	orderHandler := httpHandler.NewOrderHandler(orderService)
-	healthHandler := httpHandler.NewHealthHandler(cfg.App.Version, &pgHealthChecker{pool: dbPool})
+	orderHandler := httpHandler.NewOrderHandler(orderService, httpHandler.WithEventStream(eventBus))
+	healthHandler := httpHandler.NewHealthHandler(cfg.App.Version,
+		httpHandler.HealthCheck{Name: "database", Checker: &pgHealthChecker{pool: dbPool}},
+		httpHandler.HealthCheck{Name: "kafka", Checker: kafkaChecker})
 
 	// Create router with logger
-	router := httpHandler.NewRouter(orderHandler, healthHandler, logger)
+	apiMiddleware := []func(http.Handler) http.Handler{
+		middleware.MaxBodySize(cfg.Server.MaxBodyBytes),
+	}
+	if cfg.Server.RequireJSON {
+		apiMiddleware = append(apiMiddleware, middleware.RequireJSON())
+	}
+	if cfg.Server.ActorHeader != "" {
+		apiMiddleware = append(apiMiddleware, middleware.Actor(cfg.Server.ActorHeader))
+		logger.Info("recording actors from request header", slog.String("header", cfg.Server.ActorHeader))
+	}
+	if cfg.Server.APIKeys != "" {
+		// After Actor, so the authenticated principal is the actor
+		keys, err := middleware.ParseAPIKeys(cfg.Server.APIKeys)
+		if err != nil {
+			logger.Error("invalid AUTH_API_KEYS", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		apiMiddleware = append(apiMiddleware, middleware.APIKeyAuth(keys, logger))
+		logger.Info("API key authentication enabled")
+	}
+	// After APIKeyAuth, so authenticated clients are limited per principal
+	apiMiddleware = append(apiMiddleware, middleware.RateLimit(rateLimitConfig(cfg.RateLimit)))
+	router := httpHandler.NewRouter(orderHandler, healthHandler, logger, apiMiddleware...)
+	msgmetrics.Handle(router, prometheus.DefaultGatherer)
 
 	// Create HTTP server
 	httpServer := &http.Server{
@@ -143,10 +332,14 @@ func NewServer(cfg *config.Config) *Server {
 		ReadTimeout:  cfg.Server.ReadTimeout,
 		WriteTimeout: cfg.Server.WriteTimeout,
 	}
+	// End event streams on shutdown, or they would hold it until the deadline
+	httpServer.RegisterOnShutdown(eventBus.Close)
 
 	// Create gRPC server
 	grpcSrv := grpc.NewServer()
-	grpcHandler.RegisterOrderServer(grpcSrv, orderService, cfg.Kafka)
+	grpcHandler.RegisterOrderServer(grpcSrv, orderService, cfg.Kafka, logger)
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
 
 	return &Server{
 		httpServer:  httpServer,
@@ -154,13 +347,49 @@ func NewServer(cfg *config.Config) *Server {
 		cfg:         cfg,
 		logger:      logger,
 		dbPool:      dbPool,
+		replicaPool: replicaPool,
 		redisCloser: redisClient.Close,
 		kafkaCloser: kafkaCloser,
+		relay:       relay,
+		expiry:      expiry,
+		metricsAgg:  metricsAgg,
+		workersCtx:  workersCtx,
+		stopWorkers: stopWorkers,
 	}
 }
 
 // Start starts the HTTP and gRPC servers
 func (s *Server) Start() error {
+	// Start outbox relay in background
+	if s.relay != nil {
+		s.workers.Add(1)
+		go func() {
+			defer s.workers.Done()
+			s.logger.Info("starting outbox relay", slog.Duration("poll_interval", s.cfg.Kafka.OutboxPollInterval))
+			s.relay.Run(s.workersCtx)
+		}()
+	}
+
+	// Start order expiry in background
+	if s.expiry != nil {
+		s.workers.Add(1)
+		go func() {
+			defer s.workers.Done()
+			s.logger.Info("starting order expiry", slog.Duration("scan_interval", s.cfg.Expiry.ScanInterval))
+			s.expiry.Run(s.workersCtx)
+		}()
+	}
+
+	// Start the order metrics rollup in background
+	if s.metricsAgg != nil {
+		s.workers.Add(1)
+		go func() {
+			defer s.workers.Done()
+			s.logger.Info("starting order metrics rollup", slog.Duration("interval", s.cfg.Metrics.Interval))
+			s.metricsAgg.Run(s.workersCtx)
+		}()
+	}
+
 	// Start gRPC server in background
 	go func() {
 		lis, err := net.Listen("tcp", fmt.Sprintf(":%d", s.cfg.Server.GRPCPort))
@@ -181,20 +410,66 @@ func (s *Server) Start() error {
 	return nil
 }
 
-// Shutdown gracefully shuts down the server and closes connections
+// Shutdown drains the server in dependency order, bounded by ctx: it
+// stops accepting HTTP and gRPC requests and waits for in-flight ones,
+// stops the outbox relay, expiry worker and metrics rollup, flushes the
+// Kafka publisher, and only then closes Redis and the database pool that
+// in-flight work still needed.
+//
+// It returns the errors from draining requests and flushing Kafka; the
+// remaining steps run even if ctx ends first.
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("shutting down server")
 
+	// gRPC and HTTP drain concurrently, sharing the deadline
+	grpcStopped := make(chan struct{})
 	if s.grpcServer != nil {
 		s.logger.Info("stopping gRPC server")
-		s.grpcServer.GracefulStop()
+		go func() {
+			defer close(grpcStopped)
+			s.grpcServer.GracefulStop()
+		}()
+	}
+
+	s.logger.Info("stopping HTTP server")
+	httpErr := s.httpServer.Shutdown(ctx)
+	if httpErr != nil {
+		s.logger.Error("in-flight HTTP requests did not finish", slog.String("error", httpErr.Error()))
 	}
 
-	err := s.httpServer.Shutdown(ctx)
+	if s.grpcServer != nil {
+		select {
+		case <-grpcStopped:
+		case <-ctx.Done():
+			// Stop cancels the remaining calls, so GracefulStop returns
+			s.logger.Error("in-flight gRPC calls did not finish", slog.String("error", ctx.Err().Error()))
+			s.grpcServer.Stop()
+			<-grpcStopped
+		}
+	}
 
-	if s.dbPool != nil {
-		s.logger.Info("closing database connection pool")
-		s.dbPool.Close()
+	// Stop the relay, expiry worker and metrics rollup before closing the
+	// pool and publisher they use
+	if s.stopWorkers != nil {
+		s.logger.Info("stopping background workers")
+		s.stopWorkers()
+		stopped := make(chan struct{})
+		go func() {
+			s.workers.Wait()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+		}
+	}
+
+	var kafkaErr error
+	if s.kafkaCloser != nil {
+		s.logger.Info("flushing Kafka publisher")
+		if kafkaErr = s.kafkaCloser(ctx); kafkaErr != nil {
+			s.logger.Error("failed to flush Kafka publisher", slog.String("error", kafkaErr.Error()))
+		}
 	}
 
 	if s.redisCloser != nil {
@@ -204,37 +479,101 @@ func (s *Server) Shutdown(ctx context.Context) error {
 		}
 	}
 
-	if s.kafkaCloser != nil {
-		s.logger.Info("closing Kafka publisher")
-		if kafkaErr := s.kafkaCloser(); kafkaErr != nil {
-			s.logger.Error("failed to close Kafka publisher", slog.String("error", kafkaErr.Error()))
-		}
+	if s.dbPool != nil {
+		s.logger.Info("closing database connection pool")
+		s.dbPool.Close()
+	}
+	if s.replicaPool != nil {
+		s.replicaPool.Close()
 	}
 
-	return err
+	return errors.Join(httpErr, kafkaErr)
 }
 
-// Run starts the server and handles graceful shutdown
+// Run starts the server and shuts it down gracefully on SIGINT or SIGTERM,
+// allowing up to cfg.Server.ShutdownTimeout for in-flight work to drain.
+// It also shuts down if the HTTP server fails to start.
 func Run(cfg *config.Config) error {
 	server := NewServer(cfg)
 
-	// Start server in a goroutine
-	go func() {
-		if err := server.Start(); err != nil {
+	sigCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	startErr := make(chan error, 1)
+	go func() { startErr <- server.Start() }()
+
+	var err error
+	select {
+	case <-sigCtx.Done():
+		server.logger.Info("received shutdown signal")
+	case err = <-startErr:
+		if err != nil {
 			server.logger.Error("server error", slog.String("error", err.Error()))
 		}
-	}()
-
-	// Wait for interrupt signal
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	}
+	stop() // A second signal kills the process
 
-	// Graceful shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
-	return server.Shutdown(ctx)
+	return errors.Join(err, server.Shutdown(ctx))
+}
+
+// kafkaSender publishes to Kafka for both the service and the outbox
+// relay.
+type kafkaSender interface {
+	service.EventPublisher
+	outbox.Sender
+}
+
+// streamingSender relays outbox events to Kafka and then, once Kafka has
+// them, to the in-process bus.
+type streamingSender struct {
+	outbox.Sender
+	bus *bus.Bus
+}
+
+func (s streamingSender) Publish(ctx context.Context, evt messaging.OrderEvent) error {
+	if err := s.Sender.Publish(ctx, evt); err != nil {
+		return err
+	}
+	// A failing subscriber must not make the relay resend the event to
+	// Kafka
+	_ = s.bus.Publish(ctx, evt)
+	return nil
+}
+
+// openDBPool connects to the database of db at host and port, and checks
+// that the connection works.
+func openDBPool(db config.DatabaseConfig, host string, port int) (*pgxpool.Pool, error) {
+	dsn := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
+		db.User,
+		db.Password,
+		host,
+		port,
+		db.Database,
+		db.SSLMode,
+	)
+
+	poolCfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("parse database config: %w", err)
+	}
+	poolCfg.MaxConns = safeInt32(db.MaxOpenConns)
+	poolCfg.MinConns = safeInt32(db.MaxIdleConns)
+	poolCfg.MaxConnLifetime = db.ConnMaxLifetime
+	poolCfg.MaxConnIdleTime = db.ConnMaxIdleTime
+	postgres.SetStatementTimeout(poolCfg, db.StatementTimeout)
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("create database pool: %w", err)
+	}
+	if err := pool.Ping(context.Background()); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+	return pool, nil
 }
 
 // safeInt32 converts int to int32 with clamping to prevent overflow.
@@ -248,3 +587,47 @@ func safeInt32(v int) int32 {
 	}
 	return int32(v) // #nosec G115 -- bounds checked above
 }
+
+// kafkaAuthOptions returns the publisher options connecting over TLS and
+// authenticating with SASL, as enabled in cfg.
+func kafkaAuthOptions(cfg config.KafkaConfig) ([]kafkapub.Option, error) {
+	var opts []kafkapub.Option
+	if cfg.TLSEnabled {
+		tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+		if cfg.TLSCAFile != "" {
+			pem, err := os.ReadFile(cfg.TLSCAFile)
+			if err != nil {
+				return nil, fmt.Errorf("read KAFKA_TLS_CA_FILE: %w", err)
+			}
+			tlsCfg.RootCAs = x509.NewCertPool()
+			if !tlsCfg.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("KAFKA_TLS_CA_FILE %s: no PEM certificates", cfg.TLSCAFile)
+			}
+		}
+		opts = append(opts, kafkapub.WithTLS(tlsCfg))
+	}
+	if cfg.SASLMechanism != "" {
+		mechanism, err := kafkapub.ParseSASLMechanism(cfg.SASLMechanism)
+		if err != nil {
+			return nil, fmt.Errorf("KAFKA_SASL_MECHANISM: %w", err)
+		}
+		opts = append(opts, kafkapub.WithSASL(mechanism, cfg.SASLUsername, cfg.SASLPassword))
+	}
+	return opts, nil
+}
+
+// rateLimitConfig builds the API rate limits from cfg: a default for every
+// route, tightened for order creation when a create limit is set.
+func rateLimitConfig(cfg config.RateLimitConfig) middleware.RateLimitConfig {
+	rl := middleware.RateLimitConfig{
+		Default: middleware.Limit{PerSecond: cfg.RequestsPerSecond, Burst: cfg.Burst},
+	}
+	if cfg.CreatePerSecond > 0 {
+		rl.Routes = append(rl.Routes, middleware.RouteLimit{
+			Method:  http.MethodPost,
+			Pattern: "/api/v1/orders",
+			Limit:   middleware.Limit{PerSecond: cfg.CreatePerSecond, Burst: cfg.CreateBurst},
+		})
+	}
+	return rl
+}
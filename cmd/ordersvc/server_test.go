@@ -0,0 +1,151 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shutdownLog records the order shutdown steps happen in.
+type shutdownLog struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+func (l *shutdownLog) add(step string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.steps = append(l.steps, step)
+}
+
+func (l *shutdownLog) all() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.steps...)
+}
+
+// newDrainServer serves handler over HTTP on a local port, with a Kafka
+// closer that records when it runs. It returns the server and its URL.
+func newDrainServer(t *testing.T, handler http.Handler, log *shutdownLog) (*Server, string) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &Server{
+		httpServer: &http.Server{Handler: handler, ReadHeaderTimeout: time.Second},
+		logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+		kafkaCloser: func(context.Context) error {
+			log.add("kafka flushed")
+			return nil
+		},
+	}
+	go func() { _ = s.httpServer.Serve(lis) }()
+	return s, "http://" + lis.Addr().String()
+}
+
+// blockingHandler answers 200 once release is closed, signalling started
+// when a request arrives.
+func blockingHandler(started chan<- struct{}, release <-chan struct{}, log *shutdownLog) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		log.add("request completed")
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, "created")
+	})
+}
+
+func TestServer_Shutdown_CompletesInFlightRequest(t *testing.T) {
+	log := &shutdownLog{}
+	started, release := make(chan struct{}), make(chan struct{})
+	s, url := newDrainServer(t, blockingHandler(started, release, log), log)
+
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	resp := make(chan result, 1)
+	go func() {
+		r, err := http.Post(url+"/api/v1/orders", "application/json", nil)
+		if err != nil {
+			resp <- result{err: err}
+			return
+		}
+		defer r.Body.Close()
+		body, err := io.ReadAll(r.Body)
+		resp <- result{status: r.StatusCode, body: string(body), err: err}
+	}()
+	<-started
+
+	shutdownErr := make(chan error, 1)
+	go func() { shutdownErr <- s.Shutdown(context.Background()) }()
+
+	// New connections are refused while the request is in flight
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", url[len("http://"):])
+		if err == nil {
+			_ = conn.Close()
+		}
+		return err != nil
+	}, time.Second, 5*time.Millisecond)
+	select {
+	case err := <-shutdownErr:
+		t.Fatalf("shutdown returned before the in-flight request finished: %v", err)
+	default:
+	}
+
+	close(release)
+
+	r := <-resp
+	require.NoError(t, r.err)
+	assert.Equal(t, http.StatusOK, r.status)
+	assert.Equal(t, "created", r.body)
+	require.NoError(t, <-shutdownErr)
+	assert.Equal(t, []string{"request completed", "kafka flushed"}, log.all(),
+		"Kafka must be flushed after in-flight requests finish")
+}
+
+func TestServer_Shutdown_DeadlineExceeded_StillFlushesKafka(t *testing.T) {
+	log := &shutdownLog{}
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	s, url := newDrainServer(t, blockingHandler(started, release, log), log)
+
+	go func() {
+		r, err := http.Get(url + "/api/v1/orders")
+		if err == nil {
+			_ = r.Body.Close()
+		}
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := s.Shutdown(ctx)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []string{"kafka flushed"}, log.all())
+}
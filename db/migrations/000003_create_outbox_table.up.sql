@@ -0,0 +1,15 @@
+-- Transactional outbox for order events (ADR-0006).
+-- Rows are written in the same transaction as the order change and
+-- delivered to Kafka by the relay worker in seq order.
+CREATE TABLE IF NOT EXISTS outbox (
+    seq BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL UNIQUE,  -- Event ID, dedupes relay retries
+    order_id UUID NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Covers: WHERE published_at IS NULL ORDER BY seq
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON outbox(seq) WHERE published_at IS NULL;
@@ -0,0 +1,7 @@
+-- Restore created_at-only sort indexes
+CREATE INDEX IF NOT EXISTS idx_orders_created_at ON orders(created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_orders_customer_created ON orders(customer_id, created_at DESC) WHERE deleted_at IS NULL;
+
+-- Drop keyset pagination indexes
+DROP INDEX IF EXISTS idx_orders_created_id;
+DROP INDEX IF EXISTS idx_orders_customer_created_id;
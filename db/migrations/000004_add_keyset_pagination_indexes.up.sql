@@ -0,0 +1,13 @@
+-- Extend list indexes with id as a tiebreaker so keyset (cursor)
+-- pagination on (created_at, id) is served from the index.
+
+-- Covers: WHERE deleted_at IS NULL AND (created_at, id) < ($1, $2) ORDER BY created_at DESC, id DESC
+-- Replaces: idx_orders_created_at
+CREATE INDEX IF NOT EXISTS idx_orders_created_id ON orders(created_at DESC, id DESC) WHERE deleted_at IS NULL;
+
+-- Covers: WHERE customer_id = $1 AND deleted_at IS NULL AND (created_at, id) < ($2, $3) ORDER BY created_at DESC, id DESC
+-- Replaces: idx_orders_customer_created
+CREATE INDEX IF NOT EXISTS idx_orders_customer_created_id ON orders(customer_id, created_at DESC, id DESC) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_orders_created_at;
+DROP INDEX IF EXISTS idx_orders_customer_created;
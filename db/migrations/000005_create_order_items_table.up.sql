@@ -0,0 +1,38 @@
+-- Move order line items out of the orders.items JSONB column into a child
+-- table so they can be queried and constrained individually. Subtotals are
+-- derived (quantity * unit_price) and not stored.
+CREATE TABLE IF NOT EXISTS order_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,  -- Preserves item order within the order
+    product_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL,
+    unit_price DECIMAL(10, 2) NOT NULL,
+
+    CONSTRAINT positive_quantity CHECK (quantity > 0),
+    CONSTRAINT unique_item_position UNIQUE (order_id, position)
+);
+
+-- Backfill from the JSONB column (keys are the Go field names of domain.OrderItem)
+INSERT INTO order_items (id, order_id, position, product_id, name, quantity, unit_price)
+SELECT COALESCE((item->>'ID')::uuid, gen_random_uuid()),
+       o.id,
+       t.ord - 1,
+       item->>'ProductID',
+       item->>'Name',
+       (item->>'Quantity')::integer,
+       (item->>'Price')::decimal
+FROM orders o
+CROSS JOIN LATERAL jsonb_array_elements(o.items) WITH ORDINALITY AS t(item, ord);
+
+-- Orders created before items were tracked only carry a total. Give them a
+-- single line for that amount so the recomputed total matches.
+INSERT INTO order_items (order_id, position, product_id, name, quantity, unit_price)
+SELECT o.id, 0, 'legacy', 'Legacy order total', 1, o.total
+FROM orders o
+WHERE o.total > 0
+  AND NOT EXISTS (SELECT 1 FROM order_items i WHERE i.order_id = o.id);
+
+DROP INDEX IF EXISTS idx_orders_items;
+ALTER TABLE orders DROP COLUMN IF EXISTS items;
@@ -0,0 +1,3 @@
+-- Record why an order was cancelled. Empty for orders that were not
+-- cancelled, or were cancelled without a reason.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS cancel_reason TEXT NOT NULL DEFAULT '';
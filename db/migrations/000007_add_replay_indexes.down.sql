@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_orders_updated_at_id;
+DROP INDEX IF EXISTS idx_outbox_created_at;
@@ -0,0 +1,4 @@
+-- Support replaying events for a time range: outbox history by creation
+-- time, and current-state snapshots of orders by last change.
+CREATE INDEX IF NOT EXISTS idx_outbox_created_at ON outbox(created_at);
+CREATE INDEX IF NOT EXISTS idx_orders_updated_at_id ON orders(updated_at, id);
@@ -0,0 +1,4 @@
+-- Record the ISO 4217 currency of each order's total and item prices.
+-- Existing rows stay NULL and are read as the configured base currency
+-- (APP_BASE_CURRENCY), so the migration does not have to know it.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS currency CHAR(3);
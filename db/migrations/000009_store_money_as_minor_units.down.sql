@@ -0,0 +1,33 @@
+-- Restore the DECIMAL(10, 2) amounts from the minor-unit columns. Amounts
+-- of currencies with more than 2 decimals are rounded to 2.
+CREATE FUNCTION minor_unit_factor(code TEXT) RETURNS INTEGER
+LANGUAGE SQL IMMUTABLE AS $$
+    SELECT CASE
+        WHEN code IN ('ADP', 'AFN', 'ALL', 'AMD', 'BIF', 'BYR', 'CLP', 'COP', 'DJF', 'ESP',
+                      'GNF', 'GYD', 'IDR', 'IQD', 'IRR', 'ISK', 'ITL', 'JPY', 'KMF', 'KPW',
+                      'KRW', 'LAK', 'LBP', 'LUF', 'MGA', 'MGF', 'MMK', 'MNT', 'MRO', 'MUR',
+                      'PKR', 'PYG', 'RSD', 'RWF', 'SLL', 'SOS', 'STD', 'SYP', 'TMM', 'TRL',
+                      'TZS', 'UGX', 'UYI', 'UZS', 'VND', 'VUV', 'XAF', 'XOF', 'XPF', 'YER',
+                      'ZMK', 'ZWD') THEN 1
+        WHEN code IN ('BHD', 'JOD', 'KWD', 'LYD', 'OMR', 'TND') THEN 1000
+        WHEN code = 'CLF' THEN 10000
+        ELSE 100
+    END
+$$;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS total DECIMAL(10, 2);
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS unit_price DECIMAL(10, 2);
+
+UPDATE orders SET total = total_minor::DECIMAL / minor_unit_factor(currency);
+
+UPDATE order_items i
+SET unit_price = i.unit_price_minor::DECIMAL / minor_unit_factor(o.currency)
+FROM orders o
+WHERE o.id = i.order_id;
+
+DROP FUNCTION minor_unit_factor(TEXT);
+
+ALTER TABLE orders ALTER COLUMN total SET NOT NULL;
+ALTER TABLE orders DROP COLUMN IF EXISTS total_minor;
+ALTER TABLE order_items ALTER COLUMN unit_price SET NOT NULL;
+ALTER TABLE order_items DROP COLUMN IF EXISTS unit_price_minor;
@@ -0,0 +1,39 @@
+-- Store amounts as integer minor units (cents for USD) instead of DECIMAL,
+-- so an order total is the exact sum of its items' quantity * unit price.
+--
+-- Each amount is scaled by its currency's minor unit as the service
+-- defines it (domain.MinorUnits): no decimals for JPY and the like, 3 for
+-- BHD and the like, 4 for CLF, 2 for every other currency. Rows with no
+-- currency are in the base currency, assumed to have 2 decimals, which is
+-- all the DECIMAL(10, 2) columns could hold anyway.
+CREATE FUNCTION minor_unit_factor(code TEXT) RETURNS INTEGER
+LANGUAGE SQL IMMUTABLE AS $$
+    SELECT CASE
+        WHEN code IN ('ADP', 'AFN', 'ALL', 'AMD', 'BIF', 'BYR', 'CLP', 'COP', 'DJF', 'ESP',
+                      'GNF', 'GYD', 'IDR', 'IQD', 'IRR', 'ISK', 'ITL', 'JPY', 'KMF', 'KPW',
+                      'KRW', 'LAK', 'LBP', 'LUF', 'MGA', 'MGF', 'MMK', 'MNT', 'MRO', 'MUR',
+                      'PKR', 'PYG', 'RSD', 'RWF', 'SLL', 'SOS', 'STD', 'SYP', 'TMM', 'TRL',
+                      'TZS', 'UGX', 'UYI', 'UZS', 'VND', 'VUV', 'XAF', 'XOF', 'XPF', 'YER',
+                      'ZMK', 'ZWD') THEN 1
+        WHEN code IN ('BHD', 'JOD', 'KWD', 'LYD', 'OMR', 'TND') THEN 1000
+        WHEN code = 'CLF' THEN 10000
+        ELSE 100
+    END
+$$;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS total_minor BIGINT;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS unit_price_minor BIGINT;
+
+UPDATE orders SET total_minor = ROUND(total * minor_unit_factor(currency));
+
+UPDATE order_items i
+SET unit_price_minor = ROUND(i.unit_price * minor_unit_factor(o.currency))
+FROM orders o
+WHERE o.id = i.order_id;
+
+DROP FUNCTION minor_unit_factor(TEXT);
+
+ALTER TABLE orders ALTER COLUMN total_minor SET NOT NULL;
+ALTER TABLE orders DROP COLUMN IF EXISTS total;
+ALTER TABLE order_items ALTER COLUMN unit_price_minor SET NOT NULL;
+ALTER TABLE order_items DROP COLUMN IF EXISTS unit_price;
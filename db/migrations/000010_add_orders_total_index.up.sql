@@ -0,0 +1,3 @@
+-- Support filtering order lists by a total range. customer_id, status and
+-- created_at filters are served by the composite sort indexes.
+CREATE INDEX IF NOT EXISTS idx_orders_total ON orders(total_minor) WHERE deleted_at IS NULL;
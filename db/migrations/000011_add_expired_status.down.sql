@@ -0,0 +1,5 @@
+-- Move expired orders to cancelled so the restored constraint holds.
+UPDATE orders SET status = 'cancelled', cancel_reason = 'expired' WHERE status = 'expired';
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS valid_status;
+ALTER TABLE orders ADD CONSTRAINT valid_status
+    CHECK (status IN ('pending', 'confirmed', 'processing', 'shipped', 'delivered', 'cancelled'));
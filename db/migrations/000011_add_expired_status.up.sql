@@ -0,0 +1,6 @@
+-- Allow the expired status, set on orders left pending past the expiry
+-- TTL. The expiry scan over pending orders by created_at is served by
+-- idx_orders_status_created.
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS valid_status;
+ALTER TABLE orders ADD CONSTRAINT valid_status
+    CHECK (status IN ('pending', 'confirmed', 'processing', 'shipped', 'delivered', 'cancelled', 'expired'));
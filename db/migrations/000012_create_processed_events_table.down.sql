@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_processed_events_processed_at;
+DROP TABLE IF EXISTS processed_events;
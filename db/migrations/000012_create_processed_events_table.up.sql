@@ -0,0 +1,9 @@
+-- Event IDs processed by idempotent consumers (dedupe.Deduplicate).
+-- Rows older than the store's TTL no longer count and are purged.
+CREATE TABLE IF NOT EXISTS processed_events (
+    event_id TEXT PRIMARY KEY,
+    processed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Covers: DELETE ... WHERE processed_at <= cutoff
+CREATE INDEX IF NOT EXISTS idx_processed_events_processed_at ON processed_events(processed_at);
@@ -0,0 +1,14 @@
+-- One row per order status transition, written in the same transaction as
+-- the orders row update. occurred_at matches the OccurredAt of the event
+-- published for the transition.
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    old_status VARCHAR(50) NOT NULL,
+    new_status VARCHAR(50) NOT NULL,
+    changed_by VARCHAR(255) NOT NULL DEFAULT '',  -- Empty when the actor is unknown
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Covers: WHERE order_id = $1 ORDER BY occurred_at, id
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id ON order_status_history(order_id, occurred_at, id);
@@ -0,0 +1,6 @@
+-- Sequence number of each order's latest event, incremented with every
+-- write that publishes one so consumers can detect missed events. Existing
+-- orders start from their version, which has so far advanced with every
+-- event.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS event_seq BIGINT NOT NULL DEFAULT 0;
+UPDATE orders SET event_seq = version WHERE event_seq = 0;
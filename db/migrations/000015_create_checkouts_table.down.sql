@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_checkouts_unfinished;
+DROP TABLE IF EXISTS checkouts;
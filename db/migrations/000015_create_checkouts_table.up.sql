@@ -0,0 +1,16 @@
+-- One row per checkout saga, updated as it completes each step, so a
+-- checkout interrupted by a crash can be finished or undone. order_data is
+-- the order being checked out; it reaches the orders table only when the
+-- checkout completes.
+CREATE TABLE IF NOT EXISTS checkouts (
+    id UUID PRIMARY KEY,
+    step VARCHAR(50) NOT NULL,
+    order_data JSONB NOT NULL,
+    authorization_id VARCHAR(255) NOT NULL DEFAULT '',
+    failure TEXT NOT NULL DEFAULT '',  -- Why the checkout is being undone
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Covers: WHERE step NOT IN ('completed', 'failed') ORDER BY created_at, id
+CREATE INDEX IF NOT EXISTS idx_checkouts_unfinished ON checkouts(created_at, id) WHERE step NOT IN ('completed', 'failed');
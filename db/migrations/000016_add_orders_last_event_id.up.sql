@@ -0,0 +1,4 @@
+-- Event ID of each order's latest event, written with every write that
+-- publishes one, so the next event can name it as its causation ID.
+-- Existing orders start without one; their next event has no cause.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS last_event_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,8 @@
+-- One row per window rolled up into an order.metrics event, claimed in the
+-- transaction that publishes the rollup, so aggregators running at once
+-- emit each window only once.
+CREATE TABLE IF NOT EXISTS order_metrics_windows (
+    window_start TIMESTAMP WITH TIME ZONE PRIMARY KEY,
+    window_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    claimed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -4,26 +4,42 @@
 CREATE TABLE IF NOT EXISTS orders (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     customer_id VARCHAR(255) NOT NULL,
-    items JSONB NOT NULL,
     status VARCHAR(50) NOT NULL,
-    total DECIMAL(10, 2) NOT NULL,
+    total_minor BIGINT NOT NULL,  -- In minor units of currency, e.g. cents
+    currency CHAR(3),  -- ISO 4217; NULL predates currencies and reads as the base currency
     version INTEGER NOT NULL DEFAULT 1,  -- Optimistic locking version (ADR-0003)
+    event_seq BIGINT NOT NULL DEFAULT 0,  -- Sequence number of the order's latest event
+    last_event_id TEXT NOT NULL DEFAULT '',  -- Event ID of the order's latest event, the causation ID of its next
     created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
     deleted_at TIMESTAMP WITH TIME ZONE,
+    cancel_reason TEXT NOT NULL DEFAULT '',
 
-    CONSTRAINT valid_status CHECK (status IN ('pending', 'confirmed', 'processing', 'shipped', 'delivered', 'cancelled')),
+    CONSTRAINT valid_status CHECK (status IN ('pending', 'confirmed', 'processing', 'shipped', 'delivered', 'cancelled', 'expired')),
     CONSTRAINT positive_version CHECK (version > 0)
 );
 
 -- Composite indexes covering WHERE + ORDER BY created_at DESC for paginated queries (ADR-0002)
-CREATE INDEX IF NOT EXISTS idx_orders_created_at ON orders(created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_orders_created_id ON orders(created_at DESC, id DESC) WHERE deleted_at IS NULL;
 CREATE INDEX IF NOT EXISTS idx_orders_status_created ON orders(status, created_at DESC) WHERE deleted_at IS NULL;
-CREATE INDEX IF NOT EXISTS idx_orders_customer_created ON orders(customer_id, created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_orders_customer_created_id ON orders(customer_id, created_at DESC, id DESC) WHERE deleted_at IS NULL;
 CREATE INDEX IF NOT EXISTS idx_orders_customer_status_created ON orders(customer_id, status, created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_orders_updated_at_id ON orders(updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_orders_total ON orders(total_minor) WHERE deleted_at IS NULL;
 
--- JSONB GIN index for items array queries
-CREATE INDEX IF NOT EXISTS idx_orders_items ON orders USING GIN(items);
+-- Order line items; subtotal is derived as quantity * unit_price_minor
+CREATE TABLE IF NOT EXISTS order_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,  -- Preserves item order within the order
+    product_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    quantity INTEGER NOT NULL,
+    unit_price_minor BIGINT NOT NULL,  -- In minor units of the order's currency
+
+    CONSTRAINT positive_quantity CHECK (quantity > 0),
+    CONSTRAINT unique_item_position UNIQUE (order_id, position)
+);
 
 -- Function to automatically update updated_at timestamp
 CREATE OR REPLACE FUNCTION update_updated_at_column()
@@ -39,5 +55,53 @@ DROP TRIGGER IF EXISTS update_orders_updated_at ON orders;
 CREATE TRIGGER update_orders_updated_at BEFORE UPDATE ON orders
     FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
 
+-- Transactional outbox for order events (ADR-0006)
+CREATE TABLE IF NOT EXISTS outbox (
+    seq BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL UNIQUE,  -- Event ID, dedupes relay retries
+    order_id UUID NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    published_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON outbox(seq) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_created_at ON outbox(created_at);
+
+-- Event IDs processed by idempotent consumers
+CREATE TABLE IF NOT EXISTS processed_events (
+    event_id TEXT PRIMARY KEY,
+    processed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_processed_events_processed_at ON processed_events(processed_at);
+
+-- Order status transitions, written with the orders row update
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    old_status VARCHAR(50) NOT NULL,
+    new_status VARCHAR(50) NOT NULL,
+    changed_by VARCHAR(255) NOT NULL DEFAULT '',
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id ON order_status_history(order_id, occurred_at, id);
+
+-- Checkout saga progress, so interrupted checkouts can be resumed
+CREATE TABLE IF NOT EXISTS checkouts (
+    id UUID PRIMARY KEY,
+    step VARCHAR(50) NOT NULL,
+    order_data JSONB NOT NULL,
+    authorization_id VARCHAR(255) NOT NULL DEFAULT '',
+    failure TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_checkouts_unfinished ON checkouts(created_at, id) WHERE step NOT IN ('completed', 'failed');
+
 -- Grant permissions
 GRANT ALL PRIVILEGES ON TABLE orders TO postgres;
+GRANT ALL PRIVILEGES ON TABLE order_items TO postgres;
+GRANT ALL PRIVILEGES ON TABLE outbox TO postgres;
+GRANT ALL PRIVILEGES ON TABLE processed_events TO postgres;
+GRANT ALL PRIVILEGES ON TABLE order_status_history TO postgres;
+GRANT ALL PRIVILEGES ON TABLE checkouts TO postgres;
@@ -45,3 +45,23 @@ type RateLimiter interface {
 	// Reset clears rate limit counter for a key
 	Reset(ctx context.Context, key string) error
 }
+
+// IdempotencyRecord is the state stored under an idempotency key
+type IdempotencyRecord struct {
+	RequestHash string `json:"request_hash"`
+	// OrderID is empty while the first request with the key is in flight
+	OrderID string `json:"order_id,omitempty"`
+}
+
+// IdempotencyStore maps idempotency keys to the orders they created
+type IdempotencyStore interface {
+	// Reserve claims key for a request with the given hash.
+	// Returns nil if the key was free, or the existing record if it is taken.
+	Reserve(ctx context.Context, key, requestHash string, ttl time.Duration) (*IdempotencyRecord, error)
+
+	// Complete stores the order created for key
+	Complete(ctx context.Context, key string, rec IdempotencyRecord, ttl time.Duration) error
+
+	// Release frees key so the request can be retried
+	Release(ctx context.Context, key string) error
+}
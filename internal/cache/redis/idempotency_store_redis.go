@@ -0,0 +1,92 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/cache"
+)
+
+// idempotencyStoreRedis implements IdempotencyStore using Redis
+type idempotencyStoreRedis struct {
+	client *redis.Client
+}
+
+// NewIdempotencyStore creates a new Redis idempotency store
+func NewIdempotencyStore(client *redis.Client) cache.IdempotencyStore {
+	return &idempotencyStoreRedis{
+		client: client,
+	}
+}
+
+func (s *idempotencyStoreRedis) Reserve(ctx context.Context, key, requestHash string, ttl time.Duration) (*cache.IdempotencyRecord, error) {
+	rkey := idempotencyKey(key)
+	data, err := json.Marshal(cache.IdempotencyRecord{RequestHash: requestHash})
+	if err != nil {
+		return nil, fmt.Errorf("idempotency marshal %s: %w", rkey, err)
+	}
+
+	ok, err := s.client.SetNX(ctx, rkey, data, ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("idempotency reserve %s: %w", rkey, err)
+	}
+	if ok {
+		return nil, nil
+	}
+
+	existing, err := s.client.Get(ctx, rkey).Bytes()
+	if err == redis.Nil {
+		// Expired or released between SETNX and GET; let the caller retry
+		return nil, fmt.Errorf("idempotency reserve %s: key vanished", rkey)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("idempotency get %s: %w", rkey, err)
+	}
+
+	var rec cache.IdempotencyRecord
+	if err := json.Unmarshal(existing, &rec); err != nil {
+		return nil, fmt.Errorf("idempotency unmarshal %s: %w", rkey, err)
+	}
+	return &rec, nil
+}
+
+func (s *idempotencyStoreRedis) Complete(ctx context.Context, key string, rec cache.IdempotencyRecord, ttl time.Duration) error {
+	rkey := idempotencyKey(key)
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("idempotency marshal %s: %w", rkey, err)
+	}
+	if err := s.client.Set(ctx, rkey, data, ttl).Err(); err != nil {
+		return fmt.Errorf("idempotency set %s: %w", rkey, err)
+	}
+	return nil
+}
+
+func (s *idempotencyStoreRedis) Release(ctx context.Context, key string) error {
+	rkey := idempotencyKey(key)
+	if err := s.client.Del(ctx, rkey).Err(); err != nil {
+		return fmt.Errorf("idempotency del %s: %w", rkey, err)
+	}
+	return nil
+}
+
+func idempotencyKey(key string) string {
+	return "idempotency:" + key
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyStoreRedis_Reserve_FreeKey_ReturnsNil(t *testing.T) {
+	_, client := setupMiniredis(t)
+	store := NewIdempotencyStore(client)
+
+	rec, err := store.Reserve(context.Background(), "key-1", "hash-a", time.Hour)
+
+	assert.NoError(t, err)
+	assert.Nil(t, rec)
+}
+
+func TestIdempotencyStoreRedis_Reserve_TakenKey_ReturnsExisting(t *testing.T) {
+	_, client := setupMiniredis(t)
+	store := NewIdempotencyStore(client)
+	ctx := context.Background()
+
+	_, err := store.Reserve(ctx, "key-1", "hash-a", time.Hour)
+	require.NoError(t, err)
+
+	// In flight: no order yet
+	rec, err := store.Reserve(ctx, "key-1", "hash-b", time.Hour)
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, "hash-a", rec.RequestHash)
+	assert.Empty(t, rec.OrderID)
+
+	// Completed: order ID is returned
+	require.NoError(t, store.Complete(ctx, "key-1", cache.IdempotencyRecord{RequestHash: "hash-a", OrderID: "order-1"}, time.Hour))
+	rec, err = store.Reserve(ctx, "key-1", "hash-a", time.Hour)
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, "order-1", rec.OrderID)
+}
+
+func TestIdempotencyStoreRedis_Reserve_Expires(t *testing.T) {
+	mr, client := setupMiniredis(t)
+	store := NewIdempotencyStore(client)
+	ctx := context.Background()
+
+	_, err := store.Reserve(ctx, "key-1", "hash-a", time.Hour)
+	require.NoError(t, err)
+
+	mr.FastForward(2 * time.Hour)
+
+	rec, err := store.Reserve(ctx, "key-1", "hash-b", time.Hour)
+	assert.NoError(t, err)
+	assert.Nil(t, rec, "expired key should be free again")
+}
+
+func TestIdempotencyStoreRedis_Release_FreesKey(t *testing.T) {
+	_, client := setupMiniredis(t)
+	store := NewIdempotencyStore(client)
+	ctx := context.Background()
+
+	_, err := store.Reserve(ctx, "key-1", "hash-a", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, store.Release(ctx, "key-1"))
+
+	rec, err := store.Reserve(ctx, "key-1", "hash-a", time.Hour)
+	assert.NoError(t, err)
+	assert.Nil(t, rec)
+}
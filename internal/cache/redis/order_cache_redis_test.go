@@ -46,12 +46,12 @@ func newTestOrder() *domain.Order {
 				ProductID: "product-1",
 				Name:      "Test Product",
 				Quantity:  2,
-				Price:     10.50,
-				Subtotal:  21.00,
+				Price:     domain.Money{Amount: 1050},
+				Subtotal:  domain.Money{Amount: 2100},
 			},
 		},
 		Status:    domain.OrderStatusPending,
-		Total:     21.00,
+		Total:     domain.Money{Amount: 2100, Currency: "USD"},
 		Version:   1,
 		CreatedAt: time.Now().Truncate(time.Second),
 		UpdatedAt: time.Now().Truncate(time.Second),
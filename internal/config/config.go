@@ -18,17 +18,21 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Config holds all application configuration
 type Config struct {
-	App      AppConfig
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Kafka    KafkaConfig
-	Cache    CacheConfig
+	App       AppConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	Kafka     KafkaConfig
+	Cache     CacheConfig
+	RateLimit RateLimitConfig
+	Expiry    ExpiryConfig
+	Metrics   MetricsConfig
 }
 
 // AppConfig holds application-level configuration
@@ -37,6 +41,16 @@ type AppConfig struct {
 	Version     string
 	Environment string
 	LogLevel    string
+	// BaseCurrency is the ISO 4217 code of orders created without a
+	// currency and of orders stored before orders recorded one
+	BaseCurrency string
+	// IDStrategy names how new order and item IDs are generated:
+	// domain.IDStrategyUUIDv4 (random) or domain.IDStrategyUUIDv7
+	// (time-ordered)
+	IDStrategy string
+	// MaxOpenOrders is how many open orders one customer may have, see
+	// service.WithOrderQuota; 0 for no limit
+	MaxOpenOrders int
 }
 
 // ServerConfig holds server configuration
@@ -47,6 +61,20 @@ type ServerConfig struct {
 	WriteTimeout    time.Duration
 	ShutdownTimeout time.Duration
 	EnablePprof     bool
+	// ActorHeader names the request header an authenticating proxy sets
+	// to the caller, recorded as changed_by of status changes. Empty
+	// disables it.
+	ActorHeader string
+	// APIKeys lists the API keys accepted on order endpoints, as
+	// comma-separated name:scope:key entries with scope read or write.
+	// Empty disables API key authentication.
+	APIKeys string
+	// MaxBodyBytes caps the size of order request bodies; larger ones
+	// are refused with 413.
+	MaxBodyBytes int64
+	// RequireJSON refuses order requests with a body that is not
+	// application/json with 415.
+	RequireJSON bool
 }
 
 // DatabaseConfig holds database configuration
@@ -62,6 +90,15 @@ type DatabaseConfig struct {
 	ConnMaxLifetime time.Duration
 	ConnMaxIdleTime time.Duration
 	MigrationsPath  string
+	// StatementTimeout is the longest any statement may run before the
+	// server cancels it, a backstop for requests without a deadline. Zero
+	// keeps the server's default.
+	StatementTimeout time.Duration
+	// ReplicaHost and ReplicaPort address a read replica of the database,
+	// with the same credentials, which serves order listings. An empty
+	// ReplicaHost reads everything from the primary.
+	ReplicaHost string
+	ReplicaPort int
 }
 
 // RedisConfig holds Redis configuration
@@ -77,25 +114,70 @@ type RedisConfig struct {
 
 // KafkaConfig holds Kafka configuration
 type KafkaConfig struct {
-	Brokers []string
-	Topic   string
-	GroupID string
+	Brokers            []string // Comma-separated in KAFKA_BROKERS
+	Topic              string
+	GroupID            string
+	OutboxEnabled      bool
+	OutboxPollInterval time.Duration
+	RetryMaxAttempts   int           // Total write attempts per event; 1 disables retries
+	RetryBaseDelay     time.Duration // Backoff before the first retry, doubled per attempt
+	DeadLetterTopic    string        // Receives events that exhaust retries; empty disables
+	DeadLetterFile     string        // Local fallback when the dead-letter topic fails; empty disables
+	MetricsTopic       string        // Receives the order.metrics rollups
+	Compression        string        // Producer codec: none, gzip, snappy, lz4 or zstd
+	Acks               string        // Brokers that must store each message: none, leader or all
+	WriteTimeout       time.Duration // Bound on each write to the brokers; 0 disables
+	BreakerThreshold   int           // Consecutive failed publishes that open the circuit; 0 disables
+	BreakerCooldown    time.Duration // How long an open circuit fails publishes before probing
+	PublishRate        float64       // Events published per second; 0 disables the limit
+	PublishBurst       int           // Events that may be published at once above the rate
+	StartupAttempts    int           // Attempts to reach a broker before startup fails; 0 skips the check
+	StartupRetryDelay  time.Duration // Wait between startup attempts
+	TLSEnabled         bool          // Connect to the brokers over TLS
+	TLSCAFile          string        // PEM CA bundle for broker certificates; empty uses the system roots
+	SASLMechanism      string        // PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512; empty disables SASL
+	SASLUsername       string
+	SASLPassword       string
 }
 
 // CacheConfig holds cache configuration
 type CacheConfig struct {
-	DefaultTTL time.Duration
-	HotTTL     time.Duration
+	DefaultTTL     time.Duration
+	HotTTL         time.Duration
+	IdempotencyTTL time.Duration
+}
+
+// RateLimitConfig holds per-client API rate limits (ADR-0005)
+type RateLimitConfig struct {
+	RequestsPerSecond float64 // Token refill rate of every API route; 0 disables
+	Burst             int
+	CreatePerSecond   float64 // Stricter limit for POST /api/v1/orders; 0 uses the default
+	CreateBurst       int
+}
+
+// ExpiryConfig holds the expiry of orders left pending
+type ExpiryConfig struct {
+	TTL          time.Duration // Pending orders older than this expire; 0 disables
+	ScanInterval time.Duration
+	BatchSize    int // Orders expired per transaction
+}
+
+// MetricsConfig holds the periodic order.metrics rollup
+type MetricsConfig struct {
+	Interval time.Duration // Length of each rolled up window; 0 disables
 }
 
 // LoadFromEnv loads configuration from environment variables
 func LoadFromEnv() (*Config, error) {
 	return &Config{
 		App: AppConfig{
-			Name:        getEnv("APP_NAME", "ordersvc"),
-			Version:     getEnv("APP_VERSION", "dev"),
-			Environment: getEnv("APP_ENVIRONMENT", "development"),
-			LogLevel:    getEnv("APP_LOG_LEVEL", "info"),
+			Name:          getEnv("APP_NAME", "ordersvc"),
+			Version:       getEnv("APP_VERSION", "dev"),
+			Environment:   getEnv("APP_ENVIRONMENT", "development"),
+			LogLevel:      getEnv("APP_LOG_LEVEL", "info"),
+			BaseCurrency:  getEnv("APP_BASE_CURRENCY", "USD"),
+			IDStrategy:    getEnv("ORDER_ID_STRATEGY", "uuidv4"),
+			MaxOpenOrders: getEnvAsInt("ORDER_MAX_OPEN_PER_CUSTOMER", 0),
 		},
 		Server: ServerConfig{
 			HTTPPort:        getEnvAsInt("HTTP_PORT", 8080),
@@ -104,19 +186,26 @@ func LoadFromEnv() (*Config, error) {
 			WriteTimeout:    10 * time.Second,
 			ShutdownTimeout: 30 * time.Second,
 			EnablePprof:     false,
+			ActorHeader:     getEnv("AUTH_ACTOR_HEADER", ""),
+			APIKeys:         getEnv("AUTH_API_KEYS", ""),
+			MaxBodyBytes:    int64(getEnvAsInt("HTTP_MAX_BODY_BYTES", 1<<20)),
+			RequireJSON:     getEnvAsBool("HTTP_REQUIRE_JSON", true),
 		},
 		Database: DatabaseConfig{
-			Host:            getEnv("DATABASE_HOST", "localhost"),
-			Port:            getEnvAsInt("DATABASE_PORT", 5432),
-			User:            getEnv("DATABASE_USER", "postgres"),
-			Password:        getEnv("DATABASE_PASSWORD", "postgres"),
-			Database:        getEnv("DATABASE_NAME", "ordersvc"),
-			SSLMode:         getEnv("DATABASE_SSL_MODE", "disable"),
-			MaxOpenConns:    25,
-			MaxIdleConns:    5,
-			ConnMaxLifetime: 5 * time.Minute,
-			ConnMaxIdleTime: 10 * time.Minute,
-			MigrationsPath:  "file://db/migrations",
+			Host:             getEnv("DATABASE_HOST", "localhost"),
+			Port:             getEnvAsInt("DATABASE_PORT", 5432),
+			User:             getEnv("DATABASE_USER", "postgres"),
+			Password:         getEnv("DATABASE_PASSWORD", "postgres"),
+			Database:         getEnv("DATABASE_NAME", "ordersvc"),
+			SSLMode:          getEnv("DATABASE_SSL_MODE", "disable"),
+			MaxOpenConns:     25,
+			MaxIdleConns:     5,
+			ConnMaxLifetime:  5 * time.Minute,
+			ConnMaxIdleTime:  10 * time.Minute,
+			MigrationsPath:   "file://db/migrations",
+			StatementTimeout: getEnvAsDuration("DATABASE_STATEMENT_TIMEOUT", 30*time.Second),
+			ReplicaHost:      getEnv("DATABASE_REPLICA_HOST", ""),
+			ReplicaPort:      getEnvAsInt("DATABASE_REPLICA_PORT", getEnvAsInt("DATABASE_PORT", 5432)),
 		},
 		Redis: RedisConfig{
 			Host:        getEnv("REDIS_HOST", "localhost"),
@@ -128,13 +217,49 @@ func LoadFromEnv() (*Config, error) {
 			PoolTimeout: 4 * time.Second,
 		},
 		Kafka: KafkaConfig{
-			Brokers: []string{getEnv("KAFKA_BROKERS", "localhost:9092")},
-			Topic:   getEnv("KAFKA_TOPIC", "order-events"),
-			GroupID: getEnv("KAFKA_GROUP_ID", "ordersvc"),
+			Brokers:            getEnvAsList("KAFKA_BROKERS", "localhost:9092"),
+			Topic:              getEnv("KAFKA_TOPIC", "order-events"),
+			GroupID:            getEnv("KAFKA_GROUP_ID", "ordersvc"),
+			OutboxEnabled:      getEnvAsBool("KAFKA_OUTBOX_ENABLED", false),
+			OutboxPollInterval: getEnvAsDuration("KAFKA_OUTBOX_POLL_INTERVAL", 1*time.Second),
+			RetryMaxAttempts:   getEnvAsInt("KAFKA_RETRY_MAX_ATTEMPTS", 3),
+			RetryBaseDelay:     getEnvAsDuration("KAFKA_RETRY_BASE_DELAY", 100*time.Millisecond),
+			DeadLetterTopic:    getEnv("KAFKA_DLQ_TOPIC", ""),
+			DeadLetterFile:     getEnv("KAFKA_DLQ_FILE", ""),
+			MetricsTopic:       getEnv("KAFKA_METRICS_TOPIC", "order-metrics"),
+			Compression:        getEnv("KAFKA_COMPRESSION", "snappy"),
+			Acks:               getEnv("KAFKA_ACKS", "all"),
+			WriteTimeout:       getEnvAsDuration("KAFKA_WRITE_TIMEOUT", 5*time.Second),
+			BreakerThreshold:   getEnvAsInt("KAFKA_BREAKER_THRESHOLD", 5),
+			BreakerCooldown:    getEnvAsDuration("KAFKA_BREAKER_COOLDOWN", 30*time.Second),
+			PublishRate:        getEnvAsFloat("KAFKA_PUBLISH_RATE", 0),
+			PublishBurst:       getEnvAsInt("KAFKA_PUBLISH_BURST", 100),
+			StartupAttempts:    getEnvAsInt("KAFKA_STARTUP_RETRY_ATTEMPTS", 0),
+			StartupRetryDelay:  getEnvAsDuration("KAFKA_STARTUP_RETRY_DELAY", 2*time.Second),
+			TLSEnabled:         getEnvAsBool("KAFKA_TLS_ENABLED", false),
+			TLSCAFile:          getEnv("KAFKA_TLS_CA_FILE", ""),
+			SASLMechanism:      getEnv("KAFKA_SASL_MECHANISM", ""),
+			SASLUsername:       getEnv("KAFKA_SASL_USERNAME", ""),
+			SASLPassword:       getEnv("KAFKA_SASL_PASSWORD", ""),
 		},
 		Cache: CacheConfig{
-			DefaultTTL: 5 * time.Minute,
-			HotTTL:     1 * time.Hour,
+			DefaultTTL:     5 * time.Minute,
+			HotTTL:         1 * time.Hour,
+			IdempotencyTTL: getEnvAsDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		},
+		RateLimit: RateLimitConfig{
+			RequestsPerSecond: getEnvAsFloat("RATE_LIMIT_RPS", 0),
+			Burst:             getEnvAsInt("RATE_LIMIT_BURST", 20),
+			CreatePerSecond:   getEnvAsFloat("RATE_LIMIT_CREATE_RPS", 0),
+			CreateBurst:       getEnvAsInt("RATE_LIMIT_CREATE_BURST", 5),
+		},
+		Expiry: ExpiryConfig{
+			TTL:          getEnvAsDuration("ORDER_EXPIRY_TTL", 0),
+			ScanInterval: getEnvAsDuration("ORDER_EXPIRY_SCAN_INTERVAL", time.Minute),
+			BatchSize:    getEnvAsInt("ORDER_EXPIRY_BATCH_SIZE", 100),
+		},
+		Metrics: MetricsConfig{
+			Interval: getEnvAsDuration("ORDER_METRICS_INTERVAL", 0),
 		},
 	}, nil
 }
@@ -146,6 +271,17 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvAsList splits a comma-separated value, dropping empty entries
+func getEnvAsList(key, defaultValue string) []string {
+	var list []string
+	for _, v := range strings.Split(getEnv(key, defaultValue), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
 func getEnvAsInt(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
 		if intValue, err := strconv.Atoi(value); err == nil {
@@ -154,3 +290,30 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if durationValue, err := time.ParseDuration(value); err == nil {
+			return durationValue
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CheckoutStep is how far a checkout has got. A checkout moves from
+// started through inventory_reserved and payment_authorized to completed,
+// or to failed once the steps it completed have been undone.
+type CheckoutStep string
+
+// Checkout steps, in the order a checkout completes them
+const (
+	CheckoutStarted    CheckoutStep = "started"
+	CheckoutReserved   CheckoutStep = "inventory_reserved"
+	CheckoutAuthorized CheckoutStep = "payment_authorized"
+	CheckoutCompleted  CheckoutStep = "completed"
+	CheckoutFailed     CheckoutStep = "failed"
+)
+
+// IsFinal reports whether a checkout at step has nothing left to do.
+func (s CheckoutStep) IsFinal() bool {
+	return s == CheckoutCompleted || s == CheckoutFailed
+}
+
+// Checkout records the progress of checking out one order, so that a
+// checkout interrupted by a crash can be finished or undone.
+type Checkout struct {
+	ID              uuid.UUID // That of Order
+	Step            CheckoutStep
+	Order           *Order // Stored as an order only once the checkout completes
+	AuthorizationID string // Identifies the payment authorization, once there is one
+	Failure         string // Why the checkout is being undone; empty while it can complete
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Reserved reports whether the order's stock has been reserved.
+func (c *Checkout) Reserved() bool {
+	return c.Step == CheckoutReserved || c.Step == CheckoutAuthorized
+}
+
+// Authorized reports whether the order's payment has been authorized.
+func (c *Checkout) Authorized() bool {
+	return c.Step == CheckoutAuthorized
+}
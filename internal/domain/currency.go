@@ -0,0 +1,27 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "golang.org/x/text/currency"
+
+// DefaultCurrency is the base currency used when none is configured.
+const DefaultCurrency = "USD"
+
+// ValidCurrency reports whether code is an ISO 4217 currency code in its
+// canonical upper-case form, such as "USD" or "EUR".
+func ValidCurrency(code string) bool {
+	unit, err := currency.ParseISO(code)
+	return err == nil && unit.String() == code
+}
@@ -15,13 +15,18 @@
 // Package domain contains core business entities and value objects.
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Domain errors for order operations.
 var (
 	ErrOrderNotFound          = errors.New("order not found")
+	ErrNilOrder               = errors.New("order is nil")
 	ErrInvalidCustomerID      = errors.New("invalid customer ID")
 	ErrNoItems                = errors.New("order must have at least one item")
+	ErrNegativeTotal          = errors.New("order total must not be negative")
 	ErrInvalidProductID       = errors.New("invalid product ID")
 	ErrInvalidProductName     = errors.New("invalid product name")
 	ErrInvalidQuantity        = errors.New("quantity must be greater than 0")
@@ -29,5 +34,166 @@ var (
 	ErrInvalidStatus          = errors.New("invalid order status")
 	ErrInvalidTransition      = errors.New("invalid status transition")
 	ErrOrderAlreadyDeleted    = errors.New("order is already deleted")
+	ErrOrderNotDeleted        = errors.New("order is not deleted")
+	ErrOrderNotCancellable    = errors.New("order cannot be cancelled")
 	ErrConcurrentModification = errors.New("order was modified by another process")
+	ErrIdempotencyKeyReused   = errors.New("idempotency key reused with a different request")
+	ErrIdempotencyKeyInFlight = errors.New("request with this idempotency key is in progress")
+	ErrInvalidCursor          = errors.New("invalid pagination cursor")
+	ErrInvalidCurrency        = errors.New("invalid currency code")
+	ErrCurrencyMismatch       = errors.New("order items must share one currency")
+	ErrInvalidAmount          = errors.New("invalid money amount")
+	ErrAmountOverflow         = errors.New("money amount out of range")
+	ErrInvalidFilter          = errors.New("invalid list filter")
+	ErrInvalidBatch           = errors.New("order batch has invalid orders")
+	ErrReservationFailed      = errors.New("inventory could not be reserved")
+	ErrQuotaExceeded          = errors.New("customer has too many open orders")
+	ErrPaymentFailed          = errors.New("payment could not be authorized")
+	ErrValidation             = errors.New("order has invalid fields")
 )
+
+// ErrVersionConflict is returned when an optimistic-locking update finds the
+// stored version no longer matches the caller's. It is the same sentinel as
+// ErrConcurrentModification so errors.Is matches either name.
+var ErrVersionConflict = ErrConcurrentModification
+
+// StatusError reports a value that is not an order status. It matches
+// ErrInvalidStatus with errors.Is.
+type StatusError struct {
+	Value string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %q", ErrInvalidStatus, e.Value)
+}
+
+// Unwrap returns ErrInvalidStatus.
+func (e *StatusError) Unwrap() error {
+	return ErrInvalidStatus
+}
+
+// TransitionError reports a status change the order state machine does not
+// allow. It matches ErrInvalidTransition with errors.Is.
+type TransitionError struct {
+	From OrderStatus
+	To   OrderStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s from %s to %s", ErrInvalidTransition, e.From, e.To)
+}
+
+// Unwrap returns ErrInvalidTransition.
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// CancelError reports an order that can no longer be cancelled, such as
+// one that has shipped. It matches ErrOrderNotCancellable with errors.Is.
+type CancelError struct {
+	Status OrderStatus // Status the order is in
+}
+
+func (e *CancelError) Error() string {
+	return fmt.Sprintf("%s: order is %s", ErrOrderNotCancellable, e.Status)
+}
+
+// Unwrap returns ErrOrderNotCancellable.
+func (e *CancelError) Unwrap() error {
+	return ErrOrderNotCancellable
+}
+
+// CurrencyMismatchError reports a line item priced in a different currency
+// from the rest of its order. It matches ErrCurrencyMismatch with
+// errors.Is.
+type CurrencyMismatchError struct {
+	Want string // Currency of the order
+	Got  string // Currency of the offending item
+}
+
+func (e *CurrencyMismatchError) Error() string {
+	return fmt.Sprintf("%s: item in %s, order in %s", ErrCurrencyMismatch, e.Got, e.Want)
+}
+
+// Unwrap returns ErrCurrencyMismatch.
+func (e *CurrencyMismatchError) Unwrap() error {
+	return ErrCurrencyMismatch
+}
+
+// AmountError reports a decimal amount that is malformed or more precise
+// than its currency's minor unit. It matches ErrInvalidAmount with
+// errors.Is.
+type AmountError struct {
+	Amount   string
+	Currency string
+}
+
+func (e *AmountError) Error() string {
+	return fmt.Sprintf("%s: %q in %s", ErrInvalidAmount, e.Amount, e.Currency)
+}
+
+// Unwrap returns ErrInvalidAmount.
+func (e *AmountError) Unwrap() error {
+	return ErrInvalidAmount
+}
+
+// FilterError reports a list filter value that cannot be applied, such as
+// an unknown status or a malformed timestamp. It matches ErrInvalidFilter
+// with errors.Is.
+type FilterError struct {
+	Field  string // Filter name, e.g. "status"
+	Value  string
+	Reason string // What a valid value looks like
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("%s: %s %q %s", ErrInvalidFilter, e.Field, e.Value, e.Reason)
+}
+
+// Unwrap returns ErrInvalidFilter.
+func (e *FilterError) Unwrap() error {
+	return ErrInvalidFilter
+}
+
+// QuotaError reports an order refused because its customer already has
+// the most open orders allowed. It matches ErrQuotaExceeded with
+// errors.Is.
+type QuotaError struct {
+	CustomerID string
+	Limit      int // Open orders a customer may have
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("%s: customer %q has %d, the limit", ErrQuotaExceeded, e.CustomerID, e.Limit)
+}
+
+// Unwrap returns ErrQuotaExceeded.
+func (e *QuotaError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// BulkItemError reports why the order at Index of a batch is invalid.
+type BulkItemError struct {
+	Index int
+	Err   error
+}
+
+// BulkError reports the invalid orders of a batch that was rejected as a
+// whole, so none of its orders were stored. It matches ErrInvalidBatch with
+// errors.Is.
+type BulkError struct {
+	Failed []BulkItemError // In batch order
+}
+
+func (e *BulkError) Error() string {
+	first := e.Failed[0]
+	if len(e.Failed) == 1 {
+		return fmt.Sprintf("%s: order %d: %v", ErrInvalidBatch, first.Index, first.Err)
+	}
+	return fmt.Sprintf("%s: order %d: %v (and %d more)", ErrInvalidBatch, first.Index, first.Err, len(e.Failed)-1)
+}
+
+// Unwrap returns ErrInvalidBatch.
+func (e *BulkError) Unwrap() error {
+	return ErrInvalidBatch
+}
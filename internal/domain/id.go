@@ -0,0 +1,74 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IDGenerator mints the IDs of new orders and order items.
+type IDGenerator interface {
+	NewID() uuid.UUID
+}
+
+// IDGeneratorFunc adapts a function to IDGenerator.
+type IDGeneratorFunc func() uuid.UUID
+
+// NewID returns f().
+func (f IDGeneratorFunc) NewID() uuid.UUID { return f() }
+
+// Names of the ID generators, as accepted by ParseIDGenerator.
+const (
+	IDStrategyUUIDv4 = "uuidv4"
+	IDStrategyUUIDv7 = "uuidv7"
+)
+
+// RandomIDs generates random (version 4) UUIDs, which say nothing about
+// when an order was created. It is the default.
+var RandomIDs IDGenerator = IDGeneratorFunc(uuid.New)
+
+// TimeOrderedIDs generates version 7 UUIDs, which begin with the creation
+// time in milliseconds, so orders sort by ID in creation order. IDs
+// generated within one millisecond by one process still sort in the order
+// they were generated.
+var TimeOrderedIDs IDGenerator = IDGeneratorFunc(func() uuid.UUID {
+	return uuid.Must(uuid.NewV7())
+})
+
+// ParseIDGenerator returns the generator called name: "uuidv4" for
+// RandomIDs or "uuidv7" for TimeOrderedIDs. An empty name is "uuidv4".
+func ParseIDGenerator(name string) (IDGenerator, error) {
+	switch name {
+	case "", IDStrategyUUIDv4:
+		return RandomIDs, nil
+	case IDStrategyUUIDv7:
+		return TimeOrderedIDs, nil
+	default:
+		return nil, fmt.Errorf("unknown ID strategy %q: want %s or %s", name, IDStrategyUUIDv4, IDStrategyUUIDv7)
+	}
+}
+
+// IDTime returns the creation time encoded in a time-ordered ID, and false
+// for an ID that encodes none, such as a random one.
+func IDTime(id uuid.UUID) (time.Time, bool) {
+	if id.Version() != 7 {
+		return time.Time{}, false
+	}
+	sec, nsec := id.Time().UnixTime()
+	return time.Unix(sec, nsec), true
+}
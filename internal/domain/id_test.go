@@ -0,0 +1,133 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIDGenerators_ConcurrentIDsAreUnique(t *testing.T) {
+	const (
+		workers = 16
+		perWork = 2000
+	)
+	tests := []struct {
+		name    string
+		gen     IDGenerator
+		version uuid.Version
+	}{
+		{"uuidv4", RandomIDs, 4},
+		{"uuidv7", TimeOrderedIDs, 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := make(chan uuid.UUID, workers*perWork)
+			var wg sync.WaitGroup
+			for range workers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range perWork {
+						out <- tt.gen.NewID()
+					}
+				}()
+			}
+			wg.Wait()
+			close(out)
+
+			seen := make(map[uuid.UUID]bool, workers*perWork)
+			for id := range out {
+				require.False(t, seen[id], "duplicate ID %s", id)
+				require.Equal(t, tt.version, id.Version())
+				seen[id] = true
+			}
+			assert.Len(t, seen, workers*perWork)
+		})
+	}
+}
+
+func TestTimeOrderedIDs_SortInGenerationOrder(t *testing.T) {
+	// Many IDs share a millisecond; they must still sort in order made
+	ids := make([]uuid.UUID, 5000)
+	for i := range ids {
+		ids[i] = TimeOrderedIDs.NewID()
+	}
+
+	byBytes := append([]uuid.UUID(nil), ids...)
+	sort.Slice(byBytes, func(i, j int) bool { return bytes.Compare(byBytes[i][:], byBytes[j][:]) < 0 })
+	assert.Equal(t, ids, byBytes, "byte order is generation order")
+
+	byString := append([]uuid.UUID(nil), ids...)
+	sort.Slice(byString, func(i, j int) bool { return byString[i].String() < byString[j].String() })
+	assert.Equal(t, ids, byString, "string order is generation order")
+}
+
+func TestTimeOrderedIDs_LaterMillisecondSortsAfter(t *testing.T) {
+	first := TimeOrderedIDs.NewID()
+	time.Sleep(2 * time.Millisecond)
+	second := TimeOrderedIDs.NewID()
+
+	assert.Less(t, first.String(), second.String())
+}
+
+func TestIDTime(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	id := TimeOrderedIDs.NewID()
+	after := time.Now()
+
+	got, ok := IDTime(id)
+	require.True(t, ok)
+	assert.False(t, got.Before(before), "%v is before %v", got, before)
+	assert.False(t, got.After(after), "%v is after %v", got, after)
+
+	_, ok = IDTime(RandomIDs.NewID())
+	assert.False(t, ok, "a random ID encodes no time")
+}
+
+func TestParseIDGenerator(t *testing.T) {
+	tests := []struct {
+		name    string
+		version uuid.Version
+		wantErr bool
+	}{
+		{name: "", version: 4},
+		{name: IDStrategyUUIDv4, version: 4},
+		{name: IDStrategyUUIDv7, version: 7},
+		{name: "snowflake", wantErr: true},
+		{name: "UUIDv7", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen, err := ParseIDGenerator(tt.name)
+
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "unknown ID strategy")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.version, gen.NewID().Version())
+		})
+	}
+}
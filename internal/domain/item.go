@@ -22,28 +22,18 @@ type OrderItem struct {
 	ProductID string
 	Name      string
 	Quantity  int
-	Price     float64
-	Subtotal  float64
+	Price     Money // Unit price; an empty Currency means the order's currency
+	Subtotal  Money
 }
 
-// CalculateSubtotal computes item subtotal
-func (i *OrderItem) CalculateSubtotal() float64 {
-	return float64(i.Quantity) * i.Price
+// CalculateSubtotal computes item subtotal. Returns ErrAmountOverflow if
+// it does not fit in an int64.
+func (i *OrderItem) CalculateSubtotal() (Money, error) {
+	return i.Price.Times(i.Quantity)
 }
 
-// Validate performs item validation
+// Validate performs item validation, returning the sentinel of the first
+// rule the item breaks.
 func (i *OrderItem) Validate() error {
-	if i.ProductID == "" {
-		return ErrInvalidProductID
-	}
-	if i.Name == "" {
-		return ErrInvalidProductName
-	}
-	if i.Quantity <= 0 {
-		return ErrInvalidQuantity
-	}
-	if i.Price <= 0 {
-		return ErrInvalidPrice
-	}
-	return nil
+	return firstBroken(itemRules(i))
 }
@@ -0,0 +1,159 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/currency"
+)
+
+// Money is an amount of a currency in integer minor units, such as cents,
+// so sums and products of it are exact.
+type Money struct {
+	Amount   int64  // In minor units of Currency, e.g. 1999 for 19.99 USD
+	Currency string // ISO 4217 code
+}
+
+// MinorUnits returns the number of decimal places of currency's minor unit:
+// 2 for USD, 0 for JPY, 3 for BHD. Unknown codes get 2.
+func MinorUnits(code string) int {
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return 2
+	}
+	scale, _ := currency.Standard.Rounding(unit)
+	return scale
+}
+
+// ParseMoney parses a decimal amount such as "19.99" of currency, exactly.
+// Returns an *AmountError if amount is not a plain decimal number or has
+// more significant decimal places than the currency's minor unit.
+func ParseMoney(amount, code string) (Money, error) {
+	scale := MinorUnits(code)
+	digits, negative := strings.CutPrefix(amount, "-")
+	whole, frac, _ := strings.Cut(digits, ".")
+	if !isDigits(whole) || (strings.Contains(digits, ".") && !isDigits(frac)) {
+		return Money{}, &AmountError{Amount: amount, Currency: code}
+	}
+	if len(frac) > scale {
+		if strings.TrimRight(frac[scale:], "0") != "" {
+			return Money{}, &AmountError{Amount: amount, Currency: code}
+		}
+		frac = frac[:scale]
+	}
+	frac += strings.Repeat("0", scale-len(frac))
+
+	minor, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, &AmountError{Amount: amount, Currency: code}
+	}
+	if negative {
+		minor = -minor
+	}
+	return Money{Amount: minor, Currency: code}, nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Add returns m + n. Returns a *CurrencyMismatchError if both have a
+// currency and they differ; a Money without one takes the other's.
+// Returns ErrAmountOverflow if the sum does not fit in an int64.
+func (m Money) Add(n Money) (Money, error) {
+	switch {
+	case m.Currency == "":
+		m.Currency = n.Currency
+	case n.Currency != "" && n.Currency != m.Currency:
+		return Money{}, &CurrencyMismatchError{Want: m.Currency, Got: n.Currency}
+	}
+	sum := m.Amount + n.Amount
+	if (n.Amount > 0 && sum < m.Amount) || (n.Amount < 0 && sum > m.Amount) {
+		return Money{}, ErrAmountOverflow
+	}
+	m.Amount = sum
+	return m, nil
+}
+
+// Times returns m multiplied by quantity. Returns ErrAmountOverflow if the
+// product does not fit in an int64.
+func (m Money) Times(quantity int) (Money, error) {
+	hi, lo := bits.Mul64(abs(m.Amount), abs(int64(quantity)))
+	negative := (m.Amount < 0) != (quantity < 0)
+	limit := uint64(math.MaxInt64)
+	if negative {
+		limit++ // math.MinInt64 has no positive counterpart
+	}
+	if hi != 0 || lo > limit {
+		return Money{}, ErrAmountOverflow
+	}
+	if negative {
+		lo = -lo
+	}
+	m.Amount = int64(lo) // #nosec G115 -- lo is within int64 range, checked above
+	return m, nil
+}
+
+// abs returns the magnitude of n, which for math.MinInt64 does not fit in
+// an int64.
+func abs(n int64) uint64 {
+	u := uint64(n) // #nosec G115 -- negated below for negative n
+	if n < 0 {
+		u = -u
+	}
+	return u
+}
+
+// Decimal formats the amount as a decimal string with the currency's
+// minor unit places, e.g. "19.99" or "-0.50" for USD and "1000" for JPY.
+func (m Money) Decimal() string {
+	scale := MinorUnits(m.Currency)
+	sign := ""
+	if m.Amount < 0 {
+		sign = "-"
+	}
+	digits := strconv.FormatUint(abs(m.Amount), 10)
+	if scale == 0 {
+		return sign + digits
+	}
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
+
+// Float64 returns the amount in major units as a float, for wire formats
+// that carry one. It may not be exact; compute with Amount instead.
+func (m Money) Float64() float64 {
+	return float64(m.Amount) / math.Pow10(MinorUnits(m.Currency))
+}
+
+// String returns the amount and currency, e.g. "19.99 USD".
+func (m Money) String() string {
+	return fmt.Sprintf("%s %s", m.Decimal(), m.Currency)
+}
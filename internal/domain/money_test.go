@@ -0,0 +1,177 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinorUnits(t *testing.T) {
+	tests := []struct {
+		code string
+		want int
+	}{
+		{code: "USD", want: 2},
+		{code: "EUR", want: 2},
+		{code: "JPY", want: 0},
+		{code: "BHD", want: 3},
+		{code: "", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			assert.Equal(t, tt.want, MinorUnits(tt.code))
+		})
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		currency string
+		want     int64
+		wantErr  bool
+	}{
+		{name: "cents", amount: "19.99", currency: "USD", want: 1999},
+		{name: "one_place", amount: "0.1", currency: "USD", want: 10},
+		{name: "whole", amount: "12", currency: "USD", want: 1200},
+		{name: "trailing_zeros", amount: "10.500", currency: "USD", want: 1050},
+		{name: "negative", amount: "-0.50", currency: "USD", want: -50},
+		{name: "zero_decimals", amount: "1500", currency: "JPY", want: 1500},
+		{name: "three_decimals", amount: "1.234", currency: "BHD", want: 1234},
+		{name: "too_precise", amount: "10.555", currency: "USD", wantErr: true},
+		{name: "fraction_of_yen", amount: "1.5", currency: "JPY", wantErr: true},
+		{name: "empty", amount: "", currency: "USD", wantErr: true},
+		{name: "no_whole_part", amount: ".5", currency: "USD", wantErr: true},
+		{name: "no_fraction", amount: "5.", currency: "USD", wantErr: true},
+		{name: "exponent", amount: "1e2", currency: "USD", wantErr: true},
+		{name: "overflow", amount: "99999999999999999999", currency: "USD", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.amount, tt.currency)
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidAmount)
+				var amountErr *AmountError
+				require.True(t, errors.As(err, &amountErr))
+				assert.Equal(t, tt.amount, amountErr.Amount)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, Money{Amount: tt.want, Currency: tt.currency}, got)
+		})
+	}
+}
+
+func TestMoney_Decimal(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{money: Money{Amount: 1999, Currency: "USD"}, want: "19.99"},
+		{money: Money{Amount: 5, Currency: "USD"}, want: "0.05"},
+		{money: Money{Amount: 0, Currency: "USD"}, want: "0.00"},
+		{money: Money{Amount: -50, Currency: "USD"}, want: "-0.50"},
+		{money: Money{Amount: 1500, Currency: "JPY"}, want: "1500"},
+		{money: Money{Amount: 1234, Currency: "BHD"}, want: "1.234"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.money.Decimal())
+
+			parsed, err := ParseMoney(tt.want, tt.money.Currency)
+			require.NoError(t, err)
+			assert.Equal(t, tt.money, parsed, "Decimal must round-trip through ParseMoney")
+		})
+	}
+}
+
+func TestMoney_Add(t *testing.T) {
+	sum, err := Money{Amount: 10, Currency: "USD"}.Add(Money{Amount: 20, Currency: "USD"})
+	require.NoError(t, err)
+	assert.Equal(t, Money{Amount: 30, Currency: "USD"}, sum)
+
+	sum, err = Money{}.Add(Money{Amount: 20, Currency: "EUR"})
+	require.NoError(t, err)
+	assert.Equal(t, Money{Amount: 20, Currency: "EUR"}, sum, "zero value takes the other currency")
+
+	_, err = Money{Amount: 10, Currency: "USD"}.Add(Money{Amount: 20, Currency: "EUR"})
+	assert.Equal(t, &CurrencyMismatchError{Want: "USD", Got: "EUR"}, err)
+}
+
+func TestMoney_Add_Overflow(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b int64
+		want int64
+		err  error
+	}{
+		{name: "max", a: math.MaxInt64 - 1, b: 1, want: math.MaxInt64},
+		{name: "min", a: math.MinInt64 + 1, b: -1, want: math.MinInt64},
+		{name: "over_max", a: math.MaxInt64, b: 1, err: ErrAmountOverflow},
+		{name: "under_min", a: math.MinInt64, b: -1, err: ErrAmountOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum, err := Money{Amount: tt.a, Currency: "USD"}.Add(Money{Amount: tt.b, Currency: "USD"})
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, Money{Amount: tt.want, Currency: "USD"}, sum)
+		})
+	}
+}
+
+func TestMoney_Times(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		quantity int
+		want     int64
+		err      error
+	}{
+		{name: "exact", amount: 1050, quantity: 3, want: 3150},
+		{name: "negative", amount: -50, quantity: 3, want: -150},
+		{name: "max", amount: math.MaxInt64, quantity: 1, want: math.MaxInt64},
+		{name: "min", amount: math.MinInt64 / 2, quantity: 2, want: math.MinInt64},
+		{name: "over_max", amount: math.MaxInt64/2 + 1, quantity: 2, err: ErrAmountOverflow},
+		{name: "under_min", amount: math.MinInt64, quantity: 2, err: ErrAmountOverflow},
+		{name: "high_word", amount: math.MaxInt64, quantity: math.MaxInt32, err: ErrAmountOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Money{Amount: tt.amount, Currency: "USD"}.Times(tt.quantity)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, Money{Amount: tt.want, Currency: "USD"}, got)
+		})
+	}
+}
@@ -15,6 +15,9 @@
 package domain
 
 import (
+	"encoding/json"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -31,6 +34,9 @@ const (
 	OrderStatusShipped    OrderStatus = "shipped"
 	OrderStatusDelivered  OrderStatus = "delivered"
 	OrderStatusCancelled  OrderStatus = "cancelled"
+	// OrderStatusExpired is set by the expiry worker on orders left
+	// pending for too long
+	OrderStatusExpired OrderStatus = "expired"
 )
 
 // ValidStatuses returns all valid order statuses
@@ -42,63 +48,229 @@ func ValidStatuses() []OrderStatus {
 		OrderStatusShipped,
 		OrderStatusDelivered,
 		OrderStatusCancelled,
+		OrderStatusExpired,
 	}
 }
 
-// CanTransitionTo checks if status transition is valid
-func (s OrderStatus) CanTransitionTo(newStatus OrderStatus) bool {
-	validTransitions := map[OrderStatus][]OrderStatus{
-		OrderStatusPending:    {OrderStatusConfirmed, OrderStatusCancelled},
-		OrderStatusConfirmed:  {OrderStatusProcessing, OrderStatusCancelled},
-		OrderStatusProcessing: {OrderStatusShipped, OrderStatusCancelled},
-		OrderStatusShipped:    {OrderStatusDelivered},
-		OrderStatusDelivered:  {},
-		OrderStatusCancelled:  {},
-	}
-
-	allowed := validTransitions[s]
-	for _, status := range allowed {
-		if status == newStatus {
+// ParseOrderStatus returns the status named s.
+// Returns a *StatusError if s is not one of ValidStatuses.
+func ParseOrderStatus(s string) (OrderStatus, error) {
+	status := OrderStatus(s)
+	if !slices.Contains(ValidStatuses(), status) {
+		return "", &StatusError{Value: s}
+	}
+	return status, nil
+}
+
+// String returns the status name, such as "pending".
+func (s OrderStatus) String() string {
+	return string(s)
+}
+
+// MarshalJSON encodes the status as its name.
+func (s OrderStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(s))
+}
+
+// UnmarshalJSON decodes a status name, returning a *StatusError for an
+// unknown one. An empty string decodes to the zero OrderStatus, for
+// payloads that carry no status.
+func (s *OrderStatus) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	if name == "" {
+		*s = ""
+		return nil
+	}
+	status, err := ParseOrderStatus(name)
+	if err != nil {
+		return err
+	}
+	*s = status
+	return nil
+}
+
+// transitions is the order state machine: each status maps to the statuses
+// it may move to. Cancellation is only allowed before an order ships, and
+// only pending orders expire.
+var transitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:    {OrderStatusConfirmed, OrderStatusCancelled, OrderStatusExpired},
+	OrderStatusConfirmed:  {OrderStatusProcessing, OrderStatusCancelled},
+	OrderStatusProcessing: {OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusShipped:    {OrderStatusDelivered},
+	OrderStatusDelivered:  {},
+	OrderStatusCancelled:  {},
+	OrderStatusExpired:    {},
+}
+
+// CanTransition reports whether an order may move from one status to another
+func CanTransition(from, to OrderStatus) bool {
+	for _, status := range transitions[from] {
+		if status == to {
 			return true
 		}
 	}
 	return false
 }
 
+// IsTerminal reports whether s is a final status, one no transition
+// leaves: an order in it is no longer open.
+func (s OrderStatus) IsTerminal() bool {
+	next, ok := transitions[s]
+	return ok && len(next) == 0
+}
+
+// TerminalStatuses returns the final statuses, in sorted order.
+func TerminalStatuses() []OrderStatus {
+	var out []OrderStatus
+	for s := range transitions {
+		if s.IsTerminal() {
+			out = append(out, s)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+// CanTransitionTo checks if status transition is valid
+func (s OrderStatus) CanTransitionTo(newStatus OrderStatus) bool {
+	return CanTransition(s, newStatus)
+}
+
 // Order represents a customer order
 type Order struct {
-	ID         uuid.UUID
-	CustomerID string
-	Items      []OrderItem
-	Status     OrderStatus
-	Total      float64
-	Version    int // Optimistic locking version, incremented on each update
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	DeletedAt  *time.Time
-}
-
-// CalculateTotal computes the total from items
-func (o *Order) CalculateTotal() float64 {
-	total := 0.0
-	for _, item := range o.Items {
-		total += item.Subtotal
+	ID           uuid.UUID
+	CustomerID   string
+	Items        []OrderItem
+	Status       OrderStatus
+	Total        Money  // Its Currency is that of the order and every item price
+	Version      int    // Optimistic locking version, incremented on each update
+	EventSeq     int64  // Sequence number of the order's latest event, see messaging.OrderEvent.Seq
+	LastEventID  string // EventID of the order's latest event, the cause of its next; see messaging.OrderEvent.CausationID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    *time.Time
+	CancelReason string // Why the order was cancelled, empty if not given
+}
+
+// TransitionTo moves the order to newStatus at now, which becomes its
+// UpdatedAt.
+// Returns a *TransitionError, leaving the order unchanged, if the state
+// machine does not allow the move. Version is left to the repository, which
+// bumps it when the change is saved.
+func (o *Order) TransitionTo(newStatus OrderStatus, now time.Time) error {
+	if !CanTransition(o.Status, newStatus) {
+		return &TransitionError{From: o.Status, To: newStatus}
 	}
-	return total
+	o.Status = newStatus
+	o.UpdatedAt = now
+	return nil
 }
 
-// Validate performs domain validation
-func (o *Order) Validate() error {
-	if o.CustomerID == "" {
-		return ErrInvalidCustomerID
+// Cancel moves the order to cancelled at now, recording reason.
+// Returns a *CancelError, leaving the order unchanged, if the order has
+// already shipped or is otherwise past cancellation.
+func (o *Order) Cancel(reason string, now time.Time) error {
+	if !CanTransition(o.Status, OrderStatusCancelled) {
+		return &CancelError{Status: o.Status}
+	}
+	o.Status = OrderStatusCancelled
+	o.CancelReason = reason
+	o.UpdatedAt = now
+	return nil
+}
+
+// IsExpired reports whether the order has been pending for longer than
+// ttl at now. An order created exactly ttl ago has not yet expired.
+func (o *Order) IsExpired(now time.Time, ttl time.Duration) bool {
+	return o.Status == OrderStatusPending && o.CreatedAt.Before(now.Add(-ttl))
+}
+
+// Diff returns the names of the fields that differ between prev and o, in
+// the order customer_id, items, total, status, matching the fields of
+// published events. It returns nil if none do. Items are compared by
+// product, name, quantity and unit price, ignoring their IDs, so replacing
+// the items with identical ones is not a change.
+func (o *Order) Diff(prev *Order) []string {
+	var changed []string
+	if o.CustomerID != prev.CustomerID {
+		changed = append(changed, "customer_id")
+	}
+	if !slices.EqualFunc(o.Items, prev.Items, func(a, b OrderItem) bool {
+		return a.ProductID == b.ProductID && a.Name == b.Name && a.Quantity == b.Quantity &&
+			a.Price.Amount == b.Price.Amount && o.itemCurrency(a) == prev.itemCurrency(b)
+	}) {
+		changed = append(changed, "items")
+	}
+	if o.Total != prev.Total {
+		changed = append(changed, "total")
+	}
+	if o.Status != prev.Status {
+		changed = append(changed, "status")
+	}
+	return changed
+}
+
+// itemCurrency returns the currency item is priced in, which is the
+// order's if the item does not say.
+func (o *Order) itemCurrency(item OrderItem) string {
+	if item.Price.Currency == "" {
+		return o.Total.Currency
 	}
-	if len(o.Items) == 0 {
-		return ErrNoItems
+	return item.Price.Currency
+}
+
+// RecalculateTotal refreshes each item's subtotal from quantity * price and
+// sets Total to their sum. Call after changing Items.
+// Returns a *CurrencyMismatchError, leaving the order unchanged, if an item
+// is priced in a currency other than the order's, and ErrAmountOverflow if
+// the total does not fit in an int64. An order without a currency takes
+// the one its items are priced in.
+func (o *Order) RecalculateTotal() (Money, error) {
+	total, err := o.CalculateTotal()
+	if err != nil {
+		return Money{}, err
 	}
+
+	for i := range o.Items {
+		o.Items[i].Price.Currency = total.Currency
+		// CalculateTotal has already computed each subtotal without error
+		o.Items[i].Subtotal, _ = o.Items[i].CalculateSubtotal()
+	}
+	o.Total = total
+	return total, nil
+}
+
+// CalculateTotal computes the total of quantity * price over items, in the
+// order's currency. Returns a *CurrencyMismatchError if an item is priced
+// in another, or ErrAmountOverflow if a subtotal or the total does not fit
+// in an int64.
+func (o *Order) CalculateTotal() (Money, error) {
+	total := Money{Currency: o.Total.Currency}
 	for _, item := range o.Items {
-		if err := item.Validate(); err != nil {
-			return err
+		subtotal, err := item.CalculateSubtotal()
+		if err != nil {
+			return Money{}, err
+		}
+		if total, err = total.Add(subtotal); err != nil {
+			return Money{}, err
 		}
 	}
-	return nil
+	return total, nil
+}
+
+// Validate checks every field of the order and its items, returning a
+// ValidationErrors listing all the invalid ones, or nil.
+func (o *Order) Validate() error {
+	errs := ValidationErrors{}
+	errs.collect("", orderRules(o))
+	for i := range o.Items {
+		errs.collect(fmt.Sprintf("items[%d].", i), itemRules(&o.Items[i]))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
 }
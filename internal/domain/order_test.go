@@ -0,0 +1,488 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransition_AllPairs(t *testing.T) {
+	legal := map[OrderStatus]map[OrderStatus]bool{
+		OrderStatusPending:    {OrderStatusConfirmed: true, OrderStatusCancelled: true, OrderStatusExpired: true},
+		OrderStatusConfirmed:  {OrderStatusProcessing: true, OrderStatusCancelled: true},
+		OrderStatusProcessing: {OrderStatusShipped: true, OrderStatusCancelled: true},
+		OrderStatusShipped:    {OrderStatusDelivered: true},
+		OrderStatusDelivered:  {},
+		OrderStatusCancelled:  {},
+		OrderStatusExpired:    {},
+	}
+
+	for _, from := range ValidStatuses() {
+		for _, to := range ValidStatuses() {
+			want := legal[from][to]
+			t.Run(string(from)+"_to_"+string(to), func(t *testing.T) {
+				assert.Equal(t, want, CanTransition(from, to))
+				assert.Equal(t, want, from.CanTransitionTo(to))
+			})
+		}
+	}
+}
+
+func TestCanTransition_UnknownStatus_ReturnsFalse(t *testing.T) {
+	assert.False(t, CanTransition("unknown", OrderStatusConfirmed))
+	assert.False(t, CanTransition(OrderStatusPending, "unknown"))
+}
+
+func TestOrder_TransitionTo_Legal_UpdatesStatus(t *testing.T) {
+	order := &Order{Status: OrderStatusPending}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	err := order.TransitionTo(OrderStatusConfirmed, now)
+
+	require.NoError(t, err)
+	assert.Equal(t, OrderStatusConfirmed, order.Status)
+	assert.Equal(t, now, order.UpdatedAt)
+}
+
+func TestOrder_TransitionTo_Illegal_ReturnsErrInvalidTransition(t *testing.T) {
+	tests := []struct {
+		name string
+		from OrderStatus
+		to   OrderStatus
+	}{
+		{"shipped back to pending", OrderStatusShipped, OrderStatusPending},
+		{"cancel after shipped", OrderStatusShipped, OrderStatusCancelled},
+		{"skip confirmation", OrderStatusPending, OrderStatusShipped},
+		{"reopen cancelled", OrderStatusCancelled, OrderStatusPending},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &Order{Status: tt.from}
+
+			err := order.TransitionTo(tt.to, time.Now())
+
+			assert.ErrorIs(t, err, ErrInvalidTransition)
+			assert.Equal(t, tt.from, order.Status, "status must be unchanged")
+			assert.True(t, order.UpdatedAt.IsZero())
+		})
+	}
+}
+
+func TestOrder_TransitionTo_AllPairs(t *testing.T) {
+	for _, from := range ValidStatuses() {
+		for _, to := range ValidStatuses() {
+			t.Run(string(from)+"_to_"+string(to), func(t *testing.T) {
+				order := &Order{Status: from, Version: 3}
+
+				err := order.TransitionTo(to, time.Now())
+
+				if CanTransition(from, to) {
+					require.NoError(t, err)
+					assert.Equal(t, to, order.Status)
+				} else {
+					var terr *TransitionError
+					require.ErrorAs(t, err, &terr)
+					assert.ErrorIs(t, err, ErrInvalidTransition)
+					assert.Equal(t, TransitionError{From: from, To: to}, *terr)
+					assert.Equal(t, from, order.Status, "status must be unchanged")
+				}
+				assert.Equal(t, 3, order.Version, "version is bumped by the repository on save")
+			})
+		}
+	}
+}
+
+func TestTransitionError_Error_NamesBothStatuses(t *testing.T) {
+	err := &TransitionError{From: OrderStatusDelivered, To: OrderStatusPending}
+
+	assert.Equal(t, "invalid status transition from delivered to pending", err.Error())
+}
+
+func TestOrder_Cancel_BeforeShipping_RecordsReason(t *testing.T) {
+	for _, from := range []OrderStatus{OrderStatusPending, OrderStatusConfirmed, OrderStatusProcessing} {
+		t.Run(string(from), func(t *testing.T) {
+			order := &Order{Status: from}
+
+			require.NoError(t, order.Cancel("customer request", time.Now()))
+
+			assert.Equal(t, OrderStatusCancelled, order.Status)
+			assert.Equal(t, "customer request", order.CancelReason)
+		})
+	}
+}
+
+func TestOrder_Cancel_AfterShipping_ReturnsCancelError(t *testing.T) {
+	for _, from := range []OrderStatus{OrderStatusShipped, OrderStatusDelivered, OrderStatusCancelled} {
+		t.Run(string(from), func(t *testing.T) {
+			order := &Order{Status: from}
+
+			err := order.Cancel("too late", time.Now())
+
+			var cerr *CancelError
+			require.ErrorAs(t, err, &cerr)
+			assert.Equal(t, from, cerr.Status)
+			assert.ErrorIs(t, err, ErrOrderNotCancellable)
+			assert.Equal(t, from, order.Status, "status must be unchanged")
+			assert.Empty(t, order.CancelReason)
+		})
+	}
+}
+
+func TestOrder_RecalculateTotal_SumsQuantityTimesPrice(t *testing.T) {
+	order := &Order{
+		Items: []OrderItem{
+			{ProductID: "p1", Quantity: 2, Price: Money{Amount: 1050}, Subtotal: Money{Amount: 999}},
+			{ProductID: "p2", Quantity: 3, Price: Money{Amount: 125}},
+		},
+		Total: Money{Amount: 1, Currency: "USD"},
+	}
+
+	total, err := order.RecalculateTotal()
+
+	require.NoError(t, err)
+	assert.Equal(t, Money{Amount: 2475, Currency: "USD"}, total)
+	assert.Equal(t, total, order.Total)
+	assert.Equal(t, Money{Amount: 2100, Currency: "USD"}, order.Items[0].Subtotal, "stale subtotal must be refreshed")
+	assert.Equal(t, Money{Amount: 375, Currency: "USD"}, order.Items[1].Subtotal)
+}
+
+func TestOrder_RecalculateTotal_ExactForDecimalPrices(t *testing.T) {
+	price, err := ParseMoney("0.10", "USD")
+	require.NoError(t, err)
+	order := &Order{
+		Items: []OrderItem{
+			{ProductID: "p1", Quantity: 1, Price: price},
+			{ProductID: "p2", Quantity: 1, Price: price},
+			{ProductID: "p3", Quantity: 1, Price: price},
+		},
+		Total: Money{Currency: "USD"},
+	}
+	dime := 0.10
+	require.NotEqual(t, 0.30, dime+dime+dime, "float64 sums lose the cent here")
+
+	total, err := order.RecalculateTotal()
+
+	require.NoError(t, err)
+	assert.Equal(t, Money{Amount: 30, Currency: "USD"}, total)
+	assert.Equal(t, "0.30", total.Decimal())
+	assert.Equal(t, 0.3, total.Float64())
+}
+
+func TestOrder_RecalculateTotal_Overflow_LeavesOrderUnchanged(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []OrderItem
+	}{
+		{
+			name:  "subtotal",
+			items: []OrderItem{{ProductID: "p1", Quantity: 2, Price: Money{Amount: math.MaxInt64/2 + 1}}},
+		},
+		{
+			name: "total",
+			items: []OrderItem{
+				{ProductID: "p1", Quantity: 1, Price: Money{Amount: math.MaxInt64}},
+				{ProductID: "p2", Quantity: 1, Price: Money{Amount: 1}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &Order{Items: tt.items, Total: Money{Amount: 1, Currency: "USD"}}
+
+			_, err := order.RecalculateTotal()
+
+			require.ErrorIs(t, err, ErrAmountOverflow)
+			assert.Equal(t, Money{Amount: 1, Currency: "USD"}, order.Total)
+			assert.Equal(t, Money{}, order.Items[0].Subtotal)
+		})
+	}
+}
+
+func TestValidCurrency(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{code: "USD", want: true},
+		{code: "EUR", want: true},
+		{code: "JPY", want: true},
+		{code: "usd", want: false},
+		{code: "XYZ", want: false},
+		{code: "US", want: false},
+		{code: "EURO", want: false},
+		{code: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			assert.Equal(t, tt.want, ValidCurrency(tt.code))
+		})
+	}
+}
+
+func TestOrder_Validate_Currency(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency string
+		wantErr  error
+	}{
+		{name: "valid", currency: "EUR"},
+		{name: "missing", currency: "", wantErr: ErrInvalidCurrency},
+		{name: "unknown", currency: "ABC", wantErr: ErrInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &Order{
+				CustomerID: "cust-1",
+				Total:      Money{Currency: tt.currency},
+				Status:     OrderStatusPending,
+				Items:      []OrderItem{{ProductID: "p1", Name: "Widget", Quantity: 1, Price: Money{Amount: 500}}},
+			}
+
+			assert.ErrorIs(t, order.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestOrderItem_Validate_InvalidCurrency_ReturnsErrInvalidCurrency(t *testing.T) {
+	item := OrderItem{ProductID: "p1", Name: "Widget", Quantity: 1, Price: Money{Amount: 500, Currency: "dollars"}}
+
+	assert.ErrorIs(t, item.Validate(), ErrInvalidCurrency)
+}
+
+func TestOrder_RecalculateTotal_Currencies(t *testing.T) {
+	tests := []struct {
+		name          string
+		orderCurrency string
+		itemCurrency  [2]string
+		wantCurrency  string
+		wantMismatch  *CurrencyMismatchError
+	}{
+		{name: "items_inherit_order", orderCurrency: "EUR", wantCurrency: "EUR"},
+		{name: "items_match_order", orderCurrency: "EUR", itemCurrency: [2]string{"EUR", "EUR"}, wantCurrency: "EUR"},
+		{name: "order_takes_item_currency", itemCurrency: [2]string{"", "GBP"}, wantCurrency: "GBP"},
+		{
+			name:          "item_differs_from_order",
+			orderCurrency: "EUR",
+			itemCurrency:  [2]string{"EUR", "USD"},
+			wantMismatch:  &CurrencyMismatchError{Want: "EUR", Got: "USD"},
+		},
+		{
+			name:         "items_differ",
+			itemCurrency: [2]string{"JPY", "USD"},
+			wantMismatch: &CurrencyMismatchError{Want: "JPY", Got: "USD"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &Order{
+				Items: []OrderItem{
+					{ProductID: "p1", Quantity: 1, Price: Money{Amount: 200, Currency: tt.itemCurrency[0]}},
+					{ProductID: "p2", Quantity: 1, Price: Money{Amount: 300, Currency: tt.itemCurrency[1]}},
+				},
+				Total: Money{Amount: 1, Currency: tt.orderCurrency},
+			}
+
+			total, err := order.RecalculateTotal()
+
+			if tt.wantMismatch != nil {
+				require.ErrorIs(t, err, ErrCurrencyMismatch)
+				var mismatch *CurrencyMismatchError
+				require.ErrorAs(t, err, &mismatch)
+				assert.Equal(t, tt.wantMismatch, mismatch)
+				assert.Equal(t, Money{Amount: 1, Currency: tt.orderCurrency}, order.Total, "total must be unchanged")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, Money{Amount: 500, Currency: tt.wantCurrency}, total)
+			for _, item := range order.Items {
+				assert.Equal(t, tt.wantCurrency, item.Price.Currency, "item prices take the order's currency")
+			}
+		})
+	}
+}
+
+func TestParseOrderStatus_ValidStatuses(t *testing.T) {
+	for _, want := range ValidStatuses() {
+		t.Run(want.String(), func(t *testing.T) {
+			got, err := ParseOrderStatus(want.String())
+
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestParseOrderStatus_Unknown_ReturnsStatusError(t *testing.T) {
+	for _, s := range []string{"", "Pending", "shiped", " pending"} {
+		t.Run(s, func(t *testing.T) {
+			got, err := ParseOrderStatus(s)
+
+			require.ErrorIs(t, err, ErrInvalidStatus)
+			var statusErr *StatusError
+			require.ErrorAs(t, err, &statusErr)
+			assert.Equal(t, s, statusErr.Value)
+			assert.Empty(t, got)
+		})
+	}
+}
+
+func TestOrderStatus_JSON_RoundTrip(t *testing.T) {
+	type payload struct {
+		Status OrderStatus  `json:"status"`
+		Old    OrderStatus  `json:"old,omitempty"`
+		Ptr    *OrderStatus `json:"ptr"`
+	}
+	shipped := OrderStatusShipped
+	in := payload{Status: OrderStatusConfirmed, Ptr: &shipped}
+
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"status":"confirmed","ptr":"shipped"}`, string(data))
+
+	var out payload
+	require.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+}
+
+func TestOrderStatus_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    OrderStatus
+		wantErr error
+	}{
+		{name: "known status", data: `"delivered"`, want: OrderStatusDelivered},
+		{name: "empty is no status", data: `""`, want: ""},
+		{name: "unknown status", data: `"lost"`, wantErr: ErrInvalidStatus},
+		{name: "wrong case", data: `"CANCELLED"`, wantErr: ErrInvalidStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := OrderStatusPending
+			err := json.Unmarshal([]byte(tt.data), &got)
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	var got OrderStatus
+	assert.Error(t, json.Unmarshal([]byte(`3`), &got), "not a string")
+}
+
+func TestOrder_Diff(t *testing.T) {
+	base := func() *Order {
+		return &Order{
+			CustomerID: "cust-1",
+			Items: []OrderItem{
+				{ID: uuid.New(), ProductID: "p-1", Name: "Widget", Quantity: 2, Price: Money{Amount: 1000}, Subtotal: Money{Amount: 2000}},
+			},
+			Status: OrderStatusPending,
+			Total:  Money{Amount: 2000, Currency: "USD"},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(*Order)
+		want   []string
+	}{
+		{"no_change", func(*Order) {}, nil},
+		{"version_and_timestamps_ignored", func(o *Order) {
+			o.Version++
+			o.UpdatedAt = o.UpdatedAt.Add(time.Hour)
+		}, nil},
+		{"identical_items_with_new_ids", func(o *Order) {
+			o.Items = []OrderItem{{ID: uuid.New(), ProductID: "p-1", Name: "Widget", Quantity: 2, Price: Money{Amount: 1000, Currency: "USD"}}}
+		}, nil},
+		{"customer", func(o *Order) { o.CustomerID = "cust-2" }, []string{"customer_id"}},
+		{"status", func(o *Order) { o.Status = OrderStatusConfirmed }, []string{"status"}},
+		{"items_and_total", func(o *Order) {
+			o.Items[0].Quantity = 3
+			o.Total.Amount = 3000
+		}, []string{"items", "total"}},
+		{"item_added", func(o *Order) {
+			o.Items = append(o.Items, OrderItem{ProductID: "p-2", Name: "Gadget", Quantity: 1, Price: Money{Amount: 500}})
+		}, []string{"items"}},
+		{"every_field", func(o *Order) {
+			o.CustomerID = "cust-2"
+			o.Items[0].Price.Amount = 1500
+			o.Total.Amount = 3000
+			o.Status = OrderStatusConfirmed
+		}, []string{"customer_id", "items", "total", "status"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prev := base()
+			o := base()
+			o.Items[0].ID = prev.Items[0].ID
+			tt.change(o)
+
+			assert.Equal(t, tt.want, o.Diff(prev))
+		})
+	}
+}
+
+func TestOrder_IsExpired(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	const ttl = 24 * time.Hour
+
+	tests := []struct {
+		name      string
+		status    OrderStatus
+		createdAt time.Time
+		want      bool
+	}{
+		{name: "pending past the TTL", status: OrderStatusPending, createdAt: now.Add(-ttl - time.Nanosecond), want: true},
+		{name: "pending exactly the TTL", status: OrderStatusPending, createdAt: now.Add(-ttl), want: false},
+		{name: "pending within the TTL", status: OrderStatusPending, createdAt: now.Add(-time.Hour), want: false},
+		{name: "confirmed past the TTL", status: OrderStatusConfirmed, createdAt: now.Add(-2 * ttl), want: false},
+		{name: "already expired", status: OrderStatusExpired, createdAt: now.Add(-2 * ttl), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &Order{Status: tt.status, CreatedAt: tt.createdAt}
+
+			assert.Equal(t, tt.want, order.IsExpired(now, ttl))
+		})
+	}
+}
+
+func TestOrderStatus_IsTerminal(t *testing.T) {
+	assert.Equal(t, []OrderStatus{OrderStatusCancelled, OrderStatusDelivered, OrderStatusExpired}, TerminalStatuses())
+	assert.True(t, OrderStatusCancelled.IsTerminal())
+	assert.False(t, OrderStatusPending.IsTerminal())
+	assert.False(t, OrderStatusShipped.IsTerminal())
+	assert.False(t, OrderStatus("unknown").IsTerminal())
+}
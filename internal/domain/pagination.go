@@ -14,6 +14,14 @@
 
 package domain
 
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
 // PaginatedOrders represents a paginated list of orders
 type PaginatedOrders struct {
 	Data       []*Order
@@ -21,4 +29,46 @@ type PaginatedOrders struct {
 	PageSize   int
 	TotalCount int64
 	TotalPages int
+	NextCursor string // Empty when there are no more results
+}
+
+// Cursor is a position in the order listing, which is sorted by
+// (created_at, id) descending. Keying on the last row seen rather than an
+// offset keeps pages stable when new orders are inserted.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// CursorAfter returns the cursor positioned just after order
+func CursorAfter(order *Order) Cursor {
+	return Cursor{CreatedAt: order.CreatedAt, ID: order.ID}
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c Cursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor produced by Cursor.Encode.
+// Returns ErrInvalidCursor if s is malformed.
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	ts, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	orderID, err := uuid.Parse(id)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return Cursor{CreatedAt: createdAt, ID: orderID}, nil
 }
@@ -0,0 +1,56 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_EncodeDecode_RoundTrip(t *testing.T) {
+	c := Cursor{
+		CreatedAt: time.Date(2026, 3, 1, 12, 30, 0, 123456789, time.UTC),
+		ID:        uuid.New(),
+	}
+
+	got, err := DecodeCursor(c.Encode())
+
+	require.NoError(t, err)
+	assert.True(t, c.CreatedAt.Equal(got.CreatedAt))
+	assert.Equal(t, c.ID, got.ID)
+}
+
+func TestDecodeCursor_Malformed_ReturnsErrInvalidCursor(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "!!!"},
+		{name: "missing separator", cursor: "bm9zZXBhcmF0b3I"},
+		{name: "bad timestamp", cursor: "eWVzdGVyZGF5fDAwMDAwMDAwLTAwMDAtMDAwMC0wMDAwLTAwMDAwMDAwMDAwMA"},
+		{name: "bad id", cursor: "MjAyNi0wMy0wMVQxMjozMDowMFp8bm90LWEtdXVpZA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeCursor(tt.cursor)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
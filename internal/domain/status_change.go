@@ -0,0 +1,31 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatusChange records one transition of an order's status, as stored in
+// its status history.
+type StatusChange struct {
+	OrderID    uuid.UUID
+	OldStatus  OrderStatus
+	NewStatus  OrderStatus
+	ChangedBy  string // Who made the change, empty if unknown
+	OccurredAt time.Time
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ValidationErrors reports every invalid field of an order, keyed by the
+// field's name in requests, such as "customer_id" or "items[0].quantity".
+// Each value is the sentinel of the rule the field broke, which errors.Is
+// matches, so a ValidationErrors with a "customer_id" entry matches
+// ErrInvalidCustomerID. It also matches ErrValidation, whatever its fields.
+type ValidationErrors map[string]error
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, field := range e.Fields() {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", field, e[field]))
+	}
+	return "invalid order: " + strings.Join(msgs, "; ")
+}
+
+// Is reports whether target is ErrValidation.
+func (e ValidationErrors) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Unwrap returns the field errors, in field order.
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, field := range e.Fields() {
+		errs = append(errs, e[field])
+	}
+	return errs
+}
+
+// Fields returns the invalid fields, sorted.
+func (e ValidationErrors) Fields() []string {
+	return slices.Sorted(maps.Keys(e))
+}
+
+// Messages returns the error message of each invalid field.
+func (e ValidationErrors) Messages() map[string]string {
+	msgs := make(map[string]string, len(e))
+	for field, err := range e {
+		msgs[field] = err.Error()
+	}
+	return msgs
+}
+
+// rule is a validation check: field is invalid with err if broken.
+type rule struct {
+	field  string
+	broken bool
+	err    error
+}
+
+// orderRules returns the checks of o's own fields, in the order Validate
+// reports them. Its items are checked by itemRules.
+func orderRules(o *Order) []rule {
+	return []rule{
+		{"customer_id", o.CustomerID == "", ErrInvalidCustomerID},
+		{"currency", !ValidCurrency(o.Total.Currency), ErrInvalidCurrency},
+		{"total", o.Total.Amount < 0, ErrNegativeTotal},
+		{"status", !slices.Contains(ValidStatuses(), o.Status), &StatusError{Value: string(o.Status)}},
+		{"items", len(o.Items) == 0, ErrNoItems},
+	}
+}
+
+// itemRules returns the checks of a line item, in the order
+// OrderItem.Validate applies them.
+func itemRules(i *OrderItem) []rule {
+	return []rule{
+		{"product_id", i.ProductID == "", ErrInvalidProductID},
+		{"name", i.Name == "", ErrInvalidProductName},
+		{"quantity", i.Quantity <= 0, ErrInvalidQuantity},
+		{"price", i.Price.Amount <= 0, ErrInvalidPrice},
+		{"currency", i.Price.Currency != "" && !ValidCurrency(i.Price.Currency), ErrInvalidCurrency},
+	}
+}
+
+// firstBroken returns the error of the first broken rule, or nil.
+func firstBroken(rules []rule) error {
+	for _, r := range rules {
+		if r.broken {
+			return r.err
+		}
+	}
+	return nil
+}
+
+// collect adds the broken rules to errs, naming their fields under prefix.
+func (e ValidationErrors) collect(prefix string, rules []rule) {
+	for _, r := range rules {
+		if r.broken {
+			e[prefix+r.field] = r.err
+		}
+	}
+}
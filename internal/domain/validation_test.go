@@ -0,0 +1,104 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validOrder() *Order {
+	return &Order{
+		CustomerID: "cust-1",
+		Status:     OrderStatusPending,
+		Total:      Money{Amount: 1000, Currency: "USD"},
+		Items:      []OrderItem{{ProductID: "p1", Name: "Widget", Quantity: 2, Price: Money{Amount: 500}}},
+	}
+}
+
+func TestOrder_Validate_Rules(t *testing.T) {
+	tests := []struct {
+		name      string
+		mutate    func(o *Order)
+		wantField string // Empty means valid
+		wantErr   error
+	}{
+		{name: "valid", mutate: func(*Order) {}},
+		{name: "empty customer ID", mutate: func(o *Order) { o.CustomerID = "" }, wantField: "customer_id", wantErr: ErrInvalidCustomerID},
+		{name: "unknown currency", mutate: func(o *Order) { o.Total.Currency = "XYZ" }, wantField: "currency", wantErr: ErrInvalidCurrency},
+		{name: "negative total", mutate: func(o *Order) { o.Total.Amount = -1 }, wantField: "total", wantErr: ErrNegativeTotal},
+		{name: "zero total is valid", mutate: func(o *Order) { o.Total.Amount = 0 }},
+		{name: "unknown status", mutate: func(o *Order) { o.Status = "lost" }, wantField: "status", wantErr: ErrInvalidStatus},
+		{name: "missing status", mutate: func(o *Order) { o.Status = "" }, wantField: "status", wantErr: ErrInvalidStatus},
+		{name: "no line items", mutate: func(o *Order) { o.Items = nil }, wantField: "items", wantErr: ErrNoItems},
+		{name: "item without product ID", mutate: func(o *Order) { o.Items[0].ProductID = "" }, wantField: "items[0].product_id", wantErr: ErrInvalidProductID},
+		{name: "item without name", mutate: func(o *Order) { o.Items[0].Name = "" }, wantField: "items[0].name", wantErr: ErrInvalidProductName},
+		{name: "item with zero quantity", mutate: func(o *Order) { o.Items[0].Quantity = 0 }, wantField: "items[0].quantity", wantErr: ErrInvalidQuantity},
+		{name: "item with zero price", mutate: func(o *Order) { o.Items[0].Price.Amount = 0 }, wantField: "items[0].price", wantErr: ErrInvalidPrice},
+		{name: "item in unknown currency", mutate: func(o *Order) { o.Items[0].Price.Currency = "dollars" }, wantField: "items[0].currency", wantErr: ErrInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := validOrder()
+			tt.mutate(order)
+
+			err := order.Validate()
+
+			if tt.wantField == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var verrs ValidationErrors
+			require.ErrorAs(t, err, &verrs)
+			assert.Equal(t, []string{tt.wantField}, verrs.Fields())
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestOrder_Validate_ReportsEveryInvalidField(t *testing.T) {
+	order := validOrder()
+	order.CustomerID = ""
+	order.Status = "lost"
+	order.Items = append(order.Items, OrderItem{ProductID: "p2", Quantity: 0, Price: Money{Amount: 100}})
+
+	err := order.Validate()
+
+	var verrs ValidationErrors
+	require.ErrorAs(t, err, &verrs)
+	assert.Equal(t, []string{"customer_id", "items[1].name", "items[1].quantity", "status"}, verrs.Fields())
+	assert.Equal(t, map[string]string{
+		"customer_id":       "invalid customer ID",
+		"items[1].name":     "invalid product name",
+		"items[1].quantity": "quantity must be greater than 0",
+		"status":            `invalid order status "lost"`,
+	}, verrs.Messages())
+	assert.EqualError(t, err, `invalid order: customer_id: invalid customer ID; items[1].name: invalid product name; `+
+		`items[1].quantity: quantity must be greater than 0; status: invalid order status "lost"`)
+	assert.True(t, errors.Is(err, ErrInvalidQuantity) && errors.Is(err, ErrInvalidStatus))
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.ErrorIs(t, fmt.Errorf("create order: %w", err), ErrValidation, "matches when wrapped")
+}
+
+func TestOrderItem_Validate_ReturnsFirstBrokenRule(t *testing.T) {
+	item := OrderItem{ProductID: "", Name: "", Quantity: 1, Price: Money{Amount: 100}}
+
+	assert.Equal(t, ErrInvalidProductID, item.Validate())
+}
@@ -2,8 +2,11 @@
 package grpc
 
 import (
+	"strconv"
+
 	orderv1 "github.com/sridharn-code-sandbox/go-ordersvc/api/proto/order/v1"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/service"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -15,8 +18,8 @@ func orderToProto(o *domain.Order) *orderv1.Order {
 			ProductId: item.ProductID,
 			Name:      item.Name,
 			Quantity:  int32(item.Quantity), // #nosec G115 -- quantity is bounded by validation
-			Price:     item.Price,
-			Subtotal:  item.Subtotal,
+			Price:     item.Price.Float64(),
+			Subtotal:  item.Subtotal.Float64(),
 		}
 	}
 	return &orderv1.Order{
@@ -24,9 +27,27 @@ func orderToProto(o *domain.Order) *orderv1.Order {
 		CustomerId: o.CustomerID,
 		Items:      items,
 		Status:     string(o.Status),
-		Total:      o.Total,
+		Total:      o.Total.Float64(),
+		Currency:   o.Total.Currency,
 		Version:    int32(o.Version), // #nosec G115 -- version is a small incrementing counter
 		CreatedAt:  timestamppb.New(o.CreatedAt),
 		UpdatedAt:  timestamppb.New(o.UpdatedAt),
 	}
 }
+
+// protoToOrderItems converts request items to service DTOs. Prices are
+// formatted as the shortest decimal that reads back as the same double, so
+// 10.1 is parsed as exactly 10.10 rather than its binary approximation.
+func protoToOrderItems(items []*orderv1.CreateOrderItem) []service.OrderItemDTO {
+	out := make([]service.OrderItemDTO, len(items))
+	for i, item := range items {
+		out[i] = service.OrderItemDTO{
+			ProductID: item.GetProductId(),
+			Name:      item.GetName(),
+			Quantity:  int(item.GetQuantity()),
+			Price:     strconv.FormatFloat(item.GetPrice(), 'f', -1, 64),
+			Currency:  item.GetCurrency(),
+		}
+	}
+	return out
+}
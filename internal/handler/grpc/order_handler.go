@@ -2,7 +2,7 @@ package grpc
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 
@@ -10,11 +10,12 @@ import (
 	orderv1 "github.com/sridharn-code-sandbox/go-ordersvc/api/proto/order/v1"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/config"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
-	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	kafkamsg "github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/kafka"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/service"
 	"github.com/segmentio/kafka-go"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -23,16 +24,51 @@ type orderHandler struct {
 	orderv1.UnimplementedOrderServiceServer
 	svc      service.OrderService
 	kafkaCfg config.KafkaConfig
+	logger   *slog.Logger
 }
 
 // RegisterOrderServer registers the gRPC order service on the given server.
-func RegisterOrderServer(srv *grpc.Server, svc service.OrderService, kafkaCfg config.KafkaConfig) {
+// WatchOrders streams log the events they cannot decode to logger.
+func RegisterOrderServer(srv *grpc.Server, svc service.OrderService, kafkaCfg config.KafkaConfig, logger *slog.Logger) {
 	orderv1.RegisterOrderServiceServer(srv, &orderHandler{
 		svc:      svc,
 		kafkaCfg: kafkaCfg,
+		logger:   logger,
 	})
 }
 
+// idempotencyKeyHeader is the metadata key carrying an idempotency key,
+// the gRPC counterpart of the HTTP Idempotency-Key header.
+const idempotencyKeyHeader = "idempotency-key"
+
+func (h *orderHandler) CreateOrder(ctx context.Context, req *orderv1.CreateOrderRequest) (*orderv1.CreateOrderResponse, error) {
+	if req.GetCustomerId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "customer_id is required")
+	}
+	if len(req.GetItems()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "items are required")
+	}
+
+	dto := service.CreateOrderDTO{
+		CustomerID: req.GetCustomerId(),
+		Currency:   req.GetCurrency(),
+		Items:      protoToOrderItems(req.GetItems()),
+	}
+
+	var key string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(idempotencyKeyHeader); len(v) > 0 {
+			key = v[0]
+		}
+	}
+
+	order, _, err := h.svc.CreateOrderIdempotent(ctx, key, dto)
+	if err != nil {
+		return nil, domainToGRPCError(err)
+	}
+	return &orderv1.CreateOrderResponse{Order: orderToProto(order)}, nil
+}
+
 func (h *orderHandler) GetOrder(ctx context.Context, req *orderv1.GetOrderRequest) (*orderv1.GetOrderResponse, error) {
 	order, err := h.svc.GetOrderByID(ctx, req.GetOrderId())
 	if err != nil {
@@ -74,6 +110,22 @@ func (h *orderHandler) ListOrders(ctx context.Context, req *orderv1.ListOrdersRe
 	}, nil
 }
 
+func (h *orderHandler) UpdateStatus(ctx context.Context, req *orderv1.UpdateStatusRequest) (*orderv1.UpdateStatusResponse, error) {
+	if req.GetStatus() == "" {
+		return nil, status.Error(codes.InvalidArgument, "status is required")
+	}
+	// Expiry follows the TTL and publishes its own event
+	if domain.OrderStatus(req.GetStatus()) == domain.OrderStatusExpired {
+		return nil, status.Error(codes.InvalidArgument, "orders are only expired by the expiry worker")
+	}
+
+	order, err := h.svc.UpdateOrderStatus(ctx, req.GetOrderId(), domain.OrderStatus(req.GetStatus()))
+	if err != nil {
+		return nil, domainToGRPCError(err)
+	}
+	return &orderv1.UpdateStatusResponse{Order: orderToProto(order)}, nil
+}
+
 func (h *orderHandler) WatchOrders(req *orderv1.WatchOrdersRequest, stream grpc.ServerStreamingServer[orderv1.OrderEvent]) error {
 	if len(h.kafkaCfg.Brokers) == 0 || h.kafkaCfg.Brokers[0] == "" {
 		return status.Error(codes.Unavailable, "Kafka not configured")
@@ -88,7 +140,7 @@ func (h *orderHandler) WatchOrders(req *orderv1.WatchOrdersRequest, stream grpc.
 	})
 	defer func() {
 		if err := reader.Close(); err != nil {
-			slog.Warn("failed to close Kafka reader", slog.String("error", err.Error()))
+			h.logger.Warn("failed to close Kafka reader", slog.String("error", err.Error()))
 		}
 	}()
 
@@ -108,15 +160,15 @@ func (h *orderHandler) WatchOrders(req *orderv1.WatchOrdersRequest, stream grpc.
 			return status.Errorf(codes.Internal, "failed to read Kafka message: %v", err)
 		}
 
-		var evt messaging.OrderEvent
-		if err := json.Unmarshal(msg.Value, &evt); err != nil {
-			slog.Warn("failed to unmarshal event", slog.String("error", err.Error()))
+		evt, err := kafkamsg.Decode(msg)
+		if err != nil {
+			h.logger.Warn("failed to unmarshal event", slog.String("error", err.Error()))
 			continue
 		}
 
 		// Apply status filter if specified
 		if len(statusFilter) > 0 {
-			if _, ok := statusFilter[evt.Status]; !ok {
+			if _, ok := statusFilter[evt.Status.String()]; !ok {
 				continue
 			}
 		}
@@ -125,10 +177,11 @@ func (h *orderHandler) WatchOrders(req *orderv1.WatchOrdersRequest, stream grpc.
 			EventType:  evt.EventType,
 			OrderId:    evt.OrderID,
 			CustomerId: evt.CustomerID,
-			Status:     evt.Status,
-			OldStatus:  evt.OldStatus,
-			NewStatus:  evt.NewStatus,
+			Status:     evt.Status.String(),
+			OldStatus:  evt.OldStatus.String(),
+			NewStatus:  evt.NewStatus.String(),
 			Total:      evt.Total,
+			Currency:   evt.Currency,
 			Version:    int32(evt.Version), // #nosec G115 -- version is a small incrementing counter
 			OccurredAt: timestamppb.New(evt.OccurredAt),
 		}
@@ -140,15 +193,39 @@ func (h *orderHandler) WatchOrders(req *orderv1.WatchOrdersRequest, stream grpc.
 }
 
 func domainToGRPCError(err error) error {
-	switch err {
-	case domain.ErrOrderNotFound:
+	// Matched with errors.Is: transition and cancel errors are typed, and
+	// the service may wrap the sentinels
+	switch {
+	case errors.Is(err, domain.ErrOrderNotFound):
 		return status.Error(codes.NotFound, err.Error())
-	case domain.ErrInvalidCustomerID, domain.ErrNoItems, domain.ErrInvalidQuantity,
-		domain.ErrInvalidPrice, domain.ErrInvalidProductID, domain.ErrInvalidProductName,
-		domain.ErrInvalidTransition:
+	case errors.Is(err, domain.ErrInvalidTransition),
+		errors.Is(err, domain.ErrInvalidStatus),
+		errors.Is(err, domain.ErrInvalidCustomerID),
+		errors.Is(err, domain.ErrNoItems),
+		errors.Is(err, domain.ErrNegativeTotal),
+		errors.Is(err, domain.ErrInvalidQuantity),
+		errors.Is(err, domain.ErrInvalidPrice),
+		errors.Is(err, domain.ErrInvalidProductID),
+		errors.Is(err, domain.ErrInvalidProductName),
+		errors.Is(err, domain.ErrInvalidCurrency),
+		errors.Is(err, domain.ErrCurrencyMismatch),
+		errors.Is(err, domain.ErrInvalidAmount),
+		errors.Is(err, domain.ErrAmountOverflow),
+		errors.Is(err, domain.ErrInvalidCursor),
+		errors.Is(err, domain.ErrInvalidFilter),
+		errors.Is(err, domain.ErrIdempotencyKeyReused):
 		return status.Error(codes.InvalidArgument, err.Error())
-	case domain.ErrConcurrentModification:
+	case errors.Is(err, domain.ErrConcurrentModification),
+		errors.Is(err, domain.ErrIdempotencyKeyInFlight):
 		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, domain.ErrOrderNotCancellable),
+		errors.Is(err, domain.ErrOrderNotDeleted),
+		errors.Is(err, domain.ErrReservationFailed):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
 	default:
 		return status.Error(codes.Internal, err.Error())
 	}
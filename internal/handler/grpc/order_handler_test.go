@@ -0,0 +1,269 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"testing"
+
+	orderv1 "github.com/sridharn-code-sandbox/go-ordersvc/api/proto/order/v1"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/config"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/mocks"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// memRepo returns a repository mock storing orders in memory, with the
+// version check of the postgres repository.
+func memRepo() *mocks.OrderRepositoryMock {
+	var mu sync.Mutex
+	orders := make(map[string]domain.Order)
+	return &mocks.OrderRepositoryMock{
+		CreateFunc: func(_ context.Context, order *domain.Order) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order.Version = 1
+			orders[order.ID.String()] = *order
+			return nil
+		},
+		FindByIDFunc: func(_ context.Context, id string) (*domain.Order, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			order, ok := orders[id]
+			if !ok {
+				return nil, nil
+			}
+			return &order, nil
+		},
+		UpdateFunc: func(_ context.Context, order *domain.Order) error {
+			mu.Lock()
+			defer mu.Unlock()
+			stored, ok := orders[order.ID.String()]
+			if !ok {
+				return domain.ErrOrderNotFound
+			}
+			if stored.Version != order.Version {
+				return domain.ErrVersionConflict
+			}
+			order.Version++
+			orders[order.ID.String()] = *order
+			return nil
+		},
+		ListFunc: func(_ context.Context, _ repository.ListOptions) ([]*domain.Order, int64, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			list := make([]*domain.Order, 0, len(orders))
+			for _, order := range orders {
+				list = append(list, &order)
+			}
+			return list, int64(len(list)), nil
+		},
+	}
+}
+
+// newTestClient serves svc over an in-memory connection and returns a
+// client for it.
+func newTestClient(t *testing.T, svc service.OrderService) orderv1.OrderServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	RegisterOrderServer(srv, svc, config.KafkaConfig{}, slog.New(slog.DiscardHandler))
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return orderv1.NewOrderServiceClient(conn)
+}
+
+func createTestOrder(t *testing.T, client orderv1.OrderServiceClient) *orderv1.Order {
+	t.Helper()
+	resp, err := client.CreateOrder(context.Background(), &orderv1.CreateOrderRequest{
+		CustomerId: "cust-1",
+		Items: []*orderv1.CreateOrderItem{
+			{ProductId: "p-1", Name: "Widget", Quantity: 2, Price: 12.50},
+		},
+	})
+	require.NoError(t, err)
+	return resp.GetOrder()
+}
+
+func TestOrderServer_CreateThenGet_ReturnsOrder(t *testing.T) {
+	client := newTestClient(t, service.NewOrderService(memRepo(), nil, nil))
+
+	created := createTestOrder(t, client)
+	assert.Equal(t, "pending", created.GetStatus())
+	assert.Equal(t, 25.0, created.GetTotal())
+	assert.Equal(t, int32(1), created.GetVersion())
+
+	resp, err := client.GetOrder(context.Background(), &orderv1.GetOrderRequest{OrderId: created.GetId()})
+
+	require.NoError(t, err)
+	assert.Equal(t, created.GetId(), resp.GetOrder().GetId())
+	require.Len(t, resp.GetOrder().GetItems(), 1)
+	assert.Equal(t, "p-1", resp.GetOrder().GetItems()[0].GetProductId())
+}
+
+func TestOrderServer_CreateOrder_MissingCustomer_InvalidArgument(t *testing.T) {
+	client := newTestClient(t, service.NewOrderService(memRepo(), nil, nil))
+
+	_, err := client.CreateOrder(context.Background(), &orderv1.CreateOrderRequest{
+		Items: []*orderv1.CreateOrderItem{{ProductId: "p-1", Name: "Widget", Quantity: 1, Price: 1}},
+	})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestOrderServer_CreateOrder_Currency(t *testing.T) {
+	client := newTestClient(t, service.NewOrderService(memRepo(), nil, nil, service.WithBaseCurrency("EUR")))
+
+	created := createTestOrder(t, client)
+	assert.Equal(t, "EUR", created.GetCurrency(), "defaults to the base currency")
+
+	_, err := client.CreateOrder(context.Background(), &orderv1.CreateOrderRequest{
+		CustomerId: "cust-1",
+		Currency:   "EUR",
+		Items:      []*orderv1.CreateOrderItem{{ProductId: "p-1", Name: "Widget", Quantity: 1, Price: 1, Currency: "USD"}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestOrderServer_CreateOrder_DecimalPrices_TotalExact(t *testing.T) {
+	client := newTestClient(t, service.NewOrderService(memRepo(), nil, nil))
+	item := &orderv1.CreateOrderItem{ProductId: "p-1", Name: "Dime", Quantity: 1, Price: 0.1}
+
+	resp, err := client.CreateOrder(context.Background(), &orderv1.CreateOrderRequest{
+		CustomerId: "cust-1",
+		Items:      []*orderv1.CreateOrderItem{item, item, item},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 0.3, resp.GetOrder().GetTotal())
+
+	_, err = client.CreateOrder(context.Background(), &orderv1.CreateOrderRequest{
+		CustomerId: "cust-1",
+		Items:      []*orderv1.CreateOrderItem{{ProductId: "p-1", Name: "Widget", Quantity: 1, Price: 0.001}},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "sub-cent price")
+}
+
+func TestOrderServer_CreateOrder_QuotaReached_ResourceExhausted(t *testing.T) {
+	repo := memRepo()
+	repo.LockCustomerAndCountOpenFunc = func(context.Context, string) (int, error) { return 1, nil }
+	client := newTestClient(t, service.NewOrderService(repo, nil, nil, service.WithOrderQuota(1, &mocks.TransactorMock{})))
+
+	_, err := client.CreateOrder(context.Background(), &orderv1.CreateOrderRequest{
+		CustomerId: "cust-1",
+		Items:      []*orderv1.CreateOrderItem{{ProductId: "p-1", Name: "Widget", Quantity: 1, Price: 10}},
+	})
+
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestOrderServer_GetOrder_Missing_NotFound(t *testing.T) {
+	client := newTestClient(t, service.NewOrderService(memRepo(), nil, nil))
+
+	_, err := client.GetOrder(context.Background(), &orderv1.GetOrderRequest{OrderId: "00000000-0000-0000-0000-000000000000"})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestOrderServer_ListOrders_ReturnsCreatedOrders(t *testing.T) {
+	client := newTestClient(t, service.NewOrderService(memRepo(), nil, nil))
+	createTestOrder(t, client)
+	createTestOrder(t, client)
+
+	resp, err := client.ListOrders(context.Background(), &orderv1.ListOrdersRequest{Page: 1, PageSize: 10})
+
+	require.NoError(t, err)
+	assert.Len(t, resp.GetOrders(), 2)
+	assert.Equal(t, int64(2), resp.GetTotalCount())
+}
+
+func TestOrderServer_UpdateStatus_ValidTransition_ReturnsUpdatedOrder(t *testing.T) {
+	client := newTestClient(t, service.NewOrderService(memRepo(), nil, nil))
+	created := createTestOrder(t, client)
+
+	resp, err := client.UpdateStatus(context.Background(), &orderv1.UpdateStatusRequest{
+		OrderId: created.GetId(),
+		Status:  "confirmed",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "confirmed", resp.GetOrder().GetStatus())
+	assert.Equal(t, int32(2), resp.GetOrder().GetVersion())
+}
+
+func TestOrderServer_UpdateStatus_DomainErrors_MapToStatusCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		repo   func() *mocks.OrderRepositoryMock
+		status string
+		want   codes.Code
+	}{
+		{name: "invalid_transition", repo: memRepo, status: "delivered", want: codes.InvalidArgument},
+		{name: "missing_status", repo: memRepo, status: "", want: codes.InvalidArgument},
+		{name: "expiry", repo: memRepo, status: "expired", want: codes.InvalidArgument},
+		{
+			name: "version_conflict",
+			repo: func() *mocks.OrderRepositoryMock {
+				repo := memRepo()
+				repo.UpdateFunc = func(context.Context, *domain.Order) error { return domain.ErrVersionConflict }
+				return repo
+			},
+			status: "confirmed",
+			want:   codes.Aborted,
+		},
+		{
+			name: "query_timeout",
+			repo: func() *mocks.OrderRepositoryMock {
+				repo := memRepo()
+				repo.UpdateFunc = func(context.Context, *domain.Order) error {
+					return fmt.Errorf("update order: %w", context.DeadlineExceeded)
+				}
+				return repo
+			},
+			status: "confirmed",
+			want:   codes.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, service.NewOrderService(tt.repo(), nil, nil))
+			created := createTestOrder(t, client)
+
+			_, err := client.UpdateStatus(context.Background(), &orderv1.UpdateStatusRequest{
+				OrderId: created.GetId(),
+				Status:  tt.status,
+			})
+
+			assert.Equal(t, tt.want, status.Code(err))
+		})
+	}
+}
+
+func TestOrderServer_UpdateStatus_Missing_NotFound(t *testing.T) {
+	client := newTestClient(t, service.NewOrderService(memRepo(), nil, nil))
+
+	_, err := client.UpdateStatus(context.Background(), &orderv1.UpdateStatusRequest{
+		OrderId: "00000000-0000-0000-0000-000000000000",
+		Status:  "confirmed",
+	})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
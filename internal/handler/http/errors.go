@@ -0,0 +1,148 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http //nolint:revive // intentional: matches handler layer convention
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/middleware"
+)
+
+// requestError is a malformed request, rejected before the service is
+// called, with the status and code it is written with
+type requestError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *requestError) Error() string { return e.message }
+
+// Errors of malformed requests
+var (
+	errInvalidBody    = &requestError{http.StatusBadRequest, "INVALID_REQUEST", "invalid request body"}
+	errBodyTooLarge   = &requestError{http.StatusRequestEntityTooLarge, "BODY_TOO_LARGE", "request body too large"}
+	errMissingID      = &requestError{http.StatusBadRequest, "MISSING_ID", "order ID is required"}
+	errMissingStatus  = &requestError{http.StatusBadRequest, "MISSING_STATUS", "status is required"}
+	errMissingItems   = &requestError{http.StatusBadRequest, "MISSING_ITEMS", "items are required"}
+	errEmptyItems     = &requestError{http.StatusBadRequest, "MISSING_ITEMS", "items must not be empty"}
+	errCancelByPatch  = &requestError{http.StatusBadRequest, "INVALID_TRANSITION", "use POST /api/v1/orders/{id}/cancel to cancel an order"}
+	errExpireByClient = &requestError{http.StatusBadRequest, "INVALID_TRANSITION", "orders are only expired by the expiry worker"}
+	errNoEventStream  = &requestError{http.StatusServiceUnavailable, "STREAM_UNAVAILABLE", "order event stream not configured"}
+	errNoStreamWriter = &requestError{http.StatusInternalServerError, "INTERNAL_ERROR", "streaming not supported"}
+)
+
+// bodyError returns the requestError for a body that failed to decode
+// with err: errBodyTooLarge once middleware.MaxBodySize cut it off
+func bodyError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return errBodyTooLarge
+	}
+	return errInvalidBody
+}
+
+// errorMapping is how responses report errors matching target
+type errorMapping struct {
+	target  error
+	status  int
+	code    string
+	message string // Shown instead of the error, which may hold internals
+}
+
+// errorMappings lists the service errors writeError reports, tried in
+// order with errors.Is. ErrValidation comes first: a ValidationErrors also
+// matches the sentinels of its fields.
+var errorMappings = []errorMapping{
+	{domain.ErrValidation, http.StatusUnprocessableEntity, "VALIDATION_FAILED", "order has invalid fields"},
+	{domain.ErrOrderNotFound, http.StatusNotFound, "ORDER_NOT_FOUND", "order not found"},
+	{domain.ErrOrderAlreadyDeleted, http.StatusNotFound, "ORDER_NOT_FOUND", "order not found"},
+	{domain.ErrInvalidTransition, http.StatusBadRequest, "INVALID_TRANSITION", "invalid status transition"},
+	{domain.ErrVersionConflict, http.StatusConflict, "CONCURRENT_MODIFICATION", "order was modified by another process"},
+	{domain.ErrInvalidCustomerID, http.StatusBadRequest, "INVALID_CUSTOMER_ID", "invalid customer ID"},
+	{domain.ErrNoItems, http.StatusBadRequest, "NO_ITEMS", "order must have at least one item"},
+	{domain.ErrOrderNotCancellable, http.StatusConflict, "ORDER_NOT_CANCELLABLE", "order can no longer be cancelled"},
+	{domain.ErrOrderNotDeleted, http.StatusConflict, "ORDER_NOT_DELETED", "order is not deleted"},
+	{domain.ErrReservationFailed, http.StatusConflict, "RESERVATION_FAILED", "inventory could not be reserved"},
+	{domain.ErrQuotaExceeded, middleware.StatusQuotaExceeded, "QUOTA_EXCEEDED", "customer has too many open orders"},
+	{domain.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_REUSED", "idempotency key reused with a different request"},
+	{domain.ErrIdempotencyKeyInFlight, http.StatusConflict, "IDEMPOTENCY_KEY_IN_FLIGHT", "request with this idempotency key is in progress"},
+	{domain.ErrInvalidCurrency, http.StatusBadRequest, "INVALID_CURRENCY", "invalid currency code"},
+	{domain.ErrCurrencyMismatch, http.StatusBadRequest, "CURRENCY_MISMATCH", "order items must share one currency"},
+	{domain.ErrInvalidAmount, http.StatusBadRequest, "INVALID_AMOUNT", "price is not a valid amount of the currency"},
+	{domain.ErrAmountOverflow, http.StatusBadRequest, "AMOUNT_OUT_OF_RANGE", "an item subtotal or the order total is too large"},
+	{domain.ErrInvalidCursor, http.StatusBadRequest, "INVALID_CURSOR", "invalid pagination cursor"},
+	{domain.ErrInvalidFilter, http.StatusBadRequest, "INVALID_FILTER", "invalid list filter"},
+	{context.DeadlineExceeded, http.StatusGatewayTimeout, "TIMEOUT", "request timed out"},
+}
+
+// writeError writes err as an ErrorResponse, with the status of its
+// requestError or errorMappings entry. Any other error is a 500 that
+// does not reveal it.
+func writeError(w http.ResponseWriter, err error) {
+	status, resp := errorResponse(err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func errorResponse(err error) (int, ErrorResponse) {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return reqErr.status, ErrorResponse{Code: reqErr.code, Message: reqErr.message}
+	}
+	for _, m := range errorMappings {
+		if errors.Is(err, m.target) {
+			return m.status, ErrorResponse{Code: m.code, Message: m.message, Details: errorDetails(err)}
+		}
+	}
+	return http.StatusInternalServerError, ErrorResponse{Code: "INTERNAL_ERROR", Message: "internal server error"}
+}
+
+// errorDetails returns the fields of the typed domain error in err that
+// help a client fix its request, or nil if it has none
+func errorDetails(err error) map[string]string {
+	var (
+		verrs      domain.ValidationErrors
+		transition *domain.TransitionError
+		cancel     *domain.CancelError
+		mismatch   *domain.CurrencyMismatchError
+		amount     *domain.AmountError
+		filter     *domain.FilterError
+		quota      *domain.QuotaError
+	)
+	switch {
+	case errors.As(err, &verrs):
+		return verrs.Messages()
+	case errors.As(err, &transition):
+		return map[string]string{"from": string(transition.From), "to": string(transition.To)}
+	case errors.As(err, &cancel):
+		return map[string]string{"status": string(cancel.Status)}
+	case errors.As(err, &mismatch):
+		return map[string]string{"order_currency": mismatch.Want, "item_currency": mismatch.Got}
+	case errors.As(err, &amount):
+		return map[string]string{"amount": amount.Amount, "currency": amount.Currency}
+	case errors.As(err, &filter):
+		return map[string]string{"field": filter.Field, "value": filter.Value, "reason": filter.Reason}
+	case errors.As(err, &quota):
+		return map[string]string{"limit": strconv.Itoa(quota.Limit)}
+	}
+	return nil
+}
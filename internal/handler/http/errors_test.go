@@ -0,0 +1,95 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http //nolint:revive // intentional: matches handler layer convention
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteError_MapsEachErrorToStatusAndBody(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		want       ErrorResponse
+	}{
+		{"not_found", domain.ErrOrderNotFound, http.StatusNotFound,
+			ErrorResponse{Code: "ORDER_NOT_FOUND", Message: "order not found"}},
+		{"deleted_is_not_found", domain.ErrOrderAlreadyDeleted, http.StatusNotFound,
+			ErrorResponse{Code: "ORDER_NOT_FOUND", Message: "order not found"}},
+		{"invalid_transition", &domain.TransitionError{From: domain.OrderStatusPending, To: domain.OrderStatusDelivered}, http.StatusBadRequest,
+			ErrorResponse{Code: "INVALID_TRANSITION", Message: "invalid status transition", Details: map[string]string{"from": "pending", "to": "delivered"}}},
+		{"version_conflict", domain.ErrVersionConflict, http.StatusConflict,
+			ErrorResponse{Code: "CONCURRENT_MODIFICATION", Message: "order was modified by another process"}},
+		{"validation", domain.ValidationErrors{"customer_id": domain.ErrInvalidCustomerID, "items": domain.ErrNoItems}, http.StatusUnprocessableEntity,
+			ErrorResponse{Code: "VALIDATION_FAILED", Message: "order has invalid fields", Details: map[string]string{
+				"customer_id": "invalid customer ID", "items": "order must have at least one item"}}},
+		{"quota_exceeded", &domain.QuotaError{CustomerID: "cust-1", Limit: 3}, middleware.StatusQuotaExceeded,
+			ErrorResponse{Code: "QUOTA_EXCEEDED", Message: "customer has too many open orders", Details: map[string]string{"limit": "3"}}},
+		{"not_cancellable", &domain.CancelError{Status: domain.OrderStatusShipped}, http.StatusConflict,
+			ErrorResponse{Code: "ORDER_NOT_CANCELLABLE", Message: "order can no longer be cancelled", Details: map[string]string{"status": "shipped"}}},
+		{"invalid_filter", &domain.FilterError{Field: "status", Value: "lost", Reason: "is not an order status"}, http.StatusBadRequest,
+			ErrorResponse{Code: "INVALID_FILTER", Message: "invalid list filter", Details: map[string]string{
+				"field": "status", "value": "lost", "reason": "is not an order status"}}},
+		{"timeout", context.DeadlineExceeded, http.StatusGatewayTimeout,
+			ErrorResponse{Code: "TIMEOUT", Message: "request timed out"}},
+		{"malformed_request", errMissingID, http.StatusBadRequest,
+			ErrorResponse{Code: "MISSING_ID", Message: "order ID is required"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			writeError(rec, fmt.Errorf("service: %w", tt.err))
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			var got ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWriteError_UnknownError_Returns500WithoutInternals(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	writeError(rec, errors.New("pq: connection to 10.0.0.7:5432 refused"))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t, `{"code": "INTERNAL_ERROR", "message": "internal server error"}`, rec.Body.String())
+}
+
+func TestErrorMappings_EachCodeHasOneStatus(t *testing.T) {
+	statuses := map[string]int{}
+	for _, m := range errorMappings {
+		if status, ok := statuses[m.code]; ok {
+			assert.Equal(t, status, m.status, "code %s", m.code)
+		}
+		statuses[m.code] = m.status
+	}
+}
@@ -18,32 +18,71 @@ package http //nolint:revive // intentional: matches handler layer convention
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"sync"
 	"time"
 )
 
+// DefaultHealthCheckTimeout bounds a readiness check registered without a
+// timeout of its own.
+const DefaultHealthCheckTimeout = 2 * time.Second
+
 // HealthChecker defines the interface for checking service health
 type HealthChecker interface {
 	Ping(ctx context.Context) error
 }
 
+// HealthCheckerFunc adapts a function to the HealthChecker interface
+type HealthCheckerFunc func(ctx context.Context) error
+
+// Ping calls f(ctx).
+func (f HealthCheckerFunc) Ping(ctx context.Context) error {
+	return f(ctx)
+}
+
+// HealthCheck is a dependency checked by the readiness probe
+type HealthCheck struct {
+	Name    string // Key of the check in the response
+	Checker HealthChecker
+	// Timeout bounds the check, so a slow dependency fails on its own
+	// instead of hanging the probe. Defaults to DefaultHealthCheckTimeout.
+	Timeout time.Duration
+}
+
 // HealthHandler handles health check endpoints
 // CONSTRAINT: Health endpoints must not require authentication (ADR-0002)
 type HealthHandler struct {
-	version   string
-	dbChecker HealthChecker
+	version string
+	checks  []HealthCheck
+}
+
+// NewHealthHandler creates a new health handler whose readiness probe runs
+// checks. Checks with a nil Checker are skipped.
+func NewHealthHandler(version string, checks ...HealthCheck) *HealthHandler {
+	h := &HealthHandler{version: version}
+	for _, c := range checks {
+		h.Register(c)
+	}
+	return h
 }
 
-// NewHealthHandler creates a new health handler
-func NewHealthHandler(version string, dbChecker HealthChecker) *HealthHandler {
-	return &HealthHandler{
-		version:   version,
-		dbChecker: dbChecker,
+// Register adds a check to the readiness probe. It must not be called
+// while the handler is serving requests.
+func (h *HealthHandler) Register(check HealthCheck) {
+	if check.Checker == nil {
+		return
+	}
+	if check.Timeout <= 0 {
+		check.Timeout = DefaultHealthCheckTimeout
 	}
+	h.checks = append(h.checks, check)
 }
 
 // Healthz handles liveness probe GET /healthz
-// Returns 200 if server is alive
+// Returns 200 if server is alive. It never checks dependencies, so a
+// dependency outage does not get the pod restarted.
 func (h *HealthHandler) Healthz(w http.ResponseWriter, _ *http.Request) {
 	response := HealthResponse{
 		Status:  "ok",
@@ -61,29 +100,34 @@ func (h *HealthHandler) Healthz(w http.ResponseWriter, _ *http.Request) {
 }
 
 // Readyz handles readiness probe GET /readyz
-// Returns 200 if all dependencies are healthy, 503 otherwise
+// Returns 200 if all dependencies are healthy, 503 otherwise, listing the
+// failed ones. Checks run concurrently, each under its own timeout.
 func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
-	checks := make(map[string]string)
-	allHealthy := true
-
-	// Check database connection
-	if h.dbChecker != nil {
-		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-		defer cancel()
+	results := make([]error, len(h.checks))
+	var wg sync.WaitGroup
+	for i, c := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runCheck(r.Context(), c)
+		}()
+	}
+	wg.Wait()
 
-		if err := h.dbChecker.Ping(ctx); err != nil {
-			checks["database"] = "unhealthy: " + err.Error()
-			allHealthy = false
+	checks := make(map[string]string, len(h.checks))
+	var failed []string
+	for i, c := range h.checks {
+		if err := results[i]; err != nil {
+			checks[c.Name] = "unhealthy: " + err.Error()
+			failed = append(failed, c.Name)
 		} else {
-			checks["database"] = "ok"
+			checks[c.Name] = "ok"
 		}
-	} else {
-		checks["database"] = "not configured"
 	}
 
 	status := "ok"
 	httpStatus := http.StatusOK
-	if !allHealthy {
+	if len(failed) > 0 {
 		status = "unhealthy"
 		httpStatus = http.StatusServiceUnavailable
 	}
@@ -92,6 +136,7 @@ func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
 		Status:  status,
 		Version: h.version,
 		Checks:  checks,
+		Failed:  failed,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -100,3 +145,23 @@ func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// runCheck pings c's dependency within c.Timeout. A check that overruns
+// fails even if its checker ignores ctx.
+func runCheck(ctx context.Context, c HealthCheck) error {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- c.Checker.Ping(ctx) }()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", c.Timeout)
+	}
+	return err
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http //nolint:revive // intentional: matches handler layer convention
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	healthy = HealthCheckerFunc(func(context.Context) error { return nil })
+	down    = HealthCheckerFunc(func(context.Context) error { return errors.New("connection refused") })
+)
+
+// hanging blocks until ctx ends, or, if ignoreCtx is set, until release
+// is closed.
+func hanging(ignoreCtx bool, release <-chan struct{}) HealthChecker {
+	return HealthCheckerFunc(func(ctx context.Context) error {
+		if ignoreCtx {
+			<-release
+			return nil
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+}
+
+func probe(t *testing.T, h *HealthHandler, handler func(*HealthHandler, http.ResponseWriter, *http.Request)) (int, HealthResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler(h, rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var body HealthResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return rec.Code, body
+}
+
+func TestReadyz_AllDependenciesHealthy_Returns200(t *testing.T) {
+	h := NewHealthHandler("v1.2.3",
+		HealthCheck{Name: "database", Checker: healthy},
+		HealthCheck{Name: "kafka", Checker: healthy})
+
+	code, body := probe(t, h, (*HealthHandler).Readyz)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", body.Status)
+	assert.Equal(t, "v1.2.3", body.Version)
+	assert.Equal(t, map[string]string{"database": "ok", "kafka": "ok"}, body.Checks)
+	assert.Empty(t, body.Failed)
+}
+
+func TestReadyz_DependencyDown_Returns503ListingIt(t *testing.T) {
+	h := NewHealthHandler("dev",
+		HealthCheck{Name: "database", Checker: healthy},
+		HealthCheck{Name: "kafka", Checker: down})
+
+	code, body := probe(t, h, (*HealthHandler).Readyz)
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "unhealthy", body.Status)
+	assert.Equal(t, []string{"kafka"}, body.Failed)
+	assert.Equal(t, "ok", body.Checks["database"])
+	assert.Equal(t, "unhealthy: connection refused", body.Checks["kafka"])
+}
+
+func TestReadyz_SlowDependency_TimesOutOnItsOwn(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	tests := []struct {
+		name    string
+		checker HealthChecker
+	}{
+		{"respects_context", hanging(false, release)},
+		{"ignores_context", hanging(true, release)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthHandler("dev",
+				HealthCheck{Name: "database", Checker: tt.checker, Timeout: 20 * time.Millisecond},
+				HealthCheck{Name: "kafka", Checker: healthy})
+
+			start := time.Now()
+			code, body := probe(t, h, (*HealthHandler).Readyz)
+
+			assert.Less(t, time.Since(start), time.Second, "the probe must not hang on the slow check")
+			assert.Equal(t, http.StatusServiceUnavailable, code)
+			assert.Equal(t, []string{"database"}, body.Failed)
+			assert.Equal(t, "unhealthy: timed out after 20ms", body.Checks["database"])
+			assert.Equal(t, "ok", body.Checks["kafka"])
+		})
+	}
+}
+
+func TestReadyz_ChecksRunConcurrently(t *testing.T) {
+	slow := HealthCheckerFunc(func(context.Context) error {
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	})
+	h := NewHealthHandler("dev",
+		HealthCheck{Name: "a", Checker: slow},
+		HealthCheck{Name: "b", Checker: slow},
+		HealthCheck{Name: "c", Checker: slow})
+
+	start := time.Now()
+	code, _ := probe(t, h, (*HealthHandler).Readyz)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Less(t, time.Since(start), 140*time.Millisecond)
+}
+
+func TestHealthHandler_Register_AddsCheckAndSkipsNil(t *testing.T) {
+	h := NewHealthHandler("dev", HealthCheck{Name: "kafka"}) // Kafka not configured
+	h.Register(HealthCheck{Name: "search", Checker: down})
+
+	code, body := probe(t, h, (*HealthHandler).Readyz)
+
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, map[string]string{"search": "unhealthy: connection refused"}, body.Checks)
+}
+
+func TestHealthz_DependencyDown_StillReturns200(t *testing.T) {
+	h := NewHealthHandler("dev", HealthCheck{Name: "database", Checker: down})
+
+	code, body := probe(t, h, (*HealthHandler).Healthz)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", body.Status)
+}
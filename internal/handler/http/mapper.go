@@ -16,6 +16,7 @@ package http //nolint:revive // intentional package name matching handler layer
 
 import (
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/service"
 )
 
 // MapOrderToResponse maps a domain order to HTTP response
@@ -27,20 +28,23 @@ func MapOrderToResponse(order *domain.Order) OrderResponse {
 			ProductID: item.ProductID,
 			Name:      item.Name,
 			Quantity:  item.Quantity,
-			Price:     item.Price,
-			Subtotal:  item.Subtotal,
+			Price:     item.Price.Decimal(),
+			Subtotal:  item.Subtotal.Decimal(),
 		}
 	}
 
 	return OrderResponse{
-		ID:         order.ID.String(),
-		CustomerID: order.CustomerID,
-		Items:      items,
-		Status:     string(order.Status),
-		Total:      order.Total,
-		Version:    order.Version,
-		CreatedAt:  order.CreatedAt,
-		UpdatedAt:  order.UpdatedAt,
+		ID:           order.ID.String(),
+		CustomerID:   order.CustomerID,
+		Items:        items,
+		Status:       string(order.Status),
+		Total:        order.Total.Decimal(),
+		Currency:     order.Total.Currency,
+		Version:      order.Version,
+		CreatedAt:    order.CreatedAt,
+		UpdatedAt:    order.UpdatedAt,
+		DeletedAt:    order.DeletedAt,
+		CancelReason: order.CancelReason,
 	}
 }
 
@@ -53,17 +57,35 @@ func MapOrdersToResponse(orders []*domain.Order) []OrderResponse {
 	return responses
 }
 
-// MapRequestToOrderItems maps HTTP request items to domain items
-func MapRequestToOrderItems(items []OrderItem) []domain.OrderItem {
-	domainItems := make([]domain.OrderItem, len(items))
+// MapHistoryToResponse converts the status history of an order to its
+// response
+func MapHistoryToResponse(orderID string, history []domain.StatusChange) OrderHistoryResponse {
+	resp := OrderHistoryResponse{
+		OrderID: orderID,
+		History: make([]StatusChangeResponse, len(history)),
+	}
+	for i, c := range history {
+		resp.History[i] = StatusChangeResponse{
+			OldStatus:  string(c.OldStatus),
+			NewStatus:  string(c.NewStatus),
+			ChangedBy:  c.ChangedBy,
+			OccurredAt: c.OccurredAt,
+		}
+	}
+	return resp
+}
+
+// MapRequestToOrderItems maps HTTP request items to service items
+func MapRequestToOrderItems(items []OrderItem) []service.OrderItemDTO {
+	dtos := make([]service.OrderItemDTO, len(items))
 	for i, item := range items {
-		domainItems[i] = domain.OrderItem{
+		dtos[i] = service.OrderItemDTO{
 			ProductID: item.ProductID,
 			Name:      item.Name,
 			Quantity:  item.Quantity,
-			Price:     item.Price,
-			Subtotal:  float64(item.Quantity) * item.Price,
+			Price:     item.Price.String(),
+			Currency:  item.Currency,
 		}
 	}
-	return domainItems
+	return dtos
 }
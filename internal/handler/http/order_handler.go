@@ -18,8 +18,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
@@ -34,47 +36,53 @@ const (
 // OrderHandler handles HTTP requests for order operations
 type OrderHandler struct {
 	service service.OrderService
+	events  EventStream
 }
 
 // NewOrderHandler creates a new order handler
-func NewOrderHandler(svc service.OrderService) *OrderHandler {
-	return &OrderHandler{
+func NewOrderHandler(svc service.OrderService, opts ...OrderHandlerOption) *OrderHandler {
+	h := &OrderHandler{
 		service: svc,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // CreateOrder handles POST /api/v1/orders
 // CONSTRAINT: Returns 201 + Location header (ADR-0002)
+// An Idempotency-Key header makes retries return the original order with 200.
 func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 	var req CreateOrderRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body", "INVALID_REQUEST")
-		return
-	}
-
-	if req.CustomerID == "" {
-		writeError(w, http.StatusBadRequest, "customer_id is required", "MISSING_CUSTOMER_ID")
-		return
-	}
-	if len(req.Items) == 0 {
-		writeError(w, http.StatusBadRequest, "items are required", "MISSING_ITEMS")
+		writeError(w, bodyError(err))
 		return
 	}
 
+	// Missing or invalid fields are reported together, with 422, by the
+	// order's validation
 	dto := service.CreateOrderDTO{
 		CustomerID: req.CustomerID,
+		Currency:   req.Currency,
 		Items:      MapRequestToOrderItems(req.Items),
 	}
 
-	order, err := h.service.CreateOrder(r.Context(), dto)
+	order, replayed, err := h.service.CreateOrderIdempotent(r.Context(), r.Header.Get("Idempotency-Key"), dto)
 	if err != nil {
-		handleServiceError(w, err)
+		writeError(w, err)
 		return
 	}
 
+	// A replayed idempotent request returns the original order
+	status := http.StatusCreated
+	if replayed {
+		status = http.StatusOK
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Location", fmt.Sprintf("/api/v1/orders/%s", order.ID.String()))
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(status)
 	if err := json.NewEncoder(w).Encode(MapOrderToResponse(order)); err != nil {
 		// Log error but response headers already sent
 		return
@@ -82,17 +90,23 @@ func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetOrder handles GET /api/v1/orders/{id}
+// Supports ?include_deleted=true to return a soft-deleted order
 // CONSTRAINT: Returns 404 for missing orders (ADR-0002)
 func (h *OrderHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		writeError(w, http.StatusBadRequest, "order ID is required", "MISSING_ID")
+		writeError(w, errMissingID)
 		return
 	}
 
-	order, err := h.service.GetOrderByID(r.Context(), id)
+	get := h.service.GetOrderByID
+	if includeDeleted, _ := strconv.ParseBool(r.URL.Query().Get("include_deleted")); includeDeleted {
+		get = h.service.GetOrderByIDIncludingDeleted
+	}
+
+	order, err := get(r.Context(), id)
 	if err != nil {
-		handleServiceError(w, err)
+		writeError(w, err)
 		return
 	}
 
@@ -104,7 +118,10 @@ func (h *OrderHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
 }
 
 // ListOrders handles GET /api/v1/orders
-// Supports ?status=pending&limit=20&offset=0
+// Supports ?status=pending&limit=20&offset=0, or ?limit=20&cursor=<next_cursor>
+// for keyset pagination that stays stable while orders are being created.
+// Filters customer_id, status, created_after, created_before (RFC 3339),
+// min_total and max_total combine with AND; invalid values return 400
 func (h *OrderHandler) ListOrders(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters
 	limit := parseIntParam(r, "limit", defaultLimit)
@@ -137,24 +154,41 @@ func (h *OrderHandler) ListOrders(w http.ResponseWriter, r *http.Request) {
 		customerID = &cid
 	}
 
+	createdAfter, err := parseTimeParam(r, "created_after")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	createdBefore, err := parseTimeParam(r, "created_before")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
 	req := service.ListOrdersRequest{
-		Page:       page,
-		PageSize:   pageSize,
-		Status:     status,
-		CustomerID: customerID,
+		Page:          page,
+		PageSize:      pageSize,
+		Status:        status,
+		CustomerID:    customerID,
+		CreatedAfter:  createdAfter,
+		CreatedBefore: createdBefore,
+		MinTotal:      r.URL.Query().Get("min_total"),
+		MaxTotal:      r.URL.Query().Get("max_total"),
+		Cursor:        r.URL.Query().Get("cursor"),
 	}
 
 	result, err := h.service.ListOrders(r.Context(), req)
 	if err != nil {
-		handleServiceError(w, err)
+		writeError(w, err)
 		return
 	}
 
 	response := ListOrdersResponse{
-		Orders: MapOrdersToResponse(result.Data),
-		Total:  result.TotalCount,
-		Limit:  limit,
-		Offset: offset,
+		Orders:     MapOrdersToResponse(result.Data),
+		Total:      result.TotalCount,
+		Limit:      limit,
+		Offset:     offset,
+		NextCursor: result.NextCursor,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -169,26 +203,31 @@ func (h *OrderHandler) ListOrders(w http.ResponseWriter, r *http.Request) {
 func (h *OrderHandler) UpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		writeError(w, http.StatusBadRequest, "order ID is required", "MISSING_ID")
+		writeError(w, errMissingID)
 		return
 	}
 
 	var req UpdateStatusRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body", "INVALID_REQUEST")
+		writeError(w, bodyError(err))
 		return
 	}
 
 	if req.Status == "" {
-		writeError(w, http.StatusBadRequest, "status is required", "MISSING_STATUS")
+		writeError(w, errMissingStatus)
 		return
 	}
 
 	newStatus := domain.OrderStatus(req.Status)
+	// Expiry follows the TTL and publishes its own event
+	if newStatus == domain.OrderStatusExpired {
+		writeError(w, errExpireByClient)
+		return
+	}
 
 	order, err := h.service.UpdateOrderStatus(r.Context(), id, newStatus)
 	if err != nil {
-		handleServiceError(w, err)
+		writeError(w, err)
 		return
 	}
 
@@ -203,18 +242,18 @@ func (h *OrderHandler) UpdateOrderStatus(w http.ResponseWriter, r *http.Request)
 func (h *OrderHandler) UpdateOrder(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		writeError(w, http.StatusBadRequest, "order ID is required", "MISSING_ID")
+		writeError(w, errMissingID)
 		return
 	}
 
 	var req UpdateOrderRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "invalid request body", "INVALID_REQUEST")
+		writeError(w, bodyError(err))
 		return
 	}
 
 	if len(req.Items) == 0 {
-		writeError(w, http.StatusBadRequest, "items are required", "MISSING_ITEMS")
+		writeError(w, errMissingItems)
 		return
 	}
 
@@ -224,7 +263,59 @@ func (h *OrderHandler) UpdateOrder(w http.ResponseWriter, r *http.Request) {
 
 	order, err := h.service.UpdateOrder(r.Context(), id, dto)
 	if err != nil {
-		handleServiceError(w, err)
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(MapOrderToResponse(order)); err != nil {
+		return
+	}
+}
+
+// PatchOrder handles PATCH /api/v1/orders/{id}
+// Only the fields present in the body are changed; a status change must be
+// a valid transition other than cancellation. An empty body changes nothing
+// and returns the order.
+func (h *OrderHandler) PatchOrder(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeError(w, errMissingID)
+		return
+	}
+
+	var req PatchOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, bodyError(err))
+		return
+	}
+
+	dto := service.UpdateOrderDTO{CustomerID: req.CustomerID}
+	if req.Items != nil {
+		if len(*req.Items) == 0 {
+			writeError(w, errEmptyItems)
+			return
+		}
+		dto.Items = MapRequestToOrderItems(*req.Items)
+	}
+	if req.Status != nil {
+		status := domain.OrderStatus(*req.Status)
+		// Cancellation has its own endpoint and event
+		if status == domain.OrderStatusCancelled {
+			writeError(w, errCancelByPatch)
+			return
+		}
+		if status == domain.OrderStatusExpired {
+			writeError(w, errExpireByClient)
+			return
+		}
+		dto.Status = &status
+	}
+
+	order, err := h.service.UpdateOrder(r.Context(), id, dto)
+	if err != nil {
+		writeError(w, err)
 		return
 	}
 
@@ -239,28 +330,128 @@ func (h *OrderHandler) UpdateOrder(w http.ResponseWriter, r *http.Request) {
 func (h *OrderHandler) DeleteOrder(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 	if id == "" {
-		writeError(w, http.StatusBadRequest, "order ID is required", "MISSING_ID")
+		writeError(w, errMissingID)
 		return
 	}
 
 	if err := h.service.DeleteOrder(r.Context(), id); err != nil {
-		handleServiceError(w, err)
+		writeError(w, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// CancelOrder handles POST /api/v1/orders/{id}/cancel
+// The body is optional: {"reason": "..."}
+func (h *OrderHandler) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeError(w, errMissingID)
+		return
+	}
+
+	var req CancelOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, bodyError(err))
+		return
+	}
+
+	order, err := h.service.CancelOrder(r.Context(), id, req.Reason)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(MapOrderToResponse(order)); err != nil {
+		return
+	}
+}
+
+// RestoreOrder handles POST /api/v1/orders/{id}/restore
+func (h *OrderHandler) RestoreOrder(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeError(w, errMissingID)
+		return
+	}
+
+	order, err := h.service.RestoreOrder(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(MapOrderToResponse(order)); err != nil {
+		return
+	}
+}
+
+// GetOrderHistory handles GET /api/v1/orders/{id}/history
+// Returns the status transitions of the order, oldest first.
+func (h *OrderHandler) GetOrderHistory(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeError(w, errMissingID)
+		return
+	}
+
+	history, err := h.service.GetOrderHistory(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(MapHistoryToResponse(id, history)); err != nil {
+		return
+	}
+}
+
+// GetOrderSnapshot handles GET /api/v1/orders/{id}/snapshot
+// Returns the committed state of the order in the form of the event a
+// replay would reconstruct for it, so consumers can reconcile by version.
+func (h *OrderHandler) GetOrderSnapshot(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		writeError(w, errMissingID)
+		return
+	}
+
+	snapshot, err := h.service.GetOrderSnapshot(r.Context(), id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(snapshot); err != nil {
+		return
+	}
+}
+
 // RegisterRoutes registers all order routes on the router
 // CONSTRAINT: All endpoints must use /api/v1 prefix (ADR-0002)
 func (h *OrderHandler) RegisterRoutes(r chi.Router) {
 	r.Route("/api/v1/orders", func(r chi.Router) {
 		r.Post("/", h.CreateOrder)
 		r.Get("/", h.ListOrders)
+		r.Get("/stream", h.StreamOrders)
 		r.Get("/{id}", h.GetOrder)
 		r.Put("/{id}", h.UpdateOrder)
+		r.Patch("/{id}", h.PatchOrder)
 		r.Delete("/{id}", h.DeleteOrder)
 		r.Patch("/{id}/status", h.UpdateOrderStatus)
+		r.Post("/{id}/cancel", h.CancelOrder)
+		r.Post("/{id}/restore", h.RestoreOrder)
+		r.Get("/{id}/history", h.GetOrderHistory)
+		r.Get("/{id}/snapshot", h.GetOrderSnapshot)
 	})
 }
 
@@ -278,31 +469,16 @@ func parseIntParam(r *http.Request, name string, defaultVal int) int {
 	return val
 }
 
-func writeError(w http.ResponseWriter, status int, message, code string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	resp := ErrorResponse{
-		Error: message,
-		Code:  code,
+// parseTimeParam parses the RFC 3339 query parameter name, which is nil if
+// absent.
+func parseTimeParam(r *http.Request, name string) (*time.Time, error) {
+	str := r.URL.Query().Get(name)
+	if str == "" {
+		return nil, nil
 	}
-	_ = json.NewEncoder(w).Encode(resp)
-}
-
-func handleServiceError(w http.ResponseWriter, err error) {
-	switch {
-	case errors.Is(err, domain.ErrOrderNotFound):
-		writeError(w, http.StatusNotFound, "order not found", "ORDER_NOT_FOUND")
-	case errors.Is(err, domain.ErrInvalidTransition):
-		writeError(w, http.StatusBadRequest, "invalid status transition", "INVALID_TRANSITION")
-	case errors.Is(err, domain.ErrConcurrentModification):
-		writeError(w, http.StatusConflict, "order was modified by another process", "CONCURRENT_MODIFICATION")
-	case errors.Is(err, domain.ErrInvalidCustomerID):
-		writeError(w, http.StatusBadRequest, "invalid customer ID", "INVALID_CUSTOMER_ID")
-	case errors.Is(err, domain.ErrNoItems):
-		writeError(w, http.StatusBadRequest, "order must have at least one item", "NO_ITEMS")
-	case errors.Is(err, domain.ErrOrderAlreadyDeleted):
-		writeError(w, http.StatusNotFound, "order not found", "ORDER_NOT_FOUND")
-	default:
-		writeError(w, http.StatusInternalServerError, "internal server error", "INTERNAL_ERROR")
+	t, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		return nil, &domain.FilterError{Field: name, Value: str, Reason: "is not an RFC 3339 timestamp"}
 	}
+	return &t, nil
 }
@@ -0,0 +1,430 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http //nolint:revive // intentional: matches handler layer convention
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/bus"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/memory"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/middleware"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/mocks"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// patchFixture serves the order routes over the real service, with one
+// stored order whose version and event sequence the repository bumps on
+// save, as the Postgres repository does, and whose status
+// changes it keeps as history. The service publishes to an in-process bus,
+// which streams events and records them in events.
+type patchFixture struct {
+	stored  *domain.Order
+	saves   int
+	history []domain.StatusChange
+	events  *memory.Publisher
+	bus     *bus.Bus
+	router  chi.Router
+}
+
+func newPatchFixture(t *testing.T) *patchFixture {
+	t.Helper()
+	f := &patchFixture{
+		stored: &domain.Order{
+			ID:         uuid.New(),
+			CustomerID: "cust-1",
+			Items: []domain.OrderItem{
+				{ID: uuid.New(), ProductID: "p-1", Name: "Widget", Quantity: 2, Price: domain.Money{Amount: 1000}, Subtotal: domain.Money{Amount: 2000}},
+			},
+			Status:    domain.OrderStatusPending,
+			Total:     domain.Money{Amount: 2000, Currency: "USD"},
+			Version:   1,
+			EventSeq:  1,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		events: memory.New(),
+		bus:    bus.New(),
+	}
+	repo := &mocks.OrderRepositoryMock{
+		CreateFunc: func(_ context.Context, order *domain.Order) error {
+			order.Version, order.EventSeq = 1, 1
+			created := *order
+			f.stored = &created
+			return nil
+		},
+		FindByIDFunc: func(_ context.Context, _ string) (*domain.Order, error) {
+			order := *f.stored
+			order.Items = append([]domain.OrderItem(nil), f.stored.Items...)
+			return &order, nil
+		},
+		FindByIDIncludingDeletedFunc: func(_ context.Context, _ string) (*domain.Order, error) {
+			order := *f.stored
+			order.Items = append([]domain.OrderItem(nil), f.stored.Items...)
+			return &order, nil
+		},
+		UpdateFunc: func(_ context.Context, order *domain.Order) error {
+			return f.save(order)
+		},
+		UpdateStatusFunc: func(_ context.Context, order *domain.Order, change domain.StatusChange) error {
+			f.history = append(f.history, change)
+			return f.save(order)
+		},
+		GetHistoryFunc: func(_ context.Context, _ string) ([]domain.StatusChange, error) {
+			return f.history, nil
+		},
+	}
+	r := chi.NewRouter()
+	r.Use(middleware.Actor("X-Authenticated-User"))
+	f.bus.Subscribe(bus.Wildcard, f.events.Publish)
+	svc := service.NewOrderService(repo, nil, f.bus)
+	NewOrderHandler(svc, WithEventStream(f.bus)).RegisterRoutes(r)
+	f.router = r
+	return f
+}
+
+func (f *patchFixture) save(order *domain.Order) error {
+	f.saves++
+	order.Version++
+	order.EventSeq++
+	saved := *order
+	f.stored = &saved
+	return nil
+}
+
+func (f *patchFixture) patch(t *testing.T, body string) (int, []byte) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/orders/"+f.stored.ID.String(), strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, req)
+	return rec.Code, rec.Body.Bytes()
+}
+
+func TestPatchOrder_SingleField_OnlyThatFieldChanges(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantChanged []string
+		check       func(t *testing.T, got OrderResponse)
+	}{
+		{
+			name:        "customer_id",
+			body:        `{"customer_id": "cust-2"}`,
+			wantChanged: []string{"customer_id"},
+			check: func(t *testing.T, got OrderResponse) {
+				assert.Equal(t, "cust-2", got.CustomerID)
+				assert.Equal(t, "pending", got.Status)
+				assert.Equal(t, "20.00", got.Total)
+				require.Len(t, got.Items, 1)
+				assert.Equal(t, "p-1", got.Items[0].ProductID)
+			},
+		},
+		{
+			name:        "items",
+			body:        `{"items": [{"product_id": "p-2", "name": "Gadget", "quantity": 1, "price": "5.50"}]}`,
+			wantChanged: []string{"items", "total"},
+			check: func(t *testing.T, got OrderResponse) {
+				assert.Equal(t, "cust-1", got.CustomerID)
+				assert.Equal(t, "pending", got.Status)
+				assert.Equal(t, "5.50", got.Total)
+				require.Len(t, got.Items, 1)
+				assert.Equal(t, "p-2", got.Items[0].ProductID)
+			},
+		},
+		{
+			name:        "status",
+			body:        `{"status": "confirmed"}`,
+			wantChanged: []string{"status"},
+			check: func(t *testing.T, got OrderResponse) {
+				assert.Equal(t, "cust-1", got.CustomerID)
+				assert.Equal(t, "confirmed", got.Status)
+				assert.Equal(t, "20.00", got.Total)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPatchFixture(t)
+
+			code, body := f.patch(t, tt.body)
+
+			require.Equal(t, http.StatusOK, code, string(body))
+			var got OrderResponse
+			require.NoError(t, json.Unmarshal(body, &got))
+			tt.check(t, got)
+			assert.Equal(t, 2, got.Version, "the version is bumped")
+
+			events := f.events.Events()
+			require.Len(t, events, 1)
+			assert.Equal(t, messaging.EventOrderUpdated, events[0].EventType)
+			assert.Equal(t, tt.wantChanged, events[0].ChangedFields)
+		})
+	}
+}
+
+func TestPatchOrder_EmptyPatch_ReturnsCurrentOrderUnchanged(t *testing.T) {
+	for _, body := range []string{"", "{}", `{"customer_id": null, "items": null}`} {
+		t.Run(body, func(t *testing.T) {
+			f := newPatchFixture(t)
+
+			code, resp := f.patch(t, body)
+
+			require.Equal(t, http.StatusOK, code, string(resp))
+			var got OrderResponse
+			require.NoError(t, json.Unmarshal(resp, &got))
+			assert.Equal(t, f.stored.ID.String(), got.ID)
+			assert.Equal(t, 1, got.Version)
+			assert.Zero(t, f.saves)
+			assert.Empty(t, f.events.Events())
+		})
+	}
+}
+
+func TestPatchOrder_InvalidPatch_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantCode string
+	}{
+		{"illegal_transition", `{"status": "delivered"}`, "INVALID_TRANSITION"},
+		{"cancellation", `{"status": "cancelled"}`, "INVALID_TRANSITION"},
+		{"expiry", `{"status": "expired"}`, "INVALID_TRANSITION"},
+		{"empty_customer", `{"customer_id": ""}`, "INVALID_CUSTOMER_ID"},
+		{"empty_items", `{"items": []}`, "MISSING_ITEMS"},
+		{"malformed", `{"status": `, "INVALID_REQUEST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newPatchFixture(t)
+
+			code, body := f.patch(t, tt.body)
+
+			assert.Equal(t, http.StatusBadRequest, code)
+			var got ErrorResponse
+			require.NoError(t, json.Unmarshal(body, &got))
+			assert.Equal(t, tt.wantCode, got.Code)
+			assert.Zero(t, f.saves)
+			assert.Empty(t, f.events.Events())
+		})
+	}
+}
+
+func TestUpdateOrderStatus_Expired_Rejected(t *testing.T) {
+	f := newPatchFixture(t)
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/orders/"+f.stored.ID.String()+"/status", strings.NewReader(`{"status": "expired"}`))
+	rec := httptest.NewRecorder()
+
+	f.router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var got ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "INVALID_TRANSITION", got.Code)
+	assert.Zero(t, f.saves)
+	assert.Empty(t, f.events.Events())
+}
+
+func TestGetOrderHistory_AfterStatusChange_ReturnsTimelineMatchingEvent(t *testing.T) {
+	f := newPatchFixture(t)
+	path := "/api/v1/orders/" + f.stored.ID.String()
+	req := httptest.NewRequest(http.MethodPatch, path+"/status", strings.NewReader(`{"status": "confirmed"}`))
+	req.Header.Set("X-Authenticated-User", "alice")
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	f.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path+"/history", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var got OrderHistoryResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, f.stored.ID.String(), got.OrderID)
+	require.Len(t, got.History, 1)
+	change := got.History[0]
+	assert.Equal(t, "pending", change.OldStatus)
+	assert.Equal(t, "confirmed", change.NewStatus)
+	assert.Equal(t, "alice", change.ChangedBy)
+
+	events := f.events.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, messaging.EventOrderStatusChanged, events[0].EventType)
+	assert.True(t, change.OccurredAt.Equal(events[0].OccurredAt), "history %v, event %v", change.OccurredAt, events[0].OccurredAt)
+}
+
+func TestGetOrderSnapshot_MatchesLatestEmittedEvent(t *testing.T) {
+	f := newPatchFixture(t)
+	path := "/api/v1/orders/" + f.stored.ID.String()
+	for _, status := range []string{"confirmed", "processing"} {
+		req := httptest.NewRequest(http.MethodPatch, path+"/status", strings.NewReader(`{"status": "`+status+`"}`))
+		rec := httptest.NewRecorder()
+		f.router.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path+"/snapshot", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	snapshot, err := messaging.Unmarshal(messaging.JSONSerializer{}, rec.Body.Bytes())
+	require.NoError(t, err)
+	events := f.events.Events()
+	require.Len(t, events, 2)
+	latest := events[len(events)-1]
+	assert.Equal(t, latest.Version, snapshot.Version)
+	assert.Equal(t, latest.Status, snapshot.Status)
+	assert.Equal(t, messaging.EventOrderSnapshot, snapshot.EventType)
+	require.Len(t, snapshot.Items, 1, "snapshots carry the order lines")
+	assert.Equal(t, f.stored.Items[0].ProductID, snapshot.Items[0].SKU)
+}
+
+func TestOrderEvents_CreateUpdateStatusChange_SequenceIsContiguous(t *testing.T) {
+	f := newPatchFixture(t)
+	serve := func(method, path, body string) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		f.router.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		require.Less(t, rec.Code, 300, rec.Body.String())
+	}
+
+	serve(http.MethodPost, "/api/v1/orders", `{"customer_id": "cust-1", "items": [
+		{"product_id": "p-1", "name": "Widget", "quantity": 1, "price": "10.00"}]}`)
+	path := "/api/v1/orders/" + f.stored.ID.String()
+	serve(http.MethodPatch, path, `{"customer_id": "cust-2"}`)
+	serve(http.MethodPatch, path+"/status", `{"status": "confirmed"}`)
+
+	events := f.events.Events()
+	require.Len(t, events, 3)
+	for i, evt := range events {
+		assert.Equal(t, f.stored.ID.String(), evt.OrderID)
+		assert.Equal(t, int64(i+1), evt.Seq, "%s is out of sequence", evt.EventType)
+	}
+}
+
+func TestGetOrderHistory_NoChanges_ReturnsEmptyTimeline(t *testing.T) {
+	f := newPatchFixture(t)
+	rec := httptest.NewRecorder()
+
+	f.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders/"+f.stored.ID.String()+"/history", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"order_id": "`+f.stored.ID.String()+`", "history": []}`, rec.Body.String())
+}
+
+func TestGetOrder_QueryPastDeadline_Returns504(t *testing.T) {
+	repo := &mocks.OrderRepositoryMock{
+		FindByIDFunc: func(context.Context, string) (*domain.Order, error) {
+			return nil, fmt.Errorf("find order: %w", context.DeadlineExceeded)
+		},
+	}
+	r := chi.NewRouter()
+	NewOrderHandler(service.NewOrderService(repo, nil, nil)).RegisterRoutes(r)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders/"+uuid.NewString(), nil))
+
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+	var got ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "TIMEOUT", got.Code)
+}
+
+func TestCreateOrder_InvalidFields_Returns422ListingEach(t *testing.T) {
+	f := newPatchFixture(t)
+	body := `{"customer_id": "", "items": [
+		{"product_id": "p-1", "name": "Widget", "quantity": 1, "price": "10.00"},
+		{"product_id": "", "name": "Gadget", "quantity": 0, "price": "5.00"}
+	]}`
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var got ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "VALIDATION_FAILED", got.Code)
+	assert.Equal(t, map[string]string{
+		"customer_id":         domain.ErrInvalidCustomerID.Error(),
+		"items[1].product_id": domain.ErrInvalidProductID.Error(),
+		"items[1].quantity":   domain.ErrInvalidQuantity.Error(),
+	}, got.Details)
+	assert.Empty(t, f.events.Events())
+}
+
+func TestCreateOrder_NoItems_Returns422(t *testing.T) {
+	f := newPatchFixture(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(`{"customer_id": "cust-1"}`))
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	var got ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, map[string]string{"items": domain.ErrNoItems.Error()}, got.Details)
+}
+
+func TestCreateOrder_OpenOrderQuotaReached_ReturnsQuotaExceeded(t *testing.T) {
+	repo := &mocks.OrderRepositoryMock{
+		LockCustomerAndCountOpenFunc: func(context.Context, string) (int, error) { return 2, nil },
+	}
+	r := chi.NewRouter()
+	NewOrderHandler(service.NewOrderService(repo, nil, nil, service.WithOrderQuota(2, &mocks.TransactorMock{}))).RegisterRoutes(r)
+	body := `{"customer_id": "cust-1", "items": [{"product_id": "p-1", "name": "Widget", "quantity": 1, "price": "10.00"}]}`
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(body)))
+
+	assert.Equal(t, middleware.StatusQuotaExceeded, rec.Code)
+	var got ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "QUOTA_EXCEEDED", got.Code)
+}
+
+func TestCreateOrder_BodyOverMaxSize_Returns413(t *testing.T) {
+	f := newPatchFixture(t)
+	h := middleware.MaxBodySize(256)(f.router)
+	items := strings.Repeat(`{"product_id": "p-1", "name": "Widget", "quantity": 1, "price": "10.00"},`, 20)
+	body := `{"customer_id": "cust-1", "items": [` + strings.TrimSuffix(items, ",") + `]}`
+	// Of unknown length, so the limit is hit while decoding
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", io.MultiReader(strings.NewReader(body)))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	var got ErrorResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "BODY_TOO_LARGE", got.Code)
+	assert.Empty(t, f.events.Events())
+}
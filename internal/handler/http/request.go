@@ -14,18 +14,22 @@
 
 package http //nolint:revive // intentional package name matching handler layer
 
+import "encoding/json"
+
 // CreateOrderRequest represents the request to create an order
 type CreateOrderRequest struct {
 	CustomerID string      `json:"customer_id"`
+	Currency   string      `json:"currency,omitempty"` // ISO 4217; defaults to the base currency
 	Items      []OrderItem `json:"items"`
 }
 
 // OrderItem represents an item in an order request
 type OrderItem struct {
-	ProductID string  `json:"product_id"`
-	Name      string  `json:"name"`
-	Quantity  int     `json:"quantity"`
-	Price     float64 `json:"price"`
+	ProductID string      `json:"product_id"`
+	Name      string      `json:"name"`
+	Quantity  int         `json:"quantity"`
+	Price     json.Number `json:"price"`              // Decimal number or string, kept exact, e.g. 19.99 or "19.99"
+	Currency  string      `json:"currency,omitempty"` // Must match the order's currency if set
 }
 
 // UpdateOrderRequest represents the request to update an order
@@ -33,7 +37,20 @@ type UpdateOrderRequest struct {
 	Items []OrderItem `json:"items"`
 }
 
+// PatchOrderRequest represents a partial update of an order. Omitted or
+// null fields are left unchanged.
+type PatchOrderRequest struct {
+	CustomerID *string      `json:"customer_id"`
+	Items      *[]OrderItem `json:"items"`
+	Status     *string      `json:"status"`
+}
+
 // UpdateStatusRequest represents the request to update order status
 type UpdateStatusRequest struct {
 	Status string `json:"status"`
 }
+
+// CancelOrderRequest represents the request to cancel an order
+type CancelOrderRequest struct {
+	Reason string `json:"reason"`
+}
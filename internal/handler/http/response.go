@@ -18,24 +18,27 @@ import "time"
 
 // OrderResponse represents an order in HTTP responses
 type OrderResponse struct {
-	ID         string              `json:"id"`
-	CustomerID string              `json:"customer_id"`
-	Items      []OrderItemResponse `json:"items"`
-	Status     string              `json:"status"`
-	Total      float64             `json:"total"`
-	Version    int                 `json:"version"`
-	CreatedAt  time.Time           `json:"created_at"`
-	UpdatedAt  time.Time           `json:"updated_at"`
+	ID           string              `json:"id"`
+	CustomerID   string              `json:"customer_id"`
+	Items        []OrderItemResponse `json:"items"`
+	Status       string              `json:"status"`
+	Total        string              `json:"total"` // Decimal, e.g. "19.99"
+	Currency     string              `json:"currency"`
+	Version      int                 `json:"version"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+	DeletedAt    *time.Time          `json:"deleted_at,omitempty"`
+	CancelReason string              `json:"cancel_reason,omitempty"`
 }
 
 // OrderItemResponse represents an item in an order response
 type OrderItemResponse struct {
-	ID        string  `json:"id"`
-	ProductID string  `json:"product_id"`
-	Name      string  `json:"name"`
-	Quantity  int     `json:"quantity"`
-	Price     float64 `json:"price"`
-	Subtotal  float64 `json:"subtotal"`
+	ID        string `json:"id"`
+	ProductID string `json:"product_id"`
+	Name      string `json:"name"`
+	Quantity  int    `json:"quantity"`
+	Price     string `json:"price"`    // Decimal, like OrderResponse.Total
+	Subtotal  string `json:"subtotal"` // Decimal
 }
 
 // ListOrdersResponse represents a paginated list of orders (ADR-0002 format)
@@ -44,17 +47,37 @@ type ListOrdersResponse struct {
 	Total  int64           `json:"total"`
 	Limit  int             `json:"limit"`
 	Offset int             `json:"offset"`
+	// NextCursor fetches the following page; empty on the last page
+	NextCursor string `json:"next_cursor"`
 }
 
-// ErrorResponse represents an error response
+// OrderHistoryResponse represents the status timeline of an order
+type OrderHistoryResponse struct {
+	OrderID string                 `json:"order_id"`
+	History []StatusChangeResponse `json:"history"` // Oldest first
+}
+
+// StatusChangeResponse represents one status transition in an order history
+type StatusChangeResponse struct {
+	OldStatus  string    `json:"old_status"`
+	NewStatus  string    `json:"new_status"`
+	ChangedBy  string    `json:"changed_by,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// ErrorResponse is the body of every error response. Code is stable for
+// clients to match on; Details, when present, holds the fields of the
+// error, such as each invalid field of a VALIDATION_FAILED response
 type ErrorResponse struct {
-	Error string `json:"error"`
-	Code  string `json:"code,omitempty"`
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Details map[string]string `json:"details,omitempty"`
 }
 
 // HealthResponse represents a health check response
 type HealthResponse struct {
 	Status  string            `json:"status"`
 	Checks  map[string]string `json:"checks"`
+	Failed  []string          `json:"failed,omitempty"` // Names of the failed checks
 	Version string            `json:"version"`
 }
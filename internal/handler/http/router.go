@@ -16,19 +16,21 @@ package http //nolint:revive // intentional: matches handler layer convention
 
 import (
 	"log/slog"
+	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/middleware"
 )
 
-// NewRouter creates a new Chi router with all routes configured
+// NewRouter creates a new Chi router with all routes configured.
+// apiMiddleware wraps the order routes only, not the health checks.
 // CONSTRAINT: Health endpoints must not require authentication (ADR-0002)
-func NewRouter(orderHandler *OrderHandler, healthHandler *HealthHandler, logger *slog.Logger) *chi.Mux {
+func NewRouter(orderHandler *OrderHandler, healthHandler *HealthHandler, logger *slog.Logger, apiMiddleware ...func(http.Handler) http.Handler) *chi.Mux {
 	r := chi.NewRouter()
 
 	// Middleware stack
-	r.Use(chimiddleware.RequestID)
+	r.Use(middleware.RequestID())
 	r.Use(chimiddleware.RealIP)
 	r.Use(middleware.Logging(logger))
 	r.Use(chimiddleware.Recoverer)
@@ -38,7 +40,10 @@ func NewRouter(orderHandler *OrderHandler, healthHandler *HealthHandler, logger
 	r.Get("/readyz", healthHandler.Readyz)
 
 	// Order routes with /api/v1 prefix
-	orderHandler.RegisterRoutes(r)
+	r.Group(func(r chi.Router) {
+		r.Use(apiMiddleware...)
+		orderHandler.RegisterRoutes(r)
+	})
 
 	return r
 }
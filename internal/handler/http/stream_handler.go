@@ -0,0 +1,141 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http //nolint:revive // intentional: matches handler layer convention
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+const (
+	// streamBuffer is how many events are queued for a stream client
+	// before further events are dropped for it.
+	streamBuffer = 64
+	// streamKeepAlive is how often an idle stream sends a comment, so
+	// proxies do not close the connection.
+	streamKeepAlive = 15 * time.Second
+)
+
+// EventStream is a live feed of published order events, such as a bus.Bus
+// the service's publisher also publishes to.
+type EventStream interface {
+	// Stream returns a channel receiving events until ctx is done, when
+	// the channel is closed. Up to buffer events are queued for a slow
+	// reader.
+	Stream(ctx context.Context, buffer int) <-chan messaging.OrderEvent
+}
+
+// OrderHandlerOption configures an OrderHandler.
+type OrderHandlerOption func(*OrderHandler)
+
+// WithEventStream serves GET /api/v1/orders/stream from events. Without it
+// the endpoint responds 503.
+func WithEventStream(events EventStream) OrderHandlerOption {
+	return func(h *OrderHandler) { h.events = events }
+}
+
+// StreamOrders handles GET /api/v1/orders/stream
+// Pushes order events as server-sent events while the client stays
+// connected: each message has the event ID as its id, the event type as
+// its event, and the OrderEvent JSON as its data. The customer_id and
+// status query parameters keep only events about matching orders.
+func (h *OrderHandler) StreamOrders(w http.ResponseWriter, r *http.Request) {
+	if h.events == nil {
+		writeError(w, errNoEventStream)
+		return
+	}
+	match, err := parseStreamFilter(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// The stream outlives the server's write timeout
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		writeError(w, errNoStreamWriter)
+		return
+	}
+
+	// Subscribed before the headers are sent, so a client that has the
+	// response sees every event published after it
+	events := h.events.Stream(r.Context(), streamBuffer)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case evt, ok := <-events:
+			if !ok {
+				return // client disconnected
+			}
+			if !match(evt) {
+				continue
+			}
+			if err := writeServerSentEvent(w, evt); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// parseStreamFilter returns whether an event passes the customer_id and
+// status query parameters. An unknown status is a *domain.FilterError.
+func parseStreamFilter(r *http.Request) (func(messaging.OrderEvent) bool, error) {
+	customerID := r.URL.Query().Get("customer_id")
+	var status domain.OrderStatus
+	if s := r.URL.Query().Get("status"); s != "" {
+		parsed, err := domain.ParseOrderStatus(s)
+		if err != nil {
+			return nil, &domain.FilterError{Field: "status", Value: s, Reason: "is not an order status"}
+		}
+		status = parsed
+	}
+	return func(evt messaging.OrderEvent) bool {
+		return (customerID == "" || evt.CustomerID == customerID) &&
+			(status == "" || evt.Status == status)
+	}, nil
+}
+
+func writeServerSentEvent(w http.ResponseWriter, evt messaging.OrderEvent) error {
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", evt.EventID, evt.EventType, data)
+	return err
+}
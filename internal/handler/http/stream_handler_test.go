@@ -0,0 +1,168 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http //nolint:revive // intentional: matches handler layer convention
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/bus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serverSentEvent is one message read from an event stream.
+type serverSentEvent struct {
+	id, event string
+	data      messaging.OrderEvent
+}
+
+// openStream connects to the stream endpoint of srv with query and returns
+// a reader of its messages. The stream is closed with ctx.
+func openStream(ctx context.Context, t *testing.T, srv *httptest.Server, query string) *bufio.Reader {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/v1/orders/stream"+query, nil)
+	require.NoError(t, err)
+	resp, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	return bufio.NewReader(resp.Body)
+}
+
+// readEvent reads the next message from r, skipping comments.
+func readEvent(t *testing.T, r *bufio.Reader) serverSentEvent {
+	t.Helper()
+	var msg serverSentEvent
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		field, value, _ := strings.Cut(line, ": ")
+		switch field {
+		case "":
+			if msg.event != "" {
+				return msg
+			}
+		case "id":
+			msg.id = value
+		case "event":
+			msg.event = value
+		case "data":
+			require.NoError(t, json.Unmarshal([]byte(value), &msg.data))
+		}
+	}
+}
+
+func TestStreamOrders_OrderChange_PushedAsServerSentEvent(t *testing.T) {
+	f := newPatchFixture(t)
+	srv := httptest.NewServer(f.router)
+	defer srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream := openStream(ctx, t, srv, "")
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/orders/"+f.stored.ID.String()+"/status", strings.NewReader(`{"status": "confirmed"}`))
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	msg := readEvent(t, stream)
+	emitted := f.events.Events()
+	require.Len(t, emitted, 1)
+	assert.Equal(t, emitted[0].EventID, msg.id)
+	assert.Equal(t, messaging.EventOrderStatusChanged, msg.event)
+	assert.Equal(t, emitted[0], msg.data)
+	assert.Equal(t, domain.OrderStatusConfirmed, msg.data.NewStatus)
+}
+
+func TestStreamOrders_Filters_SkipOtherOrders(t *testing.T) {
+	f := newPatchFixture(t)
+	srv := httptest.NewServer(f.router)
+	defer srv.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream := openStream(ctx, t, srv, "?customer_id=cust-2&status=confirmed")
+
+	other := *f.stored
+	other.CustomerID = "cust-2"
+	require.NoError(t, f.bus.PublishOrderCreated(ctx, f.stored), "another customer")
+	require.NoError(t, f.bus.PublishOrderCreated(ctx, &other), "another status")
+	other.Status = domain.OrderStatusConfirmed
+	require.NoError(t, f.bus.PublishOrderStatusChanged(ctx, &other, domain.OrderStatusPending, domain.OrderStatusConfirmed))
+
+	msg := readEvent(t, stream)
+	assert.Equal(t, messaging.EventOrderStatusChanged, msg.event)
+	assert.Equal(t, "cust-2", msg.data.CustomerID)
+}
+
+// countingStream is an EventStream over a bus that counts its open streams.
+type countingStream struct {
+	*bus.Bus
+	open atomic.Int32
+}
+
+func (c *countingStream) Stream(ctx context.Context, buffer int) <-chan messaging.OrderEvent {
+	c.open.Add(1)
+	context.AfterFunc(ctx, func() { c.open.Add(-1) })
+	return c.Bus.Stream(ctx, buffer)
+}
+
+func TestStreamOrders_ClientDisconnects_EndsSubscription(t *testing.T) {
+	events := &countingStream{Bus: bus.New()}
+	srv := httptest.NewServer(http.HandlerFunc(NewOrderHandler(nil, WithEventStream(events)).StreamOrders))
+	defer srv.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	resp, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, int32(1), events.open.Load())
+
+	cancel()
+
+	assert.Eventually(t, func() bool { return events.open.Load() == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestStreamOrders_InvalidStatus_Returns400(t *testing.T) {
+	f := newPatchFixture(t)
+	rec := httptest.NewRecorder()
+
+	f.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders/stream?status=lost", nil))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "INVALID_FILTER")
+}
+
+func TestStreamOrders_NoEventStream_Returns503(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	NewOrderHandler(nil).StreamOrders(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders/stream", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "STREAM_UNAVAILABLE")
+}
@@ -0,0 +1,368 @@
+// Package async provides an EventPublisher decorator that publishes in the
+// background, so a request does not wait on a slow broker, through a
+// bounded buffer, so a broker outage cannot exhaust memory.
+package async
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+var (
+	// ErrBufferFull is returned by a publish that found the buffer full,
+	// at once under WithReject or once the WithBlock timeout passed. It is
+	// returned alongside messaging.ErrBrokerUnavailable, since a full
+	// buffer means the broker is not keeping up.
+	ErrBufferFull = errors.New("async publisher buffer full")
+
+	// ErrClosed is returned by a publish after Close.
+	ErrClosed = errors.New("async publisher closed")
+)
+
+const (
+	defaultBufferSize   = 1000
+	defaultWorkers      = 4
+	defaultBlockTimeout = time.Second
+)
+
+// fullPolicy is what a publish does when the buffer is full.
+type fullPolicy int
+
+const (
+	block fullPolicy = iota
+	dropOldest
+	reject
+)
+
+// Option configures a Publisher created by Wrap.
+type Option func(*Publisher)
+
+// WithBufferSize sets how many events may wait to be published, split
+// evenly between the workers. Zero or less uses 1000.
+func WithBufferSize(n int) Option {
+	return func(p *Publisher) { p.size = n }
+}
+
+// WithWorkers sets how many events are published at once. Zero or less
+// uses 4.
+func WithWorkers(n int) Option {
+	return func(p *Publisher) { p.workers = n }
+}
+
+// WithBlock makes a publish that finds the buffer full wait up to timeout
+// for room, or until its context ends, before failing with ErrBufferFull.
+// This is the default, with a timeout of one second; zero or less uses it.
+func WithBlock(timeout time.Duration) Option {
+	return func(p *Publisher) { p.policy, p.timeout = block, timeout }
+}
+
+// WithDropOldest makes a publish that finds the buffer full drop the
+// longest-waiting event of its worker to make room. The publish always
+// succeeds; the dropped event is logged and counted in Stats.Dropped.
+func WithDropOldest() Option {
+	return func(p *Publisher) { p.policy = dropOldest }
+}
+
+// WithReject makes a publish that finds the buffer full fail at once with
+// ErrBufferFull.
+func WithReject() Option {
+	return func(p *Publisher) { p.policy = reject }
+}
+
+// WithLogger sets where events that failed to publish, or were dropped,
+// are logged. Defaults to logging nothing.
+func WithLogger(logger *slog.Logger) Option {
+	return func(p *Publisher) { p.logger = logger }
+}
+
+// Stats is a snapshot of a Publisher's buffer and its counters. The
+// counters only grow, so they can be exported as metric counters.
+type Stats struct {
+	// Depth is how many events are waiting in the buffer, and Capacity
+	// how many it holds.
+	Depth    int
+	Capacity int
+	// Dropped counts events dropped under WithDropOldest.
+	Dropped int
+	// Rejected counts publishes failed with ErrBufferFull.
+	Rejected int
+	// Failed counts events the wrapped publisher failed to publish.
+	Failed int
+}
+
+var _ messaging.EventPublisher = (*Publisher)(nil)
+
+// Publisher queues each publish and returns, leaving a pool of workers to
+// pass it to the wrapped EventPublisher. A publish only fails if the event
+// could not be queued; the wrapped publisher's errors are logged and
+// counted in Stats.Failed, so wrap it in retry.Wrap, or fallback.Wrap, to
+// recover from them.
+//
+// Events of one order always go to the same worker, so they are published
+// in the order they were queued. Each publish runs with the values of its
+// caller's context but not its cancellation, since the request has
+// usually ended by then. The event ID and time are pinned when the event
+// is queued, and the order is copied, so the caller may change it
+// afterwards.
+//
+// Close must be called to publish the events still queued.
+type Publisher struct {
+	next    messaging.EventPublisher
+	size    int
+	workers int
+	policy  fullPolicy
+	timeout time.Duration
+	logger  *slog.Logger
+
+	mu     sync.RWMutex // Held to queue, and exclusively to close, the shards
+	closed bool
+	shards []chan job
+	done   chan struct{} // Closed once every worker has returned
+
+	dropped  atomic.Int64
+	rejected atomic.Int64
+	failed   atomic.Int64
+}
+
+// job is a queued publish.
+type job struct {
+	ctx       context.Context
+	eventType string
+	orderID   string
+	publish   func(context.Context) error
+}
+
+// Wrap returns p decorated to publish in the background, and starts its
+// workers. There is no Middleware constructor: the caller keeps the
+// *Publisher to Close it.
+func Wrap(p messaging.EventPublisher, opts ...Option) *Publisher {
+	a := &Publisher{next: p, logger: slog.New(slog.DiscardHandler), done: make(chan struct{})}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.size <= 0 {
+		a.size = defaultBufferSize
+	}
+	if a.workers <= 0 {
+		a.workers = defaultWorkers
+	}
+	if a.timeout <= 0 {
+		a.timeout = defaultBlockTimeout
+	}
+	perShard := (a.size + a.workers - 1) / a.workers
+	a.shards = make([]chan job, a.workers)
+	var wg sync.WaitGroup
+	for i := range a.shards {
+		a.shards[i] = make(chan job, perShard)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.work(a.shards[i])
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(a.done)
+	}()
+	return a
+}
+
+// Stats returns the buffer's depth and capacity and the counters.
+func (p *Publisher) Stats() Stats {
+	st := Stats{
+		Dropped:  int(p.dropped.Load()),
+		Rejected: int(p.rejected.Load()),
+		Failed:   int(p.failed.Load()),
+	}
+	for _, shard := range p.shards {
+		st.Depth += len(shard)
+		st.Capacity += cap(shard)
+	}
+	return st
+}
+
+// Close stops accepting publishes and waits for the queued events to be
+// published. If ctx ends first, Close returns its error; the workers keep
+// draining the buffer in the background. It does not close the wrapped
+// publisher. Calling Close again waits again.
+func (p *Publisher) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		for _, shard := range p.shards {
+			close(shard)
+		}
+	}
+	p.mu.Unlock()
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("async publisher close: %d events not published: %w", p.Stats().Depth, ctx.Err())
+	}
+}
+
+// PublishOrderCreated queues an order.created event.
+func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	order = cloneOrder(order)
+	return p.enqueue(ctx, messaging.EventOrderCreated, order, func(ctx context.Context) error {
+		return p.next.PublishOrderCreated(ctx, order)
+	})
+}
+
+// PublishOrderUpdated queues an order.updated event.
+func (p *Publisher) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	order, changes = cloneOrder(order), maps.Clone(changes)
+	return p.enqueue(ctx, messaging.EventOrderUpdated, order, func(ctx context.Context) error {
+		return p.next.PublishOrderUpdated(ctx, order, changes)
+	})
+}
+
+// PublishOrderStatusChanged queues an order.status_changed event.
+func (p *Publisher) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
+	order = cloneOrder(order)
+	return p.enqueue(ctx, messaging.EventOrderStatusChanged, order, func(ctx context.Context) error {
+		return p.next.PublishOrderStatusChanged(ctx, order, oldStatus, newStatus)
+	})
+}
+
+// PublishOrderCancelled queues an order.cancelled event.
+func (p *Publisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	order = cloneOrder(order)
+	return p.enqueue(ctx, messaging.EventOrderCancelled, order, func(ctx context.Context) error {
+		return p.next.PublishOrderCancelled(ctx, order, reason)
+	})
+}
+
+// PublishOrderDeleted queues an order.deleted event.
+func (p *Publisher) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	order = cloneOrder(order)
+	return p.enqueue(ctx, messaging.EventOrderDeleted, order, func(ctx context.Context) error {
+		return p.next.PublishOrderDeleted(ctx, order)
+	})
+}
+
+// PublishOrderExpired queues an order.expired event.
+func (p *Publisher) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	order = cloneOrder(order)
+	return p.enqueue(ctx, messaging.EventOrderExpired, order, func(ctx context.Context) error {
+		return p.next.PublishOrderExpired(ctx, order)
+	})
+}
+
+// PublishOrderSnapshot queues an order.snapshot event.
+func (p *Publisher) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	order = cloneOrder(order)
+	return p.enqueue(ctx, messaging.EventOrderSnapshot, order, func(ctx context.Context) error {
+		return p.next.PublishOrderSnapshot(ctx, order)
+	})
+}
+
+// enqueue queues publish on the shard of order, applying the full-buffer
+// policy if it has no room.
+func (p *Publisher) enqueue(ctx context.Context, eventType string, order *domain.Order, publish func(context.Context) error) error {
+	j := job{ctx: pin(ctx), eventType: eventType, orderID: order.ID.String(), publish: publish}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return fmt.Errorf("async publish %s: %w", eventType, ErrClosed)
+	}
+	shard := p.shards[p.shardOf(j.orderID)]
+	select {
+	case shard <- j:
+		return nil
+	default:
+	}
+
+	switch p.policy {
+	case dropOldest:
+		// A worker may take the oldest first, leaving room without a drop
+		for {
+			select {
+			case old := <-shard:
+				p.dropped.Add(1)
+				p.logger.WarnContext(old.ctx, "async publisher buffer full, dropped oldest event",
+					slog.String("event_type", old.eventType),
+					slog.String("order_id", old.orderID))
+			default:
+			}
+			select {
+			case shard <- j:
+				return nil
+			default:
+			}
+		}
+	case block:
+		timer := time.NewTimer(p.timeout)
+		defer timer.Stop()
+		select {
+		case shard <- j:
+			return nil
+		case <-timer.C:
+		case <-ctx.Done():
+			p.rejected.Add(1)
+			return fmt.Errorf("async publish %s: %w", eventType, ctx.Err())
+		}
+	}
+	p.rejected.Add(1)
+	return fmt.Errorf("async publish %s: %w: %w", eventType, ErrBufferFull, messaging.ErrBrokerUnavailable)
+}
+
+// work publishes the jobs of shard until it is closed and drained.
+func (p *Publisher) work(shard <-chan job) {
+	for j := range shard {
+		if err := j.publish(j.ctx); err != nil {
+			p.failed.Add(1)
+			p.logger.ErrorContext(j.ctx, "async publish failed",
+				slog.String("event_type", j.eventType),
+				slog.String("order_id", j.orderID),
+				slog.String("error", err.Error()))
+		}
+	}
+}
+
+// shardOf returns the index of the shard that publishes the events of
+// orderID.
+func (p *Publisher) shardOf(orderID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(orderID))
+	return int(h.Sum32() % uint32(len(p.shards)))
+}
+
+// pin returns ctx detached from its cancellation, with the event ID and
+// time fixed unless the caller already fixed them, so the event is the
+// same whenever a worker publishes it.
+func pin(ctx context.Context) context.Context {
+	ctx = context.WithoutCancel(ctx)
+	if _, ok := messaging.EventID(ctx); !ok {
+		ctx = messaging.WithEventID(ctx, uuid.NewString())
+	}
+	if _, ok := messaging.OccurredAt(ctx); !ok {
+		ctx = messaging.WithOccurredAt(ctx, time.Now())
+	}
+	return ctx
+}
+
+// cloneOrder returns a copy of order sharing nothing the caller may change.
+func cloneOrder(order *domain.Order) *domain.Order {
+	c := *order
+	c.Items = slices.Clone(order.Items)
+	if order.DeletedAt != nil {
+		deletedAt := *order.DeletedAt
+		c.DeletedAt = &deletedAt
+	}
+	return &c
+}
@@ -0,0 +1,232 @@
+package async
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOrder() *domain.Order {
+	return &domain.Order{ID: uuid.New(), CustomerID: "cust-1", Status: domain.OrderStatusPending, Version: 1, Total: domain.Money{Amount: 1000, Currency: "USD"}}
+}
+
+// gated records order.created events in a memory.Publisher, each only
+// once release lets it through. started receives an order ID as each
+// publish begins.
+type gated struct {
+	*memory.Publisher
+	started chan string
+	release chan struct{}
+	err     error
+}
+
+func newGated() *gated {
+	return &gated{Publisher: memory.New(), started: make(chan string, 100), release: make(chan struct{})}
+}
+
+func (g *gated) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	g.started <- order.ID.String()
+	<-g.release
+	if g.err != nil {
+		return g.err
+	}
+	return g.Publisher.PublishOrderCreated(ctx, order)
+}
+
+// busy returns an async publisher with one worker and room for size
+// events, whose worker is held up publishing an event of its own.
+func busy(t *testing.T, size int, opts ...Option) (*Publisher, *gated) {
+	t.Helper()
+	inner := newGated()
+	pub := Wrap(inner, append([]Option{WithWorkers(1), WithBufferSize(size)}, opts...)...)
+	t.Cleanup(func() {
+		close(inner.release)
+		require.NoError(t, pub.Close(context.Background()))
+	})
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newOrder()))
+	<-inner.started
+	return pub, inner
+}
+
+func orderIDs(rec *memory.Publisher) []string {
+	var ids []string
+	for _, evt := range rec.Events() {
+		ids = append(ids, evt.OrderID)
+	}
+	return ids
+}
+
+func TestPublisher_ReturnsBeforeBrokerAnswers(t *testing.T) {
+	inner := newGated()
+	pub := Wrap(inner)
+	order := newOrder()
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+	assert.Equal(t, order.ID.String(), <-inner.started)
+	assert.Empty(t, inner.Events(), "still held up by the broker")
+
+	close(inner.release)
+	require.NoError(t, pub.Close(context.Background()))
+	assert.Equal(t, []string{order.ID.String()}, orderIDs(inner.Publisher))
+}
+
+func TestPublisher_Reject_FullBuffer_ReturnsErrBufferFull(t *testing.T) {
+	pub, _ := busy(t, 1, WithReject())
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newOrder()), "fills the buffer")
+
+	err := pub.PublishOrderCreated(context.Background(), newOrder())
+
+	assert.ErrorIs(t, err, ErrBufferFull)
+	assert.ErrorIs(t, err, messaging.ErrBrokerUnavailable)
+	assert.Equal(t, Stats{Depth: 1, Capacity: 1, Rejected: 1}, pub.Stats())
+}
+
+func TestPublisher_Block_FullBuffer_FailsAfterTimeout(t *testing.T) {
+	const timeout = 30 * time.Millisecond
+	pub, _ := busy(t, 1, WithBlock(timeout))
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newOrder()))
+
+	start := time.Now()
+	err := pub.PublishOrderCreated(context.Background(), newOrder())
+
+	assert.ErrorIs(t, err, ErrBufferFull)
+	assert.GreaterOrEqual(t, time.Since(start), timeout)
+	assert.Equal(t, 1, pub.Stats().Rejected)
+}
+
+func TestPublisher_Block_FullBuffer_QueuesOnceRoomFrees(t *testing.T) {
+	inner := newGated()
+	pub := Wrap(inner, WithWorkers(1), WithBufferSize(1), WithBlock(time.Minute))
+	first, second, third := newOrder(), newOrder(), newOrder()
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), first))
+	<-inner.started
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), second))
+
+	go func() { inner.release <- struct{}{} }() // Lets the first through
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), third))
+
+	close(inner.release)
+	require.NoError(t, pub.Close(context.Background()))
+	assert.Equal(t, []string{first.ID.String(), second.ID.String(), third.ID.String()}, orderIDs(inner.Publisher))
+}
+
+func TestPublisher_Block_ContextEnds_StopsWaiting(t *testing.T) {
+	pub, _ := busy(t, 1, WithBlock(time.Minute))
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newOrder()))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := pub.PublishOrderCreated(ctx, newOrder())
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestPublisher_DropOldest_FullBuffer_DropsLongestWaiting(t *testing.T) {
+	inner := newGated()
+	pub := Wrap(inner, WithWorkers(1), WithBufferSize(2), WithDropOldest())
+	orders := []*domain.Order{newOrder(), newOrder(), newOrder(), newOrder()}
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), orders[0]))
+	<-inner.started
+
+	for _, order := range orders[1:] {
+		require.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+	}
+	assert.Equal(t, Stats{Depth: 2, Capacity: 2, Dropped: 1}, pub.Stats())
+
+	close(inner.release)
+	require.NoError(t, pub.Close(context.Background()))
+	assert.Equal(t, []string{orders[0].ID.String(), orders[2].ID.String(), orders[3].ID.String()}, orderIDs(inner.Publisher),
+		"the second, the oldest queued, was dropped")
+}
+
+func TestPublisher_Close_FlushesQueuedEvents(t *testing.T) {
+	rec := memory.New()
+	pub := Wrap(rec, WithWorkers(3), WithBufferSize(100))
+	for range 50 {
+		require.NoError(t, pub.PublishOrderCreated(context.Background(), newOrder()))
+	}
+
+	require.NoError(t, pub.Close(context.Background()))
+
+	assert.Len(t, rec.Events(), 50)
+	assert.Zero(t, pub.Stats().Depth)
+	err := pub.PublishOrderCreated(context.Background(), newOrder())
+	assert.ErrorIs(t, err, ErrClosed)
+	assert.NoError(t, pub.Close(context.Background()), "closing twice")
+}
+
+func TestPublisher_Close_ContextEnds_ReturnsItsError(t *testing.T) {
+	pub, inner := busy(t, 5)
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newOrder()))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := pub.Close(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "1 events not published")
+	assert.Empty(t, inner.Events())
+}
+
+func TestPublisher_EventsOfAnOrder_PublishedInOrder(t *testing.T) {
+	rec := memory.New()
+	pub := Wrap(rec, WithWorkers(4))
+	orders := []*domain.Order{newOrder(), newOrder(), newOrder()}
+	for v := 1; v <= 20; v++ {
+		for _, order := range orders {
+			order.Version = v
+			require.NoError(t, pub.PublishOrderUpdated(context.Background(), order, nil))
+		}
+	}
+
+	require.NoError(t, pub.Close(context.Background()))
+
+	last := map[string]int{}
+	for _, evt := range rec.Events() {
+		assert.Equal(t, last[evt.OrderID]+1, evt.Version, "order %s", evt.OrderID)
+		last[evt.OrderID] = evt.Version
+	}
+	assert.Len(t, rec.Events(), 60)
+}
+
+func TestPublisher_EventFixedWhenQueued(t *testing.T) {
+	inner := newGated()
+	pub := Wrap(inner)
+	order := newOrder()
+	ctx, cancel := context.WithCancel(messaging.WithEventID(context.Background(), "evt-1"))
+
+	require.NoError(t, pub.PublishOrderCreated(ctx, order))
+	queuedAt := time.Now()
+	order.CustomerID = "cust-2"
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	close(inner.release)
+	require.NoError(t, pub.Close(context.Background()))
+
+	events := inner.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "evt-1", events[0].EventID)
+	assert.Equal(t, "cust-1", events[0].CustomerID, "the caller's later change is not published")
+	assert.WithinDuration(t, queuedAt, events[0].OccurredAt, 5*time.Millisecond)
+}
+
+func TestPublisher_WrappedPublisherFails_CountedInStats(t *testing.T) {
+	inner := newGated()
+	inner.err = fmt.Errorf("write: %w", messaging.ErrBrokerUnavailable)
+	close(inner.release)
+	pub := Wrap(inner, WithLogger(slog.New(slog.DiscardHandler)))
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newOrder()), "the caller does not see it")
+	require.NoError(t, pub.Close(context.Background()))
+
+	assert.Equal(t, 1, pub.Stats().Failed)
+}
@@ -0,0 +1,188 @@
+// Package avro publishes order events as Avro in the Confluent wire format,
+// with the schema registered in a Confluent Schema Registry.
+package avro
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/hamba/avro/v2"
+	"github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	kafkapub "github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/kafka"
+)
+
+// ContentType is set in the content-type header of published messages.
+const ContentType = "application/vnd.confluent.avro"
+
+// Writer is the subset of *kafka.Writer used by Publisher. The writer must
+// have its Topic set.
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	io.Closer
+}
+
+// Codec encodes and decodes OrderEvents as Avro in the Confluent wire
+// format, looking up schema IDs in a Registry.
+type Codec struct {
+	registry *Registry
+	subject  string
+
+	mu       sync.RWMutex
+	resolved map[int]avro.Schema // writer schema ID -> schema to decode with
+}
+
+// NewCodec creates a codec that registers the OrderEvent schema under
+// subject (usually "<topic>-value").
+func NewCodec(registry *Registry, subject string) *Codec {
+	return &Codec{registry: registry, subject: subject, resolved: make(map[int]avro.Schema)}
+}
+
+// Encode returns evt as Avro, prefixed with the magic byte and schema ID.
+// The schema is registered on first use.
+func (c *Codec) Encode(ctx context.Context, evt messaging.OrderEvent) ([]byte, error) {
+	id, err := c.registry.Register(ctx, c.subject, SchemaJSON)
+	if err != nil {
+		return nil, err
+	}
+	body, err := avro.Marshal(orderEventSchema, toRecord(evt))
+	if err != nil {
+		return nil, fmt.Errorf("avro encode %s: %w", evt.EventType, err)
+	}
+	return appendWire(id, body), nil
+}
+
+// Decode parses a wire format payload. Payloads written with an older or
+// newer compatible version of the schema are resolved against the current
+// one: unknown fields are skipped and missing ones take their defaults.
+func (c *Codec) Decode(ctx context.Context, data []byte) (messaging.OrderEvent, error) {
+	id, body, err := splitWire(data)
+	if err != nil {
+		return messaging.OrderEvent{}, err
+	}
+	schema, err := c.readerSchema(ctx, id)
+	if err != nil {
+		return messaging.OrderEvent{}, err
+	}
+
+	var rec orderEventRecord
+	if err := avro.Unmarshal(schema, body, &rec); err != nil {
+		return messaging.OrderEvent{}, fmt.Errorf("avro decode schema %d: %w", id, err)
+	}
+	return fromRecord(rec), nil
+}
+
+// readerSchema returns the schema that decodes payloads written with
+// schema id into orderEventRecord
+func (c *Codec) readerSchema(ctx context.Context, id int) (avro.Schema, error) {
+	c.mu.RLock()
+	schema, ok := c.resolved[id]
+	c.mu.RUnlock()
+	if ok {
+		return schema, nil
+	}
+
+	writer, err := c.registry.Schema(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	schema = orderEventSchema
+	if writer.Fingerprint() != orderEventSchema.Fingerprint() {
+		schema, err = avro.NewSchemaCompatibility().Resolve(orderEventSchema, writer)
+		if err != nil {
+			return nil, fmt.Errorf("avro resolve schema %d: %w", id, err)
+		}
+	}
+
+	c.mu.Lock()
+	c.resolved[id] = schema
+	c.mu.Unlock()
+	return schema, nil
+}
+
+var _ messaging.EventPublisher = (*Publisher)(nil)
+
+// Publisher implements messaging.EventPublisher, writing Avro-encoded
+// events keyed by order ID.
+type Publisher struct {
+	codec  *Codec
+	writer Writer
+}
+
+// New creates a publisher that registers the OrderEvent schema under subject
+// in the Schema Registry at registryURL and writes events with inner.
+func New(registryURL, subject string, inner Writer) *Publisher {
+	return &Publisher{codec: NewCodec(NewRegistry(registryURL), subject), writer: inner}
+}
+
+// Codec returns the codec used to encode events, for decoding them.
+func (p *Publisher) Codec() *Codec {
+	return p.codec
+}
+
+// PublishOrderCreated publishes an order.created event.
+func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, messaging.NewOrderEvent(messaging.EventOrderCreated, order))
+}
+
+// PublishOrderUpdated publishes an order.updated event.
+func (p *Publisher) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	return p.Publish(ctx, messaging.NewUpdatedEvent(order, changes))
+}
+
+// PublishOrderStatusChanged publishes an order.status_changed event.
+func (p *Publisher) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
+	return p.Publish(ctx, messaging.NewStatusChangedEvent(order, oldStatus, newStatus))
+}
+
+// PublishOrderCancelled publishes an order.cancelled event.
+func (p *Publisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	return p.Publish(ctx, messaging.NewCancelledEvent(order, reason))
+}
+
+// PublishOrderDeleted publishes an order.deleted event.
+func (p *Publisher) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, messaging.NewOrderEvent(messaging.EventOrderDeleted, order))
+}
+
+// PublishOrderExpired publishes an order.expired event.
+func (p *Publisher) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, messaging.NewOrderEvent(messaging.EventOrderExpired, order))
+}
+
+// PublishOrderSnapshot publishes an order.snapshot event.
+func (p *Publisher) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, messaging.NewSnapshotEvent(order))
+}
+
+// Publish encodes evt and writes it, keyed by its order ID.
+func (p *Publisher) Publish(ctx context.Context, evt messaging.OrderEvent) error {
+	evt = messaging.Correlate(ctx, evt)
+	if err := evt.Validate(); err != nil {
+		return fmt.Errorf("avro publish %s: %w", evt.EventType, err)
+	}
+	value, err := p.codec.Encode(ctx, evt)
+	if err != nil {
+		return err
+	}
+	err = p.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(evt.OrderID),
+		Value: value,
+		Headers: []kafka.Header{
+			{Key: kafkapub.HeaderEventID, Value: []byte(evt.EventID)},
+			{Key: kafkapub.HeaderContentType, Value: []byte(ContentType)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("kafka write %s: %w", evt.EventType, err)
+	}
+	return nil
+}
+
+// Close closes the underlying writer.
+func (p *Publisher) Close() error {
+	return p.writer.Close()
+}
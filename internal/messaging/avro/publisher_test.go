@@ -0,0 +1,235 @@
+package avro
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hamba/avro/v2"
+	"github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	kafkapub "github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRegistry is an in-memory Schema Registry serving the two endpoints
+// the client uses.
+type mockRegistry struct {
+	mu        sync.Mutex
+	nextID    int
+	ids       map[string]int // schema -> ID
+	schemas   map[int]string
+	registers int
+	lookups   int
+}
+
+func newMockRegistry(t *testing.T) (*mockRegistry, *httptest.Server) {
+	t.Helper()
+	m := &mockRegistry{nextID: 41, ids: map[string]int{}, schemas: map[int]string{}}
+	srv := httptest.NewServer(http.HandlerFunc(m.serve))
+	t.Cleanup(srv.Close)
+	return m, srv
+}
+
+func (m *mockRegistry) add(schema string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if id, ok := m.ids[schema]; ok {
+		return id
+	}
+	m.nextID++
+	m.ids[schema] = m.nextID
+	m.schemas[m.nextID] = schema
+	return m.nextID
+}
+
+func (m *mockRegistry) serve(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", registryContentType)
+	switch {
+	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/subjects/"):
+		var req struct {
+			Schema string `json:"schema"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		m.mu.Lock()
+		m.registers++
+		m.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]int{"id": m.add(req.Schema)})
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/schemas/ids/"):
+		id, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/schemas/ids/"))
+		m.mu.Lock()
+		m.lookups++
+		schema, ok := m.schemas[id]
+		m.mu.Unlock()
+		if !ok {
+			http.Error(w, `{"error_code":40403,"message":"Schema not found"}`, http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"schema": schema})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// captureWriter records messages instead of sending them to Kafka.
+type captureWriter struct {
+	messages []kafka.Message
+}
+
+func (c *captureWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	c.messages = append(c.messages, msgs...)
+	return nil
+}
+
+func (c *captureWriter) Close() error { return nil }
+
+func newTestOrder() *domain.Order {
+	return &domain.Order{
+		ID:         uuid.New(),
+		CustomerID: "cust-123",
+		Items: []domain.OrderItem{
+			{ID: uuid.New(), ProductID: "p-1", Name: "Widget", Quantity: 2, Price: domain.Money{Amount: 1050}, Subtotal: domain.Money{Amount: 2100}},
+			{ID: uuid.New(), ProductID: "p-2", Name: "Gadget", Quantity: 1, Price: domain.Money{Amount: 500}, Subtotal: domain.Money{Amount: 500}},
+		},
+		Status:  domain.OrderStatusPending,
+		Total:   domain.Money{Amount: 2600, Currency: "EUR"},
+		Version: 3,
+	}
+}
+
+func TestPublisher_Publish_PrefixesConfluentWireFormat(t *testing.T) {
+	reg, srv := newMockRegistry(t)
+	w := &captureWriter{}
+	pub := New(srv.URL, "order-events-value", w)
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newTestOrder()))
+
+	require.Len(t, w.messages, 1)
+	value := w.messages[0].Value
+	id := reg.ids[SchemaJSON]
+	require.Equal(t, 42, id)
+	assert.Equal(t, []byte{0x00, 0x00, 0x00, 0x00, 0x2a}, value[:5], "magic byte then big-endian schema ID")
+
+	headers := map[string]string{}
+	for _, h := range w.messages[0].Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	assert.Equal(t, ContentType, headers[kafkapub.HeaderContentType])
+	assert.NotEmpty(t, headers[kafkapub.HeaderEventID])
+}
+
+func TestCodec_EncodeDecode_RoundTrip(t *testing.T) {
+	_, srv := newMockRegistry(t)
+	codec := NewCodec(NewRegistry(srv.URL), "order-events-value")
+
+	events := []messaging.OrderEvent{
+		messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder()),
+		messaging.NewStatusChangedEvent(newTestOrder(), domain.OrderStatusPending, domain.OrderStatusConfirmed),
+	}
+	withAddress := messaging.NewUpdatedEvent(newTestOrder(), map[string]messaging.ChangePair{"customer_id": {}, "items": {}})
+	withAddress.ShippingAddress = &messaging.AddressEvent{Line1: "1 Main St", City: "Springfield", PostalCode: "12345", Country: "US"}
+	replayed := messaging.NewOrderEvent(messaging.EventOrderDeleted, newTestOrder())
+	replayed.Replayed = true
+	replayed.CorrelationID = "req-abc"
+	replayed.Metadata = map[string]string{"region": "eu-west-1"}
+	events = append(events, withAddress, replayed)
+
+	for _, evt := range events {
+		t.Run(evt.EventType, func(t *testing.T) {
+			// timestamp-micros drops sub-microsecond precision
+			evt.OccurredAt = evt.OccurredAt.UTC().Truncate(time.Microsecond)
+
+			data, err := codec.Encode(context.Background(), evt)
+			require.NoError(t, err)
+			decoded, err := codec.Decode(context.Background(), data)
+			require.NoError(t, err)
+
+			decoded.OccurredAt = decoded.OccurredAt.UTC()
+			assert.Equal(t, evt, decoded)
+		})
+	}
+}
+
+func TestCodec_CachesSchemaIDs(t *testing.T) {
+	reg, srv := newMockRegistry(t)
+	codec := NewCodec(NewRegistry(srv.URL), "order-events-value")
+	evt := messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder())
+
+	for i := 0; i < 3; i++ {
+		data, err := codec.Encode(context.Background(), evt)
+		require.NoError(t, err)
+		_, err = codec.Decode(context.Background(), data)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, reg.registers)
+	assert.Equal(t, 1, reg.lookups)
+}
+
+func TestCodec_Decode_NewerWriterSchema_SkipsUnknownFields(t *testing.T) {
+	reg, srv := newMockRegistry(t)
+	codec := NewCodec(NewRegistry(srv.URL), "order-events-value")
+
+	// A newer producer added a "channel" field
+	var def map[string]any
+	require.NoError(t, json.Unmarshal([]byte(SchemaJSON), &def))
+	fields := def["fields"].([]any)
+	def["fields"] = append(fields, map[string]any{"name": "channel", "type": "string", "default": "web"})
+	v2JSON, err := json.Marshal(def)
+	require.NoError(t, err)
+	v2, err := avro.ParseWithCache(string(v2JSON), "", &avro.SchemaCache{})
+	require.NoError(t, err)
+	id := reg.add(string(v2JSON))
+
+	evt := messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder())
+	evt.OccurredAt = evt.OccurredAt.UTC().Truncate(time.Microsecond)
+	newer := struct {
+		orderEventRecord
+		Channel string `avro:"channel"`
+	}{orderEventRecord: toRecord(evt), Channel: "mobile"}
+	body, err := avro.Marshal(v2, newer)
+	require.NoError(t, err)
+
+	decoded, err := codec.Decode(context.Background(), appendWire(id, body))
+
+	require.NoError(t, err)
+	assert.Equal(t, evt.EventID, decoded.EventID)
+	assert.Equal(t, evt.Items, decoded.Items)
+	assert.Equal(t, evt.Total, decoded.Total)
+}
+
+func TestCodec_Decode_MissingMagicByte_ReturnsErrInvalidWireFormat(t *testing.T) {
+	_, srv := newMockRegistry(t)
+	codec := NewCodec(NewRegistry(srv.URL), "order-events-value")
+
+	_, err := codec.Decode(context.Background(), []byte(`{"event_id":"x"}`))
+
+	assert.ErrorIs(t, err, ErrInvalidWireFormat)
+}
+
+func TestPublisher_RegistryUnavailable_ReturnsErrorWithoutWriting(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	w := &captureWriter{}
+	pub := New(srv.URL, "order-events-value", w)
+
+	err := pub.PublishOrderCreated(context.Background(), newTestOrder())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 503")
+	assert.Empty(t, w.messages)
+}
@@ -0,0 +1,131 @@
+package avro
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hamba/avro/v2"
+)
+
+// registryContentType is the media type of Schema Registry API requests.
+const registryContentType = "application/vnd.schemaregistry.v1+json"
+
+// Registry is a minimal Confluent Schema Registry client. Resolved IDs and
+// schemas are cached, since a schema's ID never changes once registered:
+// a new schema version gets a new ID rather than reusing an old one.
+type Registry struct {
+	baseURL string
+	client  *http.Client
+
+	mu      sync.RWMutex
+	ids     map[string]int      // subject + "\x00" + schema -> ID
+	schemas map[int]avro.Schema // ID -> writer schema
+}
+
+// NewRegistry creates a client for the Schema Registry at baseURL.
+func NewRegistry(baseURL string) *Registry {
+	return &Registry{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+		ids:     make(map[string]int),
+		schemas: make(map[int]avro.Schema),
+	}
+}
+
+// Register returns the ID of schema under subject, registering it as a new
+// version if the registry has not seen it. The registry rejects schemas
+// that break the subject's compatibility rules.
+func (r *Registry) Register(ctx context.Context, subject, schema string) (int, error) {
+	key := subject + "\x00" + schema
+	r.mu.RLock()
+	id, ok := r.ids[key]
+	r.mu.RUnlock()
+	if ok {
+		return id, nil
+	}
+
+	body, err := json.Marshal(struct {
+		Schema string `json:"schema"`
+	}{Schema: schema})
+	if err != nil {
+		return 0, err
+	}
+
+	var resp struct {
+		ID int `json:"id"`
+	}
+	path := "/subjects/" + url.PathEscape(subject) + "/versions"
+	if err := r.do(ctx, http.MethodPost, path, body, &resp); err != nil {
+		return 0, fmt.Errorf("schema registry register %s: %w", subject, err)
+	}
+
+	r.mu.Lock()
+	r.ids[key] = resp.ID
+	r.mu.Unlock()
+	return resp.ID, nil
+}
+
+// Schema returns the schema registered with id.
+func (r *Registry) Schema(ctx context.Context, id int) (avro.Schema, error) {
+	r.mu.RLock()
+	schema, ok := r.schemas[id]
+	r.mu.RUnlock()
+	if ok {
+		return schema, nil
+	}
+
+	var resp struct {
+		Schema string `json:"schema"`
+	}
+	if err := r.do(ctx, http.MethodGet, "/schemas/ids/"+strconv.Itoa(id), nil, &resp); err != nil {
+		return nil, fmt.Errorf("schema registry get schema %d: %w", id, err)
+	}
+
+	// Parse with a private cache: an older version of OrderEvent has the
+	// same full name as the current one and must not replace it
+	schema, err := avro.ParseWithCache(resp.Schema, "", &avro.SchemaCache{})
+	if err != nil {
+		return nil, fmt.Errorf("schema registry parse schema %d: %w", id, err)
+	}
+
+	r.mu.Lock()
+	r.schemas[id] = schema
+	r.mu.Unlock()
+	return schema, nil
+}
+
+func (r *Registry) do(ctx context.Context, method, path string, body []byte, out any) error {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, r.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", registryContentType)
+	if body != nil {
+		req.Header.Set("Content-Type", registryContentType)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,190 @@
+package avro
+
+import (
+	_ "embed"
+	"encoding/json"
+	"maps"
+	"time"
+
+	"github.com/hamba/avro/v2"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+// SchemaJSON is the Avro schema for OrderEvent, registered on first publish.
+//
+//go:embed order_event.avsc
+var SchemaJSON string
+
+// orderEventSchema is SchemaJSON parsed; it is the reader schema on decode.
+var orderEventSchema = avro.MustParse(SchemaJSON)
+
+// orderEventRecord is the Avro form of messaging.OrderEvent.
+type orderEventRecord struct {
+	EventID         string                      `avro:"event_id"`
+	EventType       string                      `avro:"event_type"`
+	SchemaVersion   int                         `avro:"schema_version"`
+	OrderID         string                      `avro:"order_id"`
+	CustomerID      string                      `avro:"customer_id"`
+	Status          string                      `avro:"status"`
+	OldStatus       string                      `avro:"old_status"`
+	NewStatus       string                      `avro:"new_status"`
+	CancelReason    string                      `avro:"cancel_reason"`
+	Total           float64                     `avro:"total"`
+	TotalMinor      int64                       `avro:"total_minor"`
+	Currency        string                      `avro:"currency"`
+	Version         int64                       `avro:"version"`
+	Items           []orderLineRecord           `avro:"items"`
+	ShippingAddress *addressRecord              `avro:"shipping_address"`
+	OccurredAt      time.Time                   `avro:"occurred_at"`
+	Replayed        bool                        `avro:"replayed"`
+	CorrelationID   string                      `avro:"correlation_id"`
+	ChangedFields   []string                    `avro:"changed_fields"`
+	Seq             int64                       `avro:"seq"`
+	Metadata        map[string]string           `avro:"metadata"`
+	CausationID     string                      `avro:"causation_id"`
+	Changes         map[string]changePairRecord `avro:"changes"`
+}
+
+type orderLineRecord struct {
+	SKU            string  `avro:"sku"`
+	Name           string  `avro:"name"`
+	Quantity       int64   `avro:"quantity"`
+	UnitPrice      float64 `avro:"unit_price"`
+	Subtotal       float64 `avro:"subtotal"`
+	UnitPriceMinor int64   `avro:"unit_price_minor"`
+	SubtotalMinor  int64   `avro:"subtotal_minor"`
+}
+
+// changePairRecord holds the JSON text of each value of a
+// messaging.ChangePair.
+type changePairRecord struct {
+	Old string `avro:"old"`
+	New string `avro:"new"`
+}
+
+type addressRecord struct {
+	Line1      string `avro:"line1"`
+	Line2      string `avro:"line2"`
+	City       string `avro:"city"`
+	Region     string `avro:"region"`
+	PostalCode string `avro:"postal_code"`
+	Country    string `avro:"country"`
+}
+
+func toRecord(evt messaging.OrderEvent) orderEventRecord {
+	rec := orderEventRecord{
+		EventID:       evt.EventID,
+		EventType:     evt.EventType,
+		SchemaVersion: evt.SchemaVersion,
+		OrderID:       evt.OrderID,
+		CustomerID:    evt.CustomerID,
+		Status:        evt.Status.String(),
+		OldStatus:     evt.OldStatus.String(),
+		NewStatus:     evt.NewStatus.String(),
+		CancelReason:  evt.CancelReason,
+		Total:         evt.Total,
+		TotalMinor:    evt.TotalMinor,
+		Currency:      evt.Currency,
+		Version:       int64(evt.Version),
+		Items:         []orderLineRecord{},
+		OccurredAt:    evt.OccurredAt,
+		Replayed:      evt.Replayed,
+		CorrelationID: evt.CorrelationID,
+		ChangedFields: append([]string{}, evt.ChangedFields...),
+		Seq:           evt.Seq,
+		Metadata:      maps.Clone(evt.Metadata),
+		CausationID:   evt.CausationID,
+		Changes:       map[string]changePairRecord{},
+	}
+	for field, change := range evt.Changes {
+		rec.Changes[field] = changePairRecord{Old: string(change.Old), New: string(change.New)}
+	}
+	for _, line := range evt.Items {
+		rec.Items = append(rec.Items, orderLineRecord{
+			SKU:            line.SKU,
+			Name:           line.Name,
+			Quantity:       int64(line.Quantity),
+			UnitPrice:      line.UnitPrice,
+			Subtotal:       line.Subtotal,
+			UnitPriceMinor: line.UnitPriceMinor,
+			SubtotalMinor:  line.SubtotalMinor,
+		})
+	}
+	if a := evt.ShippingAddress; a != nil {
+		rec.ShippingAddress = &addressRecord{
+			Line1:      a.Line1,
+			Line2:      a.Line2,
+			City:       a.City,
+			Region:     a.Region,
+			PostalCode: a.PostalCode,
+			Country:    a.Country,
+		}
+	}
+	return rec
+}
+
+func fromRecord(rec orderEventRecord) messaging.OrderEvent {
+	evt := messaging.OrderEvent{
+		EventID:       rec.EventID,
+		EventType:     rec.EventType,
+		SchemaVersion: rec.SchemaVersion,
+		OrderID:       rec.OrderID,
+		CustomerID:    rec.CustomerID,
+		Status:        domain.OrderStatus(rec.Status),
+		OldStatus:     domain.OrderStatus(rec.OldStatus),
+		NewStatus:     domain.OrderStatus(rec.NewStatus),
+		CancelReason:  rec.CancelReason,
+		Total:         rec.Total,
+		TotalMinor:    rec.TotalMinor,
+		Currency:      rec.Currency,
+		Version:       int(rec.Version),
+		OccurredAt:    rec.OccurredAt,
+		Replayed:      rec.Replayed,
+		CorrelationID: rec.CorrelationID,
+		Seq:           rec.Seq,
+		CausationID:   rec.CausationID,
+	}
+	if len(rec.ChangedFields) > 0 {
+		evt.ChangedFields = rec.ChangedFields
+	}
+	if len(rec.Metadata) > 0 {
+		evt.Metadata = rec.Metadata
+	}
+	if len(rec.Changes) > 0 {
+		evt.Changes = make(map[string]messaging.ChangePair, len(rec.Changes))
+		for field, change := range rec.Changes {
+			evt.Changes[field] = messaging.ChangePair{Old: rawJSON(change.Old), New: rawJSON(change.New)}
+		}
+	}
+	for _, line := range rec.Items {
+		evt.Items = append(evt.Items, messaging.OrderLineEvent{
+			SKU:            line.SKU,
+			Name:           line.Name,
+			Quantity:       int(line.Quantity),
+			UnitPrice:      line.UnitPrice,
+			Subtotal:       line.Subtotal,
+			UnitPriceMinor: line.UnitPriceMinor,
+			SubtotalMinor:  line.SubtotalMinor,
+		})
+	}
+	if a := rec.ShippingAddress; a != nil {
+		evt.ShippingAddress = &messaging.AddressEvent{
+			Line1:      a.Line1,
+			Line2:      a.Line2,
+			City:       a.City,
+			Region:     a.Region,
+			PostalCode: a.PostalCode,
+			Country:    a.Country,
+		}
+	}
+	return evt
+}
+
+// rawJSON returns the JSON text s, or nil if s is empty.
+func rawJSON(s string) json.RawMessage {
+	if s == "" {
+		return nil
+	}
+	return json.RawMessage(s)
+}
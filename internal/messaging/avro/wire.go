@@ -0,0 +1,33 @@
+package avro
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Confluent wire format: a zero magic byte, the 4-byte big-endian schema
+// ID, then the Avro-encoded body.
+const (
+	magicByte     = 0
+	wireHeaderLen = 5
+)
+
+// ErrInvalidWireFormat is returned when a payload lacks the Confluent
+// wire format prefix.
+var ErrInvalidWireFormat = errors.New("avro: payload is not in Confluent wire format")
+
+// appendWire prefixes body with the wire format header for schemaID.
+func appendWire(schemaID int, body []byte) []byte {
+	out := make([]byte, wireHeaderLen, wireHeaderLen+len(body))
+	out[0] = magicByte
+	binary.BigEndian.PutUint32(out[1:wireHeaderLen], uint32(schemaID)) // #nosec G115 -- registry IDs are positive int32
+	return append(out, body...)
+}
+
+// splitWire returns the schema ID and Avro body of a wire format payload.
+func splitWire(data []byte) (int, []byte, error) {
+	if len(data) < wireHeaderLen || data[0] != magicByte {
+		return 0, nil, ErrInvalidWireFormat
+	}
+	return int(binary.BigEndian.Uint32(data[1:wireHeaderLen])), data[wireHeaderLen:], nil
+}
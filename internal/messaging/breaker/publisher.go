@@ -0,0 +1,274 @@
+// Package breaker provides an EventPublisher decorator that stops calling a
+// failing broker for a while, so requests fail fast instead of each waiting
+// out a write timeout.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+// ErrCircuitOpen is returned without calling the wrapped publisher while
+// the circuit is open, or half-open with a probe already in flight. It is
+// returned alongside messaging.ErrBrokerUnavailable.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Settings configures when a Publisher's circuit opens and closes again.
+type Settings struct {
+	// FailureThreshold is how many consecutive failed publishes open the
+	// circuit. Zero or less uses 5.
+	FailureThreshold int
+	// Cooldown is how long the circuit stays open before a publish may
+	// probe the broker. Zero or less uses 30s.
+	Cooldown time.Duration
+	// Clock tells the time the circuit opened. Nil uses the system clock.
+	Clock messaging.Clock
+}
+
+// DefaultSettings returns settings that open after 5 consecutive failures
+// and probe again after 30s.
+func DefaultSettings() Settings {
+	return Settings{FailureThreshold: 5, Cooldown: 30 * time.Second}
+}
+
+// Status is the position of a Publisher's circuit.
+type Status int
+
+const (
+	// Closed passes every publish through.
+	Closed Status = iota
+	// Open fails every publish with ErrCircuitOpen.
+	Open
+	// HalfOpen lets one publish through to probe the broker.
+	HalfOpen
+)
+
+// String returns the status in lower case, for logs and metric labels.
+func (s Status) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half_open"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+// State is a snapshot of a Publisher's circuit and its counters. The
+// counters only grow, so they can be exported as metric counters.
+type State struct {
+	Status Status
+	// ConsecutiveFailures is the current run of failed publishes.
+	ConsecutiveFailures int
+	// Successes and Failures count publishes the wrapped publisher ran.
+	Successes int
+	Failures  int
+	// Rejected counts publishes failed with ErrCircuitOpen.
+	Rejected int
+	// Opened counts the times the circuit opened, reopens after a failed
+	// probe included.
+	Opened int
+}
+
+var _ messaging.EventPublisher = (*Publisher)(nil)
+
+// Publisher guards the wrapped EventPublisher with a circuit breaker.
+//
+// While closed, publishes pass through. FailureThreshold consecutive
+// failures open the circuit, and for Cooldown every publish fails at once
+// with ErrCircuitOpen. The first publish after that probes the broker
+// while the circuit is half-open: success closes the circuit, failure
+// opens it for another Cooldown. Other publishes during the probe are
+// rejected.
+//
+// Invalid events are not the broker's fault: a *messaging.ValidationError
+// or messaging.ErrSerialization neither counts as a failure nor as a
+// successful probe.
+type Publisher struct {
+	next      messaging.EventPublisher
+	threshold int
+	cooldown  time.Duration
+	clock     messaging.Clock
+
+	mu       sync.Mutex
+	state    State
+	openedAt time.Time
+	probing  bool
+}
+
+// Wrap returns p guarded by a circuit breaker configured by s.
+func Wrap(p messaging.EventPublisher, s Settings) *Publisher {
+	defaults := DefaultSettings()
+	if s.FailureThreshold <= 0 {
+		s.FailureThreshold = defaults.FailureThreshold
+	}
+	if s.Cooldown <= 0 {
+		s.Cooldown = defaults.Cooldown
+	}
+	if s.Clock == nil {
+		s.Clock = messaging.SystemClock{}
+	}
+	return &Publisher{next: p, threshold: s.FailureThreshold, cooldown: s.Cooldown, clock: s.Clock}
+}
+
+// Middleware returns Wrap with s as a messaging.Middleware. Each publisher
+// it wraps gets a circuit of its own. Use Wrap instead to keep the
+// *Publisher, for its State or for metrics.RegisterBreaker.
+func Middleware(s Settings) messaging.Middleware {
+	return func(p messaging.EventPublisher) messaging.EventPublisher { return Wrap(p, s) }
+}
+
+// State returns the circuit's status and counters. An open circuit whose
+// cooldown has passed reports HalfOpen, since its next publish probes.
+func (p *Publisher) State() State {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	st := p.state
+	if st.Status == Open && !p.clock.Now().Before(p.openedAt.Add(p.cooldown)) {
+		st.Status = HalfOpen
+	}
+	return st
+}
+
+// PublishOrderCreated publishes an order.created event unless the circuit
+// is open.
+func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	return p.do(func() error { return p.next.PublishOrderCreated(ctx, order) })
+}
+
+// PublishOrderUpdated publishes an order.updated event unless the circuit
+// is open.
+func (p *Publisher) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	return p.do(func() error { return p.next.PublishOrderUpdated(ctx, order, changes) })
+}
+
+// PublishOrderStatusChanged publishes an order.status_changed event unless
+// the circuit is open.
+func (p *Publisher) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
+	return p.do(func() error { return p.next.PublishOrderStatusChanged(ctx, order, oldStatus, newStatus) })
+}
+
+// PublishOrderCancelled publishes an order.cancelled event unless the
+// circuit is open.
+func (p *Publisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	return p.do(func() error { return p.next.PublishOrderCancelled(ctx, order, reason) })
+}
+
+// PublishOrderDeleted publishes an order.deleted event unless the circuit
+// is open.
+func (p *Publisher) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	return p.do(func() error { return p.next.PublishOrderDeleted(ctx, order) })
+}
+
+// PublishOrderExpired publishes an order.expired event unless the circuit
+// is open.
+func (p *Publisher) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	return p.do(func() error { return p.next.PublishOrderExpired(ctx, order) })
+}
+
+// PublishOrderSnapshot publishes an order.snapshot event unless the circuit
+// is open.
+func (p *Publisher) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	return p.do(func() error { return p.next.PublishOrderSnapshot(ctx, order) })
+}
+
+// Sender is implemented by publishers that publish pre-built events, such
+// as the Kafka publisher.
+type Sender interface {
+	Publish(ctx context.Context, evt messaging.OrderEvent) error
+}
+
+// Publish publishes evt, as the outbox relay does, unless the circuit is
+// open. The wrapped publisher must be a Sender.
+func (p *Publisher) Publish(ctx context.Context, evt messaging.OrderEvent) error {
+	sender, ok := p.next.(Sender)
+	if !ok {
+		return fmt.Errorf("breaker publish %s: wrapped %T has no Publish method", evt.EventType, p.next)
+	}
+	return p.do(func() error { return sender.Publish(ctx, evt) })
+}
+
+// do runs publish if the circuit allows it and records the outcome.
+func (p *Publisher) do(publish func() error) error {
+	probe, err := p.allow()
+	if err != nil {
+		return err
+	}
+	err = publish()
+	p.record(probe, err)
+	return err
+}
+
+// allow reports whether a publish may run and whether it is the probe of
+// a half-open circuit.
+func (p *Publisher) allow() (probe bool, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch p.state.Status {
+	case Closed:
+		return false, nil
+	case Open:
+		if p.clock.Now().Before(p.openedAt.Add(p.cooldown)) {
+			break
+		}
+		p.state.Status = HalfOpen
+		fallthrough
+	case HalfOpen:
+		if !p.probing {
+			p.probing = true
+			return true, nil
+		}
+	}
+	p.state.Rejected++
+	return false, fmt.Errorf("%w: %w", ErrCircuitOpen, messaging.ErrBrokerUnavailable)
+}
+
+// record updates the circuit with the outcome of a publish that ran.
+func (p *Publisher) record(probe bool, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if probe {
+		p.probing = false
+	}
+	switch {
+	case err != nil && brokerFault(err):
+		p.state.Failures++
+		p.state.ConsecutiveFailures++
+		if probe || (p.state.Status == Closed && p.state.ConsecutiveFailures >= p.threshold) {
+			p.open()
+		}
+	case err != nil:
+		// The event was bad; the broker's health is unknown
+		if probe {
+			p.state.Status = Open
+		}
+	default:
+		p.state.Successes++
+		p.state.ConsecutiveFailures = 0
+		if probe {
+			p.state.Status = Closed
+		}
+	}
+}
+
+// open opens the circuit for a cooldown starting now.
+func (p *Publisher) open() {
+	p.state.Status = Open
+	p.state.Opened++
+	p.openedAt = p.clock.Now()
+}
+
+// brokerFault reports whether err may be the broker's fault, rather than
+// an event that could never be sent.
+func brokerFault(err error) bool {
+	return !errors.Is(err, messaging.ErrValidation) && !errors.Is(err, messaging.ErrSerialization)
+}
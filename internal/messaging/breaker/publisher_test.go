@@ -0,0 +1,264 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errBroker = errors.New("broker unavailable")
+
+// fakeClock is a messaging.Clock moved forward by hand.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// broker is a wrapped publisher that fails while err is set.
+type broker struct {
+	err   error
+	calls int
+}
+
+func (b *broker) publisher() *mocks.EventPublisherMock {
+	return &mocks.EventPublisherMock{
+		PublishOrderCreatedFunc: func(context.Context, *domain.Order) error {
+			b.calls++
+			return b.err
+		},
+	}
+}
+
+func newBreaker(t *testing.T) (*Publisher, *broker, *fakeClock) {
+	t.Helper()
+	b := &broker{}
+	clock := &fakeClock{now: time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)}
+	pub := Wrap(b.publisher(), Settings{FailureThreshold: 3, Cooldown: time.Minute, Clock: clock})
+	return pub, b, clock
+}
+
+func publish(pub *Publisher) error {
+	return pub.PublishOrderCreated(context.Background(), &domain.Order{})
+}
+
+func TestPublisher_ConsecutiveFailures_OpenCircuit(t *testing.T) {
+	pub, b, _ := newBreaker(t)
+	b.err = errBroker
+
+	for i := 0; i < 2; i++ {
+		assert.ErrorIs(t, publish(pub), errBroker)
+	}
+	assert.Equal(t, Closed, pub.State().Status, "below the threshold")
+
+	assert.ErrorIs(t, publish(pub), errBroker)
+	assert.Equal(t, State{Status: Open, ConsecutiveFailures: 3, Failures: 3, Opened: 1}, pub.State())
+}
+
+func TestPublisher_SuccessResetsFailureRun(t *testing.T) {
+	pub, b, _ := newBreaker(t)
+
+	for i := 0; i < 5; i++ {
+		b.err = errBroker
+		require.Error(t, publish(pub))
+		require.Error(t, publish(pub))
+		b.err = nil
+		require.NoError(t, publish(pub))
+	}
+
+	assert.Equal(t, State{Status: Closed, Successes: 5, Failures: 10}, pub.State())
+}
+
+func TestPublisher_Open_FailsFastUntilCooldown(t *testing.T) {
+	pub, b, clock := newBreaker(t)
+	b.err = errBroker
+	for i := 0; i < 3; i++ {
+		require.Error(t, publish(pub))
+	}
+	b.err = nil
+
+	clock.advance(59 * time.Second)
+	err := publish(pub)
+
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.ErrorIs(t, err, messaging.ErrBrokerUnavailable)
+	assert.Equal(t, 3, b.calls, "the broker is not called")
+	assert.Equal(t, 1, pub.State().Rejected)
+	assert.Equal(t, Open, pub.State().Status)
+
+	clock.advance(time.Second)
+	assert.Equal(t, HalfOpen, pub.State().Status, "the next publish probes")
+}
+
+func TestPublisher_HalfOpen_ProbeDecidesCircuit(t *testing.T) {
+	tests := []struct {
+		name       string
+		probeErr   error
+		wantStatus Status
+		wantOpened int
+	}{
+		{"success_closes", nil, Closed, 1},
+		{"failure_reopens", errBroker, Open, 2},
+		{"invalid_event_stays_half_open", fmt.Errorf("publish: %w", messaging.ErrValidation), HalfOpen, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub, b, clock := newBreaker(t)
+			b.err = errBroker
+			for i := 0; i < 3; i++ {
+				require.Error(t, publish(pub))
+			}
+			clock.advance(time.Minute)
+
+			b.err = tt.probeErr
+			assert.Equal(t, tt.probeErr, publish(pub))
+
+			assert.Equal(t, 4, b.calls, "the probe reached the broker")
+			assert.Equal(t, tt.wantStatus, pub.State().Status)
+			assert.Equal(t, tt.wantOpened, pub.State().Opened)
+		})
+	}
+}
+
+func TestPublisher_Reopened_WaitsAFullCooldown(t *testing.T) {
+	pub, b, clock := newBreaker(t)
+	b.err = errBroker
+	for i := 0; i < 3; i++ {
+		require.Error(t, publish(pub))
+	}
+	clock.advance(time.Minute)
+	require.ErrorIs(t, publish(pub), errBroker, "failed probe")
+
+	clock.advance(59 * time.Second)
+	assert.ErrorIs(t, publish(pub), ErrCircuitOpen)
+	clock.advance(time.Second)
+	b.err = nil
+	assert.NoError(t, publish(pub))
+	assert.Equal(t, Closed, pub.State().Status)
+}
+
+func TestPublisher_HalfOpen_RejectsPublishesDuringProbe(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	probing := make(chan struct{})
+	release := make(chan struct{})
+	calls := 0
+	next := &mocks.EventPublisherMock{
+		PublishOrderCreatedFunc: func(context.Context, *domain.Order) error {
+			calls++
+			switch calls {
+			case 1:
+				return errBroker
+			case 2:
+				close(probing)
+				<-release
+			}
+			return nil
+		},
+	}
+	pub := Wrap(next, Settings{FailureThreshold: 1, Cooldown: time.Second, Clock: clock})
+	require.Error(t, publish(pub))
+	clock.advance(time.Second)
+
+	done := make(chan error)
+	go func() { done <- publish(pub) }()
+	<-probing
+
+	assert.ErrorIs(t, publish(pub), ErrCircuitOpen, "one probe at a time")
+	close(release)
+	require.NoError(t, <-done)
+	assert.NoError(t, publish(pub), "closed after the probe")
+}
+
+func TestPublisher_InvalidEvent_DoesNotOpenCircuit(t *testing.T) {
+	b := &broker{err: &messaging.ValidationError{Field: "order_id"}}
+	pub := Wrap(b.publisher(), Settings{FailureThreshold: 1})
+
+	for i := 0; i < 3; i++ {
+		require.Error(t, publish(pub))
+	}
+
+	assert.Equal(t, Closed, pub.State().Status)
+	assert.Equal(t, 3, b.calls)
+}
+
+func TestWrap_ZeroSettings_UsesDefaults(t *testing.T) {
+	pub := Wrap(&mocks.EventPublisherMock{}, Settings{})
+
+	assert.Equal(t, 5, pub.threshold)
+	assert.Equal(t, 30*time.Second, pub.cooldown)
+	assert.Equal(t, "half_open", HalfOpen.String())
+}
+
+func TestPublisher_FullCycle_ClosedOpenHalfOpenClosed(t *testing.T) {
+	pub, b, clock := newBreaker(t)
+	var seen []Status
+	observe := func() { seen = append(seen, pub.State().Status) }
+
+	observe()
+	b.err = errBroker
+	for i := 0; i < 3; i++ {
+		require.ErrorIs(t, publish(pub), errBroker)
+	}
+	observe()
+	require.ErrorIs(t, publish(pub), ErrCircuitOpen)
+	clock.advance(time.Minute)
+	observe()
+	b.err = nil
+	require.NoError(t, publish(pub))
+	observe()
+
+	assert.Equal(t, []Status{Closed, Open, HalfOpen, Closed}, seen)
+	assert.Equal(t, State{Status: Closed, Successes: 1, Failures: 3, Rejected: 1, Opened: 1}, pub.State())
+}
+
+// sender is a Sender recording the events it is given.
+type sender struct {
+	mocks.EventPublisherMock
+	err    error
+	events []messaging.OrderEvent
+}
+
+func (s *sender) Publish(_ context.Context, evt messaging.OrderEvent) error {
+	s.events = append(s.events, evt)
+	return s.err
+}
+
+func TestPublisher_Publish_GuardsSender(t *testing.T) {
+	s := &sender{err: errBroker}
+	pub := Wrap(s, Settings{FailureThreshold: 1, Cooldown: time.Minute})
+	evt := messaging.NewOrderEvent(messaging.EventOrderCreated, &domain.Order{})
+
+	require.ErrorIs(t, pub.Publish(context.Background(), evt), errBroker)
+	assert.ErrorIs(t, pub.Publish(context.Background(), evt), ErrCircuitOpen)
+	assert.Len(t, s.events, 1, "the open circuit does not reach the sender")
+}
+
+func TestPublisher_Publish_WrappedNotSender_ReturnsError(t *testing.T) {
+	pub := Wrap(&mocks.EventPublisherMock{}, DefaultSettings())
+
+	err := pub.Publish(context.Background(), messaging.NewOrderEvent(messaging.EventOrderCreated, &domain.Order{}))
+
+	assert.ErrorContains(t, err, "has no Publish method")
+	assert.Equal(t, Closed, pub.State().Status)
+}
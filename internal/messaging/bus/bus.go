@@ -0,0 +1,226 @@
+// Package bus provides an in-process EventPublisher that dispatches order
+// events to subscribers in the same process, for deployments that run
+// without Kafka.
+package bus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+// Wildcard subscribes a handler to events of every type.
+const Wildcard = "*"
+
+var _ messaging.EventPublisher = (*Bus)(nil)
+
+// Handler reacts to a published event.
+type Handler func(ctx context.Context, evt messaging.OrderEvent) error
+
+// Bus implements messaging.EventPublisher by calling the subscribed
+// handlers synchronously, on the publishing goroutine, before the publish
+// returns.
+//
+// Every handler subscribed to the event's type runs, in subscription
+// order, followed by the Wildcard handlers. A failing or panicking handler
+// does not stop the others: the publish returns the errors of all failing
+// handlers combined with errors.Join. An event with no subscribers is
+// dropped.
+//
+// A Bus is safe for concurrent use, and handlers may subscribe further
+// handlers; those receive the next event published. The zero value is
+// ready to use.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]*subscription
+	streams  map[*stream]struct{} // Open Streams, ended by Close
+	closed   bool
+}
+
+// subscription is a subscribed handler, by pointer so it can be removed.
+type subscription struct {
+	h Handler
+}
+
+// New returns a Bus with no subscribers.
+func New() *Bus {
+	return &Bus{}
+}
+
+// Subscribe calls h for every published event of eventType, or of every
+// type if eventType is Wildcard, until the returned unsubscribe function
+// is called. A dispatch already under way may still call h once.
+func (b *Bus) Subscribe(eventType string, h Handler) (unsubscribe func()) {
+	sub := &subscription{h: h}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.handlers == nil {
+		b.handlers = map[string][]*subscription{}
+	}
+	b.handlers[eventType] = append(b.handlers[eventType], sub)
+	return func() { b.remove(eventType, sub) }
+}
+
+func (b *Bus) remove(eventType string, sub *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subs := slices.DeleteFunc(b.handlers[eventType], func(s *subscription) bool { return s == sub })
+	if len(subs) == 0 {
+		delete(b.handlers, eventType)
+		return
+	}
+	b.handlers[eventType] = subs
+}
+
+// Stream returns a channel receiving every event published until ctx is
+// done or the Bus is closed, when the subscription is removed and the
+// channel closed. Up to buffer events are queued for a slow reader;
+// further events are dropped for that reader rather than holding up the
+// publisher.
+func (b *Bus) Stream(ctx context.Context, buffer int) <-chan messaging.OrderEvent {
+	s := &stream{ch: make(chan messaging.OrderEvent, buffer)}
+	unsubscribe := b.Subscribe(Wildcard, s.send)
+	s.end = sync.OnceFunc(func() {
+		unsubscribe()
+		b.mu.Lock()
+		delete(b.streams, s)
+		b.mu.Unlock()
+		s.close()
+	})
+
+	b.mu.Lock()
+	closed := b.closed
+	if !closed {
+		if b.streams == nil {
+			b.streams = map[*stream]struct{}{}
+		}
+		b.streams[s] = struct{}{}
+	}
+	b.mu.Unlock()
+	if closed {
+		s.end()
+		return s.ch
+	}
+	context.AfterFunc(ctx, s.end)
+	return s.ch
+}
+
+// Close ends every open Stream, closing its channel, so readers such as
+// long-lived HTTP responses finish when the server shuts down. Streams
+// opened after Close are closed at once. Subscribed handlers keep
+// receiving events.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	b.closed = true
+	streams := make([]*stream, 0, len(b.streams))
+	for s := range b.streams {
+		streams = append(streams, s)
+	}
+	b.mu.Unlock()
+	for _, s := range streams {
+		s.end()
+	}
+}
+
+// stream feeds a Stream channel, guarding sends against its closing.
+type stream struct {
+	mu     sync.Mutex
+	closed bool
+	ch     chan messaging.OrderEvent
+	end    func() // Unsubscribes and closes ch, once
+}
+
+func (s *stream) send(_ context.Context, evt messaging.OrderEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	select {
+	case s.ch <- evt:
+	default: // Reader is behind; drop rather than block publishing
+	}
+	return nil
+}
+
+func (s *stream) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	close(s.ch)
+}
+
+// PublishOrderCreated dispatches an order.created event.
+func (b *Bus) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	return b.Publish(ctx, messaging.NewOrderEvent(messaging.EventOrderCreated, order))
+}
+
+// PublishOrderUpdated dispatches an order.updated event.
+func (b *Bus) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	return b.Publish(ctx, messaging.NewUpdatedEvent(order, changes))
+}
+
+// PublishOrderStatusChanged dispatches an order.status_changed event.
+func (b *Bus) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
+	return b.Publish(ctx, messaging.NewStatusChangedEvent(order, oldStatus, newStatus))
+}
+
+// PublishOrderCancelled dispatches an order.cancelled event.
+func (b *Bus) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	return b.Publish(ctx, messaging.NewCancelledEvent(order, reason))
+}
+
+// PublishOrderDeleted dispatches an order.deleted event.
+func (b *Bus) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	return b.Publish(ctx, messaging.NewOrderEvent(messaging.EventOrderDeleted, order))
+}
+
+// PublishOrderExpired dispatches an order.expired event.
+func (b *Bus) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	return b.Publish(ctx, messaging.NewOrderEvent(messaging.EventOrderExpired, order))
+}
+
+// PublishOrderSnapshot dispatches an order.snapshot event.
+func (b *Bus) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	return b.Publish(ctx, messaging.NewSnapshotEvent(order))
+}
+
+// Publish dispatches a pre-built event to its subscribers.
+func (b *Bus) Publish(ctx context.Context, evt messaging.OrderEvent) error {
+	evt = messaging.Correlate(ctx, evt)
+	var errs []error
+	for i, sub := range b.subscribers(evt.EventType) {
+		if err := call(ctx, sub.h, evt); err != nil {
+			errs = append(errs, fmt.Errorf("bus %s subscriber %d: %w", evt.EventType, i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// subscribers returns the handlers for eventType followed by the Wildcard
+// handlers, copied so they run without holding the lock.
+func (b *Bus) subscribers(eventType string) []*subscription {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	out := make([]*subscription, 0, len(b.handlers[eventType])+len(b.handlers[Wildcard]))
+	out = append(out, b.handlers[eventType]...)
+	if eventType != Wildcard {
+		out = append(out, b.handlers[Wildcard]...)
+	}
+	return out
+}
+
+// call runs h, returning a panic as an error so later subscribers still run.
+func call(ctx context.Context, h Handler, evt messaging.OrderEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return h(ctx, evt)
+}
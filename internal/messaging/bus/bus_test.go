@@ -0,0 +1,169 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOrder() *domain.Order {
+	return &domain.Order{
+		ID:         uuid.New(),
+		CustomerID: "cust-123",
+		Status:     domain.OrderStatusPending,
+		Total:      domain.Money{Amount: 2100, Currency: "USD"},
+		Version:    1,
+	}
+}
+
+// recorder returns a handler that appends the type of each event it
+// receives, prefixed with name, to log.
+func recorder(name string, log *[]string) Handler {
+	return func(_ context.Context, evt messaging.OrderEvent) error {
+		*log = append(*log, name+":"+evt.EventType)
+		return nil
+	}
+}
+
+func TestBus_Subscribe_DispatchesByEventType(t *testing.T) {
+	b := New()
+	var log []string
+	b.Subscribe(messaging.EventOrderCreated, recorder("created", &log))
+	b.Subscribe(messaging.EventOrderCancelled, recorder("cancelled", &log))
+	ctx := context.Background()
+	order := newTestOrder()
+
+	require.NoError(t, b.PublishOrderCreated(ctx, order))
+	require.NoError(t, b.PublishOrderCancelled(ctx, order, "customer request"))
+	require.NoError(t, b.PublishOrderDeleted(ctx, order), "no subscribers")
+
+	assert.Equal(t, []string{"created:" + messaging.EventOrderCreated, "cancelled:" + messaging.EventOrderCancelled}, log)
+}
+
+func TestBus_Wildcard_ReceivesEveryTypeAfterTypedHandlers(t *testing.T) {
+	b := New()
+	var log []string
+	b.Subscribe(Wildcard, recorder("all", &log))
+	b.Subscribe(messaging.EventOrderCreated, recorder("created", &log))
+	ctx := context.Background()
+	order := newTestOrder()
+
+	require.NoError(t, b.PublishOrderCreated(ctx, order))
+	require.NoError(t, b.PublishOrderStatusChanged(ctx, order, domain.OrderStatusPending, domain.OrderStatusConfirmed))
+
+	assert.Equal(t, []string{
+		"created:" + messaging.EventOrderCreated,
+		"all:" + messaging.EventOrderCreated,
+		"all:" + messaging.EventOrderStatusChanged,
+	}, log)
+}
+
+func TestBus_FailingSubscribers_DoNotBlockOthersAndAreAggregated(t *testing.T) {
+	b := New()
+	errFirst := errors.New("projection unavailable")
+	errSecond := errors.New("mailer unavailable")
+	var log []string
+	b.Subscribe(messaging.EventOrderCreated, func(context.Context, messaging.OrderEvent) error { return errFirst })
+	b.Subscribe(messaging.EventOrderCreated, func(context.Context, messaging.OrderEvent) error { panic("boom") })
+	b.Subscribe(messaging.EventOrderCreated, recorder("created", &log))
+	b.Subscribe(Wildcard, func(context.Context, messaging.OrderEvent) error { return errSecond })
+
+	err := b.PublishOrderCreated(context.Background(), newTestOrder())
+
+	assert.ErrorIs(t, err, errFirst)
+	assert.ErrorIs(t, err, errSecond)
+	assert.ErrorContains(t, err, "panic: boom")
+	assert.Equal(t, []string{"created:" + messaging.EventOrderCreated}, log, "later subscribers still run")
+}
+
+func TestBus_Publish_CarriesEventAndCorrelation(t *testing.T) {
+	b := New()
+	var got messaging.OrderEvent
+	b.Subscribe(messaging.EventOrderUpdated, func(_ context.Context, evt messaging.OrderEvent) error {
+		got = evt
+		return nil
+	})
+	order := newTestOrder()
+	ctx := messaging.WithRequestID(context.Background(), "req-abc")
+
+	require.NoError(t, b.PublishOrderUpdated(ctx, order, map[string]messaging.ChangePair{"items": {}}))
+
+	assert.Equal(t, order.ID.String(), got.OrderID)
+	assert.Equal(t, []string{"items"}, got.ChangedFields)
+	assert.Equal(t, "req-abc", got.CorrelationID)
+}
+
+func TestBus_SubscribeFromHandler_DoesNotDeadlock(t *testing.T) {
+	b := New()
+	var log []string
+	b.Subscribe(messaging.EventOrderCreated, func(context.Context, messaging.OrderEvent) error {
+		b.Subscribe(messaging.EventOrderCreated, recorder("late", &log))
+		return nil
+	})
+
+	require.NoError(t, b.PublishOrderCreated(context.Background(), newTestOrder()))
+	assert.Empty(t, log, "a handler subscribed mid-dispatch waits for the next event")
+}
+
+func TestBus_Unsubscribe_StopsDelivery(t *testing.T) {
+	b := New()
+	var log []string
+	unsubscribe := b.Subscribe(messaging.EventOrderCreated, recorder("created", &log))
+	b.Subscribe(messaging.EventOrderCreated, recorder("other", &log))
+	ctx := context.Background()
+
+	require.NoError(t, b.PublishOrderCreated(ctx, newTestOrder()))
+	unsubscribe()
+	unsubscribe()
+	require.NoError(t, b.PublishOrderCreated(ctx, newTestOrder()))
+
+	assert.Equal(t, []string{
+		"created:" + messaging.EventOrderCreated,
+		"other:" + messaging.EventOrderCreated,
+		"other:" + messaging.EventOrderCreated,
+	}, log)
+}
+
+func TestBus_Stream_DeliversUntilContextDone(t *testing.T) {
+	b := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	events := b.Stream(ctx, 1)
+	order := newTestOrder()
+
+	require.NoError(t, b.PublishOrderCreated(context.Background(), order))
+	require.NoError(t, b.PublishOrderDeleted(context.Background(), order), "dropped: the buffer is full")
+
+	evt := <-events
+	assert.Equal(t, messaging.EventOrderCreated, evt.EventType)
+	assert.Equal(t, order.ID.String(), evt.OrderID)
+
+	cancel()
+	_, open := <-events
+	assert.False(t, open, "closed once ctx is done")
+	assert.Eventually(t, func() bool { return len(b.subscribers(messaging.EventOrderCreated)) == 0 },
+		time.Second, time.Millisecond, "the subscription is removed")
+	assert.NoError(t, b.PublishOrderCreated(context.Background(), order), "publishing after close is safe")
+}
+
+func TestBus_Close_EndsOpenAndLaterStreams(t *testing.T) {
+	b := New()
+	var log []string
+	b.Subscribe(messaging.EventOrderCreated, recorder("created", &log))
+	open := b.Stream(context.Background(), 1)
+
+	b.Close()
+
+	_, ok := <-open
+	assert.False(t, ok, "open streams are closed")
+	_, ok = <-b.Stream(context.Background(), 1)
+	assert.False(t, ok, "later streams are closed at once")
+	require.NoError(t, b.PublishOrderCreated(context.Background(), newTestOrder()))
+	assert.Len(t, log, 1, "handlers keep receiving events")
+}
@@ -0,0 +1,37 @@
+package messaging
+
+import "slices"
+
+// Middleware decorates an EventPublisher, as the Wrap functions of the
+// decorator packages do. Each of them has a Middleware constructor, such
+// as retry.Middleware, for use with Chain.
+type Middleware func(EventPublisher) EventPublisher
+
+// Chain returns base decorated with mws, the first of them outermost: a
+// publish passes through mws in order before reaching base, and returns
+// through them in reverse.
+//
+// The order decides what each decorator sees. Outermost to innermost, the
+// recommended one is:
+//
+//   - otel, so the span covers the whole publish, retries included, and
+//     ends up in the message headers
+//   - metrics, so every publish the service attempts is counted and timed
+//   - filter, sample and toggle, so dropped events spend no tokens or
+//     attempts
+//   - ordered, so a stale event is discarded before it is limited
+//   - fallback, so an event goes to the outbox only once the decorators
+//     below it have given up on the broker
+//   - ratelimit, so one token is taken per event rather than per attempt
+//   - breaker, so an open circuit fails at once, without retrying
+//   - retry, innermost, so the breaker counts a publish that exhausted its
+//     retries as one failure
+//
+// Chain(base) with no middleware returns base.
+func Chain(base EventPublisher, mws ...Middleware) EventPublisher {
+	p := base
+	for _, mw := range slices.Backward(mws) {
+		p = mw(p)
+	}
+	return p
+}
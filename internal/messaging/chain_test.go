@@ -0,0 +1,79 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callLog records the steps of a publish through a chain.
+type callLog struct{ steps []string }
+
+// basePublisher logs its order.created publishes and fails them with err.
+// Its other methods are not used.
+type basePublisher struct {
+	EventPublisher
+	log *callLog
+	err error
+}
+
+func (b basePublisher) PublishOrderCreated(context.Context, *domain.Order) error {
+	b.log.steps = append(b.log.steps, "base")
+	return b.err
+}
+
+// recording returns a middleware logging name before and after the
+// order.created publishes it passes on.
+func recording(log *callLog, name string) Middleware {
+	return func(next EventPublisher) EventPublisher {
+		return recorder{EventPublisher: next, log: log, name: name}
+	}
+}
+
+type recorder struct {
+	EventPublisher
+	log  *callLog
+	name string
+}
+
+func (r recorder) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	r.log.steps = append(r.log.steps, r.name+" before")
+	err := r.EventPublisher.PublishOrderCreated(ctx, order)
+	r.log.steps = append(r.log.steps, r.name+" after")
+	return err
+}
+
+func TestChain_InvokesMiddlewaresInOrderAroundBase(t *testing.T) {
+	log := &callLog{}
+	pub := Chain(basePublisher{log: log},
+		recording(log, "first"), recording(log, "second"), recording(log, "third"))
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), &domain.Order{}))
+
+	assert.Equal(t, []string{
+		"first before", "second before", "third before",
+		"base",
+		"third after", "second after", "first after",
+	}, log.steps)
+}
+
+func TestChain_ReturnsBaseError(t *testing.T) {
+	errBroker := errors.New("broker down")
+	log := &callLog{}
+	pub := Chain(basePublisher{log: log, err: errBroker}, recording(log, "only"))
+
+	err := pub.PublishOrderCreated(context.Background(), &domain.Order{})
+
+	assert.ErrorIs(t, err, errBroker)
+	assert.Equal(t, []string{"only before", "base", "only after"}, log.steps)
+}
+
+func TestChain_NoMiddleware_ReturnsBase(t *testing.T) {
+	base := basePublisher{log: &callLog{}}
+
+	assert.Equal(t, EventPublisher(base), Chain(base))
+}
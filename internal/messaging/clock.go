@@ -0,0 +1,16 @@
+package messaging
+
+import "time"
+
+// Clock tells publishers the time to stamp on events, and the service and
+// its workers the time of the changes they make. Tests pass a fixed clock
+// to assert exact times.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the real Clock, reading time.Now.
+type SystemClock struct{}
+
+// Now returns the current time.
+func (SystemClock) Now() time.Time { return time.Now() }
@@ -0,0 +1,90 @@
+package messaging
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EnvelopeFormat selects how an OrderEvent is serialized on the wire.
+type EnvelopeFormat int
+
+const (
+	// FormatNative serializes the OrderEvent as-is. This is the default.
+	FormatNative EnvelopeFormat = iota
+	// FormatCloudEvents wraps the OrderEvent in a CloudEvents 1.0
+	// structured-mode JSON envelope.
+	FormatCloudEvents
+)
+
+// CloudEvents attribute values used by FormatCloudEvents.
+const (
+	CloudEventsSpecVersion = "1.0"
+	CloudEventsSource      = "/ordersvc"
+	CloudEventsTypePrefix  = "io.ordersvc."
+	CloudEventsContentType = "application/cloudevents+json"
+)
+
+// CloudEvent is a CloudEvents 1.0 structured-mode envelope whose data is an
+// OrderEvent.
+type CloudEvent struct {
+	SpecVersion     string     `json:"specversion"`
+	Type            string     `json:"type"`
+	Source          string     `json:"source"`
+	ID              string     `json:"id"`
+	Time            time.Time  `json:"time"`
+	DataContentType string     `json:"datacontenttype"`
+	Data            OrderEvent `json:"data"`
+}
+
+// NewCloudEvent wraps evt in a CloudEvents envelope. The id is the event's
+// EventID, so redelivering the same domain event keeps the same id.
+func NewCloudEvent(evt OrderEvent) CloudEvent {
+	return CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		Type:            CloudEventType(evt.EventType),
+		Source:          CloudEventsSource,
+		ID:              evt.EventID,
+		Time:            evt.OccurredAt,
+		DataContentType: "application/json",
+		Data:            evt,
+	}
+}
+
+// CloudEventType maps an Event* constant to its CloudEvents type,
+// e.g. "order.created" becomes "io.ordersvc.order.created".
+func CloudEventType(eventType string) string {
+	return CloudEventsTypePrefix + eventType
+}
+
+// Serializer returns the serializer implementing format f.
+// Unknown formats fall back to FormatNative.
+func (f EnvelopeFormat) Serializer() Serializer {
+	if f == FormatCloudEvents {
+		return CloudEventsSerializer{}
+	}
+	return JSONSerializer{}
+}
+
+// CloudEventsSerializer encodes events as CloudEvents structured-mode JSON.
+type CloudEventsSerializer struct{}
+
+// Marshal wraps evt in a CloudEvents envelope and encodes it as JSON.
+func (CloudEventsSerializer) Marshal(evt OrderEvent) ([]byte, error) {
+	return json.Marshal(NewCloudEvent(evt))
+}
+
+// Unmarshal decodes a CloudEvents envelope and returns its data.
+func (CloudEventsSerializer) Unmarshal(data []byte) (OrderEvent, error) {
+	var ce CloudEvent
+	if err := json.Unmarshal(data, &ce); err != nil {
+		return OrderEvent{}, err
+	}
+	if ce.SpecVersion != CloudEventsSpecVersion {
+		return OrderEvent{}, fmt.Errorf("unsupported cloudevents specversion %q", ce.SpecVersion)
+	}
+	return ce.Data, nil
+}
+
+// ContentType returns CloudEventsContentType.
+func (CloudEventsSerializer) ContentType() string { return CloudEventsContentType }
@@ -0,0 +1,118 @@
+// Package cloudevents publishes order events to Kafka as CloudEvents 1.0
+// structured-mode JSON envelopes, for consumers that standardize on
+// CloudEvents.
+package cloudevents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+// ContentType is set in the content-type header of published messages.
+const ContentType = messaging.CloudEventsContentType
+
+// Writer is the subset of *kafka.Writer used by Publisher. The writer must
+// have its Topic set.
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	io.Closer
+}
+
+var _ messaging.EventPublisher = (*Publisher)(nil)
+
+// Publisher implements messaging.EventPublisher, writing each event as a
+// CloudEvent keyed by order ID. The envelope's id is the EventID, its type
+// the event type under messaging.CloudEventsTypePrefix (for example
+// "io.ordersvc.order.created"), its time the OccurredAt, and its data the
+// OrderEvent itself.
+type Publisher struct {
+	source string
+	writer Writer
+}
+
+// New creates a publisher that writes events with inner, setting source as
+// the CloudEvents source attribute. An empty source uses
+// messaging.CloudEventsSource.
+func New(source string, inner Writer) *Publisher {
+	if source == "" {
+		source = messaging.CloudEventsSource
+	}
+	return &Publisher{source: source, writer: inner}
+}
+
+// PublishOrderCreated publishes an order.created event.
+func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, messaging.NewOrderEvent(messaging.EventOrderCreated, order))
+}
+
+// PublishOrderUpdated publishes an order.updated event.
+func (p *Publisher) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	return p.Publish(ctx, messaging.NewUpdatedEvent(order, changes))
+}
+
+// PublishOrderStatusChanged publishes an order.status_changed event.
+func (p *Publisher) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
+	return p.Publish(ctx, messaging.NewStatusChangedEvent(order, oldStatus, newStatus))
+}
+
+// PublishOrderCancelled publishes an order.cancelled event.
+func (p *Publisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	return p.Publish(ctx, messaging.NewCancelledEvent(order, reason))
+}
+
+// PublishOrderDeleted publishes an order.deleted event.
+func (p *Publisher) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, messaging.NewOrderEvent(messaging.EventOrderDeleted, order))
+}
+
+// PublishOrderExpired publishes an order.expired event.
+func (p *Publisher) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, messaging.NewOrderEvent(messaging.EventOrderExpired, order))
+}
+
+// PublishOrderSnapshot publishes an order.snapshot event.
+func (p *Publisher) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, messaging.NewSnapshotEvent(order))
+}
+
+// Publish wraps evt in a CloudEvent and writes it, keyed by its order ID.
+func (p *Publisher) Publish(ctx context.Context, evt messaging.OrderEvent) error {
+	evt = messaging.Correlate(ctx, evt)
+	if err := evt.Validate(); err != nil {
+		return fmt.Errorf("cloudevents publish %s: %w", evt.EventType, err)
+	}
+	value, err := json.Marshal(p.envelope(evt))
+	if err != nil {
+		return fmt.Errorf("cloudevents marshal %s: %w", evt.EventType, err)
+	}
+	var headers messaging.Headers
+	headers.SetEventID(evt.EventID)
+	headers.SetContentType(ContentType)
+	err = p.writer.WriteMessages(ctx, kafka.Message{
+		Key:     []byte(evt.OrderID),
+		Value:   value,
+		Headers: headers.Kafka(),
+	})
+	if err != nil {
+		return fmt.Errorf("kafka write %s: %w", evt.EventType, err)
+	}
+	return nil
+}
+
+// envelope returns evt as a CloudEvent from the publisher's source.
+func (p *Publisher) envelope(evt messaging.OrderEvent) messaging.CloudEvent {
+	ce := messaging.NewCloudEvent(evt)
+	ce.Source = p.source
+	return ce
+}
+
+// Close closes the underlying writer.
+func (p *Publisher) Close() error {
+	return p.writer.Close()
+}
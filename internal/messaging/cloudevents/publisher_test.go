@@ -0,0 +1,168 @@
+package cloudevents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	kafkapub "github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureWriter records messages instead of sending them to Kafka.
+type captureWriter struct {
+	messages []kafka.Message
+	err      error
+}
+
+func (c *captureWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.messages = append(c.messages, msgs...)
+	return nil
+}
+
+func (c *captureWriter) Close() error { return nil }
+
+func newTestOrder() *domain.Order {
+	return &domain.Order{
+		ID:         uuid.New(),
+		CustomerID: "cust-123",
+		Items: []domain.OrderItem{
+			{ID: uuid.New(), ProductID: "p-1", Name: "Widget", Quantity: 2, Price: domain.Money{Amount: 1050}, Subtotal: domain.Money{Amount: 2100}},
+		},
+		Status:  domain.OrderStatusPending,
+		Total:   domain.Money{Amount: 2100, Currency: "EUR"},
+		Version: 2,
+	}
+}
+
+func headers(msg kafka.Message) map[string]string {
+	out := map[string]string{}
+	for _, h := range msg.Headers {
+		out[h.Key] = string(h.Value)
+	}
+	return out
+}
+
+func TestPublisher_Publish_WritesStructuredEnvelope(t *testing.T) {
+	w := &captureWriter{}
+	pub := New("/ordersvc/eu-west-1", w)
+	order := newTestOrder()
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+
+	require.Len(t, w.messages, 1)
+	msg := w.messages[0]
+	assert.Equal(t, order.ID.String(), string(msg.Key))
+
+	var envelope map[string]any
+	require.NoError(t, json.Unmarshal(msg.Value, &envelope))
+	assert.Equal(t, "1.0", envelope["specversion"])
+	assert.Equal(t, "io.ordersvc.order.created", envelope["type"])
+	assert.Equal(t, "/ordersvc/eu-west-1", envelope["source"])
+	assert.Equal(t, "application/json", envelope["datacontenttype"])
+	assert.NotEmpty(t, envelope["id"])
+	require.IsType(t, map[string]any{}, envelope["data"])
+	assert.Equal(t, order.ID.String(), envelope["data"].(map[string]any)["order_id"])
+
+	var ce messaging.CloudEvent
+	require.NoError(t, json.Unmarshal(msg.Value, &ce))
+	assert.Equal(t, ce.Data.EventID, ce.ID, "id is the event ID")
+	assert.True(t, ce.Data.OccurredAt.Equal(ce.Time), "time is when the event occurred")
+
+	h := headers(msg)
+	assert.Equal(t, "application/cloudevents+json", h[kafkapub.HeaderContentType])
+	assert.Equal(t, ce.ID, h[kafkapub.HeaderEventID])
+}
+
+func TestPublisher_EmptySource_UsesDefault(t *testing.T) {
+	w := &captureWriter{}
+	require.NoError(t, New("", w).PublishOrderUpdated(context.Background(), newTestOrder(), nil))
+
+	var ce messaging.CloudEvent
+	require.NoError(t, json.Unmarshal(w.messages[0].Value, &ce))
+	assert.Equal(t, messaging.CloudEventsSource, ce.Source)
+}
+
+func TestPublisher_Data_RoundTripsToOrderEvent(t *testing.T) {
+	order := newTestOrder()
+	tests := []struct {
+		name    string
+		publish func(*Publisher) error
+		want    messaging.OrderEvent
+	}{
+		{
+			name: "status_changed",
+			publish: func(p *Publisher) error {
+				return p.PublishOrderStatusChanged(context.Background(), order, domain.OrderStatusPending, domain.OrderStatusConfirmed)
+			},
+			want: messaging.NewStatusChangedEvent(order, domain.OrderStatusPending, domain.OrderStatusConfirmed),
+		},
+		{
+			name:    "cancelled",
+			publish: func(p *Publisher) error { return p.PublishOrderCancelled(context.Background(), order, "out of stock") },
+			want:    messaging.NewCancelledEvent(order, "out of stock"),
+		},
+		{
+			name:    "deleted",
+			publish: func(p *Publisher) error { return p.PublishOrderDeleted(context.Background(), order) },
+			want:    messaging.NewOrderEvent(messaging.EventOrderDeleted, order),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &captureWriter{}
+			require.NoError(t, tt.publish(New("/ordersvc", w)))
+			require.Len(t, w.messages, 1)
+
+			got, err := messaging.Unmarshal(messaging.CloudEventsSerializer{}, w.messages[0].Value)
+
+			require.NoError(t, err)
+			// Event IDs and times are per publish
+			tt.want.EventID, tt.want.OccurredAt = got.EventID, got.OccurredAt
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPublisher_Publish_PreBuiltEvent_KeepsIDAndTime(t *testing.T) {
+	w := &captureWriter{}
+	evt := messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder())
+	evt.OccurredAt = time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	ctx := messaging.WithRequestID(context.Background(), "req-abc")
+
+	require.NoError(t, New("/ordersvc", w).Publish(ctx, evt))
+
+	var ce messaging.CloudEvent
+	require.NoError(t, json.Unmarshal(w.messages[0].Value, &ce))
+	assert.Equal(t, evt.EventID, ce.ID)
+	assert.Equal(t, evt.OccurredAt, ce.Time)
+	assert.Equal(t, "req-abc", ce.Data.CorrelationID)
+}
+
+func TestPublisher_InvalidEvent_NotWritten(t *testing.T) {
+	w := &captureWriter{}
+
+	err := New("/ordersvc", w).Publish(context.Background(), messaging.OrderEvent{EventType: messaging.EventOrderCreated})
+
+	var verr *messaging.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Empty(t, w.messages)
+}
+
+func TestPublisher_WriteError_Returned(t *testing.T) {
+	errBroker := errors.New("broker unavailable")
+	err := New("/ordersvc", &captureWriter{err: errBroker}).PublishOrderCreated(context.Background(), newTestOrder())
+
+	assert.ErrorIs(t, err, errBroker)
+}
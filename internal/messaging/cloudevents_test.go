@@ -0,0 +1,92 @@
+package messaging
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudEventType_MapsEventConstants(t *testing.T) {
+	tests := []struct {
+		eventType string
+		want      string
+	}{
+		{EventOrderCreated, "io.ordersvc.order.created"},
+		{EventOrderUpdated, "io.ordersvc.order.updated"},
+		{EventOrderStatusChanged, "io.ordersvc.order.status_changed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.eventType, func(t *testing.T) {
+			assert.Equal(t, tt.want, CloudEventType(tt.eventType))
+		})
+	}
+}
+
+func TestEnvelopeFormat_Marshal_CloudEvents_WrapsEvent(t *testing.T) {
+	evt := NewStatusChangedEvent(newTestOrder(), domain.OrderStatusPending, domain.OrderStatusConfirmed)
+
+	data, err := FormatCloudEvents.Serializer().Marshal(evt)
+	require.NoError(t, err)
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	for _, attr := range []string{"specversion", "type", "source", "id", "time", "datacontenttype", "data"} {
+		assert.Contains(t, raw, attr)
+	}
+	assert.Equal(t, "1.0", raw["specversion"])
+	assert.Equal(t, "io.ordersvc.order.status_changed", raw["type"])
+	assert.Equal(t, evt.EventID, raw["id"])
+
+	var ce CloudEvent
+	require.NoError(t, json.Unmarshal(data, &ce))
+	assert.Equal(t, evt.OrderID, ce.Data.OrderID)
+	assert.Equal(t, domain.OrderStatusConfirmed, ce.Data.NewStatus)
+}
+
+func TestEnvelopeFormat_Marshal_CloudEvents_DeterministicID(t *testing.T) {
+	evt := NewOrderEvent(EventOrderCreated, newTestOrder())
+
+	first, err := FormatCloudEvents.Serializer().Marshal(evt)
+	require.NoError(t, err)
+	second, err := FormatCloudEvents.Serializer().Marshal(evt)
+	require.NoError(t, err)
+
+	var a, b CloudEvent
+	require.NoError(t, json.Unmarshal(first, &a))
+	require.NoError(t, json.Unmarshal(second, &b))
+	assert.Equal(t, a.ID, b.ID)
+}
+
+func TestEnvelopeFormat_Marshal_Native_Unwrapped(t *testing.T) {
+	evt := NewOrderEvent(EventOrderCreated, newTestOrder())
+
+	data, err := FormatNative.Serializer().Marshal(evt)
+	require.NoError(t, err)
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.Equal(t, EventOrderCreated, raw["event_type"])
+	assert.NotContains(t, raw, "specversion")
+}
+
+func TestCloudEventsSerializer_RoundTrip(t *testing.T) {
+	evt := NewOrderEvent(EventOrderCreated, newTestOrder())
+	s := CloudEventsSerializer{}
+
+	data, err := s.Marshal(evt)
+	require.NoError(t, err)
+	decoded, err := s.Unmarshal(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, evt.EventID, decoded.EventID)
+	assert.Equal(t, evt.Items, decoded.Items)
+}
+
+func TestCloudEventsSerializer_Unmarshal_WrongSpecVersion_ReturnsError(t *testing.T) {
+	_, err := CloudEventsSerializer{}.Unmarshal([]byte(`{"specversion":"0.3","data":{}}`))
+	assert.Error(t, err)
+}
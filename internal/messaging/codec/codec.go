@@ -0,0 +1,27 @@
+// Package codec names the wire formats available for OrderEvent payloads.
+//
+// The implementations live in package messaging as Serializers so that
+// consumers can pick one from a message's content-type header; the names
+// here are aliases, and any Codec can be passed where a Serializer is
+// expected.
+package codec
+
+import "github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+
+// Codec encodes and decodes OrderEvents and reports the content type that
+// publishers put in the message header.
+type Codec = messaging.Serializer
+
+// JSONCodec encodes events as JSON (content-type application/json).
+// It is the default.
+type JSONCodec = messaging.JSONSerializer
+
+// ProtoCodec encodes events with the events.v1.OrderEvent protobuf schema
+// (content-type application/x-protobuf).
+type ProtoCodec = messaging.ProtobufSerializer
+
+// ForContentType returns the Codec for a content-type header value.
+// An empty value selects JSONCodec.
+func ForContentType(contentType string) (Codec, error) {
+	return messaging.SerializerFor(contentType)
+}
@@ -0,0 +1,151 @@
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// representativeEvent is an order.created event with a few line items
+func representativeEvent() messaging.OrderEvent {
+	order := &domain.Order{
+		ID:         uuid.New(),
+		CustomerID: uuid.New().String(),
+		Items: []domain.OrderItem{
+			{ID: uuid.New(), ProductID: "sku-1001", Name: "Widget", Quantity: 2, Price: domain.Money{Amount: 1050}, Subtotal: domain.Money{Amount: 2100}},
+			{ID: uuid.New(), ProductID: "sku-2002", Name: "Gadget", Quantity: 1, Price: domain.Money{Amount: 500}, Subtotal: domain.Money{Amount: 500}},
+			{ID: uuid.New(), ProductID: "sku-3003", Name: "Gizmo", Quantity: 4, Price: domain.Money{Amount: 225}, Subtotal: domain.Money{Amount: 900}},
+		},
+		Status:  domain.OrderStatusPending,
+		Total:   domain.Money{Amount: 3500, Currency: "USD"},
+		Version: 1,
+	}
+	evt := messaging.NewOrderEvent(messaging.EventOrderCreated, order)
+	evt.OccurredAt = evt.OccurredAt.UTC().Truncate(time.Microsecond)
+	return evt
+}
+
+func TestCodecs_RoundTrip_WithItems(t *testing.T) {
+	for _, c := range []Codec{JSONCodec{}, ProtoCodec{}} {
+		t.Run(c.ContentType(), func(t *testing.T) {
+			evt := representativeEvent()
+
+			data, err := c.Marshal(evt)
+			require.NoError(t, err)
+			decoded, err := c.Unmarshal(data)
+			require.NoError(t, err)
+
+			decoded.OccurredAt = decoded.OccurredAt.UTC()
+			assert.Equal(t, evt, decoded)
+			require.Len(t, decoded.Items, 3)
+			assert.Equal(t, "sku-3003", decoded.Items[2].SKU)
+			assert.Equal(t, 4, decoded.Items[2].Quantity)
+		})
+	}
+}
+
+func TestProtoCodec_SmallerThanJSON(t *testing.T) {
+	evt := representativeEvent()
+
+	jsonData, err := JSONCodec{}.Marshal(evt)
+	require.NoError(t, err)
+	protoData, err := ProtoCodec{}.Marshal(evt)
+	require.NoError(t, err)
+
+	assert.Less(t, len(protoData), len(jsonData))
+}
+
+func TestForContentType(t *testing.T) {
+	c, err := ForContentType(messaging.ContentTypeProtobuf)
+	require.NoError(t, err)
+	assert.IsType(t, ProtoCodec{}, c)
+
+	c, err = ForContentType("")
+	require.NoError(t, err)
+	assert.IsType(t, JSONCodec{}, c)
+
+	_, err = ForContentType("text/plain")
+	assert.Error(t, err)
+}
+
+func TestJSONCodec_ConcurrentMarshal_OutputsDoNotInterleave(t *testing.T) {
+	for _, c := range []JSONCodec{{}, {Naming: messaging.CamelCase, OmitZero: true}} {
+		t.Run(fmt.Sprintf("%+v", c), func(t *testing.T) {
+			events := make([]messaging.OrderEvent, 32)
+			want := make([][]byte, len(events))
+			for i := range events {
+				events[i] = representativeEvent()
+				events[i].Items = events[i].Items[:i%4] // Sizes differ, and so do buffers
+				var err error
+				want[i], err = c.Marshal(events[i])
+				require.NoError(t, err)
+			}
+
+			var wg sync.WaitGroup
+			got := make([][][]byte, len(events))
+			for i := range events {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range 50 {
+						data, err := c.Marshal(events[i])
+						if err != nil {
+							t.Error(err)
+							return
+						}
+						got[i] = append(got[i], data)
+					}
+				}()
+			}
+			wg.Wait()
+
+			for i := range events {
+				for _, data := range got[i] {
+					require.Equal(t, string(want[i]), string(data))
+				}
+				decoded, err := c.Unmarshal(got[i][0])
+				require.NoError(t, err)
+				assert.Equal(t, events[i].OrderID, decoded.OrderID)
+			}
+		})
+	}
+}
+
+// BenchmarkJSONCodec_Marshal compares JSONCodec with the json.Marshal call
+// it replaced, for each field naming.
+func BenchmarkJSONCodec_Marshal(b *testing.B) {
+	evt := representativeEvent()
+	b.Run("json.Marshal", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := json.Marshal(evt); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	for _, c := range []JSONCodec{{}, {Naming: messaging.CamelCase}, {OmitZero: true}} {
+		name := "snake_case"
+		if c.Naming == messaging.CamelCase {
+			name = "camelCase"
+		}
+		if c.OmitZero {
+			name += "_omit_zero"
+		}
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := c.Marshal(evt); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
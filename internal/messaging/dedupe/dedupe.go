@@ -0,0 +1,124 @@
+// Package dedupe provides a consumer handler decorator that skips events
+// already processed, so handlers stay idempotent under Kafka's at-least-once
+// delivery.
+package dedupe
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/kafka"
+)
+
+// Store records the IDs of processed events.
+type Store interface {
+	// SeenBefore reports whether the event with id was marked seen and
+	// the mark has not expired.
+	SeenBefore(ctx context.Context, id string) (bool, error)
+
+	// MarkSeen records that the event with id was processed. Marking an
+	// id again refreshes its mark.
+	MarkSeen(ctx context.Context, id string) error
+}
+
+// Purger is implemented by stores whose marks expire after a TTL.
+type Purger interface {
+	// PurgeExpired deletes expired marks and returns how many it deleted.
+	PurgeExpired(ctx context.Context) (int, error)
+}
+
+// Option configures Deduplicate and RunCleanup.
+type Option func(*options)
+
+type options struct {
+	logger *slog.Logger
+}
+
+// WithLogger logs skipped duplicates at debug level, and failures to mark
+// or purge, to logger. Defaults to logging nothing.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+func newOptions(opts []Option) options {
+	o := options{logger: slog.New(slog.DiscardHandler)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Deduplicate returns a handler that calls handler only for events whose
+// EventID store has not seen, and marks the ID seen once handler succeeds.
+// A redelivered or replayed event is skipped and committed. Events are
+// recognized by EventID alone, which publishers set once per event and
+// keep across relay retries and replays.
+//
+// An event is marked only after handler returns nil, so a failed event is
+// retried. If marking fails the error is logged and the event committed
+// anyway, since its effects have already happened. An event without an
+// EventID cannot be recognized and is always handled.
+//
+// The check and the mark are separate, so the same event delivered to two
+// handlers at once may be processed twice. The consumer reads a partition
+// sequentially and events for an order share a partition, so this only
+// happens across a consumer group rebalance.
+func Deduplicate(store Store, handler kafka.HandlerFunc, opts ...Option) kafka.HandlerFunc {
+	o := newOptions(opts)
+	return func(ctx context.Context, evt messaging.OrderEvent) error {
+		if evt.EventID == "" {
+			return handler(ctx, evt)
+		}
+
+		seen, err := store.SeenBefore(ctx, evt.EventID)
+		if err != nil {
+			return fmt.Errorf("dedupe check %s: %w", evt.EventID, err)
+		}
+		if seen {
+			o.logger.Debug("duplicate event skipped",
+				slog.String("event_type", evt.EventType),
+				slog.String("event_id", evt.EventID),
+				slog.String("order_id", evt.OrderID))
+			return nil
+		}
+
+		if err := handler(ctx, evt); err != nil {
+			return err
+		}
+
+		if err := store.MarkSeen(ctx, evt.EventID); err != nil {
+			o.logger.Warn("failed to mark event processed",
+				slog.String("event_type", evt.EventType),
+				slog.String("event_id", evt.EventID),
+				slog.String("error", err.Error()))
+		}
+		return nil
+	}
+}
+
+// RunCleanup purges expired marks from p every interval until ctx is
+// cancelled.
+func RunCleanup(ctx context.Context, p Purger, interval time.Duration, opts ...Option) {
+	o := newOptions(opts)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := p.PurgeExpired(ctx)
+		if err != nil && ctx.Err() == nil {
+			o.logger.Warn("dedupe cleanup failed", slog.String("error", err.Error()))
+		}
+		if n > 0 {
+			o.logger.Info("purged processed event marks", slog.Int("count", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package dedupe
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is a Store and Purger keeping marks in a map.
+type memoryStore struct {
+	mu       sync.Mutex
+	seen     map[string]bool
+	checkErr error
+	markErr  error
+	purges   int
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{seen: map[string]bool{}}
+}
+
+func (s *memoryStore) SeenBefore(_ context.Context, id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.seen[id], s.checkErr
+}
+
+func (s *memoryStore) MarkSeen(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.markErr != nil {
+		return s.markErr
+	}
+	s.seen[id] = true
+	return nil
+}
+
+func (s *memoryStore) PurgeExpired(context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purges++
+	n := len(s.seen)
+	s.seen = map[string]bool{}
+	return n, nil
+}
+
+func (s *memoryStore) purgeCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.purges
+}
+
+// countingHandler counts the events it handles by ID, failing with err
+// while it is set.
+type countingHandler struct {
+	calls map[string]int
+	err   error
+}
+
+func (h *countingHandler) handle(_ context.Context, evt messaging.OrderEvent) error {
+	if h.calls == nil {
+		h.calls = map[string]int{}
+	}
+	h.calls[evt.EventID]++
+	return h.err
+}
+
+func testEvent(id string) messaging.OrderEvent {
+	return messaging.OrderEvent{EventID: id, EventType: messaging.EventOrderCreated, OrderID: "o-1", Version: 1}
+}
+
+func TestDeduplicate_ReplayedEvent_ProcessedExactlyOnce(t *testing.T) {
+	h := &countingHandler{}
+	handle := Deduplicate(newMemoryStore(), h.handle)
+	evt := testEvent("evt-1")
+	replayed := evt
+	replayed.Replayed = true
+
+	// Delivered, redelivered after a rebalance, then replayed
+	for _, e := range []messaging.OrderEvent{evt, evt, replayed} {
+		require.NoError(t, handle(context.Background(), e))
+	}
+	require.NoError(t, handle(context.Background(), testEvent("evt-2")))
+
+	assert.Equal(t, map[string]int{"evt-1": 1, "evt-2": 1}, h.calls)
+}
+
+func TestDeduplicate_HandlerFails_EventNotMarkedAndRetried(t *testing.T) {
+	store := newMemoryStore()
+	h := &countingHandler{err: errors.New("downstream unavailable")}
+	handle := Deduplicate(store, h.handle)
+
+	assert.ErrorIs(t, handle(context.Background(), testEvent("evt-1")), h.err)
+	seen, _ := store.SeenBefore(context.Background(), "evt-1")
+	assert.False(t, seen)
+
+	h.err = nil
+	require.NoError(t, handle(context.Background(), testEvent("evt-1")))
+	require.NoError(t, handle(context.Background(), testEvent("evt-1")))
+	assert.Equal(t, 2, h.calls["evt-1"], "one failed attempt, one success")
+}
+
+func TestDeduplicate_CheckFails_ReturnsErrorWithoutHandling(t *testing.T) {
+	store := newMemoryStore()
+	store.checkErr = errors.New("connection refused")
+	h := &countingHandler{}
+
+	err := Deduplicate(store, h.handle)(context.Background(), testEvent("evt-1"))
+
+	assert.ErrorIs(t, err, store.checkErr)
+	assert.Empty(t, h.calls)
+}
+
+func TestDeduplicate_MarkFails_EventStillCommitted(t *testing.T) {
+	store := newMemoryStore()
+	store.markErr = errors.New("connection refused")
+	h := &countingHandler{}
+
+	err := Deduplicate(store, h.handle)(context.Background(), testEvent("evt-1"))
+
+	assert.NoError(t, err, "the handler succeeded, so the event is not retried")
+	assert.Equal(t, 1, h.calls["evt-1"])
+}
+
+func TestDeduplicate_NoEventID_AlwaysHandled(t *testing.T) {
+	store := newMemoryStore()
+	h := &countingHandler{}
+	handle := Deduplicate(store, h.handle)
+
+	require.NoError(t, handle(context.Background(), testEvent("")))
+	require.NoError(t, handle(context.Background(), testEvent("")))
+
+	assert.Equal(t, 2, h.calls[""])
+	assert.Empty(t, store.seen)
+}
+
+func TestRunCleanup_PurgesUntilCancelled(t *testing.T) {
+	store := newMemoryStore()
+	require.NoError(t, store.MarkSeen(context.Background(), "evt-1"))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		RunCleanup(ctx, store, time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool { return store.purgeCount() >= 3 }, time.Second, time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunCleanup did not return after cancel")
+	}
+	seen, _ := store.SeenBefore(context.Background(), "evt-1")
+	assert.False(t, seen)
+}
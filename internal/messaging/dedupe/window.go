@@ -0,0 +1,127 @@
+package dedupe
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/kafka"
+)
+
+// DefaultLRUSize is the number of event IDs an LRUStore created with a
+// size of zero or less remembers.
+const DefaultLRUSize = 10000
+
+// SeenStore records when events were processed, for Wrap.
+type SeenStore interface {
+	// SeenWithin reports whether the event with id was marked seen less
+	// than window ago.
+	SeenWithin(ctx context.Context, id string, window time.Duration) (bool, error)
+	// MarkSeen records that the event with id was processed now. Marking
+	// an id again refreshes its mark.
+	MarkSeen(ctx context.Context, id string) error
+}
+
+var _ SeenStore = (*LRUStore)(nil)
+
+// Wrap returns a handler that calls inner only for events whose EventID
+// store has not marked within window, like Deduplicate does for a Store.
+// Events redelivered after a rebalance are skipped and committed, while an
+// event seen longer than window ago is handled again. A nil store uses a
+// new LRUStore of DefaultLRUSize.
+func Wrap(inner kafka.HandlerFunc, store SeenStore, window time.Duration) kafka.HandlerFunc {
+	if store == nil {
+		store = NewLRUStore(DefaultLRUSize)
+	}
+	return Deduplicate(windowed{store: store, window: window}, inner)
+}
+
+// windowed adapts a SeenStore to a Store whose marks expire after window.
+type windowed struct {
+	store  SeenStore
+	window time.Duration
+}
+
+func (w windowed) SeenBefore(ctx context.Context, id string) (bool, error) {
+	return w.store.SeenWithin(ctx, id, w.window)
+}
+
+func (w windowed) MarkSeen(ctx context.Context, id string) error {
+	return w.store.MarkSeen(ctx, id)
+}
+
+// LRUStore is a SeenStore keeping marks in memory. It is safe for
+// concurrent use.
+//
+// Only the most recently marked IDs are remembered: once more than size
+// are marked, the least recently marked is forgotten, and its event is
+// handled again if redelivered. Marks are kept per process, so each
+// replica deduplicates only the events it consumed.
+type LRUStore struct {
+	size  int
+	clock messaging.Clock
+
+	mu      sync.Mutex
+	marks   map[string]*list.Element // Values are *mark
+	recency *list.List               // Most recently marked first
+}
+
+// mark is when the event with id was last marked seen.
+type mark struct {
+	id string
+	at time.Time
+}
+
+// NewLRUStore creates an LRUStore remembering up to size event IDs. A size
+// of zero or less uses DefaultLRUSize.
+func NewLRUStore(size int) *LRUStore {
+	if size <= 0 {
+		size = DefaultLRUSize
+	}
+	return &LRUStore{
+		size:    size,
+		clock:   messaging.SystemClock{},
+		marks:   make(map[string]*list.Element),
+		recency: list.New(),
+	}
+}
+
+// SeenWithin reports whether id was marked less than window ago.
+func (s *LRUStore) SeenWithin(_ context.Context, id string, window time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.marks[id]
+	if !ok {
+		return false, nil
+	}
+	return s.clock.Now().Sub(e.Value.(*mark).at) < window, nil
+}
+
+// MarkSeen marks id seen now, forgetting the least recently marked ID if
+// the store is full.
+func (s *LRUStore) MarkSeen(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.clock.Now()
+	if e, ok := s.marks[id]; ok {
+		e.Value.(*mark).at = now
+		s.recency.MoveToFront(e)
+		return nil
+	}
+	s.marks[id] = s.recency.PushFront(&mark{id: id, at: now})
+	if s.recency.Len() > s.size {
+		oldest := s.recency.Back()
+		s.recency.Remove(oldest)
+		delete(s.marks, oldest.Value.(*mark).id)
+	}
+	return nil
+}
+
+// Len returns the number of IDs the store remembers.
+func (s *LRUStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.recency.Len()
+}
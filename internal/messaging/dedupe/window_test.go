@@ -0,0 +1,102 @@
+package dedupe
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// manualClock is a clock that only moves when advanced.
+type manualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestLRUStore(size int) (*LRUStore, *manualClock) {
+	clock := &manualClock{now: time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)}
+	store := NewLRUStore(size)
+	store.clock = clock
+	return store, clock
+}
+
+func TestWrap_RepeatedEventID_HandledOncePerWindow(t *testing.T) {
+	store, clock := newTestLRUStore(10)
+	h := &countingHandler{}
+	handle := Wrap(h.handle, store, time.Minute)
+	ctx := context.Background()
+
+	require.NoError(t, handle(ctx, testEvent("evt-1")))
+	clock.advance(59 * time.Second)
+	require.NoError(t, handle(ctx, testEvent("evt-1")))
+	assert.Equal(t, 1, h.calls["evt-1"], "redelivered within the window")
+
+	clock.advance(time.Minute)
+	require.NoError(t, handle(ctx, testEvent("evt-1")))
+	assert.Equal(t, 2, h.calls["evt-1"], "the mark expired")
+}
+
+func TestWrap_NilStore_UsesLRU(t *testing.T) {
+	h := &countingHandler{}
+	handle := Wrap(h.handle, nil, time.Hour)
+
+	require.NoError(t, handle(context.Background(), testEvent("evt-1")))
+	require.NoError(t, handle(context.Background(), testEvent("evt-1")))
+
+	assert.Equal(t, 1, h.calls["evt-1"])
+}
+
+func TestLRUStore_Full_ForgetsLeastRecentlyMarked(t *testing.T) {
+	store, _ := newTestLRUStore(2)
+	ctx := context.Background()
+
+	require.NoError(t, store.MarkSeen(ctx, "a"))
+	require.NoError(t, store.MarkSeen(ctx, "b"))
+	require.NoError(t, store.MarkSeen(ctx, "a")) // b is now least recently marked
+	require.NoError(t, store.MarkSeen(ctx, "c"))
+
+	assert.Equal(t, 2, store.Len())
+	for id, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		seen, err := store.SeenWithin(ctx, id, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, want, seen, id)
+	}
+}
+
+func TestLRUStore_ConcurrentMarks_StayBounded(t *testing.T) {
+	store := NewLRUStore(50)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 100 {
+				id := fmt.Sprintf("evt-%d-%d", g, i)
+				assert.NoError(t, store.MarkSeen(ctx, id))
+				_, err := store.SeenWithin(ctx, id, time.Hour)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 50, store.Len())
+}
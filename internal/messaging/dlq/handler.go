@@ -0,0 +1,122 @@
+// Package dlq provides a consumer handler decorator that dead-letters
+// events the wrapped handler keeps failing on, so one bad message does not
+// block its partition, and a Reprocessor that replays dead-lettered
+// events onto their original topics once the failure is fixed.
+package dlq
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/kafka"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/retry"
+)
+
+// Publisher writes an event with extra headers to the dead-letter topic.
+// A kafka.Publisher created for that topic satisfies it.
+type Publisher interface {
+	PublishWithHeaders(ctx context.Context, evt messaging.OrderEvent, headers map[string]string) error
+}
+
+// Default backoff between retries of the wrapped handler.
+const (
+	defaultBaseDelay = 100 * time.Millisecond
+	defaultMaxDelay  = 2 * time.Second
+)
+
+type options struct {
+	baseDelay time.Duration
+	maxDelay  time.Duration
+	logger    *slog.Logger
+}
+
+// discardLogger is the default logger, which drops every record.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// Option configures a Handler.
+type Option func(*options)
+
+// WithBackoff sets the delay before the first retry, doubled on each
+// further retry up to maxDelay. Defaults to 100ms, capped at 2s.
+func WithBackoff(baseDelay, maxDelay time.Duration) Option {
+	return func(o *options) {
+		o.baseDelay = baseDelay
+		o.maxDelay = maxDelay
+	}
+}
+
+// WithLogger logs every dead-lettered event to logger. Defaults to logging
+// nothing.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+// Handler returns a handler that calls inner, retrying it up to maxRetries
+// times with backoff. If it still fails, the event is published to dlq with
+// headers naming the original topic, partition and offset, the attempt
+// count and the last error, and the handler returns nil so the consumer
+// commits the message.
+//
+// If the dead-letter publish fails, or ctx ends while retrying, the error
+// is returned and the message stays uncommitted.
+func Handler(inner kafka.HandlerFunc, dlq Publisher, maxRetries int, opts ...Option) kafka.HandlerFunc {
+	o := options{baseDelay: defaultBaseDelay, maxDelay: defaultMaxDelay, logger: discardLogger}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	policy := retry.Policy{
+		MaxAttempts: maxRetries + 1,
+		BaseDelay:   o.baseDelay,
+		MaxDelay:    o.maxDelay,
+	}
+
+	return func(ctx context.Context, evt messaging.OrderEvent) error {
+		attempts := 0
+		var lastErr error
+		err := retry.Do(ctx, policy, func(ctx context.Context) error {
+			attempts++
+			lastErr = inner(ctx, evt)
+			return lastErr
+		})
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+
+		if err := dlq.PublishWithHeaders(ctx, evt, headers(ctx, lastErr, attempts)); err != nil {
+			return fmt.Errorf("dead-letter %s %s: %w (handler error: %v)", evt.EventType, evt.EventID, err, lastErr)
+		}
+		o.logger.Warn("event dead-lettered",
+			slog.String("event_type", evt.EventType),
+			slog.String("event_id", evt.EventID),
+			slog.String("order_id", evt.OrderID),
+			slog.Int("attempts", attempts),
+			slog.String("error", lastErr.Error()))
+		return nil
+	}
+}
+
+// headers describes the failure and, when the consumer provided it, where
+// the event was read from and how often it was replayed.
+func headers(ctx context.Context, cause error, attempts int) map[string]string {
+	h := map[string]string{
+		kafka.HeaderDLQError:    cause.Error(),
+		kafka.HeaderDLQAttempts: strconv.Itoa(attempts),
+	}
+	if d, ok := kafka.DeliveryFromContext(ctx); ok {
+		h[kafka.HeaderDLQOriginalTopic] = d.Topic
+		h[kafka.HeaderDLQOriginalPartition] = strconv.Itoa(d.Partition)
+		h[kafka.HeaderDLQOriginalOffset] = strconv.FormatInt(d.Offset, 10)
+		// So a Reprocessor still sees how often it replayed the event
+		if n := kafka.HeaderValue(d.Headers, kafka.HeaderDLQReplays); n != "" {
+			h[kafka.HeaderDLQReplays] = n
+		}
+	}
+	return h
+}
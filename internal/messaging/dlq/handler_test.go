@@ -0,0 +1,135 @@
+package dlq
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingPublisher captures dead-lettered events.
+type recordingPublisher struct {
+	events  []messaging.OrderEvent
+	headers []map[string]string
+	err     error
+}
+
+func (p *recordingPublisher) PublishWithHeaders(_ context.Context, evt messaging.OrderEvent, headers map[string]string) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.events = append(p.events, evt)
+	p.headers = append(p.headers, headers)
+	return nil
+}
+
+// failingHandler fails the first n calls with err.
+func failingHandler(n int, err error, calls *int) kafka.HandlerFunc {
+	return func(_ context.Context, _ messaging.OrderEvent) error {
+		*calls++
+		if *calls <= n {
+			return err
+		}
+		return nil
+	}
+}
+
+func testEvent() messaging.OrderEvent {
+	return messaging.OrderEvent{EventID: "evt-1", EventType: messaging.EventOrderCreated, OrderID: "o-1", Version: 1}
+}
+
+var fastBackoff = WithBackoff(time.Millisecond, time.Millisecond)
+
+func TestHandler_PersistentFailure_PublishesToDLQWithMetadata(t *testing.T) {
+	dlq := &recordingPublisher{}
+	calls := 0
+	h := Handler(failingHandler(100, errors.New("payment service down"), &calls), dlq, 2, fastBackoff)
+	ctx := kafka.ContextWithDelivery(context.Background(),
+		kafka.Delivery{Topic: "order-events", Partition: 3, Offset: 42})
+
+	err := h(ctx, testEvent())
+
+	require.NoError(t, err, "dead-lettered event should be committed")
+	assert.Equal(t, 3, calls)
+	require.Len(t, dlq.events, 1)
+	assert.Equal(t, testEvent(), dlq.events[0])
+	assert.Equal(t, map[string]string{
+		kafka.HeaderDLQError:             "payment service down",
+		kafka.HeaderDLQAttempts:          "3",
+		kafka.HeaderDLQOriginalTopic:     "order-events",
+		kafka.HeaderDLQOriginalPartition: "3",
+		kafka.HeaderDLQOriginalOffset:    "42",
+	}, dlq.headers[0])
+}
+
+func TestHandler_ReplayedEvent_KeepsReplayCount(t *testing.T) {
+	dlq := &recordingPublisher{}
+	calls := 0
+	h := Handler(failingHandler(100, errors.New("still broken"), &calls), dlq, 0, fastBackoff)
+	ctx := kafka.ContextWithDelivery(context.Background(), kafka.Delivery{
+		Topic:   "order-events",
+		Headers: []kafkago.Header{{Key: kafka.HeaderDLQReplays, Value: []byte("2")}},
+	})
+
+	require.NoError(t, h(ctx, testEvent()))
+
+	require.Len(t, dlq.headers, 1)
+	assert.Equal(t, "2", dlq.headers[0][kafka.HeaderDLQReplays])
+}
+
+func TestHandler_TransientFailure_SucceedsOnRetry(t *testing.T) {
+	dlq := &recordingPublisher{}
+	calls := 0
+	h := Handler(failingHandler(2, errors.New("timeout"), &calls), dlq, 3, fastBackoff)
+
+	err := h(context.Background(), testEvent())
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Empty(t, dlq.events)
+}
+
+func TestHandler_ZeroRetries_DeadLettersAfterFirstFailure(t *testing.T) {
+	dlq := &recordingPublisher{}
+	calls := 0
+	h := Handler(failingHandler(1, errors.New("bad data"), &calls), dlq, 0, fastBackoff)
+
+	require.NoError(t, h(context.Background(), testEvent()))
+
+	assert.Equal(t, 1, calls)
+	require.Len(t, dlq.headers, 1)
+	assert.Equal(t, "1", dlq.headers[0][kafka.HeaderDLQAttempts])
+	assert.NotContains(t, dlq.headers[0], kafka.HeaderDLQOriginalOffset, "no delivery on context")
+}
+
+func TestHandler_DLQPublishFails_ReturnsError(t *testing.T) {
+	handlerErr := errors.New("bad data")
+	dlqErr := errors.New("dlq broker down")
+	calls := 0
+	h := Handler(failingHandler(100, handlerErr, &calls), &recordingPublisher{err: dlqErr}, 1, fastBackoff)
+
+	err := h(context.Background(), testEvent())
+
+	assert.ErrorIs(t, err, dlqErr)
+	assert.Contains(t, err.Error(), "bad data")
+}
+
+func TestHandler_ContextCancelled_DoesNotDeadLetter(t *testing.T) {
+	dlq := &recordingPublisher{}
+	ctx, cancel := context.WithCancel(context.Background())
+	h := Handler(func(context.Context, messaging.OrderEvent) error {
+		cancel()
+		return errors.New("failed")
+	}, dlq, 5, fastBackoff)
+
+	err := h(ctx, testEvent())
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, dlq.events)
+}
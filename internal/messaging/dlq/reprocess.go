@@ -0,0 +1,187 @@
+package dlq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/kafka"
+)
+
+// Reader reads the dead-letter topic. A *kafka.Reader from kafka-go with a
+// GroupID satisfies it, so a stopped run resumes where it left off.
+type Reader interface {
+	FetchMessage(ctx context.Context) (kafkago.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafkago.Message) error
+}
+
+// Writer writes replayed messages to the topics they name. A *kafka.Writer
+// from kafka-go without a Topic satisfies it.
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafkago.Message) error
+}
+
+// Reprocessor defaults.
+const (
+	defaultMaxReplays  = 3
+	defaultIdleTimeout = 5 * time.Second
+)
+
+// Reprocessor drains a dead-letter topic back into the main flow once the
+// cause of the failures is fixed. See Run.
+type Reprocessor struct {
+	reader      Reader
+	writer      Writer
+	maxReplays  int
+	idleTimeout time.Duration
+	limit       int
+	logger      *slog.Logger
+}
+
+// ReprocessorOption configures a Reprocessor.
+type ReprocessorOption func(*Reprocessor)
+
+// WithMaxReplays sets how many times one message may be replayed. A
+// message that was dead-lettered again that often is left on the
+// dead-letter topic and reported as failed, so an event that keeps failing
+// does not loop between the topics forever. Defaults to 3.
+func WithMaxReplays(n int) ReprocessorOption {
+	return func(r *Reprocessor) { r.maxReplays = n }
+}
+
+// WithIdleTimeout sets how long Run waits for another message before
+// deciding the dead-letter topic is drained. Defaults to 5s.
+func WithIdleTimeout(d time.Duration) ReprocessorOption {
+	return func(r *Reprocessor) { r.idleTimeout = d }
+}
+
+// WithLimit stops Run after n messages. Defaults to 0, no limit.
+func WithLimit(n int) ReprocessorOption {
+	return func(r *Reprocessor) { r.limit = n }
+}
+
+// WithReprocessorLogger logs every message Run cannot replay to logger.
+// Defaults to logging nothing.
+func WithReprocessorLogger(logger *slog.Logger) ReprocessorOption {
+	return func(r *Reprocessor) { r.logger = logger }
+}
+
+// NewReprocessor creates a Reprocessor reading dead-lettered messages
+// from reader and replaying them with writer.
+func NewReprocessor(reader Reader, writer Writer, opts ...ReprocessorOption) *Reprocessor {
+	r := &Reprocessor{reader: reader, writer: writer, maxReplays: defaultMaxReplays, idleTimeout: defaultIdleTimeout, logger: discardLogger}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Summary reports what a Run did.
+type Summary struct {
+	Republished int
+	ByTopic     map[string]int // Republished messages by original topic
+	Failed      []Failure      // Messages left on the dead-letter topic
+}
+
+// Failure is a dead-lettered message Run could not replay.
+type Failure struct {
+	Partition int
+	Offset    int64
+	Key       string
+	Reason    string
+}
+
+// Run replays dead-lettered messages until none arrives for the idle
+// timeout, the limit is reached, or ctx ends. Each message is written
+// unchanged to the topic in its kafka.HeaderDLQOriginalTopic header,
+// without the dead-letter and retry headers so it gets a fresh set of
+// handler attempts, and with kafka.HeaderDLQReplays counting the replay.
+// Messages without an original topic, or replayed the maximum number of
+// times already, are reported in Summary.Failed instead.
+//
+// Every message is committed once replayed or reported. If a write or
+// commit fails, Run stops and returns the error; the message stays
+// uncommitted, so the next run starts from it.
+func (r *Reprocessor) Run(ctx context.Context) (Summary, error) {
+	s := Summary{ByTopic: map[string]int{}}
+	for n := 0; r.limit <= 0 || n < r.limit; n++ {
+		msg, err := r.fetch(ctx)
+		if errors.Is(err, errDrained) {
+			return s, nil
+		}
+		if err != nil {
+			return s, fmt.Errorf("dlq reprocess: fetch: %w", err)
+		}
+
+		if reason := r.rejected(msg); reason != "" {
+			s.Failed = append(s.Failed, Failure{Partition: msg.Partition, Offset: msg.Offset, Key: string(msg.Key), Reason: reason})
+			r.logger.Warn("dead-lettered message not replayed",
+				slog.Int("partition", msg.Partition),
+				slog.Int64("offset", msg.Offset),
+				slog.String("reason", reason))
+		} else {
+			topic := kafka.HeaderValue(msg.Headers, kafka.HeaderDLQOriginalTopic)
+			if err := r.writer.WriteMessages(ctx, replay(msg, topic)); err != nil {
+				return s, fmt.Errorf("dlq reprocess: write to %s: %w", topic, err)
+			}
+			s.Republished++
+			s.ByTopic[topic]++
+		}
+		if err := r.reader.CommitMessages(ctx, msg); err != nil {
+			return s, fmt.Errorf("dlq reprocess: commit: %w", err)
+		}
+	}
+	return s, nil
+}
+
+var errDrained = errors.New("dead-letter topic drained")
+
+// fetch returns the next message, or errDrained if none arrives within
+// the idle timeout.
+func (r *Reprocessor) fetch(ctx context.Context) (kafkago.Message, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, r.idleTimeout)
+	defer cancel()
+	msg, err := r.reader.FetchMessage(fetchCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(fetchCtx.Err(), context.DeadlineExceeded) {
+		return kafkago.Message{}, errDrained
+	}
+	return msg, err
+}
+
+// rejected returns why msg cannot be replayed, or "" if it can.
+func (r *Reprocessor) rejected(msg kafkago.Message) string {
+	if kafka.HeaderValue(msg.Headers, kafka.HeaderDLQOriginalTopic) == "" {
+		return "no " + kafka.HeaderDLQOriginalTopic + " header"
+	}
+	if n := replays(msg); n >= r.maxReplays {
+		return fmt.Sprintf("replayed %d times already", n)
+	}
+	return ""
+}
+
+// replays returns how often msg was replayed already.
+func replays(msg kafkago.Message) int {
+	n, err := strconv.Atoi(kafka.HeaderValue(msg.Headers, kafka.HeaderDLQReplays))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// replay returns msg as it is written back to topic.
+func replay(msg kafkago.Message, topic string) kafkago.Message {
+	headers := make([]kafkago.Header, 0, len(msg.Headers)+1)
+	for _, h := range msg.Headers {
+		if strings.HasPrefix(h.Key, "dlq-") || h.Key == kafka.HeaderRetryAttempts {
+			continue
+		}
+		headers = append(headers, h)
+	}
+	headers = append(headers, kafkago.Header{Key: kafka.HeaderDLQReplays, Value: []byte(strconv.Itoa(replays(msg) + 1))})
+	return kafkago.Message{Topic: topic, Key: msg.Key, Value: msg.Value, Headers: headers}
+}
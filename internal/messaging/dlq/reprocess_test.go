@@ -0,0 +1,178 @@
+package dlq
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDLQ is a dead-letter topic serving seeded messages in order, then
+// blocking like an idle topic.
+type fakeDLQ struct {
+	mu        sync.Mutex
+	messages  []kafkago.Message
+	next      int
+	committed []int64
+}
+
+func (f *fakeDLQ) seed(key, originalTopic string, extra ...kafkago.Header) {
+	headers := []kafkago.Header{
+		{Key: kafka.HeaderEventID, Value: []byte("evt-" + key)},
+		{Key: kafka.HeaderDLQError, Value: []byte("payment service down")},
+		{Key: kafka.HeaderDLQAttempts, Value: []byte("3")},
+		{Key: kafka.HeaderRetryAttempts, Value: []byte("3")},
+	}
+	if originalTopic != "" {
+		headers = append(headers,
+			kafkago.Header{Key: kafka.HeaderDLQOriginalTopic, Value: []byte(originalTopic)},
+			kafkago.Header{Key: kafka.HeaderDLQOriginalPartition, Value: []byte("0")})
+	}
+	f.messages = append(f.messages, kafkago.Message{
+		Topic:   "order-events.dlq",
+		Offset:  int64(len(f.messages)),
+		Key:     []byte(key),
+		Value:   []byte(`{"order_id":"` + key + `"}`),
+		Headers: append(headers, extra...),
+	})
+}
+
+func (f *fakeDLQ) FetchMessage(ctx context.Context) (kafkago.Message, error) {
+	f.mu.Lock()
+	if f.next < len(f.messages) {
+		msg := f.messages[f.next]
+		f.next++
+		f.mu.Unlock()
+		return msg, nil
+	}
+	f.mu.Unlock()
+	<-ctx.Done()
+	return kafkago.Message{}, ctx.Err()
+}
+
+func (f *fakeDLQ) CommitMessages(_ context.Context, msgs ...kafkago.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, m := range msgs {
+		f.committed = append(f.committed, m.Offset)
+	}
+	return nil
+}
+
+// topicWriter records replayed messages, failing writes to topics in fail.
+type topicWriter struct {
+	written []kafkago.Message
+	fail    map[string]error
+}
+
+func (w *topicWriter) WriteMessages(_ context.Context, msgs ...kafkago.Message) error {
+	for _, m := range msgs {
+		if err := w.fail[m.Topic]; err != nil {
+			return err
+		}
+	}
+	w.written = append(w.written, msgs...)
+	return nil
+}
+
+func headerMap(msg kafkago.Message) map[string]string {
+	out := map[string]string{}
+	for _, h := range msg.Headers {
+		out[h.Key] = string(h.Value)
+	}
+	return out
+}
+
+var fastIdle = WithIdleTimeout(20 * time.Millisecond)
+
+func TestReprocessor_Run_RepublishesToOriginalTopics(t *testing.T) {
+	dlq := &fakeDLQ{}
+	dlq.seed("o-1", "order-events")
+	dlq.seed("o-2", "payment-events")
+	dlq.seed("o-3", "order-events")
+	w := &topicWriter{}
+
+	summary, err := NewReprocessor(dlq, w, fastIdle).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, summary.Republished)
+	assert.Equal(t, map[string]int{"order-events": 2, "payment-events": 1}, summary.ByTopic)
+	assert.Empty(t, summary.Failed)
+	assert.Equal(t, []int64{0, 1, 2}, dlq.committed)
+
+	require.Len(t, w.written, 3)
+	assert.Equal(t, []string{"order-events", "payment-events", "order-events"},
+		[]string{w.written[0].Topic, w.written[1].Topic, w.written[2].Topic})
+	for i, msg := range w.written {
+		assert.Equal(t, dlq.messages[i].Key, msg.Key)
+		assert.Equal(t, dlq.messages[i].Value, msg.Value)
+		assert.Equal(t, map[string]string{
+			kafka.HeaderEventID:    "evt-" + string(msg.Key),
+			kafka.HeaderDLQReplays: "1",
+		}, headerMap(msg), "dead-letter and retry headers are dropped")
+	}
+}
+
+func TestReprocessor_Run_UnreplayableMessages_ReportedAndCommitted(t *testing.T) {
+	dlq := &fakeDLQ{}
+	dlq.seed("o-1", "order-events", kafkago.Header{Key: kafka.HeaderDLQReplays, Value: []byte("2")})
+	dlq.seed("o-2", "")
+	dlq.seed("o-3", "order-events", kafkago.Header{Key: kafka.HeaderDLQReplays, Value: []byte("1")})
+	w := &topicWriter{}
+
+	summary, err := NewReprocessor(dlq, w, fastIdle, WithMaxReplays(2)).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Republished)
+	assert.Equal(t, []Failure{
+		{Partition: 0, Offset: 0, Key: "o-1", Reason: "replayed 2 times already"},
+		{Partition: 0, Offset: 1, Key: "o-2", Reason: "no dlq-original-topic header"},
+	}, summary.Failed)
+	assert.Equal(t, []int64{0, 1, 2}, dlq.committed)
+	require.Len(t, w.written, 1)
+	assert.Equal(t, "2", headerMap(w.written[0])[kafka.HeaderDLQReplays])
+}
+
+func TestReprocessor_Run_WriteFailure_StopsBeforeCommitting(t *testing.T) {
+	dlq := &fakeDLQ{}
+	dlq.seed("o-1", "order-events")
+	dlq.seed("o-2", "payment-events")
+	dlq.seed("o-3", "order-events")
+	errBroker := errors.New("broker unavailable")
+	w := &topicWriter{fail: map[string]error{"payment-events": errBroker}}
+
+	summary, err := NewReprocessor(dlq, w, fastIdle).Run(context.Background())
+
+	require.ErrorIs(t, err, errBroker)
+	assert.ErrorContains(t, err, "payment-events")
+	assert.Equal(t, 1, summary.Republished)
+	assert.Equal(t, []int64{0}, dlq.committed, "the failed message is read again next run")
+}
+
+func TestReprocessor_Run_Limit(t *testing.T) {
+	dlq := &fakeDLQ{}
+	for _, key := range []string{"o-1", "o-2", "o-3"} {
+		dlq.seed(key, "order-events")
+	}
+
+	summary, err := NewReprocessor(dlq, &topicWriter{}, WithLimit(2)).Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.Republished)
+	assert.Equal(t, []int64{0, 1}, dlq.committed)
+}
+
+func TestReprocessor_Run_ContextCancelled_ReturnsError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewReprocessor(&fakeDLQ{}, &topicWriter{}).Run(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
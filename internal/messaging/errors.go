@@ -0,0 +1,21 @@
+package messaging
+
+import "errors"
+
+// Publish failures are classified by cause, so callers can tell a bug in
+// the event from a broker outage with errors.Is. Publishers wrap one of
+// these alongside the underlying error.
+var (
+	// ErrValidation is wrapped by every *ValidationError: the event is
+	// missing a field consumers rely on and was not sent. Retrying does
+	// not help.
+	ErrValidation = errors.New("invalid order event")
+
+	// ErrSerialization means the event could not be encoded. Retrying does
+	// not help.
+	ErrSerialization = errors.New("event serialization failed")
+
+	// ErrBrokerUnavailable means the broker could not be reached or did
+	// not answer in time. The event may be sent later.
+	ErrBrokerUnavailable = errors.New("message broker unavailable")
+)
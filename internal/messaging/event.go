@@ -1,24 +1,221 @@
 // Package messaging defines event types for order domain events.
 package messaging
 
-import "time"
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+)
 
 // Event type constants for order domain events.
 const (
 	EventOrderCreated       = "order.created"
 	EventOrderUpdated       = "order.updated"
 	EventOrderStatusChanged = "order.status_changed"
+	EventOrderCancelled     = "order.cancelled"
+	EventOrderDeleted       = "order.deleted"
+	EventOrderExpired       = "order.expired"
+	EventOrderSnapshot      = "order.snapshot" // Current state, not a change; for read-model bootstrapping
 )
 
 // OrderEvent is the Kafka message envelope for order domain events.
 type OrderEvent struct {
-	EventType  string    `json:"event_type"`
-	OrderID    string    `json:"order_id"`
-	CustomerID string    `json:"customer_id"`
-	Status     string    `json:"status"`
-	OldStatus  string    `json:"old_status,omitempty"`
-	NewStatus  string    `json:"new_status,omitempty"`
-	Total      float64   `json:"total"`
-	Version    int       `json:"version"`
-	OccurredAt time.Time `json:"occurred_at"`
+	EventID         string                `json:"event_id"` // Unique per publish, for consumer deduplication
+	EventType       string                `json:"event_type"`
+	SchemaVersion   int                   `json:"schema_version"` // Envelope version, see CurrentSchemaVersion
+	OrderID         string                `json:"order_id"`
+	CustomerID      string                `json:"customer_id"`
+	Status          domain.OrderStatus    `json:"status"`
+	OldStatus       domain.OrderStatus    `json:"old_status,omitempty"`
+	NewStatus       domain.OrderStatus    `json:"new_status,omitempty"`
+	CancelReason    string                `json:"cancel_reason,omitempty"` // Set on order.cancelled and order.snapshot
+	Total           float64               `json:"total"`                   // Major units; may be inexact, prefer TotalMinor
+	TotalMinor      int64                 `json:"total_minor"`             // Exact total in minor units of Currency; since v3
+	Currency        string                `json:"currency,omitempty"`      // ISO 4217 code of Total and item prices; since v2
+	Version         int                   `json:"version"`
+	Items           []OrderLineEvent      `json:"items,omitempty"`
+	ShippingAddress *AddressEvent         `json:"shipping_address,omitempty"`
+	OccurredAt      time.Time             `json:"occurred_at"`
+	Replayed        bool                  `json:"replayed,omitempty"`       // Re-emitted by a replay, possibly already seen
+	CorrelationID   string                `json:"correlation_id,omitempty"` // ID of the request that caused the event; since v4
+	ChangedFields   []string              `json:"changed_fields,omitempty"` // Fields an order.updated changed, see domain.Order.Diff; since v5
+	Seq             int64                 `json:"seq,omitempty"`            // Per-order sequence, one more than the order's previous event; since v6
+	Metadata        map[string]string     `json:"metadata,omitempty"`       // Deployment-specific context added by enrichers; since v7
+	CausationID     string                `json:"causation_id,omitempty"`   // EventID of the event that led to this one, empty for the first; since v8
+	Changes         map[string]ChangePair `json:"changes,omitempty"`        // Values before and after of each of ChangedFields; since v9
+}
+
+// ChangePair is the value of a field before and after an order.updated,
+// as carried in OrderEvent.Changes. Each is the field's JSON value as the
+// envelope carries it: customer_id and status as strings, total as
+// total_minor, and items as the items array.
+type ChangePair struct {
+	Old json.RawMessage `json:"old,omitempty"`
+	New json.RawMessage `json:"new,omitempty"`
+}
+
+// OrderLineEvent is a line item carried in an OrderEvent.
+type OrderLineEvent struct {
+	SKU       string  `json:"sku"` // domain.OrderItem.ProductID
+	Name      string  `json:"name"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+	Subtotal  float64 `json:"subtotal"`
+	// UnitPrice and Subtotal exactly, in minor units of the event's
+	// currency; since v3
+	UnitPriceMinor int64 `json:"unit_price_minor"`
+	SubtotalMinor  int64 `json:"subtotal_minor"`
+}
+
+// AddressEvent is a postal address carried in an OrderEvent.
+// domain.Order does not record a shipping address yet, so publishers leave
+// OrderEvent.ShippingAddress nil until it does.
+type AddressEvent struct {
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+// NewOrderEvent builds the envelope for an event of eventType about order,
+// including its line items.
+func NewOrderEvent(eventType string, order *domain.Order) OrderEvent {
+	return OrderEvent{
+		EventID:       uuid.NewString(),
+		EventType:     eventType,
+		SchemaVersion: CurrentSchemaVersion,
+		OrderID:       order.ID.String(),
+		CustomerID:    order.CustomerID,
+		Status:        order.Status,
+		Total:         order.Total.Float64(),
+		TotalMinor:    order.Total.Amount,
+		Currency:      order.Total.Currency,
+		Version:       order.Version,
+		Seq:           order.EventSeq,
+		Items:         newOrderLineEvents(order.Items),
+		OccurredAt:    time.Now(),
+	}
+}
+
+// NewUpdatedEvent builds an order.updated envelope for order carrying the
+// changes made to it, as NewChanges returns them, and listing their
+// fields. changes may be nil when the update is not an edit of the order's
+// fields, such as a restore.
+func NewUpdatedEvent(order *domain.Order, changes map[string]ChangePair) OrderEvent {
+	evt := NewOrderEvent(EventOrderUpdated, order)
+	evt.ChangedFields = changedFields(changes)
+	evt.Changes = changes
+	return evt
+}
+
+// changeFields are the fields NewChanges reports, in the order of
+// domain.Order.Diff.
+var changeFields = []string{"customer_id", "items", "total", "status"}
+
+// NewChanges returns the fields that differ between prev and order, as
+// domain.Order.Diff names them, with their values in each. It returns nil
+// if none differ.
+func NewChanges(prev, order *domain.Order) map[string]ChangePair {
+	changed := order.Diff(prev)
+	if changed == nil {
+		return nil
+	}
+	changes := make(map[string]ChangePair, len(changed))
+	for _, field := range changed {
+		changes[field] = ChangePair{Old: changeValue(prev, field), New: changeValue(order, field)}
+	}
+	return changes
+}
+
+// changeValue returns the JSON value of field of order.
+func changeValue(order *domain.Order, field string) json.RawMessage {
+	var v any
+	switch field {
+	case "customer_id":
+		v = order.CustomerID
+	case "items":
+		v = newOrderLineEvents(order.Items)
+	case "total":
+		v = order.Total.Amount
+	case "status":
+		v = order.Status
+	}
+	data, _ := json.Marshal(v) // None of them can fail
+	return data
+}
+
+// changedFields returns the fields of changes in the order of
+// changeFields, followed by any others in alphabetical order.
+func changedFields(changes map[string]ChangePair) []string {
+	if len(changes) == 0 {
+		return nil
+	}
+	rank := func(field string) int {
+		if i := slices.Index(changeFields, field); i >= 0 {
+			return i
+		}
+		return len(changeFields)
+	}
+	fields := slices.Sorted(maps.Keys(changes))
+	slices.SortStableFunc(fields, func(a, b string) int { return cmp.Compare(rank(a), rank(b)) })
+	return fields
+}
+
+// NewStatusChangedEvent builds an order.status_changed envelope for order.
+// Line items are omitted since a status change does not alter contents.
+func NewStatusChangedEvent(order *domain.Order, oldStatus, newStatus domain.OrderStatus) OrderEvent {
+	evt := NewOrderEvent(EventOrderStatusChanged, order)
+	evt.Items = nil
+	evt.OldStatus = oldStatus
+	evt.NewStatus = newStatus
+	return evt
+}
+
+// NewCancelledEvent builds an order.cancelled envelope for order carrying
+// the cancellation reason, which may be empty. Line items are omitted.
+func NewCancelledEvent(order *domain.Order, reason string) OrderEvent {
+	evt := NewOrderEvent(EventOrderCancelled, order)
+	evt.Items = nil
+	evt.CancelReason = reason
+	return evt
+}
+
+// NewSnapshotEvent builds the order.snapshot envelope of the full current
+// state of order: its lines, and its cancellation reason if it was
+// cancelled, as of its last change. Consumers that missed events can
+// reconcile against it by Version. An order that was never stored occurs
+// now.
+func NewSnapshotEvent(order *domain.Order) OrderEvent {
+	evt := NewOrderEvent(EventOrderSnapshot, order)
+	evt.CancelReason = order.CancelReason
+	if !order.UpdatedAt.IsZero() {
+		evt.OccurredAt = order.UpdatedAt
+	}
+	return evt
+}
+
+func newOrderLineEvents(items []domain.OrderItem) []OrderLineEvent {
+	if len(items) == 0 {
+		return nil
+	}
+	lines := make([]OrderLineEvent, len(items))
+	for i, item := range items {
+		lines[i] = OrderLineEvent{
+			SKU:            item.ProductID,
+			Name:           item.Name,
+			Quantity:       item.Quantity,
+			UnitPrice:      item.Price.Float64(),
+			Subtotal:       item.Subtotal.Float64(),
+			UnitPriceMinor: item.Price.Amount,
+			SubtotalMinor:  item.Subtotal.Amount,
+		}
+	}
+	return lines
 }
@@ -0,0 +1,194 @@
+package messaging
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOrder() *domain.Order {
+	return &domain.Order{
+		ID:         uuid.New(),
+		CustomerID: "cust-123",
+		Items: []domain.OrderItem{
+			{ID: uuid.New(), ProductID: "p-1", Name: "Widget", Quantity: 2, Price: domain.Money{Amount: 1050}, Subtotal: domain.Money{Amount: 2100}},
+			{ID: uuid.New(), ProductID: "p-2", Name: "Gadget", Quantity: 1, Price: domain.Money{Amount: 500}, Subtotal: domain.Money{Amount: 500}},
+		},
+		Status:  domain.OrderStatusPending,
+		Total:   domain.Money{Amount: 2600, Currency: "EUR"},
+		Version: 1,
+	}
+}
+
+func TestNewOrderEvent_PopulatesItems(t *testing.T) {
+	order := newTestOrder()
+
+	evt := NewOrderEvent(EventOrderCreated, order)
+
+	require.Len(t, evt.Items, 2)
+	assert.Equal(t, OrderLineEvent{
+		SKU: "p-1", Name: "Widget", Quantity: 2,
+		UnitPrice: 10.50, Subtotal: 21.00, UnitPriceMinor: 1050, SubtotalMinor: 2100,
+	}, evt.Items[0])
+	assert.Equal(t, "p-2", evt.Items[1].SKU)
+	assert.Equal(t, "EUR", evt.Currency)
+	assert.Equal(t, 26.00, evt.Total)
+	assert.Equal(t, int64(2600), evt.TotalMinor)
+	assert.Nil(t, evt.ShippingAddress)
+}
+
+func TestNewStatusChangedEvent_OmitsItems(t *testing.T) {
+	evt := NewStatusChangedEvent(newTestOrder(), domain.OrderStatusPending, domain.OrderStatusConfirmed)
+
+	assert.Nil(t, evt.Items)
+	assert.Equal(t, domain.OrderStatusPending, evt.OldStatus)
+	assert.Equal(t, domain.OrderStatusConfirmed, evt.NewStatus)
+}
+
+func TestNewCancelledEvent_CarriesReason(t *testing.T) {
+	evt := NewCancelledEvent(newTestOrder(), "payment declined")
+
+	assert.Equal(t, EventOrderCancelled, evt.EventType)
+	assert.Equal(t, "payment declined", evt.CancelReason)
+	assert.Nil(t, evt.Items)
+	assert.NoError(t, evt.Validate())
+}
+
+func TestEventConstructors_PopulateEveryField(t *testing.T) {
+	order := newTestOrder()
+	order.Status = domain.OrderStatusConfirmed
+	order.Version = 3
+	order.EventSeq = 4
+	base := OrderEvent{
+		SchemaVersion: CurrentSchemaVersion,
+		OrderID:       order.ID.String(),
+		CustomerID:    "cust-123",
+		Status:        domain.OrderStatusConfirmed,
+		Total:         26.00,
+		TotalMinor:    2600,
+		Currency:      "EUR",
+		Version:       3,
+		Seq:           4,
+	}
+	items := []OrderLineEvent{
+		{SKU: "p-1", Name: "Widget", Quantity: 2, UnitPrice: 10.50, Subtotal: 21.00, UnitPriceMinor: 1050, SubtotalMinor: 2100},
+		{SKU: "p-2", Name: "Gadget", Quantity: 1, UnitPrice: 5.00, Subtotal: 5.00, UnitPriceMinor: 500, SubtotalMinor: 500},
+	}
+	with := func(eventType string, edit func(*OrderEvent)) OrderEvent {
+		evt := base
+		evt.EventType = eventType
+		edit(&evt)
+		return evt
+	}
+
+	tests := []struct {
+		name string
+		got  OrderEvent
+		want OrderEvent
+	}{
+		{
+			name: "created",
+			got:  NewOrderEvent(EventOrderCreated, order),
+			want: with(EventOrderCreated, func(e *OrderEvent) { e.Items = items }),
+		},
+		{
+			name: "updated",
+			got:  NewUpdatedEvent(order, map[string]ChangePair{"items": {}, "customer_id": {}}),
+			want: with(EventOrderUpdated, func(e *OrderEvent) {
+				e.Items = items
+				e.ChangedFields = []string{"customer_id", "items"}
+				e.Changes = map[string]ChangePair{"customer_id": {}, "items": {}}
+			}),
+		},
+		{
+			name: "status_changed",
+			got:  NewStatusChangedEvent(order, domain.OrderStatusPending, domain.OrderStatusConfirmed),
+			want: with(EventOrderStatusChanged, func(e *OrderEvent) {
+				e.OldStatus = domain.OrderStatusPending
+				e.NewStatus = domain.OrderStatusConfirmed
+			}),
+		},
+		{
+			name: "cancelled",
+			got:  NewCancelledEvent(order, "payment declined"),
+			want: with(EventOrderCancelled, func(e *OrderEvent) { e.CancelReason = "payment declined" }),
+		},
+		{
+			name: "deleted",
+			got:  NewOrderEvent(EventOrderDeleted, order),
+			want: with(EventOrderDeleted, func(e *OrderEvent) { e.Items = items }),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uuid.Parse(tt.got.EventID)
+			require.NoError(t, err, "each event gets a UUID")
+			assert.WithinDuration(t, time.Now(), tt.got.OccurredAt, time.Second)
+			require.NoError(t, tt.got.Validate())
+
+			// Per-event values, checked above
+			tt.got.EventID, tt.got.OccurredAt = "", time.Time{}
+			assert.Equal(t, tt.want, tt.got)
+		})
+	}
+}
+
+func TestNewSnapshotEvent_FullStateAsOfLastChange(t *testing.T) {
+	order := newTestOrder()
+	order.Status = domain.OrderStatusCancelled
+	order.CancelReason = "payment declined"
+	order.UpdatedAt = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	evt := NewSnapshotEvent(order)
+
+	require.NoError(t, evt.Validate())
+	assert.Equal(t, EventOrderSnapshot, evt.EventType)
+	assert.Equal(t, domain.OrderStatusCancelled, evt.Status)
+	assert.Equal(t, "payment declined", evt.CancelReason)
+	assert.Len(t, evt.Items, len(order.Items))
+	assert.Equal(t, order.UpdatedAt, evt.OccurredAt)
+}
+
+func TestOrderEvent_JSON_EmptyContents_OmitsFields(t *testing.T) {
+	evt := OrderEvent{EventType: EventOrderStatusChanged, OrderID: "o-1"}
+
+	data, err := json.Marshal(evt)
+	require.NoError(t, err)
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.NotContains(t, raw, "items")
+	assert.NotContains(t, raw, "shipping_address")
+}
+
+func TestOrderEvent_JSON_PopulatedContents_RoundTrips(t *testing.T) {
+	evt := NewOrderEvent(EventOrderCreated, newTestOrder())
+	evt.ShippingAddress = &AddressEvent{
+		Line1:      "1 Main St",
+		City:       "Springfield",
+		PostalCode: "12345",
+		Country:    "US",
+	}
+
+	data, err := json.Marshal(evt)
+	require.NoError(t, err)
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	items, ok := raw["items"].([]any)
+	require.True(t, ok, "items should serialize as an array")
+	assert.Len(t, items, 2)
+	assert.Equal(t, "p-1", items[0].(map[string]any)["sku"])
+	assert.Equal(t, 10.50, items[0].(map[string]any)["unit_price"])
+
+	var decoded OrderEvent
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, evt.Items, decoded.Items)
+	assert.Equal(t, evt.ShippingAddress, decoded.ShippingAddress)
+}
@@ -0,0 +1,139 @@
+// Package fallback provides an EventPublisher decorator that spills events
+// to the outbox while the broker is unavailable, so an outage delays their
+// delivery instead of failing the request that published them.
+package fallback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/outbox"
+)
+
+// Option configures a Publisher created by Wrap.
+type Option func(*Publisher)
+
+// WithLogger logs every event enqueued in the outbox to logger at warn
+// level. By default spilled events are not logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(p *Publisher) { p.logger = logger }
+}
+
+var _ messaging.EventPublisher = (*Publisher)(nil)
+
+// Publisher publishes through the wrapped EventPublisher and, when it
+// fails with messaging.ErrBrokerUnavailable, enqueues the event in an
+// outbox store instead; a Relay over the same store sends it once the
+// broker is back. The caller sees the outbox's result, so a publish only
+// fails if the outbox does too. Any other error, such as a
+// *messaging.ValidationError or messaging.ErrSerialization, is returned
+// as it is: the outbox would not make the event deliverable.
+//
+// Both attempts carry the same event ID, so a consumer drops the copy the
+// relay sends if the broker did receive the first. A spilled event may
+// reach consumers after later events of its order that the broker took
+// directly.
+type Publisher struct {
+	next   messaging.EventPublisher
+	outbox *outbox.Publisher
+	logger *slog.Logger
+}
+
+// Wrap returns p decorated to fall back to store when the broker is
+// unavailable.
+func Wrap(p messaging.EventPublisher, store outbox.Store, opts ...Option) messaging.EventPublisher {
+	f := &Publisher{next: p, outbox: outbox.NewPublisher(store)}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Middleware returns Wrap with store and opts as a messaging.Middleware.
+func Middleware(store outbox.Store, opts ...Option) messaging.Middleware {
+	return func(p messaging.EventPublisher) messaging.EventPublisher { return Wrap(p, store, opts...) }
+}
+
+// PublishOrderCreated publishes an order.created event, enqueueing it if
+// the broker is unavailable.
+func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	return p.do(ctx, messaging.EventOrderCreated, order, func(ctx context.Context, pub messaging.EventPublisher) error {
+		return pub.PublishOrderCreated(ctx, order)
+	})
+}
+
+// PublishOrderUpdated publishes an order.updated event, enqueueing it if
+// the broker is unavailable.
+func (p *Publisher) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	return p.do(ctx, messaging.EventOrderUpdated, order, func(ctx context.Context, pub messaging.EventPublisher) error {
+		return pub.PublishOrderUpdated(ctx, order, changes)
+	})
+}
+
+// PublishOrderStatusChanged publishes an order.status_changed event,
+// enqueueing it if the broker is unavailable.
+func (p *Publisher) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
+	return p.do(ctx, messaging.EventOrderStatusChanged, order, func(ctx context.Context, pub messaging.EventPublisher) error {
+		return pub.PublishOrderStatusChanged(ctx, order, oldStatus, newStatus)
+	})
+}
+
+// PublishOrderCancelled publishes an order.cancelled event, enqueueing it
+// if the broker is unavailable.
+func (p *Publisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	return p.do(ctx, messaging.EventOrderCancelled, order, func(ctx context.Context, pub messaging.EventPublisher) error {
+		return pub.PublishOrderCancelled(ctx, order, reason)
+	})
+}
+
+// PublishOrderDeleted publishes an order.deleted event, enqueueing it if
+// the broker is unavailable.
+func (p *Publisher) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	return p.do(ctx, messaging.EventOrderDeleted, order, func(ctx context.Context, pub messaging.EventPublisher) error {
+		return pub.PublishOrderDeleted(ctx, order)
+	})
+}
+
+// PublishOrderExpired publishes an order.expired event, enqueueing it if
+// the broker is unavailable.
+func (p *Publisher) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	return p.do(ctx, messaging.EventOrderExpired, order, func(ctx context.Context, pub messaging.EventPublisher) error {
+		return pub.PublishOrderExpired(ctx, order)
+	})
+}
+
+// PublishOrderSnapshot publishes an order.snapshot event, enqueueing it if
+// the broker is unavailable.
+func (p *Publisher) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	return p.do(ctx, messaging.EventOrderSnapshot, order, func(ctx context.Context, pub messaging.EventPublisher) error {
+		return pub.PublishOrderSnapshot(ctx, order)
+	})
+}
+
+// do runs publish against the wrapped publisher, then against the outbox
+// if the broker was unavailable. The event ID is pinned first so that both
+// build the same event.
+func (p *Publisher) do(ctx context.Context, eventType string, order *domain.Order, publish func(context.Context, messaging.EventPublisher) error) error {
+	if _, ok := messaging.EventID(ctx); !ok {
+		ctx = messaging.WithEventID(ctx, uuid.NewString())
+	}
+	err := publish(ctx, p.next)
+	if err == nil || !errors.Is(err, messaging.ErrBrokerUnavailable) {
+		return err
+	}
+	if oerr := publish(ctx, p.outbox); oerr != nil {
+		return fmt.Errorf("fallback to outbox: %w (broker: %w)", oerr, err)
+	}
+	if p.logger != nil {
+		p.logger.WarnContext(ctx, "broker unavailable, event enqueued in outbox",
+			slog.String("event_type", eventType),
+			slog.String("order_id", order.ID.String()),
+			slog.String("error", err.Error()))
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package fallback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/memory"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/outbox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOrder() *domain.Order {
+	return &domain.Order{ID: uuid.New(), CustomerID: "cust-1", Status: domain.OrderStatusPending, Version: 1, Total: domain.Money{Amount: 1000, Currency: "USD"}}
+}
+
+// memStore is an outbox.Store that keeps records in memory and fails
+// Enqueue with err.
+type memStore struct {
+	outbox.Store
+	mu      sync.Mutex
+	records []outbox.Record
+	err     error
+}
+
+func (s *memStore) Enqueue(_ context.Context, rec outbox.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.records = append(s.records, rec)
+	return nil
+}
+
+// failing is a publisher whose publishes fail with err after noting the
+// event ID they were given.
+type failing struct {
+	messaging.EventPublisher
+	err      error
+	eventIDs []string
+}
+
+func (f *failing) PublishOrderCreated(ctx context.Context, _ *domain.Order) error {
+	id, _ := messaging.EventID(ctx)
+	f.eventIDs = append(f.eventIDs, id)
+	return f.err
+}
+
+func (f *failing) PublishOrderUpdated(ctx context.Context, order *domain.Order, _ map[string]messaging.ChangePair) error {
+	return f.PublishOrderCreated(ctx, order)
+}
+
+func TestPublisher_PrimarySucceeds_OutboxUntouched(t *testing.T) {
+	rec := memory.New()
+	store := &memStore{}
+	pub := Wrap(rec, store)
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newOrder()))
+
+	assert.Len(t, rec.Events(), 1)
+	assert.Empty(t, store.records)
+}
+
+func TestPublisher_BrokerUnavailable_EnqueuesEvent(t *testing.T) {
+	primary := &failing{err: fmt.Errorf("write: %w", messaging.ErrBrokerUnavailable)}
+	store := &memStore{}
+	pub := Wrap(primary, store)
+	order := newOrder()
+	changes := map[string]messaging.ChangePair{"customer_id": {Old: json.RawMessage(`"cust-0"`), New: json.RawMessage(`"cust-1"`)}}
+
+	require.NoError(t, pub.PublishOrderUpdated(context.Background(), order, changes), "the caller does not see the outage")
+
+	require.Len(t, store.records, 1)
+	got := store.records[0]
+	assert.Equal(t, messaging.EventOrderUpdated, got.EventType)
+	assert.Equal(t, order.ID.String(), got.OrderID)
+	require.Len(t, primary.eventIDs, 1)
+	assert.Equal(t, primary.eventIDs[0], got.ID, "both attempts carry the same event ID")
+	var evt messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(got.Payload, &evt))
+	assert.Equal(t, changes, evt.Changes)
+}
+
+func TestPublisher_BrokerUnavailable_KeepsPinnedEventID(t *testing.T) {
+	store := &memStore{}
+	pub := Wrap(&failing{err: messaging.ErrBrokerUnavailable}, store)
+	ctx := messaging.WithEventID(context.Background(), "evt-1")
+
+	require.NoError(t, pub.PublishOrderCreated(ctx, newOrder()))
+
+	require.Len(t, store.records, 1)
+	assert.Equal(t, "evt-1", store.records[0].ID)
+}
+
+func TestPublisher_OtherError_ReturnedWithoutEnqueueing(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"serialization", fmt.Errorf("encode: %w", messaging.ErrSerialization)},
+		{"validation", &messaging.ValidationError{Field: "customer_id"}},
+		{"unclassified", errors.New("boom")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &memStore{}
+			pub := Wrap(&failing{err: tt.err}, store)
+
+			err := pub.PublishOrderCreated(context.Background(), newOrder())
+
+			assert.ErrorIs(t, err, tt.err)
+			assert.Empty(t, store.records)
+		})
+	}
+}
+
+func TestPublisher_OutboxFailsToo_ReturnsBothErrors(t *testing.T) {
+	errDB := errors.New("database down")
+	pub := Wrap(&failing{err: messaging.ErrBrokerUnavailable}, &memStore{err: errDB})
+
+	err := pub.PublishOrderCreated(context.Background(), newOrder())
+
+	assert.ErrorIs(t, err, errDB)
+	assert.ErrorIs(t, err, messaging.ErrBrokerUnavailable)
+}
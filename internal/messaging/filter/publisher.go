@@ -0,0 +1,117 @@
+// Package filter provides an EventPublisher decorator that only forwards
+// the events a predicate accepts, for example to keep an environment from
+// emitting some events to shared topics.
+package filter
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+var _ messaging.EventPublisher = (*Publisher)(nil)
+
+// Predicate reports whether evt should be published.
+type Predicate func(evt messaging.OrderEvent) bool
+
+// Option configures a Publisher created by Wrap.
+type Option func(*Publisher)
+
+// WithLogger logs every dropped event to logger at debug level. By default
+// dropped events are not logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(p *Publisher) { p.logger = logger }
+}
+
+// Publisher forwards to the wrapped EventPublisher, unchanged, only the
+// events its predicate accepts. Dropped events are not an error: the
+// Publish* call returns nil without reaching the wrapped publisher.
+//
+// The predicate sees the event as the messaging constructors build it,
+// correlated with the publish context, before the wrapped publisher builds
+// its own.
+type Publisher struct {
+	next   messaging.EventPublisher
+	keep   Predicate
+	logger *slog.Logger
+}
+
+// Wrap returns p decorated to drop the events keep rejects.
+func Wrap(p messaging.EventPublisher, keep Predicate, opts ...Option) *Publisher {
+	f := &Publisher{next: p, keep: keep}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Middleware returns Wrap with keep and opts as a messaging.Middleware.
+func Middleware(keep Predicate, opts ...Option) messaging.Middleware {
+	return func(p messaging.EventPublisher) messaging.EventPublisher { return Wrap(p, keep, opts...) }
+}
+
+// PublishOrderCreated publishes an order.created event if it is kept.
+func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	return p.filter(ctx, messaging.NewOrderEvent(messaging.EventOrderCreated, order), func() error {
+		return p.next.PublishOrderCreated(ctx, order)
+	})
+}
+
+// PublishOrderUpdated publishes an order.updated event if it is kept.
+func (p *Publisher) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	return p.filter(ctx, messaging.NewUpdatedEvent(order, changes), func() error {
+		return p.next.PublishOrderUpdated(ctx, order, changes)
+	})
+}
+
+// PublishOrderStatusChanged publishes an order.status_changed event if it
+// is kept.
+func (p *Publisher) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
+	return p.filter(ctx, messaging.NewStatusChangedEvent(order, oldStatus, newStatus), func() error {
+		return p.next.PublishOrderStatusChanged(ctx, order, oldStatus, newStatus)
+	})
+}
+
+// PublishOrderCancelled publishes an order.cancelled event if it is kept.
+func (p *Publisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	return p.filter(ctx, messaging.NewCancelledEvent(order, reason), func() error {
+		return p.next.PublishOrderCancelled(ctx, order, reason)
+	})
+}
+
+// PublishOrderDeleted publishes an order.deleted event if it is kept.
+func (p *Publisher) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	return p.filter(ctx, messaging.NewOrderEvent(messaging.EventOrderDeleted, order), func() error {
+		return p.next.PublishOrderDeleted(ctx, order)
+	})
+}
+
+// PublishOrderExpired publishes an order.expired event if it is kept.
+func (p *Publisher) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	return p.filter(ctx, messaging.NewOrderEvent(messaging.EventOrderExpired, order), func() error {
+		return p.next.PublishOrderExpired(ctx, order)
+	})
+}
+
+// PublishOrderSnapshot publishes an order.snapshot event if it is kept.
+func (p *Publisher) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	return p.filter(ctx, messaging.NewSnapshotEvent(order), func() error {
+		return p.next.PublishOrderSnapshot(ctx, order)
+	})
+}
+
+// filter calls publish if the predicate keeps evt.
+func (p *Publisher) filter(ctx context.Context, evt messaging.OrderEvent, publish func() error) error {
+	evt = messaging.Correlate(ctx, evt)
+	if p.keep(evt) {
+		return publish()
+	}
+	if p.logger != nil {
+		p.logger.DebugContext(ctx, "event filtered out",
+			slog.String("event_type", evt.EventType),
+			slog.String("order_id", evt.OrderID))
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package filter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/memory"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func orderWithTotal(amount int64) *domain.Order {
+	return &domain.Order{
+		ID:         uuid.New(),
+		CustomerID: "cust-123",
+		Status:     domain.OrderStatusPending,
+		Total:      domain.Money{Amount: amount, Currency: "USD"},
+		Version:    1,
+	}
+}
+
+// dropSmallUpdates drops order.updated events below $100, keeping the rest.
+func dropSmallUpdates(evt messaging.OrderEvent) bool {
+	return evt.EventType != messaging.EventOrderUpdated || evt.TotalMinor >= 10000
+}
+
+func TestPublisher_DroppedEvents_NotForwarded(t *testing.T) {
+	rec := memory.New()
+	pub := Wrap(rec, dropSmallUpdates)
+	ctx := context.Background()
+	small, large := orderWithTotal(999), orderWithTotal(25000)
+
+	require.NoError(t, pub.PublishOrderUpdated(ctx, small, map[string]messaging.ChangePair{"items": {}}), "dropping is not an error")
+	require.NoError(t, pub.PublishOrderUpdated(ctx, large, map[string]messaging.ChangePair{"items": {}}))
+	require.NoError(t, pub.PublishOrderCreated(ctx, small))
+	require.NoError(t, pub.PublishOrderCancelled(ctx, small, "out of stock"))
+
+	assert.Equal(t, []string{messaging.EventOrderCreated, messaging.EventOrderCancelled}, typesFor(rec, small))
+	assert.Equal(t, []string{messaging.EventOrderUpdated}, typesFor(rec, large))
+}
+
+func typesFor(rec *memory.Publisher, order *domain.Order) []string {
+	var types []string
+	for _, evt := range rec.EventsForOrder(order.ID.String()) {
+		types = append(types, evt.EventType)
+	}
+	return types
+}
+
+func TestPublisher_KeptEvents_ForwardedUnchanged(t *testing.T) {
+	order := orderWithTotal(25000)
+	ctx := messaging.WithRequestID(context.Background(), "req-abc")
+	var gotCtx context.Context
+	var calls []string
+	next := &mocks.EventPublisherMock{
+		PublishOrderCreatedFunc: func(ctx context.Context, o *domain.Order) error {
+			gotCtx = ctx
+			assert.Same(t, order, o)
+			calls = append(calls, "created")
+			return nil
+		},
+		PublishOrderUpdatedFunc: func(_ context.Context, o *domain.Order, changed map[string]messaging.ChangePair) error {
+			assert.Same(t, order, o)
+			assert.Equal(t, map[string]messaging.ChangePair{"customer_id": {}}, changed)
+			calls = append(calls, "updated")
+			return nil
+		},
+		PublishOrderStatusChangedFunc: func(_ context.Context, o *domain.Order, from, to domain.OrderStatus) error {
+			assert.Same(t, order, o)
+			assert.Equal(t, domain.OrderStatusPending, from)
+			assert.Equal(t, domain.OrderStatusConfirmed, to)
+			calls = append(calls, "status_changed")
+			return nil
+		},
+		PublishOrderCancelledFunc: func(_ context.Context, o *domain.Order, reason string) error {
+			assert.Same(t, order, o)
+			assert.Equal(t, "out of stock", reason)
+			calls = append(calls, "cancelled")
+			return nil
+		},
+		PublishOrderDeletedFunc: func(_ context.Context, o *domain.Order) error {
+			assert.Same(t, order, o)
+			calls = append(calls, "deleted")
+			return nil
+		},
+	}
+	pub := Wrap(next, func(messaging.OrderEvent) bool { return true })
+
+	require.NoError(t, pub.PublishOrderCreated(ctx, order))
+	require.NoError(t, pub.PublishOrderUpdated(ctx, order, map[string]messaging.ChangePair{"customer_id": {}}))
+	require.NoError(t, pub.PublishOrderStatusChanged(ctx, order, domain.OrderStatusPending, domain.OrderStatusConfirmed))
+	require.NoError(t, pub.PublishOrderCancelled(ctx, order, "out of stock"))
+	require.NoError(t, pub.PublishOrderDeleted(ctx, order))
+
+	assert.Equal(t, []string{"created", "updated", "status_changed", "cancelled", "deleted"}, calls)
+	id, _ := messaging.RequestID(gotCtx)
+	assert.Equal(t, "req-abc", id, "the publish context is passed through")
+}
+
+func TestPublisher_PredicateSeesTheEvent(t *testing.T) {
+	var seen []messaging.OrderEvent
+	pub := Wrap(memory.New(), func(evt messaging.OrderEvent) bool {
+		seen = append(seen, evt)
+		return true
+	})
+	order := orderWithTotal(500)
+	ctx := messaging.WithRequestID(context.Background(), "req-abc")
+
+	require.NoError(t, pub.PublishOrderStatusChanged(ctx, order, domain.OrderStatusPending, domain.OrderStatusConfirmed))
+	require.NoError(t, pub.PublishOrderCancelled(ctx, order, "fraud"))
+
+	require.Len(t, seen, 2)
+	assert.Equal(t, messaging.EventOrderStatusChanged, seen[0].EventType)
+	assert.Equal(t, domain.OrderStatusConfirmed, seen[0].NewStatus)
+	assert.Equal(t, "req-abc", seen[0].CorrelationID)
+	assert.Equal(t, "fraud", seen[1].CancelReason)
+}
+
+func TestPublisher_WrappedError_Returned(t *testing.T) {
+	errBroker := errors.New("broker unavailable")
+	next := &mocks.EventPublisherMock{
+		PublishOrderDeletedFunc: func(context.Context, *domain.Order) error { return errBroker },
+	}
+
+	err := Wrap(next, func(messaging.OrderEvent) bool { return true }).PublishOrderDeleted(context.Background(), orderWithTotal(100))
+
+	assert.ErrorIs(t, err, errBroker)
+}
+
+func TestPublisher_WithLogger_LogsDroppedEvents(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	pub := Wrap(memory.New(), dropSmallUpdates, WithLogger(logger))
+	order := orderWithTotal(100)
+
+	require.NoError(t, pub.PublishOrderUpdated(context.Background(), order, nil))
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+
+	assert.Contains(t, buf.String(), "event filtered out")
+	assert.Contains(t, buf.String(), "event_type=order.updated")
+	assert.Contains(t, buf.String(), "order_id="+order.ID.String())
+	assert.NotContains(t, buf.String(), "order.created")
+}
@@ -0,0 +1,189 @@
+package messaging
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+)
+
+// Well-known message headers, written by the publishers and read by the
+// consumers. Use them through Headers rather than by name.
+const (
+	// HeaderEventID carries OrderEvent.EventID, so consumers can
+	// deduplicate without decoding the payload.
+	HeaderEventID = "event-id"
+	// HeaderContentType names the serializer's content type, so consumers
+	// can pick the matching decoder.
+	HeaderContentType = "content-type"
+	// HeaderSchemaVersion carries OrderEvent.SchemaVersion, so consumers
+	// can route or reject an envelope version without decoding it.
+	HeaderSchemaVersion = "schema-version"
+
+	// HeaderTenantID and HeaderRequestID are copied from the publish
+	// context, when it carries them (see WithTenantID and WithRequestID).
+	HeaderTenantID  = "tenant-id"
+	HeaderRequestID = "request-id"
+
+	// HeaderEventType and HeaderOrderID carry OrderEvent.EventType and
+	// OrderID on every message, so consumers can route without decoding.
+	HeaderEventType = "event-type"
+	HeaderOrderID   = "order-id"
+
+	// The rest of the event metadata of a tombstone, which has no payload
+	// to carry it.
+	HeaderStatus     = "order-status"
+	HeaderVersion    = "order-version"
+	HeaderOccurredAt = "occurred-at" // RFC 3339 with nanoseconds
+
+	// HeaderTraceParent and HeaderTraceState carry the W3C trace context
+	// of the publish span. The OpenTelemetry propagator sets them.
+	HeaderTraceParent = "traceparent"
+	HeaderTraceState  = "tracestate"
+)
+
+// Headers are the headers of a message, in order, with typed accessors
+// for the well-known ones. Headers it has no accessor for are kept as
+// they are. The zero value is empty and ready to use.
+//
+// *Headers is also an OpenTelemetry propagation.TextMapCarrier, so trace
+// context is injected into and extracted from it directly.
+type Headers struct {
+	list []kafka.Header
+}
+
+// HeadersFromKafka returns a copy of the kafka-go message headers hs.
+func HeadersFromKafka(hs []kafka.Header) Headers {
+	return Headers{list: append([]kafka.Header(nil), hs...)}
+}
+
+// Kafka returns the headers as a kafka-go header slice, for a message.
+func (h Headers) Kafka() []kafka.Header {
+	return append([]kafka.Header(nil), h.list...)
+}
+
+// Get returns the value of the first header named key, or "" if there is
+// none.
+func (h Headers) Get(key string) string {
+	for _, hdr := range h.list {
+		if hdr.Key == key {
+			return string(hdr.Value)
+		}
+	}
+	return ""
+}
+
+// Set replaces the value of the first header named key, or appends one.
+func (h *Headers) Set(key, value string) {
+	for i, hdr := range h.list {
+		if hdr.Key == key {
+			h.list[i].Value = []byte(value)
+			return
+		}
+	}
+	h.list = append(h.list, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+// Del removes every header named key.
+func (h *Headers) Del(key string) {
+	h.list = slices.DeleteFunc(h.list, func(hdr kafka.Header) bool { return hdr.Key == key })
+}
+
+// Keys lists the header names, in order.
+func (h Headers) Keys() []string {
+	keys := make([]string, len(h.list))
+	for i, hdr := range h.list {
+		keys[i] = hdr.Key
+	}
+	return keys
+}
+
+// Len returns the number of headers.
+func (h Headers) Len() int { return len(h.list) }
+
+// EventID returns the HeaderEventID header.
+func (h Headers) EventID() string { return h.Get(HeaderEventID) }
+
+// SetEventID sets the HeaderEventID header.
+func (h *Headers) SetEventID(id string) { h.Set(HeaderEventID, id) }
+
+// ContentType returns the HeaderContentType header.
+func (h Headers) ContentType() string { return h.Get(HeaderContentType) }
+
+// SetContentType sets the HeaderContentType header.
+func (h *Headers) SetContentType(contentType string) { h.Set(HeaderContentType, contentType) }
+
+// SchemaVersion returns the HeaderSchemaVersion header, or an error if it
+// is missing or not a number.
+func (h Headers) SchemaVersion() (int, error) { return h.int(HeaderSchemaVersion) }
+
+// SetSchemaVersion sets the HeaderSchemaVersion header.
+func (h *Headers) SetSchemaVersion(v int) { h.Set(HeaderSchemaVersion, strconv.Itoa(v)) }
+
+// TenantID returns the HeaderTenantID header.
+func (h Headers) TenantID() string { return h.Get(HeaderTenantID) }
+
+// SetTenantID sets the HeaderTenantID header.
+func (h *Headers) SetTenantID(id string) { h.Set(HeaderTenantID, id) }
+
+// RequestID returns the HeaderRequestID header.
+func (h Headers) RequestID() string { return h.Get(HeaderRequestID) }
+
+// SetRequestID sets the HeaderRequestID header.
+func (h *Headers) SetRequestID(id string) { h.Set(HeaderRequestID, id) }
+
+// EventType returns the HeaderEventType header.
+func (h Headers) EventType() string { return h.Get(HeaderEventType) }
+
+// SetEventType sets the HeaderEventType header.
+func (h *Headers) SetEventType(eventType string) { h.Set(HeaderEventType, eventType) }
+
+// OrderID returns the HeaderOrderID header.
+func (h Headers) OrderID() string { return h.Get(HeaderOrderID) }
+
+// SetOrderID sets the HeaderOrderID header.
+func (h *Headers) SetOrderID(id string) { h.Set(HeaderOrderID, id) }
+
+// Status returns the HeaderStatus header.
+func (h Headers) Status() domain.OrderStatus { return domain.OrderStatus(h.Get(HeaderStatus)) }
+
+// SetStatus sets the HeaderStatus header.
+func (h *Headers) SetStatus(s domain.OrderStatus) { h.Set(HeaderStatus, string(s)) }
+
+// Version returns the HeaderVersion header, or an error if it is missing
+// or not a number.
+func (h Headers) Version() (int, error) { return h.int(HeaderVersion) }
+
+// SetVersion sets the HeaderVersion header.
+func (h *Headers) SetVersion(v int) { h.Set(HeaderVersion, strconv.Itoa(v)) }
+
+// OccurredAt returns the HeaderOccurredAt header, or an error if it is
+// missing or not an RFC 3339 time.
+func (h Headers) OccurredAt() (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, h.Get(HeaderOccurredAt))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("header %s: %w", HeaderOccurredAt, err)
+	}
+	return t, nil
+}
+
+// SetOccurredAt sets the HeaderOccurredAt header.
+func (h *Headers) SetOccurredAt(t time.Time) { h.Set(HeaderOccurredAt, t.Format(time.RFC3339Nano)) }
+
+// TraceParent returns the HeaderTraceParent header.
+func (h Headers) TraceParent() string { return h.Get(HeaderTraceParent) }
+
+// TraceState returns the HeaderTraceState header.
+func (h Headers) TraceState() string { return h.Get(HeaderTraceState) }
+
+// int returns the header named key as a number.
+func (h Headers) int(key string) (int, error) {
+	n, err := strconv.Atoi(h.Get(key))
+	if err != nil {
+		return 0, fmt.Errorf("header %s: %w", key, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,101 @@
+package messaging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaders_WellKnown_RoundTripThroughKafka(t *testing.T) {
+	at := time.Date(2026, 3, 14, 10, 0, 0, 123456789, time.UTC)
+	var h Headers
+	h.SetEventID("evt-1")
+	h.SetContentType("application/json")
+	h.SetSchemaVersion(2)
+	h.SetTenantID("tenant-42")
+	h.SetRequestID("req-abc")
+	h.SetEventType(EventOrderCancelled)
+	h.SetOrderID("order-1")
+	h.SetStatus(domain.OrderStatusCancelled)
+	h.SetVersion(7)
+	h.SetOccurredAt(at)
+	h.Set(HeaderTraceParent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.Set(HeaderTraceState, "vendor=value")
+
+	got := HeadersFromKafka(h.Kafka())
+
+	assert.Equal(t, "evt-1", got.EventID())
+	assert.Equal(t, "application/json", got.ContentType())
+	schemaVersion, err := got.SchemaVersion()
+	require.NoError(t, err)
+	assert.Equal(t, 2, schemaVersion)
+	assert.Equal(t, "tenant-42", got.TenantID())
+	assert.Equal(t, "req-abc", got.RequestID())
+	assert.Equal(t, EventOrderCancelled, got.EventType())
+	assert.Equal(t, "order-1", got.OrderID())
+	assert.Equal(t, domain.OrderStatusCancelled, got.Status())
+	version, err := got.Version()
+	require.NoError(t, err)
+	assert.Equal(t, 7, version)
+	occurredAt, err := got.OccurredAt()
+	require.NoError(t, err)
+	assert.True(t, at.Equal(occurredAt))
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", got.TraceParent())
+	assert.Equal(t, "vendor=value", got.TraceState())
+	assert.Equal(t, 12, got.Len())
+}
+
+func TestHeaders_UnknownHeaders_Preserved(t *testing.T) {
+	in := []kafka.Header{
+		{Key: "x-custom", Value: []byte("one")},
+		{Key: HeaderEventID, Value: []byte("evt-1")},
+		{Key: "x-custom", Value: []byte("two")}, // Repeated keys survive too
+		{Key: "x-binary", Value: []byte{0x00, 0xff}},
+	}
+
+	h := HeadersFromKafka(in)
+	h.SetEventID("evt-2")
+	h.SetTenantID("tenant-42")
+
+	assert.Equal(t, []kafka.Header{
+		{Key: "x-custom", Value: []byte("one")},
+		{Key: HeaderEventID, Value: []byte("evt-2")},
+		{Key: "x-custom", Value: []byte("two")},
+		{Key: "x-binary", Value: []byte{0x00, 0xff}},
+		{Key: HeaderTenantID, Value: []byte("tenant-42")},
+	}, h.Kafka())
+	assert.Equal(t, "evt-1", string(in[1].Value), "the kafka-go headers are copied, not changed")
+}
+
+func TestHeaders_Del_RemovesEveryHeaderNamed(t *testing.T) {
+	h := HeadersFromKafka([]kafka.Header{
+		{Key: "x-custom", Value: []byte("one")},
+		{Key: HeaderEventID, Value: []byte("evt-1")},
+		{Key: "x-custom", Value: []byte("two")},
+	})
+
+	h.Del("x-custom")
+
+	assert.Equal(t, []string{HeaderEventID}, h.Keys())
+	assert.Empty(t, h.Get("x-custom"))
+}
+
+func TestHeaders_MissingOrInvalidTypedValue_ReturnsError(t *testing.T) {
+	var h Headers
+	assert.Empty(t, h.EventID())
+	assert.Nil(t, h.Kafka())
+	_, err := h.Version()
+	assert.ErrorContains(t, err, HeaderVersion)
+
+	h.Set(HeaderSchemaVersion, "two")
+	_, err = h.SchemaVersion()
+	assert.ErrorContains(t, err, HeaderSchemaVersion)
+
+	h.Set(HeaderOccurredAt, "yesterday")
+	_, err = h.OccurredAt()
+	assert.ErrorContains(t, err, HeaderOccurredAt)
+}
@@ -0,0 +1,17 @@
+package messaging
+
+import (
+	_ "embed"
+	"slices"
+)
+
+//go:embed order_event.schema.json
+var orderEventJSONSchema []byte
+
+// JSONSchema returns the JSON Schema (draft 2020-12) of the OrderEvent
+// JSON encoding, for consumers that validate events without this package.
+// Its required fields and bounds match Validate. The returned slice is a
+// copy the caller may modify.
+func JSONSchema() []byte {
+	return slices.Clone(orderEventJSONSchema)
+}
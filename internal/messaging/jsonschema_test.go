@@ -0,0 +1,119 @@
+package messaging
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type objectSchema struct {
+	Required   []string                   `json:"required"`
+	Properties map[string]json.RawMessage `json:"properties"`
+	Defs       map[string]objectSchema    `json:"$defs"`
+	Then       struct {
+		Required []string `json:"required"`
+	} `json:"then"`
+}
+
+func parseJSONSchema(t *testing.T) objectSchema {
+	t.Helper()
+	var s objectSchema
+	require.NoError(t, json.Unmarshal(JSONSchema(), &s))
+	return s
+}
+
+// jsonFields returns the JSON names of typ's exported fields.
+func jsonFields(typ reflect.Type) []string {
+	var names []string
+	for _, f := range reflect.VisibleFields(typ) {
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func TestJSONSchema_CoversEveryField(t *testing.T) {
+	s := parseJSONSchema(t)
+
+	tests := []struct {
+		name   string
+		typ    reflect.Type
+		schema objectSchema
+	}{
+		{"OrderEvent", reflect.TypeFor[OrderEvent](), s},
+		{"OrderLineEvent", reflect.TypeFor[OrderLineEvent](), s.Defs["OrderLineEvent"]},
+		{"AddressEvent", reflect.TypeFor[AddressEvent](), s.Defs["AddressEvent"]},
+		{"ChangePair", reflect.TypeFor[ChangePair](), s.Defs["ChangePair"]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := jsonFields(tt.typ)
+			require.NotEmpty(t, fields)
+			for _, name := range fields {
+				assert.Contains(t, tt.schema.Properties, name, "field %s is missing from the schema", name)
+			}
+			assert.Len(t, tt.schema.Properties, len(fields), "the schema describes only fields of %s", tt.name)
+		})
+	}
+}
+
+func TestJSONSchema_RequiredMatchesValidate(t *testing.T) {
+	s := parseJSONSchema(t)
+
+	// requiredBy returns the fields whose absence from evt's JSON fails
+	// Validate
+	requiredBy := func(evt OrderEvent) []string {
+		raw, err := json.Marshal(evt)
+		require.NoError(t, err)
+		var doc map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(raw, &doc))
+
+		var required []string
+		for name := range doc {
+			without := map[string]json.RawMessage{}
+			for k, v := range doc {
+				if k != name {
+					without[k] = v
+				}
+			}
+			raw, err := json.Marshal(without)
+			require.NoError(t, err)
+			var got OrderEvent
+			require.NoError(t, json.Unmarshal(raw, &got))
+
+			var verr *ValidationError
+			if err := got.Validate(); errors.As(err, &verr) {
+				assert.Equal(t, name, verr.Field)
+				required = append(required, name)
+			}
+		}
+		slices.Sort(required)
+		return required
+	}
+
+	evt := NewOrderEvent(EventOrderCreated, newTestOrder())
+	assert.Equal(t, slices.Sorted(slices.Values(s.Required)), requiredBy(evt))
+
+	changed := NewStatusChangedEvent(newTestOrder(), domain.OrderStatusPending, domain.OrderStatusConfirmed)
+	want := slices.Sorted(slices.Values(append(slices.Clone(s.Required), s.Then.Required...)))
+	assert.Equal(t, want, requiredBy(changed), "old_status and new_status are required when the event type is status_changed")
+}
+
+func TestJSONSchema_ReturnsCopy(t *testing.T) {
+	JSONSchema()[0] = 'x'
+
+	assert.True(t, json.Valid(JSONSchema()))
+}
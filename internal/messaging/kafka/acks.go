@@ -0,0 +1,85 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Acks is how many brokers must store a message before a write succeeds.
+//
+// Fewer acks make writes faster but can lose messages: with AcksLeader a
+// message is lost if the leader fails before its followers copy it, and
+// with AcksNone the publisher never learns whether the broker stored the
+// message at all, so WithRetry cannot retry lost writes.
+//
+// More acks do not prevent duplicates. kafka-go has no idempotent
+// producer, so a write retried by WithRetry after its acknowledgement was
+// lost is stored twice; consumers deduplicate by EventID (see package
+// dedupe). kafka-go writes one batch per partition at a time, so retries
+// never reorder a partition's messages.
+type Acks int
+
+// Supported acknowledgement levels.
+const (
+	AcksNone Acks = iota
+	AcksLeader
+	// AcksAll requires every in-sync replica to store the message. It is
+	// the default.
+	AcksAll
+)
+
+// ErrUnknownAcks is returned by New when WithAcks was given a value that
+// is not one of the Acks constants, and by ParseAcks for an unknown name.
+var ErrUnknownAcks = errors.New("kafka: unknown acks level")
+
+// ParseAcks returns the level named name: none, leader or all in any
+// case, or the equivalent Kafka producer setting 0, 1 or -1.
+func ParseAcks(name string) (Acks, error) {
+	switch strings.ToLower(name) {
+	case "none", "0":
+		return AcksNone, nil
+	case "leader", "1":
+		return AcksLeader, nil
+	case "all", "-1":
+		return AcksAll, nil
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownAcks, name)
+}
+
+func (a Acks) String() string {
+	switch a {
+	case AcksNone:
+		return "none"
+	case AcksLeader:
+		return "leader"
+	case AcksAll:
+		return "all"
+	default:
+		return fmt.Sprintf("Acks(%d)", int(a))
+	}
+}
+
+// requiredAcks returns the kafka-go setting for a.
+func (a Acks) requiredAcks() (kafka.RequiredAcks, error) {
+	switch a {
+	case AcksNone:
+		return kafka.RequireNone, nil
+	case AcksLeader:
+		return kafka.RequireOne, nil
+	case AcksAll:
+		return kafka.RequireAll, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnknownAcks, a)
+	}
+}
+
+// WithAcks sets how many brokers must store each message before a publish
+// succeeds. Defaults to AcksAll, so a published event survives the loss of
+// its partition leader. New returns ErrUnknownAcks for a value that is not
+// one of the Acks constants.
+func WithAcks(a Acks) Option {
+	return func(o *options) { o.acks = a }
+}
@@ -0,0 +1,67 @@
+package kafka
+
+import (
+	"testing"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_WithAcks_ConfiguresWriter(t *testing.T) {
+	tests := []struct {
+		acks Acks
+		want kafkago.RequiredAcks
+	}{
+		{AcksNone, kafkago.RequireNone},
+		{AcksLeader, kafkago.RequireOne},
+		{AcksAll, kafkago.RequireAll},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acks.String(), func(t *testing.T) {
+			pub := mustNew(t, WithAcks(tt.acks))
+
+			writer, ok := pub.writer.(*kafkago.Writer)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, writer.RequiredAcks)
+		})
+	}
+
+	writer := mustNew(t).writer.(*kafkago.Writer)
+	assert.Equal(t, kafkago.RequireAll, writer.RequiredAcks, "all by default")
+}
+
+func TestNew_UnknownAcks_ReturnsError(t *testing.T) {
+	for _, opt := range []Option{WithAcks(Acks(42)), WithRequiredAcks(kafkago.RequiredAcks(2))} {
+		_, err := New([]string{"localhost:9092"}, "order-events", opt)
+
+		assert.ErrorIs(t, err, ErrUnknownAcks)
+	}
+}
+
+func TestParseAcks(t *testing.T) {
+	tests := []struct {
+		name string
+		want Acks
+	}{
+		{"none", AcksNone},
+		{"0", AcksNone},
+		{"Leader", AcksLeader},
+		{"1", AcksLeader},
+		{"all", AcksAll},
+		{"-1", AcksAll},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAcks(tt.name)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := ParseAcks("quorum")
+	assert.ErrorIs(t, err, ErrUnknownAcks)
+}
@@ -0,0 +1,289 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// ErrGroupActive is returned by ResetOffsets for a consumer group that
+// still has members: their next commit would overwrite the reset.
+var ErrGroupActive = errors.New("kafka: consumer group has active members")
+
+// groupClient is the subset of *kafka.Client used by Admin.
+type groupClient interface {
+	Metadata(ctx context.Context, req *kafka.MetadataRequest) (*kafka.MetadataResponse, error)
+	ListOffsets(ctx context.Context, req *kafka.ListOffsetsRequest) (*kafka.ListOffsetsResponse, error)
+	OffsetFetch(ctx context.Context, req *kafka.OffsetFetchRequest) (*kafka.OffsetFetchResponse, error)
+	OffsetCommit(ctx context.Context, req *kafka.OffsetCommitRequest) (*kafka.OffsetCommitResponse, error)
+	DescribeGroups(ctx context.Context, req *kafka.DescribeGroupsRequest) (*kafka.DescribeGroupsResponse, error)
+}
+
+// Admin reports and resets the offsets of the consumer groups reading a
+// topic, so a Consumer can be operated without the Kafka CLI tools.
+type Admin struct {
+	client groupClient
+}
+
+// NewAdmin creates an Admin for the cluster at brokers.
+func NewAdmin(brokers []string) *Admin {
+	return &Admin{client: &kafka.Client{Addr: kafka.TCP(brokers...)}}
+}
+
+// OffsetReset is where ResetOffsets moves a group's offsets to: one of
+// ResetToEarliest, ResetToLatest or ResetToTime.
+type OffsetReset struct {
+	latest bool
+	at     time.Time // Zero for the earliest or latest offset
+}
+
+var (
+	// ResetToEarliest reprocesses every message still on the topic.
+	ResetToEarliest = OffsetReset{}
+	// ResetToLatest skips every message already on the topic.
+	ResetToLatest = OffsetReset{latest: true}
+)
+
+// ResetToTime reprocesses the messages written at or after t. Partitions
+// with no such message are reset to their latest offset.
+func ResetToTime(t time.Time) OffsetReset {
+	return OffsetReset{at: t}
+}
+
+// String returns "earliest", "latest", or the time of a ResetToTime.
+func (r OffsetReset) String() string {
+	switch {
+	case r.latest:
+		return "latest"
+	case r.at.IsZero():
+		return "earliest"
+	default:
+		return r.at.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+// PartitionLag is how far a consumer group is behind on one partition.
+type PartitionLag struct {
+	Partition int
+	Committed int64 // Next offset the group reads; -1 if it has committed none
+	End       int64 // Offset the next message written will get
+	Lag       int64 // Messages written the group has not yet read
+}
+
+// TotalLag returns the lag summed over partitions.
+func TotalLag(partitions []PartitionLag) int64 {
+	var total int64
+	for _, p := range partitions {
+		total += p.Lag
+	}
+	return total
+}
+
+// ConsumerLag returns the lag of group on each partition of topic, by
+// partition. A partition the group has committed no offset on lags by
+// every message still on it, as a Consumer starts from the earliest.
+func (a *Admin) ConsumerLag(ctx context.Context, group, topic string) ([]PartitionLag, error) {
+	partitions, err := a.partitions(ctx, topic)
+	if err != nil {
+		return nil, fmt.Errorf("consumer lag of %s on %s: %w", group, topic, err)
+	}
+	committed, err := a.committed(ctx, group, topic, partitions)
+	if err != nil {
+		return nil, fmt.Errorf("consumer lag of %s on %s: %w", group, topic, err)
+	}
+	bounds, err := a.listOffsets(ctx, topic, partitions, kafka.FirstOffsetOf, kafka.LastOffsetOf)
+	if err != nil {
+		return nil, fmt.Errorf("consumer lag of %s on %s: %w", group, topic, err)
+	}
+
+	lags := make([]PartitionLag, len(partitions))
+	for i, p := range partitions {
+		lag := PartitionLag{Partition: p, Committed: committed[p], End: bounds[p].LastOffset}
+		from := lag.Committed
+		if from < 0 {
+			from = bounds[p].FirstOffset
+		}
+		// A committed offset below the earliest is of deleted messages
+		lag.Lag = max(lag.End-max(from, bounds[p].FirstOffset), 0)
+		lags[i] = lag
+	}
+	return lags, nil
+}
+
+// ResetOffsets commits the offset to of every partition of topic for
+// group, and returns the new offsets by partition. It fails with
+// ErrGroupActive, committing nothing, while the group has members, so
+// stop its consumers first.
+func (a *Admin) ResetOffsets(ctx context.Context, group, topic string, to OffsetReset) (map[int]int64, error) {
+	if err := a.checkInactive(ctx, group); err != nil {
+		return nil, fmt.Errorf("reset offsets of %s on %s: %w", group, topic, err)
+	}
+	partitions, err := a.partitions(ctx, topic)
+	if err != nil {
+		return nil, fmt.Errorf("reset offsets of %s on %s: %w", group, topic, err)
+	}
+	offsets, err := a.resetTargets(ctx, topic, partitions, to)
+	if err != nil {
+		return nil, fmt.Errorf("reset offsets of %s on %s to %s: %w", group, topic, to, err)
+	}
+
+	commits := make([]kafka.OffsetCommit, 0, len(partitions))
+	for _, p := range partitions {
+		commits = append(commits, kafka.OffsetCommit{Partition: p, Offset: offsets[p]})
+	}
+	// Generation -1 and no member ID commit as an admin, outside the group
+	resp, err := a.client.OffsetCommit(ctx, &kafka.OffsetCommitRequest{
+		GroupID:      group,
+		GenerationID: -1,
+		Topics:       map[string][]kafka.OffsetCommit{topic: commits},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reset offsets of %s on %s: %w", group, topic, err)
+	}
+	for _, p := range resp.Topics[topic] {
+		if p.Error != nil {
+			return nil, fmt.Errorf("reset offsets of %s on %s: partition %d: %w", group, topic, p.Partition, p.Error)
+		}
+	}
+	return offsets, nil
+}
+
+// checkInactive returns an error wrapping ErrGroupActive if group has
+// members.
+func (a *Admin) checkInactive(ctx context.Context, group string) error {
+	resp, err := a.client.DescribeGroups(ctx, &kafka.DescribeGroupsRequest{GroupIDs: []string{group}})
+	if err != nil {
+		return err
+	}
+	for _, g := range resp.Groups {
+		if g.GroupID != group {
+			continue
+		}
+		if g.Error != nil {
+			return g.Error
+		}
+		if len(g.Members) > 0 {
+			return fmt.Errorf("%w: %d members, state %s", ErrGroupActive, len(g.Members), g.GroupState)
+		}
+	}
+	return nil
+}
+
+// resetTargets returns the offset to resets each of partitions to.
+func (a *Admin) resetTargets(ctx context.Context, topic string, partitions []int, to OffsetReset) (map[int]int64, error) {
+	// A ResetToTime partition with no message that recent gets the latest
+	latest := to.latest || !to.at.IsZero()
+	bound := kafka.FirstOffsetOf
+	if latest {
+		bound = kafka.LastOffsetOf
+	}
+	bounds, err := a.listOffsets(ctx, topic, partitions, bound)
+	if err != nil {
+		return nil, err
+	}
+	offsets := make(map[int]int64, len(partitions))
+	for _, p := range partitions {
+		if latest {
+			offsets[p] = bounds[p].LastOffset
+		} else {
+			offsets[p] = bounds[p].FirstOffset
+		}
+	}
+	if !to.latest && !to.at.IsZero() {
+		found, err := a.listOffsets(ctx, topic, partitions, func(p int) kafka.OffsetRequest {
+			return kafka.TimeOffsetOf(p, to.at)
+		})
+		if err != nil {
+			return nil, err
+		}
+		for p, po := range found {
+			for offset := range po.Offsets {
+				if offset >= 0 { // -1 when there is none
+					offsets[p] = offset
+				}
+			}
+		}
+	}
+	return offsets, nil
+}
+
+// partitions returns the partition IDs of topic, in order.
+func (a *Admin) partitions(ctx context.Context, topic string) ([]int, error) {
+	resp, err := a.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range resp.Topics {
+		if t.Name != topic {
+			continue
+		}
+		if t.Error != nil {
+			return nil, t.Error
+		}
+		ids := make([]int, len(t.Partitions))
+		for i, p := range t.Partitions {
+			ids[i] = p.ID
+		}
+		slices.Sort(ids)
+		return ids, nil
+	}
+	return nil, kafka.UnknownTopicOrPartition
+}
+
+// committed returns the offset group has committed on each of partitions,
+// -1 for none.
+func (a *Admin) committed(ctx context.Context, group, topic string, partitions []int) (map[int]int64, error) {
+	resp, err := a.client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{
+		GroupID: group,
+		Topics:  map[string][]int{topic: partitions},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+	committed := make(map[int]int64, len(partitions))
+	for _, p := range partitions {
+		committed[p] = -1
+	}
+	for _, p := range resp.Topics[topic] {
+		if p.Error != nil {
+			return nil, fmt.Errorf("partition %d: %w", p.Partition, p.Error)
+		}
+		committed[p.Partition] = p.CommittedOffset
+	}
+	return committed, nil
+}
+
+// listOffsets looks up the offsets each of at asks for on every one of
+// partitions, by partition.
+func (a *Admin) listOffsets(ctx context.Context, topic string, partitions []int, at ...func(partition int) kafka.OffsetRequest) (map[int]kafka.PartitionOffsets, error) {
+	reqs := make([]kafka.OffsetRequest, 0, len(partitions)*len(at))
+	for _, p := range partitions {
+		for _, fn := range at {
+			reqs = append(reqs, fn(p))
+		}
+	}
+	resp, err := a.client.ListOffsets(ctx, &kafka.ListOffsetsRequest{Topics: map[string][]kafka.OffsetRequest{topic: reqs}})
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[int]kafka.PartitionOffsets, len(partitions))
+	for _, po := range resp.Topics[topic] {
+		if po.Error != nil {
+			return nil, fmt.Errorf("partition %d: %w", po.Partition, po.Error)
+		}
+		out[po.Partition] = po
+	}
+	for _, p := range partitions {
+		if _, ok := out[p]; !ok {
+			return nil, fmt.Errorf("partition %d: no offsets returned", p)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,235 @@
+package kafka
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubPartition holds the offsets of one partition of the stub topic.
+type stubPartition struct {
+	first int64       // Earliest offset still on the partition
+	times []time.Time // Write time of each offset from first
+}
+
+func (p *stubPartition) end() int64 { return p.first + int64(len(p.times)) }
+
+// stubCluster is an in-memory broker stub for Admin, holding one topic
+// and the offsets one group committed on it.
+type stubCluster struct {
+	mu         sync.Mutex
+	topic      string
+	partitions map[int]*stubPartition
+	committed  map[int]int64
+	members    int
+	commits    int // OffsetCommit requests served
+}
+
+func newStubCluster(topic string, partitions int) *stubCluster {
+	c := &stubCluster{topic: topic, partitions: map[int]*stubPartition{}, committed: map[int]int64{}}
+	for p := range partitions {
+		c.partitions[p] = &stubPartition{}
+	}
+	return c
+}
+
+// produce appends n messages written at at to partition.
+func (c *stubCluster) produce(partition, n int, at time.Time) {
+	p := c.partitions[partition]
+	for range n {
+		p.times = append(p.times, at)
+	}
+}
+
+// expire drops the partition's messages below offset, as retention does.
+func (c *stubCluster) expire(partition int, offset int64) {
+	p := c.partitions[partition]
+	p.times = p.times[offset-p.first:]
+	p.first = offset
+}
+
+func (c *stubCluster) Metadata(_ context.Context, req *kafkago.MetadataRequest) (*kafkago.MetadataResponse, error) {
+	resp := &kafkago.MetadataResponse{}
+	for _, name := range req.Topics {
+		if name != c.topic {
+			resp.Topics = append(resp.Topics, kafkago.Topic{Name: name, Error: kafkago.UnknownTopicOrPartition})
+			continue
+		}
+		t := kafkago.Topic{Name: name}
+		for id := range c.partitions {
+			t.Partitions = append(t.Partitions, kafkago.Partition{Topic: name, ID: id})
+		}
+		resp.Topics = append(resp.Topics, t)
+	}
+	return resp, nil
+}
+
+func (c *stubCluster) ListOffsets(_ context.Context, req *kafkago.ListOffsetsRequest) (*kafkago.ListOffsetsResponse, error) {
+	byPartition := map[int]kafkago.PartitionOffsets{}
+	for _, r := range req.Topics[c.topic] {
+		p := c.partitions[r.Partition]
+		po, ok := byPartition[r.Partition]
+		if !ok {
+			po = kafkago.PartitionOffsets{Partition: r.Partition, FirstOffset: -1, LastOffset: -1, Offsets: map[int64]time.Time{}}
+		}
+		switch r.Timestamp {
+		case kafkago.FirstOffset:
+			po.FirstOffset = p.first
+		case kafkago.LastOffset:
+			po.LastOffset = p.end()
+		default:
+			found := int64(-1)
+			for i, at := range p.times {
+				if at.UnixMilli() >= r.Timestamp {
+					found = p.first + int64(i)
+					break
+				}
+			}
+			po.Offsets[found] = time.UnixMilli(r.Timestamp)
+		}
+		byPartition[r.Partition] = po
+	}
+	resp := &kafkago.ListOffsetsResponse{Topics: map[string][]kafkago.PartitionOffsets{}}
+	for _, po := range byPartition {
+		resp.Topics[c.topic] = append(resp.Topics[c.topic], po)
+	}
+	return resp, nil
+}
+
+func (c *stubCluster) OffsetFetch(_ context.Context, req *kafkago.OffsetFetchRequest) (*kafkago.OffsetFetchResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := &kafkago.OffsetFetchResponse{Topics: map[string][]kafkago.OffsetFetchPartition{}}
+	for _, p := range req.Topics[c.topic] {
+		offset, ok := c.committed[p]
+		if !ok {
+			offset = -1
+		}
+		resp.Topics[c.topic] = append(resp.Topics[c.topic], kafkago.OffsetFetchPartition{Partition: p, CommittedOffset: offset})
+	}
+	return resp, nil
+}
+
+func (c *stubCluster) OffsetCommit(_ context.Context, req *kafkago.OffsetCommitRequest) (*kafkago.OffsetCommitResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.commits++
+	resp := &kafkago.OffsetCommitResponse{Topics: map[string][]kafkago.OffsetCommitPartition{}}
+	for _, oc := range req.Topics[c.topic] {
+		c.committed[oc.Partition] = oc.Offset
+		resp.Topics[c.topic] = append(resp.Topics[c.topic], kafkago.OffsetCommitPartition{Partition: oc.Partition})
+	}
+	return resp, nil
+}
+
+func (c *stubCluster) DescribeGroups(_ context.Context, req *kafkago.DescribeGroupsRequest) (*kafkago.DescribeGroupsResponse, error) {
+	resp := &kafkago.DescribeGroupsResponse{}
+	for _, id := range req.GroupIDs {
+		g := kafkago.DescribeGroupsResponseGroup{GroupID: id, GroupState: "Empty"}
+		if c.members > 0 {
+			g.GroupState = "Stable"
+			g.Members = make([]kafkago.DescribeGroupsResponseMember, c.members)
+		}
+		resp.Groups = append(resp.Groups, g)
+	}
+	return resp, nil
+}
+
+func TestAdmin_ConsumerLag_KnownState(t *testing.T) {
+	at := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	cluster := newStubCluster("order-events", 4)
+	cluster.produce(0, 10, at)
+	cluster.committed[0] = 4 // Read 0-3
+	cluster.produce(1, 5, at)
+	cluster.committed[1] = 5 // Caught up
+	cluster.produce(2, 8, at)
+	cluster.expire(2, 3) // No commit: lags by the 5 messages left
+	cluster.produce(3, 8, at)
+	cluster.expire(3, 6)
+	cluster.committed[3] = 2 // Behind retention: only 6 and 7 are left to read
+
+	lags, err := (&Admin{client: cluster}).ConsumerLag(context.Background(), "warehouse", "order-events")
+
+	require.NoError(t, err)
+	assert.Equal(t, []PartitionLag{
+		{Partition: 0, Committed: 4, End: 10, Lag: 6},
+		{Partition: 1, Committed: 5, End: 5, Lag: 0},
+		{Partition: 2, Committed: -1, End: 8, Lag: 5},
+		{Partition: 3, Committed: 2, End: 8, Lag: 2},
+	}, lags)
+	assert.EqualValues(t, 13, TotalLag(lags))
+}
+
+func TestAdmin_ResetOffsets(t *testing.T) {
+	t0 := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		to      OffsetReset
+		want    map[int]int64
+		wantLag int64
+	}{
+		{name: "earliest", to: ResetToEarliest, want: map[int]int64{0: 2, 1: 0}, wantLag: 6 + 3},
+		{name: "latest", to: ResetToLatest, want: map[int]int64{0: 8, 1: 3}, wantLag: 0},
+		// Partition 0 has messages from t0+1h on offsets 5-7; partition 1
+		// has none that recent
+		{name: "time", to: ResetToTime(t0.Add(time.Hour)), want: map[int]int64{0: 5, 1: 3}, wantLag: 3},
+		{name: "time_before_all", to: ResetToTime(t0.Add(-time.Hour)), want: map[int]int64{0: 2, 1: 0}, wantLag: 6 + 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := newStubCluster("order-events", 2)
+			cluster.produce(0, 5, t0)
+			cluster.produce(0, 3, t0.Add(time.Hour))
+			cluster.expire(0, 2)
+			cluster.produce(1, 3, t0)
+			cluster.committed[0], cluster.committed[1] = 7, 1
+			admin := &Admin{client: cluster}
+
+			got, err := admin.ResetOffsets(context.Background(), "warehouse", "order-events", tt.to)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want, cluster.committed)
+			lags, err := admin.ConsumerLag(context.Background(), "warehouse", "order-events")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLag, TotalLag(lags))
+		})
+	}
+}
+
+func TestAdmin_ResetOffsets_ActiveGroup_CommitsNothing(t *testing.T) {
+	cluster := newStubCluster("order-events", 1)
+	cluster.produce(0, 3, time.Now())
+	cluster.committed[0] = 3
+	cluster.members = 2
+
+	_, err := (&Admin{client: cluster}).ResetOffsets(context.Background(), "warehouse", "order-events", ResetToEarliest)
+
+	require.ErrorIs(t, err, ErrGroupActive)
+	assert.ErrorContains(t, err, "warehouse")
+	assert.ErrorContains(t, err, "2 members")
+	assert.Zero(t, cluster.commits)
+	assert.Equal(t, int64(3), cluster.committed[0])
+}
+
+func TestAdmin_UnknownTopic_ReturnsError(t *testing.T) {
+	admin := &Admin{client: newStubCluster("order-events", 1)}
+
+	_, err := admin.ConsumerLag(context.Background(), "warehouse", "payments")
+	assert.ErrorIs(t, err, kafkago.UnknownTopicOrPartition)
+
+	_, err = admin.ResetOffsets(context.Background(), "warehouse", "payments", ResetToLatest)
+	assert.ErrorIs(t, err, kafkago.UnknownTopicOrPartition)
+}
+
+func TestOffsetReset_String(t *testing.T) {
+	assert.Equal(t, "earliest", ResetToEarliest.String())
+	assert.Equal(t, "latest", ResetToLatest.String())
+	assert.Equal(t, "2026-03-14T10:00:00Z", ResetToTime(time.Date(2026, 3, 14, 11, 0, 0, 0, time.FixedZone("CET", 3600))).String())
+}
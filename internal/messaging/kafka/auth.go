@@ -0,0 +1,99 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+// SASLMechanism names a SASL mechanism the publisher authenticates with.
+type SASLMechanism string
+
+// Supported SASL mechanisms.
+const (
+	SASLPlain       SASLMechanism = "PLAIN"
+	SASLScramSHA256 SASLMechanism = "SCRAM-SHA-256"
+	SASLScramSHA512 SASLMechanism = "SCRAM-SHA-512"
+)
+
+// ErrUnknownSASLMechanism is returned by New when WithSASL was given a
+// mechanism that is not one of the SASLMechanism constants, and by
+// ParseSASLMechanism for an unknown name.
+var ErrUnknownSASLMechanism = errors.New("kafka: unknown SASL mechanism")
+
+// ParseSASLMechanism returns the mechanism named name, in any case.
+func ParseSASLMechanism(name string) (SASLMechanism, error) {
+	for _, m := range []SASLMechanism{SASLPlain, SASLScramSHA256, SASLScramSHA512} {
+		if strings.EqualFold(name, string(m)) {
+			return m, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownSASLMechanism, name)
+}
+
+// saslConfig holds the credentials set with WithSASL.
+type saslConfig struct {
+	mechanism SASLMechanism
+	username  string
+	password  string
+}
+
+// saslMechanism returns the kafka-go mechanism authenticating with c.
+func (c saslConfig) saslMechanism() (sasl.Mechanism, error) {
+	switch c.mechanism {
+	case SASLPlain:
+		return plain.Mechanism{Username: c.username, Password: c.password}, nil
+	case SASLScramSHA256:
+		return scram.Mechanism(scram.SHA256, c.username, c.password)
+	case SASLScramSHA512:
+		return scram.Mechanism(scram.SHA512, c.username, c.password)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSASLMechanism, c.mechanism)
+	}
+}
+
+// WithTLS connects to the brokers over TLS configured by cfg. Defaults to
+// plaintext connections.
+func WithTLS(cfg *tls.Config) Option {
+	return func(o *options) { o.tls = cfg }
+}
+
+// WithSASL authenticates to the brokers with mechanism as username.
+// Defaults to no authentication. Use it with WithTLS: without it the
+// credentials, or with SASLPlain the password itself, cross the network
+// in the clear, and New logs a warning to the WithLogger logger. New
+// returns ErrUnknownSASLMechanism for a mechanism that is not one of the
+// SASLMechanism constants.
+func WithSASL(mechanism SASLMechanism, username, password string) Option {
+	return func(o *options) {
+		o.sasl = &saslConfig{mechanism: mechanism, username: username, password: password}
+	}
+}
+
+// transport returns the transport the publisher's connections use, or nil
+// for kafka-go's default plaintext, unauthenticated transport.
+func (o *options) transport() (*kafka.Transport, error) {
+	if o.tls == nil && o.sasl == nil {
+		return nil, nil
+	}
+	t := &kafka.Transport{TLS: o.tls}
+	if o.sasl != nil {
+		mechanism, err := o.sasl.saslMechanism()
+		if err != nil {
+			return nil, err
+		}
+		t.SASL = mechanism
+		if o.tls == nil {
+			o.logger.Warn("kafka SASL credentials are sent without TLS",
+				slog.String("mechanism", string(o.sasl.mechanism)))
+		}
+	}
+	return t, nil
+}
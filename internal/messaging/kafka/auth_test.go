@@ -0,0 +1,112 @@
+package kafka
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"testing"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writerTransport returns the *kafka.Transport configured on pub's writer.
+func writerTransport(t *testing.T, pub *Publisher) *kafkago.Transport {
+	t.Helper()
+	writer, ok := pub.writer.(*kafkago.Writer)
+	require.True(t, ok)
+	if writer.Transport == nil {
+		return nil
+	}
+	transport, ok := writer.Transport.(*kafkago.Transport)
+	require.True(t, ok)
+	return transport
+}
+
+func TestNew_WithTLSAndSASL_ConfiguresTransport(t *testing.T) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: "broker.internal"}
+	tests := []struct {
+		mechanism SASLMechanism
+		want      string
+	}{
+		{SASLPlain, "PLAIN"},
+		{SASLScramSHA256, "SCRAM-SHA-256"},
+		{SASLScramSHA512, "SCRAM-SHA-512"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			h := &captureHandler{level: slog.LevelWarn}
+			pub := mustNew(t, WithLogger(slog.New(h)), WithTLS(cfg), WithSASL(tt.mechanism, "ordersvc", "s3cret"))
+
+			transport := writerTransport(t, pub)
+			require.NotNil(t, transport)
+			assert.Same(t, cfg, transport.TLS)
+			require.NotNil(t, transport.SASL)
+			assert.Equal(t, tt.want, transport.SASL.Name())
+			assert.Same(t, transport, pub.transport, "Ping uses the same transport")
+			assert.Empty(t, h.records, "no warning over TLS")
+		})
+	}
+}
+
+func TestNew_WithTLSOnly_ConfiguresTransportWithoutSASL(t *testing.T) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	transport := writerTransport(t, mustNew(t, WithTLS(cfg)))
+
+	require.NotNil(t, transport)
+	assert.Same(t, cfg, transport.TLS)
+	assert.Nil(t, transport.SASL)
+}
+
+func TestNew_NoAuth_UsesDefaultTransport(t *testing.T) {
+	pub := mustNew(t)
+
+	assert.Nil(t, writerTransport(t, pub))
+	assert.Nil(t, pub.transport)
+}
+
+func TestNew_SASLWithoutTLS_WarnsButSucceeds(t *testing.T) {
+	h := &captureHandler{level: slog.LevelWarn}
+
+	pub := mustNew(t, WithLogger(slog.New(h)), WithSASL(SASLPlain, "ordersvc", "s3cret"))
+
+	transport := writerTransport(t, pub)
+	require.NotNil(t, transport)
+	assert.Nil(t, transport.TLS)
+	assert.Equal(t, "PLAIN", transport.SASL.Name())
+	require.Len(t, h.records, 1)
+	assert.Equal(t, slog.LevelWarn, h.records[0].Level)
+	assert.Equal(t, map[string]any{"mechanism": "PLAIN"}, attrs(h.records[0]), "credentials are not logged")
+}
+
+func TestNew_UnknownSASLMechanism_ReturnsError(t *testing.T) {
+	_, err := New([]string{"localhost:9092"}, "order-events", WithSASL("GSSAPI", "ordersvc", "s3cret"))
+
+	assert.ErrorIs(t, err, ErrUnknownSASLMechanism)
+}
+
+func TestParseSASLMechanism(t *testing.T) {
+	tests := []struct {
+		name string
+		want SASLMechanism
+	}{
+		{"PLAIN", SASLPlain},
+		{"plain", SASLPlain},
+		{"scram-sha-256", SASLScramSHA256},
+		{"SCRAM-SHA-512", SASLScramSHA512},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSASLMechanism(tt.name)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := ParseSASLMechanism("OAUTHBEARER")
+	assert.ErrorIs(t, err, ErrUnknownSASLMechanism)
+}
@@ -0,0 +1,204 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/retry"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrPrecedingEventFailed is the error of a batch event held back because
+// an earlier event for the same order could not be encoded.
+var ErrPrecedingEventFailed = errors.New("earlier event for the order failed")
+
+// FailedEvent is an event that PublishBatch did not write.
+type FailedEvent struct {
+	Index int // Position of the event in the batch
+	Event messaging.OrderEvent
+	Err   error
+}
+
+// BatchError is returned by PublishBatch when some events were not
+// written, identified by their index in the batch. Events it does not list
+// were written.
+type BatchError struct {
+	Failed []FailedEvent // In batch order
+	Total  int           // Number of events in the batch
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("kafka batch: %d of %d event(s) failed, first at index %d: %v",
+		len(e.Failed), e.Total, e.Failed[0].Index, e.Failed[0].Err)
+}
+
+// Unwrap returns the error of every failed event, so errors.Is and
+// errors.As see through a BatchError.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, f := range e.Failed {
+		errs[i] = f.Err
+	}
+	return errs
+}
+
+// Indexes returns the batch positions of the failed events, ascending.
+func (e *BatchError) Indexes() []int {
+	indexes := make([]int, len(e.Failed))
+	for i, f := range e.Failed {
+		indexes[i] = f.Index
+	}
+	return indexes
+}
+
+// Events returns the failed events in batch order, ready to be retried.
+func (e *BatchError) Events() []messaging.OrderEvent {
+	events := make([]messaging.OrderEvent, len(e.Failed))
+	for i, f := range e.Failed {
+		events[i] = f.Event
+	}
+	return events
+}
+
+// batchEntry tracks one event through PublishBatch.
+type batchEntry struct {
+	evt  messaging.OrderEvent
+	msg  kafka.Message
+	span trace.Span
+	err  error
+}
+
+// PublishBatch writes events to Kafka in a single produce call, keyed like
+// Publish, so events for one order keep their order in the batch.
+//
+// Retries configured with WithRetry resend only the messages that failed.
+// An event that fails validation is not sent, and neither are the later
+// events for its order. If any event is not written, PublishBatch returns
+// a *BatchError listing them. A write can fail partway through an order's
+// events, so retrying the failed ones may deliver them after later events
+// for the same order that succeeded.
+//
+// In ModeAsync PublishBatch returns nil once the batch is enqueued, and a
+// *BatchError is sent on Errors instead. A batch that gives up waiting for
+// WithMaxInFlight returns a *BatchError wrapping ErrTooManyInFlight.
+func (p *Publisher) PublishBatch(ctx context.Context, events []messaging.OrderEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	write, ok := p.drain.add(len(events))
+	if !ok {
+		return failedBatch(events, ErrPublisherClosed)
+	}
+	if p.mode == ModeAsync {
+		keys := make([]string, len(events))
+		for i, evt := range events {
+			keys[i] = p.partitionKeyOf(evt)
+		}
+		err := p.async(ctx, write, keys, func(ctx context.Context) error {
+			return p.writeBatch(ctx, events)
+		})
+		if err != nil {
+			return failedBatch(events, err)
+		}
+		return nil
+	}
+	defer p.drain.done(write)
+	return p.writeBatch(ctx, events)
+}
+
+// failedBatch returns the *BatchError of events none of which were written
+// because of err.
+func failedBatch(events []messaging.OrderEvent, err error) *BatchError {
+	batchErr := &BatchError{Total: len(events)}
+	for i, evt := range events {
+		batchErr.Failed = append(batchErr.Failed, FailedEvent{Index: i, Event: evt,
+			Err: fmt.Errorf("kafka publish %s: %w", evt.EventType, err)})
+	}
+	return batchErr
+}
+
+// writeBatch encodes and writes events in one produce call, returning a
+// *BatchError if any were not written.
+func (p *Publisher) writeBatch(ctx context.Context, events []messaging.OrderEvent) error {
+	start := time.Now()
+
+	entries := make([]batchEntry, len(events))
+	blocked := make(map[string]struct{}) // Orders with an event that could not be encoded
+	var pending []int                    // Indexes of entries still to be written
+	for i, evt := range events {
+		spanCtx, span := p.startPublishSpan(ctx, evt)
+		entries[i] = batchEntry{evt: evt, span: span}
+
+		if _, ok := blocked[evt.OrderID]; ok {
+			entries[i].err = fmt.Errorf("kafka publish %s: %w", evt.EventType, ErrPrecedingEventFailed)
+			continue
+		}
+		msg, err := p.message(spanCtx, evt)
+		if err != nil {
+			entries[i].err = err
+			blocked[evt.OrderID] = struct{}{}
+			continue
+		}
+		entries[i].msg = msg
+		if span.IsRecording() {
+			p.inflight.Store(evt.EventID, span)
+		}
+		pending = append(pending, i)
+	}
+
+	attempts := 0
+	err := retry.Do(ctx, p.retry, func(ctx context.Context) error {
+		if len(pending) == 0 {
+			return nil
+		}
+		attempts++
+		msgs := make([]kafka.Message, len(pending))
+		for j, i := range pending {
+			msgs[j] = entries[i].msg
+		}
+
+		err := p.writeMessages(ctx, msgs...)
+		var writeErrs kafka.WriteErrors
+		perMessage := errors.As(err, &writeErrs) && len(writeErrs) == len(msgs)
+
+		failed := pending[:0]
+		for j, i := range pending {
+			entries[i].err = err
+			if perMessage {
+				entries[i].err = writeErrs[j]
+			}
+			if entries[i].err != nil {
+				failed = append(failed, i)
+			}
+		}
+		pending = failed
+		return err
+	})
+	if err != nil {
+		for _, i := range pending {
+			e := &entries[i]
+			e.err = p.writeFailed(ctx, e.evt, e.msg, e.err, attempts)
+		}
+	}
+
+	// Every event is recorded with the duration of the whole batch
+	elapsed := time.Since(start)
+	batchErr := &BatchError{Total: len(events)}
+	for i, e := range entries {
+		p.inflight.Delete(e.evt.EventID)
+		endSpan(e.span, e.err)
+		p.metrics.ObservePublish(e.evt.EventType, elapsed, e.err)
+		p.logPublish(ctx, e.evt, e.err)
+		if e.err != nil {
+			batchErr.Failed = append(batchErr.Failed, FailedEvent{Index: i, Event: e.evt, Err: e.err})
+		}
+	}
+	if len(batchErr.Failed) > 0 {
+		return batchErr
+	}
+	return nil
+}
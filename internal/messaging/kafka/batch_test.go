@@ -0,0 +1,266 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// partialWriter fails the messages of the listed event IDs with a
+// kafka.WriteErrors, the first failN times each is written.
+type partialWriter struct {
+	mu      sync.Mutex
+	fail    map[string]error // Event ID -> write error
+	failN   int
+	tries   map[string]int
+	calls   [][]kafkago.Message
+	written []kafkago.Message
+}
+
+func (w *partialWriter) WriteMessages(_ context.Context, msgs ...kafkago.Message) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.tries == nil {
+		w.tries = make(map[string]int)
+	}
+	w.calls = append(w.calls, msgs)
+
+	errs := make(kafkago.WriteErrors, len(msgs))
+	failed := false
+	for i, msg := range msgs {
+		id := eventID(msg)
+		w.tries[id]++
+		if err, ok := w.fail[id]; ok && w.tries[id] <= w.failN {
+			errs[i] = err
+			failed = true
+			continue
+		}
+		w.written = append(w.written, msg)
+	}
+	if failed {
+		return errs
+	}
+	return nil
+}
+
+func (w *partialWriter) Close() error { return nil }
+
+func eventID(msg kafkago.Message) string {
+	for _, h := range msg.Headers {
+		if h.Key == HeaderEventID {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// newTestBatch returns n events per order for the given orders,
+// interleaved, with increasing versions per order.
+func newTestBatch(orders, n int) []messaging.OrderEvent {
+	batch := make([]*domain.Order, orders)
+	for o := range batch {
+		batch[o] = newTestOrder()
+	}
+	var events []messaging.OrderEvent
+	for v := 1; v <= n; v++ {
+		for _, order := range batch {
+			order.Version = v
+			events = append(events, messaging.NewOrderEvent(messaging.EventOrderUpdated, order))
+		}
+	}
+	return events
+}
+
+func TestPublisher_PublishBatch_SingleWritePreservingOrder(t *testing.T) {
+	w := &mockWriter{}
+	pub := newTestPublisher(w)
+	events := newTestBatch(3, 2)
+
+	require.NoError(t, pub.PublishBatch(context.Background(), events))
+
+	assert.Equal(t, 1, w.attempts, "batch should be written in one call")
+	require.Len(t, w.messages, len(events))
+	for i, msg := range w.messages {
+		assert.Equal(t, events[i].EventID, eventID(msg))
+		assert.Equal(t, events[i].OrderID, string(msg.Key))
+		assert.Equal(t, "order-events", msg.Topic)
+	}
+}
+
+func TestPublisher_PublishSnapshots_WritesOneBatchKeyedByOrder(t *testing.T) {
+	w := &mockWriter{}
+	pub := newTestPublisher(w)
+	orders := []*domain.Order{newTestOrder(), newTestOrder(), newTestOrder()}
+	orders[1].Version = 4
+
+	require.NoError(t, pub.PublishSnapshots(context.Background(), orders))
+
+	assert.Equal(t, 1, w.attempts, "snapshots should be written in one call")
+	require.Len(t, w.messages, len(orders))
+	for i, msg := range w.messages {
+		var evt messaging.OrderEvent
+		require.NoError(t, json.Unmarshal(msg.Value, &evt))
+		assert.Equal(t, messaging.EventOrderSnapshot, evt.EventType)
+		assert.Equal(t, orders[i].ID.String(), string(msg.Key))
+		assert.Equal(t, orders[i].Version, evt.Version)
+		assert.Len(t, evt.Items, len(orders[i].Items))
+	}
+}
+
+func TestPublisher_PublishBatch_Empty_WritesNothing(t *testing.T) {
+	w := &mockWriter{}
+	pub := newTestPublisher(w)
+
+	require.NoError(t, pub.PublishBatch(context.Background(), nil))
+
+	assert.Zero(t, w.attempts)
+}
+
+func TestPublisher_PublishBatch_PartialFailure_ReturnsFailedEvents(t *testing.T) {
+	events := newTestBatch(4, 1)
+	leaderErr := errors.New("not leader for partition")
+	w := &partialWriter{fail: map[string]error{
+		events[1].EventID: leaderErr,
+		events[3].EventID: leaderErr,
+	}, failN: 1}
+	pub := newTestPublisher(&mockWriter{})
+	pub.writer = w
+
+	err := pub.PublishBatch(context.Background(), events)
+
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 4, batchErr.Total)
+	assert.Equal(t, []messaging.OrderEvent{events[1], events[3]}, batchErr.Events())
+	assert.Equal(t, []int{1, 3}, batchErr.Indexes())
+	assert.ErrorIs(t, err, ErrPublishFailed)
+	assert.ErrorIs(t, batchErr.Failed[0].Err, leaderErr)
+	assert.Len(t, w.written, 2)
+
+	// Retrying just the failed events completes the batch
+	require.NoError(t, pub.PublishBatch(context.Background(), batchErr.Events()))
+	assert.Len(t, w.written, 4)
+}
+
+func TestPublisher_PublishBatch_WithRetry_ResendsOnlyFailed(t *testing.T) {
+	events := newTestBatch(3, 1)
+	w := &partialWriter{fail: map[string]error{events[2].EventID: errors.New("request timed out")}, failN: 2}
+	pub := newTestPublisher(&mockWriter{})
+	pub.writer = w
+	pub.retry = retry.Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	require.NoError(t, pub.PublishBatch(context.Background(), events))
+
+	require.Len(t, w.calls, 3)
+	assert.Len(t, w.calls[0], 3)
+	assert.Len(t, w.calls[1], 1)
+	assert.Equal(t, events[2].EventID, eventID(w.calls[2][0]))
+	assert.Len(t, w.written, 3)
+}
+
+func TestPublisher_PublishBatch_WriterError_FailsEveryEvent(t *testing.T) {
+	brokerErr := errors.New("broker unavailable")
+	w := &mockWriter{err: brokerErr}
+	pub := newTestPublisher(w)
+	events := newTestBatch(2, 1)
+
+	err := pub.PublishBatch(context.Background(), events)
+
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, events, batchErr.Events())
+	assert.ErrorIs(t, err, brokerErr)
+}
+
+func TestPublisher_PublishBatch_InvalidEvent_HoldsBackLaterEventsForOrder(t *testing.T) {
+	w := &mockWriter{}
+	pub := newTestPublisher(w)
+	events := newTestBatch(2, 2) // a1, b1, a2, b2
+	events[0].CustomerID = ""
+
+	err := pub.PublishBatch(context.Background(), events)
+
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	require.Len(t, batchErr.Failed, 2)
+	var verr *messaging.ValidationError
+	assert.ErrorAs(t, batchErr.Failed[0].Err, &verr)
+	assert.Equal(t, events[2], batchErr.Failed[1].Event)
+	assert.ErrorIs(t, batchErr.Failed[1].Err, ErrPrecedingEventFailed)
+
+	require.Len(t, w.messages, 2)
+	assert.Equal(t, events[1].EventID, eventID(w.messages[0]))
+	assert.Equal(t, events[3].EventID, eventID(w.messages[1]))
+}
+
+func TestPublisher_PublishBatch_OneInvalidEvent_ReportsItsIndex(t *testing.T) {
+	w := &mockWriter{}
+	pub := newTestPublisher(w)
+	events := newTestBatch(5, 1)
+	events[3].OrderID = ""
+
+	err := pub.PublishBatch(context.Background(), events)
+
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, []int{3}, batchErr.Indexes())
+	assert.Contains(t, err.Error(), "index 3")
+	var verr *messaging.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "order_id", verr.Field)
+	assert.Len(t, w.messages, 4, "valid events are still written")
+}
+
+// latencyWriter simulates the broker round trip of each produce call.
+type latencyWriter struct{ rtt time.Duration }
+
+func (w latencyWriter) WriteMessages(_ context.Context, _ ...kafkago.Message) error {
+	time.Sleep(w.rtt)
+	return nil
+}
+
+func (latencyWriter) Close() error { return nil }
+
+const benchRTT = 200 * time.Microsecond
+
+func BenchmarkPublisher_Publish_OneByOne(b *testing.B) {
+	pub := newTestPublisher(&mockWriter{})
+	pub.writer = latencyWriter{rtt: benchRTT}
+	events := newTestBatch(100, 1)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, evt := range events {
+			if err := pub.Publish(ctx, evt); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	b.ReportMetric(float64(b.N*len(events))/b.Elapsed().Seconds(), "events/s")
+}
+
+func BenchmarkPublisher_PublishBatch(b *testing.B) {
+	pub := newTestPublisher(&mockWriter{})
+	pub.writer = latencyWriter{rtt: benchRTT}
+	events := newTestBatch(100, 1)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := pub.PublishBatch(ctx, events); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(b.N*len(events))/b.Elapsed().Seconds(), "events/s")
+}
@@ -0,0 +1,89 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// commitTimeout bounds the commit of pending offsets on shutdown, which
+// runs after the consumer's context has ended.
+const commitTimeout = 5 * time.Second
+
+// WithCommitBatch commits offsets once n messages have been handled, or
+// interval after the oldest uncommitted one, whichever comes first, instead
+// of after every message. Offsets are only ever committed up to the last
+// handled message, and pending ones are committed when Run returns, so
+// delivery stays at least once: a crash redelivers at most the batch.
+// n below 1 means 1; a zero interval waits for n messages.
+func WithCommitBatch(n int, interval time.Duration) ConsumerOption {
+	return func(c *Consumer) {
+		c.commitSize = max(n, 1)
+		c.commitInterval = interval
+	}
+}
+
+// offsetBatch holds handled messages whose offsets are not yet committed.
+type offsetBatch struct {
+	reader   messageReader
+	size     int
+	interval time.Duration
+
+	pending []kafka.Message
+	due     time.Time // When pending must be committed, if interval is set
+}
+
+func (c *Consumer) newOffsetBatch() *offsetBatch {
+	return &offsetBatch{reader: c.reader, size: max(c.commitSize, 1), interval: c.commitInterval}
+}
+
+// fetch fetches the next message. If the batch comes due while waiting,
+// it returns errCommitDue instead.
+func (b *offsetBatch) fetch(ctx context.Context) (kafka.Message, error) {
+	if len(b.pending) == 0 || b.interval <= 0 {
+		return b.reader.FetchMessage(ctx)
+	}
+	fetchCtx, cancel := context.WithDeadline(ctx, b.due)
+	defer cancel()
+	msg, err := b.reader.FetchMessage(fetchCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(fetchCtx.Err(), context.DeadlineExceeded) {
+		return kafka.Message{}, errCommitDue
+	}
+	return msg, err
+}
+
+var errCommitDue = errors.New("commit due")
+
+// add records msg as handled, committing the batch once it is full.
+func (b *offsetBatch) add(ctx context.Context, msg kafka.Message) error {
+	if len(b.pending) == 0 {
+		b.due = time.Now().Add(b.interval)
+	}
+	b.pending = append(b.pending, msg)
+	if len(b.pending) >= b.size {
+		return b.commit(ctx)
+	}
+	return nil
+}
+
+// commit commits the pending offsets.
+func (b *offsetBatch) commit(ctx context.Context) error {
+	if len(b.pending) == 0 {
+		return nil
+	}
+	if err := b.reader.CommitMessages(ctx, b.pending...); err != nil {
+		return err
+	}
+	b.pending = b.pending[:0]
+	return nil
+}
+
+// flush commits the pending offsets once Run is returning, even though
+// ctx may have ended.
+func (b *offsetBatch) flush(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), commitTimeout)
+	defer cancel()
+	return b.commit(ctx)
+}
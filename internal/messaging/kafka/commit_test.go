@@ -0,0 +1,105 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func createdEvents(n int) []messaging.OrderEvent {
+	events := make([]messaging.OrderEvent, n)
+	for i := range events {
+		events[i] = messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-1"}
+	}
+	return events
+}
+
+// countingHandler counts handled offsets and fails on the offset in fail.
+type countingHandler struct {
+	mu      sync.Mutex
+	handled []int64
+	failed  int
+}
+
+func (h *countingHandler) register(c *Consumer, fail int64) {
+	c.SetFallbackHandler(func(ctx context.Context, _ messaging.OrderEvent) error {
+		d, _ := DeliveryFromContext(ctx)
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if d.Offset == fail {
+			h.failed++
+			return errors.New("downstream unavailable")
+		}
+		h.handled = append(h.handled, d.Offset)
+		return nil
+	})
+}
+
+func (h *countingHandler) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.handled)
+}
+
+func (r *stubReader) commitBatches() [][]int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]int64(nil), r.batches...)
+}
+
+func TestConsumer_WithCommitBatch_CommitsEveryN(t *testing.T) {
+	reader := newStubReader(t, createdEvents(7)...)
+	c := newConsumer(reader, WithCommitBatch(3, 0))
+	h := &countingHandler{}
+	h.register(c, -1)
+
+	runUntil(t, c, func() bool { return h.count() == 7 && len(reader.commits()) == 6 })
+
+	assert.Equal(t, [][]int64{{0, 1, 2}, {3, 4, 5}, {6}}, reader.commitBatches(), "the partial batch is committed on shutdown")
+}
+
+func TestConsumer_WithCommitBatch_CommitsAfterInterval(t *testing.T) {
+	reader := newStubReader(t, createdEvents(2)...)
+	c := newConsumer(reader, WithCommitBatch(100, 20*time.Millisecond))
+	h := &countingHandler{}
+	h.register(c, -1)
+
+	start := time.Now()
+	runUntil(t, c, func() bool { return len(reader.commits()) == 2 })
+
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Equal(t, [][]int64{{0, 1}}, reader.commitBatches(), "an idle consumer commits once the interval passes")
+}
+
+func TestConsumer_WithCommitBatch_FailureMidBatch_CommitsUpToLastSuccess(t *testing.T) {
+	reader := newStubReader(t, createdEvents(5)...)
+	c := newConsumer(reader, WithCommitBatch(10, 0))
+	c.retryDelay = time.Millisecond
+	h := &countingHandler{}
+	h.register(c, 3)
+
+	runUntil(t, c, func() bool {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		return h.failed >= 2
+	})
+
+	assert.Equal(t, [][]int64{{0, 1, 2}}, reader.commitBatches())
+	assert.Equal(t, 3, h.count(), "nothing after the failed message is handled")
+}
+
+func TestConsumer_DefaultCommitsEachMessage(t *testing.T) {
+	reader := newStubReader(t, createdEvents(3)...)
+	c := newConsumer(reader)
+	h := &countingHandler{}
+	h.register(c, -1)
+
+	runUntil(t, c, func() bool { return len(reader.commits()) == 3 })
+
+	assert.Equal(t, [][]int64{{0}, {1}, {2}}, reader.commitBatches())
+}
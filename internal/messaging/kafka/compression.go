@@ -0,0 +1,90 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Compression selects the codec message batches are compressed with.
+// Consumers decompress transparently: the codec is recorded in each batch.
+//
+// The codecs trade producer and consumer CPU for smaller requests and topic
+// storage. Snappy and LZ4 are cheap enough not to limit throughput and
+// shrink JSON events several times over; zstd compresses best at some
+// more CPU and gzip costs the most CPU for a similar ratio. Ratios grow
+// with batch size, since each batch is compressed as a whole. See
+// BenchmarkCompression for sizes of a typical OrderEvent.
+type Compression int
+
+// Supported compression codecs.
+const (
+	CompressionNone Compression = iota
+	CompressionGzip
+	// CompressionSnappy is the default.
+	CompressionSnappy
+	CompressionLZ4
+	CompressionZstd
+)
+
+// ErrUnknownCompression is returned by New when WithCompression was given
+// a value that is not one of the Compression constants, and by
+// ParseCompression for an unknown name.
+var ErrUnknownCompression = errors.New("kafka: unknown compression codec")
+
+// ParseCompression returns the codec named name: one of none, gzip,
+// snappy, lz4 or zstd, in any case.
+func ParseCompression(name string) (Compression, error) {
+	for _, c := range []Compression{CompressionNone, CompressionGzip, CompressionSnappy, CompressionLZ4, CompressionZstd} {
+		if strings.EqualFold(name, c.String()) {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %q", ErrUnknownCompression, name)
+}
+
+func (c Compression) String() string {
+	switch c {
+	case CompressionNone:
+		return "none"
+	case CompressionGzip:
+		return "gzip"
+	case CompressionSnappy:
+		return "snappy"
+	case CompressionLZ4:
+		return "lz4"
+	case CompressionZstd:
+		return "zstd"
+	default:
+		return fmt.Sprintf("Compression(%d)", int(c))
+	}
+}
+
+// writerCompression returns the kafka-go setting for c. The zero
+// kafka.Compression disables compression.
+func (c Compression) writerCompression() (kafka.Compression, error) {
+	switch c {
+	case CompressionNone:
+		return 0, nil
+	case CompressionGzip:
+		return kafka.Gzip, nil
+	case CompressionSnappy:
+		return kafka.Snappy, nil
+	case CompressionLZ4:
+		return kafka.Lz4, nil
+	case CompressionZstd:
+		return kafka.Zstd, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnknownCompression, c)
+	}
+}
+
+// WithCompression compresses message batches with c, trading producer and
+// consumer CPU for smaller requests and topic storage. Defaults to
+// CompressionSnappy. New returns ErrUnknownCompression for a value that is
+// not one of the Compression constants.
+func WithCompression(c Compression) Option {
+	return func(o *options) { o.compression = c }
+}
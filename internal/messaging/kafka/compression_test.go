@@ -0,0 +1,163 @@
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// compressingBroker stores message values compressed with codec, as a
+// broker stores produced batches, and decompresses them on fetch. A nil
+// codec stores values as is.
+type compressingBroker struct {
+	codec  kafkago.CompressionCodec
+	stored []kafkago.Message
+}
+
+func (b *compressingBroker) WriteMessages(_ context.Context, msgs ...kafkago.Message) error {
+	for _, msg := range msgs {
+		if b.codec != nil {
+			var buf bytes.Buffer
+			w := b.codec.NewWriter(&buf)
+			if _, err := w.Write(msg.Value); err != nil {
+				return err
+			}
+			if err := w.Close(); err != nil {
+				return err
+			}
+			msg.Value = buf.Bytes()
+		}
+		b.stored = append(b.stored, msg)
+	}
+	return nil
+}
+
+func (b *compressingBroker) Close() error { return nil }
+
+// fetch returns the stored messages with their values decompressed.
+func (b *compressingBroker) fetch(t *testing.T) []kafkago.Message {
+	t.Helper()
+	msgs := make([]kafkago.Message, len(b.stored))
+	for i, msg := range b.stored {
+		if b.codec != nil {
+			r := b.codec.NewReader(bytes.NewReader(msg.Value))
+			value, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+			msg.Value = value
+		}
+		msgs[i] = msg
+	}
+	return msgs
+}
+
+func TestNew_WithCompression_ConfiguresWriter(t *testing.T) {
+	tests := []struct {
+		compression Compression
+		want        kafkago.Compression
+	}{
+		{CompressionNone, 0},
+		{CompressionGzip, kafkago.Gzip},
+		{CompressionSnappy, kafkago.Snappy},
+		{CompressionLZ4, kafkago.Lz4},
+		{CompressionZstd, kafkago.Zstd},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.compression.String(), func(t *testing.T) {
+			pub := mustNew(t, WithCompression(tt.compression))
+
+			writer, ok := pub.writer.(*kafkago.Writer)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, writer.Compression)
+		})
+	}
+
+	writer := mustNew(t).writer.(*kafkago.Writer)
+	assert.Equal(t, kafkago.Snappy, writer.Compression, "snappy by default")
+}
+
+func TestParseCompression(t *testing.T) {
+	tests := []struct {
+		name string
+		want Compression
+	}{
+		{"none", CompressionNone},
+		{"gzip", CompressionGzip},
+		{"snappy", CompressionSnappy},
+		{"LZ4", CompressionLZ4},
+		{"zstd", CompressionZstd},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCompression(tt.name)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseCompression_UnknownName_ReturnsError(t *testing.T) {
+	for _, name := range []string{"", "brotli", "snappy "} {
+		_, err := ParseCompression(name)
+
+		require.ErrorIs(t, err, ErrUnknownCompression, "name %q", name)
+		assert.Contains(t, err.Error(), fmt.Sprintf("%q", name))
+	}
+}
+
+// BenchmarkCompression compresses batches of a typical OrderEvent, as
+// the writer compresses each produce batch, and reports the compressed
+// bytes per event:
+//
+//	go test -run '^$' -bench Compression ./internal/messaging/kafka/
+func BenchmarkCompression(b *testing.B) {
+	// Each event of a batch is a different order, so IDs do not repeat
+	event := func() []byte {
+		order := newTestOrder()
+		order.Items = append(order.Items,
+			domain.OrderItem{ID: uuid.New(), ProductID: "p-2", Name: "Gadget", Quantity: 1, Price: domain.Money{Amount: 4999}, Subtotal: domain.Money{Amount: 4999}},
+			domain.OrderItem{ID: uuid.New(), ProductID: "p-3", Name: "Gizmo", Quantity: 3, Price: domain.Money{Amount: 250}, Subtotal: domain.Money{Amount: 750}},
+		)
+		value, err := messaging.JSONSerializer{}.Marshal(messaging.NewOrderEvent(messaging.EventOrderCreated, order))
+		require.NoError(b, err)
+		return value
+	}
+
+	for _, size := range []int{1, 100} {
+		var batch []byte
+		for range size {
+			batch = append(batch, event()...)
+		}
+		for _, c := range []Compression{CompressionNone, CompressionGzip, CompressionSnappy, CompressionLZ4, CompressionZstd} {
+			b.Run(fmt.Sprintf("%s/batch=%d", c, size), func(b *testing.B) {
+				wc, err := c.writerCompression()
+				require.NoError(b, err)
+				var buf bytes.Buffer
+				b.SetBytes(int64(len(batch)))
+				for b.Loop() {
+					buf.Reset()
+					if wc == 0 {
+						buf.Write(batch)
+						continue
+					}
+					w := wc.Codec().NewWriter(&buf)
+					_, _ = w.Write(batch)
+					_ = w.Close()
+				}
+				b.ReportMetric(float64(buf.Len())/float64(size), "bytes/event")
+			})
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/codec"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const defaultRetryDelay = time.Second
+
+// messageReader abstracts kafka.Reader for testability.
+type messageReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	io.Closer
+}
+
+// HandlerFunc handles a decoded order event.
+// Returning an error leaves the message uncommitted so it is retried.
+type HandlerFunc func(ctx context.Context, evt messaging.OrderEvent) error
+
+// Delivery locates a consumed message in Kafka and carries its key and
+// headers, for debugging or replaying it by hand.
+type Delivery struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Headers   []kafka.Header
+}
+
+// HandlerWithMeta handles a decoded order event along with the Delivery of
+// the message it came in. Register one with RegisterHandlerWithMeta.
+type HandlerWithMeta func(ctx context.Context, evt messaging.OrderEvent, d Delivery) error
+
+// MessageHeaders returns the message's headers with typed accessors for
+// the standard ones, such as EventType and SchemaVersion; the static
+// headers of the producer's WithHeaders are read with Get.
+func (d Delivery) MessageHeaders() messaging.Headers {
+	return messaging.HeadersFromKafka(d.Headers)
+}
+
+type deliveryKey struct{}
+
+// ContextWithDelivery returns a copy of ctx carrying d.
+func ContextWithDelivery(ctx context.Context, d Delivery) context.Context {
+	return context.WithValue(ctx, deliveryKey{}, d)
+}
+
+// DeliveryFromContext returns the Delivery of the message being handled.
+// The Consumer sets it on the context passed to handlers.
+func DeliveryFromContext(ctx context.Context) (Delivery, bool) {
+	d, ok := ctx.Value(deliveryKey{}).(Delivery)
+	return d, ok
+}
+
+// Consumer reads order events from Kafka and dispatches them to handlers
+// registered by event type.
+type Consumer struct {
+	reader     messageReader
+	retryDelay time.Duration
+
+	handlerRetry *HandlerRetry
+	writer       messageWriter // Retry and dead-letter topics, see WithHandlerRetry
+
+	commitSize     int
+	commitInterval time.Duration
+
+	headerFilter HeaderFilter
+	filter       EventFilter
+
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+	fallback HandlerFunc
+	tracer   trace.Tracer
+	logger   *slog.Logger
+}
+
+// EventFilter reports whether the consumer should handle evt.
+type EventFilter func(evt messaging.OrderEvent) bool
+
+// HeaderFilter reports whether the consumer should handle a message with
+// headers, before its value is decoded.
+type HeaderFilter func(headers []kafka.Header) bool
+
+// ConsumerOption configures a Consumer created by NewConsumer.
+type ConsumerOption func(*Consumer)
+
+// WithFilter handles only the events for which fn returns true. The others
+// are committed without reaching a handler, so the consumer group moves
+// past them. Defaults to handling every event.
+func WithFilter(fn EventFilter) ConsumerOption {
+	return func(c *Consumer) { c.filter = fn }
+}
+
+// WithHeaderFilter skips, without decoding them, the messages for which fn
+// returns false, committing them like WithFilter does. It is cheaper than
+// WithFilter for predicates the headers can answer, such as the tenant-id
+// header. It runs before any WithFilter. Defaults to handling every message.
+func WithHeaderFilter(fn HeaderFilter) ConsumerOption {
+	return func(c *Consumer) { c.headerFilter = fn }
+}
+
+// WithConsumerLogger sets where the consumer logs the messages it cannot
+// decode, handler failures and events with no handler. Defaults to logging
+// nothing.
+func WithConsumerLogger(logger *slog.Logger) ConsumerOption {
+	return func(c *Consumer) { c.logger = logger }
+}
+
+// NewConsumer creates a Kafka event consumer in the given consumer group.
+func NewConsumer(brokers []string, topic, groupID string, opts ...ConsumerOption) *Consumer {
+	return newReaderConsumer(brokers, kafka.ReaderConfig{
+		Brokers: brokers,
+		Topic:   topic,
+		GroupID: groupID,
+	}, opts...)
+}
+
+// NewMultiTopicConsumer creates a Kafka event consumer in the given
+// consumer group that reads every topic in topics, such as the topics a
+// publisher set up with WithTopicRouter writes to. Events are dispatched by
+// their event type whichever topic they arrive on; DeliveryFromContext
+// tells handlers which one it was.
+func NewMultiTopicConsumer(brokers, topics []string, groupID string, opts ...ConsumerOption) *Consumer {
+	return newReaderConsumer(brokers, kafka.ReaderConfig{
+		Brokers:     brokers,
+		GroupTopics: topics,
+		GroupID:     groupID,
+	}, opts...)
+}
+
+// newReaderConsumer creates a consumer reading with a Kafka reader of cfg.
+func newReaderConsumer(brokers []string, cfg kafka.ReaderConfig, opts ...ConsumerOption) *Consumer {
+	c := newConsumer(kafka.NewReader(cfg), opts...)
+	if c.handlerRetry != nil && c.handlerRetry.writesTopics() {
+		c.writer = &kafka.Writer{Addr: kafka.TCP(brokers...), RequiredAcks: kafka.RequireAll}
+	}
+	return c
+}
+
+func newConsumer(r messageReader, opts ...ConsumerOption) *Consumer {
+	c := &Consumer{
+		reader:     r,
+		retryDelay: defaultRetryDelay,
+		handlers:   make(map[string]HandlerFunc),
+		logger:     discardLogger,
+	}
+	c.fallback = c.logUnhandled
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// RegisterHandler registers h for events of eventType, replacing any
+// handler already registered for that type.
+func (c *Consumer) RegisterHandler(eventType string, h HandlerFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[eventType] = h
+}
+
+// RegisterHandlerWithMeta registers h for events of eventType like
+// RegisterHandler, passing it the Delivery of each event's message.
+func (c *Consumer) RegisterHandlerWithMeta(eventType string, h HandlerWithMeta) {
+	c.RegisterHandler(eventType, func(ctx context.Context, evt messaging.OrderEvent) error {
+		d, _ := DeliveryFromContext(ctx)
+		return h(ctx, evt, d)
+	})
+}
+
+// SetFallbackHandler sets the handler for event types with no registered
+// handler. The default logs the event and lets it be committed.
+func (c *Consumer) SetFallbackHandler(h HandlerFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fallback = h
+}
+
+// Run consumes messages until ctx is cancelled, then returns nil.
+//
+// The offset of a message is committed only after its handler returns nil.
+// A failing handler is retried on the same message, so a partition never
+// advances past an event that was not handled, unless WithHandlerRetry
+// bounds the attempts. Messages that cannot be
+// decoded are logged and committed, as are those WithFilter or
+// WithHeaderFilter skip. An event of a newer schema version than the
+// consumer reads is not committed: it goes to the dead-letter topic of
+// WithHandlerRetry if there is one, and otherwise Run returns an error
+// wrapping its *messaging.SchemaVersionError, so that a consumer upgraded
+// to read it resumes from it. WithCommitBatch commits several offsets at a
+// time; those still pending are committed before Run returns.
+func (c *Consumer) Run(ctx context.Context) (err error) {
+	batch := c.newOffsetBatch()
+	defer func() {
+		if cerr := batch.flush(ctx); cerr != nil {
+			err = errors.Join(err, cerr)
+		}
+	}()
+
+	for {
+		msg, err := batch.fetch(ctx)
+		switch {
+		case errors.Is(err, errCommitDue):
+			err = batch.commit(ctx)
+		case err == nil:
+			if err = c.handle(ctx, msg); err == nil {
+				err = batch.add(ctx, msg)
+			}
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Close closes the underlying Kafka reader, and the writer of any retry
+// and dead-letter topics.
+func (c *Consumer) Close() error {
+	err := c.reader.Close()
+	if c.writer != nil {
+		err = errors.Join(err, c.writer.Close())
+	}
+	return err
+}
+
+// handle dispatches msg, retrying until the handler succeeds or ctx ends,
+// or per WithHandlerRetry.
+func (c *Consumer) handle(ctx context.Context, msg kafka.Message) error {
+	if c.headerFilter != nil && !c.headerFilter(msg.Headers) {
+		return nil
+	}
+	evt, err := Decode(msg)
+	if errors.Is(err, messaging.ErrUnsupportedSchemaVersion) {
+		return c.unsupportedVersion(ctx, msg, err)
+	}
+	if err != nil {
+		c.logger.Warn("failed to unmarshal event",
+			slog.Int64("offset", msg.Offset),
+			slog.String("error", err.Error()))
+		return nil
+	}
+	if c.filter != nil && !c.filter(evt) {
+		return nil
+	}
+
+	// Continue the producer's trace, if the message carries one
+	ctx, span := c.startConsumeSpan(ctx, msg, evt)
+	ctx = ContextWithDelivery(ctx, Delivery{
+		Topic:     msg.Topic,
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       msg.Key,
+		Headers:   msg.Headers,
+	})
+
+	h := c.handlerFor(evt.EventType)
+	if c.handlerRetry != nil {
+		return c.handleWithRetry(ctx, span, msg, evt, h)
+	}
+	for attempt := 1; ; attempt++ {
+		err := h(ctx, evt)
+		if err == nil {
+			endSpan(span, nil)
+			return nil
+		}
+		c.logger.Warn("event handler failed",
+			slog.String("event_type", evt.EventType),
+			slog.String("order_id", evt.OrderID),
+			slog.String("error", err.Error()))
+		attemptEvent(span, attempt, err)
+
+		select {
+		case <-ctx.Done():
+			err = errors.Join(err, ctx.Err())
+			endSpan(span, err)
+			return err
+		case <-time.After(c.retryDelay):
+		}
+	}
+}
+
+// Decode picks a codec from msg's content-type header and decodes the
+// event, so anything written with WithCodec can be read back. Messages
+// without the header are decoded as JSON. The event is upgraded to
+// messaging.CurrentSchemaVersion; an event of a newer schema version
+// returns a *messaging.SchemaVersionError. A tombstone, a message with no
+// value, is rebuilt from its headers (see WithTombstoneOnCancel), or
+// returns ErrTombstone if it lacks them.
+func Decode(msg kafka.Message) (messaging.OrderEvent, error) {
+	if len(msg.Value) == 0 {
+		return decodeTombstone(msg)
+	}
+	c, err := codec.ForContentType(messaging.HeadersFromKafka(msg.Headers).ContentType())
+	if err != nil {
+		return messaging.OrderEvent{}, err
+	}
+	return messaging.Unmarshal(c, msg.Value)
+}
+
+func (c *Consumer) handlerFor(eventType string) HandlerFunc {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if h, ok := c.handlers[eventType]; ok {
+		return h
+	}
+	return c.fallback
+}
+
+func (c *Consumer) logUnhandled(_ context.Context, evt messaging.OrderEvent) error {
+	c.logger.Warn("no handler registered for event type",
+		slog.String("event_type", evt.EventType),
+		slog.String("order_id", evt.OrderID))
+	return nil
+}
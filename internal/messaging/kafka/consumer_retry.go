@@ -0,0 +1,223 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HeaderRetryAttempts carries the number of handler attempts already made
+// on an event the consumer moved to a retry or dead-letter topic, so the
+// count survives redelivery.
+const HeaderRetryAttempts = "retry-attempts"
+
+// HandlerRetry configures how a Consumer retries a failing handler, see
+// WithHandlerRetry.
+type HandlerRetry struct {
+	// MaxAttempts is the total number of handler attempts per event,
+	// counted across deliveries.
+	MaxAttempts int
+	// BaseDelay is the backoff before the second attempt. It doubles on
+	// each further attempt, capped at MaxDelay if that is set.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// RetryTopic, if set, receives an event after each failed attempt but
+	// the last, and the message is committed so the partition moves on. A
+	// consumer of RetryTopic waits out the backoff before handling it.
+	// Empty retries in place, holding up the partition.
+	RetryTopic string
+	// DeadLetterTopic receives an event once its attempts are used up.
+	// Empty logs and commits it.
+	DeadLetterTopic string
+}
+
+// WithHandlerRetry bounds the attempts at handling each event, instead of
+// retrying a failing handler every second until it succeeds. The attempts
+// made so far travel in the retry-attempts header of events written to the
+// retry and dead-letter topics; dead-lettered events also carry the
+// dlq-error, dlq-attempts and dlq-original-* headers.
+func WithHandlerRetry(r HandlerRetry) ConsumerOption {
+	return func(c *Consumer) {
+		if r.MaxAttempts < 1 {
+			r.MaxAttempts = 1
+		}
+		c.handlerRetry = &r
+	}
+}
+
+// delay returns the backoff after the given attempt.
+func (r *HandlerRetry) delay(attempt int) time.Duration {
+	d := r.BaseDelay
+	for i := 1; i < attempt && (r.MaxDelay <= 0 || d < r.MaxDelay); i++ {
+		d *= 2
+	}
+	if r.MaxDelay > 0 && d > r.MaxDelay {
+		d = r.MaxDelay
+	}
+	return d
+}
+
+func (r *HandlerRetry) writesTopics() bool {
+	return r.RetryTopic != "" || r.DeadLetterTopic != ""
+}
+
+// retryAttempts returns the attempts already made on msg, from its
+// retry-attempts header.
+func retryAttempts(msg kafka.Message) int {
+	n, err := strconv.Atoi(HeaderValue(msg.Headers, HeaderRetryAttempts))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// handleWithRetry runs h on evt until it succeeds or c.handlerRetry is
+// exhausted, then moves evt to the retry or dead-letter topic. It returns
+// an error only if ctx ends first.
+func (c *Consumer) handleWithRetry(ctx context.Context, span trace.Span, msg kafka.Message, evt messaging.OrderEvent, h HandlerFunc) error {
+	r := c.handlerRetry
+	prior := retryAttempts(msg)
+
+	// A redelivered event waits out the backoff since it was written
+	if prior > 0 && !msg.Time.IsZero() {
+		if err := sleep(ctx, time.Until(msg.Time.Add(r.delay(prior)))); err != nil {
+			endSpan(span, err)
+			return err
+		}
+	}
+
+	var err error
+	attempt := prior
+	for attempt < r.MaxAttempts {
+		attempt++
+		if err = h(ctx, evt); err == nil {
+			endSpan(span, nil)
+			return nil
+		}
+		c.logger.Warn("event handler failed",
+			slog.String("event_type", evt.EventType),
+			slog.String("order_id", evt.OrderID),
+			slog.Int("attempt", attempt),
+			slog.String("error", err.Error()))
+		attemptEvent(span, attempt, err)
+
+		if attempt < r.MaxAttempts && r.RetryTopic != "" {
+			err = c.forward(ctx, msg, r.RetryTopic, attempt, nil)
+			endSpan(span, err)
+			return err
+		}
+		if attempt < r.MaxAttempts {
+			if serr := sleep(ctx, r.delay(attempt)); serr != nil {
+				err = errors.Join(err, serr)
+				endSpan(span, err)
+				return err
+			}
+		}
+	}
+	if err == nil {
+		err = fmt.Errorf("%d attempt(s) already made", prior)
+	}
+
+	if r.DeadLetterTopic == "" {
+		c.logger.Error("event dropped after exhausting handler attempts",
+			slog.String("event_type", evt.EventType),
+			slog.String("order_id", evt.OrderID),
+			slog.Int("attempts", attempt),
+			slog.String("error", err.Error()))
+		endSpan(span, err)
+		return nil
+	}
+	ferr := c.forward(ctx, msg, r.DeadLetterTopic, attempt, deadLetterHeaders(msg, attempt, err))
+	if ferr == nil {
+		c.logger.Warn("event dead-lettered",
+			slog.String("event_type", evt.EventType),
+			slog.String("event_id", evt.EventID),
+			slog.String("order_id", evt.OrderID),
+			slog.Int("attempts", attempt))
+	}
+	endSpan(span, err)
+	return ferr
+}
+
+// deadLetterHeaders returns the headers recording why msg, from which
+// attempts handler attempts were made, was dead-lettered with err.
+func deadLetterHeaders(msg kafka.Message, attempts int, err error) []kafka.Header {
+	return []kafka.Header{
+		{Key: HeaderDLQError, Value: []byte(err.Error())},
+		{Key: HeaderDLQAttempts, Value: []byte(strconv.Itoa(attempts))},
+		{Key: HeaderDLQOriginalTopic, Value: []byte(msg.Topic)},
+		{Key: HeaderDLQOriginalPartition, Value: []byte(strconv.Itoa(msg.Partition))},
+		{Key: HeaderDLQOriginalOffset, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+	}
+}
+
+// unsupportedVersion handles msg, whose event is of a schema version the
+// consumer cannot read (verr). Committing it would drop the event for
+// good, so it goes to the dead-letter topic of WithHandlerRetry, to be
+// replayed once the consumer is upgraded, or else the consumer stops with
+// the error, leaving the partition at it.
+func (c *Consumer) unsupportedVersion(ctx context.Context, msg kafka.Message, verr error) error {
+	if c.handlerRetry == nil || c.handlerRetry.DeadLetterTopic == "" {
+		c.logger.Error("event of unsupported schema version, stopping",
+			slog.Int64("offset", msg.Offset),
+			slog.String("error", verr.Error()))
+		return fmt.Errorf("kafka consume %s offset %d: %w", msg.Topic, msg.Offset, verr)
+	}
+	attempts := retryAttempts(msg)
+	if err := c.forward(ctx, msg, c.handlerRetry.DeadLetterTopic, attempts, deadLetterHeaders(msg, attempts, verr)); err != nil {
+		return err
+	}
+	c.logger.Warn("event of unsupported schema version dead-lettered",
+		slog.String("event_id", HeaderValue(msg.Headers, HeaderEventID)),
+		slog.Int64("offset", msg.Offset),
+		slog.String("error", verr.Error()))
+	return nil
+}
+
+// forward writes msg to topic with its attempt count and any extra
+// headers, retrying the write every retryDelay until it succeeds or ctx
+// ends, so the message is never committed without landing somewhere.
+func (c *Consumer) forward(ctx context.Context, msg kafka.Message, topic string, attempts int, extra []kafka.Header) error {
+	headers := messaging.HeadersFromKafka(msg.Headers)
+	headers.Set(HeaderRetryAttempts, strconv.Itoa(attempts))
+	for _, h := range extra {
+		headers.Set(h.Key, string(h.Value))
+	}
+	out := kafka.Message{Topic: topic, Key: msg.Key, Value: msg.Value, Headers: headers.Kafka()}
+
+	for {
+		err := c.writer.WriteMessages(ctx, out)
+		if err == nil {
+			return nil
+		}
+		c.logger.Warn("failed to forward event",
+			slog.String("topic", topic),
+			slog.Int64("offset", msg.Offset),
+			slog.String("error", err.Error()))
+		if serr := sleep(ctx, c.retryDelay); serr != nil {
+			return errors.Join(fmt.Errorf("kafka write %s: %w", topic, err), serr)
+		}
+	}
+}
+
+// sleep waits for d, or returns ctx.Err() if ctx ends first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,197 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyHandler fails its first failures calls, or every call if failures
+// is negative.
+type flakyHandler struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	at       []time.Time
+}
+
+func (f *flakyHandler) handle(context.Context, messaging.OrderEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	f.at = append(f.at, time.Now())
+	if f.failures < 0 || f.calls <= f.failures {
+		return errors.New("downstream returned 500")
+	}
+	return nil
+}
+
+func (f *flakyHandler) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+func (w *mockWriter) written() []kafkago.Message {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]kafkago.Message(nil), w.messages...)
+}
+
+func newRetryConsumer(reader *stubReader, w *mockWriter, r HandlerRetry, h *flakyHandler) *Consumer {
+	c := newConsumer(reader, WithHandlerRetry(r))
+	c.writer = w
+	c.retryDelay = time.Millisecond
+	c.SetFallbackHandler(h.handle)
+	return c
+}
+
+func TestConsumer_HandlerRetry_FailsTwiceThenSucceeds(t *testing.T) {
+	reader := newStubReader(t, messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-1"})
+	w := &mockWriter{}
+	h := &flakyHandler{failures: 2}
+	c := newRetryConsumer(reader, w, HandlerRetry{
+		MaxAttempts:     3,
+		BaseDelay:       10 * time.Millisecond,
+		DeadLetterTopic: "order-events.dlq",
+	}, h)
+
+	runUntil(t, c, func() bool { return len(reader.commits()) == 1 })
+
+	assert.Equal(t, 3, h.count())
+	assert.Empty(t, w.written(), "a handled event is not forwarded")
+	require.Len(t, h.at, 3)
+	assert.GreaterOrEqual(t, h.at[1].Sub(h.at[0]), 10*time.Millisecond)
+	assert.GreaterOrEqual(t, h.at[2].Sub(h.at[1]), 20*time.Millisecond, "the backoff doubles")
+}
+
+func TestConsumer_HandlerRetry_AlwaysFails_DeadLettered(t *testing.T) {
+	reader := newStubReader(t,
+		messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-1"},
+		messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-2"},
+	)
+	reader.messages[0].Topic = "order-events"
+	w := &mockWriter{}
+	h := &flakyHandler{failures: -1}
+	c := newRetryConsumer(reader, w, HandlerRetry{
+		MaxAttempts:     3,
+		BaseDelay:       time.Millisecond,
+		DeadLetterTopic: "order-events.dlq",
+	}, h)
+
+	runUntil(t, c, func() bool { return len(reader.commits()) == 2 })
+
+	assert.Equal(t, 6, h.count(), "each event gets MaxAttempts")
+	assert.Equal(t, []int64{0, 1}, reader.commits(), "the partition moves past failed events")
+	sent := w.written()
+	require.Len(t, sent, 2)
+	msg := sent[0]
+	assert.Equal(t, "order-events.dlq", msg.Topic)
+	assert.Equal(t, reader.messages[0].Value, msg.Value)
+	assert.Equal(t, "3", HeaderValue(msg.Headers, HeaderRetryAttempts))
+	assert.Equal(t, "3", HeaderValue(msg.Headers, HeaderDLQAttempts))
+	assert.Equal(t, "downstream returned 500", HeaderValue(msg.Headers, HeaderDLQError))
+	assert.Equal(t, "order-events", HeaderValue(msg.Headers, HeaderDLQOriginalTopic))
+	assert.Equal(t, "0", HeaderValue(msg.Headers, HeaderDLQOriginalOffset))
+}
+
+func TestConsumer_HandlerRetry_NewerSchemaVersion_DeadLettered(t *testing.T) {
+	reader := newStubReader(t,
+		messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-1", SchemaVersion: messaging.CurrentSchemaVersion + 1},
+		messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-2"},
+	)
+	reader.messages[0].Topic = "order-events"
+	w := &mockWriter{}
+	h := &flakyHandler{}
+	c := newRetryConsumer(reader, w, HandlerRetry{MaxAttempts: 3, DeadLetterTopic: "order-events.dlq"}, h)
+
+	runUntil(t, c, func() bool { return len(reader.commits()) == 2 })
+
+	assert.Equal(t, 1, h.count(), "only the readable event reaches the handler")
+	sent := w.written()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "order-events.dlq", sent[0].Topic)
+	assert.Equal(t, reader.messages[0].Value, sent[0].Value)
+	assert.Equal(t, "0", HeaderValue(sent[0].Headers, HeaderDLQAttempts))
+	assert.Contains(t, HeaderValue(sent[0].Headers, HeaderDLQError), "schema version")
+	assert.Equal(t, "order-events", HeaderValue(sent[0].Headers, HeaderDLQOriginalTopic))
+}
+
+func TestConsumer_HandlerRetry_RetryTopic_AttemptsSurviveRedelivery(t *testing.T) {
+	r := HandlerRetry{
+		MaxAttempts:     3,
+		BaseDelay:       20 * time.Millisecond,
+		RetryTopic:      "order-events.retry",
+		DeadLetterTopic: "order-events.dlq",
+	}
+	h := &flakyHandler{failures: -1}
+	reader := newStubReader(t, messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-1"})
+
+	// Each delivery makes one attempt and forwards the event, which is
+	// then consumed again from where it was forwarded
+	var topics, attempts []string
+	for delivery := 1; delivery <= 3; delivery++ {
+		w := &mockWriter{}
+		c := newRetryConsumer(reader, w, r, h)
+		runUntil(t, c, func() bool { return len(reader.commits()) == 1 })
+
+		assert.Equal(t, delivery, h.count())
+		sent := w.written()
+		require.Len(t, sent, 1)
+		topics = append(topics, sent[0].Topic)
+		attempts = append(attempts, HeaderValue(sent[0].Headers, HeaderRetryAttempts))
+
+		redelivered := sent[0]
+		redelivered.Time = time.Now()
+		reader = &stubReader{messages: []kafkago.Message{redelivered}}
+	}
+
+	assert.Equal(t, []string{"order-events.retry", "order-events.retry", "order-events.dlq"}, topics)
+	assert.Equal(t, []string{"1", "2", "3"}, attempts)
+	require.Len(t, h.at, 3)
+	assert.GreaterOrEqual(t, h.at[2].Sub(h.at[1]), 40*time.Millisecond, "a redelivered event waits out its backoff")
+}
+
+func TestConsumer_HandlerRetry_NoDeadLetterTopic_CommitsAfterMaxAttempts(t *testing.T) {
+	reader := newStubReader(t, messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-1"})
+	w := &mockWriter{}
+	h := &flakyHandler{failures: -1}
+	c := newRetryConsumer(reader, w, HandlerRetry{MaxAttempts: 2}, h)
+
+	runUntil(t, c, func() bool { return len(reader.commits()) == 1 })
+
+	assert.Equal(t, 2, h.count())
+	assert.Empty(t, w.written())
+}
+
+func TestConsumer_HandlerRetry_DeadLetterWriteFails_NotCommitted(t *testing.T) {
+	reader := newStubReader(t, messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-1"})
+	w := &mockWriter{topicErr: map[string]error{"order-events.dlq": errors.New("broker unavailable")}}
+	h := &flakyHandler{failures: -1}
+	c := newRetryConsumer(reader, w, HandlerRetry{MaxAttempts: 1, DeadLetterTopic: "order-events.dlq"}, h)
+
+	runUntil(t, c, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return w.attempts >= 3
+	})
+
+	assert.Equal(t, 1, h.count(), "the handler is not retried, only the write")
+	assert.Empty(t, reader.commits())
+}
+
+func TestHandlerRetry_Delay(t *testing.T) {
+	r := HandlerRetry{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	assert.Equal(t, 100*time.Millisecond, r.delay(1))
+	assert.Equal(t, 200*time.Millisecond, r.delay(2))
+	assert.Equal(t, 300*time.Millisecond, r.delay(3))
+	assert.Equal(t, 300*time.Millisecond, r.delay(10))
+}
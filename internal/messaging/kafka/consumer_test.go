@@ -0,0 +1,493 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/codec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// stubReader is an in-memory broker stub that serves queued messages and
+// records commits.
+type stubReader struct {
+	mu        sync.Mutex
+	messages  []kafkago.Message
+	next      int
+	committed []int64
+	batches   [][]int64 // Offsets of each CommitMessages call
+	closed    bool
+}
+
+func newStubReader(t *testing.T, events ...messaging.OrderEvent) *stubReader {
+	t.Helper()
+	r := &stubReader{}
+	for i, evt := range events {
+		value, err := json.Marshal(evt)
+		require.NoError(t, err)
+		r.messages = append(r.messages, kafkago.Message{
+			Key:    []byte(evt.OrderID),
+			Value:  value,
+			Offset: int64(i),
+		})
+	}
+	return r
+}
+
+func (r *stubReader) FetchMessage(ctx context.Context) (kafkago.Message, error) {
+	r.mu.Lock()
+	if r.next < len(r.messages) {
+		msg := r.messages[r.next]
+		r.next++
+		r.mu.Unlock()
+		return msg, nil
+	}
+	r.mu.Unlock()
+
+	// No more messages: block until the consumer shuts down
+	<-ctx.Done()
+	return kafkago.Message{}, ctx.Err()
+}
+
+func (r *stubReader) CommitMessages(_ context.Context, msgs ...kafkago.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var batch []int64
+	for _, m := range msgs {
+		batch = append(batch, m.Offset)
+	}
+	r.committed = append(r.committed, batch...)
+	r.batches = append(r.batches, batch)
+	return nil
+}
+
+func (r *stubReader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	return nil
+}
+
+func (r *stubReader) commits() []int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int64(nil), r.committed...)
+}
+
+// runUntil runs the consumer until cond holds, then cancels it.
+func runUntil(t *testing.T, c *Consumer, cond func() bool) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- c.Run(ctx) }()
+
+	require.Eventually(t, cond, time.Second, 5*time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err, "Run should return nil on context cancel")
+	case <-time.After(time.Second):
+		t.Fatal("consumer did not stop after context cancel")
+	}
+}
+
+func TestConsumer_Run_DispatchesByEventType(t *testing.T) {
+	reader := newStubReader(t,
+		messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-1"},
+		messaging.OrderEvent{EventType: messaging.EventOrderStatusChanged, OrderID: "o-1", NewStatus: "confirmed"},
+	)
+	c := newConsumer(reader)
+
+	var mu sync.Mutex
+	var created, changed []messaging.OrderEvent
+	c.RegisterHandler(messaging.EventOrderCreated, func(_ context.Context, evt messaging.OrderEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		created = append(created, evt)
+		return nil
+	})
+	c.RegisterHandler(messaging.EventOrderStatusChanged, func(_ context.Context, evt messaging.OrderEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		changed = append(changed, evt)
+		return nil
+	})
+
+	runUntil(t, c, func() bool { return len(reader.commits()) == 2 })
+
+	require.Len(t, created, 1)
+	require.Len(t, changed, 1)
+	assert.Equal(t, domain.OrderStatusConfirmed, changed[0].NewStatus)
+	assert.Equal(t, []int64{0, 1}, reader.commits())
+}
+
+func TestConsumer_Run_UnknownEventType_UsesFallback(t *testing.T) {
+	reader := newStubReader(t, messaging.OrderEvent{EventType: "order.unknown", OrderID: "o-1"})
+	c := newConsumer(reader)
+
+	var mu sync.Mutex
+	var fallbackEvents []messaging.OrderEvent
+	c.SetFallbackHandler(func(_ context.Context, evt messaging.OrderEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		fallbackEvents = append(fallbackEvents, evt)
+		return nil
+	})
+
+	runUntil(t, c, func() bool { return len(reader.commits()) == 1 })
+
+	require.Len(t, fallbackEvents, 1)
+	assert.Equal(t, "order.unknown", fallbackEvents[0].EventType)
+}
+
+func TestConsumer_Run_HandlerError_CommitsOnlyAfterSuccess(t *testing.T) {
+	reader := newStubReader(t, messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-1"})
+	c := newConsumer(reader)
+	c.retryDelay = time.Millisecond
+
+	var mu sync.Mutex
+	calls := 0
+	c.RegisterHandler(messaging.EventOrderCreated, func(_ context.Context, _ messaging.OrderEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls < 3 {
+			assert.Empty(t, reader.commits(), "offset must not be committed before handler succeeds")
+			return errors.New("downstream unavailable")
+		}
+		return nil
+	})
+
+	runUntil(t, c, func() bool { return len(reader.commits()) == 1 })
+
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []int64{0}, reader.commits())
+}
+
+func TestConsumer_Run_ContextCancelled_StopsWithoutCommit(t *testing.T) {
+	reader := newStubReader(t, messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-1"})
+	c := newConsumer(reader)
+	c.retryDelay = time.Millisecond
+
+	var mu sync.Mutex
+	calls := 0
+	c.RegisterHandler(messaging.EventOrderCreated, func(_ context.Context, _ messaging.OrderEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return errors.New("always fails")
+	})
+
+	runUntil(t, c, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls >= 2
+	})
+
+	assert.Empty(t, reader.commits())
+}
+
+func TestConsumer_Run_UndecodableMessage_IsCommitted(t *testing.T) {
+	reader := &stubReader{messages: []kafkago.Message{{Value: []byte("not-json"), Offset: 7}}}
+	c := newConsumer(reader)
+
+	runUntil(t, c, func() bool { return len(reader.commits()) == 1 })
+
+	assert.Equal(t, []int64{7}, reader.commits())
+}
+
+func TestConsumer_Run_NewerSchemaVersion_StopsWithoutCommit(t *testing.T) {
+	reader := newStubReader(t,
+		messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-1"},
+		messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-2", SchemaVersion: messaging.CurrentSchemaVersion + 1},
+		messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-3"},
+	)
+	c := newConsumer(reader)
+	var handled []string
+	c.RegisterHandler(messaging.EventOrderCreated, func(_ context.Context, evt messaging.OrderEvent) error {
+		handled = append(handled, evt.OrderID)
+		return nil
+	})
+
+	err := c.Run(context.Background())
+
+	var verr *messaging.SchemaVersionError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, messaging.CurrentSchemaVersion+1, verr.Version)
+	assert.Equal(t, []string{"o-1"}, handled, "nothing after the unreadable event is handled")
+	assert.Equal(t, []int64{0}, reader.commits(), "the unreadable event is not committed")
+}
+
+func TestConsumer_Close_ClosesReader(t *testing.T) {
+	reader := &stubReader{}
+	c := newConsumer(reader)
+
+	assert.NoError(t, c.Close())
+	assert.True(t, reader.closed)
+}
+
+func TestConsumer_Run_ProtobufMessage_DecodedByContentType(t *testing.T) {
+	evt := messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-1", Version: 2}
+	value, err := messaging.ProtobufSerializer{}.Marshal(evt)
+	require.NoError(t, err)
+	reader := &stubReader{messages: []kafkago.Message{{
+		Value:   value,
+		Headers: []kafkago.Header{{Key: HeaderContentType, Value: []byte(messaging.ContentTypeProtobuf)}},
+	}}}
+	c := newConsumer(reader)
+
+	var mu sync.Mutex
+	var got []messaging.OrderEvent
+	c.RegisterHandler(messaging.EventOrderCreated, func(_ context.Context, e messaging.OrderEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, e)
+		return nil
+	})
+
+	runUntil(t, c, func() bool { return len(reader.commits()) == 1 })
+
+	require.Len(t, got, 1)
+	assert.Equal(t, "o-1", got[0].OrderID)
+	assert.Equal(t, 2, got[0].Version)
+}
+
+func TestConsumer_Run_DecodesWhatPublisherWrote(t *testing.T) {
+	for _, c := range []codec.Codec{codec.JSONCodec{}, codec.ProtoCodec{}} {
+		t.Run(c.ContentType(), func(t *testing.T) {
+			w := &mockWriter{}
+			pub := newTestPublisher(w)
+			pub.serializer = c
+			order := newTestOrder()
+			require.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+			sent, err := c.Unmarshal(w.lastMessage().Value)
+			require.NoError(t, err)
+
+			reader := &stubReader{messages: w.messages}
+			consumer := newConsumer(reader)
+			var mu sync.Mutex
+			var got []messaging.OrderEvent
+			consumer.SetFallbackHandler(func(_ context.Context, e messaging.OrderEvent) error {
+				mu.Lock()
+				defer mu.Unlock()
+				got = append(got, e)
+				return nil
+			})
+
+			runUntil(t, consumer, func() bool { return len(reader.commits()) == 1 })
+
+			require.Len(t, got, 1)
+			assert.Equal(t, sent.EventID, got[0].EventID)
+			assert.Equal(t, order.ID.String(), got[0].OrderID)
+			assert.Equal(t, order.CustomerID, got[0].CustomerID)
+			assert.Equal(t, order.Total.Amount, got[0].TotalMinor)
+			assert.Equal(t, order.Version, got[0].Version)
+			assert.Equal(t, messaging.EventOrderCreated, got[0].EventType)
+			require.Len(t, got[0].Items, 1)
+			assert.Equal(t, sent.Items, got[0].Items)
+			assert.True(t, sent.OccurredAt.Equal(got[0].OccurredAt))
+		})
+	}
+}
+
+func TestConsumer_Run_HandlerContext_CarriesDelivery(t *testing.T) {
+	reader := newStubReader(t, messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-1"})
+	reader.messages[0].Topic = "order-events"
+	reader.messages[0].Partition = 2
+	c := newConsumer(reader)
+
+	var mu sync.Mutex
+	var got Delivery
+	c.SetFallbackHandler(func(ctx context.Context, _ messaging.OrderEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got, _ = DeliveryFromContext(ctx)
+		return nil
+	})
+
+	runUntil(t, c, func() bool { return len(reader.commits()) == 1 })
+
+	assert.Equal(t, Delivery{Topic: "order-events", Partition: 2, Offset: 0, Key: []byte("o-1")}, got)
+}
+
+func TestConsumer_RegisterHandlerWithMeta_MetaMatchesMessage(t *testing.T) {
+	w := &mockWriter{}
+	order := newTestOrder()
+	require.NoError(t, newTestPublisher(w).PublishOrderCreated(messaging.WithTenantID(context.Background(), "acme"), order))
+	msg := w.lastMessage()
+	msg.Topic, msg.Partition, msg.Offset = "order-events", 5, 1234
+	reader := &stubReader{messages: []kafkago.Message{msg}}
+	c := newConsumer(reader)
+
+	var mu sync.Mutex
+	var got Delivery
+	c.RegisterHandlerWithMeta(messaging.EventOrderCreated, func(_ context.Context, evt messaging.OrderEvent, d Delivery) error {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, order.ID.String(), evt.OrderID)
+		got = d
+		return nil
+	})
+
+	runUntil(t, c, func() bool { return len(reader.commits()) == 1 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "order-events", got.Topic)
+	assert.Equal(t, 5, got.Partition)
+	assert.Equal(t, int64(1234), got.Offset)
+	assert.Equal(t, []byte(order.ID.String()), got.Key)
+	assert.Equal(t, msg.Headers, got.Headers)
+	assert.Contains(t, got.Headers, kafkago.Header{Key: HeaderTenantID, Value: []byte("acme")})
+}
+
+func TestConsumer_Run_ContinuesProducerTrace(t *testing.T) {
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+
+	reader := newStubReader(t, messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-1"})
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	reader.messages[0].Headers = []kafkago.Header{{Key: "traceparent", Value: []byte(traceparent)}}
+	c := newConsumer(reader)
+
+	var mu sync.Mutex
+	var got trace.SpanContext
+	c.RegisterHandler(messaging.EventOrderCreated, func(ctx context.Context, _ messaging.OrderEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got = trace.SpanContextFromContext(ctx)
+		return nil
+	})
+
+	runUntil(t, c, func() bool { return len(reader.commits()) == 1 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", got.TraceID().String())
+	assert.True(t, got.IsRemote())
+}
+
+func TestConsumer_WithFilter_HandlesMatchingEventsAndCommitsAll(t *testing.T) {
+	reader := newStubReader(t,
+		messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-1"},
+		messaging.OrderEvent{EventType: messaging.EventOrderUpdated, OrderID: "o-1"},
+		messaging.OrderEvent{EventType: messaging.EventOrderCancelled, OrderID: "o-2"},
+		messaging.OrderEvent{EventType: messaging.EventOrderUpdated, OrderID: "o-2"},
+	)
+	c := newConsumer(reader, WithFilter(func(evt messaging.OrderEvent) bool {
+		return evt.EventType == messaging.EventOrderUpdated
+	}))
+
+	var mu sync.Mutex
+	var got []messaging.OrderEvent
+	c.SetFallbackHandler(func(_ context.Context, evt messaging.OrderEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, evt)
+		return nil
+	})
+
+	runUntil(t, c, func() bool { return len(reader.commits()) == 4 })
+
+	require.Len(t, got, 2)
+	for _, evt := range got {
+		assert.Equal(t, messaging.EventOrderUpdated, evt.EventType)
+	}
+	assert.Equal(t, []int64{0, 1, 2, 3}, reader.commits(), "skipped events must still be committed")
+}
+
+func TestConsumer_WithHeaderFilter_SkipsBeforeDecoding(t *testing.T) {
+	reader := newStubReader(t,
+		messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-1"},
+		messaging.OrderEvent{EventType: messaging.EventOrderCreated, OrderID: "o-2"},
+	)
+	reader.messages[0].Headers = []kafkago.Header{{Key: HeaderTenantID, Value: []byte("acme")}}
+	reader.messages[1].Headers = []kafkago.Header{{Key: HeaderTenantID, Value: []byte("globex")}}
+
+	var decoded []string
+	c := newConsumer(reader,
+		WithHeaderFilter(func(headers []kafkago.Header) bool {
+			return HeaderValue(headers, HeaderTenantID) == "acme"
+		}),
+		// Runs only on messages that were decoded
+		WithFilter(func(evt messaging.OrderEvent) bool {
+			decoded = append(decoded, evt.OrderID)
+			return true
+		}),
+	)
+	var mu sync.Mutex
+	var got []string
+	c.SetFallbackHandler(func(_ context.Context, evt messaging.OrderEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, evt.OrderID)
+		return nil
+	})
+
+	runUntil(t, c, func() bool { return len(reader.commits()) == 2 })
+
+	assert.Equal(t, []string{"o-1"}, got)
+	assert.Equal(t, []string{"o-1"}, decoded, "the filtered-out message must not be decoded")
+	assert.Equal(t, []int64{0, 1}, reader.commits())
+}
+
+func TestConsumer_RoutedTopics_DispatchedByEventType(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t, WithTopicRouter(func(eventType string) string {
+		if eventType == messaging.EventOrderCreated {
+			return "orders.created"
+		}
+		return "orders.lifecycle"
+	}))
+	pub.writer = w
+	order := newTestOrder()
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+	require.NoError(t, pub.PublishOrderCancelled(context.Background(), order, ""))
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newTestOrder()))
+
+	reader := &stubReader{messages: w.messages}
+	c := newConsumer(reader)
+	var mu sync.Mutex
+	got := map[string][]string{} // Event type -> topics it arrived on
+	record := func(ctx context.Context, e messaging.OrderEvent) error {
+		d, ok := DeliveryFromContext(ctx)
+		require.True(t, ok)
+		mu.Lock()
+		defer mu.Unlock()
+		got[e.EventType] = append(got[e.EventType], d.Topic)
+		return nil
+	}
+	c.RegisterHandler(messaging.EventOrderCreated, record)
+	c.RegisterHandler(messaging.EventOrderCancelled, record)
+
+	runUntil(t, c, func() bool { return len(reader.commits()) == 3 })
+
+	assert.Equal(t, map[string][]string{
+		messaging.EventOrderCreated:   {"orders.created", "orders.created"},
+		messaging.EventOrderCancelled: {"orders.lifecycle"},
+	}, got)
+}
+
+func TestNewMultiTopicConsumer_ReadsEveryTopicInGroup(t *testing.T) {
+	topics := []string{"orders.created", "orders.lifecycle"}
+	c := NewMultiTopicConsumer([]string{"localhost:9092"}, topics, "billing")
+
+	cfg := c.reader.(*kafkago.Reader).Config()
+	assert.Equal(t, topics, cfg.GroupTopics)
+	assert.Empty(t, cfg.Topic)
+	assert.Equal(t, "billing", cfg.GroupID)
+	assert.NoError(t, c.Close())
+}
@@ -0,0 +1,137 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Headers added to dead-lettered messages. The original headers and value
+// are kept unchanged so the message can be replayed onto its topic as is.
+// The partition and offset are only known, and only set, for events
+// dead-lettered by a consumer.
+const (
+	HeaderDLQError             = "dlq-error"
+	HeaderDLQAttempts          = "dlq-attempts"
+	HeaderDLQOriginalTopic     = "dlq-original-topic"
+	HeaderDLQOriginalPartition = "dlq-original-partition"
+	HeaderDLQOriginalOffset    = "dlq-original-offset"
+	// HeaderDLQReplays counts the times dlq.Reprocessor has put a message
+	// back on its original topic. It survives dead-lettering again with
+	// the other original headers.
+	HeaderDLQReplays = "dlq-replays"
+)
+
+// deadLetterTimeout bounds the dead-letter write, which runs even when the
+// publish context has already been cancelled.
+const deadLetterTimeout = 5 * time.Second
+
+// deadLetter is where events go once every publish attempt has failed.
+type deadLetter struct {
+	topic string // Empty disables the DLQ topic
+	path  string // Empty disables the file fallback
+
+	mu sync.Mutex // Serializes appends to path
+}
+
+// WithDeadLetter sends events that still fail after all retries to topic,
+// with the failure reason, attempt count and original topic in headers.
+func WithDeadLetter(topic string) Option {
+	return func(o *options) { o.deadLetter.topic = topic }
+}
+
+// WithDeadLetterFile appends events to the file at path, one JSON record
+// per line, when they cannot be written to the dead-letter topic either
+// (or when no dead-letter topic is set).
+func WithDeadLetterFile(path string) Option {
+	return func(o *options) { o.deadLetter.path = path }
+}
+
+// DeadLetterRecord is one line of the dead-letter file.
+type DeadLetterRecord struct {
+	OriginalTopic string            `json:"original_topic"`
+	Key           string            `json:"key"`
+	Headers       map[string]string `json:"headers"`
+	Value         []byte            `json:"value"` // Serialized event, base64 in JSON
+	Error         string            `json:"error"`
+	Attempts      int               `json:"attempts"`
+	FailedAt      time.Time         `json:"failed_at"`
+}
+
+func (d *deadLetter) enabled() bool {
+	return d.topic != "" || d.path != ""
+}
+
+// sendDeadLetter stores msg, which failed after attempts tries with cause, in the
+// dead-letter topic, falling back to the file. Returns an error only if
+// the event could not be stored anywhere.
+func (p *Publisher) sendDeadLetter(ctx context.Context, msg kafka.Message, cause error, attempts int) error {
+	d := p.deadLetter
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deadLetterTimeout)
+	defer cancel()
+
+	var topicErr error
+	if d.topic != "" {
+		dlq := kafka.Message{
+			Topic: d.topic,
+			Key:   msg.Key,
+			Value: msg.Value,
+			Headers: append(append([]kafka.Header{}, msg.Headers...),
+				kafka.Header{Key: HeaderDLQError, Value: []byte(cause.Error())},
+				kafka.Header{Key: HeaderDLQAttempts, Value: []byte(strconv.Itoa(attempts))},
+				kafka.Header{Key: HeaderDLQOriginalTopic, Value: []byte(msg.Topic)},
+			),
+		}
+		if topicErr = p.writeMessages(ctx, dlq); topicErr == nil {
+			return nil
+		}
+		topicErr = fmt.Errorf("kafka dead-letter write %s: %w", d.topic, topicErr)
+	}
+
+	if d.path == "" {
+		return topicErr
+	}
+	if err := d.appendFile(msg, cause, attempts); err != nil {
+		return errors.Join(topicErr, fmt.Errorf("kafka dead-letter file %s: %w", d.path, err))
+	}
+	return nil
+}
+
+func (d *deadLetter) appendFile(msg kafka.Message, cause error, attempts int) error {
+	headers := make(map[string]string, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	line, err := json.Marshal(DeadLetterRecord{
+		OriginalTopic: msg.Topic,
+		Key:           string(msg.Key),
+		Headers:       headers,
+		Value:         msg.Value,
+		Error:         cause.Error(),
+		Attempts:      attempts,
+		FailedAt:      time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	f, err := os.OpenFile(d.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
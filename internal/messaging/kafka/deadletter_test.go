@@ -0,0 +1,122 @@
+package kafka
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSerializer remembers the last payload it produced
+type recordingSerializer struct {
+	messaging.JSONSerializer
+	last []byte
+}
+
+func (s *recordingSerializer) Marshal(evt messaging.OrderEvent) ([]byte, error) {
+	data, err := s.JSONSerializer.Marshal(evt)
+	s.last = data
+	return data, err
+}
+
+func TestPublisher_DeadLetter_PreservesEventBytesAndFailureReason(t *testing.T) {
+	brokerErr := errors.New("leader not available")
+	w := &mockWriter{topicErr: map[string]error{"order-events": brokerErr}}
+	ser := &recordingSerializer{}
+	pub := mustNew(t,
+		WithSerializer(ser),
+		WithRetry(2, time.Millisecond),
+		WithDeadLetter("order-events.dlq"),
+	)
+	pub.writer = w
+	order := newTestOrder()
+
+	err := pub.PublishOrderCreated(context.Background(), order)
+
+	// The caller still sees the publish failure
+	require.ErrorIs(t, err, ErrPublishFailed)
+	require.Len(t, w.messages, 1)
+
+	dlq := w.lastMessage()
+	assert.Equal(t, "order-events.dlq", dlq.Topic)
+	assert.Equal(t, order.ID.String(), string(dlq.Key))
+
+	headers := map[string]string{}
+	for _, h := range dlq.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	assert.Contains(t, headers[HeaderDLQError], "leader not available")
+	assert.Equal(t, "2", headers[HeaderDLQAttempts])
+	assert.Equal(t, "order-events", headers[HeaderDLQOriginalTopic])
+	assert.Equal(t, messaging.ContentTypeJSON, headers[HeaderContentType])
+
+	// The value is the original serialized event, byte for byte
+	assert.Equal(t, ser.last, dlq.Value)
+	evt, err := Decode(dlq)
+	require.NoError(t, err)
+	assert.Equal(t, headers[HeaderEventID], evt.EventID)
+}
+
+func TestPublisher_DeadLetterTopicFails_AppendsToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead-letter.jsonl")
+	w := &mockWriter{topicErr: map[string]error{
+		"order-events":     errors.New("leader not available"),
+		"order-events.dlq": errors.New("dlq unavailable"),
+	}}
+	pub := mustNew(t,
+		WithDeadLetter("order-events.dlq"),
+		WithDeadLetterFile(path),
+	)
+	pub.writer = w
+	order := newTestOrder()
+
+	require.ErrorIs(t, pub.PublishOrderCreated(context.Background(), order), ErrPublishFailed)
+	require.ErrorIs(t, pub.PublishOrderUpdated(context.Background(), order, nil), ErrPublishFailed)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var records []DeadLetterRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec DeadLetterRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &rec))
+		records = append(records, rec)
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, records, 2, "file is appended to, not overwritten")
+
+	rec := records[0]
+	assert.Equal(t, "order-events", rec.OriginalTopic)
+	assert.Equal(t, order.ID.String(), rec.Key)
+	assert.Equal(t, 1, rec.Attempts)
+	assert.Contains(t, rec.Error, "leader not available")
+
+	evt, err := messaging.JSONSerializer{}.Unmarshal(rec.Value)
+	require.NoError(t, err)
+	assert.Equal(t, messaging.EventOrderCreated, evt.EventType)
+	assert.Equal(t, rec.Headers[HeaderEventID], evt.EventID)
+}
+
+func TestPublisher_DeadLetterUnavailable_ReturnsBothErrors(t *testing.T) {
+	w := &mockWriter{topicErr: map[string]error{
+		"order-events":     errors.New("leader not available"),
+		"order-events.dlq": errors.New("dlq unavailable"),
+	}}
+	pub := mustNew(t, WithDeadLetter("order-events.dlq"))
+	pub.writer = w
+
+	err := pub.PublishOrderCreated(context.Background(), newTestOrder())
+
+	assert.ErrorIs(t, err, ErrPublishFailed)
+	assert.Contains(t, err.Error(), "dlq unavailable")
+}
@@ -0,0 +1,133 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrPublisherClosed is returned by publishes started after Close.
+var ErrPublisherClosed = errors.New("kafka publisher closed")
+
+// DrainError is returned by Close when ctx ends before every in-flight
+// write has finished. The writes are abandoned and their events may not
+// have reached the topic.
+type DrainError struct {
+	Dropped int // Events in writes still pending at the deadline
+	Err     error
+}
+
+func (e *DrainError) Error() string {
+	return fmt.Sprintf("kafka close: %d in-flight event(s) dropped: %v", e.Dropped, e.Err)
+}
+
+// Unwrap returns the context error that ended the wait.
+func (e *DrainError) Unwrap() error { return e.Err }
+
+// FlushError is returned by Flush when ctx ends before every write in
+// flight at the call has finished. The writes carry on and the publisher
+// stays usable.
+type FlushError struct {
+	Pending int // Events in those writes still pending at the deadline
+	Err     error
+}
+
+func (e *FlushError) Error() string {
+	return fmt.Sprintf("kafka flush: %d event(s) still pending: %v", e.Pending, e.Err)
+}
+
+// Unwrap returns the context error that ended the wait.
+func (e *FlushError) Unwrap() error { return e.Err }
+
+// drainWrite is an in-flight write registered with drainGroup.add.
+type drainWrite struct {
+	n    int           // Events in the write
+	done chan struct{} // Closed when the write finishes
+}
+
+// drainGroup tracks in-flight writes so Flush and Close can wait for
+// them, and refuses new ones once closed.
+type drainGroup struct {
+	mu      sync.Mutex // Orders add against close, as WaitGroup.Add must not race Wait
+	closed  bool
+	writes  map[*drainWrite]struct{}
+	wg      sync.WaitGroup
+	pending atomic.Int64 // Events in in-flight writes
+}
+
+// add registers a write of n events, or reports false if closed.
+func (d *drainGroup) add(n int) (*drainWrite, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return nil, false
+	}
+	if d.writes == nil {
+		d.writes = make(map[*drainWrite]struct{})
+	}
+	w := &drainWrite{n: n, done: make(chan struct{})}
+	d.writes[w] = struct{}{}
+	d.wg.Add(1)
+	d.pending.Add(int64(n))
+	return w, true
+}
+
+// done marks a write registered with add as finished.
+func (d *drainGroup) done(w *drainWrite) {
+	d.mu.Lock()
+	delete(d.writes, w)
+	d.mu.Unlock()
+	d.pending.Add(-int64(w.n))
+	close(w.done)
+	d.wg.Done()
+}
+
+// flush waits for the writes in flight now, but not those added later,
+// until ctx ends, then returns a *FlushError.
+func (d *drainGroup) flush(ctx context.Context) error {
+	d.mu.Lock()
+	writes := make([]*drainWrite, 0, len(d.writes))
+	for w := range d.writes {
+		writes = append(writes, w)
+	}
+	d.mu.Unlock()
+
+	for i, w := range writes {
+		select {
+		case <-w.done:
+		case <-ctx.Done():
+			pending := 0
+			for _, w := range writes[i:] {
+				select {
+				case <-w.done:
+				default:
+					pending += w.n
+				}
+			}
+			return &FlushError{Pending: pending, Err: ctx.Err()}
+		}
+	}
+	return nil
+}
+
+// close stops accepting writes and waits for in-flight ones until ctx
+// ends, then returns a *DrainError.
+func (d *drainGroup) close(ctx context.Context) error {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return &DrainError{Dropped: int(d.pending.Load()), Err: ctx.Err()}
+	}
+}
@@ -0,0 +1,222 @@
+package kafka
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowWriter blocks every write until release is closed.
+type slowWriter struct {
+	started chan struct{} // Receives once per write
+	release chan struct{}
+
+	mu      sync.Mutex
+	written int
+	closed  bool
+}
+
+func newSlowWriter() *slowWriter {
+	return &slowWriter{started: make(chan struct{}, 16), release: make(chan struct{})}
+}
+
+func (w *slowWriter) WriteMessages(_ context.Context, msgs ...kafkago.Message) error {
+	w.started <- struct{}{}
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.written += len(msgs)
+	return nil
+}
+
+func (w *slowWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	return nil
+}
+
+// startSlowPublishes starts n publishes and waits until all are writing.
+func startSlowPublishes(t *testing.T, pub *Publisher, w *slowWriter, n int) <-chan error {
+	t.Helper()
+	errs := make(chan error, n)
+	for range n {
+		go func() { errs <- pub.PublishOrderCreated(context.Background(), newTestOrder()) }()
+	}
+	for range n {
+		select {
+		case <-w.started:
+		case <-time.After(time.Second):
+			t.Fatal("publish did not reach the writer")
+		}
+	}
+	return errs
+}
+
+func TestPublisher_Close_DeadlineWithPendingWrites_ReportsDropped(t *testing.T) {
+	w := newSlowWriter()
+	pub := newTestPublisher(&mockWriter{})
+	pub.writer = w
+	errs := startSlowPublishes(t, pub, w, 3)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := pub.Close(ctx)
+
+	var drainErr *DrainError
+	require.ErrorAs(t, err, &drainErr)
+	assert.Equal(t, 3, drainErr.Dropped)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "3 in-flight event(s) dropped")
+	assert.False(t, w.closed, "writer stays open for the pending writes")
+
+	close(w.release)
+	for range 3 {
+		assert.NoError(t, <-errs)
+	}
+}
+
+func TestPublisher_Close_WaitsForInFlightWrites(t *testing.T) {
+	w := newSlowWriter()
+	pub := newTestPublisher(&mockWriter{})
+	pub.writer = w
+	errs := startSlowPublishes(t, pub, w, 2)
+
+	closed := make(chan error, 1)
+	go func() { closed <- pub.Close(context.Background()) }()
+	select {
+	case <-closed:
+		t.Fatal("Close returned with writes in flight")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(w.release)
+	require.NoError(t, <-closed)
+	assert.Equal(t, 2, w.written)
+	assert.True(t, w.closed)
+	for range 2 {
+		assert.NoError(t, <-errs)
+	}
+}
+
+func TestPublisher_AfterClose_RejectsPublishes(t *testing.T) {
+	w := &mockWriter{}
+	pub := newTestPublisher(w)
+	require.NoError(t, pub.Close(context.Background()))
+
+	err := pub.PublishOrderCreated(context.Background(), newTestOrder())
+	assert.ErrorIs(t, err, ErrPublisherClosed)
+
+	events := newTestBatch(2, 1)
+	err = pub.PublishBatch(context.Background(), events)
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, events, batchErr.Events())
+	assert.ErrorIs(t, err, ErrPublisherClosed)
+
+	err = pub.Publish(context.Background(), messaging.NewOrderEvent(messaging.EventOrderUpdated, newTestOrder()))
+	assert.ErrorIs(t, err, ErrPublisherClosed)
+	assert.Zero(t, w.attempts)
+}
+
+func TestPublisher_Flush_WaitsForBufferedWritesAndStaysOpen(t *testing.T) {
+	w := newSlowWriter()
+	pub := mustNew(t, WithMode(ModeAsync))
+	pub.writer = w
+	ctx := context.Background()
+
+	for range 3 {
+		require.NoError(t, pub.PublishOrderCreated(ctx, newTestOrder()))
+	}
+	require.NoError(t, pub.PublishBatch(ctx, newTestBatch(2, 2)))
+
+	flushed := make(chan error, 1)
+	go func() { flushed <- pub.Flush(ctx) }()
+	select {
+	case <-flushed:
+		t.Fatal("Flush returned with writes buffered")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(w.release)
+	require.NoError(t, <-flushed)
+	assert.Equal(t, 7, w.written, "every buffered event reached the broker")
+	assert.False(t, w.closed)
+
+	require.NoError(t, pub.PublishOrderUpdated(ctx, newTestOrder(), nil), "the publisher is still usable")
+	require.NoError(t, pub.Flush(ctx))
+	assert.Equal(t, 8, w.written)
+}
+
+func TestPublisher_Flush_DeadlineWithPendingWrites_ReportsPending(t *testing.T) {
+	w := newSlowWriter()
+	pub := mustNew(t, WithMode(ModeAsync))
+	pub.writer = w
+	for range 2 {
+		require.NoError(t, pub.PublishOrderCreated(context.Background(), newTestOrder()))
+	}
+	require.NoError(t, pub.PublishBatch(context.Background(), newTestBatch(3, 1)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := pub.Flush(ctx)
+
+	var flushErr *FlushError
+	require.ErrorAs(t, err, &flushErr)
+	assert.Equal(t, 5, flushErr.Pending)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "5 event(s) still pending")
+
+	close(w.release)
+	require.NoError(t, pub.Close(context.Background()), "the writes were not abandoned")
+	assert.Equal(t, 5, w.written)
+}
+
+// gateWriter blocks the i-th write until gates[i] is closed.
+type gateWriter struct {
+	started chan struct{}
+	gates   []chan struct{}
+	calls   atomic.Int32
+}
+
+func (w *gateWriter) WriteMessages(context.Context, ...kafkago.Message) error {
+	gate := w.gates[w.calls.Add(1)-1]
+	w.started <- struct{}{}
+	<-gate
+	return nil
+}
+
+func (w *gateWriter) Close() error { return nil }
+
+func TestPublisher_Flush_IgnoresWritesStartedAfterIt(t *testing.T) {
+	w := &gateWriter{started: make(chan struct{}, 2), gates: []chan struct{}{make(chan struct{}), make(chan struct{})}}
+	pub := mustNew(t, WithMode(ModeAsync))
+	pub.writer = w
+	ctx := context.Background()
+
+	require.NoError(t, pub.PublishOrderCreated(ctx, newTestOrder()))
+	<-w.started
+	flushed := make(chan error, 1)
+	go func() { flushed <- pub.Flush(ctx) }()
+	time.Sleep(10 * time.Millisecond) // Let Flush see the first write
+	require.NoError(t, pub.PublishOrderCreated(ctx, newTestOrder()))
+	<-w.started
+
+	close(w.gates[0])
+	select {
+	case err := <-flushed:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Flush waited for a write started after it")
+	}
+
+	close(w.gates[1])
+	require.NoError(t, pub.Flush(ctx))
+}
@@ -0,0 +1,41 @@
+package kafka
+
+import (
+	"context"
+	"maps"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+// Enricher adds deployment-specific context to an event just before it is
+// serialized, typically by setting entries of its Metadata, such as the
+// originating region or a tenant ID taken from ctx.
+type Enricher func(ctx context.Context, evt *messaging.OrderEvent)
+
+// WithEnricher adds e to the enrichers run on every event just before it
+// is serialized; they run in the order they were added, and before the
+// redactor set with WithRedactor, so it can drop what they add. As with
+// the redactor, the event has already been validated and its partition
+// key and headers are taken from the event as published. Enrichers get a
+// copy whose Metadata is never nil: the caller's event and map are left
+// unchanged. With the transactional outbox they run when the relay sends
+// the event, with the relay's context. Tombstones carry no payload and
+// are not enriched. Defaults to none, which publishes no metadata.
+func WithEnricher(e Enricher) Option {
+	return func(o *options) { o.enrichers = append(o.enrichers, e) }
+}
+
+// enrich returns evt as the publisher's enrichers leave it.
+func (p *Publisher) enrich(ctx context.Context, evt messaging.OrderEvent) messaging.OrderEvent {
+	if len(p.enrichers) == 0 {
+		return evt
+	}
+	evt.Metadata = maps.Clone(evt.Metadata)
+	if evt.Metadata == nil {
+		evt.Metadata = make(map[string]string)
+	}
+	for _, e := range p.enrichers {
+		e(ctx, &evt)
+	}
+	return evt
+}
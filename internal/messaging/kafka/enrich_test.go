@@ -0,0 +1,121 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithEnricher_RunsInOrder(t *testing.T) {
+	var calls []string
+	w := &mockWriter{}
+	pub := mustNew(t,
+		WithEnricher(func(_ context.Context, evt *messaging.OrderEvent) {
+			calls = append(calls, "region")
+			evt.Metadata["region"] = "eu-west-1"
+			evt.Metadata["origin"] = "first"
+		}),
+		WithEnricher(func(_ context.Context, evt *messaging.OrderEvent) {
+			calls = append(calls, "origin")
+			evt.Metadata["origin"] = "second"
+		}))
+	pub.writer = w
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newTestOrder()))
+
+	assert.Equal(t, []string{"region", "origin"}, calls)
+	var got messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(w.lastMessage().Value, &got))
+	assert.Equal(t, map[string]string{"region": "eu-west-1", "origin": "second"}, got.Metadata, "later enrichers see and override earlier ones")
+}
+
+func TestWithEnricher_GetsPublishContext(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t, WithEnricher(func(ctx context.Context, evt *messaging.OrderEvent) {
+		if id, ok := messaging.TenantID(ctx); ok {
+			evt.Metadata["tenant_id"] = id
+		}
+	}))
+	pub.writer = w
+
+	ctx := messaging.WithTenantID(context.Background(), "tenant-7")
+	require.NoError(t, pub.PublishOrderCreated(ctx, newTestOrder()))
+
+	var got messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(w.lastMessage().Value, &got))
+	assert.Equal(t, "tenant-7", got.Metadata["tenant_id"])
+}
+
+func TestWithEnricher_EmptyMetadataOmitted(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{"no enrichers", nil},
+		{"enricher adds nothing", []Option{WithEnricher(func(context.Context, *messaging.OrderEvent) {})}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &mockWriter{}
+			pub := mustNew(t, tt.opts...)
+			pub.writer = w
+
+			require.NoError(t, pub.PublishOrderCreated(context.Background(), newTestOrder()))
+
+			var raw map[string]any
+			require.NoError(t, json.Unmarshal(w.lastMessage().Value, &raw))
+			assert.NotContains(t, raw, "metadata")
+		})
+	}
+}
+
+func TestWithEnricher_EditsCopy(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t, WithEnricher(func(_ context.Context, evt *messaging.OrderEvent) {
+		evt.Metadata["region"] = "us-east-1"
+	}))
+	pub.writer = w
+	evt := messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder())
+	evt.Metadata = map[string]string{"source": "import"}
+
+	require.NoError(t, pub.Publish(context.Background(), evt))
+
+	var got messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(w.lastMessage().Value, &got))
+	assert.Equal(t, map[string]string{"source": "import", "region": "us-east-1"}, got.Metadata)
+	assert.Equal(t, map[string]string{"source": "import"}, evt.Metadata, "the caller's map is not shared")
+}
+
+func TestWithEnricher_RunsBeforeRedactor(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t,
+		WithRedactor(func(evt *messaging.OrderEvent) { delete(evt.Metadata, "client_ip") }),
+		WithEnricher(func(_ context.Context, evt *messaging.OrderEvent) {
+			evt.Metadata["client_ip"] = "203.0.113.9"
+			evt.Metadata["region"] = "eu-west-1"
+		}))
+	pub.writer = w
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newTestOrder()))
+
+	var got messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(w.lastMessage().Value, &got))
+	assert.Equal(t, map[string]string{"region": "eu-west-1"}, got.Metadata)
+}
+
+func TestWithEnricher_TombstoneNotEnriched(t *testing.T) {
+	calls := 0
+	w := &mockWriter{}
+	pub := mustNew(t, WithTombstoneOnDelete(true), WithEnricher(func(context.Context, *messaging.OrderEvent) { calls++ }))
+	pub.writer = w
+
+	require.NoError(t, pub.PublishOrderDeleted(context.Background(), newTestOrder()))
+
+	assert.Nil(t, w.lastMessage().Value)
+	assert.Zero(t, calls)
+}
@@ -0,0 +1,72 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Trace context travels with each message in its messaging.Headers
+var _ propagation.TextMapCarrier = (*messaging.Headers)(nil)
+
+// ErrReservedHeader is returned, wrapped in ErrInvalidConfig, by New for a
+// WithHeaders header that the publisher sets itself.
+var ErrReservedHeader = errors.New("kafka: header is set by the publisher")
+
+// reservedHeaders are the headers the publisher derives from the event or
+// the publish context, which a static header must not override.
+var reservedHeaders = []string{
+	messaging.HeaderEventID, messaging.HeaderContentType, messaging.HeaderSchemaVersion,
+	messaging.HeaderTenantID, messaging.HeaderRequestID,
+	messaging.HeaderEventType, messaging.HeaderOrderID, messaging.HeaderStatus,
+	messaging.HeaderVersion, messaging.HeaderOccurredAt,
+	messaging.HeaderTraceParent, messaging.HeaderTraceState,
+}
+
+// WithHeaders adds static headers to every message, such as the
+// originating service or region for an event mesh to route on. They come
+// after the standard headers, so they can never contradict the payload;
+// New returns an error wrapping ErrInvalidConfig and ErrReservedHeader
+// for one of the standard names (see messaging.Headers), and
+// ErrInvalidConfig for an empty name. Calling it again adds to the
+// headers, replacing any of the same name. Defaults to none.
+func WithHeaders(headers map[string]string) Option {
+	return func(o *options) {
+		if o.headers == nil {
+			o.headers = make(map[string]string, len(headers))
+		}
+		maps.Copy(o.headers, headers)
+	}
+}
+
+// staticHeaders validates the headers of WithHeaders and returns them
+// sorted by name.
+func staticHeaders(headers map[string]string) ([]kafka.Header, error) {
+	hs := make([]kafka.Header, 0, len(headers))
+	for _, k := range slices.Sorted(maps.Keys(headers)) {
+		if k == "" {
+			return nil, errors.New("header name must not be empty")
+		}
+		if slices.Contains(reservedHeaders, k) {
+			return nil, fmt.Errorf("%w: %q", ErrReservedHeader, k)
+		}
+		hs = append(hs, kafka.Header{Key: k, Value: []byte(headers[k])})
+	}
+	return hs, nil
+}
+
+// HeaderValue returns the value of the first header named key, or "" if
+// there is none. It helps write a HeaderFilter.
+func HeaderValue(headers []kafka.Header, key string) string {
+	for _, h := range headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package kafka
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublisher_SetsStandardAndStaticHeaders_MatchingBody(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		eventType string
+		publish   func(*Publisher, *domain.Order) error
+	}{
+		{messaging.EventOrderCreated, func(p *Publisher, o *domain.Order) error { return p.PublishOrderCreated(ctx, o) }},
+		{messaging.EventOrderUpdated, func(p *Publisher, o *domain.Order) error { return p.PublishOrderUpdated(ctx, o, nil) }},
+		{messaging.EventOrderStatusChanged, func(p *Publisher, o *domain.Order) error {
+			return p.PublishOrderStatusChanged(ctx, o, domain.OrderStatusPending, domain.OrderStatusConfirmed)
+		}},
+		{messaging.EventOrderCancelled, func(p *Publisher, o *domain.Order) error { return p.PublishOrderCancelled(ctx, o, "changed mind") }},
+		{messaging.EventOrderDeleted, func(p *Publisher, o *domain.Order) error { return p.PublishOrderDeleted(ctx, o) }},
+		{messaging.EventOrderExpired, func(p *Publisher, o *domain.Order) error { return p.PublishOrderExpired(ctx, o) }},
+		{messaging.EventOrderSnapshot, func(p *Publisher, o *domain.Order) error { return p.PublishOrderSnapshot(ctx, o) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.eventType, func(t *testing.T) {
+			w := &mockWriter{}
+			pub := mustNew(t, WithHeaders(map[string]string{"origin-service": "ordersvc"}),
+				WithHeaders(map[string]string{"origin-region": "eu-west-1"}))
+			pub.writer = w
+			order := newTestOrder()
+
+			require.NoError(t, tt.publish(pub, order))
+
+			msg := w.lastMessage()
+			evt, err := Decode(msg)
+			require.NoError(t, err)
+			headers := messaging.HeadersFromKafka(msg.Headers)
+			assert.Equal(t, tt.eventType, evt.EventType)
+			assert.Equal(t, evt.EventType, headers.EventType())
+			assert.Equal(t, evt.OrderID, headers.OrderID())
+			assert.Equal(t, order.ID.String(), headers.OrderID())
+			assert.Equal(t, evt.EventID, headers.EventID())
+			assert.Equal(t, messaging.ContentTypeJSON, headers.ContentType())
+			version, err := headers.SchemaVersion()
+			require.NoError(t, err)
+			assert.Equal(t, evt.SchemaVersion, version)
+			assert.Equal(t, "ordersvc", headers.Get("origin-service"))
+			assert.Equal(t, "eu-west-1", headers.Get("origin-region"))
+		})
+	}
+}
+
+func TestPublisher_WithHeaders_PublishWithHeadersOverrides(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t, WithHeaders(map[string]string{"origin-service": "ordersvc"}))
+	pub.writer = w
+	evt := messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder())
+
+	require.NoError(t, pub.PublishWithHeaders(context.Background(), evt, map[string]string{"origin-service": "replayer"}))
+
+	headers := messaging.HeadersFromKafka(w.lastMessage().Headers)
+	assert.Equal(t, "replayer", headers.Get("origin-service"))
+}
+
+func TestNew_WithHeaders_Invalid_ReturnsErrInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		reserved bool
+	}{
+		{"event_type", HeaderEventType, true},
+		{"order_id", HeaderOrderID, true},
+		{"schema_version", HeaderSchemaVersion, true},
+		{"traceparent", messaging.HeaderTraceParent, true},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub, err := New([]string{"localhost:9092"}, "order-events", WithHeaders(map[string]string{tt.key: "x"}))
+
+			assert.Nil(t, pub)
+			assert.ErrorIs(t, err, ErrInvalidConfig)
+			if tt.reserved {
+				assert.ErrorIs(t, err, ErrReservedHeader)
+			}
+		})
+	}
+}
+
+func TestConsumer_Delivery_MessageHeaders(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t, WithHeaders(map[string]string{"origin-region": "eu-west-1"}))
+	pub.writer = w
+	order := newTestOrder()
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+	reader := &stubReader{messages: []kafkago.Message{w.lastMessage()}}
+	c := newConsumer(reader)
+
+	var mu sync.Mutex
+	var got messaging.Headers
+	c.RegisterHandler(messaging.EventOrderCreated, func(ctx context.Context, _ messaging.OrderEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		d, ok := DeliveryFromContext(ctx)
+		assert.True(t, ok)
+		got = d.MessageHeaders()
+		return nil
+	})
+
+	runUntil(t, c, func() bool { return len(reader.commits()) == 1 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, messaging.EventOrderCreated, got.EventType())
+	assert.Equal(t, order.ID.String(), got.OrderID())
+	assert.Equal(t, "eu-west-1", got.Get("origin-region"))
+}
@@ -0,0 +1,43 @@
+package kafka
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+// WithLogger logs every publish to logger: at debug level once the event is
+// written, and at error level, with the error, if it is not. Each record
+// carries the event_type, order_id, version and topic of the event.
+// Records below the logger's level cost no allocations. Defaults to
+// logging nothing.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) { o.logger = logger }
+}
+
+// discardLogger is the default logger, which drops every record.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// logPublish logs the outcome of publishing evt, err being nil if it was
+// written.
+func (p *Publisher) logPublish(ctx context.Context, evt messaging.OrderEvent, err error) {
+	level, msg := slog.LevelDebug, "event published"
+	if err != nil {
+		level, msg = slog.LevelError, "event publish failed"
+	}
+	// Before building any attributes, so disabled records are free
+	if !p.logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("event_type", evt.EventType),
+		slog.String("order_id", evt.OrderID),
+		slog.Int("version", evt.Version),
+		slog.String("topic", p.topicFor(evt.EventType)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	p.logger.LogAttrs(ctx, level, msg, attrs...)
+}
@@ -0,0 +1,122 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureHandler records every slog record at or above its level.
+type captureHandler struct {
+	level   slog.Level
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *captureHandler) Enabled(_ context.Context, level slog.Level) bool { return level >= h.level }
+
+func (h *captureHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r.Clone())
+	return nil
+}
+
+func (h *captureHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *captureHandler) WithGroup(string) slog.Handler      { return h }
+
+// attrs returns the attributes of r by key.
+func attrs(r slog.Record) map[string]any {
+	out := map[string]any{}
+	r.Attrs(func(a slog.Attr) bool {
+		out[a.Key] = a.Value.Any()
+		return true
+	})
+	return out
+}
+
+func TestPublisher_WithLogger_LogsSuccessAtDebug(t *testing.T) {
+	h := &captureHandler{level: slog.LevelDebug}
+	pub := mustNew(t, WithLogger(slog.New(h)))
+	pub.writer = &mockWriter{}
+	order := newTestOrder()
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+
+	require.Len(t, h.records, 1)
+	r := h.records[0]
+	assert.Equal(t, slog.LevelDebug, r.Level)
+	assert.Equal(t, "event published", r.Message)
+	assert.Equal(t, map[string]any{
+		"event_type": messaging.EventOrderCreated,
+		"order_id":   order.ID.String(),
+		"version":    int64(order.Version),
+		"topic":      "order-events",
+	}, attrs(r))
+}
+
+func TestPublisher_WithLogger_LogsFailureAtError(t *testing.T) {
+	h := &captureHandler{level: slog.LevelDebug}
+	pub := mustNew(t, WithLogger(slog.New(h)), WithTopicRouter(func(string) string { return "orders.cancelled" }))
+	pub.writer = &mockWriter{err: errors.New("broker unavailable")}
+	order := newTestOrder()
+
+	err := pub.PublishOrderCancelled(context.Background(), order, "")
+
+	require.Error(t, err)
+	require.Len(t, h.records, 1)
+	r := h.records[0]
+	assert.Equal(t, slog.LevelError, r.Level)
+	assert.Equal(t, "event publish failed", r.Message)
+	got := attrs(r)
+	assert.Equal(t, messaging.EventOrderCancelled, got["event_type"])
+	assert.Equal(t, order.ID.String(), got["order_id"])
+	assert.Equal(t, "orders.cancelled", got["topic"])
+	assert.Equal(t, err.Error(), got["error"])
+}
+
+func TestPublisher_WithLogger_BatchLogsEachEvent(t *testing.T) {
+	h := &captureHandler{level: slog.LevelDebug}
+	pub := mustNew(t, WithLogger(slog.New(h)))
+	pub.writer = &mockWriter{}
+	events := []messaging.OrderEvent{
+		messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder()),
+		messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder()),
+	}
+
+	require.NoError(t, pub.PublishBatch(context.Background(), events))
+
+	require.Len(t, h.records, 2)
+	for i, r := range h.records {
+		assert.Equal(t, events[i].OrderID, attrs(r)["order_id"])
+	}
+}
+
+func TestPublisher_LogPublish_DisabledLevel_DoesNotAllocate(t *testing.T) {
+	tests := []struct {
+		name   string
+		logger *slog.Logger
+		err    error
+	}{
+		{"default_logger", discardLogger, errors.New("broker unavailable")},
+		{"debug_below_level", slog.New(&captureHandler{level: slog.LevelInfo}), nil},
+	}
+
+	evt := messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub := mustNew(t, WithLogger(tt.logger))
+			ctx := context.Background()
+
+			allocs := testing.AllocsPerRun(100, func() { pub.logPublish(ctx, evt, tt.err) })
+
+			assert.Zero(t, allocs)
+		})
+	}
+}
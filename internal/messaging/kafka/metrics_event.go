@@ -0,0 +1,71 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/retry"
+)
+
+var _ messaging.MetricsPublisher = (*Publisher)(nil)
+
+// WithMetricsTopic sets the topic PublishOrderMetrics writes order.metrics
+// rollups to. It should not be a topic of order events, whose consumers
+// expect OrderEvent envelopes. Without it, PublishOrderMetrics fails with
+// ErrNoTopic.
+func WithMetricsTopic(topic string) Option {
+	return func(o *options) { o.metricsTopic = topic }
+}
+
+// PublishOrderMetrics writes evt to the topic set with WithMetricsTopic,
+// keyed by its window start, retrying as WithRetry sets. It encodes evt as
+// JSON whatever the serializer, writes it before returning even in
+// ModeAsync, and does not dead-letter it: the caller retries a failed
+// rollup itself, under the same event ID.
+func (p *Publisher) PublishOrderMetrics(ctx context.Context, evt messaging.OrderMetricsEvent) (err error) {
+	if p.metricsTopic == "" {
+		return fmt.Errorf("kafka publish %s: %w", evt.EventType, ErrNoTopic)
+	}
+	write, ok := p.drain.add(1)
+	if !ok {
+		return fmt.Errorf("kafka publish %s: %w", evt.EventType, ErrPublisherClosed)
+	}
+	defer p.drain.done(write)
+
+	start := time.Now()
+	defer func() { p.metrics.ObservePublish(evt.EventType, time.Since(start), err) }()
+
+	value, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("kafka marshal %s: %w: %w", evt.EventType, messaging.ErrSerialization, err)
+	}
+	var headers messaging.Headers
+	headers.SetEventID(evt.EventID)
+	headers.SetEventType(evt.EventType)
+	headers.SetContentType(messaging.ContentTypeJSON)
+	headers.SetSchemaVersion(evt.SchemaVersion)
+	for _, h := range p.headers {
+		headers.Set(h.Key, string(h.Value))
+	}
+	msg := kafka.Message{
+		Topic:   p.metricsTopic,
+		Key:     []byte(evt.WindowStart.UTC().Format(time.RFC3339Nano)),
+		Value:   value,
+		Headers: headers.Kafka(),
+	}
+
+	err = retry.Do(ctx, p.retry, func(ctx context.Context) error {
+		return p.writeMessages(ctx, msg)
+	})
+	if err == nil {
+		return nil
+	}
+	if brokerUnavailable(err) {
+		return fmt.Errorf("kafka write %s: %w: %w: %w", evt.EventType, ErrPublishFailed, messaging.ErrBrokerUnavailable, err)
+	}
+	return fmt.Errorf("kafka write %s: %w: %w", evt.EventType, ErrPublishFailed, err)
+}
@@ -0,0 +1,67 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMetricsEvent() messaging.OrderMetricsEvent {
+	start := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	return messaging.NewOrderMetricsEvent(start, start.Add(time.Hour), []messaging.StatusMetrics{
+		{Status: domain.OrderStatusPending, Count: 2, Revenue: []messaging.RevenueMetrics{{Currency: "USD", Total: 21, TotalMinor: 2100}}},
+	})
+}
+
+func TestPublisher_PublishOrderMetrics_WritesJSONToMetricsTopic(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t, WithMetricsTopic("order-metrics"), WithSerializer(messaging.ProtobufSerializer{}),
+		WithHeaders(map[string]string{"origin-region": "eu-west-1"}))
+	pub.writer = w
+	evt := newTestMetricsEvent()
+
+	require.NoError(t, pub.PublishOrderMetrics(context.Background(), evt))
+
+	require.Len(t, w.messages, 1)
+	msg := w.messages[0]
+	assert.Equal(t, "order-metrics", msg.Topic)
+	assert.Equal(t, "2026-10-14T09:00:00Z", string(msg.Key))
+	headers := messaging.HeadersFromKafka(msg.Headers)
+	assert.Equal(t, evt.EventID, headers.EventID())
+	assert.Equal(t, messaging.EventOrderMetrics, headers.EventType())
+	assert.Equal(t, messaging.ContentTypeJSON, headers.ContentType(), "JSON whatever the serializer")
+	assert.Equal(t, "eu-west-1", headers.Get("origin-region"))
+	var got messaging.OrderMetricsEvent
+	require.NoError(t, json.Unmarshal(msg.Value, &got))
+	assert.Equal(t, evt.EventID, got.EventID)
+	assert.Equal(t, evt.Statuses, got.Statuses)
+}
+
+func TestPublisher_PublishOrderMetrics_NoMetricsTopic_ReturnsErrNoTopic(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t)
+	pub.writer = w
+
+	err := pub.PublishOrderMetrics(context.Background(), newTestMetricsEvent())
+
+	assert.ErrorIs(t, err, ErrNoTopic)
+	assert.Empty(t, w.messages)
+}
+
+func TestPublisher_PublishOrderMetrics_WriteFails_RetriesThenReturnsErrPublishFailed(t *testing.T) {
+	w := &mockWriter{err: errors.New("rejected")}
+	pub := mustNew(t, WithMetricsTopic("order-metrics"), WithRetry(3, 0))
+	pub.writer = w
+
+	err := pub.PublishOrderMetrics(context.Background(), newTestMetricsEvent())
+
+	assert.ErrorIs(t, err, ErrPublishFailed)
+	assert.Equal(t, 3, w.attempts)
+}
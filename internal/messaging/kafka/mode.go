@@ -0,0 +1,143 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+// Mode selects whether publishes wait for the write to Kafka.
+type Mode int
+
+const (
+	// ModeSync makes each publish wait for the brokers to acknowledge the
+	// write, including retries and dead-lettering, and return its error.
+	// It is the default.
+	ModeSync Mode = iota
+	// ModeAsync makes each publish enqueue the write and return
+	// immediately. Write errors are delivered on Publisher.Errors instead.
+	ModeAsync
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeSync:
+		return "sync"
+	case ModeAsync:
+		return "async"
+	default:
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+}
+
+// asyncErrorBuffer is the capacity of the channel returned by Errors.
+const asyncErrorBuffer = 256
+
+// DefaultMaxInFlight is the default limit of WithMaxInFlight.
+const DefaultMaxInFlight = 1024
+
+// ErrTooManyInFlight is returned by a ModeAsync publish whose context
+// ended while it waited for one of the WithMaxInFlight writes to finish.
+// The event was not enqueued. It wraps the context's error.
+var ErrTooManyInFlight = errors.New("kafka: too many async writes in flight")
+
+// WithMode sets whether publishes wait for their write. Defaults to
+// ModeSync.
+//
+// In ModeAsync every publish method returns nil once the write is
+// enqueued; only a publish refused by a closed publisher, or one that
+// gave up waiting for WithMaxInFlight, returns an error.
+// Validation, write and dead-letter errors are sent on Errors, so callers
+// that need to know an event reached the topic should use ModeSync.
+func WithMode(m Mode) Option {
+	return func(o *options) { o.mode = m }
+}
+
+// WithMaxInFlight bounds the writes ModeAsync runs at once, a batch
+// counting as one, and so the goroutines and encoded messages a broker
+// outage can pile up while each write retries. A publish finding all n in
+// flight blocks until one finishes, and if its context ends first it
+// returns an error wrapping ErrTooManyInFlight without enqueueing the
+// event, so callers feel the backpressure instead of the process growing
+// without bound. Defaults to DefaultMaxInFlight. New returns an error if n
+// is not positive. It has no effect in ModeSync, where each publish is
+// its own write.
+func WithMaxInFlight(n int) Option {
+	return func(o *options) { o.maxInFlight = n }
+}
+
+// AsyncError is sent on Errors when an event published in ModeAsync was
+// not written. Err is the error the publish would have returned in
+// ModeSync.
+type AsyncError struct {
+	Event messaging.OrderEvent
+	Err   error
+}
+
+func (e *AsyncError) Error() string {
+	return fmt.Sprintf("async publish of event %s: %v", e.Event.EventID, e.Err)
+}
+
+// Unwrap returns Err.
+func (e *AsyncError) Unwrap() error { return e.Err }
+
+// Errors returns the channel on which ModeAsync publishes report failure:
+// an *AsyncError for each event of Publish and the Publish* methods that
+// was not written, and the *BatchError of each PublishBatch with failed
+// events. It never receives in ModeSync.
+//
+// The channel is buffered; errors arriving while it is full are dropped,
+// though still recorded by WithMetrics. Close closes it once every write
+// has finished.
+func (p *Publisher) Errors() <-chan error {
+	return p.errs
+}
+
+// async runs write in the background, after the unfinished async writes
+// sharing one of its partition keys, and reports its error on Errors. It
+// first waits for an in-flight slot, returning an error wrapping
+// ErrTooManyInFlight, with w done, if ctx ends before one is free. The
+// write keeps the values of ctx but not its cancellation, since the
+// caller's context typically ends as soon as the publish returns.
+func (p *Publisher) async(ctx context.Context, w *drainWrite, keys []string, write func(context.Context) error) error {
+	if err := p.acquireSlot(ctx); err != nil {
+		p.drain.done(w)
+		return err
+	}
+	ctx = context.WithoutCancel(ctx)
+	p.lanes.submit(keys, func() {
+		defer p.drain.done(w)
+		defer func() { <-p.slots }()
+		if err := write(ctx); err != nil {
+			p.reportAsync(err)
+		}
+	})
+	return nil
+}
+
+// acquireSlot takes an in-flight slot, waiting while all are taken until
+// ctx ends.
+func (p *Publisher) acquireSlot(ctx context.Context) error {
+	// A free slot is taken even if ctx has already ended
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	default:
+	}
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrTooManyInFlight, ctx.Err())
+	}
+}
+
+// reportAsync sends err on Errors without blocking.
+func (p *Publisher) reportAsync(err error) {
+	select {
+	case p.errs <- err:
+	default:
+	}
+}
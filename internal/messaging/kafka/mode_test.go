@@ -0,0 +1,160 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newModePublisher(t *testing.T, w messageWriter, mode Mode) *Publisher {
+	t.Helper()
+	pub := mustNew(t, WithMode(mode))
+	pub.writer = w
+	return pub
+}
+
+// nextError waits for the next error on pub.Errors.
+func nextError(t *testing.T, pub *Publisher) error {
+	t.Helper()
+	select {
+	case err := <-pub.Errors():
+		return err
+	case <-time.After(time.Second):
+		t.Fatal("no error delivered on Errors")
+		return nil
+	}
+}
+
+func TestPublisher_ModeSync_ReturnsWriteError(t *testing.T) {
+	writeErr := errors.New("broker unavailable")
+	pub := newModePublisher(t, &mockWriter{err: writeErr}, ModeSync)
+
+	err := pub.PublishOrderCreated(context.Background(), newTestOrder())
+
+	require.ErrorIs(t, err, ErrPublishFailed)
+	assert.ErrorIs(t, err, writeErr)
+	require.NoError(t, pub.Close(context.Background()))
+	_, open := <-pub.Errors()
+	assert.False(t, open, "no errors are delivered in sync mode")
+}
+
+func TestPublisher_ModeAsync_DeliversWriteErrorOnChannel(t *testing.T) {
+	writeErr := errors.New("broker unavailable")
+	pub := newModePublisher(t, &mockWriter{err: writeErr}, ModeAsync)
+	order := newTestOrder()
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), order), "enqueue succeeds")
+
+	err := nextError(t, pub)
+	require.ErrorIs(t, err, ErrPublishFailed)
+	assert.ErrorIs(t, err, writeErr)
+	var asyncErr *AsyncError
+	require.ErrorAs(t, err, &asyncErr)
+	assert.Equal(t, order.ID.String(), asyncErr.Event.OrderID)
+	assert.Equal(t, messaging.EventOrderCreated, asyncErr.Event.EventType)
+}
+
+func TestPublisher_ModeAsync_InvalidEventReportedOnChannel(t *testing.T) {
+	pub := newModePublisher(t, &mockWriter{}, ModeAsync)
+	order := newTestOrder()
+	order.CustomerID = ""
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+
+	var verr *messaging.ValidationError
+	assert.ErrorAs(t, nextError(t, pub), &verr)
+}
+
+// ctxWriter is a slowWriter that fails writes whose context has ended, as
+// kafka.Writer does.
+type ctxWriter struct{ *slowWriter }
+
+func (w ctxWriter) WriteMessages(ctx context.Context, msgs ...kafkago.Message) error {
+	w.started <- struct{}{}
+	<-w.release
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.written += len(msgs)
+	return nil
+}
+
+func TestPublisher_ModeAsync_WritesAfterCallerContextEnds(t *testing.T) {
+	w := ctxWriter{newSlowWriter()}
+	pub := newModePublisher(t, w, ModeAsync)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	require.NoError(t, pub.PublishOrderCreated(ctx, newTestOrder()))
+	<-w.started
+	cancel()
+	close(w.release)
+
+	require.NoError(t, pub.Close(context.Background()), "Close waits for the write")
+	assert.Equal(t, 1, w.written)
+	assert.True(t, w.closed)
+	_, open := <-pub.Errors()
+	assert.False(t, open, "Close closes Errors")
+}
+
+func TestPublisher_ModeAsync_BatchErrorOnChannel(t *testing.T) {
+	writeErr := errors.New("broker unavailable")
+	pub := newModePublisher(t, &mockWriter{err: writeErr}, ModeAsync)
+	events := []messaging.OrderEvent{
+		messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder()),
+		messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder()),
+	}
+
+	require.NoError(t, pub.PublishBatch(context.Background(), events))
+
+	var batchErr *BatchError
+	require.ErrorAs(t, nextError(t, pub), &batchErr)
+	assert.Equal(t, []int{0, 1}, batchErr.Indexes())
+	assert.ErrorIs(t, batchErr, writeErr)
+}
+
+func TestPublisher_ModeAsync_AfterClose_ReturnsErrPublisherClosed(t *testing.T) {
+	pub := newModePublisher(t, &mockWriter{}, ModeAsync)
+	require.NoError(t, pub.Close(context.Background()))
+
+	err := pub.PublishOrderCreated(context.Background(), newTestOrder())
+
+	assert.ErrorIs(t, err, ErrPublisherClosed, "a refused publish is not enqueued")
+}
+
+func TestPublisher_ModeAsync_MaxInFlight_BlocksUntilContextEnds(t *testing.T) {
+	w := newSlowWriter()
+	pub := mustNew(t, WithMode(ModeAsync), WithMaxInFlight(1))
+	pub.writer = w
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newTestOrder()))
+	<-w.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := pub.PublishOrderCreated(ctx, newTestOrder())
+	require.ErrorIs(t, err, ErrTooManyInFlight)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	events := []messaging.OrderEvent{
+		messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder()),
+		messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder()),
+	}
+	err = pub.PublishBatch(ctx, events)
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, []int{0, 1}, batchErr.Indexes())
+	assert.ErrorIs(t, batchErr, ErrTooManyInFlight)
+
+	close(w.release)
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newTestOrder()), "a slot frees once the write finishes")
+	require.NoError(t, pub.Close(context.Background()))
+	assert.Equal(t, 2, w.written, "refused publishes are not enqueued")
+}
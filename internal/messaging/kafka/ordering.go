@@ -0,0 +1,64 @@
+package kafka
+
+import (
+	"sync"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+// partitionKeyOf returns the key of evt's message: the key function's
+// choice, or the order ID if that is empty.
+func (p *Publisher) partitionKeyOf(evt messaging.OrderEvent) string {
+	if key := p.partitionKey(evt); key != "" {
+		return key
+	}
+	return evt.OrderID
+}
+
+// keyedQueue runs jobs in the background, each after the jobs submitted
+// before it that share one of its keys. Jobs with no key in common run
+// concurrently.
+type keyedQueue struct {
+	mu    sync.Mutex
+	tails map[string]chan struct{} // Closed when the last job submitted with the key finishes
+}
+
+// submit runs job once every earlier job with one of keys has finished.
+func (q *keyedQueue) submit(keys []string, job func()) {
+	done := make(chan struct{})
+	q.mu.Lock()
+	if q.tails == nil {
+		q.tails = make(map[string]chan struct{})
+	}
+	var waits []chan struct{}
+	for _, key := range keys {
+		if tail, ok := q.tails[key]; ok && tail != done {
+			waits = append(waits, tail)
+		}
+		q.tails[key] = done
+	}
+	q.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		for _, wait := range waits {
+			<-wait
+		}
+		job()
+
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		for _, key := range keys {
+			if q.tails[key] == done {
+				delete(q.tails, key)
+			}
+		}
+	}()
+}
+
+// len returns how many keys have unfinished jobs.
+func (q *keyedQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.tails)
+}
@@ -0,0 +1,231 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// partitionedBroker is a fake topic of partitions that assigns messages
+// by key with kafka-go's hash balancer, as the real writer does. Each write
+// is delayed by a random jitter and some fail, so writes racing for the
+// same partition land in whatever order they finish.
+type partitionedBroker struct {
+	failRate float64
+
+	mu         sync.Mutex
+	partitions [][]kafkago.Message
+}
+
+func newPartitionedBroker(n int, failRate float64) *partitionedBroker {
+	return &partitionedBroker{failRate: failRate, partitions: make([][]kafkago.Message, n)}
+}
+
+func (b *partitionedBroker) WriteMessages(_ context.Context, msgs ...kafkago.Message) error {
+	time.Sleep(rand.N(200 * time.Microsecond)) // #nosec G404 -- test jitter
+	if rand.Float64() < b.failRate {           // #nosec G404 -- test jitter
+		return errors.New("leader not available")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ids := make([]int, len(b.partitions))
+	for i := range ids {
+		ids[i] = i
+	}
+	balancer := &kafkago.Hash{}
+	for _, msg := range msgs {
+		p := balancer.Balance(msg, ids...)
+		b.partitions[p] = append(b.partitions[p], msg)
+	}
+	return nil
+}
+
+func (b *partitionedBroker) Close() error { return nil }
+
+// count returns how many messages the broker stored.
+func (b *partitionedBroker) count() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := 0
+	for _, p := range b.partitions {
+		n += len(p)
+	}
+	return n
+}
+
+// orderingViolations consumes every partition in order and describes each
+// breach of per-order ordering: an order's events found on more than one
+// partition, or a Version not greater than the one consumed before it.
+func orderingViolations(t *testing.T, partitions [][]kafkago.Message) []string {
+	t.Helper()
+	type seen struct {
+		partition int
+		version   int
+	}
+	last := map[string]seen{}
+	var violations []string
+	for p, msgs := range partitions {
+		for offset, msg := range msgs {
+			evt, err := Decode(msg)
+			require.NoError(t, err)
+			prev, ok := last[evt.OrderID]
+			switch {
+			case !ok:
+			case prev.partition != p:
+				violations = append(violations, fmt.Sprintf("order %s on partitions %d and %d", evt.OrderID, prev.partition, p))
+			case evt.Version <= prev.version:
+				violations = append(violations, fmt.Sprintf("order %s: version %d after %d at partition %d offset %d",
+					evt.OrderID, evt.Version, prev.version, p, offset))
+			}
+			last[evt.OrderID] = seen{partition: p, version: evt.Version}
+		}
+	}
+	return violations
+}
+
+func TestOrderingViolations_DetectsReorderAndSplit(t *testing.T) {
+	msg := func(order *domain.Order, version int) kafkago.Message {
+		order.Version = version
+		w := &mockWriter{}
+		pub := newTestPublisher(w)
+		require.NoError(t, pub.PublishOrderUpdated(context.Background(), order, nil))
+		return w.lastMessage()
+	}
+	a, b := newTestOrder(), newTestOrder()
+
+	violations := orderingViolations(t, [][]kafkago.Message{
+		{msg(a, 1), msg(a, 3), msg(a, 2), msg(b, 1)},
+		{msg(b, 2)},
+	})
+
+	assert.Len(t, violations, 2)
+}
+
+// trackedOrder is an order whose publishes are serialized by mu, as the
+// repository's optimistic locking serializes saves of one order.
+type trackedOrder struct {
+	mu    sync.Mutex
+	order *domain.Order
+}
+
+// publishNext publishes the order's next event: order.created first, then
+// alternately order.updated and order.status_changed, each one version on.
+func (o *trackedOrder) publishNext(ctx context.Context, pub *Publisher) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.order.Version++
+	switch {
+	case o.order.Version == 1:
+		return pub.PublishOrderCreated(ctx, o.order)
+	case o.order.Version%2 == 0:
+		return pub.PublishOrderUpdated(ctx, o.order, map[string]messaging.ChangePair{"items": {}})
+	default:
+		return pub.PublishOrderStatusChanged(ctx, o.order, domain.OrderStatusPending, domain.OrderStatusConfirmed)
+	}
+}
+
+// The acceptance test of the package's ordering guarantee: many goroutines
+// publish the events of overlapping orders, through retries and jittered
+// writes, and each order's versions must be consumed strictly increasing
+// from a single partition.
+func TestPublisher_ConcurrentPublishes_KeepPerOrderOrder(t *testing.T) {
+	const (
+		partitions = 8
+		orders     = 16
+		workers    = 32
+		perWorker  = 25
+	)
+
+	for _, mode := range []Mode{ModeSync, ModeAsync} {
+		t.Run(mode.String(), func(t *testing.T) {
+			broker := newPartitionedBroker(partitions, 0.05)
+			pub := mustNew(t, WithMode(mode), WithRetry(10, time.Microsecond))
+			pub.writer = broker
+			tracked := make([]*trackedOrder, orders)
+			for i := range tracked {
+				order := newTestOrder()
+				order.Version = 0
+				tracked[i] = &trackedOrder{order: order}
+			}
+
+			ctx := context.Background()
+			var wg sync.WaitGroup
+			for range workers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for range perWorker {
+						o := tracked[rand.IntN(orders)] // #nosec G404 -- test workload
+						assert.NoError(t, o.publishNext(ctx, pub))
+					}
+				}()
+			}
+			wg.Wait()
+			require.NoError(t, pub.Flush(ctx))
+
+			select {
+			case err := <-pub.Errors():
+				t.Fatalf("async publish failed: %v", err)
+			default:
+			}
+			assert.Equal(t, workers*perWorker, broker.count(), "every event is stored once")
+			assert.Empty(t, orderingViolations(t, broker.partitions))
+			assert.Zero(t, pub.lanes.len(), "finished writes leave no queue behind")
+		})
+	}
+}
+
+func TestPublisher_EmptyPartitionKey_KeepsOrderOnOnePartition(t *testing.T) {
+	broker := newPartitionedBroker(8, 0)
+	pub := mustNew(t, WithPartitionKey(func(messaging.OrderEvent) string { return "" }))
+	pub.writer = broker
+	o := &trackedOrder{order: newTestOrder()}
+	o.order.Version = 0
+
+	for range 20 {
+		require.NoError(t, o.publishNext(context.Background(), pub))
+	}
+
+	assert.Empty(t, orderingViolations(t, broker.partitions))
+}
+
+func TestKeyedQueue_SameKeyInOrderOtherKeysConcurrent(t *testing.T) {
+	var q keyedQueue
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var ran []string
+	record := func(name string) func() {
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+			ran = append(ran, name)
+		}
+	}
+
+	q.submit([]string{"a"}, func() { <-release; record("a1")() })
+	q.submit([]string{"a", "b", "a"}, record("ab"))
+	q.submit([]string{"a"}, record("a2"))
+	otherDone := make(chan struct{})
+	q.submit([]string{"c"}, func() { record("c")(); close(otherDone) })
+
+	select {
+	case <-otherDone:
+	case <-time.After(time.Second):
+		t.Fatal("a job on another key waited for a1")
+	}
+	close(release)
+	require.Eventually(t, func() bool { return q.len() == 0 }, time.Second, time.Millisecond)
+
+	assert.Equal(t, []string{"c", "a1", "ab", "a2"}, ran)
+}
@@ -0,0 +1,135 @@
+package kafka
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go/protocol"
+	"github.com/segmentio/kafka-go/protocol/apiversions"
+	"github.com/segmentio/kafka-go/protocol/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBroker answers the ApiVersions and Metadata requests a metadata
+// fetch makes, describing a one-broker cluster with topic. Other requests
+// get no answer.
+func fakeBroker(t *testing.T, topic string) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = lis.Close() })
+
+	host, portStr, _ := net.SplitHostPort(lis.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go serveFakeBroker(conn, host, int32(port), topic) // #nosec G115 -- ports fit in int32
+		}
+	}()
+	return lis.Addr().String()
+}
+
+func serveFakeBroker(conn net.Conn, host string, port int32, topic string) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		version, correlationID, _, msg, err := protocol.ReadRequest(r)
+		if err != nil {
+			return
+		}
+		var resp protocol.Message
+		switch msg.(type) {
+		case *apiversions.Request:
+			resp = &apiversions.Response{ApiKeys: []apiversions.ApiKeyResponse{
+				{ApiKey: int16(protocol.ApiVersions), MinVersion: 0, MaxVersion: 2},
+				{ApiKey: int16(protocol.Metadata), MinVersion: 0, MaxVersion: 8},
+			}}
+		case *metadata.Request:
+			resp = &metadata.Response{
+				Brokers: []metadata.ResponseBroker{{NodeID: 1, Host: host, Port: port}},
+				Topics: []metadata.ResponseTopic{{
+					Name:       topic,
+					Partitions: []metadata.ResponsePartition{{PartitionIndex: 0, LeaderID: 1, ReplicaNodes: []int32{1}, IsrNodes: []int32{1}}},
+				}},
+			}
+		default:
+			continue
+		}
+		if err := protocol.WriteResponse(conn, version, correlationID, resp); err != nil {
+			return
+		}
+	}
+}
+
+// silentBroker accepts connections and never answers.
+func silentBroker(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { _ = conn.Close() })
+		}
+	}()
+	return lis.Addr().String()
+}
+
+// closedPort returns an address nothing listens on.
+func closedPort(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	require.NoError(t, lis.Close())
+	return addr
+}
+
+func TestPublisher_Ping_ReachableBroker_ReturnsNil(t *testing.T) {
+	pub, err := New([]string{fakeBroker(t, "order-events")}, "order-events")
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	assert.NoError(t, pub.Ping(ctx))
+}
+
+func TestPublisher_Ping_BadBrokers_ReturnsErrorWithinDeadline(t *testing.T) {
+	tests := []struct {
+		name    string
+		brokers func(t *testing.T) []string
+	}{
+		{"connection_refused", func(t *testing.T) []string { return []string{closedPort(t)} }},
+		{"broker_never_answers", func(t *testing.T) []string { return []string{silentBroker(t)} }},
+		{"unresolvable_host", func(*testing.T) []string { return []string{"kafka.invalid:9092"} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub, err := New(tt.brokers(t), "order-events")
+			require.NoError(t, err)
+			const deadline = 200 * time.Millisecond
+			ctx, cancel := context.WithTimeout(context.Background(), deadline)
+			defer cancel()
+
+			start := time.Now()
+			err = pub.Ping(ctx)
+
+			assert.ErrorContains(t, err, "kafka ping")
+			assert.Less(t, time.Since(start), deadline+time.Second, "Ping must give up at the deadline")
+		})
+	}
+}
@@ -1,15 +1,64 @@
 // Package kafka implements event publishing using Apache Kafka.
+//
+// # Ordering
+//
+// Events for one order are consumed in the order they were published, as
+// long as the caller publishes them in Version order, each after the last
+// has returned, as the order service does. The publisher upholds this by:
+//
+//   - writing all of an order's events with the same message key, which
+//     the hash balancer sends to one partition. A key function returning
+//     an empty key falls back to the order ID rather than spreading the
+//     order's events across partitions.
+//   - in ModeAsync, queueing each write behind the unfinished writes with
+//     the same key, retries and dead-lettering included, so a later event
+//     cannot overtake an earlier one that is slow or being retried.
+//
+// It does not hold across topics: events that WithTopicRouter sends to
+// different topics may be consumed in any order. Nor does it hold across a
+// change of key: with KeyByCustomerID, moving an order to another customer
+// moves its later events to another partition.
 package kafka
 
 import (
 	"context"
-	"encoding/json"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"io"
+	"log/slog"
+	"maps"
+	"net"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/codec"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/noop"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/retry"
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Message headers set on every event, named here for consumers that read
+// them by hand. See messaging.Headers.
+const (
+	HeaderEventID       = messaging.HeaderEventID
+	HeaderContentType   = messaging.HeaderContentType
+	HeaderSchemaVersion = messaging.HeaderSchemaVersion
+	HeaderEventType     = messaging.HeaderEventType
+	HeaderOrderID       = messaging.HeaderOrderID
+)
+
+// Message headers copied from the publish context, set only when the
+// context carries the value (see messaging.WithTenantID and
+// messaging.WithRequestID).
+const (
+	HeaderTenantID  = messaging.HeaderTenantID
+	HeaderRequestID = messaging.HeaderRequestID
 )
 
 // messageWriter abstracts kafka.Writer for testability.
@@ -18,80 +67,577 @@ type messageWriter interface {
 	io.Closer
 }
 
-// Publisher implements service.EventPublisher using Kafka.
+var (
+	_ messaging.EventPublisher = (*Publisher)(nil)
+	_ messaging.BatchPublisher = (*Publisher)(nil)
+	_ messaging.Pinger         = (*Publisher)(nil)
+)
+
+// Publisher implements messaging.EventPublisher using Kafka.
 type Publisher struct {
-	writer messageWriter
-	topic  string
+	writer       messageWriter
+	brokers      net.Addr
+	transport    kafka.RoundTripper // Nil for kafka-go's default
+	topic        string
+	serializer   messaging.Serializer
+	retry        retry.Policy
+	deadLetter   *deadLetter
+	tracer       trace.Tracer
+	metrics      messaging.Metrics
+	logger       *slog.Logger
+	partitionKey PartitionKeyFunc
+	topicRouter  TopicRouter
+	metricsTopic string // Of order.metrics rollups, "" for none
+	maxBytes     int
+	writeTimeout time.Duration
+	tombstones   map[string]bool // Event types written as tombstones
+	redactor     Redactor        // Nil for none
+	enrichers    []Enricher      // Run in order before the redactor
+	headers      []kafka.Header  // Set on every message, see WithHeaders
+	clock        messaging.Clock
+	inflight     sync.Map   // Event ID -> publish span, until the write completes
+	lanes        keyedQueue // Orders ModeAsync writes per partition key
+	drain        drainGroup
+	mode         Mode
+	slots        chan struct{} // One per ModeAsync write in flight, nil in ModeSync
+	errs         chan error    // Failures of ModeAsync writes, see Errors
+	closeErrs    sync.Once
 }
 
-// NewPublisher creates a Kafka event publisher.
-func NewPublisher(brokers []string, topic string) *Publisher {
+// ErrPublishFailed is wrapped by every error from a failed write, after
+// any retries configured with WithRetry are exhausted. Failures to reach
+// the brokers also wrap messaging.ErrBrokerUnavailable.
+var ErrPublishFailed = errors.New("kafka publish failed")
+
+// ErrNoTopic is returned when the topic router set with WithTopicRouter
+// returns an empty topic for an event. Nothing is written.
+var ErrNoTopic = errors.New("kafka: no topic for event")
+
+// maxRetryDelay caps the backoff between retries set by WithRetry.
+const maxRetryDelay = 5 * time.Second
+
+// options holds the tunable Kafka writer settings.
+type options struct {
+	batchTimeout time.Duration
+	acks         Acks
+	serializer   messaging.Serializer
+	retry        retry.Policy
+	deadLetter   deadLetter
+	tracer       trace.Tracer
+	metrics      messaging.Metrics
+	logger       *slog.Logger
+	partitionKey PartitionKeyFunc
+	topicRouter  TopicRouter
+	metricsTopic string
+	clock        messaging.Clock
+	mode         Mode
+	maxInFlight  int
+	compression  Compression
+	maxBytes     int
+	writeTimeout time.Duration
+	tls          *tls.Config
+	sasl         *saslConfig
+	tombstones   map[string]bool
+	redactor     Redactor
+	enrichers    []Enricher
+	headers      map[string]string
+	startup      *startupRetry // Nil to skip the startup check
+}
+
+// Option configures a Publisher created by New.
+type Option func(*options)
+
+// WithBatchTimeout sets how long the writer waits to fill a batch before
+// flushing it. Defaults to 10ms.
+func WithBatchTimeout(d time.Duration) Option {
+	return func(o *options) { o.batchTimeout = d }
+}
+
+// WithRequiredAcks is WithAcks for the equivalent kafka-go setting.
+func WithRequiredAcks(acks kafka.RequiredAcks) Option {
+	a := Acks(-1) // Rejected by New
+	switch acks {
+	case kafka.RequireNone:
+		a = AcksNone
+	case kafka.RequireOne:
+		a = AcksLeader
+	case kafka.RequireAll:
+		a = AcksAll
+	}
+	return WithAcks(a)
+}
+
+// WithEnvelopeFormat sets the wire format of published events.
+// Defaults to messaging.FormatNative.
+func WithEnvelopeFormat(f messaging.EnvelopeFormat) Option {
+	return func(o *options) { o.serializer = f.Serializer() }
+}
+
+// WithSerializer sets the serializer used to encode events.
+// Defaults to messaging.JSONSerializer. A JSONSerializer with Naming or
+// OmitZero set changes the JSON keys or omits zero totals and versions
+// for consumers that expect them; their decoders must match.
+func WithSerializer(s messaging.Serializer) Option {
+	return func(o *options) { o.serializer = s }
+}
+
+// WithCodec sets the codec used to encode events; the content-type header
+// is taken from it. Defaults to codec.JSONCodec.
+func WithCodec(c codec.Codec) Option {
+	return WithSerializer(c)
+}
+
+// WithRetry retries a failed write up to maxAttempts times in total,
+// doubling the delay from baseDelay (with jitter, capped at 5s) between
+// attempts. Retries stop as soon as the publish context is done.
+// Defaults to a single attempt. A retried write may be stored twice if
+// only its acknowledgement was lost; see Acks.
+func WithRetry(maxAttempts int, baseDelay time.Duration) Option {
+	return func(o *options) {
+		o.retry = retry.Policy{
+			MaxAttempts: maxAttempts,
+			BaseDelay:   baseDelay,
+			MaxDelay:    max(baseDelay, maxRetryDelay),
+		}
+	}
+}
+
+// WithMetrics records every publish, including failed ones, into m.
+// Defaults to noop.Metrics.
+func WithMetrics(m messaging.Metrics) Option {
+	return func(o *options) { o.metrics = m }
+}
+
+// PartitionKeyFunc returns the message key for evt. Messages are
+// hash-partitioned by key, so events with the same key stay in order.
+// An empty key falls back to the order ID, so an order's events are never
+// spread across partitions.
+//
+// The key of an order's events should not change over its life, or its
+// later events may be consumed before its earlier ones.
+type PartitionKeyFunc func(evt messaging.OrderEvent) string
+
+// KeyByOrderID keys messages by order ID, so all events for one order go to
+// the same partition. It is the default.
+func KeyByOrderID(evt messaging.OrderEvent) string {
+	return evt.OrderID
+}
+
+// KeyByCustomerID keys messages by customer ID, so all of a customer's
+// events go to the same partition. An order moved to another customer
+// moves partition too, so its events are only ordered per customer.
+func KeyByCustomerID(evt messaging.OrderEvent) string {
+	return evt.CustomerID
+}
+
+// WithPartitionKey sets how message keys are derived, e.g. KeyByCustomerID
+// to keep all of a customer's events in order. Defaults to KeyByOrderID.
+//
+// Changing the key function of a live topic moves events to different
+// partitions: events published before and after the change may then be
+// consumed out of order.
+func WithPartitionKey(fn PartitionKeyFunc) Option {
+	return func(o *options) { o.partitionKey = fn }
+}
+
+// TopicRouter returns the topic that events of eventType are written to.
+type TopicRouter func(eventType string) string
+
+// WithTopicRouter writes each event to the topic router returns for its
+// type, e.g. to keep order.created and order.cancelled on topics with
+// different retention. Publishing an event the router returns an empty
+// topic for fails with ErrNoTopic. Defaults to the topic passed to New for
+// every event.
+func WithTopicRouter(router TopicRouter) Option {
+	return func(o *options) { o.topicRouter = router }
+}
+
+// WithClock sets the clock that stamps OccurredAt on the events the
+// Publish* methods build, unless the context pins the time with
+// messaging.WithOccurredAt. Defaults to messaging.SystemClock.
+func WithClock(c messaging.Clock) Option {
+	return func(o *options) { o.clock = c }
+}
+
+// New creates a Kafka event publisher writing to topic.
+// Messages are hash-partitioned by their key, the order ID unless
+// WithPartitionKey is set, so events for one order stay in order.
+// It returns an error wrapping ErrInvalidConfig if an option has an
+// invalid value. With WithStartupRetry, it also checks that a broker is
+// reachable, returning ErrNoBrokersReachable if none is.
+func New(brokers []string, topic string, opts ...Option) (*Publisher, error) {
+	o := options{
+		batchTimeout: 10 * time.Millisecond,
+		acks:         AcksAll,
+		serializer:   messaging.JSONSerializer{},
+		retry:        retry.Policy{MaxAttempts: 1},
+		tracer:       defaultTracer(),
+		metrics:      noop.Metrics{},
+		logger:       discardLogger,
+		partitionKey: KeyByOrderID,
+		clock:        messaging.SystemClock{},
+		compression:  CompressionSnappy,
+		maxBytes:     DefaultMaxMessageBytes,
+		maxInFlight:  DefaultMaxInFlight,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	compression, err := o.compression.writerCompression()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	requiredAcks, err := o.acks.requiredAcks()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	transport, err := o.transport()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	if o.maxBytes < 1 {
+		return nil, fmt.Errorf("%w: max message bytes must be positive, got %d", ErrInvalidConfig, o.maxBytes)
+	}
+	if o.maxInFlight < 1 {
+		return nil, fmt.Errorf("%w: max in-flight writes must be positive, got %d", ErrInvalidConfig, o.maxInFlight)
+	}
+	if o.writeTimeout < 0 {
+		return nil, fmt.Errorf("%w: write timeout must not be negative, got %s", ErrInvalidConfig, o.writeTimeout)
+	}
+	if o.startup != nil {
+		if err := o.startup.validate(); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
+	}
+	headers, err := staticHeaders(o.headers)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	p := &Publisher{
+		brokers:      kafka.TCP(brokers...),
+		topic:        topic,
+		serializer:   o.serializer,
+		retry:        o.retry,
+		deadLetter:   &deadLetter{topic: o.deadLetter.topic, path: o.deadLetter.path},
+		tracer:       o.tracer,
+		metrics:      o.metrics,
+		logger:       o.logger,
+		partitionKey: o.partitionKey,
+		topicRouter:  o.topicRouter,
+		metricsTopic: o.metricsTopic,
+		maxBytes:     o.maxBytes,
+		writeTimeout: o.writeTimeout,
+		tombstones:   o.tombstones,
+		redactor:     o.redactor,
+		enrichers:    o.enrichers,
+		headers:      headers,
+		clock:        o.clock,
+		mode:         o.mode,
+		errs:         make(chan error, asyncErrorBuffer),
+	}
+	if o.mode == ModeAsync {
+		p.slots = make(chan struct{}, o.maxInFlight)
+	}
 	w := &kafka.Writer{
-		Addr:         kafka.TCP(brokers...),
-		Topic:        topic,
+		Addr:         p.brokers,
 		Balancer:     &kafka.Hash{},
-		BatchTimeout: 10 * time.Millisecond,
-		RequiredAcks: kafka.RequireOne,
+		BatchTimeout: o.batchTimeout,
+		RequiredAcks: requiredAcks,
+		Compression:  compression,
+		BatchBytes:   int64(o.maxBytes),
+		Completion:   p.recordPartitions,
+	}
+	if transport != nil {
+		// Assigned only when set: a nil *kafka.Transport in the interface
+		// would replace kafka-go's default transport
+		w.Transport = transport
+		p.transport = transport
 	}
-	return &Publisher{writer: w, topic: topic}
+	p.writer = w
+	if o.startup != nil {
+		if err := p.awaitBrokers(*o.startup); err != nil {
+			_ = w.Close()
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// NewPublisher creates a Kafka event publisher with default options.
+func NewPublisher(brokers []string, topic string) *Publisher {
+	p, _ := New(brokers, topic) // The defaults are valid
+	return p
 }
 
 // PublishOrderCreated publishes an order.created event to Kafka.
 func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
-	evt := messaging.OrderEvent{
-		EventType:  messaging.EventOrderCreated,
-		OrderID:    order.ID.String(),
-		CustomerID: order.CustomerID,
-		Status:     string(order.Status),
-		Total:      order.Total,
-		Version:    order.Version,
-		OccurredAt: time.Now(),
-	}
-	return p.publish(ctx, order.ID.String(), evt)
+	return p.Publish(ctx, p.stamp(messaging.NewOrderEvent(messaging.EventOrderCreated, order)))
 }
 
 // PublishOrderUpdated publishes an order.updated event to Kafka.
-func (p *Publisher) PublishOrderUpdated(ctx context.Context, order *domain.Order) error {
-	evt := messaging.OrderEvent{
-		EventType:  messaging.EventOrderUpdated,
-		OrderID:    order.ID.String(),
-		CustomerID: order.CustomerID,
-		Status:     string(order.Status),
-		Total:      order.Total,
-		Version:    order.Version,
-		OccurredAt: time.Now(),
-	}
-	return p.publish(ctx, order.ID.String(), evt)
+func (p *Publisher) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	return p.Publish(ctx, p.stamp(messaging.NewUpdatedEvent(order, changes)))
 }
 
 // PublishOrderStatusChanged publishes an order.status_changed event to Kafka.
 func (p *Publisher) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
-	evt := messaging.OrderEvent{
-		EventType:  messaging.EventOrderStatusChanged,
-		OrderID:    order.ID.String(),
-		CustomerID: order.CustomerID,
-		Status:     string(order.Status),
-		OldStatus:  string(oldStatus),
-		NewStatus:  string(newStatus),
-		Total:      order.Total,
-		Version:    order.Version,
-		OccurredAt: time.Now(),
-	}
-	return p.publish(ctx, order.ID.String(), evt)
-}
-
-// Close flushes and closes the underlying Kafka writer.
-func (p *Publisher) Close() error {
+	return p.Publish(ctx, p.stamp(messaging.NewStatusChangedEvent(order, oldStatus, newStatus)))
+}
+
+// PublishOrderCancelled publishes an order.cancelled event to Kafka.
+func (p *Publisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	return p.Publish(ctx, p.stamp(messaging.NewCancelledEvent(order, reason)))
+}
+
+// PublishOrderDeleted publishes an order.deleted event to Kafka.
+func (p *Publisher) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, p.stamp(messaging.NewOrderEvent(messaging.EventOrderDeleted, order)))
+}
+
+// PublishOrderExpired publishes an order.expired event to Kafka.
+func (p *Publisher) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, p.stamp(messaging.NewOrderEvent(messaging.EventOrderExpired, order)))
+}
+
+// PublishOrderSnapshot publishes an order.snapshot event to Kafka.
+func (p *Publisher) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, messaging.NewSnapshotEvent(order))
+}
+
+// PublishSnapshots publishes an order.snapshot event for each order with
+// PublishBatch, in a single produce call.
+func (p *Publisher) PublishSnapshots(ctx context.Context, orders []*domain.Order) error {
+	events := make([]messaging.OrderEvent, len(orders))
+	for i, order := range orders {
+		events[i] = messaging.NewSnapshotEvent(order)
+	}
+	return p.PublishBatch(ctx, events)
+}
+
+// stamp sets evt.OccurredAt from the publisher's clock.
+func (p *Publisher) stamp(evt messaging.OrderEvent) messaging.OrderEvent {
+	evt.OccurredAt = p.clock.Now()
+	return evt
+}
+
+// Publish writes a pre-built event to Kafka, keyed by the partition key
+// function.
+// The outbox relay uses it to deliver stored events.
+func (p *Publisher) Publish(ctx context.Context, evt messaging.OrderEvent) error {
+	return p.publish(ctx, evt)
+}
+
+// PublishWithHeaders writes a pre-built event to Kafka like Publish, adding
+// headers to the message. The consumer dead-letter handler uses it to
+// record where a failed event came from.
+func (p *Publisher) PublishWithHeaders(ctx context.Context, evt messaging.OrderEvent, headers map[string]string) error {
+	extra := make([]kafka.Header, 0, len(headers))
+	for _, k := range slices.Sorted(maps.Keys(headers)) {
+		extra = append(extra, kafka.Header{Key: k, Value: []byte(headers[k])})
+	}
+	return p.publish(ctx, evt, extra...)
+}
+
+// Ping fetches the metadata of the publisher's topic from one of its
+// brokers, reporting whether the publisher can reach the cluster. The
+// readiness probe uses it.
+func (p *Publisher) Ping(ctx context.Context) error {
+	client := &kafka.Client{Addr: p.brokers, Transport: p.transport}
+	if _, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{p.topic}}); err != nil {
+		return fmt.Errorf("kafka ping: %w", err)
+	}
+	return nil
+}
+
+// Flush waits for the writes in flight when it is called, including their
+// retries and dead-lettering, to finish: in ModeAsync, every publish that
+// has returned; in ModeSync, those still running on other goroutines.
+// Publishes started during Flush are not waited for, and the publisher
+// stays usable afterwards. Failed writes are reported as usual, on Errors
+// in ModeAsync, not by Flush.
+//
+// If ctx ends first, Flush returns a *FlushError with the number of those
+// events still pending.
+func (p *Publisher) Flush(ctx context.Context) error {
+	return p.drain.flush(ctx)
+}
+
+// Close stops accepting publishes, which then fail with
+// ErrPublisherClosed, and waits for in-flight writes, including their
+// retries and dead-lettering, to finish. It then closes Errors and
+// flushes and closes the underlying Kafka writer.
+//
+// If ctx ends first, Close returns a *DrainError with the number of
+// events still pending and leaves the writer and Errors open.
+func (p *Publisher) Close(ctx context.Context) error {
+	if err := p.drain.close(ctx); err != nil {
+		return err
+	}
+	p.closeErrs.Do(func() {
+		if p.errs != nil {
+			close(p.errs)
+		}
+	})
 	return p.writer.Close()
 }
 
-func (p *Publisher) publish(ctx context.Context, key string, evt messaging.OrderEvent) error {
-	value, err := json.Marshal(evt)
+// publish writes evt, in the background in ModeAsync.
+func (p *Publisher) publish(ctx context.Context, evt messaging.OrderEvent, extra ...kafka.Header) error {
+	// Before the span and any async error see the event
+	evt = messaging.Correlate(ctx, evt)
+	write, ok := p.drain.add(1)
+	if !ok {
+		return fmt.Errorf("kafka publish %s: %w", evt.EventType, ErrPublisherClosed)
+	}
+	if p.mode == ModeAsync {
+		err := p.async(ctx, write, []string{p.partitionKeyOf(evt)}, func(ctx context.Context) error {
+			if err := p.write(ctx, evt, extra...); err != nil {
+				return &AsyncError{Event: evt, Err: err}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("kafka publish %s: %w", evt.EventType, err)
+		}
+		return nil
+	}
+	defer p.drain.done(write)
+	return p.write(ctx, evt, extra...)
+}
+
+// write encodes and writes evt, retrying and dead-lettering per the
+// publisher's options.
+func (p *Publisher) write(ctx context.Context, evt messaging.OrderEvent, extra ...kafka.Header) (err error) {
+	start := time.Now()
+	ctx, span := p.startPublishSpan(ctx, evt)
+	defer func() {
+		endSpan(span, err)
+		p.metrics.ObservePublish(evt.EventType, time.Since(start), err)
+		p.logPublish(ctx, evt, err)
+	}()
+	if span.IsRecording() {
+		p.inflight.Store(evt.EventID, span)
+		defer p.inflight.Delete(evt.EventID)
+	}
+
+	msg, err := p.message(ctx, evt, extra...)
 	if err != nil {
 		return err
 	}
-	return p.writer.WriteMessages(ctx, kafka.Message{
-		Key:   []byte(key),
-		Value: value,
+	attempts := 0
+	err = retry.Do(ctx, p.retry, func(ctx context.Context) error {
+		attempts++
+		return p.writeMessages(ctx, msg)
 	})
+	if err == nil {
+		return nil
+	}
+	return p.writeFailed(ctx, evt, msg, err, attempts)
+}
+
+// message validates and encodes evt as a message for its routed topic,
+// with the extra headers after the standard, context and static ones.
+func (p *Publisher) message(ctx context.Context, evt messaging.OrderEvent, extra ...kafka.Header) (kafka.Message, error) {
+	evt = messaging.Correlate(ctx, evt)
+	if err := evt.Validate(); err != nil {
+		return kafka.Message{}, fmt.Errorf("kafka publish %s: %w", evt.EventType, err)
+	}
+	topic := p.topicFor(evt.EventType)
+	if topic == "" {
+		return kafka.Message{}, fmt.Errorf("kafka publish %s: %w", evt.EventType, ErrNoTopic)
+	}
+	var headers messaging.Headers
+	headers.SetEventID(evt.EventID)
+	headers.SetContentType(p.serializer.ContentType())
+	headers.SetSchemaVersion(evt.SchemaVersion)
+	headers.SetEventType(evt.EventType)
+	headers.SetOrderID(evt.OrderID)
+	var value []byte
+	if p.tombstones[evt.EventType] {
+		setTombstoneHeaders(&headers, evt)
+	} else {
+		var err error
+		if value, err = p.serializer.Marshal(p.redact(p.enrich(ctx, evt))); err != nil {
+			return kafka.Message{}, fmt.Errorf("kafka marshal %s: %w: %w", evt.EventType, messaging.ErrSerialization, err)
+		}
+	}
+	if id, ok := messaging.TenantID(ctx); ok {
+		headers.SetTenantID(id)
+	}
+	if id, ok := messaging.RequestID(ctx); ok {
+		headers.SetRequestID(id)
+	}
+	for _, h := range p.headers {
+		headers.Set(h.Key, string(h.Value))
+	}
+	for _, h := range extra {
+		headers.Set(h.Key, string(h.Value))
+	}
+	// Carry the publish span's context so consumers can continue the trace
+	otel.GetTextMapPropagator().Inject(ctx, &headers)
+
+	msg := kafka.Message{
+		Topic:   topic,
+		Key:     p.messageKey(evt),
+		Value:   value,
+		Headers: headers.Kafka(),
+	}
+	// Rather than have the writer or broker reject it with a cryptic error
+	if size := messageSize(msg); p.maxBytes > 0 && size > p.maxBytes {
+		return kafka.Message{}, fmt.Errorf("kafka publish %s: %w", evt.EventType,
+			&MessageTooLargeError{OrderID: evt.OrderID, EventType: evt.EventType, Size: size, Limit: p.maxBytes})
+	}
+	return msg, nil
+}
+
+// topicFor returns the topic for events of eventType: the router's choice
+// if one is set, otherwise the publisher's topic.
+func (p *Publisher) topicFor(eventType string) string {
+	if p.topicRouter == nil {
+		return p.topic
+	}
+	return p.topicRouter(eventType)
+}
+
+// messageKey returns the partition key of evt as a message key.
+func (p *Publisher) messageKey(evt messaging.OrderEvent) []byte {
+	return []byte(p.partitionKeyOf(evt))
+}
+
+// writeFailed wraps the cause of a failed write of msg in ErrPublishFailed,
+// and in messaging.ErrBrokerUnavailable if the brokers could not be
+// reached, and dead-letters msg, if configured.
+func (p *Publisher) writeFailed(ctx context.Context, evt messaging.OrderEvent, msg kafka.Message, cause error, attempts int) error {
+	err := fmt.Errorf("kafka write %s: %w: %w", evt.EventType, ErrPublishFailed, cause)
+	if brokerUnavailable(cause) {
+		err = fmt.Errorf("kafka write %s: %w: %w: %w", evt.EventType, ErrPublishFailed, messaging.ErrBrokerUnavailable, cause)
+	}
+	if p.deadLetter != nil && p.deadLetter.enabled() {
+		// The event is kept for replay, but the caller still learns that
+		// it did not reach the topic
+		if dlqErr := p.sendDeadLetter(ctx, msg, err, attempts); dlqErr != nil {
+			return errors.Join(err, dlqErr)
+		}
+	}
+	return err
+}
+
+// brokerUnavailable reports whether err means the brokers could not be
+// reached, did not answer in time or returned an error Kafka documents as
+// retriable, rather than that they rejected the message.
+func brokerUnavailable(err error) bool {
+	var netErr net.Error
+	var kafkaErr kafka.Error
+	var writeErrs kafka.WriteErrors
+	switch {
+	// Before net.Error, which kafka.Error also implements
+	case errors.As(err, &kafkaErr):
+		return kafkaErr.Timeout() || kafkaErr.Temporary()
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr):
+		return true
+	case errors.As(err, &writeErrs):
+		return slices.ContainsFunc(writeErrs, func(err error) bool { return err != nil && brokerUnavailable(err) })
+	}
+	return false
 }
@@ -4,15 +4,23 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math"
+	"net"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/codec"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/noop"
 	kafkago "github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 // mockWriter captures messages written to Kafka for test assertions.
@@ -20,13 +28,23 @@ type mockWriter struct {
 	mu       sync.Mutex
 	messages []kafkago.Message
 	err      error
+	failN    int // fail this many writes with err before succeeding
+	attempts int
 	closed   bool
+	// topicErr fails every write to the given topics
+	topicErr map[string]error
 }
 
 func (m *mockWriter) WriteMessages(_ context.Context, msgs ...kafkago.Message) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	if m.err != nil {
+	m.attempts++
+	for _, msg := range msgs {
+		if err := m.topicErr[msg.Topic]; err != nil {
+			return err
+		}
+	}
+	if m.err != nil && (m.failN == 0 || m.attempts <= m.failN) {
 		return m.err
 	}
 	m.messages = append(m.messages, msgs...)
@@ -47,18 +65,33 @@ func (m *mockWriter) lastMessage() kafkago.Message {
 }
 
 func newTestPublisher(w *mockWriter) *Publisher {
-	return &Publisher{writer: w, topic: "order-events"}
+	return &Publisher{writer: w, topic: "order-events", serializer: messaging.JSONSerializer{},
+		metrics: noop.Metrics{}, logger: discardLogger, partitionKey: KeyByOrderID, clock: messaging.SystemClock{}}
 }
 
+// mustNew creates a publisher for order-events with opts, failing the test
+// if New fails.
+func mustNew(t *testing.T, opts ...Option) *Publisher {
+	t.Helper()
+	pub, err := New([]string{"localhost:9092"}, "order-events", opts...)
+	require.NoError(t, err)
+	return pub
+}
+
+// fakeClock always returns the same time.
+type fakeClock struct{ now time.Time }
+
+func (c fakeClock) Now() time.Time { return c.now }
+
 func newTestOrder() *domain.Order {
 	return &domain.Order{
 		ID:         uuid.New(),
 		CustomerID: "cust-123",
 		Items: []domain.OrderItem{
-			{ID: uuid.New(), ProductID: "p-1", Name: "Widget", Quantity: 2, Price: 10.50, Subtotal: 21.00},
+			{ID: uuid.New(), ProductID: "p-1", Name: "Widget", Quantity: 2, Price: domain.Money{Amount: 1050}, Subtotal: domain.Money{Amount: 2100}},
 		},
 		Status:  domain.OrderStatusPending,
-		Total:   21.00,
+		Total:   domain.Money{Amount: 2100, Currency: "USD"},
 		Version: 1,
 	}
 }
@@ -74,6 +107,7 @@ func TestPublisher_PublishOrderCreated_WritesCorrectMessage(t *testing.T) {
 	assert.Len(t, w.messages, 1)
 
 	msg := w.lastMessage()
+	assert.Equal(t, "order-events", msg.Topic)
 	assert.Equal(t, order.ID.String(), string(msg.Key))
 
 	var evt messaging.OrderEvent
@@ -81,7 +115,7 @@ func TestPublisher_PublishOrderCreated_WritesCorrectMessage(t *testing.T) {
 	assert.Equal(t, messaging.EventOrderCreated, evt.EventType)
 	assert.Equal(t, order.ID.String(), evt.OrderID)
 	assert.Equal(t, order.CustomerID, evt.CustomerID)
-	assert.Equal(t, "pending", evt.Status)
+	assert.Equal(t, domain.OrderStatusPending, evt.Status)
 	assert.Equal(t, 21.00, evt.Total)
 	assert.Equal(t, 1, evt.Version)
 	assert.Empty(t, evt.OldStatus)
@@ -96,7 +130,7 @@ func TestPublisher_PublishOrderUpdated_WritesCorrectMessage(t *testing.T) {
 	order.Status = domain.OrderStatusConfirmed
 	order.Version = 3
 
-	err := pub.PublishOrderUpdated(context.Background(), order)
+	err := pub.PublishOrderUpdated(context.Background(), order, nil)
 
 	require.NoError(t, err)
 	msg := w.lastMessage()
@@ -105,7 +139,7 @@ func TestPublisher_PublishOrderUpdated_WritesCorrectMessage(t *testing.T) {
 	var evt messaging.OrderEvent
 	require.NoError(t, json.Unmarshal(msg.Value, &evt))
 	assert.Equal(t, messaging.EventOrderUpdated, evt.EventType)
-	assert.Equal(t, "confirmed", evt.Status)
+	assert.Equal(t, domain.OrderStatusConfirmed, evt.Status)
 	assert.Equal(t, 3, evt.Version)
 }
 
@@ -128,26 +162,328 @@ func TestPublisher_PublishOrderStatusChanged_IncludesOldAndNewStatus(t *testing.
 	var evt messaging.OrderEvent
 	require.NoError(t, json.Unmarshal(msg.Value, &evt))
 	assert.Equal(t, messaging.EventOrderStatusChanged, evt.EventType)
-	assert.Equal(t, "pending", evt.OldStatus)
-	assert.Equal(t, "confirmed", evt.NewStatus)
-	assert.Equal(t, "confirmed", evt.Status)
+	assert.Equal(t, domain.OrderStatusPending, evt.OldStatus)
+	assert.Equal(t, domain.OrderStatusConfirmed, evt.NewStatus)
+	assert.Equal(t, domain.OrderStatusConfirmed, evt.Status)
+}
+
+func TestPublisher_PublishOrderCancelled_CarriesReason(t *testing.T) {
+	w := &mockWriter{}
+	pub := newTestPublisher(w)
+	order := newTestOrder()
+	order.Status = domain.OrderStatusCancelled
+
+	err := pub.PublishOrderCancelled(context.Background(), order, "out of stock")
+
+	require.NoError(t, err)
+	var evt messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(w.lastMessage().Value, &evt))
+	assert.Equal(t, messaging.EventOrderCancelled, evt.EventType)
+	assert.Equal(t, "out of stock", evt.CancelReason)
+	assert.Equal(t, domain.OrderStatusCancelled, evt.Status)
+	assert.Equal(t, order.ID.String(), string(w.lastMessage().Key))
+}
+
+func TestPublisher_WithClock_StampsOccurredAt(t *testing.T) {
+	fixed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		publish func(*Publisher, *domain.Order) error
+	}{
+		{"created", func(p *Publisher, o *domain.Order) error { return p.PublishOrderCreated(context.Background(), o) }},
+		{"updated", func(p *Publisher, o *domain.Order) error { return p.PublishOrderUpdated(context.Background(), o, nil) }},
+		{"status_changed", func(p *Publisher, o *domain.Order) error {
+			return p.PublishOrderStatusChanged(context.Background(), o, domain.OrderStatusPending, domain.OrderStatusConfirmed)
+		}},
+		{"cancelled", func(p *Publisher, o *domain.Order) error { return p.PublishOrderCancelled(context.Background(), o, "") }},
+		{"deleted", func(p *Publisher, o *domain.Order) error { return p.PublishOrderDeleted(context.Background(), o) }},
+		{"expired", func(p *Publisher, o *domain.Order) error { return p.PublishOrderExpired(context.Background(), o) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &mockWriter{}
+			pub := mustNew(t, WithClock(fakeClock{now: fixed}))
+			pub.writer = w
+
+			require.NoError(t, tt.publish(pub, newTestOrder()))
+
+			var evt messaging.OrderEvent
+			require.NoError(t, json.Unmarshal(w.lastMessage().Value, &evt))
+			assert.True(t, fixed.Equal(evt.OccurredAt), "got %v", evt.OccurredAt)
+		})
+	}
+}
+
+func TestPublisher_Publish_PrebuiltEvent_KeepsOccurredAt(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t,
+		WithClock(fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}))
+	pub.writer = w
+	evt := messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder())
+	evt.OccurredAt = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, pub.Publish(context.Background(), evt))
+
+	var got messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(w.lastMessage().Value, &got))
+	assert.True(t, evt.OccurredAt.Equal(got.OccurredAt), "relayed events keep their original time")
+}
+
+func TestPublisher_PublishOrderDeleted_WritesDeletedEvent(t *testing.T) {
+	w := &mockWriter{}
+	pub := newTestPublisher(w)
+	order := newTestOrder()
+
+	err := pub.PublishOrderDeleted(context.Background(), order)
+
+	require.NoError(t, err)
+	var evt messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(w.lastMessage().Value, &evt))
+	assert.Equal(t, messaging.EventOrderDeleted, evt.EventType)
+	assert.Equal(t, order.ID.String(), string(w.lastMessage().Key))
+}
+
+func TestPublisher_PublishOrderExpired_WritesExpiredEvent(t *testing.T) {
+	w := &mockWriter{}
+	pub := newTestPublisher(w)
+	order := newTestOrder()
+	order.Status = domain.OrderStatusExpired
+
+	require.NoError(t, pub.PublishOrderExpired(context.Background(), order))
+
+	var evt messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(w.lastMessage().Value, &evt))
+	assert.Equal(t, messaging.EventOrderExpired, evt.EventType)
+	assert.Equal(t, domain.OrderStatusExpired, evt.Status)
+	assert.Equal(t, order.ID.String(), string(w.lastMessage().Key))
+}
+
+func TestPublisher_PublishOrderSnapshot_WritesFullState(t *testing.T) {
+	w := &mockWriter{}
+	pub := newTestPublisher(w)
+	order := newTestOrder()
+	order.Status = domain.OrderStatusCancelled
+	order.CancelReason = "out of stock"
+	order.Version = 7
+	order.UpdatedAt = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	require.NoError(t, pub.PublishOrderSnapshot(context.Background(), order))
+
+	var evt messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(w.lastMessage().Value, &evt))
+	assert.Equal(t, messaging.EventOrderSnapshot, evt.EventType)
+	assert.Equal(t, domain.OrderStatusCancelled, evt.Status)
+	assert.Equal(t, "out of stock", evt.CancelReason)
+	assert.True(t, order.UpdatedAt.Equal(evt.OccurredAt), "a snapshot occurs at the order's last change, got %v", evt.OccurredAt)
+	assert.Equal(t, 7, evt.Version)
+	assert.Equal(t, order.Total.Amount, evt.TotalMinor)
+	require.Len(t, evt.Items, len(order.Items))
+	assert.Equal(t, order.Items[0].ProductID, evt.Items[0].SKU)
+	assert.Equal(t, order.ID.String(), string(w.lastMessage().Key))
 }
 
 func TestPublisher_PublishOrderCreated_WriterError_ReturnsError(t *testing.T) {
-	w := &mockWriter{err: errors.New("broker unavailable")}
+	brokerErr := errors.New("broker unavailable")
+	w := &mockWriter{err: brokerErr}
 	pub := newTestPublisher(w)
 
 	err := pub.PublishOrderCreated(context.Background(), newTestOrder())
 
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "broker unavailable")
+	assert.ErrorIs(t, err, brokerErr)
+	assert.Contains(t, err.Error(), "kafka write order.created")
+}
+
+func TestPublisher_PublishOrderCreated_InvalidEvent_NotWritten(t *testing.T) {
+	w := &mockWriter{}
+	pub := newTestPublisher(w)
+	order := newTestOrder()
+	order.CustomerID = ""
+
+	err := pub.PublishOrderCreated(context.Background(), order)
+
+	var verr *messaging.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, "customer_id", verr.Field)
+	assert.Empty(t, w.messages)
+}
+
+func TestPublisher_Failures_ClassifiedByCause(t *testing.T) {
+	unencodable := messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder())
+	unencodable.Total = math.NaN() // encoding/json rejects NaN
+	invalid := messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder())
+	invalid.OrderID = ""
+	valid := messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder())
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+	tests := []struct {
+		name     string
+		evt      messaging.OrderEvent
+		writeErr error
+		want     error
+		notWant  []error
+	}{
+		{"validation", invalid, nil, messaging.ErrValidation, []error{messaging.ErrSerialization, messaging.ErrBrokerUnavailable}},
+		{"serialization", unencodable, nil, messaging.ErrSerialization, []error{messaging.ErrValidation, messaging.ErrBrokerUnavailable}},
+		{"write_timeout", valid, context.DeadlineExceeded, messaging.ErrBrokerUnavailable, []error{messaging.ErrSerialization}},
+		{"connection_refused", valid, refused, messaging.ErrBrokerUnavailable, nil},
+		{"broker_request_timeout", valid, kafkago.RequestTimedOut, messaging.ErrBrokerUnavailable, nil},
+		{"leader_unavailable_for_one_message", valid, kafkago.WriteErrors{kafkago.LeaderNotAvailable}, messaging.ErrBrokerUnavailable, nil},
+		{"message_rejected", valid, kafkago.MessageSizeTooLarge, ErrPublishFailed, []error{messaging.ErrBrokerUnavailable}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &mockWriter{err: tt.writeErr}
+
+			err := newTestPublisher(w).Publish(context.Background(), tt.evt)
+
+			require.ErrorIs(t, err, tt.want)
+			for _, other := range tt.notWant {
+				assert.NotErrorIs(t, err, other)
+			}
+		})
+	}
+}
+
+// publishMetrics records the publishes reported to it.
+type publishMetrics struct {
+	noop.Metrics
+	mu      sync.Mutex
+	results map[string][]error // Event type -> publish results
+}
+
+func (m *publishMetrics) ObservePublish(eventType string, _ time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.results == nil {
+		m.results = make(map[string][]error)
+	}
+	m.results[eventType] = append(m.results[eventType], err)
+}
+
+func TestPublisher_WithMetrics_RecordsSuccessAndFailure(t *testing.T) {
+	brokerErr := errors.New("broker unavailable")
+	w := &mockWriter{err: brokerErr, failN: 1}
+	metrics := &publishMetrics{}
+	pub := mustNew(t, WithMetrics(metrics))
+	pub.writer = w
+
+	assert.Error(t, pub.PublishOrderCreated(context.Background(), newTestOrder()))
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newTestOrder()))
+	require.NoError(t, pub.PublishOrderUpdated(context.Background(), newTestOrder(), nil))
+
+	created := metrics.results[messaging.EventOrderCreated]
+	require.Len(t, created, 2)
+	assert.ErrorIs(t, created[0], brokerErr)
+	assert.NoError(t, created[1])
+	assert.Equal(t, []error{nil}, metrics.results[messaging.EventOrderUpdated])
+}
+
+func TestPublisher_PublishWithHeaders_AddsHeaders(t *testing.T) {
+	w := &mockWriter{}
+	pub := newTestPublisher(w)
+	evt := messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder())
+
+	err := pub.PublishWithHeaders(context.Background(), evt, map[string]string{
+		HeaderDLQOriginalTopic: "order-events",
+		HeaderDLQError:         "handler failed",
+	})
+
+	require.NoError(t, err)
+	headers := map[string]string{}
+	for _, h := range w.lastMessage().Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	assert.Equal(t, evt.EventID, headers[HeaderEventID])
+	assert.Equal(t, "order-events", headers[HeaderDLQOriginalTopic])
+	assert.Equal(t, "handler failed", headers[HeaderDLQError])
+}
+
+func TestPublisher_WithRetry_FailsTwiceThenSucceeds(t *testing.T) {
+	w := &mockWriter{err: errors.New("leader not available"), failN: 2}
+	pub := mustNew(t, WithRetry(3, time.Millisecond))
+	pub.writer = w
+
+	err := pub.PublishOrderCreated(context.Background(), newTestOrder())
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, w.attempts)
+	assert.Len(t, w.messages, 1)
+}
+
+func TestPublisher_WithRetry_Exhausted_ReturnsErrPublishFailed(t *testing.T) {
+	brokerErr := errors.New("leader not available")
+	w := &mockWriter{err: brokerErr}
+	pub := mustNew(t, WithRetry(3, time.Millisecond))
+	pub.writer = w
+
+	err := pub.PublishOrderCreated(context.Background(), newTestOrder())
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrPublishFailed)
+	assert.ErrorIs(t, err, brokerErr)
+	assert.Equal(t, 3, w.attempts)
+}
+
+func TestPublisher_WithRetry_ContextDeadline_StopsPromptly(t *testing.T) {
+	w := &mockWriter{err: errors.New("leader not available")}
+	pub := mustNew(t, WithRetry(10, time.Second))
+	pub.writer = w
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := pub.PublishOrderCreated(ctx, newTestOrder())
+
+	assert.ErrorIs(t, err, ErrPublishFailed)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 400*time.Millisecond, "should not wait out the backoff")
+	assert.Equal(t, 1, w.attempts)
+}
+
+func TestPublisher_NoRetry_SingleAttempt(t *testing.T) {
+	w := &mockWriter{err: errors.New("leader not available")}
+	pub := mustNew(t)
+	pub.writer = w
+
+	err := pub.PublishOrderCreated(context.Background(), newTestOrder())
+
+	assert.ErrorIs(t, err, ErrPublishFailed)
+	assert.Equal(t, 1, w.attempts)
+}
+
+func TestNew_AppliesOptions(t *testing.T) {
+	pub := mustNew(t,
+		WithBatchTimeout(50*time.Millisecond),
+		WithRequiredAcks(kafkago.RequireOne),
+	)
+
+	w, ok := pub.writer.(*kafkago.Writer)
+	require.True(t, ok)
+	assert.Equal(t, 50*time.Millisecond, w.BatchTimeout)
+	assert.Equal(t, kafkago.RequireOne, w.RequiredAcks)
+	assert.Empty(t, w.Topic, "topic is set per message")
+	assert.Equal(t, "order-events", pub.topic)
+}
+
+func TestNew_Defaults(t *testing.T) {
+	pub := mustNew(t)
+
+	w, ok := pub.writer.(*kafkago.Writer)
+	require.True(t, ok)
+	assert.Equal(t, 10*time.Millisecond, w.BatchTimeout)
+	assert.Equal(t, kafkago.RequireAll, w.RequiredAcks, "durable by default")
+	assert.IsType(t, &kafkago.Hash{}, w.Balancer)
 }
 
 func TestPublisher_Close_ClosesWriter(t *testing.T) {
 	w := &mockWriter{}
 	pub := newTestPublisher(w)
 
-	err := pub.Close()
+	err := pub.Close(context.Background())
 
 	assert.NoError(t, err)
 	assert.True(t, w.closed)
@@ -167,7 +503,7 @@ func TestPublisher_MessageKey_IsOrderID(t *testing.T) {
 		{
 			name: "updated",
 			publish: func(pub *Publisher, order *domain.Order) error {
-				return pub.PublishOrderUpdated(context.Background(), order)
+				return pub.PublishOrderUpdated(context.Background(), order, nil)
 			},
 		},
 		{
@@ -196,3 +532,295 @@ func TestPublisher_MessageKey_IsOrderID(t *testing.T) {
 		})
 	}
 }
+
+func TestPublisher_SameOrderID_SameKey(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t)
+	pub.writer = w
+	order := newTestOrder()
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+	order.Version = 2
+	require.NoError(t, pub.PublishOrderStatusChanged(context.Background(), order,
+		domain.OrderStatusPending, domain.OrderStatusConfirmed))
+
+	require.Len(t, w.messages, 2)
+	assert.Equal(t, w.messages[0].Key, w.messages[1].Key)
+	assert.Equal(t, []byte(order.ID.String()), w.messages[0].Key)
+}
+
+func TestPublisher_WithPartitionKey_KeysByCustomerID(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t, WithPartitionKey(KeyByCustomerID))
+	pub.writer = w
+	first, second := newTestOrder(), newTestOrder()
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), first))
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), second))
+
+	require.Len(t, w.messages, 2)
+	assert.Equal(t, []byte("cust-123"), w.messages[0].Key)
+	assert.Equal(t, w.messages[0].Key, w.messages[1].Key)
+}
+
+func TestPublisher_WithPartitionKey_UsesKeyAsMessageKey(t *testing.T) {
+	order := newTestOrder()
+	tests := []struct {
+		name  string
+		keyer PartitionKeyFunc
+		want  []byte
+	}{
+		{name: "order_id", keyer: KeyByOrderID, want: []byte(order.ID.String())},
+		{name: "customer_id", keyer: KeyByCustomerID, want: []byte(order.CustomerID)},
+		{name: "custom", keyer: func(evt messaging.OrderEvent) string { return "tenant-" + evt.CustomerID }, want: []byte("tenant-cust-123")},
+		{name: "empty_falls_back_to_order_id", keyer: func(messaging.OrderEvent) string { return "" }, want: []byte(order.ID.String())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &mockWriter{}
+			pub := mustNew(t, WithPartitionKey(tt.keyer))
+			pub.writer = w
+
+			require.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+
+			assert.Equal(t, tt.want, w.lastMessage().Key)
+		})
+	}
+}
+
+func TestPublisher_WithTopicRouter_WritesEachTypeToItsTopic(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t, WithTopicRouter(func(eventType string) string {
+		switch eventType {
+		case messaging.EventOrderCreated:
+			return "orders.created"
+		case messaging.EventOrderCancelled:
+			return "orders.cancelled"
+		}
+		return "order-events"
+	}))
+	pub.writer = w
+	order := newTestOrder()
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+	require.NoError(t, pub.PublishOrderCancelled(context.Background(), order, "out of stock"))
+	require.NoError(t, pub.PublishOrderDeleted(context.Background(), order))
+
+	require.Len(t, w.messages, 3)
+	assert.Equal(t, "orders.created", w.messages[0].Topic)
+	assert.Equal(t, "orders.cancelled", w.messages[1].Topic)
+	assert.Equal(t, "order-events", w.messages[2].Topic)
+}
+
+func TestPublisher_WithTopicRouter_EmptyTopic_ReturnsErrNoTopic(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t, WithTopicRouter(func(string) string { return "" }))
+	pub.writer = w
+
+	err := pub.PublishOrderCreated(context.Background(), newTestOrder())
+
+	assert.ErrorIs(t, err, ErrNoTopic)
+	assert.Empty(t, w.messages)
+}
+
+func TestPublisher_EventIDHeader_MatchesBody(t *testing.T) {
+	w := &mockWriter{}
+	pub := newTestPublisher(w)
+
+	err := pub.PublishOrderCreated(context.Background(), newTestOrder())
+	require.NoError(t, err)
+
+	msg := w.lastMessage()
+	var header string
+	for _, h := range msg.Headers {
+		if h.Key == HeaderEventID {
+			header = string(h.Value)
+		}
+	}
+
+	var evt messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(msg.Value, &evt))
+	assert.NotEmpty(t, evt.EventID)
+	assert.Equal(t, evt.EventID, header, "header and body must carry the same event ID")
+}
+
+func TestPublisher_PublishOrderStatusChanged_Twice_DistinctEventIDs(t *testing.T) {
+	w := &mockWriter{}
+	pub := newTestPublisher(w)
+	order := newTestOrder()
+
+	for i := 0; i < 2; i++ {
+		err := pub.PublishOrderStatusChanged(context.Background(), order,
+			domain.OrderStatusPending, domain.OrderStatusConfirmed)
+		require.NoError(t, err)
+	}
+
+	require.Len(t, w.messages, 2)
+	var first, second messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(w.messages[0].Value, &first))
+	require.NoError(t, json.Unmarshal(w.messages[1].Value, &second))
+	assert.NotEqual(t, first.EventID, second.EventID)
+}
+
+func TestPublisher_WithEnvelopeFormat_CloudEvents(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t, WithEnvelopeFormat(messaging.FormatCloudEvents))
+	pub.writer = w
+	order := newTestOrder()
+
+	err := pub.PublishOrderCreated(context.Background(), order)
+	require.NoError(t, err)
+
+	msg := w.lastMessage()
+	assert.Equal(t, order.ID.String(), string(msg.Key))
+
+	var ce messaging.CloudEvent
+	require.NoError(t, json.Unmarshal(msg.Value, &ce))
+	assert.Equal(t, "io.ordersvc.order.created", ce.Type)
+	assert.Equal(t, order.ID.String(), ce.Data.OrderID)
+
+	headers := map[string]string{}
+	for _, h := range msg.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	assert.Equal(t, ce.ID, headers[HeaderEventID])
+	assert.Equal(t, messaging.CloudEventsContentType, headers[HeaderContentType])
+}
+
+func TestPublisher_WithSerializer_Protobuf_SetsContentType(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t, WithSerializer(messaging.ProtobufSerializer{}))
+	pub.writer = w
+	order := newTestOrder()
+
+	err := pub.PublishOrderStatusChanged(context.Background(), order,
+		domain.OrderStatusPending, domain.OrderStatusConfirmed)
+	require.NoError(t, err)
+
+	msg := w.lastMessage()
+	headers := map[string]string{}
+	for _, h := range msg.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	assert.Equal(t, messaging.ContentTypeProtobuf, headers[HeaderContentType])
+
+	evt, err := Decode(msg)
+	require.NoError(t, err)
+	assert.Equal(t, order.ID.String(), evt.OrderID)
+	assert.Equal(t, domain.OrderStatusPending, evt.OldStatus)
+	assert.Equal(t, domain.OrderStatusConfirmed, evt.NewStatus)
+}
+
+func TestNew_WithCodec_SetsSerializer(t *testing.T) {
+	pub := mustNew(t, WithCodec(codec.ProtoCodec{}))
+	assert.Equal(t, codec.ProtoCodec{}, pub.serializer)
+
+	pub = mustNew(t)
+	assert.Equal(t, codec.JSONCodec{}, pub.serializer, "JSON is the default")
+}
+
+func TestPublisher_DefaultSerializer_SetsJSONContentType(t *testing.T) {
+	w := &mockWriter{}
+	pub := newTestPublisher(w)
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newTestOrder()))
+
+	var contentType string
+	for _, h := range w.lastMessage().Headers {
+		if h.Key == HeaderContentType {
+			contentType = string(h.Value)
+		}
+	}
+	assert.Equal(t, messaging.ContentTypeJSON, contentType)
+}
+
+func TestPublisher_SetsSchemaVersion(t *testing.T) {
+	w := &mockWriter{}
+	pub := newTestPublisher(w)
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newTestOrder()))
+
+	msg := w.lastMessage()
+	headers := map[string]string{}
+	for _, h := range msg.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	assert.Equal(t, "9", headers[HeaderSchemaVersion])
+	var evt messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(msg.Value, &evt))
+	assert.Equal(t, messaging.CurrentSchemaVersion, evt.SchemaVersion)
+}
+
+func TestDecode_FutureSchemaVersion_ReturnsSchemaVersionError(t *testing.T) {
+	evt := messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder())
+	evt.SchemaVersion = messaging.CurrentSchemaVersion + 1
+	value, err := json.Marshal(evt)
+	require.NoError(t, err)
+
+	_, err = Decode(kafkago.Message{Value: value})
+
+	var verr *messaging.SchemaVersionError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, messaging.CurrentSchemaVersion+1, verr.Version)
+}
+
+func TestPublisher_InjectsTraceContextHeaders(t *testing.T) {
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+
+	tp := sdktrace.NewTracerProvider()
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	ctx, span := tp.Tracer("test").Start(context.Background(), "create order")
+	defer span.End()
+
+	w := &mockWriter{}
+	pub := newTestPublisher(w)
+	require.NoError(t, pub.PublishOrderCreated(ctx, newTestOrder()))
+
+	msg := w.lastMessage()
+	assert.Contains(t, messaging.HeadersFromKafka(msg.Headers).TraceParent(), span.SpanContext().TraceID().String())
+}
+
+func TestPublisher_ContextMetadata_SetsHeaders(t *testing.T) {
+	ctx := messaging.WithTenantID(context.Background(), "tenant-42")
+	ctx = messaging.WithRequestID(ctx, "req-abc")
+
+	tests := []struct {
+		name    string
+		publish func(*Publisher) error
+	}{
+		{name: "publish", publish: func(p *Publisher) error { return p.PublishOrderCreated(ctx, newTestOrder()) }},
+		{name: "batch", publish: func(p *Publisher) error { return p.PublishBatch(ctx, newTestBatch(1, 1)) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &mockWriter{}
+			require.NoError(t, tt.publish(newTestPublisher(w)))
+
+			msg := w.lastMessage()
+			headers := messaging.HeadersFromKafka(msg.Headers)
+			assert.Equal(t, "tenant-42", headers.TenantID())
+			assert.Equal(t, "req-abc", headers.RequestID())
+
+			var evt messaging.OrderEvent
+			require.NoError(t, json.Unmarshal(msg.Value, &evt))
+			assert.Equal(t, "req-abc", evt.CorrelationID)
+		})
+	}
+}
+
+func TestPublisher_NoContextMetadata_OmitsHeaders(t *testing.T) {
+	w := &mockWriter{}
+	pub := newTestPublisher(w)
+	ctx := messaging.WithTenantID(context.Background(), "")
+
+	require.NoError(t, pub.PublishOrderCreated(ctx, newTestOrder()))
+
+	for _, h := range w.lastMessage().Headers {
+		assert.NotEqual(t, HeaderTenantID, h.Key)
+		assert.NotEqual(t, HeaderRequestID, h.Key)
+	}
+}
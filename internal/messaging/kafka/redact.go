@@ -0,0 +1,81 @@
+package kafka
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+// Redactor edits an event in place before it is serialized, to keep
+// sensitive fields such as the shipping address off the topic.
+type Redactor func(evt *messaging.OrderEvent)
+
+// WithRedactor runs r on every event just before it is serialized. The
+// event has already been validated, so r may blank required fields; its
+// partition key and headers are taken from the unredacted event, so
+// redacting the customer ID does not move it to another partition. r gets
+// a copy: the caller's event and order are left unchanged. Tombstones
+// carry no payload and are not redacted. Defaults to none.
+func WithRedactor(r Redactor) Option {
+	return func(o *options) { o.redactor = r }
+}
+
+// visibleIDChars is how many trailing characters of an ID RedactAddress
+// leaves readable.
+const visibleIDChars = 4
+
+// RedactAddress is a Redactor that drops the shipping address and masks
+// the customer ID, leaving only its last four characters, which is enough
+// to tell customers apart when debugging but not to look one up. The
+// customer IDs before and after an update are masked the same way.
+func RedactAddress(evt *messaging.OrderEvent) {
+	evt.ShippingAddress = nil
+	evt.CustomerID = maskID(evt.CustomerID)
+	if c, ok := evt.Changes["customer_id"]; ok {
+		evt.Changes["customer_id"] = messaging.ChangePair{Old: maskJSONID(c.Old), New: maskJSONID(c.New)}
+	}
+}
+
+// maskJSONID is maskID for an ID encoded as a JSON string. Anything else,
+// an absent value included, is returned as it is.
+func maskJSONID(raw json.RawMessage) json.RawMessage {
+	var id string
+	if json.Unmarshal(raw, &id) != nil {
+		return raw
+	}
+	masked, _ := json.Marshal(maskID(id)) // A string always encodes
+	return masked
+}
+
+// maskID replaces all but the last visibleIDChars characters of id with
+// '*', and all of an ID no longer than that.
+func maskID(id string) string {
+	runes := []rune(id)
+	keep := len(runes) - visibleIDChars
+	if keep <= 0 {
+		return strings.Repeat("*", len(runes))
+	}
+	return strings.Repeat("*", keep) + string(runes[keep:])
+}
+
+// redact returns evt as the publisher's redactor leaves it. The fields
+// evt shares with its caller are copied first, so the redactor can change
+// them freely.
+func (p *Publisher) redact(evt messaging.OrderEvent) messaging.OrderEvent {
+	if p.redactor == nil {
+		return evt
+	}
+	evt.Items = slices.Clone(evt.Items)
+	evt.ChangedFields = slices.Clone(evt.ChangedFields)
+	evt.Metadata = maps.Clone(evt.Metadata)
+	evt.Changes = maps.Clone(evt.Changes)
+	if evt.ShippingAddress != nil {
+		addr := *evt.ShippingAddress
+		evt.ShippingAddress = &addr
+	}
+	p.redactor(&evt)
+	return evt
+}
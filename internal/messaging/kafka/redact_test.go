@@ -0,0 +1,131 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eventWithAddress(order *domain.Order) messaging.OrderEvent {
+	evt := messaging.NewOrderEvent(messaging.EventOrderCreated, order)
+	evt.ShippingAddress = &messaging.AddressEvent{Line1: "1 Main St", City: "Springfield", PostalCode: "12345", Country: "US"}
+	return evt
+}
+
+func TestWithRedactor_RedactAddress_RedactsPayloadOnly(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t, WithRedactor(RedactAddress), WithPartitionKey(KeyByCustomerID))
+	pub.writer = w
+	order := newTestOrder()
+	evt := eventWithAddress(order)
+
+	require.NoError(t, pub.Publish(context.Background(), evt))
+
+	msg := w.lastMessage()
+	var got messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(msg.Value, &got))
+	assert.Nil(t, got.ShippingAddress)
+	assert.Equal(t, "****-123", got.CustomerID)
+	assert.Equal(t, order.ID.String(), got.OrderID, "other fields are kept")
+	assert.Equal(t, "cust-123", string(msg.Key), "the key comes from the unredacted event")
+
+	assert.Equal(t, "cust-123", order.CustomerID, "the order is unchanged")
+	assert.Equal(t, "cust-123", evt.CustomerID, "the event is unchanged")
+	require.NotNil(t, evt.ShippingAddress)
+	assert.Equal(t, "1 Main St", evt.ShippingAddress.Line1)
+}
+
+func TestWithRedactor_RedactAddress_MasksCustomerIDChange(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t, WithRedactor(RedactAddress))
+	pub.writer = w
+	order := newTestOrder()
+	changes := map[string]messaging.ChangePair{
+		"customer_id": {Old: json.RawMessage(`"cust-9876"`), New: json.RawMessage(`"cust-123"`)},
+		"total":       {Old: json.RawMessage(`1000`), New: json.RawMessage(`2000`)},
+	}
+
+	require.NoError(t, pub.PublishOrderUpdated(context.Background(), order, changes))
+
+	var got messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(w.lastMessage().Value, &got))
+	assert.JSONEq(t, `"*****9876"`, string(got.Changes["customer_id"].Old))
+	assert.JSONEq(t, `"****-123"`, string(got.Changes["customer_id"].New))
+	assert.JSONEq(t, `2000`, string(got.Changes["total"].New), "other changes are kept")
+	assert.Equal(t, `"cust-9876"`, string(changes["customer_id"].Old), "the caller's changes are not shared")
+}
+
+func TestWithRedactor_EditsCopies(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t, WithRedactor(func(evt *messaging.OrderEvent) {
+		evt.ShippingAddress.Line1 = ""
+		evt.Items[0].Name = ""
+	}))
+	pub.writer = w
+	evt := eventWithAddress(newTestOrder())
+
+	require.NoError(t, pub.Publish(context.Background(), evt))
+
+	var got messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(w.lastMessage().Value, &got))
+	assert.Empty(t, got.ShippingAddress.Line1)
+	assert.Empty(t, got.Items[0].Name)
+	assert.Equal(t, "1 Main St", evt.ShippingAddress.Line1, "the caller's address is not shared")
+	assert.Equal(t, "Widget", evt.Items[0].Name, "the caller's items are not shared")
+}
+
+func TestWithRedactor_RunsAfterValidation(t *testing.T) {
+	calls := 0
+	w := &mockWriter{}
+	pub := mustNew(t, WithRedactor(func(evt *messaging.OrderEvent) {
+		calls++
+		evt.CustomerID = "" // Required, but only checked on the original
+	}))
+	pub.writer = w
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newTestOrder()))
+	assert.Equal(t, 1, calls)
+	assert.Len(t, w.messages, 1)
+
+	err := pub.Publish(context.Background(), messaging.OrderEvent{EventType: messaging.EventOrderCreated})
+	var verr *messaging.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, 1, calls, "an invalid event is not redacted")
+	assert.Len(t, w.messages, 1)
+}
+
+func TestWithRedactor_TombstoneNotRedacted(t *testing.T) {
+	calls := 0
+	w := &mockWriter{}
+	pub := mustNew(t, WithTombstoneOnDelete(true), WithRedactor(func(*messaging.OrderEvent) { calls++ }))
+	pub.writer = w
+
+	require.NoError(t, pub.PublishOrderDeleted(context.Background(), newTestOrder()))
+
+	assert.Nil(t, w.lastMessage().Value)
+	assert.Zero(t, calls)
+}
+
+func TestMaskID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"cust-123", "****-123"},
+		{"1234", "****"},
+		{"ab", "**"},
+		{"", ""},
+		{"kündin-42", "*****n-42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			assert.Equal(t, tt.want, maskID(tt.id))
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// DefaultMaxMessageBytes is the default limit of WithMaxMessageBytes,
+// matching the 1MiB message.max.bytes brokers ship with.
+const DefaultMaxMessageBytes = 1 << 20
+
+// ErrMessageTooLarge is wrapped by a *MessageTooLargeError.
+var ErrMessageTooLarge = errors.New("kafka message too large")
+
+// MessageTooLargeError reports an event whose encoded message exceeds the
+// limit set with WithMaxMessageBytes, typically an order with a very long
+// list of items. The message was not written.
+type MessageTooLargeError struct {
+	OrderID   string
+	EventType string
+	Size      int // Bytes of key, value and headers
+	Limit     int
+}
+
+func (e *MessageTooLargeError) Error() string {
+	return fmt.Sprintf("%s: %s for order %s is %d bytes, limit %d", ErrMessageTooLarge, e.EventType, e.OrderID, e.Size, e.Limit)
+}
+
+// Unwrap returns ErrMessageTooLarge.
+func (e *MessageTooLargeError) Unwrap() error { return ErrMessageTooLarge }
+
+// WithMaxMessageBytes rejects events whose encoded message, counting its
+// key, value and headers, is larger than n bytes with a
+// *MessageTooLargeError, before attempting the write. It also caps the
+// writer's batches at n bytes. Set it to the topic's max.message.bytes.
+// Defaults to DefaultMaxMessageBytes. New returns an error if n is not
+// positive.
+func WithMaxMessageBytes(n int) Option {
+	return func(o *options) { o.maxBytes = n }
+}
+
+// messageSize returns the bytes msg counts against the message size limit.
+func messageSize(msg kafka.Message) int {
+	n := len(msg.Key) + len(msg.Value)
+	for _, h := range msg.Headers {
+		n += len(h.Key) + len(h.Value)
+	}
+	return n
+}
@@ -0,0 +1,83 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderWithItems returns the test order with n line items.
+func orderWithItems(n int) *domain.Order {
+	order := newTestOrder()
+	order.Items = make([]domain.OrderItem, n)
+	for i := range order.Items {
+		order.Items[i] = domain.OrderItem{
+			ID: uuid.New(), ProductID: fmt.Sprintf("p-%d", i), Name: "Widget", Quantity: 1,
+			Price: domain.Money{Amount: 100}, Subtotal: domain.Money{Amount: 100},
+		}
+	}
+	order.Total = domain.Money{Amount: int64(n) * 100, Currency: "USD"}
+	return order
+}
+
+func TestPublisher_WithMaxMessageBytes_OversizedEvent_RejectedBeforeWrite(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t, WithMaxMessageBytes(4096))
+	pub.writer = w
+	order := orderWithItems(100)
+
+	err := pub.PublishOrderCreated(context.Background(), order)
+
+	require.ErrorIs(t, err, ErrMessageTooLarge)
+	var tooLarge *MessageTooLargeError
+	require.ErrorAs(t, err, &tooLarge)
+	assert.Equal(t, order.ID.String(), tooLarge.OrderID)
+	assert.Equal(t, 4096, tooLarge.Limit)
+	assert.Greater(t, tooLarge.Size, 4096)
+	assert.Contains(t, err.Error(), order.ID.String())
+	assert.Contains(t, err.Error(), fmt.Sprintf("%d bytes, limit 4096", tooLarge.Size))
+	assert.Zero(t, w.attempts, "no write is attempted")
+}
+
+func TestPublisher_WithMaxMessageBytes_UnderLimit_Written(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t, WithMaxMessageBytes(4096))
+	pub.writer = w
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), orderWithItems(2)))
+
+	require.Len(t, w.messages, 1)
+	assert.LessOrEqual(t, messageSize(w.messages[0]), 4096)
+}
+
+func TestPublisher_DefaultMaxMessageBytes_RejectsOverOneMiB(t *testing.T) {
+	w := &mockWriter{}
+	pub := mustNew(t)
+	pub.writer = w
+
+	err := pub.PublishOrderCreated(context.Background(), orderWithItems(10000))
+
+	assert.ErrorIs(t, err, ErrMessageTooLarge)
+	assert.Zero(t, w.attempts)
+}
+
+func TestNew_WithMaxMessageBytes_ConfiguresWriter(t *testing.T) {
+	writer := mustNew(t, WithMaxMessageBytes(512*1024)).writer.(*kafkago.Writer)
+	assert.EqualValues(t, 512*1024, writer.BatchBytes)
+
+	writer = mustNew(t).writer.(*kafkago.Writer)
+	assert.EqualValues(t, DefaultMaxMessageBytes, writer.BatchBytes)
+}
+
+func TestNew_WithMaxMessageBytes_NotPositive_ReturnsError(t *testing.T) {
+	pub, err := New([]string{"localhost:9092"}, "order-events", WithMaxMessageBytes(0))
+
+	require.Error(t, err)
+	assert.Nil(t, pub)
+}
@@ -0,0 +1,90 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+// ErrInvalidConfig is wrapped by every error New returns for an option
+// with an invalid value, alongside the option's own error where it has
+// one, such as ErrUnknownAcks. Retrying does not help.
+var ErrInvalidConfig = errors.New("kafka: invalid configuration")
+
+// ErrNoBrokersReachable is returned by New, when WithStartupRetry is set,
+// if no broker answered in any attempt. It wraps
+// messaging.ErrBrokerUnavailable and the last error from each broker.
+var ErrNoBrokersReachable = errors.New("kafka: no brokers reachable")
+
+// startupProbeTimeout bounds the wait for one broker's answer during the
+// startup check.
+const startupProbeTimeout = 5 * time.Second
+
+// startupRetry holds the settings of WithStartupRetry.
+type startupRetry struct {
+	attempts int
+	delay    time.Duration
+}
+
+// WithStartupRetry makes New check that the cluster is reachable before
+// returning. Each attempt asks every broker in turn for the topic's
+// metadata, and the check passes as soon as one answers, so a dead broker
+// early in the list does not fail startup. An attempt in which none
+// answers is repeated after delay, up to attempts in all, before New
+// gives up with ErrNoBrokersReachable. Without it, New does not connect;
+// the first publish does. New returns an error wrapping ErrInvalidConfig
+// if attempts is less than 1 or delay is negative.
+func WithStartupRetry(attempts int, delay time.Duration) Option {
+	return func(o *options) { o.startup = &startupRetry{attempts: attempts, delay: delay} }
+}
+
+func (s startupRetry) validate() error {
+	if s.attempts < 1 {
+		return fmt.Errorf("startup retry attempts must be at least 1, got %d", s.attempts)
+	}
+	if s.delay < 0 {
+		return fmt.Errorf("startup retry delay must not be negative, got %s", s.delay)
+	}
+	return nil
+}
+
+// awaitBrokers probes the publisher's brokers, one at a time, until one
+// answers or every attempt of s has failed.
+func (p *Publisher) awaitBrokers(s startupRetry) error {
+	// The address lists the brokers separated by commas
+	addrs := strings.Split(p.brokers.String(), ",")
+	errs := make([]error, len(addrs))
+	for attempt := 1; ; attempt++ {
+		for i, addr := range addrs {
+			if errs[i] = p.probe(addr); errs[i] == nil {
+				return nil
+			}
+			errs[i] = fmt.Errorf("%s: %w", addr, errs[i])
+		}
+		if attempt >= s.attempts {
+			break
+		}
+		p.logger.Warn("no kafka broker reachable, retrying",
+			slog.Int("attempt", attempt), slog.Duration("delay", s.delay),
+			slog.String("error", errors.Join(errs...).Error()))
+		time.Sleep(s.delay)
+	}
+	return fmt.Errorf("%w after %d attempts: %w: %w", ErrNoBrokersReachable, s.attempts,
+		messaging.ErrBrokerUnavailable, errors.Join(errs...))
+}
+
+// probe fetches the metadata of the publisher's topic from the broker at
+// addr alone.
+func (p *Publisher) probe(addr string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), startupProbeTimeout)
+	defer cancel()
+	client := &kafka.Client{Addr: kafka.TCP(addr), Transport: p.transport}
+	_, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{p.topic}})
+	return err
+}
@@ -0,0 +1,83 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_WithStartupRetry_FirstBrokerDead_LaterBrokerAlive_Succeeds(t *testing.T) {
+	tests := []struct {
+		name    string
+		brokers func(t *testing.T) []string
+	}{
+		{"separate_entries", func(t *testing.T) []string {
+			return []string{closedPort(t), closedPort(t), fakeBroker(t, "order-events")}
+		}},
+		{"unresolvable_host_first", func(t *testing.T) []string {
+			return []string{"kafka.invalid:9092", fakeBroker(t, "order-events")}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub, err := New(tt.brokers(t), "order-events", WithStartupRetry(1, 0))
+
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = pub.Close(t.Context()) })
+		})
+	}
+}
+
+func TestNew_WithStartupRetry_NoBrokerReachable_ReturnsErrNoBrokersReachable(t *testing.T) {
+	dead1, dead2 := closedPort(t), closedPort(t)
+	const delay = 30 * time.Millisecond
+
+	start := time.Now()
+	pub, err := New([]string{dead1, dead2}, "order-events", WithStartupRetry(3, delay))
+
+	assert.Nil(t, pub)
+	assert.ErrorIs(t, err, ErrNoBrokersReachable)
+	assert.ErrorIs(t, err, messaging.ErrBrokerUnavailable)
+	assert.NotErrorIs(t, err, ErrInvalidConfig)
+	assert.ErrorContains(t, err, dead1)
+	assert.ErrorContains(t, err, dead2)
+	assert.GreaterOrEqual(t, time.Since(start), 2*delay, "waits between the 3 attempts")
+}
+
+func TestNew_WithoutStartupRetry_DoesNotConnect(t *testing.T) {
+	pub, err := New([]string{closedPort(t)}, "order-events")
+
+	require.NoError(t, err)
+	assert.NotNil(t, pub)
+}
+
+func TestNew_InvalidOption_ReturnsErrInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  Option
+	}{
+		{"unknown_compression", WithCompression(Compression(99))},
+		{"unknown_acks", WithAcks(Acks(99))},
+		{"max_message_bytes_not_positive", WithMaxMessageBytes(0)},
+		{"max_in_flight_not_positive", WithMaxInFlight(0)},
+		{"negative_write_timeout", WithWriteTimeout(-time.Second)},
+		{"startup_retry_no_attempts", WithStartupRetry(0, time.Second)},
+		{"startup_retry_negative_delay", WithStartupRetry(3, -time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The broker is alive, so only the option can fail New; tt.opt
+			// comes last to override the valid startup retry
+			pub, err := New([]string{fakeBroker(t, "order-events")}, "order-events", WithStartupRetry(1, 0), tt.opt)
+
+			assert.Nil(t, pub)
+			assert.ErrorIs(t, err, ErrInvalidConfig)
+			assert.NotErrorIs(t, err, ErrNoBrokersReachable)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package kafka
+
+import (
+	"context"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// WithWriteTimeout bounds each write to the brokers, including every retry
+// and dead-letter write, to d, so a hung broker cannot block a publish
+// for longer than the caller expects. The caller's context still applies:
+// a write ends at whichever deadline comes first. A write that times out
+// fails with an error wrapping messaging.ErrBrokerUnavailable and
+// context.DeadlineExceeded. Defaults to 0, no timeout beyond the caller's
+// context. New returns an error if d is negative.
+func WithWriteTimeout(d time.Duration) Option {
+	return func(o *options) { o.writeTimeout = d }
+}
+
+// writeMessages writes msgs with the publisher's writer, giving up after
+// the write timeout if one is set.
+func (p *Publisher) writeMessages(ctx context.Context, msgs ...kafka.Message) error {
+	if p.writeTimeout <= 0 {
+		return p.writer.WriteMessages(ctx, msgs...)
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.writeTimeout)
+	defer cancel()
+	return p.writer.WriteMessages(ctx, msgs...)
+}
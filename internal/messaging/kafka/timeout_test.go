@@ -0,0 +1,109 @@
+package kafka
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hungWriter never completes a write, like a broker that accepted the
+// connection and stopped answering, returning only once ctx is done.
+type hungWriter struct {
+	mu        sync.Mutex
+	attempts  int
+	deadlines []bool // Whether each write's context had a deadline
+}
+
+func (w *hungWriter) WriteMessages(ctx context.Context, _ ...kafkago.Message) error {
+	w.mu.Lock()
+	w.attempts++
+	_, ok := ctx.Deadline()
+	w.deadlines = append(w.deadlines, ok)
+	w.mu.Unlock()
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (w *hungWriter) Close() error { return nil }
+
+func TestPublisher_WithWriteTimeout_HungBroker_ReturnsWithinTimeout(t *testing.T) {
+	pub := mustNew(t, WithWriteTimeout(50*time.Millisecond))
+	pub.writer = &hungWriter{}
+
+	start := time.Now()
+	err := pub.PublishOrderCreated(context.Background(), newTestOrder())
+	elapsed := time.Since(start)
+
+	require.ErrorIs(t, err, messaging.ErrBrokerUnavailable)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, err, ErrPublishFailed)
+	assert.GreaterOrEqual(t, elapsed, 50*time.Millisecond)
+	assert.Less(t, elapsed, 500*time.Millisecond)
+}
+
+func TestPublisher_WithWriteTimeout_AppliesToEachAttempt(t *testing.T) {
+	w := &hungWriter{}
+	pub := mustNew(t, WithWriteTimeout(20*time.Millisecond), WithRetry(3, time.Millisecond))
+	pub.writer = w
+
+	err := pub.PublishOrderCreated(context.Background(), newTestOrder())
+
+	require.ErrorIs(t, err, messaging.ErrBrokerUnavailable)
+	assert.Equal(t, 3, w.attempts, "a timed out attempt is retried")
+}
+
+func TestPublisher_WithWriteTimeout_CallerDeadlineStillApplies(t *testing.T) {
+	pub := mustNew(t, WithWriteTimeout(10*time.Second))
+	pub.writer = &hungWriter{}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := pub.PublishOrderCreated(ctx, newTestOrder())
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestPublisher_WithWriteTimeout_BatchAndDeadLetterWritesBounded(t *testing.T) {
+	w := &hungWriter{}
+	pub := mustNew(t, WithWriteTimeout(20*time.Millisecond), WithDeadLetter("order-events-dlq"))
+	pub.writer = w
+	events := []messaging.OrderEvent{
+		messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder()),
+		messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder()),
+	}
+
+	start := time.Now()
+	err := pub.PublishBatch(context.Background(), events)
+
+	require.ErrorIs(t, err, messaging.ErrBrokerUnavailable)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, 3, w.attempts, "one batch write and a dead-letter write per event")
+	assert.Equal(t, []bool{true, true, true}, w.deadlines)
+}
+
+func TestPublisher_NoWriteTimeout_OnlyCallerContext(t *testing.T) {
+	w := &hungWriter{}
+	pub := mustNew(t)
+	pub.writer = w
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := pub.PublishOrderCreated(ctx, newTestOrder())
+
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []bool{false}, w.deadlines, "no deadline is added by default")
+}
+
+func TestNew_NegativeWriteTimeout_ReturnsError(t *testing.T) {
+	_, err := New([]string{"localhost:9092"}, "order-events", WithWriteTimeout(-time.Second))
+
+	assert.ErrorContains(t, err, "write timeout must not be negative")
+}
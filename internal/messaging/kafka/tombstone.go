@@ -0,0 +1,83 @@
+package kafka
+
+import (
+	"errors"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+// Message headers carrying the rest of the event metadata of a tombstone,
+// which has no payload. See WithTombstoneOnCancel.
+const (
+	HeaderStatus     = messaging.HeaderStatus
+	HeaderVersion    = messaging.HeaderVersion
+	HeaderOccurredAt = messaging.HeaderOccurredAt
+)
+
+// ErrTombstone is returned by Decode for a message with no value that
+// lacks the headers of a tombstone written by Publisher, such as one
+// written by another producer.
+var ErrTombstone = errors.New("kafka: tombstone without event headers")
+
+// WithTombstoneOnCancel makes PublishOrderCancelled write a tombstone: a
+// message keyed by order ID with a nil value, so a log-compacted topic
+// eventually drops the order's key. The event's ID, type, order ID,
+// status, version and time travel in headers instead, and the cancel
+// reason, items and totals are not sent. Defaults to false.
+//
+// Consumers must accept nil-value messages. Decode, and so Consumer,
+// rebuilds the event from the headers; other consumers see a message with
+// no payload. Compaction only drops the order's earlier events if they
+// share its key, so do not combine this with a WithPartitionKey that keys
+// by something other than the order ID.
+func WithTombstoneOnCancel(on bool) Option {
+	return withTombstone(messaging.EventOrderCancelled, on)
+}
+
+// WithTombstoneOnDelete is WithTombstoneOnCancel for PublishOrderDeleted.
+func WithTombstoneOnDelete(on bool) Option {
+	return withTombstone(messaging.EventOrderDeleted, on)
+}
+
+func withTombstone(eventType string, on bool) Option {
+	return func(o *options) {
+		if o.tombstones == nil {
+			o.tombstones = make(map[string]bool)
+		}
+		o.tombstones[eventType] = on
+	}
+}
+
+// setTombstoneHeaders sets the headers carrying the metadata of evt that
+// a tombstone lacks beyond those of every message.
+func setTombstoneHeaders(h *messaging.Headers, evt messaging.OrderEvent) {
+	h.SetStatus(evt.Status)
+	h.SetVersion(evt.Version)
+	h.SetOccurredAt(evt.OccurredAt)
+}
+
+// decodeTombstone rebuilds the event of a tombstone from its headers.
+func decodeTombstone(msg kafka.Message) (messaging.OrderEvent, error) {
+	h := messaging.HeadersFromKafka(msg.Headers)
+	evt := messaging.OrderEvent{
+		EventID:   h.EventID(),
+		EventType: h.EventType(),
+		OrderID:   h.OrderID(),
+		Status:    h.Status(),
+	}
+	if evt.EventType == "" || evt.OrderID == "" {
+		return messaging.OrderEvent{}, ErrTombstone
+	}
+	var err error
+	if evt.SchemaVersion, err = h.SchemaVersion(); err != nil {
+		return messaging.OrderEvent{}, errors.Join(ErrTombstone, err)
+	}
+	if evt.Version, err = h.Version(); err != nil {
+		return messaging.OrderEvent{}, errors.Join(ErrTombstone, err)
+	}
+	if evt.OccurredAt, err = h.OccurredAt(); err != nil {
+		return messaging.OrderEvent{}, errors.Join(ErrTombstone, err)
+	}
+	return evt, nil
+}
@@ -0,0 +1,97 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTombstonePublisher returns a publisher writing to w with opts.
+func newTombstonePublisher(t *testing.T, w *mockWriter, opts ...Option) *Publisher {
+	t.Helper()
+	pub := mustNew(t, opts...)
+	pub.writer = w
+	return pub
+}
+
+func TestWithTombstoneOnCancel_WritesNilValueWithMetadataHeaders(t *testing.T) {
+	w := &mockWriter{}
+	occurred := time.Date(2026, 3, 14, 10, 0, 0, 123456789, time.UTC)
+	pub := newTombstonePublisher(t, w, WithTombstoneOnCancel(true), WithClock(fakeClock{now: occurred}))
+	order := newTestOrder()
+	order.Status = domain.OrderStatusCancelled
+	order.Version = 3
+
+	require.NoError(t, pub.PublishOrderCancelled(context.Background(), order, "customer request"))
+
+	msg := w.lastMessage()
+	assert.Nil(t, msg.Value)
+	assert.Equal(t, order.ID.String(), string(msg.Key))
+	assert.NotEmpty(t, HeaderValue(msg.Headers, HeaderEventID))
+	assert.Equal(t, messaging.EventOrderCancelled, HeaderValue(msg.Headers, HeaderEventType))
+	assert.Equal(t, order.ID.String(), HeaderValue(msg.Headers, HeaderOrderID))
+	assert.Equal(t, "cancelled", HeaderValue(msg.Headers, HeaderStatus))
+	assert.Equal(t, "3", HeaderValue(msg.Headers, HeaderVersion))
+	assert.Equal(t, "2026-03-14T10:00:00.123456789Z", HeaderValue(msg.Headers, HeaderOccurredAt))
+	assert.Equal(t, "9", HeaderValue(msg.Headers, HeaderSchemaVersion))
+
+	evt, err := Decode(msg)
+	require.NoError(t, err)
+	assert.Equal(t, HeaderValue(msg.Headers, HeaderEventID), evt.EventID)
+	assert.Equal(t, messaging.EventOrderCancelled, evt.EventType)
+	assert.Equal(t, order.ID.String(), evt.OrderID)
+	assert.Equal(t, domain.OrderStatusCancelled, evt.Status)
+	assert.Equal(t, 3, evt.Version)
+	assert.True(t, occurred.Equal(evt.OccurredAt))
+	assert.Empty(t, evt.CancelReason, "the reason is not sent")
+}
+
+func TestWithTombstone_OnlyTheSelectedEventTypes(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []Option
+		tombstone map[string]bool
+	}{
+		{"default", nil, map[string]bool{}},
+		{"cancel", []Option{WithTombstoneOnCancel(true)}, map[string]bool{messaging.EventOrderCancelled: true}},
+		{"delete", []Option{WithTombstoneOnDelete(true)}, map[string]bool{messaging.EventOrderDeleted: true}},
+		{"turned_off", []Option{WithTombstoneOnCancel(true), WithTombstoneOnCancel(false)}, map[string]bool{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &mockWriter{}
+			pub := newTombstonePublisher(t, w, tt.opts...)
+			ctx := context.Background()
+			order := newTestOrder()
+
+			require.NoError(t, pub.PublishOrderCreated(ctx, order))
+			require.NoError(t, pub.PublishOrderCancelled(ctx, order, ""))
+			require.NoError(t, pub.PublishOrderDeleted(ctx, order))
+
+			require.Len(t, w.messages, 3)
+			for _, msg := range w.messages {
+				eventType := HeaderValue(msg.Headers, HeaderEventType)
+				if eventType == "" {
+					var evt messaging.OrderEvent
+					require.NoError(t, json.Unmarshal(msg.Value, &evt))
+					eventType = evt.EventType
+				}
+				assert.Equal(t, tt.tombstone[eventType], msg.Value == nil, eventType)
+			}
+		})
+	}
+}
+
+func TestDecode_ForeignTombstone_ReturnsErrTombstone(t *testing.T) {
+	_, err := Decode(kafkago.Message{Key: []byte("o-1")})
+
+	assert.ErrorIs(t, err, ErrTombstone)
+}
@@ -0,0 +1,127 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer used when none is configured.
+const instrumentationName = "github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/kafka"
+
+// Span attribute keys set on publish and consume spans.
+const (
+	AttrOrderID   = attribute.Key("order.id")
+	AttrEventType = attribute.Key("event.type")
+	AttrTopic     = attribute.Key("messaging.destination.name")
+	AttrPartition = attribute.Key("messaging.kafka.destination.partition")
+	AttrOffset    = attribute.Key("messaging.kafka.message.offset")
+)
+
+// WithTracer sets the tracer for publish spans. Defaults to the global
+// TracerProvider, which records nothing unless OpenTelemetry is set up.
+func WithTracer(t trace.Tracer) Option {
+	return func(o *options) { o.tracer = t }
+}
+
+// SetTracer sets the tracer for consume spans. Defaults to the global
+// TracerProvider, which records nothing unless OpenTelemetry is set up.
+func (c *Consumer) SetTracer(t trace.Tracer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tracer = t
+}
+
+func defaultTracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// startPublishSpan starts the producer span for evt. The span context is
+// what gets injected into the message headers.
+func (p *Publisher) startPublishSpan(ctx context.Context, evt messaging.OrderEvent) (context.Context, trace.Span) {
+	tracer := p.tracer
+	if tracer == nil {
+		tracer = defaultTracer()
+	}
+	return tracer.Start(ctx, "publish "+evt.EventType,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			AttrOrderID.String(evt.OrderID),
+			AttrEventType.String(evt.EventType),
+			AttrTopic.String(p.topicFor(evt.EventType)),
+		),
+	)
+}
+
+// recordPartitions is the kafka.Writer completion callback. The partition
+// is only chosen inside the writer, so it is added to the publish span of
+// each message, found by event ID, once the write completes.
+func (p *Publisher) recordPartitions(msgs []kafka.Message, err error) {
+	if err != nil {
+		return
+	}
+	for _, msg := range msgs {
+		for _, h := range msg.Headers {
+			if h.Key != HeaderEventID {
+				continue
+			}
+			if span, ok := p.inflight.Load(string(h.Value)); ok {
+				span.(trace.Span).SetAttributes(
+					AttrPartition.Int(msg.Partition),
+					AttrOffset.Int64(msg.Offset),
+				)
+			}
+		}
+	}
+}
+
+// startConsumeSpan starts the consumer span for evt, named after its event
+// type. It continues the producer's trace carried in msg's headers and
+// links to the producing span.
+func (c *Consumer) startConsumeSpan(ctx context.Context, msg kafka.Message, evt messaging.OrderEvent) (context.Context, trace.Span) {
+	c.mu.RLock()
+	tracer := c.tracer
+	c.mu.RUnlock()
+	if tracer == nil {
+		tracer = defaultTracer()
+	}
+
+	headers := messaging.HeadersFromKafka(msg.Headers)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, &headers)
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			AttrOrderID.String(evt.OrderID),
+			AttrEventType.String(evt.EventType),
+			AttrTopic.String(msg.Topic),
+			AttrPartition.Int(msg.Partition),
+			AttrOffset.Int64(msg.Offset),
+		),
+	}
+	if producer := trace.SpanContextFromContext(ctx); producer.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: producer}))
+	}
+	return tracer.Start(ctx, evt.EventType, opts...)
+}
+
+// endSpan records err on span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// attemptEvent is the span event added for each failed handler attempt.
+func attemptEvent(span trace.Span, attempt int, err error) {
+	span.AddEvent("handler failed", trace.WithAttributes(
+		attribute.Int("attempt", attempt),
+		attribute.String("error", err.Error()),
+	))
+}
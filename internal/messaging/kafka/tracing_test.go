@@ -0,0 +1,153 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	kafkago "github.com/segmentio/kafka-go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newSpanRecorder(t *testing.T) (*tracetest.SpanRecorder, trace.Tracer) {
+	t.Helper()
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	return sr, tp.Tracer("test")
+}
+
+func useTraceContextPropagator(t *testing.T) {
+	t.Helper()
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+}
+
+func spanAttrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	out := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		out[kv.Key] = kv.Value
+	}
+	return out
+}
+
+// partitionWriter assigns every message to partition 3 and reports it
+// through the publisher's completion callback, like kafka.Writer does.
+type partitionWriter struct {
+	mockWriter
+	pub *Publisher
+}
+
+func (w *partitionWriter) WriteMessages(ctx context.Context, msgs ...kafkago.Message) error {
+	for i := range msgs {
+		msgs[i].Partition = 3
+		msgs[i].Offset = 17
+	}
+	w.pub.recordPartitions(msgs, nil)
+	return w.mockWriter.WriteMessages(ctx, msgs...)
+}
+
+func TestPublisher_Publish_RecordsProducerSpan(t *testing.T) {
+	useTraceContextPropagator(t)
+	sr, tracer := newSpanRecorder(t)
+	pub := mustNew(t, WithTracer(tracer))
+	w := &partitionWriter{pub: pub}
+	pub.writer = w
+	order := newTestOrder()
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "publish "+messaging.EventOrderCreated, span.Name())
+	assert.Equal(t, trace.SpanKindProducer, span.SpanKind())
+
+	a := spanAttrs(span)
+	assert.Equal(t, order.ID.String(), a[AttrOrderID].AsString())
+	assert.Equal(t, messaging.EventOrderCreated, a[AttrEventType].AsString())
+	assert.Equal(t, "order-events", a[AttrTopic].AsString())
+	assert.Equal(t, int64(3), a[AttrPartition].AsInt64())
+
+	// The injected traceparent points at the publish span itself
+	msg := w.lastMessage()
+	traceparent := messaging.HeadersFromKafka(msg.Headers).TraceParent()
+	assert.Contains(t, traceparent, span.SpanContext().TraceID().String())
+	assert.Contains(t, traceparent, span.SpanContext().SpanID().String())
+}
+
+func TestPublisher_Publish_WriteError_MarksSpanFailed(t *testing.T) {
+	sr, tracer := newSpanRecorder(t)
+	pub := mustNew(t, WithTracer(tracer))
+	pub.writer = &mockWriter{err: errors.New("broker down")}
+
+	require.Error(t, pub.PublishOrderCreated(context.Background(), newTestOrder()))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+func TestTracing_PublishThenConsume_ContinuesTraceAndLinksProducer(t *testing.T) {
+	useTraceContextPropagator(t)
+	sr, tracer := newSpanRecorder(t)
+
+	ctx, parent := tracer.Start(context.Background(), "create order")
+	w := &mockWriter{}
+	pub := mustNew(t, WithTracer(tracer))
+	pub.writer = w
+	order := newTestOrder()
+	require.NoError(t, pub.PublishOrderCreated(ctx, order))
+	parent.End()
+
+	reader := &stubReader{messages: []kafkago.Message{w.lastMessage()}}
+	c := newConsumer(reader)
+	c.SetTracer(tracer)
+	var handlerSpan trace.SpanContext
+	c.RegisterHandler(messaging.EventOrderCreated, func(ctx context.Context, _ messaging.OrderEvent) error {
+		handlerSpan = trace.SpanContextFromContext(ctx)
+		return nil
+	})
+	runUntil(t, c, func() bool { return len(reader.commits()) == 1 })
+
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range sr.Ended() {
+		byName[s.Name()] = s
+	}
+	producer := byName["publish "+messaging.EventOrderCreated]
+	consumer := byName[messaging.EventOrderCreated]
+	require.NotNil(t, producer)
+	require.NotNil(t, consumer)
+
+	assert.Equal(t, trace.SpanKindConsumer, consumer.SpanKind())
+	assert.Equal(t, producer.SpanContext().TraceID(), consumer.SpanContext().TraceID())
+	assert.Equal(t, producer.SpanContext().SpanID(), consumer.Parent().SpanID())
+	require.Len(t, consumer.Links(), 1)
+	assert.Equal(t, producer.SpanContext().SpanID(), consumer.Links()[0].SpanContext.SpanID())
+	assert.Equal(t, order.ID.String(), spanAttrs(consumer)[AttrOrderID].AsString())
+
+	// Handlers run inside the consumer span
+	assert.Equal(t, consumer.SpanContext().SpanID(), handlerSpan.SpanID())
+}
+
+func TestPublisher_NoTracerConfigured_DoesNotRecord(t *testing.T) {
+	useTraceContextPropagator(t)
+	w := &mockWriter{}
+	pub := mustNew(t)
+	pub.writer = w
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newTestOrder()))
+
+	msg := w.lastMessage()
+	assert.Empty(t, messaging.HeadersFromKafka(msg.Headers).TraceParent(), "no-op tracer has no span context to inject")
+}
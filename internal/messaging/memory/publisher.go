@@ -0,0 +1,134 @@
+// Package memory provides an EventPublisher that records events in memory,
+// so tests can assert which events were emitted without running Kafka.
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+var (
+	_ messaging.EventPublisher = (*Publisher)(nil)
+	_ messaging.BatchPublisher = (*Publisher)(nil)
+)
+
+// Publisher records every published event, in publish order. It is safe
+// for concurrent use; the zero value is ready to use.
+type Publisher struct {
+	mu     sync.Mutex
+	events []messaging.OrderEvent
+}
+
+// New returns an empty Publisher.
+func New() *Publisher {
+	return &Publisher{}
+}
+
+// PublishOrderCreated records an order.created event.
+func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	p.record(messaging.Correlate(ctx, messaging.NewOrderEvent(messaging.EventOrderCreated, order)))
+	return nil
+}
+
+// PublishOrderUpdated records an order.updated event.
+func (p *Publisher) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	p.record(messaging.Correlate(ctx, messaging.NewUpdatedEvent(order, changes)))
+	return nil
+}
+
+// PublishOrderStatusChanged records an order.status_changed event.
+func (p *Publisher) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
+	p.record(messaging.Correlate(ctx, messaging.NewStatusChangedEvent(order, oldStatus, newStatus)))
+	return nil
+}
+
+// PublishOrderCancelled records an order.cancelled event.
+func (p *Publisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	p.record(messaging.Correlate(ctx, messaging.NewCancelledEvent(order, reason)))
+	return nil
+}
+
+// PublishOrderDeleted records an order.deleted event.
+func (p *Publisher) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	p.record(messaging.Correlate(ctx, messaging.NewOrderEvent(messaging.EventOrderDeleted, order)))
+	return nil
+}
+
+// PublishOrderExpired records an order.expired event.
+func (p *Publisher) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	p.record(messaging.Correlate(ctx, messaging.NewOrderEvent(messaging.EventOrderExpired, order)))
+	return nil
+}
+
+// PublishOrderSnapshot records an order.snapshot event.
+func (p *Publisher) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	p.record(messaging.Correlate(ctx, messaging.NewSnapshotEvent(order)))
+	return nil
+}
+
+// Publish records a pre-built event.
+func (p *Publisher) Publish(ctx context.Context, evt messaging.OrderEvent) error {
+	p.record(messaging.Correlate(ctx, evt))
+	return nil
+}
+
+// PublishBatch records events in batch order.
+func (p *Publisher) PublishBatch(_ context.Context, events []messaging.OrderEvent) error {
+	p.record(events...)
+	return nil
+}
+
+func (p *Publisher) record(events ...messaging.OrderEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, events...)
+}
+
+// Events returns a copy of the recorded events, oldest first.
+func (p *Publisher) Events() []messaging.OrderEvent {
+	return p.filter(func(messaging.OrderEvent) bool { return true })
+}
+
+// EventsOfType returns the recorded events of eventType, oldest first.
+func (p *Publisher) EventsOfType(eventType string) []messaging.OrderEvent {
+	return p.filter(func(evt messaging.OrderEvent) bool { return evt.EventType == eventType })
+}
+
+// EventsForOrder returns the recorded events about the order with orderID,
+// oldest first.
+func (p *Publisher) EventsForOrder(orderID string) []messaging.OrderEvent {
+	return p.filter(func(evt messaging.OrderEvent) bool { return evt.OrderID == orderID })
+}
+
+// Types returns the type of every recorded event, oldest first, for
+// asserting on the sequence of events.
+func (p *Publisher) Types() []string {
+	events := p.Events()
+	types := make([]string, len(events))
+	for i, evt := range events {
+		types[i] = evt.EventType
+	}
+	return types
+}
+
+// Reset discards the recorded events.
+func (p *Publisher) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = nil
+}
+
+func (p *Publisher) filter(keep func(messaging.OrderEvent) bool) []messaging.OrderEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var out []messaging.OrderEvent
+	for _, evt := range p.events {
+		if keep(evt) {
+			out = append(out, evt)
+		}
+	}
+	return out
+}
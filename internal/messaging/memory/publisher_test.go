@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOrder() *domain.Order {
+	return &domain.Order{
+		ID:         uuid.New(),
+		CustomerID: "cust-123",
+		Status:     domain.OrderStatusPending,
+		Total:      domain.Money{Amount: 2100, Currency: "USD"},
+		Version:    1,
+	}
+}
+
+func TestPublisher_RecordsEventsInOrder(t *testing.T) {
+	pub := New()
+	ctx := context.Background()
+	order := newTestOrder()
+
+	require.NoError(t, pub.PublishOrderCreated(ctx, order))
+	require.NoError(t, pub.PublishOrderUpdated(ctx, order, nil))
+	require.NoError(t, pub.PublishOrderStatusChanged(ctx, order, domain.OrderStatusPending, domain.OrderStatusConfirmed))
+	require.NoError(t, pub.PublishOrderCancelled(ctx, order, "customer request"))
+	require.NoError(t, pub.PublishOrderDeleted(ctx, order))
+
+	assert.Equal(t, []string{
+		messaging.EventOrderCreated,
+		messaging.EventOrderUpdated,
+		messaging.EventOrderStatusChanged,
+		messaging.EventOrderCancelled,
+		messaging.EventOrderDeleted,
+	}, pub.Types())
+
+	events := pub.Events()
+	require.Len(t, events, 5)
+	for _, evt := range events {
+		assert.Equal(t, order.ID.String(), evt.OrderID)
+	}
+	assert.Equal(t, domain.OrderStatusConfirmed, events[2].NewStatus)
+	assert.Equal(t, "customer request", events[3].CancelReason)
+}
+
+func TestPublisher_EventsOfTypeAndForOrder_Filter(t *testing.T) {
+	pub := New()
+	ctx := context.Background()
+	a, b := newTestOrder(), newTestOrder()
+
+	require.NoError(t, pub.PublishOrderCreated(ctx, a))
+	require.NoError(t, pub.PublishOrderCreated(ctx, b))
+	require.NoError(t, pub.PublishOrderStatusChanged(ctx, a, domain.OrderStatusPending, domain.OrderStatusConfirmed))
+
+	created := pub.EventsOfType(messaging.EventOrderCreated)
+	require.Len(t, created, 2)
+	assert.Equal(t, a.ID.String(), created[0].OrderID)
+	assert.Equal(t, b.ID.String(), created[1].OrderID)
+
+	forA := pub.EventsForOrder(a.ID.String())
+	require.Len(t, forA, 2)
+	assert.Equal(t, messaging.EventOrderStatusChanged, forA[1].EventType)
+
+	assert.Empty(t, pub.EventsOfType(messaging.EventOrderDeleted))
+}
+
+func TestPublisher_PublishAndBatch_RecordPrebuiltEvents(t *testing.T) {
+	pub := New()
+	ctx := context.Background()
+	order := newTestOrder()
+	first := messaging.NewOrderEvent(messaging.EventOrderCreated, order)
+	batch := []messaging.OrderEvent{
+		messaging.NewOrderEvent(messaging.EventOrderUpdated, order),
+		messaging.NewOrderEvent(messaging.EventOrderDeleted, order),
+	}
+
+	require.NoError(t, pub.Publish(ctx, first))
+	require.NoError(t, pub.PublishBatch(ctx, batch))
+
+	assert.Equal(t, append([]messaging.OrderEvent{first}, batch...), pub.Events())
+}
+
+func TestPublisher_CausationIDOnContext_SetUnlessEventHasOne(t *testing.T) {
+	pub := New()
+	ctx := messaging.WithCausationID(context.Background(), "evt-created")
+	order := newTestOrder()
+	relayed := messaging.NewOrderEvent(messaging.EventOrderUpdated, order)
+	relayed.CausationID = "evt-original"
+
+	require.NoError(t, pub.PublishOrderStatusChanged(ctx, order, domain.OrderStatusPending, domain.OrderStatusConfirmed))
+	require.NoError(t, pub.Publish(ctx, relayed))
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+
+	events := pub.Events()
+	require.Len(t, events, 3)
+	assert.Equal(t, "evt-created", events[0].CausationID)
+	assert.Equal(t, "evt-original", events[1].CausationID)
+	assert.Empty(t, events[2].CausationID)
+}
+
+func TestPublisher_Events_ReturnsCopy(t *testing.T) {
+	pub := New()
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newTestOrder()))
+
+	events := pub.Events()
+	events[0].EventType = "tampered"
+
+	assert.Equal(t, messaging.EventOrderCreated, pub.Events()[0].EventType)
+}
+
+func TestPublisher_Reset_DiscardsEvents(t *testing.T) {
+	pub := New()
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newTestOrder()))
+
+	pub.Reset()
+
+	assert.Empty(t, pub.Events())
+	require.NoError(t, pub.PublishOrderUpdated(context.Background(), newTestOrder(), nil))
+	assert.Equal(t, []string{messaging.EventOrderUpdated}, pub.Types())
+}
+
+func TestPublisher_ConcurrentPublishes_AllRecorded(t *testing.T) {
+	var pub Publisher
+	const n = 50
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = pub.PublishOrderCreated(context.Background(), newTestOrder())
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, pub.Events(), n)
+}
@@ -0,0 +1,109 @@
+package messaging
+
+import (
+	"context"
+	"time"
+)
+
+// contextKey is the type of the context keys defined by this package, so
+// they cannot collide with keys defined elsewhere.
+type contextKey string
+
+// Context keys of request metadata that publishers copy onto the messages
+// they write. Set them with WithTenantID, WithRequestID, WithEventID,
+// WithOccurredAt and WithCausationID.
+const (
+	TenantIDKey    contextKey = "tenant-id"
+	RequestIDKey   contextKey = "request-id"
+	EventIDKey     contextKey = "event-id"
+	OccurredAtKey  contextKey = "occurred-at"
+	CausationIDKey contextKey = "causation-id"
+)
+
+// WithTenantID returns a copy of ctx carrying the tenant the published
+// events belong to.
+func WithTenantID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, TenantIDKey, id)
+}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request that
+// caused the published events.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, RequestIDKey, id)
+}
+
+// WithEventID returns a copy of ctx pinning the EventID of the event the
+// next publish builds, so that repeating the publish, as a retry does,
+// emits the same event rather than a new one. Use it around the publish of
+// a single event only.
+func WithEventID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, EventIDKey, id)
+}
+
+// WithOccurredAt returns a copy of ctx pinning the OccurredAt of the event
+// the next publish builds, so the event carries the time the change was
+// recorded with, such as the occurred_at of a status history row. Like
+// WithEventID, use it around the publish of a single event only.
+func WithOccurredAt(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, OccurredAtKey, t)
+}
+
+// WithCausationID returns a copy of ctx naming id as the EventID of the
+// event that led to the events the next publishes build, so consumers can
+// follow an order's events back to the one that started them.
+func WithCausationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, CausationIDKey, id)
+}
+
+// TenantID returns the tenant ID set on ctx by WithTenantID, if any.
+func TenantID(ctx context.Context) (string, bool) {
+	return stringValue(ctx, TenantIDKey)
+}
+
+// RequestID returns the request ID set on ctx by WithRequestID, if any.
+func RequestID(ctx context.Context) (string, bool) {
+	return stringValue(ctx, RequestIDKey)
+}
+
+// EventID returns the event ID pinned on ctx by WithEventID, if any.
+func EventID(ctx context.Context) (string, bool) {
+	return stringValue(ctx, EventIDKey)
+}
+
+// CausationID returns the event ID set on ctx by WithCausationID, if any.
+func CausationID(ctx context.Context) (string, bool) {
+	return stringValue(ctx, CausationIDKey)
+}
+
+// OccurredAt returns the time pinned on ctx by WithOccurredAt, if any.
+func OccurredAt(ctx context.Context) (time.Time, bool) {
+	t, ok := ctx.Value(OccurredAtKey).(time.Time)
+	return t, ok && !t.IsZero()
+}
+
+// Correlate returns evt with CorrelationID set to the request ID on ctx,
+// so the event can be traced back to the request that caused it. An event
+// that already has one, such as a stored event being relayed, keeps it,
+// and likewise its CausationID, which is otherwise taken from ctx. If ctx
+// pins an event ID or occurrence time, evt takes them.
+func Correlate(ctx context.Context, evt OrderEvent) OrderEvent {
+	if evt.CorrelationID == "" {
+		evt.CorrelationID, _ = RequestID(ctx)
+	}
+	if evt.CausationID == "" {
+		evt.CausationID, _ = CausationID(ctx)
+	}
+	if id, ok := EventID(ctx); ok {
+		evt.EventID = id
+	}
+	if t, ok := OccurredAt(ctx); ok {
+		evt.OccurredAt = t
+	}
+	return evt
+}
+
+// stringValue returns the non-empty string stored under key.
+func stringValue(ctx context.Context, key contextKey) (string, bool) {
+	v, ok := ctx.Value(key).(string)
+	return v, ok && v != ""
+}
@@ -0,0 +1,14 @@
+package messaging
+
+import "time"
+
+// Metrics records how the event pipeline is performing.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObservePublish records one publish of eventType that took d.
+	// err is the publish result, nil on success.
+	ObservePublish(eventType string, d time.Duration, err error)
+
+	// SetOutboxBacklog records how many outbox records await delivery.
+	SetOutboxBacklog(n int)
+}
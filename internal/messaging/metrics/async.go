@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/async"
+)
+
+// AsyncBuffer is an async publisher whose buffer can be exported, such as
+// an *async.Publisher.
+type AsyncBuffer interface {
+	Stats() async.Stats
+}
+
+// RegisterAsync exports the buffer of b on reg, read at every scrape:
+// ordersvc_publisher_buffer_depth is how many events are waiting, out of
+// ordersvc_publisher_buffer_capacity, alongside counters of the events
+// dropped, the publishes rejected and the events that failed to publish.
+func RegisterAsync(reg prometheus.Registerer, b AsyncBuffer) error {
+	if err := reg.Register(newAsyncCollector(b)); err != nil {
+		return fmt.Errorf("register async publisher metrics: %w", err)
+	}
+	return nil
+}
+
+// asyncCollector reports an AsyncBuffer's Stats as metrics.
+type asyncCollector struct {
+	buffer   AsyncBuffer
+	depth    *prometheus.Desc
+	capacity *prometheus.Desc
+	dropped  *prometheus.Desc
+	rejected *prometheus.Desc
+	failed   *prometheus.Desc
+}
+
+func newAsyncCollector(b AsyncBuffer) *asyncCollector {
+	return &asyncCollector{
+		buffer: b,
+		depth: prometheus.NewDesc("ordersvc_publisher_buffer_depth",
+			"Events waiting in the async publisher's buffer.", nil, nil),
+		capacity: prometheus.NewDesc("ordersvc_publisher_buffer_capacity",
+			"Events the async publisher's buffer holds.", nil, nil),
+		dropped: prometheus.NewDesc("ordersvc_publisher_buffer_dropped_total",
+			"Events dropped from the full async publisher buffer.", nil, nil),
+		rejected: prometheus.NewDesc("ordersvc_publisher_buffer_rejected_total",
+			"Publishes failed because the async publisher buffer was full.", nil, nil),
+		failed: prometheus.NewDesc("ordersvc_publisher_async_failures_total",
+			"Buffered events the async publisher failed to publish.", nil, nil),
+	}
+}
+
+// Describe sends the descriptors of the async publisher metrics.
+func (c *asyncCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.depth
+	ch <- c.capacity
+	ch <- c.dropped
+	ch <- c.rejected
+	ch <- c.failed
+}
+
+// Collect sends the buffer's current stats.
+func (c *asyncCollector) Collect(ch chan<- prometheus.Metric) {
+	st := c.buffer.Stats()
+	ch <- prometheus.MustNewConstMetric(c.depth, prometheus.GaugeValue, float64(st.Depth))
+	ch <- prometheus.MustNewConstMetric(c.capacity, prometheus.GaugeValue, float64(st.Capacity))
+	ch <- prometheus.MustNewConstMetric(c.dropped, prometheus.CounterValue, float64(st.Dropped))
+	ch <- prometheus.MustNewConstMetric(c.rejected, prometheus.CounterValue, float64(st.Rejected))
+	ch <- prometheus.MustNewConstMetric(c.failed, prometheus.CounterValue, float64(st.Failed))
+}
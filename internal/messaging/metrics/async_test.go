@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/async"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// asyncStats is an AsyncBuffer reporting fixed stats.
+type asyncStats async.Stats
+
+func (s *asyncStats) Stats() async.Stats { return async.Stats(*s) }
+
+func TestRegisterAsync_ExportsStatsAtScrape(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	st := &asyncStats{Capacity: 100}
+	require.NoError(t, RegisterAsync(reg, st))
+
+	value := func(name string) float64 {
+		mf := gather(t, reg, name)
+		require.NotNil(t, mf, name)
+		m := mf.GetMetric()[0]
+		if m.GetCounter() != nil {
+			return m.GetCounter().GetValue()
+		}
+		return m.GetGauge().GetValue()
+	}
+	assert.Equal(t, 0.0, value("ordersvc_publisher_buffer_depth"))
+
+	*st = asyncStats{Depth: 40, Capacity: 100, Dropped: 3, Rejected: 2, Failed: 1}
+	assert.Equal(t, 40.0, value("ordersvc_publisher_buffer_depth"))
+	assert.Equal(t, 100.0, value("ordersvc_publisher_buffer_capacity"))
+	assert.Equal(t, 3.0, value("ordersvc_publisher_buffer_dropped_total"))
+	assert.Equal(t, 2.0, value("ordersvc_publisher_buffer_rejected_total"))
+	assert.Equal(t, 1.0, value("ordersvc_publisher_async_failures_total"))
+}
+
+func TestRegisterAsync_Twice_ReturnsError(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	require.NoError(t, RegisterAsync(reg, &asyncStats{}))
+
+	assert.Error(t, RegisterAsync(reg, &asyncStats{}))
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/breaker"
+)
+
+// Breaker is a circuit breaker whose state can be exported, such as a
+// *breaker.Publisher.
+type Breaker interface {
+	State() breaker.State
+}
+
+// RegisterBreaker exports the state of b on reg, read at every scrape:
+// ordersvc_publisher_circuit_state is 0 while closed, 1 open and 2
+// half-open, alongside counters of the circuit opening and of the
+// publishes it rejected.
+func RegisterBreaker(reg prometheus.Registerer, b Breaker) error {
+	if err := reg.Register(newBreakerCollector(b)); err != nil {
+		return fmt.Errorf("register circuit breaker metrics: %w", err)
+	}
+	return nil
+}
+
+// breakerCollector reports a Breaker's State as metrics.
+type breakerCollector struct {
+	breaker  Breaker
+	state    *prometheus.Desc
+	failures *prometheus.Desc
+	opened   *prometheus.Desc
+	rejected *prometheus.Desc
+}
+
+func newBreakerCollector(b Breaker) *breakerCollector {
+	return &breakerCollector{
+		breaker: b,
+		state: prometheus.NewDesc("ordersvc_publisher_circuit_state",
+			"Publisher circuit breaker: 0 closed, 1 open, 2 half-open.", nil, nil),
+		failures: prometheus.NewDesc("ordersvc_publisher_circuit_consecutive_failures",
+			"Consecutive failed publishes counted by the circuit breaker.", nil, nil),
+		opened: prometheus.NewDesc("ordersvc_publisher_circuit_opened_total",
+			"Times the publisher circuit breaker opened.", nil, nil),
+		rejected: prometheus.NewDesc("ordersvc_publisher_circuit_rejected_total",
+			"Publishes failed by the open circuit breaker without reaching the broker.", nil, nil),
+	}
+}
+
+// Describe sends the descriptors of the breaker metrics.
+func (c *breakerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.state
+	ch <- c.failures
+	ch <- c.opened
+	ch <- c.rejected
+}
+
+// Collect sends the breaker's current state.
+func (c *breakerCollector) Collect(ch chan<- prometheus.Metric) {
+	st := c.breaker.State()
+	ch <- prometheus.MustNewConstMetric(c.state, prometheus.GaugeValue, float64(st.Status))
+	ch <- prometheus.MustNewConstMetric(c.failures, prometheus.GaugeValue, float64(st.ConsecutiveFailures))
+	ch <- prometheus.MustNewConstMetric(c.opened, prometheus.CounterValue, float64(st.Opened))
+	ch <- prometheus.MustNewConstMetric(c.rejected, prometheus.CounterValue, float64(st.Rejected))
+}
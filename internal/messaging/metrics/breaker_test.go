@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/breaker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// breakerState is a Breaker reporting a fixed state.
+type breakerState breaker.State
+
+func (s *breakerState) State() breaker.State { return breaker.State(*s) }
+
+func TestRegisterBreaker_ExportsStateAtScrape(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	st := &breakerState{Status: breaker.Closed}
+	require.NoError(t, RegisterBreaker(reg, st))
+
+	value := func(name string) float64 {
+		mf := gather(t, reg, name)
+		require.NotNil(t, mf, name)
+		m := mf.GetMetric()[0]
+		if m.GetCounter() != nil {
+			return m.GetCounter().GetValue()
+		}
+		return m.GetGauge().GetValue()
+	}
+	assert.Equal(t, 0.0, value("ordersvc_publisher_circuit_state"))
+
+	*st = breakerState{Status: breaker.Open, ConsecutiveFailures: 5, Opened: 2, Rejected: 7}
+	assert.Equal(t, 1.0, value("ordersvc_publisher_circuit_state"))
+	assert.Equal(t, 5.0, value("ordersvc_publisher_circuit_consecutive_failures"))
+	assert.Equal(t, 2.0, value("ordersvc_publisher_circuit_opened_total"))
+	assert.Equal(t, 7.0, value("ordersvc_publisher_circuit_rejected_total"))
+
+	st.Status = breaker.HalfOpen
+	assert.Equal(t, 2.0, value("ordersvc_publisher_circuit_state"))
+}
+
+func TestRegisterBreaker_Twice_ReturnsError(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	require.NoError(t, RegisterBreaker(reg, &breakerState{}))
+
+	assert.Error(t, RegisterBreaker(reg, &breakerState{}))
+}
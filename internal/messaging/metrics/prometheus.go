@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+// Values of the result label.
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+// Path is where Handle mounts the metrics endpoint.
+const Path = "/metrics"
+
+var _ messaging.Metrics = (*Prometheus)(nil)
+
+// Prometheus is a messaging.Metrics backed by Prometheus collectors.
+type Prometheus struct {
+	published *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+	backlog   prometheus.Gauge
+}
+
+// NewPrometheus registers the event pipeline collectors on reg.
+// Calling it again with the same registry shares the collectors.
+func NewPrometheus(reg prometheus.Registerer) (*Prometheus, error) {
+	published := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ordersvc_events_published_total",
+		Help: "Order events published, by event type and result.",
+	}, []string{"event_type", "result"})
+
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ordersvc_event_publish_duration_seconds",
+		Help:    "Time taken to publish an order event.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"event_type"})
+
+	backlog := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "ordersvc_outbox_backlog",
+		Help: "Outbox records not yet delivered to the broker.",
+	})
+
+	var err error
+	if published, err = register(reg, published); err != nil {
+		return nil, err
+	}
+	if duration, err = register(reg, duration); err != nil {
+		return nil, err
+	}
+	if backlog, err = register(reg, backlog); err != nil {
+		return nil, err
+	}
+
+	return &Prometheus{published: published, duration: duration, backlog: backlog}, nil
+}
+
+// register adds c to reg, returning the existing collector if an identical
+// one is already registered
+func register[C prometheus.Collector](reg prometheus.Registerer, c C) (C, error) {
+	if err := reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(C); ok {
+				return existing, nil
+			}
+		}
+		return c, fmt.Errorf("register publish metrics: %w", err)
+	}
+	return c, nil
+}
+
+// ObservePublish counts the publish by result and records its latency.
+func (m *Prometheus) ObservePublish(eventType string, d time.Duration, err error) {
+	m.duration.WithLabelValues(eventType).Observe(d.Seconds())
+
+	result := ResultSuccess
+	if err != nil {
+		result = ResultError
+	}
+	m.published.WithLabelValues(eventType, result).Inc()
+}
+
+// SetOutboxBacklog sets the outbox backlog gauge.
+func (m *Prometheus) SetOutboxBacklog(n int) {
+	m.backlog.Set(float64(n))
+}
+
+// Mux is satisfied by http.ServeMux and chi routers.
+type Mux interface {
+	Handle(pattern string, h http.Handler)
+}
+
+// Handle serves the metrics gathered by g on mux at Path.
+func Handle(mux Mux, g prometheus.Gatherer) {
+	mux.Handle(Path, promhttp.HandlerFor(g, promhttp.HandlerOpts{}))
+}
@@ -0,0 +1,100 @@
+// Package metrics records event pipeline metrics in Prometheus, either
+// through an EventPublisher decorator or as a messaging.Metrics that
+// publishers and the outbox relay report into.
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+var _ messaging.EventPublisher = (*Publisher)(nil)
+
+// Publisher wraps an EventPublisher and counts and times every publish.
+type Publisher struct {
+	next    messaging.EventPublisher
+	metrics messaging.Metrics
+}
+
+// Wrap returns p decorated with publish metrics registered on reg.
+// Wrapping several publishers with the same registry shares the collectors.
+func Wrap(p messaging.EventPublisher, reg prometheus.Registerer) (messaging.EventPublisher, error) {
+	m, err := NewPrometheus(reg)
+	if err != nil {
+		return nil, err
+	}
+	return &Publisher{next: p, metrics: m}, nil
+}
+
+// Middleware returns Wrap with reg as a messaging.Middleware, registering
+// the collectors once, up front, so that registration errors are returned
+// here rather than lost inside the chain.
+func Middleware(reg prometheus.Registerer) (messaging.Middleware, error) {
+	m, err := NewPrometheus(reg)
+	if err != nil {
+		return nil, err
+	}
+	return func(p messaging.EventPublisher) messaging.EventPublisher {
+		return &Publisher{next: p, metrics: m}
+	}, nil
+}
+
+// PublishOrderCreated publishes an order.created event and records it.
+func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	return p.observe(messaging.EventOrderCreated, func() error {
+		return p.next.PublishOrderCreated(ctx, order)
+	})
+}
+
+// PublishOrderUpdated publishes an order.updated event and records it.
+func (p *Publisher) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	return p.observe(messaging.EventOrderUpdated, func() error {
+		return p.next.PublishOrderUpdated(ctx, order, changes)
+	})
+}
+
+// PublishOrderStatusChanged publishes an order.status_changed event and records it.
+func (p *Publisher) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
+	return p.observe(messaging.EventOrderStatusChanged, func() error {
+		return p.next.PublishOrderStatusChanged(ctx, order, oldStatus, newStatus)
+	})
+}
+
+// PublishOrderCancelled publishes an order.cancelled event and records it.
+func (p *Publisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	return p.observe(messaging.EventOrderCancelled, func() error {
+		return p.next.PublishOrderCancelled(ctx, order, reason)
+	})
+}
+
+// PublishOrderDeleted publishes an order.deleted event and records it.
+func (p *Publisher) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	return p.observe(messaging.EventOrderDeleted, func() error {
+		return p.next.PublishOrderDeleted(ctx, order)
+	})
+}
+
+// PublishOrderExpired publishes an order.expired event and records it.
+func (p *Publisher) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	return p.observe(messaging.EventOrderExpired, func() error {
+		return p.next.PublishOrderExpired(ctx, order)
+	})
+}
+
+// PublishOrderSnapshot publishes an order.snapshot event and records it.
+func (p *Publisher) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	return p.observe(messaging.EventOrderSnapshot, func() error {
+		return p.next.PublishOrderSnapshot(ctx, order)
+	})
+}
+
+func (p *Publisher) observe(eventType string, publish func() error) error {
+	start := time.Now()
+	err := publish()
+	p.metrics.ObservePublish(eventType, time.Since(start), err)
+	return err
+}
@@ -0,0 +1,180 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOrder() *domain.Order {
+	return &domain.Order{ID: uuid.New(), CustomerID: "cust-1", Status: domain.OrderStatusPending, Total: domain.Money{Amount: 4250, Currency: "USD"}}
+}
+
+// gather returns the metric family called name from reg, or nil
+func gather(t *testing.T, reg *prometheus.Registry, name string) *dto.MetricFamily {
+	t.Helper()
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	for _, mf := range families {
+		if mf.GetName() == name {
+			return mf
+		}
+	}
+	return nil
+}
+
+// labelsMatch reports whether m carries exactly the given label values
+func labelsMatch(m *dto.Metric, labels map[string]string) bool {
+	if len(m.GetLabel()) != len(labels) {
+		return false
+	}
+	for _, lp := range m.GetLabel() {
+		if labels[lp.GetName()] != lp.GetValue() {
+			return false
+		}
+	}
+	return true
+}
+
+func counterValue(t *testing.T, reg *prometheus.Registry, eventType, result string) float64 {
+	t.Helper()
+	mf := gather(t, reg, "ordersvc_events_published_total")
+	if mf == nil {
+		return 0
+	}
+	for _, m := range mf.GetMetric() {
+		if labelsMatch(m, map[string]string{"event_type": eventType, "result": result}) {
+			return m.GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
+func histogramCount(t *testing.T, reg *prometheus.Registry, eventType string) uint64 {
+	t.Helper()
+	mf := gather(t, reg, "ordersvc_event_publish_duration_seconds")
+	if mf == nil {
+		return 0
+	}
+	for _, m := range mf.GetMetric() {
+		if labelsMatch(m, map[string]string{"event_type": eventType}) {
+			return m.GetHistogram().GetSampleCount()
+		}
+	}
+	return 0
+}
+
+func TestWrap_Publish_CountsSuccessAndErrorPerEventType(t *testing.T) {
+	errBroker := errors.New("broker down")
+
+	tests := []struct {
+		eventType string
+		publish   func(messaging.EventPublisher, *domain.Order) error
+		fail      func(*mocks.EventPublisherMock)
+	}{
+		{
+			eventType: messaging.EventOrderCreated,
+			publish: func(p messaging.EventPublisher, o *domain.Order) error {
+				return p.PublishOrderCreated(context.Background(), o)
+			},
+			fail: func(m *mocks.EventPublisherMock) {
+				m.PublishOrderCreatedFunc = func(context.Context, *domain.Order) error { return errBroker }
+			},
+		},
+		{
+			eventType: messaging.EventOrderUpdated,
+			publish: func(p messaging.EventPublisher, o *domain.Order) error {
+				return p.PublishOrderUpdated(context.Background(), o, nil)
+			},
+			fail: func(m *mocks.EventPublisherMock) {
+				m.PublishOrderUpdatedFunc = func(context.Context, *domain.Order, map[string]messaging.ChangePair) error { return errBroker }
+			},
+		},
+		{
+			eventType: messaging.EventOrderStatusChanged,
+			publish: func(p messaging.EventPublisher, o *domain.Order) error {
+				return p.PublishOrderStatusChanged(context.Background(), o,
+					domain.OrderStatusPending, domain.OrderStatusConfirmed)
+			},
+			fail: func(m *mocks.EventPublisherMock) {
+				m.PublishOrderStatusChangedFunc = func(context.Context, *domain.Order, domain.OrderStatus, domain.OrderStatus) error {
+					return errBroker
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.eventType, func(t *testing.T) {
+			reg := prometheus.NewRegistry()
+			mock := &mocks.EventPublisherMock{}
+			p, err := Wrap(mock, reg)
+			require.NoError(t, err)
+
+			require.NoError(t, tt.publish(p, newTestOrder()))
+			require.NoError(t, tt.publish(p, newTestOrder()))
+
+			tt.fail(mock)
+			err = tt.publish(p, newTestOrder())
+			assert.ErrorIs(t, err, errBroker)
+
+			assert.Equal(t, 2.0, counterValue(t, reg, tt.eventType, ResultSuccess))
+			assert.Equal(t, 1.0, counterValue(t, reg, tt.eventType, ResultError))
+			assert.Equal(t, uint64(3), histogramCount(t, reg, tt.eventType))
+		})
+	}
+}
+
+func TestWrap_SharedRegistry_ReusesCollectors(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	first, err := Wrap(&mocks.EventPublisherMock{}, reg)
+	require.NoError(t, err)
+	second, err := Wrap(&mocks.EventPublisherMock{}, reg)
+	require.NoError(t, err)
+
+	require.NoError(t, first.PublishOrderCreated(context.Background(), newTestOrder()))
+	require.NoError(t, second.PublishOrderCreated(context.Background(), newTestOrder()))
+
+	assert.Equal(t, 2.0, counterValue(t, reg, messaging.EventOrderCreated, ResultSuccess))
+}
+
+func TestPrometheus_SetOutboxBacklog_SetsGauge(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := NewPrometheus(reg)
+	require.NoError(t, err)
+
+	m.SetOutboxBacklog(7)
+	m.SetOutboxBacklog(3)
+
+	mf := gather(t, reg, "ordersvc_outbox_backlog")
+	require.NotNil(t, mf)
+	assert.Equal(t, 3.0, mf.GetMetric()[0].GetGauge().GetValue())
+}
+
+func TestHandle_ServesRegisteredMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := NewPrometheus(reg)
+	require.NoError(t, err)
+	m.ObservePublish(messaging.EventOrderCreated, time.Millisecond, nil)
+
+	mux := http.NewServeMux()
+	Handle(mux, reg)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `ordersvc_events_published_total{event_type="order.created",result="success"} 1`)
+}
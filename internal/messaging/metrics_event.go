@@ -0,0 +1,76 @@
+package messaging
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+)
+
+// EventOrderMetrics is the event type of OrderMetricsEvent.
+const EventOrderMetrics = "order.metrics"
+
+// MetricsSchemaVersion is the version of the OrderMetricsEvent envelope,
+// which is versioned apart from OrderEvent.
+const MetricsSchemaVersion = 1
+
+// OrderMetricsEvent is the envelope of an order.metrics event: a rollup of
+// the orders created in the window from WindowStart, inclusive, to
+// WindowEnd, exclusive, by their status when it was computed. It is about
+// no one order, so it is published to a topic of its own rather than with
+// the OrderEvents.
+type OrderMetricsEvent struct {
+	EventID       string          `json:"event_id"` // Derived from the window, so a rollup emitted twice keeps its ID
+	EventType     string          `json:"event_type"`
+	SchemaVersion int             `json:"schema_version"` // Envelope version, see MetricsSchemaVersion
+	WindowStart   time.Time       `json:"window_start"`
+	WindowEnd     time.Time       `json:"window_end"`
+	Statuses      []StatusMetrics `json:"statuses"` // Every status, in domain.ValidStatuses order
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+// StatusMetrics is the number of orders of a window in one status, and
+// their revenue.
+type StatusMetrics struct {
+	Status  domain.OrderStatus `json:"status"`
+	Count   int64              `json:"count"`
+	Revenue []RevenueMetrics   `json:"revenue"` // One per currency, by code; empty when Count is 0
+}
+
+// RevenueMetrics is the sum of the totals of orders in one currency.
+type RevenueMetrics struct {
+	Currency   string  `json:"currency"`
+	Total      float64 `json:"total"` // Major units; may be inexact, prefer TotalMinor
+	TotalMinor int64   `json:"total_minor"`
+}
+
+// metricsNamespace is the namespace of the name-based UUIDs of rollups.
+var metricsNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("urn:ordersvc:"+EventOrderMetrics))
+
+// NewOrderMetricsEvent builds the rollup of the window [start, end) from
+// statuses, adding each valid status it lacks with a zero count, so the
+// rollup of an empty window still lists every status.
+func NewOrderMetricsEvent(start, end time.Time, statuses []StatusMetrics) OrderMetricsEvent {
+	all := make([]StatusMetrics, 0, len(domain.ValidStatuses()))
+	for _, status := range domain.ValidStatuses() {
+		m := StatusMetrics{Status: status, Revenue: []RevenueMetrics{}}
+		if i := slices.IndexFunc(statuses, func(s StatusMetrics) bool { return s.Status == status }); i >= 0 {
+			m.Count = statuses[i].Count
+			m.Revenue = append(m.Revenue, statuses[i].Revenue...)
+			slices.SortFunc(m.Revenue, func(a, b RevenueMetrics) int { return cmp.Compare(a.Currency, b.Currency) })
+		}
+		all = append(all, m)
+	}
+	window := start.UTC().Format(time.RFC3339Nano) + "/" + end.UTC().Format(time.RFC3339Nano)
+	return OrderMetricsEvent{
+		EventID:       uuid.NewSHA1(metricsNamespace, []byte(window)).String(),
+		EventType:     EventOrderMetrics,
+		SchemaVersion: MetricsSchemaVersion,
+		WindowStart:   start,
+		WindowEnd:     end,
+		Statuses:      all,
+		OccurredAt:    time.Now(),
+	}
+}
@@ -0,0 +1,80 @@
+package messaging
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOrderMetricsEvent_FillsMissingStatusesWithZeros(t *testing.T) {
+	start := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	evt := NewOrderMetricsEvent(start, end, []StatusMetrics{
+		{Status: domain.OrderStatusConfirmed, Count: 3, Revenue: []RevenueMetrics{
+			{Currency: "USD", Total: 30, TotalMinor: 3000},
+			{Currency: "EUR", Total: 5, TotalMinor: 500},
+		}},
+	})
+
+	assert.Equal(t, EventOrderMetrics, evt.EventType)
+	assert.Equal(t, MetricsSchemaVersion, evt.SchemaVersion)
+	assert.Equal(t, start, evt.WindowStart)
+	assert.Equal(t, end, evt.WindowEnd)
+	require.Len(t, evt.Statuses, len(domain.ValidStatuses()))
+	for i, status := range domain.ValidStatuses() {
+		got := evt.Statuses[i]
+		assert.Equal(t, status, got.Status)
+		if status == domain.OrderStatusConfirmed {
+			assert.Equal(t, int64(3), got.Count)
+			assert.Equal(t, []RevenueMetrics{
+				{Currency: "EUR", Total: 5, TotalMinor: 500},
+				{Currency: "USD", Total: 30, TotalMinor: 3000},
+			}, got.Revenue, "sorted by currency")
+			continue
+		}
+		assert.Zero(t, got.Count, status)
+		assert.Empty(t, got.Revenue, status)
+	}
+}
+
+func TestNewOrderMetricsEvent_EmptyWindow_EncodesZeroCountsAndEmptyRevenue(t *testing.T) {
+	start := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+
+	data, err := json.Marshal(NewOrderMetricsEvent(start, start.Add(time.Hour), nil))
+	require.NoError(t, err)
+
+	var decoded struct {
+		Statuses []struct {
+			Status  string            `json:"status"`
+			Count   *int64            `json:"count"`
+			Revenue []json.RawMessage `json:"revenue"`
+		} `json:"statuses"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Len(t, decoded.Statuses, len(domain.ValidStatuses()))
+	for _, s := range decoded.Statuses {
+		require.NotNil(t, s.Count, s.Status)
+		assert.Zero(t, *s.Count, s.Status)
+		assert.NotNil(t, s.Revenue, "%s revenue is [] rather than null", s.Status)
+	}
+}
+
+func TestNewOrderMetricsEvent_EventIDDependsOnWindowOnly(t *testing.T) {
+	start := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	first := NewOrderMetricsEvent(start, end, nil)
+	again := NewOrderMetricsEvent(start.In(time.FixedZone("CEST", 2*60*60)), end, []StatusMetrics{
+		{Status: domain.OrderStatusPending, Count: 1},
+	})
+	next := NewOrderMetricsEvent(end, end.Add(time.Hour), nil)
+
+	assert.NotEmpty(t, first.EventID)
+	assert.Equal(t, first.EventID, again.EventID, "the same window in another zone")
+	assert.NotEqual(t, first.EventID, next.EventID)
+}
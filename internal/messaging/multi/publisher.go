@@ -0,0 +1,175 @@
+// Package multi provides EventPublishers that fan events out to several
+// publishers, either best-effort (MultiPublisher) or fail-fast
+// (FailFastPublisher).
+package multi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+var (
+	_ messaging.EventPublisher = MultiPublisher(nil)
+	_ messaging.EventPublisher = FailFastPublisher(nil)
+)
+
+// MultiPublisher publishes each event to every child publisher in order,
+// best-effort.
+//
+// A failing child does not stop the others: every child is attempted, and
+// the errors of all failing children are returned combined with errors.Join.
+// A nil error means every child succeeded.
+type MultiPublisher []messaging.EventPublisher //nolint:revive // stutter is intentional: the type is named in config and docs
+
+// New returns a MultiPublisher over pubs, skipping nil entries.
+func New(pubs ...messaging.EventPublisher) MultiPublisher {
+	return MultiPublisher(nonNil(pubs))
+}
+
+// PublishOrderCreated publishes an order.created event to every child.
+func (m MultiPublisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	return m.each(func(p messaging.EventPublisher) error {
+		return p.PublishOrderCreated(ctx, order)
+	})
+}
+
+// PublishOrderUpdated publishes an order.updated event to every child.
+func (m MultiPublisher) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	return m.each(func(p messaging.EventPublisher) error {
+		return p.PublishOrderUpdated(ctx, order, changes)
+	})
+}
+
+// PublishOrderStatusChanged publishes an order.status_changed event to every child.
+func (m MultiPublisher) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
+	return m.each(func(p messaging.EventPublisher) error {
+		return p.PublishOrderStatusChanged(ctx, order, oldStatus, newStatus)
+	})
+}
+
+// PublishOrderCancelled publishes an order.cancelled event to every child.
+func (m MultiPublisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	return m.each(func(p messaging.EventPublisher) error {
+		return p.PublishOrderCancelled(ctx, order, reason)
+	})
+}
+
+// PublishOrderDeleted publishes an order.deleted event to every child.
+func (m MultiPublisher) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	return m.each(func(p messaging.EventPublisher) error {
+		return p.PublishOrderDeleted(ctx, order)
+	})
+}
+
+// PublishOrderExpired publishes an order.expired event to every child.
+func (m MultiPublisher) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	return m.each(func(p messaging.EventPublisher) error {
+		return p.PublishOrderExpired(ctx, order)
+	})
+}
+
+// PublishOrderSnapshot publishes an order.snapshot event to every child.
+func (m MultiPublisher) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	return m.each(func(p messaging.EventPublisher) error {
+		return p.PublishOrderSnapshot(ctx, order)
+	})
+}
+
+func (m MultiPublisher) each(publish func(messaging.EventPublisher) error) error {
+	var errs []error
+	for _, p := range m {
+		if err := publish(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// FailFastPublisher publishes each event to every child publisher in order,
+// stopping at the first child that fails and returning its error. Later
+// children are not called for that event; earlier ones have already
+// published it.
+type FailFastPublisher []messaging.EventPublisher
+
+// NewFailFast returns a FailFastPublisher over pubs, skipping nil entries.
+func NewFailFast(pubs ...messaging.EventPublisher) FailFastPublisher {
+	return FailFastPublisher(nonNil(pubs))
+}
+
+// PublishOrderCreated publishes an order.created event to each child until
+// one fails.
+func (f FailFastPublisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	return f.each(func(p messaging.EventPublisher) error {
+		return p.PublishOrderCreated(ctx, order)
+	})
+}
+
+// PublishOrderUpdated publishes an order.updated event to each child until
+// one fails.
+func (f FailFastPublisher) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	return f.each(func(p messaging.EventPublisher) error {
+		return p.PublishOrderUpdated(ctx, order, changes)
+	})
+}
+
+// PublishOrderStatusChanged publishes an order.status_changed event to each
+// child until one fails.
+func (f FailFastPublisher) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
+	return f.each(func(p messaging.EventPublisher) error {
+		return p.PublishOrderStatusChanged(ctx, order, oldStatus, newStatus)
+	})
+}
+
+// PublishOrderCancelled publishes an order.cancelled event to each child
+// until one fails.
+func (f FailFastPublisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	return f.each(func(p messaging.EventPublisher) error {
+		return p.PublishOrderCancelled(ctx, order, reason)
+	})
+}
+
+// PublishOrderDeleted publishes an order.deleted event to each child until
+// one fails.
+func (f FailFastPublisher) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	return f.each(func(p messaging.EventPublisher) error {
+		return p.PublishOrderDeleted(ctx, order)
+	})
+}
+
+// PublishOrderExpired publishes an order.expired event to each child until
+// one fails.
+func (f FailFastPublisher) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	return f.each(func(p messaging.EventPublisher) error {
+		return p.PublishOrderExpired(ctx, order)
+	})
+}
+
+// PublishOrderSnapshot publishes an order.snapshot event to each child until
+// one fails.
+func (f FailFastPublisher) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	return f.each(func(p messaging.EventPublisher) error {
+		return p.PublishOrderSnapshot(ctx, order)
+	})
+}
+
+func (f FailFastPublisher) each(publish func(messaging.EventPublisher) error) error {
+	for _, p := range f {
+		if err := publish(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func nonNil(pubs []messaging.EventPublisher) []messaging.EventPublisher {
+	out := make([]messaging.EventPublisher, 0, len(pubs))
+	for _, p := range pubs {
+		if p != nil {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,160 @@
+package multi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/noop"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingPublisher returns a mock that counts calls and fails with err.
+func recordingPublisher(calls *int, err error) *mocks.EventPublisherMock {
+	return &mocks.EventPublisherMock{
+		PublishOrderCreatedFunc: func(_ context.Context, _ *domain.Order) error {
+			*calls++
+			return err
+		},
+		PublishOrderUpdatedFunc: func(_ context.Context, _ *domain.Order, _ map[string]messaging.ChangePair) error {
+			*calls++
+			return err
+		},
+		PublishOrderStatusChangedFunc: func(_ context.Context, _ *domain.Order, _, _ domain.OrderStatus) error {
+			*calls++
+			return err
+		},
+	}
+}
+
+func TestMultiPublisher_AllSucceed_ReturnsNil(t *testing.T) {
+	var a, b int
+	pub := New(recordingPublisher(&a, nil), recordingPublisher(&b, nil))
+
+	err := pub.PublishOrderStatusChanged(context.Background(), &domain.Order{},
+		domain.OrderStatusPending, domain.OrderStatusConfirmed)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, a)
+	assert.Equal(t, 1, b)
+}
+
+func TestMultiPublisher_FirstFails_StillCallsAllChildren(t *testing.T) {
+	errKafka := errors.New("kafka down")
+	tests := []struct {
+		name    string
+		publish func(messaging.EventPublisher) error
+	}{
+		{"created", func(p messaging.EventPublisher) error {
+			return p.PublishOrderCreated(context.Background(), &domain.Order{})
+		}},
+		{"updated", func(p messaging.EventPublisher) error {
+			return p.PublishOrderUpdated(context.Background(), &domain.Order{}, nil)
+		}},
+		{"status_changed", func(p messaging.EventPublisher) error {
+			return p.PublishOrderStatusChanged(context.Background(), &domain.Order{},
+				domain.OrderStatusPending, domain.OrderStatusConfirmed)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var first, second int
+			pub := New(recordingPublisher(&first, errKafka), recordingPublisher(&second, nil))
+
+			err := tt.publish(pub)
+
+			assert.ErrorIs(t, err, errKafka)
+			assert.Equal(t, 1, first)
+			assert.Equal(t, 1, second, "later children must be called after a failure")
+		})
+	}
+}
+
+func TestMultiPublisher_SeveralFail_ReturnsCombinedError(t *testing.T) {
+	errKafka := errors.New("kafka down")
+	errAudit := errors.New("audit log full")
+	var a, b, c int
+	pub := New(recordingPublisher(&a, errKafka), recordingPublisher(&b, nil), recordingPublisher(&c, errAudit))
+
+	err := pub.PublishOrderCreated(context.Background(), &domain.Order{})
+
+	assert.ErrorIs(t, err, errKafka)
+	assert.ErrorIs(t, err, errAudit)
+	assert.Equal(t, 1, b)
+}
+
+func TestNew_SkipsNilAndEmptyIsNoop(t *testing.T) {
+	pub := New(nil)
+
+	assert.Empty(t, pub)
+	assert.NoError(t, pub.PublishOrderCreated(context.Background(), &domain.Order{}))
+}
+
+func TestFailFastPublisher_AllSucceed_CallsEveryChild(t *testing.T) {
+	var a, b int
+	pub := NewFailFast(recordingPublisher(&a, nil), recordingPublisher(&b, nil))
+
+	err := pub.PublishOrderUpdated(context.Background(), &domain.Order{}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, a)
+	assert.Equal(t, 1, b)
+}
+
+func TestFailFastPublisher_ChildFails_StopsAndReturnsItsError(t *testing.T) {
+	errNewCluster := errors.New("new cluster unreachable")
+	tests := []struct {
+		name    string
+		publish func(messaging.EventPublisher) error
+	}{
+		{"created", func(p messaging.EventPublisher) error {
+			return p.PublishOrderCreated(context.Background(), &domain.Order{})
+		}},
+		{"updated", func(p messaging.EventPublisher) error {
+			return p.PublishOrderUpdated(context.Background(), &domain.Order{}, nil)
+		}},
+		{"status_changed", func(p messaging.EventPublisher) error {
+			return p.PublishOrderStatusChanged(context.Background(), &domain.Order{},
+				domain.OrderStatusPending, domain.OrderStatusConfirmed)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var first, second, third int
+			pub := NewFailFast(recordingPublisher(&first, nil),
+				recordingPublisher(&second, errNewCluster), recordingPublisher(&third, nil))
+
+			err := tt.publish(pub)
+
+			assert.ErrorIs(t, err, errNewCluster)
+			assert.Equal(t, 1, first)
+			assert.Equal(t, 1, second)
+			assert.Zero(t, third, "children after the failure must not be called")
+		})
+	}
+}
+
+func TestNew_ComposesWithNoop(t *testing.T) {
+	errKafka := errors.New("kafka down")
+	var calls int
+
+	bestEffort := New(noop.Publisher{}, recordingPublisher(&calls, errKafka))
+	failFast := NewFailFast(noop.Publisher{}, recordingPublisher(&calls, errKafka))
+
+	assert.ErrorIs(t, bestEffort.PublishOrderCreated(context.Background(), &domain.Order{}), errKafka)
+	assert.ErrorIs(t, failFast.PublishOrderCreated(context.Background(), &domain.Order{}), errKafka)
+	assert.Equal(t, 2, calls)
+	assert.NoError(t, NewFailFast(noop.Publisher{}).PublishOrderCreated(context.Background(), &domain.Order{}))
+}
+
+func TestNewFailFast_SkipsNil(t *testing.T) {
+	pub := NewFailFast(nil)
+
+	assert.Empty(t, pub)
+	assert.NoError(t, pub.PublishOrderDeleted(context.Background(), &domain.Order{}))
+}
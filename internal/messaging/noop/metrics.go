@@ -0,0 +1,18 @@
+package noop
+
+import (
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+var _ messaging.Metrics = Metrics{}
+
+// Metrics is a messaging.Metrics that discards every measurement.
+type Metrics struct{}
+
+// ObservePublish is a no-op.
+func (Metrics) ObservePublish(_ string, _ time.Duration, _ error) {}
+
+// SetOutboxBacklog is a no-op.
+func (Metrics) SetOutboxBacklog(_ int) {}
@@ -5,18 +5,51 @@ import (
 	"context"
 
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+var (
+	_ messaging.EventPublisher = Publisher{}
+	_ messaging.BatchPublisher = Publisher{}
+	_ messaging.Pinger         = Publisher{}
 )
 
 // Publisher is a no-op EventPublisher used when Kafka is not configured.
 type Publisher struct{}
 
+// OrNoop returns p, or a no-op Publisher if p is nil.
+func OrNoop(p messaging.EventPublisher) messaging.EventPublisher {
+	if p == nil {
+		return Publisher{}
+	}
+	return p
+}
+
 // PublishOrderCreated is a no-op.
 func (Publisher) PublishOrderCreated(_ context.Context, _ *domain.Order) error { return nil }
 
 // PublishOrderUpdated is a no-op.
-func (Publisher) PublishOrderUpdated(_ context.Context, _ *domain.Order) error { return nil }
+func (Publisher) PublishOrderUpdated(_ context.Context, _ *domain.Order, _ map[string]messaging.ChangePair) error { return nil }
 
 // PublishOrderStatusChanged is a no-op.
 func (Publisher) PublishOrderStatusChanged(_ context.Context, _ *domain.Order, _, _ domain.OrderStatus) error {
 	return nil
 }
+
+// PublishOrderCancelled is a no-op.
+func (Publisher) PublishOrderCancelled(_ context.Context, _ *domain.Order, _ string) error { return nil }
+
+// PublishOrderDeleted is a no-op.
+func (Publisher) PublishOrderDeleted(_ context.Context, _ *domain.Order) error { return nil }
+
+// PublishOrderExpired is a no-op.
+func (Publisher) PublishOrderExpired(_ context.Context, _ *domain.Order) error { return nil }
+
+// PublishOrderSnapshot is a no-op.
+func (Publisher) PublishOrderSnapshot(_ context.Context, _ *domain.Order) error { return nil }
+
+// PublishBatch is a no-op.
+func (Publisher) PublishBatch(_ context.Context, _ []messaging.OrderEvent) error { return nil }
+
+// Ping is a no-op: there is no broker to reach.
+func (Publisher) Ping(_ context.Context) error { return nil }
@@ -0,0 +1,41 @@
+package noop
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrNoop_NilPublisher_ReturnsNoop(t *testing.T) {
+	pub := OrNoop(nil)
+
+	assert.Equal(t, Publisher{}, pub)
+	assert.NoError(t, pub.PublishOrderCreated(context.Background(), &domain.Order{}))
+	assert.NoError(t, pub.PublishOrderUpdated(context.Background(), &domain.Order{}, nil))
+	assert.NoError(t, pub.PublishOrderStatusChanged(context.Background(), &domain.Order{},
+		domain.OrderStatusPending, domain.OrderStatusConfirmed))
+	assert.NoError(t, pub.PublishOrderCancelled(context.Background(), &domain.Order{}, "customer request"))
+	assert.NoError(t, pub.PublishOrderDeleted(context.Background(), &domain.Order{}))
+	assert.NoError(t, pub.PublishOrderExpired(context.Background(), &domain.Order{}))
+	assert.NoError(t, pub.PublishOrderSnapshot(context.Background(), &domain.Order{}))
+}
+
+func TestPublisher_PublishBatch_ReturnsNil(t *testing.T) {
+	assert.NoError(t, Publisher{}.PublishBatch(context.Background(), []messaging.OrderEvent{{}}))
+}
+
+func TestPublisher_Ping_ReturnsNil(t *testing.T) {
+	assert.NoError(t, Publisher{}.Ping(context.Background()))
+}
+
+func TestOrNoop_InjectedPublisher_ReturnsSame(t *testing.T) {
+	injected := &mocks.EventPublisherMock{}
+
+	pub := OrNoop(injected)
+
+	assert.Same(t, injected, pub)
+}
@@ -0,0 +1,197 @@
+// Package ordered provides an EventPublisher decorator that only lets each
+// order's events through in increasing Version order.
+package ordered
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+// DefaultSize is how many orders a Publisher remembers when Wrap is given
+// a size of zero or less.
+const DefaultSize = 10000
+
+// ErrStaleEvent is returned for an event whose order Version is not
+// greater than the last one published for the order. The event is not
+// published. A retry of an event that was already delivered also gets it,
+// so callers may treat it as a duplicate.
+var ErrStaleEvent = errors.New("stale event: version already published")
+
+var _ messaging.EventPublisher = (*Publisher)(nil)
+
+// Publisher tracks the last Version published per order and rejects
+// events that would move an order backwards.
+//
+// Publishes for the same order are serialized, so of two racing events the
+// older one can never reach the wrapped publisher after the newer one.
+// A version is only recorded once the wrapped publisher succeeds, so a
+// failed publish may be retried. Publishes for different orders run
+// concurrently.
+//
+// Only the most recently published orders are remembered: once more than
+// size orders are tracked, the least recently used is forgotten and its
+// next event is accepted whatever its version. Versions are kept in
+// memory, so each replica guards only its own publishes.
+type Publisher struct {
+	next messaging.EventPublisher
+	size int
+
+	mu      sync.Mutex
+	orders  map[string]*list.Element // Values are *orderState
+	recency *list.List               // Most recently used first
+}
+
+// orderState is the last version published for one order. mu serializes
+// the order's publishes; refs counts the publishes holding or waiting for
+// it, which keep the entry from being evicted.
+type orderState struct {
+	id        string
+	mu        sync.Mutex
+	version   int
+	published bool // Whether version has been set
+	refs      int
+}
+
+// Wrap returns p decorated with a per-order version guard remembering up
+// to size orders. A size of zero or less uses DefaultSize.
+func Wrap(p messaging.EventPublisher, size int) *Publisher {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &Publisher{
+		next:    p,
+		size:    size,
+		orders:  make(map[string]*list.Element),
+		recency: list.New(),
+	}
+}
+
+// Middleware returns Wrap with size as a messaging.Middleware. Each
+// publisher it wraps remembers the versions of its own events.
+func Middleware(size int) messaging.Middleware {
+	return func(p messaging.EventPublisher) messaging.EventPublisher { return Wrap(p, size) }
+}
+
+// PublishOrderCreated publishes an order.created event unless it is stale.
+func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	return p.guard(order, func() error {
+		return p.next.PublishOrderCreated(ctx, order)
+	})
+}
+
+// PublishOrderUpdated publishes an order.updated event unless it is stale.
+func (p *Publisher) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	return p.guard(order, func() error {
+		return p.next.PublishOrderUpdated(ctx, order, changes)
+	})
+}
+
+// PublishOrderStatusChanged publishes an order.status_changed event unless
+// it is stale.
+func (p *Publisher) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
+	return p.guard(order, func() error {
+		return p.next.PublishOrderStatusChanged(ctx, order, oldStatus, newStatus)
+	})
+}
+
+// PublishOrderCancelled publishes an order.cancelled event unless it is
+// stale.
+func (p *Publisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	return p.guard(order, func() error {
+		return p.next.PublishOrderCancelled(ctx, order, reason)
+	})
+}
+
+// PublishOrderDeleted publishes an order.deleted event unless it is stale.
+func (p *Publisher) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	return p.guard(order, func() error {
+		return p.next.PublishOrderDeleted(ctx, order)
+	})
+}
+
+// PublishOrderExpired publishes an order.expired event unless it is stale.
+func (p *Publisher) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	return p.guard(order, func() error {
+		return p.next.PublishOrderExpired(ctx, order)
+	})
+}
+
+// PublishOrderSnapshot publishes an order.snapshot event. A snapshot
+// restates the order's current version rather than advancing it, so it is
+// neither checked against nor recorded as the last version published.
+func (p *Publisher) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	return p.next.PublishOrderSnapshot(ctx, order)
+}
+
+// guard calls publish if order.Version is newer than the last version
+// published for the order, and records it on success.
+func (p *Publisher) guard(order *domain.Order, publish func() error) error {
+	st := p.acquire(order.ID.String())
+	defer p.release(st)
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.published && order.Version <= st.version {
+		return fmt.Errorf("%w: order %s version %d, last published %d",
+			ErrStaleEvent, st.id, order.Version, st.version)
+	}
+	if err := publish(); err != nil {
+		return err
+	}
+	st.version, st.published = order.Version, true
+	return nil
+}
+
+// acquire returns the state of order id, creating it if needed, and marks
+// it in use and most recently used.
+func (p *Publisher) acquire(id string) *orderState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if el, ok := p.orders[id]; ok {
+		p.recency.MoveToFront(el)
+		st := el.Value.(*orderState)
+		st.refs++
+		return st
+	}
+	st := &orderState{id: id, refs: 1}
+	p.orders[id] = p.recency.PushFront(st)
+	p.evict()
+	return st
+}
+
+// release marks st no longer in use by one publish.
+func (p *Publisher) release(st *orderState) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	st.refs--
+	p.evict()
+}
+
+// evict forgets least recently used orders until at most size are
+// tracked. Orders with publishes in flight are skipped, so the size may be
+// exceeded while they finish. Callers must hold p.mu.
+func (p *Publisher) evict() {
+	for el := p.recency.Back(); el != nil && len(p.orders) > p.size; {
+		prev := el.Prev()
+		if st := el.Value.(*orderState); st.refs == 0 {
+			p.recency.Remove(el)
+			delete(p.orders, st.id)
+		}
+		el = prev
+	}
+}
+
+// Len returns how many orders are tracked.
+func (p *Publisher) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.orders)
+}
@@ -0,0 +1,164 @@
+package ordered
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/memory"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func orderAt(id uuid.UUID, version int) *domain.Order {
+	return &domain.Order{ID: id, Status: domain.OrderStatusPending, Version: version}
+}
+
+func versions(events []messaging.OrderEvent) []int {
+	out := make([]int, len(events))
+	for i, evt := range events {
+		out[i] = evt.Version
+	}
+	return out
+}
+
+func TestPublisher_StaleOrRepeatedVersion_ReturnsErrStaleEvent(t *testing.T) {
+	rec := memory.New()
+	pub := Wrap(rec, 10)
+	ctx := context.Background()
+	id := uuid.New()
+
+	require.NoError(t, pub.PublishOrderCreated(ctx, orderAt(id, 1)))
+	require.NoError(t, pub.PublishOrderUpdated(ctx, orderAt(id, 3), nil))
+
+	err := pub.PublishOrderStatusChanged(ctx, orderAt(id, 2), domain.OrderStatusPending, domain.OrderStatusConfirmed)
+	assert.ErrorIs(t, err, ErrStaleEvent, "older version")
+	err = pub.PublishOrderCancelled(ctx, orderAt(id, 3), "")
+	assert.ErrorIs(t, err, ErrStaleEvent, "repeated version")
+
+	require.NoError(t, pub.PublishOrderDeleted(ctx, orderAt(id, 4)))
+	assert.Equal(t, []int{1, 3, 4}, versions(rec.Events()))
+	assert.Equal(t, []string{messaging.EventOrderCreated, messaging.EventOrderUpdated, messaging.EventOrderDeleted}, rec.Types())
+}
+
+func TestPublisher_Snapshot_NotCheckedOrRecorded(t *testing.T) {
+	rec := memory.New()
+	pub := Wrap(rec, 10)
+	ctx := context.Background()
+	id := uuid.New()
+
+	require.NoError(t, pub.PublishOrderUpdated(ctx, orderAt(id, 2), nil))
+	require.NoError(t, pub.PublishOrderSnapshot(ctx, orderAt(id, 2)), "a snapshot restates the current version")
+	require.NoError(t, pub.PublishOrderSnapshot(ctx, orderAt(id, 5)))
+	require.NoError(t, pub.PublishOrderUpdated(ctx, orderAt(id, 3), nil), "snapshots do not advance the last version")
+
+	assert.Equal(t, []string{messaging.EventOrderUpdated, messaging.EventOrderSnapshot, messaging.EventOrderSnapshot, messaging.EventOrderUpdated}, rec.Types())
+}
+
+func TestPublisher_OrdersAreTrackedSeparately(t *testing.T) {
+	rec := memory.New()
+	pub := Wrap(rec, 10)
+	ctx := context.Background()
+
+	require.NoError(t, pub.PublishOrderUpdated(ctx, orderAt(uuid.New(), 5), nil))
+	require.NoError(t, pub.PublishOrderUpdated(ctx, orderAt(uuid.New(), 1), nil))
+
+	assert.Len(t, rec.Events(), 2)
+}
+
+func TestPublisher_FailedPublish_IsNotRecorded(t *testing.T) {
+	calls := 0
+	next := &mocks.EventPublisherMock{
+		PublishOrderUpdatedFunc: func(context.Context, *domain.Order, map[string]messaging.ChangePair) error {
+			calls++
+			if calls == 1 {
+				return errors.New("broker unavailable")
+			}
+			return nil
+		},
+	}
+	pub := Wrap(next, 10)
+	order := orderAt(uuid.New(), 2)
+
+	require.Error(t, pub.PublishOrderUpdated(context.Background(), order, nil))
+	assert.NoError(t, pub.PublishOrderUpdated(context.Background(), order, nil), "the failed version may be retried")
+	assert.Equal(t, 2, calls)
+}
+
+func TestPublisher_LeastRecentlyUsedOrderIsEvicted(t *testing.T) {
+	rec := memory.New()
+	pub := Wrap(rec, 2)
+	ctx := context.Background()
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+
+	require.NoError(t, pub.PublishOrderCreated(ctx, orderAt(a, 1)))
+	require.NoError(t, pub.PublishOrderCreated(ctx, orderAt(b, 1)))
+	require.NoError(t, pub.PublishOrderUpdated(ctx, orderAt(a, 2), nil)) // b is now least recently used
+	require.NoError(t, pub.PublishOrderCreated(ctx, orderAt(c, 1)))
+
+	assert.Equal(t, 2, pub.Len())
+	assert.ErrorIs(t, pub.PublishOrderUpdated(ctx, orderAt(a, 2), nil), ErrStaleEvent, "a is still tracked")
+	assert.NoError(t, pub.PublishOrderUpdated(ctx, orderAt(b, 1), nil), "b was forgotten")
+}
+
+func TestPublisher_ConcurrentInterleavedVersions_EmitsOnlyIncreasing(t *testing.T) {
+	const (
+		orders   = 8
+		perOrder = 50
+	)
+	rec := memory.New()
+	pub := Wrap(rec, orders)
+	ids := make([]uuid.UUID, orders)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	// Every version of every order is published from its own goroutine,
+	// in shuffled order, so versions race each other
+	type job struct {
+		id      uuid.UUID
+		version int
+	}
+	var jobs []job
+	for _, id := range ids {
+		for v := 1; v <= perOrder; v++ {
+			jobs = append(jobs, job{id, v})
+		}
+	}
+	rand.Shuffle(len(jobs), func(i, j int) { jobs[i], jobs[j] = jobs[j], jobs[i] })
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	stale := 0
+	for _, j := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := pub.PublishOrderUpdated(context.Background(), orderAt(j.id, j.version), nil)
+			if err != nil {
+				assert.ErrorIs(t, err, ErrStaleEvent)
+				mu.Lock()
+				stale++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	emitted := 0
+	for _, id := range ids {
+		got := versions(rec.EventsForOrder(id.String()))
+		require.NotEmpty(t, got)
+		for i := 1; i < len(got); i++ {
+			assert.Greater(t, got[i], got[i-1], "order %s emitted %v", id, got)
+		}
+		emitted += len(got)
+	}
+	assert.Equal(t, len(jobs), emitted+stale, "every event is either emitted or rejected")
+}
@@ -0,0 +1,108 @@
+// Package otel provides an EventPublisher decorator that traces each publish
+// with OpenTelemetry.
+package otel
+
+import (
+	"context"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attribute keys set on every publish span.
+const (
+	AttrOrderID   = attribute.Key("order.id")
+	AttrEventType = attribute.Key("event.type")
+	AttrTotal     = attribute.Key("order.total")
+)
+
+var _ messaging.EventPublisher = (*Publisher)(nil)
+
+// Publisher wraps an EventPublisher with a producer span per publish.
+// The span is carried in the context passed to the wrapped publisher, so a
+// Kafka publisher underneath propagates it in message headers.
+type Publisher struct {
+	next   messaging.EventPublisher
+	tracer trace.Tracer
+}
+
+// Wrap returns p decorated with tracing spans from tracer.
+func Wrap(p messaging.EventPublisher, tracer trace.Tracer) messaging.EventPublisher {
+	return &Publisher{next: p, tracer: tracer}
+}
+
+// Middleware returns Wrap with tracer as a messaging.Middleware.
+func Middleware(tracer trace.Tracer) messaging.Middleware {
+	return func(p messaging.EventPublisher) messaging.EventPublisher { return Wrap(p, tracer) }
+}
+
+// PublishOrderCreated publishes an order.created event inside a span.
+func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	return p.traced(ctx, messaging.EventOrderCreated, order, func(ctx context.Context) error {
+		return p.next.PublishOrderCreated(ctx, order)
+	})
+}
+
+// PublishOrderUpdated publishes an order.updated event inside a span.
+func (p *Publisher) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	return p.traced(ctx, messaging.EventOrderUpdated, order, func(ctx context.Context) error {
+		return p.next.PublishOrderUpdated(ctx, order, changes)
+	})
+}
+
+// PublishOrderStatusChanged publishes an order.status_changed event inside a span.
+func (p *Publisher) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
+	return p.traced(ctx, messaging.EventOrderStatusChanged, order, func(ctx context.Context) error {
+		return p.next.PublishOrderStatusChanged(ctx, order, oldStatus, newStatus)
+	})
+}
+
+// PublishOrderCancelled publishes an order.cancelled event inside a span.
+func (p *Publisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	return p.traced(ctx, messaging.EventOrderCancelled, order, func(ctx context.Context) error {
+		return p.next.PublishOrderCancelled(ctx, order, reason)
+	})
+}
+
+// PublishOrderDeleted publishes an order.deleted event inside a span.
+func (p *Publisher) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	return p.traced(ctx, messaging.EventOrderDeleted, order, func(ctx context.Context) error {
+		return p.next.PublishOrderDeleted(ctx, order)
+	})
+}
+
+// PublishOrderExpired publishes an order.expired event inside a span.
+func (p *Publisher) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	return p.traced(ctx, messaging.EventOrderExpired, order, func(ctx context.Context) error {
+		return p.next.PublishOrderExpired(ctx, order)
+	})
+}
+
+// PublishOrderSnapshot publishes an order.snapshot event inside a span.
+func (p *Publisher) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	return p.traced(ctx, messaging.EventOrderSnapshot, order, func(ctx context.Context) error {
+		return p.next.PublishOrderSnapshot(ctx, order)
+	})
+}
+
+func (p *Publisher) traced(ctx context.Context, eventType string, order *domain.Order, publish func(context.Context) error) error {
+	ctx, span := p.tracer.Start(ctx, "publish "+eventType,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			AttrOrderID.String(order.ID.String()),
+			AttrEventType.String(eventType),
+			AttrTotal.Float64(order.Total.Float64()),
+		),
+	)
+	defer span.End()
+
+	if err := publish(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newRecorder(t *testing.T) (*tracetest.SpanRecorder, trace.Tracer) {
+	t.Helper()
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	return sr, tp.Tracer("test")
+}
+
+func newTestOrder() *domain.Order {
+	return &domain.Order{ID: uuid.New(), CustomerID: "cust-1", Status: domain.OrderStatusPending, Total: domain.Money{Amount: 4250, Currency: "USD"}}
+}
+
+func attrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	out := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		out[kv.Key] = kv.Value
+	}
+	return out
+}
+
+func TestWrap_Publish_RecordsSpanPerEventType(t *testing.T) {
+	tests := []struct {
+		eventType string
+		publish   func(messaging.EventPublisher, *domain.Order) error
+	}{
+		{messaging.EventOrderCreated, func(p messaging.EventPublisher, o *domain.Order) error {
+			return p.PublishOrderCreated(context.Background(), o)
+		}},
+		{messaging.EventOrderUpdated, func(p messaging.EventPublisher, o *domain.Order) error {
+			return p.PublishOrderUpdated(context.Background(), o, nil)
+		}},
+		{messaging.EventOrderStatusChanged, func(p messaging.EventPublisher, o *domain.Order) error {
+			return p.PublishOrderStatusChanged(context.Background(), o,
+				domain.OrderStatusPending, domain.OrderStatusConfirmed)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.eventType, func(t *testing.T) {
+			sr, tracer := newRecorder(t)
+			order := newTestOrder()
+			pub := Wrap(&mocks.EventPublisherMock{}, tracer)
+
+			require.NoError(t, tt.publish(pub, order))
+
+			spans := sr.Ended()
+			require.Len(t, spans, 1)
+			span := spans[0]
+			assert.Equal(t, "publish "+tt.eventType, span.Name())
+			assert.Equal(t, trace.SpanKindProducer, span.SpanKind())
+			a := attrs(span)
+			assert.Equal(t, order.ID.String(), a[AttrOrderID].AsString())
+			assert.Equal(t, tt.eventType, a[AttrEventType].AsString())
+			assert.Equal(t, 42.50, a[AttrTotal].AsFloat64())
+			assert.Equal(t, codes.Unset, span.Status().Code)
+		})
+	}
+}
+
+func TestWrap_PublishError_RecordsErrorStatus(t *testing.T) {
+	sr, tracer := newRecorder(t)
+	errBroker := errors.New("broker unavailable")
+	pub := Wrap(&mocks.EventPublisherMock{
+		PublishOrderCreatedFunc: func(_ context.Context, _ *domain.Order) error { return errBroker },
+	}, tracer)
+
+	err := pub.PublishOrderCreated(context.Background(), newTestOrder())
+
+	assert.ErrorIs(t, err, errBroker)
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "broker unavailable", spans[0].Status().Description)
+	require.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, "exception", spans[0].Events()[0].Name)
+}
+
+func TestWrap_PassesSpanContextToWrappedPublisher(t *testing.T) {
+	sr, tracer := newRecorder(t)
+	var inner trace.SpanContext
+	pub := Wrap(&mocks.EventPublisherMock{
+		PublishOrderCreatedFunc: func(ctx context.Context, _ *domain.Order) error {
+			inner = trace.SpanContextFromContext(ctx)
+			return nil
+		},
+	}, tracer)
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newTestOrder()))
+
+	require.Len(t, sr.Ended(), 1)
+	assert.Equal(t, sr.Ended()[0].SpanContext().SpanID(), inner.SpanID())
+}
@@ -0,0 +1,393 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/noop"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memStore is an in-memory Store for tests.
+type memStore struct {
+	mu        sync.Mutex
+	records   []Record
+	published map[string]bool
+	markErr   error
+}
+
+func newMemStore() *memStore {
+	return &memStore{published: make(map[string]bool)}
+}
+
+func (s *memStore) Enqueue(_ context.Context, rec Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.records {
+		if r.ID == rec.ID {
+			return nil
+		}
+	}
+	s.records = append(s.records, rec)
+	return nil
+}
+
+func (s *memStore) FetchUnpublished(_ context.Context, limit int) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Record
+	for _, r := range s.records {
+		if !s.published[r.ID] && len(out) < limit {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (s *memStore) MarkPublished(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.markErr != nil {
+		return s.markErr
+	}
+	s.published[id] = true
+	return nil
+}
+
+func (s *memStore) unpublished() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.records) - len(s.published)
+}
+
+func (s *memStore) CountUnpublished(_ context.Context) (int, error) {
+	return s.unpublished(), nil
+}
+
+// backlogMetrics records the backlog values reported to it.
+type backlogMetrics struct {
+	noop.Metrics
+	backlog []int
+}
+
+func (m *backlogMetrics) SetOutboxBacklog(n int) { m.backlog = append(m.backlog, n) }
+
+// flakySender fails while down is true and records delivered events.
+type flakySender struct {
+	mu        sync.Mutex
+	down      bool
+	failOrder string
+	delivered []messaging.OrderEvent
+}
+
+func (f *flakySender) Publish(_ context.Context, evt messaging.OrderEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.down || evt.OrderID == f.failOrder {
+		return errors.New("broker unavailable")
+	}
+	f.delivered = append(f.delivered, evt)
+	return nil
+}
+
+func (f *flakySender) setDown(down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+}
+
+func newTestOrder() *domain.Order {
+	return &domain.Order{
+		ID:         uuid.New(),
+		CustomerID: "cust-123",
+		Status:     domain.OrderStatusPending,
+		Total:      domain.Money{Amount: 2100, Currency: "USD"},
+		Version:    1,
+	}
+}
+
+func TestPublisher_PublishOrderCreated_EnqueuesEvent(t *testing.T) {
+	store := newMemStore()
+	pub := NewPublisher(store)
+	order := newTestOrder()
+
+	err := pub.PublishOrderCreated(context.Background(), order)
+
+	require.NoError(t, err)
+	require.Len(t, store.records, 1)
+	rec := store.records[0]
+	assert.NotEmpty(t, rec.ID)
+	assert.Equal(t, order.ID.String(), rec.OrderID)
+	assert.Equal(t, messaging.EventOrderCreated, rec.EventType)
+
+	var evt messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(rec.Payload, &evt))
+	assert.Equal(t, order.ID.String(), evt.OrderID)
+	assert.Equal(t, 21.00, evt.Total)
+	assert.Equal(t, rec.ID, evt.EventID, "record ID should be the event ID")
+}
+
+// fakeClock always returns the same time.
+type fakeClock struct{ now time.Time }
+
+func (c fakeClock) Now() time.Time { return c.now }
+
+func TestPublisher_WithClock_StampsEventAndRecord(t *testing.T) {
+	store := newMemStore()
+	fixed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	pub := NewPublisher(store, WithClock(fakeClock{now: fixed}))
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newTestOrder()))
+
+	require.Len(t, store.records, 1)
+	assert.Equal(t, fixed, store.records[0].CreatedAt)
+	var evt messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(store.records[0].Payload, &evt))
+	assert.True(t, fixed.Equal(evt.OccurredAt), "got %v", evt.OccurredAt)
+}
+
+func TestPublisher_PinnedOccurredAt_OverridesClock(t *testing.T) {
+	store := newMemStore()
+	pinned := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	pub := NewPublisher(store, WithClock(fakeClock{now: pinned.Add(time.Hour)}))
+	ctx := messaging.WithOccurredAt(context.Background(), pinned)
+
+	require.NoError(t, pub.PublishOrderStatusChanged(ctx, newTestOrder(), domain.OrderStatusPending, domain.OrderStatusConfirmed))
+
+	require.Len(t, store.records, 1)
+	assert.Equal(t, pinned, store.records[0].CreatedAt)
+	var evt messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(store.records[0].Payload, &evt))
+	assert.True(t, pinned.Equal(evt.OccurredAt), "got %v", evt.OccurredAt)
+}
+
+func TestPublisher_PublishOrderStatusChanged_EnqueuesOldAndNewStatus(t *testing.T) {
+	store := newMemStore()
+	pub := NewPublisher(store)
+
+	err := pub.PublishOrderStatusChanged(context.Background(), newTestOrder(),
+		domain.OrderStatusPending, domain.OrderStatusConfirmed)
+
+	require.NoError(t, err)
+	var evt messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(store.records[0].Payload, &evt))
+	assert.Equal(t, messaging.EventOrderStatusChanged, evt.EventType)
+	assert.Equal(t, domain.OrderStatusPending, evt.OldStatus)
+	assert.Equal(t, domain.OrderStatusConfirmed, evt.NewStatus)
+}
+
+func TestRelay_RelayedEvent_KeepsRequestCorrelationID(t *testing.T) {
+	store := newMemStore()
+	sender := &flakySender{}
+	relay := NewRelay(store, sender, time.Second)
+
+	reqCtx := messaging.WithRequestID(context.Background(), "req-abc")
+	require.NoError(t, NewPublisher(store).PublishOrderCreated(reqCtx, newTestOrder()))
+	require.NoError(t, relay.RelayOnce(context.Background()))
+
+	require.Len(t, sender.delivered, 1)
+	assert.Equal(t, "req-abc", sender.delivered[0].CorrelationID,
+		"the relay has no request, so the stored ID must be kept")
+}
+
+func TestRelay_KafkaOutage_NoEventsDropped(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	pub := NewPublisher(store)
+	sender := &flakySender{down: true}
+	relay := NewRelay(store, sender, time.Second)
+
+	order := newTestOrder()
+	require.NoError(t, pub.PublishOrderCreated(ctx, order))
+	order.Version = 2
+	require.NoError(t, pub.PublishOrderUpdated(ctx, order, nil))
+	order.Version = 3
+	require.NoError(t, pub.PublishOrderStatusChanged(ctx, order, domain.OrderStatusPending, domain.OrderStatusConfirmed))
+
+	// Broker is down: nothing is delivered or marked
+	for i := 0; i < 3; i++ {
+		require.NoError(t, relay.RelayOnce(ctx))
+	}
+	assert.Empty(t, sender.delivered)
+	assert.Equal(t, 3, store.unpublished())
+
+	// Broker recovers: everything is delivered exactly once, in order
+	sender.setDown(false)
+	require.NoError(t, relay.RelayOnce(ctx))
+	require.NoError(t, relay.RelayOnce(ctx))
+
+	require.Len(t, sender.delivered, 3)
+	assert.Equal(t, messaging.EventOrderCreated, sender.delivered[0].EventType)
+	assert.Equal(t, messaging.EventOrderUpdated, sender.delivered[1].EventType)
+	assert.Equal(t, messaging.EventOrderStatusChanged, sender.delivered[2].EventType)
+	assert.Equal(t, 0, store.unpublished())
+}
+
+func TestRelay_RelayOnce_ReportsBacklog(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	pub := NewPublisher(store)
+	sender := &flakySender{down: true}
+	relay := NewRelay(store, sender, time.Second)
+	metrics := &backlogMetrics{}
+	relay.SetMetrics(metrics)
+
+	order := newTestOrder()
+	require.NoError(t, pub.PublishOrderCreated(ctx, order))
+	order.Version = 2
+	require.NoError(t, pub.PublishOrderUpdated(ctx, order, nil))
+
+	require.NoError(t, relay.RelayOnce(ctx))
+	sender.setDown(false)
+	require.NoError(t, relay.RelayOnce(ctx))
+
+	assert.Equal(t, []int{2, 0}, metrics.backlog)
+}
+
+func TestRelay_SendFailure_HoldsBackLaterEventsForSameOrder(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	pub := NewPublisher(store)
+	failing := newTestOrder()
+	healthy := newTestOrder()
+	sender := &flakySender{failOrder: failing.ID.String()}
+	relay := NewRelay(store, sender, time.Second)
+
+	require.NoError(t, pub.PublishOrderCreated(ctx, failing))
+	require.NoError(t, pub.PublishOrderCreated(ctx, healthy))
+	require.NoError(t, pub.PublishOrderUpdated(ctx, failing, nil))
+
+	require.NoError(t, relay.RelayOnce(ctx))
+
+	require.Len(t, sender.delivered, 1, "only the healthy order is delivered")
+	assert.Equal(t, healthy.ID.String(), sender.delivered[0].OrderID)
+	assert.Equal(t, 2, store.unpublished(), "failed order's events stay queued")
+}
+
+// lostAckSender delivers every event but reports the first delivery as
+// failed, as when the broker's acknowledgement is lost.
+type lostAckSender struct {
+	delivered []messaging.OrderEvent
+}
+
+func (s *lostAckSender) Publish(_ context.Context, evt messaging.OrderEvent) error {
+	s.delivered = append(s.delivered, evt)
+	if len(s.delivered) == 1 {
+		return errors.New("write timeout")
+	}
+	return nil
+}
+
+func TestRelay_ResentEvent_KeepsEventID(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	sender := &lostAckSender{}
+	relay := NewRelay(store, sender, time.Second)
+	order := newTestOrder()
+	require.NoError(t, NewPublisher(store).PublishOrderCreated(ctx, order))
+	order.Version = 2
+	require.NoError(t, NewPublisher(store).PublishOrderUpdated(ctx, order, map[string]messaging.ChangePair{"items": {}}))
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, relay.RelayOnce(ctx))
+	}
+
+	require.Len(t, sender.delivered, 3, "the created event is resent after its lost acknowledgement")
+	created, resent, updated := sender.delivered[0], sender.delivered[1], sender.delivered[2]
+	assert.Equal(t, created.EventID, resent.EventID, "a duplicate delivery has the same event ID")
+	assert.NotEqual(t, created.EventID, updated.EventID, "distinct events have distinct IDs")
+	assert.Equal(t, 0, store.unpublished())
+}
+
+func TestRelay_MarkFailure_RetriesMarkWithoutResending(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	store.markErr = errors.New("db unavailable")
+	sender := &flakySender{}
+	relay := NewRelay(store, sender, time.Second)
+	require.NoError(t, NewPublisher(store).PublishOrderCreated(ctx, newTestOrder()))
+
+	require.NoError(t, relay.RelayOnce(ctx))
+	store.markErr = nil
+	require.NoError(t, relay.RelayOnce(ctx))
+
+	assert.Len(t, sender.delivered, 1, "event must not be sent twice")
+	assert.Equal(t, 0, store.unpublished())
+}
+
+func TestRelay_Run_StopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	relay := NewRelay(newMemStore(), &flakySender{}, 10*time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		relay.Run(ctx)
+		close(done)
+	}()
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("relay did not stop after context cancel")
+	}
+}
+
+// intermittentSender fails every nth publish attempt.
+type intermittentSender struct {
+	mu        sync.Mutex
+	n         int
+	attempts  int
+	delivered []messaging.OrderEvent
+}
+
+func (s *intermittentSender) Publish(_ context.Context, evt messaging.OrderEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	if s.attempts%s.n == 0 {
+		return errors.New("broker timeout")
+	}
+	s.delivered = append(s.delivered, evt)
+	return nil
+}
+
+func TestRelay_IntermittentFailures_AtLeastOnceInPerOrderOrder(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	pub := NewPublisher(store)
+	sender := &intermittentSender{n: 3}
+	relay := NewRelay(store, sender, time.Second)
+
+	orders := []*domain.Order{newTestOrder(), newTestOrder(), newTestOrder()}
+	for v := 1; v <= 4; v++ {
+		for _, o := range orders {
+			o.Version = v
+			require.NoError(t, pub.PublishOrderUpdated(ctx, o, nil))
+		}
+	}
+
+	for i := 0; i < 20 && store.unpublished() > 0; i++ {
+		require.NoError(t, relay.RelayOnce(ctx))
+	}
+	require.Equal(t, 0, store.unpublished())
+
+	// Every event is delivered, and each order's versions arrive in order
+	lastVersion := map[string]int{}
+	for _, evt := range sender.delivered {
+		assert.Greater(t, evt.Version, lastVersion[evt.OrderID],
+			"events for order %s delivered out of order", evt.OrderID)
+		lastVersion[evt.OrderID] = evt.Version
+	}
+	for _, o := range orders {
+		assert.Equal(t, 4, lastVersion[o.ID.String()])
+	}
+}
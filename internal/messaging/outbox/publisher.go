@@ -0,0 +1,97 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+var _ messaging.EventPublisher = (*Publisher)(nil)
+
+// Publisher implements messaging.EventPublisher by enqueueing events in the
+// outbox instead of sending them to the broker.
+// It must be called inside the transaction that persists the order.
+type Publisher struct {
+	store Store
+	clock messaging.Clock
+}
+
+// PublisherOption configures a Publisher created by NewPublisher.
+type PublisherOption func(*Publisher)
+
+// WithClock sets the clock that stamps OccurredAt on enqueued events, and
+// so the CreatedAt of their records, unless the context pins the time with
+// messaging.WithOccurredAt. Defaults to messaging.SystemClock.
+func WithClock(c messaging.Clock) PublisherOption {
+	return func(p *Publisher) { p.clock = c }
+}
+
+// NewPublisher creates an outbox event publisher.
+func NewPublisher(store Store, opts ...PublisherOption) *Publisher {
+	p := &Publisher{store: store, clock: messaging.SystemClock{}}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// PublishOrderCreated enqueues an order.created event.
+func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	return p.enqueue(ctx, messaging.NewOrderEvent(messaging.EventOrderCreated, order))
+}
+
+// PublishOrderUpdated enqueues an order.updated event.
+func (p *Publisher) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	return p.enqueue(ctx, messaging.NewUpdatedEvent(order, changes))
+}
+
+// PublishOrderStatusChanged enqueues an order.status_changed event.
+func (p *Publisher) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
+	return p.enqueue(ctx, messaging.NewStatusChangedEvent(order, oldStatus, newStatus))
+}
+
+// PublishOrderCancelled enqueues an order.cancelled event.
+func (p *Publisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	return p.enqueue(ctx, messaging.NewCancelledEvent(order, reason))
+}
+
+// PublishOrderDeleted enqueues an order.deleted event.
+func (p *Publisher) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	return p.enqueue(ctx, messaging.NewOrderEvent(messaging.EventOrderDeleted, order))
+}
+
+// PublishOrderExpired enqueues an order.expired event.
+func (p *Publisher) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	return p.enqueue(ctx, messaging.NewOrderEvent(messaging.EventOrderExpired, order))
+}
+
+// PublishOrderSnapshot enqueues an order.snapshot event.
+func (p *Publisher) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	return p.enqueue(ctx, messaging.NewSnapshotEvent(order))
+}
+
+func (p *Publisher) enqueue(ctx context.Context, evt messaging.OrderEvent) error {
+	evt.OccurredAt = p.clock.Now()
+	evt = messaging.Correlate(ctx, evt)
+	if err := evt.Validate(); err != nil {
+		return fmt.Errorf("outbox enqueue %s: %w", evt.EventType, err)
+	}
+	payload, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("outbox marshal %s: %w", evt.EventType, err)
+	}
+	rec := Record{
+		ID:        evt.EventID,
+		OrderID:   evt.OrderID,
+		EventType: evt.EventType,
+		Payload:   payload,
+		CreatedAt: evt.OccurredAt,
+	}
+	if err := p.store.Enqueue(ctx, rec); err != nil {
+		return fmt.Errorf("outbox enqueue %s: %w", evt.EventType, err)
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/noop"
+)
+
+const defaultBatchSize = 100
+
+// Sender delivers a stored event to the broker.
+// kafka.Publisher satisfies it.
+type Sender interface {
+	Publish(ctx context.Context, evt messaging.OrderEvent) error
+}
+
+// Relay polls the outbox and delivers unpublished records.
+//
+// Delivery is at-least-once. A record that was sent but could not be marked
+// as published is remembered by ID and only re-marked on the next poll, so
+// store errors do not cause duplicate sends. When a record fails to send,
+// later records for the same order are held back until it succeeds, which
+// preserves per-order ordering.
+type Relay struct {
+	store     Store
+	sender    Sender
+	interval  time.Duration
+	batchSize int
+	metrics   messaging.Metrics
+	logger    *slog.Logger
+
+	// sent holds IDs of records delivered but not yet marked published.
+	sent map[string]struct{}
+}
+
+// NewRelay creates a relay that polls store every interval and hands
+// unpublished records to sender.
+func NewRelay(store Store, sender Sender, interval time.Duration) *Relay {
+	return &Relay{
+		store:     store,
+		sender:    sender,
+		interval:  interval,
+		batchSize: defaultBatchSize,
+		metrics:   noop.Metrics{},
+		logger:    slog.New(slog.DiscardHandler),
+		sent:      make(map[string]struct{}),
+	}
+}
+
+// SetMetrics sets where the relay reports the outbox backlog, if the store
+// implements BacklogCounter. Call it before Run.
+func (w *Relay) SetMetrics(m messaging.Metrics) {
+	w.metrics = m
+}
+
+// SetLogger sets where the relay logs the records it fails to deliver or
+// mark. It logs nothing by default. Call it before Run.
+func (w *Relay) SetLogger(l *slog.Logger) {
+	w.logger = l
+}
+
+// Run polls the outbox until ctx is cancelled.
+func (w *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.RelayOnce(ctx); err != nil && ctx.Err() == nil {
+			w.logger.Warn("outbox relay failed", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce delivers one batch of unpublished records.
+// It returns an error only if the batch could not be fetched; per-record
+// failures are logged and retried on the next call.
+// RelayOnce must not be called concurrently.
+func (w *Relay) RelayOnce(ctx context.Context) error {
+	records, err := w.store.FetchUnpublished(ctx, w.batchSize)
+	if err != nil {
+		return fmt.Errorf("outbox fetch: %w", err)
+	}
+
+	// Orders with a failed record; their later records must wait.
+	blocked := make(map[string]struct{})
+
+	for _, rec := range records {
+		if _, ok := blocked[rec.OrderID]; ok {
+			continue
+		}
+
+		if _, ok := w.sent[rec.ID]; !ok {
+			if err := w.send(ctx, rec); err != nil {
+				blocked[rec.OrderID] = struct{}{}
+				w.logger.Warn("outbox send failed",
+					slog.String("event_id", rec.ID),
+					slog.String("order_id", rec.OrderID),
+					slog.String("error", err.Error()))
+				continue
+			}
+			w.sent[rec.ID] = struct{}{}
+		}
+
+		if err := w.store.MarkPublished(ctx, rec.ID); err != nil {
+			blocked[rec.OrderID] = struct{}{}
+			w.logger.Warn("outbox mark published failed",
+				slog.String("event_id", rec.ID),
+				slog.String("order_id", rec.OrderID),
+				slog.String("error", err.Error()))
+			continue
+		}
+		delete(w.sent, rec.ID)
+	}
+
+	w.reportBacklog(ctx)
+	return nil
+}
+
+func (w *Relay) reportBacklog(ctx context.Context) {
+	counter, ok := w.store.(BacklogCounter)
+	if !ok {
+		return
+	}
+	n, err := counter.CountUnpublished(ctx)
+	if err != nil {
+		w.logger.Warn("outbox backlog count failed", slog.String("error", err.Error()))
+		return
+	}
+	w.metrics.SetOutboxBacklog(n)
+}
+
+func (w *Relay) send(ctx context.Context, rec Record) error {
+	evt, err := messaging.Unmarshal(messaging.JSONSerializer{}, rec.Payload)
+	if err != nil {
+		return fmt.Errorf("unmarshal payload: %w", err)
+	}
+	return w.sender.Publish(ctx, evt)
+}
@@ -0,0 +1,64 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+// HeaderReplayed is set to "true" on every event re-emitted by
+// Relay.Replay, so consumers can tell replays from first deliveries.
+const HeaderReplayed = "replayed"
+
+// ErrNoHistory is returned by Relay.Replay when the relay's store does not
+// implement HistoryReader.
+var ErrNoHistory = errors.New("outbox: store keeps no history")
+
+// ReplayPublisher receives replayed events with their headers.
+// kafka.Publisher satisfies it.
+type ReplayPublisher interface {
+	PublishWithHeaders(ctx context.Context, evt messaging.OrderEvent, headers map[string]string) error
+}
+
+// Replay republishes every stored event that occurred in [from, to),
+// published or not, in the order it was enqueued, and returns how many it
+// republished. Each event has Replayed set and carries HeaderReplayed.
+//
+// Replay only reads the store: records are not marked published and the
+// relay's pending state is left alone, so it may run alongside Run.
+// It stops at the first event publisher fails to publish, returning an
+// error naming it; events before it have been republished.
+func (w *Relay) Replay(ctx context.Context, from, to time.Time, publisher ReplayPublisher) (int, error) {
+	history, ok := w.store.(HistoryReader)
+	if !ok {
+		return 0, ErrNoHistory
+	}
+	headers := map[string]string{HeaderReplayed: "true"}
+
+	n := 0
+	var afterSeq int64
+	for {
+		records, err := history.FetchCreatedBetween(ctx, from, to, afterSeq, w.batchSize)
+		if err != nil {
+			return n, fmt.Errorf("outbox replay fetch: %w", err)
+		}
+		for _, rec := range records {
+			evt, err := messaging.Unmarshal(messaging.JSONSerializer{}, rec.Payload)
+			if err != nil {
+				return n, fmt.Errorf("outbox replay %s: unmarshal payload: %w", rec.ID, err)
+			}
+			evt.Replayed = true
+			if err := publisher.PublishWithHeaders(ctx, evt, headers); err != nil {
+				return n, fmt.Errorf("outbox replay %s: %w", rec.ID, err)
+			}
+			n++
+			afterSeq = rec.Seq
+		}
+		if len(records) < w.batchSize {
+			return n, nil
+		}
+	}
+}
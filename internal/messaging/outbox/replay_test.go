@@ -0,0 +1,145 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// historyStore is a memStore that keeps every record for replays,
+// numbering them in enqueue order.
+type historyStore struct {
+	*memStore
+}
+
+func newHistoryStore() *historyStore {
+	return &historyStore{memStore: newMemStore()}
+}
+
+func (s *historyStore) Enqueue(ctx context.Context, rec Record) error {
+	s.mu.Lock()
+	rec.Seq = int64(len(s.records) + 1)
+	s.mu.Unlock()
+	return s.memStore.Enqueue(ctx, rec)
+}
+
+func (s *historyStore) FetchCreatedBetween(_ context.Context, from, to time.Time, afterSeq int64, limit int) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Record
+	for _, r := range s.records {
+		if r.Seq > afterSeq && !r.CreatedAt.Before(from) && r.CreatedAt.Before(to) && len(out) < limit {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// stepClock advances by a minute on every call.
+type stepClock struct {
+	mu   sync.Mutex
+	next time.Time
+}
+
+func (c *stepClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.next
+	c.next = c.next.Add(time.Minute)
+	return now
+}
+
+// headerRecorder records events with the headers they were published with.
+type headerRecorder struct {
+	events  []messaging.OrderEvent
+	headers []map[string]string
+	failAt  int // Fails the failAt-th publish when non-zero
+}
+
+func (r *headerRecorder) PublishWithHeaders(_ context.Context, evt messaging.OrderEvent, headers map[string]string) error {
+	if r.failAt > 0 && len(r.events)+1 == r.failAt {
+		return errors.New("broker unavailable")
+	}
+	r.events = append(r.events, evt)
+	r.headers = append(r.headers, headers)
+	return nil
+}
+
+var replayStart = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// seedHistory enqueues five updates of one order, a minute apart from
+// replayStart, with versions 1 to 5.
+func seedHistory(t *testing.T, store Store) {
+	t.Helper()
+	pub := NewPublisher(store, WithClock(&stepClock{next: replayStart}))
+	order := newTestOrder()
+	for v := 1; v <= 5; v++ {
+		order.Version = v
+		require.NoError(t, pub.PublishOrderUpdated(context.Background(), order, nil))
+	}
+}
+
+func TestRelay_Replay_RepublishesWindowInOrderWithHeader(t *testing.T) {
+	store := newHistoryStore()
+	seedHistory(t, store)
+	relay := NewRelay(store, &flakySender{}, time.Second)
+	relay.batchSize = 2 // Forces paging
+	rec := &headerRecorder{}
+
+	n, err := relay.Replay(context.Background(), replayStart.Add(time.Minute), replayStart.Add(4*time.Minute), rec)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	require.Len(t, rec.events, 3)
+	for i, evt := range rec.events {
+		assert.Equal(t, i+2, evt.Version, "events replayed out of order")
+		assert.True(t, evt.Replayed)
+		assert.Equal(t, "true", rec.headers[i][HeaderReplayed])
+	}
+}
+
+func TestRelay_Replay_LeavesSentStateUnchanged(t *testing.T) {
+	ctx := context.Background()
+	store := newHistoryStore()
+	seedHistory(t, store)
+	sender := &flakySender{}
+	relay := NewRelay(store, sender, time.Second)
+
+	n, err := relay.Replay(ctx, replayStart, replayStart.Add(time.Hour), &headerRecorder{})
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, 5, store.unpublished(), "replay must not mark records published")
+	assert.Empty(t, relay.sent)
+
+	// The relay still delivers every record, without the replay flag
+	require.NoError(t, relay.RelayOnce(ctx))
+	require.Len(t, sender.delivered, 5)
+	assert.False(t, sender.delivered[0].Replayed)
+	assert.Equal(t, 0, store.unpublished())
+}
+
+func TestRelay_Replay_PublishFailure_StopsWithCount(t *testing.T) {
+	store := newHistoryStore()
+	seedHistory(t, store)
+	relay := NewRelay(store, &flakySender{}, time.Second)
+
+	n, err := relay.Replay(context.Background(), replayStart, replayStart.Add(time.Hour), &headerRecorder{failAt: 3})
+
+	require.Error(t, err)
+	assert.Equal(t, 2, n)
+}
+
+func TestRelay_Replay_StoreWithoutHistory_ErrNoHistory(t *testing.T) {
+	relay := NewRelay(newMemStore(), &flakySender{}, time.Second)
+
+	_, err := relay.Replay(context.Background(), replayStart, replayStart.Add(time.Hour), &headerRecorder{})
+
+	assert.ErrorIs(t, err, ErrNoHistory)
+}
@@ -0,0 +1,52 @@
+// Package outbox implements the transactional outbox pattern for order events.
+//
+// Events are written to an outbox table in the same database transaction as
+// the order change, then delivered to the broker by a Relay. An order
+// can therefore never be committed without its event, and a broker outage
+// only delays delivery.
+package outbox
+
+import (
+	"context"
+	"time"
+)
+
+// Record is a single order event stored in the outbox.
+type Record struct {
+	ID        string // Unique event ID, used to dedupe relay retries
+	OrderID   string
+	EventType string
+	Payload   []byte // JSON-encoded messaging.OrderEvent
+	CreatedAt time.Time
+	Seq       int64 // Position in the outbox, assigned by the store; ignored by Enqueue
+}
+
+// Store persists outbox records.
+type Store interface {
+	// Enqueue inserts a record. Implementations must join the transaction
+	// carried by ctx, if any, so the record commits with the order write.
+	// Enqueueing a record whose ID already exists is a no-op.
+	Enqueue(ctx context.Context, rec Record) error
+
+	// FetchUnpublished returns up to limit unpublished records in the order
+	// they were enqueued.
+	FetchUnpublished(ctx context.Context, limit int) ([]Record, error)
+
+	// MarkPublished marks a record as delivered.
+	// Marking an already-delivered record is a no-op.
+	MarkPublished(ctx context.Context, id string) error
+}
+
+// HistoryReader is implemented by stores that keep delivered records.
+// Replays read past events through it.
+type HistoryReader interface {
+	// FetchCreatedBetween returns up to limit records, published or not,
+	// created in [from, to) and with Seq greater than afterSeq, in Seq order.
+	FetchCreatedBetween(ctx context.Context, from, to time.Time, afterSeq int64, limit int) ([]Record, error)
+}
+
+// BacklogCounter is implemented by stores that can count undelivered
+// records. A Relay over such a store reports the backlog after each batch.
+type BacklogCounter interface {
+	CountUnpublished(ctx context.Context) (int, error)
+}
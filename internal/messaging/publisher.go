@@ -0,0 +1,40 @@
+package messaging
+
+import (
+	"context"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+)
+
+// EventPublisher publishes order domain events to a message broker.
+type EventPublisher interface {
+	PublishOrderCreated(ctx context.Context, order *domain.Order) error
+	PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]ChangePair) error
+	PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error
+	PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error
+	PublishOrderDeleted(ctx context.Context, order *domain.Order) error
+	PublishOrderExpired(ctx context.Context, order *domain.Order) error
+	// PublishOrderSnapshot publishes the order's full current state, so
+	// consumers that start mid-stream can build their read model without
+	// replaying from the beginning. It is keyed by order ID like every
+	// other event, so a compacted topic keeps the latest per order.
+	PublishOrderSnapshot(ctx context.Context, order *domain.Order) error
+}
+
+// BatchPublisher publishes pre-built events in bulk, such as when
+// importing historical orders.
+type BatchPublisher interface {
+	PublishBatch(ctx context.Context, events []OrderEvent) error
+}
+
+// MetricsPublisher publishes order.metrics rollups. kafka.Publisher
+// implements it.
+type MetricsPublisher interface {
+	PublishOrderMetrics(ctx context.Context, evt OrderMetricsEvent) error
+}
+
+// Pinger is implemented by publishers that can check they reach their
+// broker without publishing, for readiness probes.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
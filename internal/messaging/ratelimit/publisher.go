@@ -0,0 +1,182 @@
+// Package ratelimit provides an EventPublisher decorator that caps how
+// fast events are published, so a runaway batch job cannot flood a shared
+// Kafka cluster and starve other producers.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"golang.org/x/time/rate"
+)
+
+// ErrRateLimited is returned, without calling the wrapped publisher, by a
+// publisher created with FailFast when no token is available.
+var ErrRateLimited = errors.New("publish rate limit exceeded")
+
+// Option configures a Publisher.
+type Option func(*Publisher)
+
+// FailFast makes publishes fail with ErrRateLimited when the limiter has
+// no token to spare, instead of waiting for one. A batch larger than the
+// limiter's burst always fails.
+func FailFast() Option {
+	return func(p *Publisher) { p.failFast = true }
+}
+
+var (
+	_ messaging.EventPublisher = (*Publisher)(nil)
+	_ messaging.BatchPublisher = (*Publisher)(nil)
+)
+
+// Publisher takes a token from its limiter before each publish, waiting
+// until one is available or the context ends. A batch takes a token per
+// event. The limiter may be shared with other publishers, to cap their
+// combined rate.
+type Publisher struct {
+	next     messaging.EventPublisher
+	limiter  *rate.Limiter
+	failFast bool
+}
+
+// Wrap returns inner limited to the rate of limiter.
+func Wrap(inner messaging.EventPublisher, limiter *rate.Limiter, opts ...Option) messaging.EventPublisher {
+	p := &Publisher{next: inner, limiter: limiter}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Middleware returns Wrap with limiter and opts as a messaging.Middleware.
+// The publishers it wraps share limiter.
+func Middleware(limiter *rate.Limiter, opts ...Option) messaging.Middleware {
+	return func(inner messaging.EventPublisher) messaging.EventPublisher { return Wrap(inner, limiter, opts...) }
+}
+
+// PublishOrderCreated publishes an order.created event once a token is
+// available.
+func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	if err := p.take(ctx, 1); err != nil {
+		return err
+	}
+	return p.next.PublishOrderCreated(ctx, order)
+}
+
+// PublishOrderUpdated publishes an order.updated event once a token is
+// available.
+func (p *Publisher) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	if err := p.take(ctx, 1); err != nil {
+		return err
+	}
+	return p.next.PublishOrderUpdated(ctx, order, changes)
+}
+
+// PublishOrderStatusChanged publishes an order.status_changed event once a
+// token is available.
+func (p *Publisher) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
+	if err := p.take(ctx, 1); err != nil {
+		return err
+	}
+	return p.next.PublishOrderStatusChanged(ctx, order, oldStatus, newStatus)
+}
+
+// PublishOrderCancelled publishes an order.cancelled event once a token is
+// available.
+func (p *Publisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	if err := p.take(ctx, 1); err != nil {
+		return err
+	}
+	return p.next.PublishOrderCancelled(ctx, order, reason)
+}
+
+// PublishOrderDeleted publishes an order.deleted event once a token is
+// available.
+func (p *Publisher) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	if err := p.take(ctx, 1); err != nil {
+		return err
+	}
+	return p.next.PublishOrderDeleted(ctx, order)
+}
+
+// PublishOrderExpired publishes an order.expired event once a token is
+// available.
+func (p *Publisher) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	if err := p.take(ctx, 1); err != nil {
+		return err
+	}
+	return p.next.PublishOrderExpired(ctx, order)
+}
+
+// PublishOrderSnapshot publishes an order.snapshot event once a token is
+// available.
+func (p *Publisher) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	if err := p.take(ctx, 1); err != nil {
+		return err
+	}
+	return p.next.PublishOrderSnapshot(ctx, order)
+}
+
+// Sender is implemented by publishers that publish pre-built events, such
+// as the Kafka publisher.
+type Sender interface {
+	Publish(ctx context.Context, evt messaging.OrderEvent) error
+}
+
+// Publish publishes evt, as the outbox relay does, once a token is
+// available. The wrapped publisher must be a Sender.
+func (p *Publisher) Publish(ctx context.Context, evt messaging.OrderEvent) error {
+	sender, ok := p.next.(Sender)
+	if !ok {
+		return fmt.Errorf("ratelimit publish %s: wrapped %T has no Publish method", evt.EventType, p.next)
+	}
+	if err := p.take(ctx, 1); err != nil {
+		return err
+	}
+	return sender.Publish(ctx, evt)
+}
+
+// PublishBatch publishes events once a token is available for each of
+// them. The wrapped publisher must be a messaging.BatchPublisher.
+func (p *Publisher) PublishBatch(ctx context.Context, events []messaging.OrderEvent) error {
+	batcher, ok := p.next.(messaging.BatchPublisher)
+	if !ok {
+		return fmt.Errorf("ratelimit publish batch: wrapped %T has no PublishBatch method", p.next)
+	}
+	if err := p.take(ctx, len(events)); err != nil {
+		return err
+	}
+	return batcher.PublishBatch(ctx, events)
+}
+
+// take takes n tokens from the limiter, waiting for them unless the
+// publisher fails fast. A wait for more than the burst is split into
+// waits of at most the burst, which the limiter refuses otherwise.
+func (p *Publisher) take(ctx context.Context, n int) error {
+	if p.failFast {
+		if !p.limiter.AllowN(time.Now(), n) {
+			return ErrRateLimited
+		}
+		return nil
+	}
+	for n > 0 {
+		chunk := n
+		if burst := p.limiter.Burst(); burst > 0 && chunk > burst {
+			chunk = burst
+		}
+		if err := p.limiter.WaitN(ctx, chunk); err != nil {
+			// The limiter fails early, without ctx.Err, if the token would
+			// come after the deadline
+			if _, ok := ctx.Deadline(); ok && ctx.Err() == nil {
+				err = fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+			}
+			return fmt.Errorf("ratelimit wait: %w", err)
+		}
+		n -= chunk
+	}
+	return nil
+}
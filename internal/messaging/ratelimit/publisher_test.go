@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func newOrder() *domain.Order {
+	return &domain.Order{ID: uuid.New(), CustomerID: "cust-1", Status: domain.OrderStatusPending, Version: 1, Total: domain.Money{Amount: 1000, Currency: "USD"}}
+}
+
+func newBatch(n int) []messaging.OrderEvent {
+	events := make([]messaging.OrderEvent, n)
+	for i := range events {
+		events[i] = messaging.NewOrderEvent(messaging.EventOrderCreated, newOrder())
+	}
+	return events
+}
+
+func TestPublisher_ThroughputCappedToRate(t *testing.T) {
+	rec := memory.New()
+	// 50 per second with no burst: one event every 20ms
+	pub := Wrap(rec, rate.NewLimiter(50, 1))
+
+	start := time.Now()
+	for range 11 {
+		require.NoError(t, pub.PublishOrderCreated(context.Background(), newOrder()))
+	}
+	elapsed := time.Since(start)
+
+	assert.Len(t, rec.Events(), 11)
+	// The first event takes the initial token; the other 10 wait 20ms each
+	assert.GreaterOrEqual(t, elapsed, 180*time.Millisecond)
+}
+
+func TestPublisher_Batch_TakesATokenPerEvent(t *testing.T) {
+	rec := memory.New()
+	limiter := rate.NewLimiter(100, 5)
+	pub := Wrap(rec, limiter).(messaging.BatchPublisher)
+
+	start := time.Now()
+	require.NoError(t, pub.PublishBatch(context.Background(), newBatch(5)))
+	assert.Less(t, time.Since(start), 40*time.Millisecond, "the burst covers the first batch")
+
+	require.NoError(t, pub.PublishBatch(context.Background(), newBatch(5)))
+	assert.GreaterOrEqual(t, time.Since(start), 45*time.Millisecond, "the second waits for 5 new tokens")
+	assert.Len(t, rec.Events(), 10)
+}
+
+func TestPublisher_BatchLargerThanBurst_WaitsInSteps(t *testing.T) {
+	rec := memory.New()
+	pub := Wrap(rec, rate.NewLimiter(200, 2)).(messaging.BatchPublisher)
+
+	require.NoError(t, pub.PublishBatch(context.Background(), newBatch(7)))
+
+	assert.Len(t, rec.Events(), 7)
+}
+
+func TestPublisher_CancelUnblocksWaitingCall(t *testing.T) {
+	rec := memory.New()
+	limiter := rate.NewLimiter(rate.Every(time.Minute), 1)
+	pub := Wrap(rec, limiter)
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newOrder()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pub.PublishOrderUpdated(ctx, newOrder(), nil) }()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("publish still waiting after its context was cancelled")
+	}
+	assert.Len(t, rec.Events(), 1, "the waiting event was not published")
+}
+
+func TestPublisher_TokenAfterDeadline_FailsWithDeadlineExceeded(t *testing.T) {
+	pub := Wrap(memory.New(), rate.NewLimiter(rate.Every(time.Minute), 1))
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newOrder()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	err := pub.PublishOrderCreated(ctx, newOrder())
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "fails without waiting out the deadline")
+}
+
+func TestPublisher_FailFast_ReturnsErrRateLimited(t *testing.T) {
+	rec := memory.New()
+	pub := Wrap(rec, rate.NewLimiter(rate.Every(time.Minute), 3), FailFast())
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), newOrder()))
+	require.NoError(t, pub.(messaging.BatchPublisher).PublishBatch(context.Background(), newBatch(2)))
+	err := pub.PublishOrderDeleted(context.Background(), newOrder())
+	assert.ErrorIs(t, err, ErrRateLimited)
+	err = pub.(messaging.BatchPublisher).PublishBatch(context.Background(), newBatch(4))
+	assert.ErrorIs(t, err, ErrRateLimited, "larger than the burst")
+
+	assert.Len(t, rec.Events(), 3)
+}
+
+func TestPublisher_InnerWithoutBatch_ReturnsError(t *testing.T) {
+	pub := Wrap(struct{ messaging.EventPublisher }{memory.New()}, rate.NewLimiter(rate.Inf, 0))
+
+	err := pub.(messaging.BatchPublisher).PublishBatch(context.Background(), newBatch(1))
+
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrRateLimited))
+}
@@ -0,0 +1,241 @@
+// Package replay re-emits past order events for a time range, so a
+// consumer can rebuild its read model after a bug.
+//
+// Events are read from the outbox history when it has any for the range.
+// Otherwise they are reconstructed from the current state of the orders
+// changed in the range, one event per order: an order.snapshot, or an
+// order.deleted for an order that is soft-deleted. Every replayed event has
+// Replayed set, and replays are rate limited so they do not flood the
+// broker.
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/outbox"
+)
+
+// Destination receives replayed events. kafka.Publisher, webhook.Publisher
+// and memory.Publisher satisfy it.
+type Destination interface {
+	Publish(ctx context.Context, evt messaging.OrderEvent) error
+}
+
+// OrderLister lists orders for reconstructing events when there is no
+// outbox history.
+type OrderLister interface {
+	// ListChangedBetween returns up to limit orders, soft-deleted ones
+	// included, last updated in [from, to), ordered by UpdatedAt then ID.
+	// If after is not nil, only orders sorting after it are returned.
+	ListChangedBetween(ctx context.Context, from, to time.Time, after *domain.Order, limit int) ([]*domain.Order, error)
+}
+
+// ErrNoSource is returned by Replay when the Replayer has neither an
+// outbox history nor an order lister.
+var ErrNoSource = errors.New("replay: no event source configured")
+
+// ErrInvalidRange is returned by Replay when from is not before to.
+var ErrInvalidRange = errors.New("replay: from must be before to")
+
+// Defaults for a Replayer created by New.
+const (
+	defaultRate      = 100 // Events per second
+	defaultBatchSize = 500
+)
+
+// Replayer re-emits the events of a time range. It is safe for concurrent
+// use, though each Replay call is rate limited separately.
+type Replayer struct {
+	history   outbox.HistoryReader
+	orders    OrderLister
+	rate      float64
+	batchSize int
+	logger    *slog.Logger
+}
+
+// Option configures a Replayer created by New.
+type Option func(*Replayer)
+
+// WithRate caps replays at perSecond events per second. Zero or less
+// removes the cap. Defaults to 100.
+func WithRate(perSecond float64) Option {
+	return func(r *Replayer) { r.rate = perSecond }
+}
+
+// WithBatchSize sets how many records or orders are read per query.
+// Defaults to 500.
+func WithBatchSize(n int) Option {
+	return func(r *Replayer) {
+		if n > 0 {
+			r.batchSize = n
+		}
+	}
+}
+
+// WithLogger logs a summary of every replay to logger. Defaults to logging
+// nothing.
+func WithLogger(logger *slog.Logger) Option {
+	return func(r *Replayer) { r.logger = logger }
+}
+
+// New returns a Replayer reading from history, falling back to orders when
+// history has no events for the range. Either may be nil.
+func New(history outbox.HistoryReader, orders OrderLister, opts ...Option) *Replayer {
+	r := &Replayer{
+		history:   history,
+		orders:    orders,
+		rate:      defaultRate,
+		batchSize: defaultBatchSize,
+		logger:    slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Replay publishes to dest every event that occurred in [from, to), oldest
+// first, each with Replayed set. Stored events keep their event ID, so
+// consumers can recognise the ones they already processed.
+//
+// Replay stops at the first event dest fails to publish, returning an
+// error naming it; events before it have been published.
+func (r *Replayer) Replay(ctx context.Context, from, to time.Time, dest Destination) error {
+	if !from.Before(to) {
+		return ErrInvalidRange
+	}
+	if r.history == nil && r.orders == nil {
+		return ErrNoSource
+	}
+
+	limit := newLimiter(r.rate)
+	defer limit.stop()
+	publish := func(evt messaging.OrderEvent) error {
+		if err := limit.wait(ctx); err != nil {
+			return err
+		}
+		evt.Replayed = true
+		if err := dest.Publish(ctx, evt); err != nil {
+			return fmt.Errorf("replay %s %s: %w", evt.EventType, evt.EventID, err)
+		}
+		return nil
+	}
+
+	if r.history != nil {
+		n, err := r.replayHistory(ctx, from, to, publish)
+		if err != nil {
+			return err
+		}
+		if n > 0 || r.orders == nil {
+			r.logger.Info("replayed outbox events", slog.Int("count", n),
+				slog.Time("from", from), slog.Time("to", to))
+			return nil
+		}
+	}
+	n, err := r.replayOrders(ctx, from, to, publish)
+	if err != nil {
+		return err
+	}
+	r.logger.Info("replayed order snapshots", slog.Int("count", n),
+		slog.Time("from", from), slog.Time("to", to))
+	return nil
+}
+
+// replayHistory publishes the outbox records created in [from, to) and
+// returns how many it published.
+func (r *Replayer) replayHistory(ctx context.Context, from, to time.Time, publish func(messaging.OrderEvent) error) (int, error) {
+	n := 0
+	var afterSeq int64
+	for {
+		records, err := r.history.FetchCreatedBetween(ctx, from, to, afterSeq, r.batchSize)
+		if err != nil {
+			return n, fmt.Errorf("replay: fetch outbox history: %w", err)
+		}
+		for _, rec := range records {
+			evt, err := messaging.Unmarshal(messaging.JSONSerializer{}, rec.Payload)
+			if err != nil {
+				return n, fmt.Errorf("replay: decode outbox record %s: %w", rec.ID, err)
+			}
+			if err := publish(evt); err != nil {
+				return n, err
+			}
+			n++
+			afterSeq = rec.Seq
+		}
+		if len(records) < r.batchSize {
+			return n, nil
+		}
+	}
+}
+
+// replayOrders publishes a current-state event for every order changed in
+// [from, to) and returns how many it published.
+func (r *Replayer) replayOrders(ctx context.Context, from, to time.Time, publish func(messaging.OrderEvent) error) (int, error) {
+	n := 0
+	var after *domain.Order
+	for {
+		orders, err := r.orders.ListChangedBetween(ctx, from, to, after, r.batchSize)
+		if err != nil {
+			return n, fmt.Errorf("replay: list orders: %w", err)
+		}
+		for _, order := range orders {
+			if err := publish(currentState(order)); err != nil {
+				return n, err
+			}
+			n++
+			after = order
+		}
+		if len(orders) < r.batchSize {
+			return n, nil
+		}
+	}
+}
+
+// currentState returns the event reconstructing order: its snapshot, or,
+// since a snapshot cannot say an order is gone, its deletion.
+func currentState(order *domain.Order) messaging.OrderEvent {
+	if order.DeletedAt == nil {
+		return messaging.NewSnapshotEvent(order)
+	}
+	evt := messaging.NewOrderEvent(messaging.EventOrderDeleted, order)
+	evt.OccurredAt = *order.DeletedAt
+	return evt
+}
+
+// limiter spaces out events to a maximum rate.
+type limiter struct {
+	ticker *time.Ticker // Nil when unlimited
+}
+
+func newLimiter(perSecond float64) *limiter {
+	if perSecond <= 0 {
+		return &limiter{}
+	}
+	interval := max(time.Duration(float64(time.Second)/perSecond), time.Nanosecond)
+	return &limiter{ticker: time.NewTicker(interval)}
+}
+
+// wait blocks until the next event may be sent or ctx is done.
+func (l *limiter) wait(ctx context.Context) error {
+	if l.ticker == nil {
+		return ctx.Err()
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-l.ticker.C:
+		return nil
+	}
+}
+
+func (l *limiter) stop() {
+	if l.ticker != nil {
+		l.ticker.Stop()
+	}
+}
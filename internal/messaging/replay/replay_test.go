@@ -0,0 +1,207 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/memory"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/outbox"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	day   = time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	from  = day
+	to    = day.Add(24 * time.Hour)
+	noCap = WithRate(0)
+)
+
+// historyStore is an outbox.HistoryReader over records in Seq order.
+type historyStore struct {
+	records []outbox.Record
+	calls   int
+}
+
+func (s *historyStore) FetchCreatedBetween(_ context.Context, from, to time.Time, afterSeq int64, limit int) ([]outbox.Record, error) {
+	s.calls++
+	var page []outbox.Record
+	for _, rec := range s.records {
+		if rec.Seq > afterSeq && !rec.CreatedAt.Before(from) && rec.CreatedAt.Before(to) && len(page) < limit {
+			page = append(page, rec)
+		}
+	}
+	return page, nil
+}
+
+// orderStore is an OrderLister over orders sorted by UpdatedAt.
+type orderStore struct {
+	orders []*domain.Order
+}
+
+func (s *orderStore) ListChangedBetween(_ context.Context, from, to time.Time, after *domain.Order, limit int) ([]*domain.Order, error) {
+	var page []*domain.Order
+	past := after == nil
+	for _, order := range s.orders {
+		if !past {
+			past = order == after
+			continue
+		}
+		if !order.UpdatedAt.Before(from) && order.UpdatedAt.Before(to) && len(page) < limit {
+			page = append(page, order)
+		}
+	}
+	return page, nil
+}
+
+// failingDestination fails on the nth publish.
+type failingDestination struct {
+	n, calls int
+	err      error
+}
+
+func (d *failingDestination) Publish(context.Context, messaging.OrderEvent) error {
+	d.calls++
+	if d.calls == d.n {
+		return d.err
+	}
+	return nil
+}
+
+func newTestOrder(updatedAt time.Time) *domain.Order {
+	return &domain.Order{
+		ID:         uuid.New(),
+		CustomerID: "cust-123",
+		Status:     domain.OrderStatusConfirmed,
+		Total:      domain.Money{Amount: 2100, Currency: "USD"},
+		Version:    2,
+		CreatedAt:  updatedAt.Add(-time.Hour),
+		UpdatedAt:  updatedAt,
+	}
+}
+
+// newRecord stores an event for a new order, created at createdAt.
+func newRecord(t *testing.T, seq int64, eventType string, createdAt time.Time) outbox.Record {
+	t.Helper()
+	evt := messaging.NewOrderEvent(eventType, newTestOrder(createdAt))
+	evt.OccurredAt = createdAt
+	payload, err := json.Marshal(evt)
+	require.NoError(t, err)
+	return outbox.Record{ID: evt.EventID, OrderID: evt.OrderID, EventType: eventType, Payload: payload, CreatedAt: createdAt, Seq: seq}
+}
+
+func TestReplayer_History_RepublishesEventsInRangeFlagged(t *testing.T) {
+	history := &historyStore{records: []outbox.Record{
+		newRecord(t, 1, messaging.EventOrderCreated, from.Add(-time.Minute)), // Before the range
+		newRecord(t, 2, messaging.EventOrderCreated, from),
+		newRecord(t, 3, messaging.EventOrderUpdated, from.Add(time.Hour)),
+		newRecord(t, 4, messaging.EventOrderDeleted, from.Add(2*time.Hour)),
+		newRecord(t, 5, messaging.EventOrderCreated, to), // End is exclusive
+	}}
+	dest := memory.New()
+	r := New(history, &orderStore{}, noCap, WithBatchSize(2))
+
+	require.NoError(t, r.Replay(context.Background(), from, to, dest))
+
+	events := dest.Events()
+	require.Len(t, events, 3)
+	for i, evt := range events {
+		assert.True(t, evt.Replayed)
+		assert.Equal(t, history.records[i+1].ID, evt.EventID, "stored events keep their ID")
+	}
+	assert.Equal(t, []string{messaging.EventOrderCreated, messaging.EventOrderUpdated, messaging.EventOrderDeleted}, dest.Types())
+	assert.Equal(t, 2, history.calls, "pages until a short page")
+}
+
+func TestReplayer_NoHistoryInRange_ReconstructsFromOrders(t *testing.T) {
+	live := newTestOrder(from.Add(time.Hour))
+	deleted := newTestOrder(from.Add(2 * time.Hour))
+	deletedAt := deleted.UpdatedAt
+	deleted.DeletedAt = &deletedAt
+	orders := &orderStore{orders: []*domain.Order{
+		newTestOrder(from.Add(-time.Hour)), // Before the range
+		live,
+		deleted,
+	}}
+	history := &historyStore{records: []outbox.Record{newRecord(t, 1, messaging.EventOrderCreated, from.Add(-time.Hour))}}
+	dest := memory.New()
+	r := New(history, orders, noCap, WithBatchSize(1))
+
+	require.NoError(t, r.Replay(context.Background(), from, to, dest))
+
+	events := dest.Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, messaging.EventOrderSnapshot, events[0].EventType)
+	assert.Equal(t, live.ID.String(), events[0].OrderID)
+	assert.Equal(t, live.UpdatedAt, events[0].OccurredAt)
+	assert.Equal(t, messaging.EventOrderDeleted, events[1].EventType)
+	assert.Equal(t, deleted.ID.String(), events[1].OrderID)
+	assert.Equal(t, deletedAt, events[1].OccurredAt)
+	for _, evt := range events {
+		assert.True(t, evt.Replayed)
+		assert.NoError(t, evt.Validate())
+	}
+}
+
+func TestReplayer_NoHistoryReader_UsesOrders(t *testing.T) {
+	dest := memory.New()
+	r := New(nil, &orderStore{orders: []*domain.Order{newTestOrder(from)}}, noCap)
+
+	require.NoError(t, r.Replay(context.Background(), from, to, dest))
+
+	assert.Len(t, dest.Events(), 1)
+}
+
+func TestReplayer_PublishFails_StopsAndNamesEvent(t *testing.T) {
+	history := &historyStore{records: []outbox.Record{
+		newRecord(t, 1, messaging.EventOrderCreated, from),
+		newRecord(t, 2, messaging.EventOrderUpdated, from),
+		newRecord(t, 3, messaging.EventOrderUpdated, from),
+	}}
+	brokerErr := errors.New("broker unavailable")
+	dest := &failingDestination{n: 2, err: brokerErr}
+	r := New(history, nil, noCap)
+
+	err := r.Replay(context.Background(), from, to, dest)
+
+	require.ErrorIs(t, err, brokerErr)
+	assert.Contains(t, err.Error(), history.records[1].ID)
+	assert.Equal(t, 2, dest.calls)
+}
+
+func TestReplayer_InvalidArguments(t *testing.T) {
+	assert.ErrorIs(t, New(nil, nil).Replay(context.Background(), from, to, memory.New()), ErrNoSource)
+	assert.ErrorIs(t, New(nil, &orderStore{}).Replay(context.Background(), to, from, memory.New()), ErrInvalidRange)
+	assert.ErrorIs(t, New(nil, &orderStore{}).Replay(context.Background(), from, from, memory.New()), ErrInvalidRange)
+}
+
+func TestReplayer_WithRate_CapsPublishRate(t *testing.T) {
+	var orders []*domain.Order
+	for i := range 5 {
+		orders = append(orders, newTestOrder(from.Add(time.Duration(i)*time.Minute)))
+	}
+	dest := memory.New()
+	r := New(nil, &orderStore{orders: orders}, WithRate(100)) // One event per 10ms
+
+	start := time.Now()
+	require.NoError(t, r.Replay(context.Background(), from, to, dest))
+
+	assert.Len(t, dest.Events(), 5)
+	assert.GreaterOrEqual(t, time.Since(start), 45*time.Millisecond)
+}
+
+func TestReplayer_ContextCancelled_StopsWaiting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := New(nil, &orderStore{orders: []*domain.Order{newTestOrder(from)}}, WithRate(0.001))
+
+	err := r.Replay(ctx, from, to, memory.New())
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,168 @@
+// Package retry provides an EventPublisher decorator that retries failed
+// publishes with exponential backoff.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+// Policy configures how publishes are retried.
+type Policy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int
+	// BaseDelay is the backoff before the second attempt. It doubles on
+	// each further attempt, capped at MaxDelay.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff and must be set for delays to grow.
+	MaxDelay time.Duration
+	// Retryable reports whether err is worth retrying. Nil retries all errors
+	// except a *messaging.ValidationError, which never succeeds on retry.
+	Retryable func(err error) bool
+}
+
+// DefaultPolicy returns a policy of 3 attempts starting at 100ms, capped at 2s.
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts: 3,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    2 * time.Second,
+	}
+}
+
+var _ messaging.EventPublisher = (*Publisher)(nil)
+
+// Publisher retries each call to the wrapped EventPublisher per its Policy.
+type Publisher struct {
+	next   messaging.EventPublisher
+	policy Policy
+}
+
+// Wrap returns p decorated with retries according to policy.
+func Wrap(p messaging.EventPublisher, policy Policy) messaging.EventPublisher {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = 1
+	}
+	return &Publisher{next: p, policy: policy}
+}
+
+// Middleware returns Wrap with policy as a messaging.Middleware.
+func Middleware(policy Policy) messaging.Middleware {
+	return func(p messaging.EventPublisher) messaging.EventPublisher { return Wrap(p, policy) }
+}
+
+// PublishOrderCreated publishes an order.created event, retrying on failure.
+func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	return p.do(ctx, func(ctx context.Context) error {
+		return p.next.PublishOrderCreated(ctx, order)
+	})
+}
+
+// PublishOrderUpdated publishes an order.updated event, retrying on failure.
+func (p *Publisher) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	return p.do(ctx, func(ctx context.Context) error {
+		return p.next.PublishOrderUpdated(ctx, order, changes)
+	})
+}
+
+// PublishOrderStatusChanged publishes an order.status_changed event,
+// retrying on failure.
+func (p *Publisher) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
+	return p.do(ctx, func(ctx context.Context) error {
+		return p.next.PublishOrderStatusChanged(ctx, order, oldStatus, newStatus)
+	})
+}
+
+// PublishOrderCancelled publishes an order.cancelled event, retrying on
+// failure.
+func (p *Publisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	return p.do(ctx, func(ctx context.Context) error {
+		return p.next.PublishOrderCancelled(ctx, order, reason)
+	})
+}
+
+// PublishOrderDeleted publishes an order.deleted event, retrying on failure.
+func (p *Publisher) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	return p.do(ctx, func(ctx context.Context) error {
+		return p.next.PublishOrderDeleted(ctx, order)
+	})
+}
+
+// PublishOrderExpired publishes an order.expired event, retrying on failure.
+func (p *Publisher) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	return p.do(ctx, func(ctx context.Context) error {
+		return p.next.PublishOrderExpired(ctx, order)
+	})
+}
+
+// PublishOrderSnapshot publishes an order.snapshot event, retrying on failure.
+func (p *Publisher) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	return p.do(ctx, func(ctx context.Context) error {
+		return p.next.PublishOrderSnapshot(ctx, order)
+	})
+}
+
+// do retries publish, pinning the event ID so every attempt emits the same
+// event and consumers can dedupe an attempt that failed after delivery.
+func (p *Publisher) do(ctx context.Context, publish func(context.Context) error) error {
+	if _, ok := messaging.EventID(ctx); !ok {
+		ctx = messaging.WithEventID(ctx, uuid.NewString())
+	}
+	return Do(ctx, p.policy, publish)
+}
+
+// Do calls fn until it succeeds, policy.MaxAttempts is reached, or ctx is
+// done, sleeping with exponential backoff between attempts. The returned
+// error wraps the last error from fn, or ctx.Err() if ctx ended first.
+func Do(ctx context.Context, policy Policy, fn func(context.Context) error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if attempt >= policy.MaxAttempts || !policy.retryable(err) {
+			return fmt.Errorf("publish failed after %d attempt(s): %w", attempt, err)
+		}
+
+		timer := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("publish aborted after %d attempt(s): %w (last error: %v)", attempt, ctx.Err(), err)
+		case <-timer.C:
+		}
+	}
+}
+
+func (p Policy) retryable(err error) bool {
+	var verr *messaging.ValidationError
+	if errors.As(err, &verr) {
+		return false
+	}
+	return p.Retryable == nil || p.Retryable(err)
+}
+
+// backoff returns the delay after the given attempt: BaseDelay doubled per
+// attempt and capped at MaxDelay, with the upper half randomized so
+// concurrent retries spread out.
+func (p Policy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half+1) // #nosec G404 -- jitter does not need a CSPRNG
+}
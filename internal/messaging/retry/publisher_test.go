@@ -0,0 +1,183 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/memory"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errBroker = errors.New("broker unavailable")
+
+func testPolicy(attempts int) Policy {
+	return Policy{MaxAttempts: attempts, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+}
+
+// failingPublisher returns a mock whose methods fail the first n calls.
+func failingPublisher(n int, calls *int) *mocks.EventPublisherMock {
+	fail := func() error {
+		*calls++
+		if *calls <= n {
+			return errBroker
+		}
+		return nil
+	}
+	return &mocks.EventPublisherMock{
+		PublishOrderCreatedFunc: func(_ context.Context, _ *domain.Order) error { return fail() },
+		PublishOrderUpdatedFunc: func(_ context.Context, _ *domain.Order, _ map[string]messaging.ChangePair) error { return fail() },
+		PublishOrderStatusChangedFunc: func(_ context.Context, _ *domain.Order, _, _ domain.OrderStatus) error {
+			return fail()
+		},
+	}
+}
+
+func TestWrap_FailsNTimesThenSucceeds_Retries(t *testing.T) {
+	tests := []struct {
+		name    string
+		publish func(messaging.EventPublisher) error
+	}{
+		{"created", func(p messaging.EventPublisher) error {
+			return p.PublishOrderCreated(context.Background(), &domain.Order{})
+		}},
+		{"updated", func(p messaging.EventPublisher) error {
+			return p.PublishOrderUpdated(context.Background(), &domain.Order{}, nil)
+		}},
+		{"status_changed", func(p messaging.EventPublisher) error {
+			return p.PublishOrderStatusChanged(context.Background(), &domain.Order{},
+				domain.OrderStatusPending, domain.OrderStatusConfirmed)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			pub := Wrap(failingPublisher(2, &calls), testPolicy(5))
+
+			err := tt.publish(pub)
+
+			require.NoError(t, err)
+			assert.Equal(t, 3, calls)
+		})
+	}
+}
+
+func TestMiddleware_InChain_Retries(t *testing.T) {
+	calls := 0
+	pub := messaging.Chain(failingPublisher(2, &calls), Middleware(testPolicy(3)))
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), &domain.Order{}))
+	assert.Equal(t, 3, calls)
+}
+
+func TestWrap_RetriedPublish_ReusesEventID(t *testing.T) {
+	rec := memory.New()
+	attempts := 0
+	// Every attempt reaches the broker, but the first reports a failure
+	next := &mocks.EventPublisherMock{
+		PublishOrderCreatedFunc: func(ctx context.Context, order *domain.Order) error {
+			attempts++
+			require.NoError(t, rec.PublishOrderCreated(ctx, order))
+			if attempts == 1 {
+				return errBroker
+			}
+			return nil
+		},
+	}
+	pub := Wrap(next, testPolicy(3))
+	order := &domain.Order{ID: uuid.New(), CustomerID: "cust-1", Version: 1}
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+
+	events := rec.Events()
+	require.Len(t, events, 3)
+	assert.Equal(t, events[0].EventID, events[1].EventID, "attempts of one publish are the same event")
+	assert.NotEqual(t, events[1].EventID, events[2].EventID, "separate publishes are separate events")
+}
+
+func TestWrap_AttemptsExhausted_ReturnsLastErrorWithCount(t *testing.T) {
+	calls := 0
+	pub := Wrap(failingPublisher(10, &calls), testPolicy(3))
+
+	err := pub.PublishOrderCreated(context.Background(), &domain.Order{})
+
+	assert.ErrorIs(t, err, errBroker)
+	assert.Contains(t, err.Error(), "after 3 attempt(s)")
+	assert.Equal(t, 3, calls)
+}
+
+func TestWrap_NonRetryableError_StopsImmediately(t *testing.T) {
+	calls := 0
+	policy := testPolicy(5)
+	policy.Retryable = func(err error) bool { return !errors.Is(err, errBroker) }
+	pub := Wrap(failingPublisher(10, &calls), policy)
+
+	err := pub.PublishOrderCreated(context.Background(), &domain.Order{})
+
+	assert.ErrorIs(t, err, errBroker)
+	assert.Equal(t, 1, calls)
+}
+
+func TestWrap_ValidationError_NotRetried(t *testing.T) {
+	calls := 0
+	pub := Wrap(&mocks.EventPublisherMock{
+		PublishOrderCreatedFunc: func(_ context.Context, _ *domain.Order) error {
+			calls++
+			return &messaging.ValidationError{Field: "customer_id", Reason: "is required"}
+		},
+	}, testPolicy(5))
+
+	err := pub.PublishOrderCreated(context.Background(), &domain.Order{})
+
+	var verr *messaging.ValidationError
+	assert.ErrorAs(t, err, &verr)
+	assert.Equal(t, 1, calls)
+}
+
+func TestWrap_ContextCancelled_AbortsEarly(t *testing.T) {
+	calls := 0
+	policy := Policy{MaxAttempts: 10, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	pub := Wrap(failingPublisher(10, &calls), policy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := pub.PublishOrderCreated(ctx, &domain.Order{})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), errBroker.Error())
+	assert.Equal(t, 1, calls)
+	assert.Less(t, time.Since(start), time.Second, "should not wait out the backoff")
+}
+
+func TestBackoff_GrowsAndCaps(t *testing.T) {
+	p := &Publisher{policy: Policy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}}
+
+	tests := []struct {
+		attempt  int
+		min, max time.Duration
+	}{
+		{1, 5 * time.Millisecond, 10 * time.Millisecond},
+		{2, 10 * time.Millisecond, 20 * time.Millisecond},
+		{3, 20 * time.Millisecond, 40 * time.Millisecond},
+		{4, 25 * time.Millisecond, 50 * time.Millisecond},
+		{40, 25 * time.Millisecond, 50 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			d := p.policy.backoff(tt.attempt)
+			assert.GreaterOrEqual(t, d, tt.min, "attempt %d", tt.attempt)
+			assert.LessOrEqual(t, d, tt.max, "attempt %d", tt.attempt)
+		}
+	}
+}
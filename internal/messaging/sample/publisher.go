@@ -0,0 +1,69 @@
+// Package sample provides an EventPublisher decorator that forwards only a
+// random fraction of each event type, to cut the volume reaching consumers
+// that can do with a sample, such as analytics.
+package sample
+
+import (
+	"math/rand/v2"
+	"sync"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/filter"
+)
+
+// Option configures the sampling of a Publisher created by Wrap.
+type Option func(*sampler)
+
+// WithSeed draws the keep or drop decisions from a generator seeded with
+// seed, so the same sequence of events is always sampled the same way.
+// By default the generator is randomly seeded.
+func WithSeed(seed uint64) Option {
+	return func(s *sampler) { s.rng = rand.New(rand.NewPCG(seed, seed)) }
+}
+
+// Wrap returns inner decorated to keep each event with the probability
+// rates gives for its type: 0.1 keeps about one order.updated in ten, 1
+// keeps them all, and 0 none. Types missing from rates are always kept;
+// rates outside [0, 1] are clamped.
+//
+// Dropped events are not an error: the Publish* call returns nil without
+// reaching inner, as with filter.Wrap.
+func Wrap(inner messaging.EventPublisher, rates map[string]float64, opts ...Option) *filter.Publisher {
+	s := &sampler{rates: make(map[string]float64, len(rates))}
+	for eventType, rate := range rates {
+		s.rates[eventType] = min(max(rate, 0), 1)
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.rng == nil {
+		s.rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	return filter.Wrap(inner, s.keep)
+}
+
+// Middleware returns Wrap with rates and opts as a messaging.Middleware.
+func Middleware(rates map[string]float64, opts ...Option) messaging.Middleware {
+	return func(inner messaging.EventPublisher) messaging.EventPublisher { return Wrap(inner, rates, opts...) }
+}
+
+// sampler decides which events to keep. *rand.Rand is not safe for
+// concurrent use, so draws are serialized.
+type sampler struct {
+	rates map[string]float64
+	mu    sync.Mutex
+	rng   *rand.Rand
+}
+
+func (s *sampler) keep(evt messaging.OrderEvent) bool {
+	rate, ok := s.rates[evt.EventType]
+	switch {
+	case !ok || rate >= 1:
+		return true
+	case rate <= 0:
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Float64() < rate
+}
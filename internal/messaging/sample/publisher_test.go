@@ -0,0 +1,105 @@
+package sample
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOrder() *domain.Order {
+	return &domain.Order{
+		ID:         uuid.New(),
+		CustomerID: "cust-123",
+		Status:     domain.OrderStatusPending,
+		Total:      domain.Money{Amount: 2100, Currency: "USD"},
+		Version:    1,
+	}
+}
+
+// publishEach publishes n order.created and n order.updated events.
+func publishEach(t *testing.T, pub messaging.EventPublisher, n int) {
+	t.Helper()
+	ctx := context.Background()
+	for range n {
+		order := newTestOrder()
+		require.NoError(t, pub.PublishOrderCreated(ctx, order))
+		require.NoError(t, pub.PublishOrderUpdated(ctx, order, map[string]messaging.ChangePair{"items": {}}), "dropping is not an error")
+	}
+}
+
+func TestWrap_FixedSeed_KeepsConfiguredFraction(t *testing.T) {
+	const n = 10000
+	tests := []struct {
+		name string
+		rate float64
+	}{
+		{"ten_percent", 0.1},
+		{"half", 0.5},
+		{"ninety_percent", 0.9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := memory.New()
+			pub := Wrap(rec, map[string]float64{messaging.EventOrderUpdated: tt.rate, messaging.EventOrderCreated: 1}, WithSeed(42))
+
+			publishEach(t, pub, n)
+
+			assert.Len(t, rec.EventsOfType(messaging.EventOrderCreated), n, "created is always kept")
+			kept := len(rec.EventsOfType(messaging.EventOrderUpdated))
+			assert.InDelta(t, tt.rate*n, kept, 0.02*n, "kept %d of %d", kept, n)
+		})
+	}
+}
+
+func TestWrap_SameSeed_SamplesSameEvents(t *testing.T) {
+	orders := make([]*domain.Order, 200)
+	for i := range orders {
+		orders[i] = newTestOrder()
+	}
+	sampled := func() []string {
+		rec := memory.New()
+		pub := Wrap(rec, map[string]float64{messaging.EventOrderUpdated: 0.3}, WithSeed(7))
+		for _, order := range orders {
+			require.NoError(t, pub.PublishOrderUpdated(context.Background(), order, nil))
+		}
+		var ids []string
+		for _, evt := range rec.Events() {
+			ids = append(ids, evt.OrderID)
+		}
+		return ids
+	}
+
+	first := sampled()
+	assert.NotEmpty(t, first)
+	assert.Equal(t, first, sampled())
+}
+
+func TestWrap_UnlistedAndBoundaryRates(t *testing.T) {
+	rec := memory.New()
+	pub := Wrap(rec, map[string]float64{
+		messaging.EventOrderUpdated:   0,
+		messaging.EventOrderCancelled: -1,
+		messaging.EventOrderDeleted:   2,
+	})
+	ctx := context.Background()
+	order := newTestOrder()
+
+	for range 50 {
+		require.NoError(t, pub.PublishOrderUpdated(ctx, order, nil))
+		require.NoError(t, pub.PublishOrderCancelled(ctx, order, "out of stock"))
+		require.NoError(t, pub.PublishOrderDeleted(ctx, order))
+		require.NoError(t, pub.PublishOrderStatusChanged(ctx, order, domain.OrderStatusPending, domain.OrderStatusConfirmed))
+	}
+
+	assert.Empty(t, rec.EventsOfType(messaging.EventOrderUpdated), "0 drops every event")
+	assert.Empty(t, rec.EventsOfType(messaging.EventOrderCancelled), "below 0 is clamped to 0")
+	assert.Len(t, rec.EventsOfType(messaging.EventOrderDeleted), 50, "above 1 is clamped to 1")
+	assert.Len(t, rec.EventsOfType(messaging.EventOrderStatusChanged), 50, "unlisted types are kept")
+}
@@ -0,0 +1,252 @@
+package messaging
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+)
+
+// CurrentSchemaVersion is the OrderEvent envelope version this package
+// publishes, carried in OrderEvent.SchemaVersion and the Kafka
+// schema-version header. Bump it, and add an upgrade from the previous
+// version, whenever the envelope changes in a way consumers must know
+// about.
+//
+// Version 1 is the baseline: the envelope as first published, plus the
+// optional fields added since with zero defaults (cancel_reason,
+// replayed). Events without a schema version predate the field and are
+// version 1. Version 2 adds currency. Version 3 adds the exact amounts
+// total_minor and the line items' unit_price_minor and subtotal_minor.
+// Version 4 adds correlation_id. Version 5 adds changed_fields. Version 6
+// adds seq. Version 7 adds metadata. Version 8 adds causation_id. Version
+// 9 adds changes.
+const CurrentSchemaVersion = 9
+
+// ErrUnsupportedSchemaVersion is wrapped by a *SchemaVersionError.
+var ErrUnsupportedSchemaVersion = errors.New("unsupported event schema version")
+
+// SchemaVersionError reports an event whose envelope version this package
+// cannot read, typically one published by a newer release.
+type SchemaVersionError struct {
+	Version int
+}
+
+func (e *SchemaVersionError) Error() string {
+	return fmt.Sprintf("event schema version %d, want at most %d", e.Version, CurrentSchemaVersion)
+}
+
+// Unwrap returns ErrUnsupportedSchemaVersion.
+func (e *SchemaVersionError) Unwrap() error { return ErrUnsupportedSchemaVersion }
+
+// upgrades[v] converts an event of schema version v to version v+1.
+var upgrades = map[int]func(*OrderEvent){
+	0: func(*OrderEvent) {}, // Unversioned events are the v1 baseline
+	// A v1 event does not say its currency; it stays empty, which
+	// consumers read as the publisher's base currency
+	1: func(*OrderEvent) {},
+	// Before v3 amounts were only carried as floats, which were rounded
+	// from cents, so rounding them back recovers the exact amounts
+	2: func(evt *OrderEvent) {
+		evt.TotalMinor = toMinor(evt.Total, evt.Currency)
+		for i := range evt.Items {
+			line := &evt.Items[i]
+			line.UnitPriceMinor = toMinor(line.UnitPrice, evt.Currency)
+			line.SubtotalMinor = toMinor(line.Subtotal, evt.Currency)
+		}
+	},
+	// A v3 event does not say which request caused it
+	3: func(*OrderEvent) {},
+	// A v4 order.updated does not say what changed; it stays empty, as
+	// for updates that are not field edits
+	4: func(*OrderEvent) {},
+	// A v5 event has no sequence number; it stays 0, so consumers cannot
+	// check it for gaps
+	5: func(*OrderEvent) {},
+	// A v6 event carries no metadata; it stays empty
+	6: func(*OrderEvent) {},
+	// A v7 event does not say which event led to it
+	7: func(*OrderEvent) {},
+	// A v8 order.updated names its changed fields without their values;
+	// changes stays empty
+	8: func(*OrderEvent) {},
+}
+
+// toMinor converts a float amount in major units of currency to its
+// nearest minor-unit amount.
+func toMinor(amount float64, currency string) int64 {
+	return int64(math.Round(amount * math.Pow10(domain.MinorUnits(currency))))
+}
+
+// Upgrade returns evt converted to CurrentSchemaVersion. It returns a
+// *SchemaVersionError if evt is from a newer version, or an older one
+// with no upgrade path.
+func Upgrade(evt OrderEvent) (OrderEvent, error) {
+	if evt.SchemaVersion > CurrentSchemaVersion || evt.SchemaVersion < 0 {
+		return OrderEvent{}, &SchemaVersionError{Version: evt.SchemaVersion}
+	}
+	for evt.SchemaVersion < CurrentSchemaVersion {
+		upgrade, ok := upgrades[evt.SchemaVersion]
+		if !ok {
+			return OrderEvent{}, &SchemaVersionError{Version: evt.SchemaVersion}
+		}
+		upgrade(&evt)
+		evt.SchemaVersion++
+	}
+	return evt, nil
+}
+
+// schemaDecoders maps each supported schema version to a decoder of JSON
+// envelopes into that version's shape. OrderEvent is the struct of
+// CurrentSchemaVersion; when a version adds fields, the previous shape is
+// frozen here as a decoder that drops them, so consumers pinned to it keep
+// decoding.
+var schemaDecoders = map[int]func(data []byte) (OrderEvent, error){
+	1: decodeV1,
+	2: decodeV2,
+	3: decodeV3,
+	4: decodeV4,
+	5: decodeV5,
+	6: decodeV6,
+	7: decodeV7,
+	8: decodeV8,
+	9: decodeV9,
+}
+
+// decodeV1 decodes a v1 envelope: a v2 one without currency.
+func decodeV1(data []byte) (OrderEvent, error) {
+	evt, err := decodeV2(data)
+	if err != nil {
+		return OrderEvent{}, err
+	}
+	evt.Currency = ""
+	return evt, nil
+}
+
+// decodeV2 decodes a v2 envelope: a v3 one without the minor-unit
+// amounts.
+func decodeV2(data []byte) (OrderEvent, error) {
+	evt, err := decodeV3(data)
+	if err != nil {
+		return OrderEvent{}, err
+	}
+	evt.TotalMinor = 0
+	for i := range evt.Items {
+		evt.Items[i].UnitPriceMinor = 0
+		evt.Items[i].SubtotalMinor = 0
+	}
+	return evt, nil
+}
+
+// decodeV3 decodes a v3 envelope: a v4 one without the correlation ID.
+func decodeV3(data []byte) (OrderEvent, error) {
+	evt, err := decodeV4(data)
+	if err != nil {
+		return OrderEvent{}, err
+	}
+	evt.CorrelationID = ""
+	return evt, nil
+}
+
+// decodeV4 decodes a v4 envelope: a v5 one without the changed fields.
+func decodeV4(data []byte) (OrderEvent, error) {
+	evt, err := decodeV5(data)
+	if err != nil {
+		return OrderEvent{}, err
+	}
+	evt.ChangedFields = nil
+	return evt, nil
+}
+
+// decodeV5 decodes a v5 envelope: a v6 one without the sequence number.
+func decodeV5(data []byte) (OrderEvent, error) {
+	evt, err := decodeV6(data)
+	if err != nil {
+		return OrderEvent{}, err
+	}
+	evt.Seq = 0
+	return evt, nil
+}
+
+// decodeV6 decodes a v6 envelope: a v7 one without the metadata.
+func decodeV6(data []byte) (OrderEvent, error) {
+	evt, err := decodeV7(data)
+	if err != nil {
+		return OrderEvent{}, err
+	}
+	evt.Metadata = nil
+	return evt, nil
+}
+
+// decodeV7 decodes a v7 envelope: a v8 one without the causation ID.
+func decodeV7(data []byte) (OrderEvent, error) {
+	evt, err := decodeV8(data)
+	if err != nil {
+		return OrderEvent{}, err
+	}
+	evt.CausationID = ""
+	return evt, nil
+}
+
+// decodeV8 decodes a v8 envelope: a v9 one without the changes.
+func decodeV8(data []byte) (OrderEvent, error) {
+	evt, err := decodeV9(data)
+	if err != nil {
+		return OrderEvent{}, err
+	}
+	evt.Changes = nil
+	return evt, nil
+}
+
+// decodeV9 decodes a v9 envelope, whose struct is OrderEvent. Unknown
+// fields are ignored.
+func decodeV9(data []byte) (OrderEvent, error) {
+	var evt OrderEvent
+	if err := json.Unmarshal(data, &evt); err != nil {
+		return OrderEvent{}, err
+	}
+	return evt, nil
+}
+
+// SchemaVersions returns the schema versions DecodeVersion supports,
+// ascending.
+func SchemaVersions() []int {
+	return slices.Sorted(maps.Keys(schemaDecoders))
+}
+
+// DecodeVersion decodes a JSON envelope as schema version, whatever
+// version it was published with. Versions only add fields, so a newer
+// payload decodes by ignoring the fields version does not know, and an
+// older one leaves them at their zero values. This lets a consumer pin
+// the version it was written against while publishers move ahead.
+//
+// The returned event has SchemaVersion set to version. DecodeVersion
+// returns a *SchemaVersionError if version is not in SchemaVersions.
+func DecodeVersion(version int, data []byte) (OrderEvent, error) {
+	decode, ok := schemaDecoders[version]
+	if !ok {
+		return OrderEvent{}, &SchemaVersionError{Version: version}
+	}
+	evt, err := decode(data)
+	if err != nil {
+		return OrderEvent{}, err
+	}
+	evt.SchemaVersion = version
+	return evt, nil
+}
+
+// Unmarshal decodes data with s and upgrades the event to
+// CurrentSchemaVersion. Consumers should decode through it rather than
+// s.Unmarshal, so an event of an unknown version is rejected with a
+// *SchemaVersionError instead of being misread.
+func Unmarshal(s Serializer, data []byte) (OrderEvent, error) {
+	evt, err := s.Unmarshal(data)
+	if err != nil {
+		return OrderEvent{}, err
+	}
+	return Upgrade(evt)
+}
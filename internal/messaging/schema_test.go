@@ -0,0 +1,280 @@
+package messaging
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOrderEvent_SetsCurrentSchemaVersion(t *testing.T) {
+	evt := NewOrderEvent(EventOrderCreated, newTestOrder())
+
+	assert.Equal(t, CurrentSchemaVersion, evt.SchemaVersion)
+}
+
+func TestUpgrade(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int
+		want    int
+		wantErr bool
+	}{
+		{name: "unversioned_upgraded", version: 0, want: CurrentSchemaVersion},
+		{name: "v1_upgraded", version: 1, want: CurrentSchemaVersion},
+		{name: "current", version: CurrentSchemaVersion, want: CurrentSchemaVersion},
+		{name: "future", version: CurrentSchemaVersion + 1, wantErr: true},
+		{name: "negative", version: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evt := NewOrderEvent(EventOrderCreated, newTestOrder())
+			evt.SchemaVersion = tt.version
+
+			got, err := Upgrade(evt)
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrUnsupportedSchemaVersion)
+				var verr *SchemaVersionError
+				require.ErrorAs(t, err, &verr)
+				assert.Equal(t, tt.version, verr.Version)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got.SchemaVersion)
+			assert.Equal(t, evt.EventID, got.EventID)
+		})
+	}
+}
+
+func TestUnmarshal_FutureSchemaVersion_ReturnsSchemaVersionError(t *testing.T) {
+	for _, s := range []Serializer{JSONSerializer{}, ProtobufSerializer{}, CloudEventsSerializer{}} {
+		t.Run(s.ContentType(), func(t *testing.T) {
+			evt := NewOrderEvent(EventOrderCreated, newTestOrder())
+			evt.SchemaVersion = CurrentSchemaVersion + 1
+			data, err := s.Marshal(evt)
+			require.NoError(t, err)
+
+			_, err = Unmarshal(s, data)
+
+			assert.ErrorIs(t, err, ErrUnsupportedSchemaVersion)
+		})
+	}
+}
+
+func TestUnmarshal_UnversionedJSON_UpgradedToCurrent(t *testing.T) {
+	data := []byte(`{"event_id":"e-1","event_type":"order.created","order_id":"o-1","customer_id":"c-1","version":1}`)
+
+	evt, err := Unmarshal(JSONSerializer{}, data)
+
+	require.NoError(t, err)
+	assert.Equal(t, CurrentSchemaVersion, evt.SchemaVersion)
+	assert.Equal(t, "o-1", evt.OrderID)
+}
+
+func TestUnmarshal_DecodeError_Returned(t *testing.T) {
+	_, err := Unmarshal(JSONSerializer{}, []byte("{"))
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnsupportedSchemaVersion)
+}
+
+func TestDecodeVersion_NewerPayload_DecodesAsV1IgnoringUnknownFields(t *testing.T) {
+	data := []byte(`{
+		"event_id": "e-1",
+		"event_type": "order.created",
+		"schema_version": 10,
+		"order_id": "o-1",
+		"customer_id": "c-1",
+		"status": "pending",
+		"total": 21,
+		"total_minor": 2100,
+		"currency": "EUR",
+		"version": 1,
+		"items": [{"sku": "p-1", "name": "Widget", "quantity": 2, "unit_price": 10.5, "subtotal": 21, "unit_price_minor": 1050, "subtotal_minor": 2100, "warehouse": "eu-1"}],
+		"occurred_at": "2026-03-14T10:00:00Z",
+		"gift_message": "Happy birthday",
+		"channel": {"kind": "web"}
+	}`)
+
+	evt, err := DecodeVersion(1, data)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, evt.SchemaVersion)
+	assert.Equal(t, "e-1", evt.EventID)
+	assert.Equal(t, "o-1", evt.OrderID)
+	assert.Equal(t, 21.0, evt.Total)
+	assert.Empty(t, evt.Currency, "currency is a v2 field")
+	assert.Zero(t, evt.TotalMinor, "total_minor is a v3 field")
+	require.Len(t, evt.Items, 1)
+	assert.Equal(t, OrderLineEvent{SKU: "p-1", Name: "Widget", Quantity: 2, UnitPrice: 10.5, Subtotal: 21}, evt.Items[0])
+	assert.NoError(t, evt.Validate())
+
+	_, err = Unmarshal(JSONSerializer{}, data)
+	assert.ErrorIs(t, err, ErrUnsupportedSchemaVersion, "Unmarshal stays strict about newer versions")
+}
+
+func TestDecodeVersion_V2_KeepsCurrency(t *testing.T) {
+	data := []byte(`{"event_id":"e-1","event_type":"order.created","schema_version":2,"order_id":"o-1","currency":"EUR"}`)
+
+	evt, err := DecodeVersion(2, data)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, evt.SchemaVersion)
+	assert.Equal(t, "EUR", evt.Currency)
+}
+
+func TestDecodeVersion_V2_DropsMinorUnitAmounts(t *testing.T) {
+	data := []byte(`{"event_id":"e-1","schema_version":3,"total":21,"total_minor":2100,"currency":"EUR",
+		"items":[{"sku":"p-1","unit_price":10.5,"subtotal":21,"unit_price_minor":1050,"subtotal_minor":2100}]}`)
+
+	evt, err := DecodeVersion(2, data)
+
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", evt.Currency)
+	assert.Zero(t, evt.TotalMinor)
+	require.Len(t, evt.Items, 1)
+	assert.Equal(t, OrderLineEvent{SKU: "p-1", UnitPrice: 10.5, Subtotal: 21}, evt.Items[0])
+}
+
+func TestUnmarshal_V2Event_UpgradedWithMinorUnitAmounts(t *testing.T) {
+	tests := []struct {
+		name      string
+		currency  string
+		total     string
+		wantMinor int64
+	}{
+		{name: "cents", currency: "USD", total: "0.3", wantMinor: 30},
+		{name: "no_currency_is_cents", total: "26.1", wantMinor: 2610},
+		{name: "zero_decimals", currency: "JPY", total: "1500", wantMinor: 1500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := []byte(`{"event_id":"e-1","event_type":"order.created","schema_version":2,"order_id":"o-1",` +
+				`"currency":"` + tt.currency + `","total":` + tt.total + `,` +
+				`"items":[{"sku":"p-1","quantity":1,"unit_price":` + tt.total + `,"subtotal":` + tt.total + `}]}`)
+
+			evt, err := Unmarshal(JSONSerializer{}, data)
+
+			require.NoError(t, err)
+			assert.Equal(t, CurrentSchemaVersion, evt.SchemaVersion)
+			assert.Equal(t, tt.wantMinor, evt.TotalMinor)
+			require.Len(t, evt.Items, 1)
+			assert.Equal(t, tt.wantMinor, evt.Items[0].UnitPriceMinor)
+			assert.Equal(t, tt.wantMinor, evt.Items[0].SubtotalMinor)
+		})
+	}
+}
+
+func TestDecodeVersion_V3_DropsCorrelationID(t *testing.T) {
+	data := []byte(`{"event_id":"e-1","schema_version":4,"order_id":"o-1","total_minor":2100,"correlation_id":"req-abc"}`)
+
+	evt, err := DecodeVersion(3, data)
+	require.NoError(t, err)
+	assert.Empty(t, evt.CorrelationID)
+	assert.Equal(t, int64(2100), evt.TotalMinor)
+
+	evt, err = DecodeVersion(4, data)
+	require.NoError(t, err)
+	assert.Equal(t, "req-abc", evt.CorrelationID)
+}
+
+func TestDecodeVersion_V4_DropsChangedFields(t *testing.T) {
+	data := []byte(`{"event_id":"e-1","event_type":"order.updated","schema_version":5,"order_id":"o-1","correlation_id":"req-abc","changed_fields":["items","total"]}`)
+
+	evt, err := DecodeVersion(4, data)
+	require.NoError(t, err)
+	assert.Nil(t, evt.ChangedFields)
+	assert.Equal(t, "req-abc", evt.CorrelationID)
+
+	evt, err = DecodeVersion(5, data)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"items", "total"}, evt.ChangedFields)
+}
+
+func TestDecodeVersion_V5_DropsSeq(t *testing.T) {
+	data := []byte(`{"event_id":"e-1","event_type":"order.updated","schema_version":6,"order_id":"o-1","changed_fields":["items"],"seq":4}`)
+
+	evt, err := DecodeVersion(5, data)
+	require.NoError(t, err)
+	assert.Zero(t, evt.Seq)
+	assert.Equal(t, []string{"items"}, evt.ChangedFields)
+
+	evt, err = DecodeVersion(6, data)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), evt.Seq)
+}
+
+func TestDecodeVersion_V6_DropsMetadata(t *testing.T) {
+	data := []byte(`{"event_id":"e-1","event_type":"order.created","schema_version":7,"order_id":"o-1","seq":1,"metadata":{"region":"eu-west-1"}}`)
+
+	evt, err := DecodeVersion(6, data)
+	require.NoError(t, err)
+	assert.Nil(t, evt.Metadata)
+	assert.Equal(t, int64(1), evt.Seq)
+
+	evt, err = DecodeVersion(7, data)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"region": "eu-west-1"}, evt.Metadata)
+}
+
+func TestDecodeVersion_V7_DropsCausationID(t *testing.T) {
+	data := []byte(`{"event_id":"e-2","event_type":"order.status_changed","schema_version":8,"order_id":"o-1","seq":2,"causation_id":"e-1"}`)
+
+	evt, err := DecodeVersion(7, data)
+	require.NoError(t, err)
+	assert.Empty(t, evt.CausationID)
+	assert.Equal(t, int64(2), evt.Seq)
+
+	evt, err = DecodeVersion(8, data)
+	require.NoError(t, err)
+	assert.Equal(t, "e-1", evt.CausationID)
+}
+
+func TestDecodeVersion_V8_DropsChanges(t *testing.T) {
+	data := []byte(`{"event_id":"e-2","event_type":"order.updated","schema_version":9,"order_id":"o-1","changed_fields":["customer_id"],` +
+		`"changes":{"customer_id":{"old":"c-1","new":"c-2"}}}`)
+
+	evt, err := DecodeVersion(8, data)
+	require.NoError(t, err)
+	assert.Nil(t, evt.Changes)
+	assert.Equal(t, []string{"customer_id"}, evt.ChangedFields)
+
+	evt, err = DecodeVersion(9, data)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]ChangePair{"customer_id": {Old: json.RawMessage(`"c-1"`), New: json.RawMessage(`"c-2"`)}}, evt.Changes)
+}
+
+func TestUnmarshal_V1Event_UpgradedWithoutCurrency(t *testing.T) {
+	data := []byte(`{"event_id":"e-1","event_type":"order.created","schema_version":1,"order_id":"o-1","customer_id":"c-1","version":1}`)
+
+	evt, err := Unmarshal(JSONSerializer{}, data)
+
+	require.NoError(t, err)
+	assert.Equal(t, CurrentSchemaVersion, evt.SchemaVersion)
+	assert.Empty(t, evt.Currency)
+}
+
+func TestDecodeVersion_OlderPayload_Decodes(t *testing.T) {
+	evt, err := DecodeVersion(1, []byte(`{"event_id":"e-1","event_type":"order.created","order_id":"o-1"}`))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, evt.SchemaVersion)
+	assert.Equal(t, "o-1", evt.OrderID)
+}
+
+func TestDecodeVersion_UnsupportedVersion_ReturnsSchemaVersionError(t *testing.T) {
+	_, err := DecodeVersion(CurrentSchemaVersion+1, []byte(`{}`))
+
+	var verr *SchemaVersionError
+	require.ErrorAs(t, err, &verr)
+	assert.Equal(t, CurrentSchemaVersion+1, verr.Version)
+}
+
+func TestSchemaVersions_IncludesCurrent(t *testing.T) {
+	assert.Contains(t, SchemaVersions(), CurrentSchemaVersion)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}, SchemaVersions())
+}
@@ -0,0 +1,243 @@
+package messaging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Content types identifying each serializer's wire format. Publishers set
+// the matching value in a message header so consumers can pick a decoder.
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+// Serializer converts OrderEvents to and from their wire format.
+type Serializer interface {
+	Marshal(evt OrderEvent) ([]byte, error)
+	Unmarshal(data []byte) (OrderEvent, error)
+	// ContentType identifies the wire format.
+	ContentType() string
+}
+
+// FieldNaming selects the key style of JSON-encoded events.
+type FieldNaming int
+
+const (
+	// SnakeCase keys fields as documented, e.g. "order_id". This is the
+	// default.
+	SnakeCase FieldNaming = iota
+	// CamelCase keys fields in lower camel case, e.g. "orderId", item and
+	// address fields included.
+	CamelCase
+)
+
+// JSONSerializer encodes events as plain JSON. It is the default, and its
+// zero value writes the documented envelope. Consumers must decode with
+// the same Naming the publisher used.
+type JSONSerializer struct {
+	Naming FieldNaming
+	// OmitZero leaves out total, total_minor and version when they are
+	// zero instead of writing them as 0
+	OmitZero bool
+}
+
+// jsonEncoder is a buffer and the json.Encoder writing to it.
+type jsonEncoder struct {
+	out bytes.Buffer
+	enc *json.Encoder // Writes to out
+}
+
+// jsonEncoders holds idle jsonEncoders, so that Marshal reuses their
+// buffers instead of growing a new one for every event. Each is used by
+// one Marshal call at a time, and what it returns is a copy.
+var jsonEncoders = sync.Pool{New: func() any {
+	e := &jsonEncoder{}
+	e.enc = json.NewEncoder(&e.out)
+	return e
+}}
+
+// maxPooledBuffer bounds the buffers kept in jsonEncoders, so that one
+// unusually large event does not pin its buffer for the process lifetime.
+const maxPooledBuffer = 64 << 10
+
+// Marshal encodes evt as JSON. It is safe for concurrent use.
+func (s JSONSerializer) Marshal(evt OrderEvent) ([]byte, error) {
+	e := jsonEncoders.Get().(*jsonEncoder)
+	defer func() {
+		if e.out.Cap() <= maxPooledBuffer {
+			jsonEncoders.Put(e)
+		}
+	}()
+	e.out.Reset()
+	if err := e.enc.Encode(evt); err != nil {
+		return nil, err
+	}
+	// Encode ends the document with a newline, which json.Marshal does not
+	data := bytes.TrimSuffix(e.out.Bytes(), []byte("\n"))
+	if s == (JSONSerializer{}) {
+		return bytes.Clone(data), nil
+	}
+	// rekey writes the envelope into a buffer of its own
+	return rekey(data, s.encodeKey, s.omit)
+}
+
+// Unmarshal decodes a JSON-encoded event.
+func (s JSONSerializer) Unmarshal(data []byte) (OrderEvent, error) {
+	if s.Naming == CamelCase {
+		var err error
+		if data, err = rekey(data, snakeCase, nil); err != nil {
+			return OrderEvent{}, err
+		}
+	}
+	var evt OrderEvent
+	if err := json.Unmarshal(data, &evt); err != nil {
+		return OrderEvent{}, err
+	}
+	return evt, nil
+}
+
+// encodeKey returns the key written for the snake_case key of a field.
+func (s JSONSerializer) encodeKey(key string) string {
+	if s.Naming == CamelCase {
+		return camelCase(key)
+	}
+	return key
+}
+
+// omit reports whether the envelope member key with value raw is left out.
+func (s JSONSerializer) omit(key string, raw json.RawMessage) bool {
+	switch key {
+	case "total", "total_minor", "version":
+		return s.OmitZero && string(raw) == "0"
+	}
+	return false
+}
+
+// rekey re-encodes the JSON document data with every object key passed
+// through key, keeping member order. Members of the top-level object for
+// which omit reports true are dropped; omit may be nil. The keys of
+// metadata and changes are data rather than field names and are kept as
+// they are.
+func rekey(data []byte, key func(string) string, omit func(string, json.RawMessage) bool) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return data, nil
+	}
+	switch data[0] {
+	case '[':
+		var elems []json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		buf.WriteByte('[')
+		for i, elem := range elems {
+			out, err := rekey(elem, key, nil)
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.Write(out)
+		}
+		buf.WriteByte(']')
+		return buf.Bytes(), nil
+	case '{':
+		dec := json.NewDecoder(bytes.NewReader(data))
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		buf.WriteByte('{')
+		for n := 0; dec.More(); {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			name, _ := tok.(string)
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return nil, err
+			}
+			if omit != nil && omit(name, raw) {
+				continue
+			}
+			out := []byte(raw)
+			if name != "metadata" && name != "changes" {
+				if out, err = rekey(raw, key, nil); err != nil {
+					return nil, err
+				}
+			}
+			encoded, err := json.Marshal(key(name))
+			if err != nil {
+				return nil, err
+			}
+			if n > 0 {
+				buf.WriteByte(',')
+			}
+			n++
+			buf.Write(encoded)
+			buf.WriteByte(':')
+			buf.Write(out)
+		}
+		buf.WriteByte('}')
+		return buf.Bytes(), nil
+	default:
+		return data, nil
+	}
+}
+
+// camelCase returns a snake_case key in lower camel case.
+func camelCase(key string) string {
+	var b strings.Builder
+	upper := false
+	for _, r := range key {
+		switch {
+		case r == '_':
+			upper = true
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// snakeCase returns a lower camel case key in snake_case.
+func snakeCase(key string) string {
+	var b strings.Builder
+	for _, r := range key {
+		if unicode.IsUpper(r) {
+			b.WriteByte('_')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ContentType returns ContentTypeJSON.
+func (JSONSerializer) ContentType() string { return ContentTypeJSON }
+
+// SerializerFor returns the serializer for contentType. An empty content
+// type selects JSON, which is what publishers sent before the header existed.
+func SerializerFor(contentType string) (Serializer, error) {
+	switch contentType {
+	case "", ContentTypeJSON:
+		return JSONSerializer{}, nil
+	case ContentTypeProtobuf:
+		return ProtobufSerializer{}, nil
+	case CloudEventsContentType:
+		return CloudEventsSerializer{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported content type %q", contentType)
+	}
+}
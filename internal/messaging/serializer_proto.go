@@ -0,0 +1,145 @@
+package messaging
+
+import (
+	"encoding/json"
+
+	eventsv1 "github.com/sridharn-code-sandbox/go-ordersvc/api/proto/events/v1"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ProtobufSerializer encodes events using the events.v1.OrderEvent schema.
+type ProtobufSerializer struct{}
+
+// Marshal encodes evt as protobuf.
+func (ProtobufSerializer) Marshal(evt OrderEvent) ([]byte, error) {
+	return proto.Marshal(toProto(evt))
+}
+
+// Unmarshal decodes a protobuf-encoded event.
+func (ProtobufSerializer) Unmarshal(data []byte) (OrderEvent, error) {
+	var pb eventsv1.OrderEvent
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return OrderEvent{}, err
+	}
+	return fromProto(&pb), nil
+}
+
+// ContentType returns ContentTypeProtobuf.
+func (ProtobufSerializer) ContentType() string { return ContentTypeProtobuf }
+
+func toProto(evt OrderEvent) *eventsv1.OrderEvent {
+	pb := &eventsv1.OrderEvent{
+		EventId:       evt.EventID,
+		EventType:     evt.EventType,
+		SchemaVersion: int32(evt.SchemaVersion), // #nosec G115 -- schema versions are small
+		OrderId:       evt.OrderID,
+		CustomerId:    evt.CustomerID,
+		Status:        evt.Status.String(),
+		OldStatus:     evt.OldStatus.String(),
+		NewStatus:     evt.NewStatus.String(),
+		CancelReason:  evt.CancelReason,
+		Total:         evt.Total,
+		TotalMinor:    evt.TotalMinor,
+		Currency:      evt.Currency,
+		Version:       int64(evt.Version),
+		OccurredAt:    timestamppb.New(evt.OccurredAt),
+		Replayed:      evt.Replayed,
+		CorrelationId: evt.CorrelationID,
+		ChangedFields: evt.ChangedFields,
+		Seq:           evt.Seq,
+		Metadata:      evt.Metadata,
+		CausationId:   evt.CausationID,
+	}
+	if len(evt.Changes) > 0 {
+		pb.Changes = make(map[string]*eventsv1.ChangePair, len(evt.Changes))
+		for field, change := range evt.Changes {
+			pb.Changes[field] = &eventsv1.ChangePair{Old: string(change.Old), New: string(change.New)}
+		}
+	}
+	for _, line := range evt.Items {
+		pb.Items = append(pb.Items, &eventsv1.OrderLine{
+			Sku:            line.SKU,
+			Name:           line.Name,
+			Quantity:       int64(line.Quantity),
+			UnitPrice:      line.UnitPrice,
+			Subtotal:       line.Subtotal,
+			UnitPriceMinor: line.UnitPriceMinor,
+			SubtotalMinor:  line.SubtotalMinor,
+		})
+	}
+	if a := evt.ShippingAddress; a != nil {
+		pb.ShippingAddress = &eventsv1.Address{
+			Line1:      a.Line1,
+			Line2:      a.Line2,
+			City:       a.City,
+			Region:     a.Region,
+			PostalCode: a.PostalCode,
+			Country:    a.Country,
+		}
+	}
+	return pb
+}
+
+func fromProto(pb *eventsv1.OrderEvent) OrderEvent {
+	evt := OrderEvent{
+		EventID:       pb.GetEventId(),
+		EventType:     pb.GetEventType(),
+		SchemaVersion: int(pb.GetSchemaVersion()),
+		OrderID:       pb.GetOrderId(),
+		CustomerID:    pb.GetCustomerId(),
+		Status:        domain.OrderStatus(pb.GetStatus()),
+		OldStatus:     domain.OrderStatus(pb.GetOldStatus()),
+		NewStatus:     domain.OrderStatus(pb.GetNewStatus()),
+		CancelReason:  pb.GetCancelReason(),
+		Total:         pb.GetTotal(),
+		TotalMinor:    pb.GetTotalMinor(),
+		Currency:      pb.GetCurrency(),
+		Version:       int(pb.GetVersion()),
+		OccurredAt:    pb.GetOccurredAt().AsTime(),
+		Replayed:      pb.GetReplayed(),
+		CorrelationID: pb.GetCorrelationId(),
+		ChangedFields: pb.GetChangedFields(),
+		Seq:           pb.GetSeq(),
+		Metadata:      pb.GetMetadata(),
+		CausationID:   pb.GetCausationId(),
+	}
+	if len(pb.GetChanges()) > 0 {
+		evt.Changes = make(map[string]ChangePair, len(pb.GetChanges()))
+		for field, change := range pb.GetChanges() {
+			evt.Changes[field] = ChangePair{Old: protoJSON(change.GetOld()), New: protoJSON(change.GetNew())}
+		}
+	}
+	for _, line := range pb.GetItems() {
+		evt.Items = append(evt.Items, OrderLineEvent{
+			SKU:            line.GetSku(),
+			Name:           line.GetName(),
+			Quantity:       int(line.GetQuantity()),
+			UnitPrice:      line.GetUnitPrice(),
+			Subtotal:       line.GetSubtotal(),
+			UnitPriceMinor: line.GetUnitPriceMinor(),
+			SubtotalMinor:  line.GetSubtotalMinor(),
+		})
+	}
+	if a := pb.GetShippingAddress(); a != nil {
+		evt.ShippingAddress = &AddressEvent{
+			Line1:      a.GetLine1(),
+			Line2:      a.GetLine2(),
+			City:       a.GetCity(),
+			Region:     a.GetRegion(),
+			PostalCode: a.GetPostalCode(),
+			Country:    a.GetCountry(),
+		}
+	}
+	return evt
+}
+
+// protoJSON returns the JSON text s of a ChangePair value, or nil if s is
+// empty.
+func protoJSON(s string) json.RawMessage {
+	if s == "" {
+		return nil
+	}
+	return json.RawMessage(s)
+}
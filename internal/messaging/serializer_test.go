@@ -0,0 +1,306 @@
+package messaging
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSerializers_RoundTrip_AllEventTypes(t *testing.T) {
+	order := newTestOrder()
+	events := map[string]OrderEvent{
+		EventOrderCreated:       NewOrderEvent(EventOrderCreated, order),
+		EventOrderUpdated:       NewUpdatedEvent(order, map[string]ChangePair{"total": {Old: json.RawMessage(`1000`), New: json.RawMessage(`2100`)}}),
+		EventOrderStatusChanged: NewStatusChangedEvent(order, domain.OrderStatusPending, domain.OrderStatusConfirmed),
+		EventOrderCancelled:     NewCancelledEvent(order, "customer request"),
+	}
+	replayed := NewOrderEvent(EventOrderUpdated, order)
+	replayed.Replayed = true
+	events["replayed"] = replayed
+	correlated := NewOrderEvent(EventOrderCreated, order)
+	correlated.CorrelationID = "req-abc"
+	events["correlated"] = correlated
+	enriched := NewOrderEvent(EventOrderCreated, order)
+	enriched.Metadata = map[string]string{"region": "eu-west-1", "tenant_id": "t-1"}
+	events["enriched"] = enriched
+	serializers := []Serializer{JSONSerializer{}, JSONSerializer{Naming: CamelCase, OmitZero: true}, ProtobufSerializer{}}
+
+	for _, s := range serializers {
+		for name, evt := range events {
+			t.Run(s.ContentType()+"/"+name, func(t *testing.T) {
+				// Normalize to UTC so monotonic/location data doesn't affect equality
+				evt.OccurredAt = evt.OccurredAt.UTC().Truncate(time.Microsecond)
+
+				data, err := s.Marshal(evt)
+				require.NoError(t, err)
+				decoded, err := s.Unmarshal(data)
+				require.NoError(t, err)
+
+				decoded.OccurredAt = decoded.OccurredAt.UTC()
+				assert.Equal(t, evt, decoded)
+			})
+		}
+	}
+}
+
+func TestSerializers_RoundTrip_StatusFields(t *testing.T) {
+	evt := NewStatusChangedEvent(newTestOrder(), domain.OrderStatusShipped, domain.OrderStatusDelivered)
+
+	for _, s := range []Serializer{JSONSerializer{}, ProtobufSerializer{}} {
+		t.Run(s.ContentType(), func(t *testing.T) {
+			data, err := s.Marshal(evt)
+			require.NoError(t, err)
+			decoded, err := s.Unmarshal(data)
+			require.NoError(t, err)
+
+			assert.Equal(t, domain.OrderStatusShipped, decoded.OldStatus)
+			assert.Equal(t, domain.OrderStatusDelivered, decoded.NewStatus)
+		})
+	}
+}
+
+// eventsByType returns an event of every type about order, with every
+// optional field its type carries set.
+func eventsByType(order *domain.Order) map[string]OrderEvent {
+	return map[string]OrderEvent{
+		EventOrderCreated:       NewOrderEvent(EventOrderCreated, order),
+		EventOrderUpdated:       NewUpdatedEvent(order, map[string]ChangePair{"total": {Old: json.RawMessage(`1000`), New: json.RawMessage(`2100`)}}),
+		EventOrderStatusChanged: NewStatusChangedEvent(order, domain.OrderStatusPending, domain.OrderStatusConfirmed),
+		EventOrderCancelled:     NewCancelledEvent(order, "customer request"),
+		EventOrderDeleted:       NewOrderEvent(EventOrderDeleted, order),
+		EventOrderExpired:       NewOrderEvent(EventOrderExpired, order),
+		EventOrderSnapshot:      NewOrderEvent(EventOrderSnapshot, order),
+	}
+}
+
+// decodeObject decodes a JSON object, failing the test if it is not one.
+func decodeObject(t *testing.T, data []byte) map[string]any {
+	t.Helper()
+	var out map[string]any
+	require.NoError(t, json.Unmarshal(data, &out))
+	return out
+}
+
+// camelKeys returns v with the keys of every object in it in camelCase.
+func camelKeys(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, val := range v {
+			out[camelCase(key)] = camelKeys(val)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, val := range v {
+			out[i] = camelKeys(val)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func TestJSONSerializer_CamelCase_SameFieldsAsSnakeCase(t *testing.T) {
+	order := newTestOrder()
+	order.EventSeq = 3
+	snake, camel := JSONSerializer{}, JSONSerializer{Naming: CamelCase}
+
+	for eventType, evt := range eventsByType(order) {
+		t.Run(eventType, func(t *testing.T) {
+			snakeData, err := snake.Marshal(evt)
+			require.NoError(t, err)
+			camelData, err := camel.Marshal(evt)
+			require.NoError(t, err)
+
+			assert.Equal(t, camelKeys(decodeObject(t, snakeData)), decodeObject(t, camelData))
+			decoded, err := camel.Unmarshal(camelData)
+			require.NoError(t, err)
+			assert.Equal(t, evt.OrderID, decoded.OrderID)
+			assert.Equal(t, evt.Seq, decoded.Seq)
+		})
+	}
+}
+
+func TestJSONSerializer_CamelCase_KeepsMetadataKeys(t *testing.T) {
+	evt := NewOrderEvent(EventOrderCreated, newTestOrder())
+	evt.Metadata = map[string]string{"tenant_id": "t-1", "originRegion": "eu-west-1"}
+	s := JSONSerializer{Naming: CamelCase}
+
+	data, err := s.Marshal(evt)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"tenant_id": "t-1", "originRegion": "eu-west-1"}, decodeObject(t, data)["metadata"])
+
+	decoded, err := s.Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, evt.Metadata, decoded.Metadata)
+}
+
+func TestJSONSerializer_CamelCase_KeepsChangesKeys(t *testing.T) {
+	changes := map[string]ChangePair{"customer_id": {Old: json.RawMessage(`"cust-1"`), New: json.RawMessage(`"cust-2"`)}}
+	evt := NewUpdatedEvent(newTestOrder(), changes)
+	s := JSONSerializer{Naming: CamelCase}
+
+	data, err := s.Marshal(evt)
+	require.NoError(t, err)
+	assert.Contains(t, decodeObject(t, data)["changes"], "customer_id")
+
+	decoded, err := s.Unmarshal(data)
+	require.NoError(t, err)
+	assert.Equal(t, changes, decoded.Changes)
+}
+
+func TestJSONSerializer_CamelCase_UnmarshalIndented(t *testing.T) {
+	evt := NewStatusChangedEvent(newTestOrder(), domain.OrderStatusPending, domain.OrderStatusConfirmed)
+	evt.Metadata = map[string]string{"a\"b": "c"}
+	s := JSONSerializer{Naming: CamelCase}
+	data, err := s.Marshal(evt)
+	require.NoError(t, err)
+	var indented bytes.Buffer
+	require.NoError(t, json.Indent(&indented, data, "", "  "))
+
+	decoded, err := s.Unmarshal(indented.Bytes())
+
+	require.NoError(t, err)
+	assert.Equal(t, evt.OrderID, decoded.OrderID)
+	assert.Equal(t, evt.NewStatus, decoded.NewStatus)
+	assert.Equal(t, evt.Metadata, decoded.Metadata)
+}
+
+func TestJSONSerializer_CamelCase_UnmarshalInvalid_ReturnsError(t *testing.T) {
+	for _, data := range []string{`{"orderId":`, `{"orderId":"a`, `{"orderId" "a"}`, `{"items":[{"sku":1}`, `{,}`, `nonsense`} {
+		t.Run(data, func(t *testing.T) {
+			_, err := JSONSerializer{Naming: CamelCase}.Unmarshal([]byte(data))
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestJSONSerializer_CamelCase_Keys(t *testing.T) {
+	evt := NewStatusChangedEvent(newTestOrder(), domain.OrderStatusPending, domain.OrderStatusConfirmed)
+	evt.Items = NewOrderEvent(EventOrderCreated, newTestOrder()).Items
+
+	data, err := JSONSerializer{Naming: CamelCase}.Marshal(evt)
+	require.NoError(t, err)
+
+	got := decodeObject(t, data)
+	for _, key := range []string{"eventId", "eventType", "schemaVersion", "orderId", "customerId", "oldStatus", "newStatus", "totalMinor", "occurredAt"} {
+		assert.Contains(t, got, key)
+	}
+	assert.NotContains(t, got, "order_id")
+	item := got["items"].([]any)[0].(map[string]any)
+	assert.Contains(t, item, "unitPriceMinor")
+	assert.Contains(t, item, "subtotalMinor")
+}
+
+func TestJSONSerializer_Default_WireFormatUnchanged(t *testing.T) {
+	for eventType, evt := range eventsByType(newTestOrder()) {
+		t.Run(eventType, func(t *testing.T) {
+			want, err := json.Marshal(evt)
+			require.NoError(t, err)
+
+			got, err := JSONSerializer{}.Marshal(evt)
+
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(got))
+			if eventType == EventOrderCreated {
+				assert.NotContains(t, decodeObject(t, got), "old_status", "empty statuses are omitted")
+			}
+		})
+	}
+}
+
+func TestJSONSerializer_OmitZero(t *testing.T) {
+	zero := NewOrderEvent(EventOrderCreated, &domain.Order{ID: uuid.New(), CustomerID: "cust-1", Status: domain.OrderStatusPending})
+	set := NewOrderEvent(EventOrderCreated, newTestOrder())
+
+	tests := []struct {
+		name     string
+		s        JSONSerializer
+		evt      OrderEvent
+		wantKeys []string
+		noKeys   []string
+	}{
+		{"zero_omitted", JSONSerializer{OmitZero: true}, zero, nil, []string{"total", "total_minor", "version"}},
+		{"zero_omitted_camel", JSONSerializer{Naming: CamelCase, OmitZero: true}, zero, nil, []string{"total", "totalMinor", "version"}},
+		{"set_kept", JSONSerializer{OmitZero: true}, set, []string{"total", "total_minor", "version"}, nil},
+		{"zero_kept_by_default", JSONSerializer{}, zero, []string{"total", "total_minor", "version"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.s.Marshal(tt.evt)
+			require.NoError(t, err)
+
+			got := decodeObject(t, data)
+			for _, key := range tt.wantKeys {
+				assert.Contains(t, got, key)
+			}
+			for _, key := range tt.noKeys {
+				assert.NotContains(t, got, key)
+			}
+			assert.Contains(t, got, "status", "other zero-valued fields are unaffected")
+
+			decoded, err := tt.s.Unmarshal(data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.evt.Version, decoded.Version)
+			assert.Equal(t, tt.evt.TotalMinor, decoded.TotalMinor)
+		})
+	}
+}
+
+func TestJSONSerializer_Unmarshal_UnknownStatus_ReturnsErrInvalidStatus(t *testing.T) {
+	_, err := JSONSerializer{}.Unmarshal([]byte(`{"event_type":"order.status_changed","status":"lost"}`))
+
+	assert.ErrorIs(t, err, domain.ErrInvalidStatus)
+}
+
+func TestProtobufSerializer_ShippingAddress_RoundTrips(t *testing.T) {
+	evt := NewOrderEvent(EventOrderCreated, newTestOrder())
+	evt.ShippingAddress = &AddressEvent{Line1: "1 Main St", City: "Springfield", PostalCode: "12345", Country: "US"}
+
+	data, err := ProtobufSerializer{}.Marshal(evt)
+	require.NoError(t, err)
+	decoded, err := ProtobufSerializer{}.Unmarshal(data)
+	require.NoError(t, err)
+
+	assert.Equal(t, evt.ShippingAddress, decoded.ShippingAddress)
+}
+
+func TestProtobufSerializer_Unmarshal_Garbage_ReturnsError(t *testing.T) {
+	_, err := ProtobufSerializer{}.Unmarshal([]byte{0xff, 0xff, 0xff})
+	assert.Error(t, err)
+}
+
+func TestSerializerFor(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        Serializer
+		wantErr     bool
+	}{
+		{"", JSONSerializer{}, false},
+		{ContentTypeJSON, JSONSerializer{}, false},
+		{ContentTypeProtobuf, ProtobufSerializer{}, false},
+		{CloudEventsContentType, CloudEventsSerializer{}, false},
+		{"text/plain", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			got, err := SerializerFor(tt.contentType)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
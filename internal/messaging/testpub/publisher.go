@@ -0,0 +1,172 @@
+// Package testpub provides an EventPublisher whose failures tests script,
+// for exercising retry, outbox and dead-letter logic without a broker.
+package testpub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+)
+
+var (
+	_ messaging.EventPublisher = (*Publisher)(nil)
+	_ messaging.BatchPublisher = (*Publisher)(nil)
+)
+
+// ErrInjected is returned by the attempts failed by FailNTimes and
+// FailOnType.
+var ErrInjected = errors.New("testpub: injected failure")
+
+// FailFunc decides the outcome of a publish attempt of evt: a non-nil error
+// fails the attempt and is returned to the caller.
+type FailFunc func(evt messaging.OrderEvent) error
+
+// FailNTimes fails the first n attempts with ErrInjected, whatever the
+// event, and lets every later attempt through. It is safe for concurrent
+// use.
+func FailNTimes(n int) FailFunc {
+	var attempts atomic.Int64
+	return func(messaging.OrderEvent) error {
+		if attempts.Add(1) <= int64(n) {
+			return ErrInjected
+		}
+		return nil
+	}
+}
+
+// FailOnType fails every attempt to publish an event of eventType with
+// ErrInjected.
+func FailOnType(eventType string) FailFunc {
+	return func(evt messaging.OrderEvent) error {
+		if evt.EventType == eventType {
+			return ErrInjected
+		}
+		return nil
+	}
+}
+
+// Attempt is one publish attempt and its outcome.
+type Attempt struct {
+	Event messaging.OrderEvent
+	Err   error // Nil if the attempt succeeded
+}
+
+// Publisher records every publish attempt, in order, failing those its
+// FailFunc fails. It is safe for concurrent use.
+type Publisher struct {
+	fail FailFunc
+
+	mu       sync.Mutex
+	attempts []Attempt
+}
+
+// New returns a Publisher that fails the attempts fail fails. A nil fail
+// lets every attempt through.
+func New(fail FailFunc) *Publisher {
+	if fail == nil {
+		fail = func(messaging.OrderEvent) error { return nil }
+	}
+	return &Publisher{fail: fail}
+}
+
+// PublishOrderCreated attempts an order.created event.
+func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, messaging.NewOrderEvent(messaging.EventOrderCreated, order))
+}
+
+// PublishOrderUpdated attempts an order.updated event.
+func (p *Publisher) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	return p.Publish(ctx, messaging.NewUpdatedEvent(order, changes))
+}
+
+// PublishOrderStatusChanged attempts an order.status_changed event.
+func (p *Publisher) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
+	return p.Publish(ctx, messaging.NewStatusChangedEvent(order, oldStatus, newStatus))
+}
+
+// PublishOrderCancelled attempts an order.cancelled event.
+func (p *Publisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	return p.Publish(ctx, messaging.NewCancelledEvent(order, reason))
+}
+
+// PublishOrderDeleted attempts an order.deleted event.
+func (p *Publisher) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, messaging.NewOrderEvent(messaging.EventOrderDeleted, order))
+}
+
+// PublishOrderExpired attempts an order.expired event.
+func (p *Publisher) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, messaging.NewOrderEvent(messaging.EventOrderExpired, order))
+}
+
+// PublishOrderSnapshot attempts an order.snapshot event.
+func (p *Publisher) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, messaging.NewSnapshotEvent(order))
+}
+
+// Publish attempts a pre-built event.
+func (p *Publisher) Publish(ctx context.Context, evt messaging.OrderEvent) error {
+	return p.attempt(messaging.Correlate(ctx, evt))
+}
+
+// PublishBatch attempts events in batch order, stopping at the first
+// failure, which it returns. Later events are not attempted.
+func (p *Publisher) PublishBatch(ctx context.Context, events []messaging.OrderEvent) error {
+	for _, evt := range events {
+		if err := p.Publish(ctx, evt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attempt records an attempt of evt, failed if the FailFunc says so.
+func (p *Publisher) attempt(evt messaging.OrderEvent) error {
+	// Under the lock, so attempts are recorded in the order the FailFunc saw them
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	err := p.fail(evt)
+	p.attempts = append(p.attempts, Attempt{Event: evt, Err: err})
+	return err
+}
+
+// Attempts returns a copy of every recorded attempt, oldest first.
+func (p *Publisher) Attempts() []Attempt {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Attempt(nil), p.attempts...)
+}
+
+// Published returns the events of the successful attempts, oldest first.
+func (p *Publisher) Published() []messaging.OrderEvent {
+	var out []messaging.OrderEvent
+	for _, a := range p.Attempts() {
+		if a.Err == nil {
+			out = append(out, a.Event)
+		}
+	}
+	return out
+}
+
+// Failed returns the failed attempts, oldest first.
+func (p *Publisher) Failed() []Attempt {
+	var out []Attempt
+	for _, a := range p.Attempts() {
+		if a.Err != nil {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// Reset discards the recorded attempts. It does not reset the state of
+// the FailFunc, such as the count of FailNTimes.
+func (p *Publisher) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.attempts = nil
+}
@@ -0,0 +1,167 @@
+package testpub
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOrder() *domain.Order {
+	return &domain.Order{
+		ID:         uuid.New(),
+		CustomerID: "cust-123",
+		Status:     domain.OrderStatusPending,
+		Total:      domain.Money{Amount: 2100, Currency: "USD"},
+		Version:    1,
+	}
+}
+
+func errs(attempts []Attempt) []error {
+	out := make([]error, len(attempts))
+	for i, a := range attempts {
+		out[i] = a.Err
+	}
+	return out
+}
+
+func TestFailNTimes_FailsExactlyTheFirstN(t *testing.T) {
+	pub := New(FailNTimes(2))
+	order := newTestOrder()
+
+	for range 4 {
+		_ = pub.PublishOrderCreated(context.Background(), order)
+	}
+
+	assert.Equal(t, []error{ErrInjected, ErrInjected, nil, nil}, errs(pub.Attempts()))
+	assert.Len(t, pub.Published(), 2)
+	assert.Len(t, pub.Failed(), 2)
+}
+
+func TestFailNTimes_Zero_NeverFails(t *testing.T) {
+	pub := New(FailNTimes(0))
+
+	assert.NoError(t, pub.PublishOrderDeleted(context.Background(), newTestOrder()))
+	assert.Len(t, pub.Published(), 1)
+}
+
+func TestFailOnType_FailsOnlyThatType(t *testing.T) {
+	pub := New(FailOnType(messaging.EventOrderCancelled))
+	ctx := context.Background()
+	order := newTestOrder()
+
+	require.NoError(t, pub.PublishOrderCreated(ctx, order))
+	assert.ErrorIs(t, pub.PublishOrderCancelled(ctx, order, "out of stock"), ErrInjected)
+	assert.ErrorIs(t, pub.PublishOrderCancelled(ctx, order, "out of stock"), ErrInjected, "every attempt fails")
+	require.NoError(t, pub.PublishOrderStatusChanged(ctx, order, domain.OrderStatusPending, domain.OrderStatusConfirmed))
+
+	var published []string
+	for _, evt := range pub.Published() {
+		published = append(published, evt.EventType)
+	}
+	assert.Equal(t, []string{messaging.EventOrderCreated, messaging.EventOrderStatusChanged}, published)
+	for _, a := range pub.Failed() {
+		assert.Equal(t, messaging.EventOrderCancelled, a.Event.EventType)
+		assert.Equal(t, "out of stock", a.Event.CancelReason)
+	}
+}
+
+func TestPublisher_CustomFailFunc_ScriptsPerEvent(t *testing.T) {
+	errBroker := errors.New("broker unavailable")
+	failing := newTestOrder()
+	pub := New(func(evt messaging.OrderEvent) error {
+		if evt.OrderID == failing.ID.String() {
+			return errBroker
+		}
+		return nil
+	})
+	ctx := context.Background()
+
+	assert.ErrorIs(t, pub.PublishOrderUpdated(ctx, failing, map[string]messaging.ChangePair{"items": {}}), errBroker)
+	require.NoError(t, pub.PublishOrderUpdated(ctx, newTestOrder(), map[string]messaging.ChangePair{"items": {}}))
+
+	attempts := pub.Attempts()
+	require.Len(t, attempts, 2)
+	assert.Equal(t, failing.ID.String(), attempts[0].Event.OrderID)
+	assert.Equal(t, []string{"items"}, attempts[0].Event.ChangedFields)
+	assert.NoError(t, attempts[1].Err)
+}
+
+func TestPublisher_NilFailFunc_RecordsEverySuccess(t *testing.T) {
+	pub := New(nil)
+	ctx := messaging.WithRequestID(context.Background(), "req-abc")
+	evt := messaging.NewOrderEvent(messaging.EventOrderCreated, newTestOrder())
+
+	require.NoError(t, pub.Publish(ctx, evt))
+
+	published := pub.Published()
+	require.Len(t, published, 1)
+	assert.Equal(t, evt.EventID, published[0].EventID)
+	assert.Equal(t, "req-abc", published[0].CorrelationID)
+	assert.Empty(t, pub.Failed())
+}
+
+func TestPublisher_PublishBatch_StopsAtFirstFailure(t *testing.T) {
+	pub := New(FailOnType(messaging.EventOrderCancelled))
+	order := newTestOrder()
+	events := []messaging.OrderEvent{
+		messaging.NewOrderEvent(messaging.EventOrderCreated, order),
+		messaging.NewCancelledEvent(order, ""),
+		messaging.NewOrderEvent(messaging.EventOrderDeleted, order),
+	}
+
+	err := pub.PublishBatch(context.Background(), events)
+
+	assert.ErrorIs(t, err, ErrInjected)
+	assert.Equal(t, []error{nil, ErrInjected}, errs(pub.Attempts()), "the deleted event is not attempted")
+}
+
+func TestPublisher_Reset_KeepsFailFuncState(t *testing.T) {
+	pub := New(FailNTimes(1))
+	order := newTestOrder()
+
+	require.Error(t, pub.PublishOrderCreated(context.Background(), order))
+	pub.Reset()
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+
+	assert.Equal(t, []error{nil}, errs(pub.Attempts()))
+}
+
+func TestPublisher_UnderRetry_RecordsEachAttempt(t *testing.T) {
+	pub := New(FailNTimes(2))
+	retrying := retry.Wrap(pub, retry.Policy{MaxAttempts: 3})
+
+	require.NoError(t, retrying.PublishOrderCreated(context.Background(), newTestOrder()))
+
+	attempts := pub.Attempts()
+	assert.Equal(t, []error{ErrInjected, ErrInjected, nil}, errs(attempts))
+	assert.Equal(t, attempts[0].Event.EventID, attempts[2].Event.EventID, "retries resend the same event")
+}
+
+func TestFailNTimes_Concurrent_FailsExactlyN(t *testing.T) {
+	const publishes, failures = 100, 30
+	pub := New(FailNTimes(failures))
+
+	var wg sync.WaitGroup
+	for range publishes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = pub.PublishOrderCreated(context.Background(), newTestOrder())
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, pub.Failed(), failures)
+	assert.Len(t, pub.Published(), publishes-failures)
+	for _, a := range pub.Attempts()[:failures] {
+		assert.ErrorIs(t, a.Err, ErrInjected, "the first attempts recorded are the failed ones")
+	}
+}
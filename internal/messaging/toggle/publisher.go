@@ -0,0 +1,80 @@
+// Package toggle provides an EventPublisher decorator whose event types can
+// be switched off and on while the service runs, to silence a noisy event
+// type during an incident without redeploying.
+package toggle
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/filter"
+)
+
+var _ messaging.EventPublisher = (*Publisher)(nil)
+
+// Publisher forwards events to the wrapped EventPublisher unless their type
+// is disabled. Publishes of a disabled type are not an error: they return
+// nil without reaching the wrapped publisher, as with filter.Wrap.
+//
+// Every event type is enabled until Disable is called for it. The switches
+// are safe to flip concurrently with publishes, for example from an admin
+// endpoint; a change applies to the publishes that start after it returns.
+type Publisher struct {
+	*filter.Publisher
+	mu       sync.Mutex                      // Serializes changes to disabled
+	disabled atomic.Pointer[map[string]bool] // Replaced on change, never modified
+}
+
+// Wrap returns p decorated with event type switches, the types in disabled
+// starting off.
+func Wrap(p messaging.EventPublisher, disabled ...string) *Publisher {
+	t := &Publisher{}
+	set := make(map[string]bool, len(disabled))
+	for _, eventType := range disabled {
+		set[eventType] = true
+	}
+	t.disabled.Store(&set)
+	t.Publisher = filter.Wrap(p, func(evt messaging.OrderEvent) bool { return t.IsEnabled(evt.EventType) })
+	return t
+}
+
+// Enable switches eventType back on.
+func (p *Publisher) Enable(eventType string) {
+	p.set(eventType, false)
+}
+
+// Disable switches eventType off, so its publishes are dropped.
+func (p *Publisher) Disable(eventType string) {
+	p.set(eventType, true)
+}
+
+// IsEnabled reports whether events of eventType are published.
+func (p *Publisher) IsEnabled(eventType string) bool {
+	return !(*p.disabled.Load())[eventType]
+}
+
+// Disabled returns the disabled event types, sorted.
+func (p *Publisher) Disabled() []string {
+	return slices.Sorted(maps.Keys(*p.disabled.Load()))
+}
+
+// set swaps in a copy of the disabled set with eventType switched, so
+// IsEnabled reads it without locking.
+func (p *Publisher) set(eventType string, off bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	current := *p.disabled.Load()
+	if current[eventType] == off {
+		return
+	}
+	next := maps.Clone(current)
+	if off {
+		next[eventType] = true
+	} else {
+		delete(next, eventType)
+	}
+	p.disabled.Store(&next)
+}
@@ -0,0 +1,117 @@
+package toggle
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOrder() *domain.Order {
+	return &domain.Order{ID: uuid.New(), CustomerID: "cust-1", Status: domain.OrderStatusPending, Version: 1, Total: domain.Money{Amount: 1000, Currency: "USD"}}
+}
+
+func eventTypes(rec *memory.Publisher) []string {
+	var types []string
+	for _, evt := range rec.Events() {
+		types = append(types, evt.EventType)
+	}
+	return types
+}
+
+// publishAll publishes a created, an updated and a status change event.
+func publishAll(t *testing.T, pub messaging.EventPublisher) {
+	t.Helper()
+	ctx := context.Background()
+	order := newOrder()
+	require.NoError(t, pub.PublishOrderCreated(ctx, order))
+	require.NoError(t, pub.PublishOrderUpdated(ctx, order, map[string]messaging.ChangePair{"items": {}}), "dropping is not an error")
+	require.NoError(t, pub.PublishOrderStatusChanged(ctx, order, domain.OrderStatusPending, domain.OrderStatusConfirmed))
+}
+
+func TestPublisher_DisabledType_DroppedOthersFlow(t *testing.T) {
+	rec := memory.New()
+	pub := Wrap(rec)
+	pub.Disable(messaging.EventOrderUpdated)
+
+	publishAll(t, pub)
+
+	assert.Equal(t, []string{messaging.EventOrderCreated, messaging.EventOrderStatusChanged}, eventTypes(rec))
+	assert.False(t, pub.IsEnabled(messaging.EventOrderUpdated))
+	assert.True(t, pub.IsEnabled(messaging.EventOrderCreated))
+}
+
+func TestPublisher_Enable_ResumesType(t *testing.T) {
+	rec := memory.New()
+	pub := Wrap(rec, messaging.EventOrderUpdated)
+	publishAll(t, pub)
+
+	pub.Enable(messaging.EventOrderUpdated)
+	publishAll(t, pub)
+
+	assert.Equal(t, []string{
+		messaging.EventOrderCreated, messaging.EventOrderStatusChanged,
+		messaging.EventOrderCreated, messaging.EventOrderUpdated, messaging.EventOrderStatusChanged,
+	}, eventTypes(rec))
+	assert.Empty(t, pub.Disabled())
+}
+
+func TestPublisher_Disabled_ListsTypesSorted(t *testing.T) {
+	pub := Wrap(memory.New(), messaging.EventOrderUpdated)
+	pub.Disable(messaging.EventOrderSnapshot)
+	pub.Disable(messaging.EventOrderCreated)
+	pub.Disable(messaging.EventOrderCreated)
+	pub.Enable(messaging.EventOrderExpired)
+
+	assert.Equal(t, []string{messaging.EventOrderCreated, messaging.EventOrderSnapshot, messaging.EventOrderUpdated}, pub.Disabled())
+}
+
+func TestPublisher_ConcurrentToggleAndPublish(t *testing.T) {
+	rec := memory.New()
+	pub := Wrap(rec)
+	order := newOrder()
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				if i%2 == 0 {
+					pub.Disable(messaging.EventOrderUpdated)
+				} else {
+					pub.Enable(messaging.EventOrderUpdated)
+				}
+				_ = pub.IsEnabled(messaging.EventOrderUpdated)
+				_ = pub.Disabled()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				assert.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+				assert.NoError(t, pub.PublishOrderUpdated(context.Background(), order, nil))
+			}
+		}()
+	}
+	wg.Wait()
+
+	created := 0
+	for _, evt := range rec.Events() {
+		if evt.EventType == messaging.EventOrderCreated {
+			created++
+		}
+	}
+	assert.Equal(t, 8*200, created, "created events are never dropped")
+
+	pub.Disable(messaging.EventOrderUpdated)
+	before := len(rec.Events())
+	require.NoError(t, pub.PublishOrderUpdated(context.Background(), order, nil))
+	assert.Len(t, rec.Events(), before, "the last switch wins")
+}
@@ -0,0 +1,55 @@
+package messaging
+
+import (
+	"fmt"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+)
+
+// ValidationError reports an OrderEvent field that is missing or invalid.
+type ValidationError struct {
+	Field  string // JSON name of the offending field
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid order event: %s %s", e.Field, e.Reason)
+}
+
+// Unwrap returns ErrValidation.
+func (e *ValidationError) Unwrap() error { return ErrValidation }
+
+// Validate checks that evt has the fields every consumer relies on.
+// Returns a *ValidationError naming the first offending field.
+func (evt OrderEvent) Validate() error {
+	switch {
+	case evt.EventID == "":
+		return &ValidationError{Field: "event_id", Reason: "is required"}
+	case evt.EventType == "":
+		return &ValidationError{Field: "event_type", Reason: "is required"}
+	case evt.OrderID == "":
+		return &ValidationError{Field: "order_id", Reason: "is required"}
+	case evt.CustomerID == "":
+		return &ValidationError{Field: "customer_id", Reason: "is required"}
+	case evt.Currency != "" && !domain.ValidCurrency(evt.Currency):
+		return &ValidationError{Field: "currency", Reason: fmt.Sprintf("must be an ISO 4217 code, got %q", evt.Currency)}
+	case evt.Total < 0:
+		return &ValidationError{Field: "total", Reason: fmt.Sprintf("must not be negative, got %v", evt.Total)}
+	case evt.TotalMinor < 0:
+		return &ValidationError{Field: "total_minor", Reason: fmt.Sprintf("must not be negative, got %d", evt.TotalMinor)}
+	case evt.Version < 1:
+		return &ValidationError{Field: "version", Reason: fmt.Sprintf("must be at least 1, got %d", evt.Version)}
+	case evt.OccurredAt.IsZero():
+		return &ValidationError{Field: "occurred_at", Reason: "is required"}
+	}
+
+	if evt.EventType == EventOrderStatusChanged {
+		if evt.OldStatus == "" {
+			return &ValidationError{Field: "old_status", Reason: "is required for " + EventOrderStatusChanged}
+		}
+		if evt.NewStatus == "" {
+			return &ValidationError{Field: "new_status", Reason: "is required for " + EventOrderStatusChanged}
+		}
+	}
+	return nil
+}
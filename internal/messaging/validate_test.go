@@ -0,0 +1,57 @@
+package messaging
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderEvent_Validate(t *testing.T) {
+	valid := func() OrderEvent { return NewOrderEvent(EventOrderCreated, newTestOrder()) }
+	statusChanged := func() OrderEvent {
+		return NewStatusChangedEvent(newTestOrder(), domain.OrderStatusPending, domain.OrderStatusConfirmed)
+	}
+
+	tests := []struct {
+		name      string
+		evt       func() OrderEvent
+		wantField string // Empty means valid
+	}{
+		{name: "valid created event", evt: valid},
+		{name: "valid status changed event", evt: statusChanged},
+		{name: "zero total is valid", evt: func() OrderEvent { e := valid(); e.Total = 0; return e }},
+		{name: "empty event ID", evt: func() OrderEvent { e := valid(); e.EventID = ""; return e }, wantField: "event_id"},
+		{name: "empty event type", evt: func() OrderEvent { e := valid(); e.EventType = ""; return e }, wantField: "event_type"},
+		{name: "empty order ID", evt: func() OrderEvent { e := valid(); e.OrderID = ""; return e }, wantField: "order_id"},
+		{name: "empty customer ID", evt: func() OrderEvent { e := valid(); e.CustomerID = ""; return e }, wantField: "customer_id"},
+		{name: "empty currency is valid", evt: func() OrderEvent { e := valid(); e.Currency = ""; return e }},
+		{name: "unknown currency", evt: func() OrderEvent { e := valid(); e.Currency = "XYZ"; return e }, wantField: "currency"},
+		{name: "negative total", evt: func() OrderEvent { e := valid(); e.Total = -0.01; return e }, wantField: "total"},
+		{name: "negative total_minor", evt: func() OrderEvent { e := valid(); e.TotalMinor = -1; return e }, wantField: "total_minor"},
+		{name: "zero version", evt: func() OrderEvent { e := valid(); e.Version = 0; return e }, wantField: "version"},
+		{name: "zero occurred at", evt: func() OrderEvent { e := valid(); e.OccurredAt = time.Time{}; return e }, wantField: "occurred_at"},
+		{name: "status changed without old status", evt: func() OrderEvent { e := statusChanged(); e.OldStatus = ""; return e }, wantField: "old_status"},
+		{name: "status changed without new status", evt: func() OrderEvent { e := statusChanged(); e.NewStatus = ""; return e }, wantField: "new_status"},
+		{name: "old/new status not required for other types", evt: func() OrderEvent { e := valid(); e.OldStatus = ""; e.NewStatus = ""; return e }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.evt().Validate()
+
+			if tt.wantField == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var verr *ValidationError
+			require.True(t, errors.As(err, &verr), "want *ValidationError, got %v", err)
+			assert.ErrorIs(t, err, ErrValidation)
+			assert.Equal(t, tt.wantField, verr.Field)
+			assert.Contains(t, err.Error(), tt.wantField)
+		})
+	}
+}
@@ -0,0 +1,313 @@
+// Package webhook implements event publishing by POSTing events to
+// subscriber URLs, signed so subscribers can verify where they came from.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/kafka"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/retry"
+)
+
+// Request headers sent with every delivery. HeaderSignature carries Sign of
+// the request body under the subscriber's secret.
+const (
+	HeaderSignature = "X-Ordersvc-Signature"
+	HeaderEventID   = "X-Ordersvc-Event-Id"
+	HeaderEventType = "X-Ordersvc-Event-Type"
+)
+
+// HeaderDLQWebhookURL is added to dead-lettered events, alongside
+// kafka.HeaderDLQError and kafka.HeaderDLQAttempts, naming the subscriber
+// the event could not be delivered to.
+const HeaderDLQWebhookURL = "dlq-webhook-url"
+
+// ErrDeliveryFailed is wrapped by every error from a failed delivery, after
+// retries are exhausted.
+var ErrDeliveryFailed = errors.New("webhook delivery failed")
+
+// StatusError is the error of a delivery the subscriber answered with a
+// non-2xx status.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("subscriber responded %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Temporary reports whether the status may succeed on retry: 408, 429 and
+// 5xx. Other 4xx responses mean the subscriber rejected the event.
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode == http.StatusRequestTimeout ||
+		e.StatusCode == http.StatusTooManyRequests ||
+		e.StatusCode >= 500
+}
+
+// Subscriber is an endpoint that receives events.
+type Subscriber struct {
+	URL    string
+	Secret []byte // Signs every request to URL
+	// EventTypes limits the events sent to URL, e.g.
+	// []string{messaging.EventOrderStatusChanged}. Empty sends every event.
+	EventTypes []string
+}
+
+// Wants reports whether s subscribes to events of eventType.
+func (s Subscriber) Wants(eventType string) bool {
+	return len(s.EventTypes) == 0 || slices.Contains(s.EventTypes, eventType)
+}
+
+// DeadLetter stores events that could not be delivered, with headers
+// describing the failure. A kafka.Publisher for a dead-letter topic
+// satisfies it.
+type DeadLetter interface {
+	PublishWithHeaders(ctx context.Context, evt messaging.OrderEvent, headers map[string]string) error
+}
+
+// Defaults for a Publisher created by New.
+const (
+	defaultTimeout = 10 * time.Second
+	maxRetryDelay  = 30 * time.Second
+)
+
+// deadLetterTimeout bounds the dead-letter write, which runs even when the
+// publish context has already been cancelled.
+const deadLetterTimeout = 5 * time.Second
+
+var _ messaging.EventPublisher = (*Publisher)(nil)
+
+// Publisher implements messaging.EventPublisher by POSTing each event as
+// JSON to every subscriber that wants it.
+type Publisher struct {
+	subscribers []Subscriber
+	client      *http.Client
+	retry       retry.Policy
+	deadLetter  DeadLetter
+	clock       messaging.Clock
+}
+
+type options struct {
+	client     *http.Client
+	retry      retry.Policy
+	deadLetter DeadLetter
+	clock      messaging.Clock
+}
+
+// Option configures a Publisher created by New.
+type Option func(*options)
+
+// WithHTTPClient sets the client used for deliveries. Defaults to a client
+// with a 10s timeout per attempt.
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) { o.client = c }
+}
+
+// WithRetry makes up to maxAttempts delivery attempts per subscriber,
+// doubling the delay from baseDelay (with jitter, capped at 30s) between
+// attempts. Only network errors and 408, 429 and 5xx responses are
+// retried. Defaults to retry.DefaultPolicy.
+func WithRetry(maxAttempts int, baseDelay time.Duration) Option {
+	return func(o *options) {
+		o.retry = retry.Policy{
+			MaxAttempts: maxAttempts,
+			BaseDelay:   baseDelay,
+			MaxDelay:    max(baseDelay, maxRetryDelay),
+		}
+	}
+}
+
+// WithDeadLetter sends events that still fail after all attempts to dl,
+// with the subscriber URL, failure reason and attempt count in headers.
+func WithDeadLetter(dl DeadLetter) Option {
+	return func(o *options) { o.deadLetter = dl }
+}
+
+// WithClock sets the clock that stamps OccurredAt on events built by the
+// Publish* methods, unless the context pins the time with
+// messaging.WithOccurredAt. Defaults to messaging.SystemClock.
+func WithClock(c messaging.Clock) Option {
+	return func(o *options) { o.clock = c }
+}
+
+// New creates a webhook publisher delivering to subs.
+func New(subs []Subscriber, opts ...Option) *Publisher {
+	o := options{
+		client: &http.Client{Timeout: defaultTimeout},
+		retry:  retry.DefaultPolicy(),
+		clock:  messaging.SystemClock{},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.retry.MaxAttempts < 1 {
+		o.retry.MaxAttempts = 1
+	}
+	o.retry.Retryable = retryable
+
+	return &Publisher{
+		subscribers: slices.Clone(subs),
+		client:      o.client,
+		retry:       o.retry,
+		deadLetter:  o.deadLetter,
+		clock:       o.clock,
+	}
+}
+
+// PublishOrderCreated delivers an order.created event to subscribers.
+func (p *Publisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, p.stamp(messaging.NewOrderEvent(messaging.EventOrderCreated, order)))
+}
+
+// PublishOrderUpdated delivers an order.updated event to subscribers.
+func (p *Publisher) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
+	return p.Publish(ctx, p.stamp(messaging.NewUpdatedEvent(order, changes)))
+}
+
+// PublishOrderStatusChanged delivers an order.status_changed event to
+// subscribers.
+func (p *Publisher) PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error {
+	return p.Publish(ctx, p.stamp(messaging.NewStatusChangedEvent(order, oldStatus, newStatus)))
+}
+
+// PublishOrderCancelled delivers an order.cancelled event to subscribers.
+func (p *Publisher) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	return p.Publish(ctx, p.stamp(messaging.NewCancelledEvent(order, reason)))
+}
+
+// PublishOrderDeleted delivers an order.deleted event to subscribers.
+func (p *Publisher) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, p.stamp(messaging.NewOrderEvent(messaging.EventOrderDeleted, order)))
+}
+
+// PublishOrderExpired delivers an order.expired event to subscribers.
+func (p *Publisher) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, p.stamp(messaging.NewOrderEvent(messaging.EventOrderExpired, order)))
+}
+
+// PublishOrderSnapshot delivers an order.snapshot event to subscribers.
+func (p *Publisher) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	return p.Publish(ctx, messaging.NewSnapshotEvent(order))
+}
+
+// stamp sets evt.OccurredAt from the publisher's clock.
+func (p *Publisher) stamp(evt messaging.OrderEvent) messaging.OrderEvent {
+	evt.OccurredAt = p.clock.Now()
+	return evt
+}
+
+// Publish delivers a pre-built event to every subscriber that wants it,
+// concurrently. A failing subscriber does not stop the others; the errors
+// of all failed deliveries are returned combined with errors.Join, each
+// wrapping ErrDeliveryFailed, even when the event was dead-lettered.
+func (p *Publisher) Publish(ctx context.Context, evt messaging.OrderEvent) error {
+	evt = messaging.Correlate(ctx, evt)
+	if err := evt.Validate(); err != nil {
+		return fmt.Errorf("webhook publish %s: %w", evt.EventType, err)
+	}
+	body, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("webhook marshal %s: %w", evt.EventType, err)
+	}
+
+	errs := make([]error, len(p.subscribers))
+	var wg sync.WaitGroup
+	for i, sub := range p.subscribers {
+		if !sub.Wants(evt.EventType) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = p.deliver(ctx, sub, evt, body)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// deliver POSTs body to sub, retrying per the publisher's policy, and
+// dead-letters evt if every attempt fails.
+func (p *Publisher) deliver(ctx context.Context, sub Subscriber, evt messaging.OrderEvent, body []byte) error {
+	signature := Sign(sub.Secret, body)
+	attempts := 0
+	err := retry.Do(ctx, p.retry, func(ctx context.Context) error {
+		attempts++
+		return p.post(ctx, sub.URL, evt, body, signature)
+	})
+	if err == nil {
+		return nil
+	}
+
+	err = fmt.Errorf("webhook %s to %s: %w: %w", evt.EventType, sub.URL, ErrDeliveryFailed, err)
+	if p.deadLetter != nil {
+		if dlqErr := p.sendDeadLetter(ctx, sub, evt, err, attempts); dlqErr != nil {
+			return errors.Join(err, dlqErr)
+		}
+	}
+	return err
+}
+
+// post makes one delivery attempt.
+func (p *Publisher) post(ctx context.Context, url string, evt messaging.OrderEvent, body []byte, signature string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventID, evt.EventID)
+	req.Header.Set(HeaderEventType, evt.EventType)
+	req.Header.Set(HeaderSignature, signature)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// sendDeadLetter stores evt, which failed delivery to sub after attempts
+// tries with cause.
+func (p *Publisher) sendDeadLetter(ctx context.Context, sub Subscriber, evt messaging.OrderEvent, cause error, attempts int) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deadLetterTimeout)
+	defer cancel()
+
+	err := p.deadLetter.PublishWithHeaders(ctx, evt, map[string]string{
+		kafka.HeaderDLQError:    cause.Error(),
+		kafka.HeaderDLQAttempts: strconv.Itoa(attempts),
+		HeaderDLQWebhookURL:     sub.URL,
+	})
+	if err != nil {
+		return fmt.Errorf("webhook dead-letter %s: %w", evt.EventID, err)
+	}
+	return nil
+}
+
+// retryable retries network errors and temporary statuses. A subscriber
+// rejecting the event with another 4xx will keep rejecting it.
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Temporary()
+	}
+	return true
+}
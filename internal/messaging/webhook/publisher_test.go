@@ -0,0 +1,261 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/kafka"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("s3cret")
+
+// subscriberServer is a test subscriber that verifies signatures and
+// answers the first failN deliveries with failStatus.
+type subscriberServer struct {
+	*httptest.Server
+	failN      int32
+	failStatus int
+
+	calls    atomic.Int32
+	mu       sync.Mutex
+	received []messaging.OrderEvent
+	badSigs  int
+}
+
+func newSubscriberServer(t *testing.T, failN int32, failStatus int) *subscriberServer {
+	t.Helper()
+	s := &subscriberServer{failN: failN, failStatus: failStatus}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *subscriberServer) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if !Verify(testSecret, body, r.Header.Get(HeaderSignature)) {
+		s.mu.Lock()
+		s.badSigs++
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if s.calls.Add(1) <= s.failN {
+		w.WriteHeader(s.failStatus)
+		return
+	}
+	var evt messaging.OrderEvent
+	if err := json.Unmarshal(body, &evt); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.received = append(s.received, evt)
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *subscriberServer) events() []messaging.OrderEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]messaging.OrderEvent(nil), s.received...)
+}
+
+// recordingDeadLetter captures dead-lettered events.
+type recordingDeadLetter struct {
+	mu      sync.Mutex
+	events  []messaging.OrderEvent
+	headers []map[string]string
+	err     error
+}
+
+func (d *recordingDeadLetter) PublishWithHeaders(_ context.Context, evt messaging.OrderEvent, headers map[string]string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err != nil {
+		return d.err
+	}
+	d.events = append(d.events, evt)
+	d.headers = append(d.headers, headers)
+	return nil
+}
+
+func newTestOrder() *domain.Order {
+	return &domain.Order{
+		ID:         uuid.New(),
+		CustomerID: "cust-123",
+		Items: []domain.OrderItem{
+			{ID: uuid.New(), ProductID: "p-1", Name: "Widget", Quantity: 2, Price: domain.Money{Amount: 1050}, Subtotal: domain.Money{Amount: 2100}},
+		},
+		Status:  domain.OrderStatusPending,
+		Total:   domain.Money{Amount: 2100, Currency: "USD"},
+		Version: 1,
+	}
+}
+
+var fastRetry = WithRetry(3, time.Millisecond)
+
+func TestPublisher_PublishOrderCreated_PostsSignedEvent(t *testing.T) {
+	var headers http.Header
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	pub := New([]Subscriber{{URL: srv.URL, Secret: testSecret}})
+	order := newTestOrder()
+
+	require.NoError(t, pub.PublishOrderCreated(context.Background(), order))
+
+	assert.Equal(t, "application/json", headers.Get("Content-Type"))
+	assert.Equal(t, messaging.EventOrderCreated, headers.Get(HeaderEventType))
+	assert.True(t, Verify(testSecret, body, headers.Get(HeaderSignature)))
+	assert.False(t, Verify([]byte("other"), body, headers.Get(HeaderSignature)))
+
+	var evt messaging.OrderEvent
+	require.NoError(t, json.Unmarshal(body, &evt))
+	assert.Equal(t, order.ID.String(), evt.OrderID)
+	assert.Equal(t, evt.EventID, headers.Get(HeaderEventID))
+}
+
+func TestPublisher_ServerError_RetriesUntilDelivered(t *testing.T) {
+	srv := newSubscriberServer(t, 2, http.StatusServiceUnavailable)
+	dl := &recordingDeadLetter{}
+	pub := New([]Subscriber{{URL: srv.URL, Secret: testSecret}}, fastRetry, WithDeadLetter(dl))
+
+	require.NoError(t, pub.PublishOrderUpdated(context.Background(), newTestOrder(), nil))
+
+	assert.Equal(t, int32(3), srv.calls.Load())
+	assert.Len(t, srv.events(), 1)
+	assert.Empty(t, dl.events)
+}
+
+func TestPublisher_RetriesExhausted_DeadLetters(t *testing.T) {
+	srv := newSubscriberServer(t, 100, http.StatusBadGateway)
+	dl := &recordingDeadLetter{}
+	pub := New([]Subscriber{{URL: srv.URL, Secret: testSecret}}, fastRetry, WithDeadLetter(dl))
+	order := newTestOrder()
+
+	err := pub.PublishOrderUpdated(context.Background(), order, nil)
+
+	require.ErrorIs(t, err, ErrDeliveryFailed, "caller still learns the event was not delivered")
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusBadGateway, statusErr.StatusCode)
+	assert.Equal(t, int32(3), srv.calls.Load())
+
+	require.Len(t, dl.events, 1)
+	assert.Equal(t, order.ID.String(), dl.events[0].OrderID)
+	assert.Equal(t, "3", dl.headers[0][kafka.HeaderDLQAttempts])
+	assert.Equal(t, srv.URL, dl.headers[0][HeaderDLQWebhookURL])
+	assert.Contains(t, dl.headers[0][kafka.HeaderDLQError], "502")
+}
+
+func TestPublisher_ClientError_NotRetried(t *testing.T) {
+	srv := newSubscriberServer(t, 100, http.StatusUnprocessableEntity)
+	dl := &recordingDeadLetter{}
+	pub := New([]Subscriber{{URL: srv.URL, Secret: testSecret}}, fastRetry, WithDeadLetter(dl))
+
+	err := pub.PublishOrderUpdated(context.Background(), newTestOrder(), nil)
+
+	require.ErrorIs(t, err, ErrDeliveryFailed)
+	assert.Equal(t, int32(1), srv.calls.Load())
+	require.Len(t, dl.headers, 1)
+	assert.Equal(t, "1", dl.headers[0][kafka.HeaderDLQAttempts])
+}
+
+func TestPublisher_DeadLetterFails_ReturnsBothErrors(t *testing.T) {
+	srv := newSubscriberServer(t, 100, http.StatusInternalServerError)
+	dlqErr := errors.New("dlq broker down")
+	pub := New([]Subscriber{{URL: srv.URL, Secret: testSecret}},
+		WithRetry(1, time.Millisecond), WithDeadLetter(&recordingDeadLetter{err: dlqErr}))
+
+	err := pub.PublishOrderUpdated(context.Background(), newTestOrder(), nil)
+
+	assert.ErrorIs(t, err, ErrDeliveryFailed)
+	assert.ErrorIs(t, err, dlqErr)
+}
+
+func TestPublisher_WrongSecret_RejectedBySubscriber(t *testing.T) {
+	srv := newSubscriberServer(t, 0, 0)
+	pub := New([]Subscriber{{URL: srv.URL, Secret: []byte("wrong")}}, fastRetry)
+
+	err := pub.PublishOrderUpdated(context.Background(), newTestOrder(), nil)
+
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusUnauthorized, statusErr.StatusCode)
+	assert.Equal(t, 1, srv.badSigs)
+	assert.Empty(t, srv.events())
+}
+
+func TestPublisher_FiltersSubscribersByEventType(t *testing.T) {
+	all := newSubscriberServer(t, 0, 0)
+	statusOnly := newSubscriberServer(t, 0, 0)
+	pub := New([]Subscriber{
+		{URL: all.URL, Secret: testSecret},
+		{URL: statusOnly.URL, Secret: testSecret, EventTypes: []string{messaging.EventOrderStatusChanged}},
+	})
+	order := newTestOrder()
+	ctx := context.Background()
+
+	require.NoError(t, pub.PublishOrderCreated(ctx, order))
+	require.NoError(t, pub.PublishOrderStatusChanged(ctx, order, domain.OrderStatusPending, domain.OrderStatusConfirmed))
+
+	assert.Len(t, all.events(), 2)
+	require.Len(t, statusOnly.events(), 1)
+	assert.Equal(t, messaging.EventOrderStatusChanged, statusOnly.events()[0].EventType)
+	assert.Equal(t, domain.OrderStatusConfirmed, statusOnly.events()[0].NewStatus)
+}
+
+func TestPublisher_OneSubscriberFails_OthersStillDelivered(t *testing.T) {
+	ok := newSubscriberServer(t, 0, 0)
+	down := newSubscriberServer(t, 100, http.StatusServiceUnavailable)
+	pub := New([]Subscriber{
+		{URL: down.URL, Secret: testSecret},
+		{URL: ok.URL, Secret: testSecret},
+	}, WithRetry(2, time.Millisecond))
+
+	err := pub.PublishOrderUpdated(context.Background(), newTestOrder(), nil)
+
+	require.ErrorIs(t, err, ErrDeliveryFailed)
+	assert.Contains(t, err.Error(), down.URL)
+	assert.NotContains(t, err.Error(), ok.URL)
+	assert.Len(t, ok.events(), 1)
+}
+
+func TestPublisher_InvalidEvent_NotDelivered(t *testing.T) {
+	srv := newSubscriberServer(t, 0, 0)
+	pub := New([]Subscriber{{URL: srv.URL, Secret: testSecret}})
+	order := newTestOrder()
+	order.CustomerID = ""
+
+	err := pub.PublishOrderCreated(context.Background(), order)
+
+	var verr *messaging.ValidationError
+	require.ErrorAs(t, err, &verr)
+	assert.Zero(t, srv.calls.Load())
+}
+
+func TestVerify_MalformedSignature_False(t *testing.T) {
+	body := []byte(`{"event_id":"e-1"}`)
+
+	for _, sig := range []string{"", "deadbeef", "sha256=zz", "sha1=" + Sign(testSecret, body)[len("sha256="):]} {
+		assert.False(t, Verify(testSecret, body, sig), sig)
+	}
+	assert.True(t, Verify(testSecret, body, Sign(testSecret, body)))
+}
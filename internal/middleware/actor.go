@@ -0,0 +1,56 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+)
+
+// maxActorLen matches the changed_by column of the order status history.
+const maxActorLen = 255
+
+// Actor returns a middleware that records the caller named in header as
+// the actor of the changes made by the request, with domain.WithActor.
+// The service does not authenticate callers itself: use Actor only behind
+// a proxy that authenticates them, sets header, and strips any header a
+// client sent. Requests without a usable header carry no actor.
+func Actor(header string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actor := r.Header.Get(header)
+			if !validActor(actor) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(domain.WithActor(r.Context(), actor)))
+		})
+	}
+}
+
+// validActor reports whether actor is non-empty, not too long, and only
+// printable ASCII, so it can be stored and logged as is.
+func validActor(actor string) bool {
+	if actor == "" || len(actor) > maxActorLen {
+		return false
+	}
+	for i := 0; i < len(actor); i++ {
+		if actor[i] < 0x20 || actor[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
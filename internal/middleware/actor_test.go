@@ -0,0 +1,63 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+const testActorHeader = "X-Authenticated-User"
+
+// serveWithActor serves a request with the given actor header through
+// Actor and returns the actor the handler saw.
+func serveWithActor(header string) string {
+	var seen string
+	h := Actor(testActorHeader)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seen = domain.ActorFromContext(r.Context())
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", nil)
+	if header != "" {
+		req.Header.Set(testActorHeader, header)
+	}
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	return seen
+}
+
+func TestActor_Header_SetsActor(t *testing.T) {
+	assert.Equal(t, "alice@example.com", serveWithActor("alice@example.com"))
+}
+
+func TestActor_MissingOrInvalidHeader_NoActor(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{"missing", ""},
+		{"too_long", strings.Repeat("a", maxActorLen+1)},
+		{"control_characters", "alice\nforged log line"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Empty(t, serveWithActor(tt.header))
+		})
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+)
+
+// Scope is what an API key may do.
+type Scope string
+
+const (
+	// ScopeRead allows only reading orders: GET, HEAD and OPTIONS requests
+	ScopeRead Scope = "read"
+	// ScopeWrite allows reading and changing orders
+	ScopeWrite Scope = "write"
+)
+
+// allows reports whether s permits requests with method.
+func (s Scope) allows(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return s == ScopeRead || s == ScopeWrite
+	}
+	return s == ScopeWrite
+}
+
+// Principal is the client an API key authenticates.
+type Principal struct {
+	Name  string // Recorded as the actor of the client's changes
+	Scope Scope
+}
+
+// APIKeyStore looks up the principal of an API key.
+type APIKeyStore interface {
+	// Lookup returns the principal key belongs to, and false if key is
+	// unknown.
+	Lookup(ctx context.Context, key string) (Principal, bool, error)
+}
+
+var _ APIKeyStore = (*StaticKeys)(nil)
+
+// StaticKeys is an APIKeyStore of a fixed set of keys. It keeps only
+// digests of the keys, so lookups do not compare keys byte by byte.
+type StaticKeys struct {
+	principals map[[sha256.Size]byte]Principal
+}
+
+// NewStaticKeys creates a store of keys, mapping each key to its
+// principal.
+func NewStaticKeys(keys map[string]Principal) *StaticKeys {
+	s := &StaticKeys{principals: make(map[[sha256.Size]byte]Principal, len(keys))}
+	for key, p := range keys {
+		s.principals[sha256.Sum256([]byte(key))] = p
+	}
+	return s
+}
+
+// ParseAPIKeys parses a comma-separated list of name:scope:key entries,
+// such as "reporting:read:k1,checkout:write:k2", into a store. Keys may
+// contain colons; names may not.
+func ParseAPIKeys(spec string) (*StaticKeys, error) {
+	keys := make(map[string]Principal)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("api key entry %q: want name:scope:key", redact(entry))
+		}
+		scope := Scope(parts[1])
+		if scope != ScopeRead && scope != ScopeWrite {
+			return nil, fmt.Errorf("api key %s: unknown scope %q", parts[0], parts[1])
+		}
+		if _, dup := keys[parts[2]]; dup {
+			return nil, fmt.Errorf("api key %s: key is already in use", parts[0])
+		}
+		keys[parts[2]] = Principal{Name: parts[0], Scope: scope}
+	}
+	return NewStaticKeys(keys), nil
+}
+
+// redact returns entry with everything after its second colon, the key,
+// hidden.
+func redact(entry string) string {
+	parts := strings.SplitN(entry, ":", 3)
+	if len(parts) == 3 {
+		return parts[0] + ":" + parts[1] + ":***"
+	}
+	return "***"
+}
+
+// Lookup returns the principal of key.
+func (s *StaticKeys) Lookup(_ context.Context, key string) (Principal, bool, error) {
+	p, ok := s.principals[sha256.Sum256([]byte(key))]
+	return p, ok, nil
+}
+
+type principalKey struct{}
+
+// PrincipalFromContext returns the principal APIKeyAuth authenticated the
+// request as.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// APIKeyAuth returns a middleware that authenticates each request by the
+// API key in its "Authorization: Bearer <key>" header, looked up in store.
+// Requests without a key, or with one store does not know, get 401
+// Unauthorized; requests the key's scope does not allow, such as a POST
+// with a read-only key, get 403 Forbidden. The principal is put in the
+// request context, and recorded with domain.WithActor as the actor of the
+// changes the request makes. Failed lookups are logged to logger.
+func APIKeyAuth(store APIKeyStore, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, ok := bearerToken(r.Header.Get("Authorization"))
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="ordersvc"`)
+				writeError(w, http.StatusUnauthorized, "API key required", "MISSING_API_KEY")
+				return
+			}
+			p, ok, err := store.Lookup(r.Context(), key)
+			if err != nil {
+				logger.Error("api key lookup failed", slog.String("error", err.Error()))
+				writeError(w, http.StatusInternalServerError, "internal server error", "INTERNAL_ERROR")
+				return
+			}
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="ordersvc", error="invalid_token"`)
+				writeError(w, http.StatusUnauthorized, "invalid API key", "INVALID_API_KEY")
+				return
+			}
+			if !p.Scope.allows(r.Method) {
+				writeError(w, http.StatusForbidden, "API key is not allowed to change orders", "INSUFFICIENT_SCOPE")
+				return
+			}
+			ctx := context.WithValue(r.Context(), principalKey{}, p)
+			next.ServeHTTP(w, r.WithContext(domain.WithActor(ctx, p.Name)))
+		})
+	}
+}
+
+// bearerToken returns the token of an Authorization header using the
+// Bearer scheme, whose name is case-insensitive.
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// writeError writes an error response in the shape the handlers use
+func writeError(w http.ResponseWriter, status int, message, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"code":    code,
+		"message": message,
+	})
+}
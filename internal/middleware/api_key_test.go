@@ -0,0 +1,169 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKeys = NewStaticKeys(map[string]Principal{
+	"k-report":   {Name: "reporting", Scope: ScopeRead},
+	"k-checkout": {Name: "checkout", Scope: ScopeWrite},
+})
+
+// serveWithKey serves a request with method and Authorization header
+// through APIKeyAuth, returning the response and whether the handler ran.
+func serveWithKey(store APIKeyStore, method, authorization string) (*httptest.ResponseRecorder, bool) {
+	reached := false
+	h := APIKeyAuth(store, slog.New(slog.DiscardHandler))(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		reached = true
+	}))
+	req := httptest.NewRequest(method, "/api/v1/orders", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec, reached
+}
+
+func TestAPIKeyAuth_Rejected(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		wantStatus    int
+		wantCode      string
+	}{
+		{"missing", http.MethodGet, "", http.StatusUnauthorized, "MISSING_API_KEY"},
+		{"other_scheme", http.MethodGet, "Basic azpr", http.StatusUnauthorized, "MISSING_API_KEY"},
+		{"empty_token", http.MethodGet, "Bearer ", http.StatusUnauthorized, "MISSING_API_KEY"},
+		{"invalid", http.MethodGet, "Bearer k-unknown", http.StatusUnauthorized, "INVALID_API_KEY"},
+		{"read_only_post", http.MethodPost, "Bearer k-report", http.StatusForbidden, "INSUFFICIENT_SCOPE"},
+		{"read_only_patch", http.MethodPatch, "Bearer k-report", http.StatusForbidden, "INSUFFICIENT_SCOPE"},
+		{"read_only_delete", http.MethodDelete, "Bearer k-report", http.StatusForbidden, "INSUFFICIENT_SCOPE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, reached := serveWithKey(testKeys, tt.method, tt.authorization)
+
+			assert.False(t, reached)
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			var body map[string]string
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantCode, body["code"])
+			if tt.wantStatus == http.StatusUnauthorized {
+				assert.Contains(t, rec.Header().Get("WWW-Authenticate"), "Bearer")
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuth_Allowed(t *testing.T) {
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+	}{
+		{"read_only_get", http.MethodGet, "Bearer k-report"},
+		{"read_only_head", http.MethodHead, "Bearer k-report"},
+		{"write_get", http.MethodGet, "Bearer k-checkout"},
+		{"write_post", http.MethodPost, "bearer k-checkout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, reached := serveWithKey(testKeys, tt.method, tt.authorization)
+
+			assert.True(t, reached)
+			assert.Equal(t, http.StatusOK, rec.Code)
+		})
+	}
+}
+
+func TestAPIKeyAuth_PrincipalIsActor(t *testing.T) {
+	var principal Principal
+	var actor string
+	h := APIKeyAuth(testKeys, slog.New(slog.DiscardHandler))(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		principal, _ = PrincipalFromContext(r.Context())
+		actor = domain.ActorFromContext(r.Context())
+	}))
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/orders/1/status", nil)
+	req.Header.Set("Authorization", "Bearer k-checkout")
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, Principal{Name: "checkout", Scope: ScopeWrite}, principal)
+	assert.Equal(t, "checkout", actor)
+}
+
+type failingKeyStore struct{}
+
+func (failingKeyStore) Lookup(context.Context, string) (Principal, bool, error) {
+	return Principal{}, false, errors.New("connection refused")
+}
+
+func TestAPIKeyAuth_StoreError_Returns500(t *testing.T) {
+	rec, reached := serveWithKey(failingKeyStore{}, http.MethodGet, "Bearer k-report")
+
+	assert.False(t, reached)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestParseAPIKeys(t *testing.T) {
+	keys, err := ParseAPIKeys("reporting:read:k1, checkout:write:k:2,")
+	require.NoError(t, err)
+
+	p, ok, err := keys.Lookup(context.Background(), "k:2")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, Principal{Name: "checkout", Scope: ScopeWrite}, p)
+	_, ok, _ = keys.Lookup(context.Background(), "k1")
+	assert.True(t, ok)
+}
+
+func TestParseAPIKeys_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{"missing_key", "reporting:read"},
+		{"empty_name", ":read:k1"},
+		{"empty_key", "reporting:read:"},
+		{"unknown_scope", "reporting:admin:k1"},
+		{"duplicate_key", "a:read:k1,b:write:k1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseAPIKeys(tt.spec)
+
+			require.Error(t, err)
+			assert.NotContains(t, err.Error(), "k1", "keys are not echoed")
+		})
+	}
+}
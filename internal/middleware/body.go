@@ -0,0 +1,72 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"mime"
+	"net/http"
+)
+
+// DefaultMaxBodyBytes is the request body limit of MaxBodySize when given
+// zero or less.
+const DefaultMaxBodyBytes = 1 << 20
+
+// MaxBodySize returns a middleware that limits request bodies to n bytes.
+// A request declaring a longer Content-Length is refused with 413 before
+// the handler runs; any other body is read through http.MaxBytesReader,
+// so a handler decoding it stops at the limit with an *http.MaxBytesError
+// instead of reading the whole body into memory.
+func MaxBodySize(n int64) func(http.Handler) http.Handler {
+	if n <= 0 {
+		n = DefaultMaxBodyBytes
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > n {
+				writeError(w, http.StatusRequestEntityTooLarge, "request body too large", "BODY_TOO_LARGE")
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, n)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireJSON returns a middleware that refuses, with 415, requests that
+// have a body but no application/json Content-Type. Media type parameters
+// such as charset are allowed. Requests without a body, such as GETs and
+// empty PATCHes, pass whatever their Content-Type.
+func RequireJSON() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if hasBody(r) && !isJSON(r.Header.Get("Content-Type")) {
+				writeError(w, http.StatusUnsupportedMediaType, "content type must be application/json", "UNSUPPORTED_MEDIA_TYPE")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// hasBody reports whether r may carry a body: one of known non-zero
+// length, or of unknown length, as with chunked encoding.
+func hasBody(r *http.Request) bool {
+	return r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
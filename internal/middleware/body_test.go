@@ -0,0 +1,122 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readBody is a handler that reads the whole body, recording what it got.
+type readBody struct {
+	called bool
+	body   string
+	err    error
+}
+
+func (h *readBody) ServeHTTP(_ http.ResponseWriter, r *http.Request) {
+	h.called = true
+	b, err := io.ReadAll(r.Body)
+	h.body, h.err = string(b), err
+}
+
+func TestMaxBodySize_DeclaredLengthOverLimit_Returns413(t *testing.T) {
+	next := &readBody{}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(strings.Repeat("x", 65)))
+	rec := httptest.NewRecorder()
+
+	MaxBodySize(64)(next).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.False(t, next.called, "refused before the handler")
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "BODY_TOO_LARGE", body["code"])
+}
+
+func TestMaxBodySize_UnknownLengthOverLimit_ReadStopsAtLimit(t *testing.T) {
+	next := &readBody{}
+	// Not a strings.Reader, so the length is unknown, as with chunked
+	// encoding
+	body := io.MultiReader(strings.NewReader(strings.Repeat("x", 1000)))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", body)
+	require.Equal(t, int64(-1), req.ContentLength)
+
+	MaxBodySize(64)(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	var tooLarge *http.MaxBytesError
+	require.True(t, errors.As(next.err, &tooLarge), "got %v", next.err)
+	assert.Equal(t, int64(64), tooLarge.Limit)
+	assert.Len(t, next.body, 64)
+}
+
+func TestMaxBodySize_UnderLimit_PassesBody(t *testing.T) {
+	next := &readBody{}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(`{"customer_id": "cust-1"}`))
+
+	MaxBodySize(0)(next).ServeHTTP(httptest.NewRecorder(), req)
+
+	require.NoError(t, next.err)
+	assert.Equal(t, `{"customer_id": "cust-1"}`, next.body)
+}
+
+func TestRequireJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		body        string
+		contentType string
+		wantStatus  int
+	}{
+		{"json", http.MethodPost, "{}", "application/json", http.StatusOK},
+		{"json_with_charset", http.MethodPost, "{}", "application/json; charset=utf-8", http.StatusOK},
+		{"json_mixed_case", http.MethodPut, "{}", "Application/JSON", http.StatusOK},
+		{"missing", http.MethodPost, "{}", "", http.StatusUnsupportedMediaType},
+		{"form", http.MethodPost, "a=b", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"text", http.MethodPatch, "{}", "text/plain", http.StatusUnsupportedMediaType},
+		{"malformed", http.MethodPost, "{}", "application/json; =", http.StatusUnsupportedMediaType},
+		{"get_without_body", http.MethodGet, "", "", http.StatusOK},
+		{"empty_patch", http.MethodPatch, "", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &readBody{}
+			req := httptest.NewRequest(tt.method, "/api/v1/orders", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+
+			RequireJSON()(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Equal(t, tt.wantStatus == http.StatusOK, next.called)
+			if tt.wantStatus != http.StatusOK {
+				var body map[string]string
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				assert.Equal(t, "UNSUPPORTED_MEDIA_TYPE", body["code"])
+			}
+		})
+	}
+}
@@ -18,8 +18,6 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
-
-	"github.com/go-chi/chi/v5/middleware"
 )
 
 // responseWriter wraps http.ResponseWriter to capture status code
@@ -41,7 +39,14 @@ func (rw *responseWriter) WriteHeader(code int) {
 	}
 }
 
-// Logging returns a middleware that logs HTTP requests using slog
+// Unwrap returns the wrapped writer, so http.ResponseController can flush
+// streamed responses through it.
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Logging returns a middleware that logs HTTP requests using slog, with
+// the request ID from RequestID, which must run before it.
 // CONSTRAINT: Every request must be logged with slog (ADR-0002)
 func Logging(logger *slog.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -49,9 +54,6 @@ func Logging(logger *slog.Logger) func(http.Handler) http.Handler {
 			start := time.Now()
 			wrapped := wrapResponseWriter(w)
 
-			// Get request ID from chi middleware if available
-			reqID := middleware.GetReqID(r.Context())
-
 			next.ServeHTTP(wrapped, r)
 
 			duration := time.Since(start)
@@ -62,7 +64,7 @@ func Logging(logger *slog.Logger) func(http.Handler) http.Handler {
 				slog.Int("status", wrapped.status),
 				slog.Duration("duration", duration),
 				slog.String("remote_addr", r.RemoteAddr),
-				slog.String("request_id", reqID),
+				slog.String("request_id", GetRequestID(r.Context())),
 			)
 		})
 	}
@@ -15,15 +15,228 @@
 package middleware
 
 import (
+	"encoding/json"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
-// RateLimit returns a middleware that rate limits requests
-func RateLimit() func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// TODO: implement rate limiting using Redis
-			next.ServeHTTP(w, r)
+// StatusQuotaExceeded is the status of a request refused because the
+// client has used up an allowance: of requests here, or of something a
+// handler counts, such as a customer's open orders. Handlers use it so
+// that throttling statuses stay defined in middleware (ADR-0005).
+const StatusQuotaExceeded = http.StatusTooManyRequests
+
+// sweepInterval is how often idle buckets are dropped.
+const sweepInterval = time.Minute
+
+// Limit is a token-bucket rate: a client may make Burst requests at once,
+// after which its bucket refills at PerSecond requests per second.
+// A PerSecond of zero or less disables limiting.
+type Limit struct {
+	PerSecond float64
+	Burst     int // Raised to 1 if lower
+}
+
+// RouteLimit applies Limit instead of the default limit to requests with
+// Method whose path matches Pattern, a chi-style route pattern such as
+// "/api/v1/orders/{id}". A trailing slash is ignored.
+type RouteLimit struct {
+	Method  string
+	Pattern string
+	Limit   Limit
+}
+
+// RateLimitConfig configures RateLimit.
+type RateLimitConfig struct {
+	// Default limits requests that match no route in Routes
+	Default Limit
+	// Routes set per-route limits; the first match applies. Each route
+	// has buckets of its own, separate from the default ones.
+	Routes []RouteLimit
+	// Key returns the client identity buckets are kept by.
+	// Defaults to ClientKey.
+	Key func(r *http.Request) string
+}
+
+// RateLimit returns a middleware that limits each client to its token
+// bucket, keyed by cfg.Key. Requests over the limit get 429 Too Many
+// Requests with a Retry-After header giving the seconds until a token is
+// available. Buckets are kept in memory, so each replica limits on its
+// own. It is safe for concurrent use.
+//
+// CONSTRAINT: Rate limiting lives in middleware, not handlers (ADR-0005)
+func RateLimit(cfg RateLimitConfig) func(http.Handler) http.Handler {
+	return newRateLimiter(cfg, time.Now).middleware
+}
+
+// ClientKey identifies the client of r by the principal APIKeyAuth
+// authenticated it as, or by its IP address when it was not. Nothing the
+// client sends unauthenticated picks its bucket, so it cannot escape its
+// limit by varying a header. Put RateLimit after APIKeyAuth so requests
+// are limited per principal, and after chi's RealIP middleware so clients
+// behind a proxy are told apart.
+func ClientKey(r *http.Request) string {
+	if p, ok := PrincipalFromContext(r.Context()); ok {
+		return "principal:" + p.Name
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr // RealIP sets it without a port
+	}
+	return "ip:" + host
+}
+
+// rateLimiter holds the buckets of every route limit.
+type rateLimiter struct {
+	key    func(r *http.Request) string
+	routes []routeBuckets
+	def    *buckets // Nil when the default limit is disabled
+}
+
+type routeBuckets struct {
+	method   string
+	segments []string
+	buckets  *buckets // Nil when the route is unlimited
+}
+
+func newRateLimiter(cfg RateLimitConfig, now func() time.Time) *rateLimiter {
+	l := &rateLimiter{key: cfg.Key, def: newBuckets(cfg.Default, now)}
+	if l.key == nil {
+		l.key = ClientKey
+	}
+	for _, rl := range cfg.Routes {
+		l.routes = append(l.routes, routeBuckets{
+			method:   rl.Method,
+			segments: splitPath(rl.Pattern),
+			buckets:  newBuckets(rl.Limit, now),
 		})
 	}
+	return l
+}
+
+func (l *rateLimiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b := l.bucketsFor(r)
+		if b == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ok, wait := b.take(l.key(r))
+		if !ok {
+			// Retry-After is in whole seconds, rounded up so a client
+			// retrying on time finds a token
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"code":    "RATE_LIMITED",
+				"message": "rate limit exceeded",
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bucketsFor returns the buckets limiting r, or nil if r is unlimited.
+func (l *rateLimiter) bucketsFor(r *http.Request) *buckets {
+	path := splitPath(r.URL.Path)
+	for _, rb := range l.routes {
+		if rb.method == r.Method && matchSegments(rb.segments, path) {
+			return rb.buckets
+		}
+	}
+	return l.def
+}
+
+// splitPath splits a path or route pattern into segments, ignoring a
+// trailing slash.
+func splitPath(p string) []string {
+	return strings.Split(strings.Trim(p, "/"), "/")
+}
+
+// matchSegments reports whether path matches pattern, where a "{...}"
+// segment of pattern matches any one segment.
+func matchSegments(pattern, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+	for i, seg := range pattern {
+		if seg != path[i] && !strings.HasPrefix(seg, "{") {
+			return false
+		}
+	}
+	return true
+}
+
+// buckets is a set of token buckets sharing one limit, keyed by client.
+type buckets struct {
+	rate  float64 // Tokens per second
+	burst float64
+	now   func() time.Time
+
+	mu        sync.Mutex
+	clients   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time // When tokens was last brought up to date
+}
+
+func newBuckets(limit Limit, now func() time.Time) *buckets {
+	if limit.PerSecond <= 0 {
+		return nil
+	}
+	return &buckets{
+		rate:      limit.PerSecond,
+		burst:     float64(max(limit.Burst, 1)),
+		now:       now,
+		clients:   make(map[string]*bucket),
+		lastSweep: now(),
+	}
+}
+
+// take removes a token from the bucket of key. If there is none, it
+// returns false and how long until there will be.
+func (b *buckets) take(key string) (bool, time.Duration) {
+	now := b.now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sweep(now)
+
+	c, ok := b.clients[key]
+	if !ok {
+		c = &bucket{tokens: b.burst, last: now}
+		b.clients[key] = c
+	}
+	c.tokens = min(b.burst, c.tokens+now.Sub(c.last).Seconds()*b.rate)
+	c.last = now
+
+	if c.tokens >= 1 {
+		c.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - c.tokens) / b.rate * float64(time.Second))
+}
+
+// sweep drops, at most once per sweepInterval, the buckets that have
+// refilled completely: a new bucket for the client would be the same.
+func (b *buckets) sweep(now time.Time) {
+	if now.Sub(b.lastSweep) < sweepInterval {
+		return
+	}
+	b.lastSweep = now
+	refill := time.Duration(b.burst / b.rate * float64(time.Second))
+	for key, c := range b.clients {
+		if now.Sub(c.last) >= refill {
+			delete(b.clients, key)
+		}
+	}
 }
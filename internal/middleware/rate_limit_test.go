@@ -0,0 +1,227 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClock is a clock that only moves when advanced.
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// newLimitedHandler returns a handler limited by cfg on clock that answers
+// 200 when a request gets through.
+func newLimitedHandler(cfg RateLimitConfig, clock *testClock) http.Handler {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	return newRateLimiter(cfg, clock.Now).middleware(ok)
+}
+
+// send serves a request from remoteAddr and returns the response. A
+// non-empty principal is put in the context as APIKeyAuth would.
+func send(h http.Handler, method, path, remoteAddr, principal string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = remoteAddr
+	if principal != "" {
+		req = req.WithContext(context.WithValue(req.Context(), principalKey{}, Principal{Name: principal}))
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimit_BucketExhausted_Returns429WithRetryAfter(t *testing.T) {
+	clock := newTestClock()
+	h := newLimitedHandler(RateLimitConfig{Default: Limit{PerSecond: 0.5, Burst: 3}}, clock)
+
+	for i := 0; i < 3; i++ {
+		rec := send(h, http.MethodGet, "/api/v1/orders", "10.0.0.1:5000", "")
+		require.Equal(t, http.StatusOK, rec.Code, "request %d within burst", i+1)
+	}
+
+	rec := send(h, http.MethodGet, "/api/v1/orders", "10.0.0.1:5000", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"), "one token at 0.5/s")
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "RATE_LIMITED", body["code"])
+	assert.Equal(t, "rate limit exceeded", body["message"])
+
+	clock.advance(time.Second)
+	rec = send(h, http.MethodGet, "/api/v1/orders", "10.0.0.1:5000", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"), "half a token refilled")
+
+	clock.advance(time.Second)
+	rec = send(h, http.MethodGet, "/api/v1/orders", "10.0.0.1:5000", "")
+	assert.Equal(t, http.StatusOK, rec.Code, "refilled after Retry-After")
+}
+
+func TestRateLimit_ClientsHaveSeparateBuckets(t *testing.T) {
+	h := newLimitedHandler(RateLimitConfig{Default: Limit{PerSecond: 1, Burst: 1}}, newTestClock())
+
+	assert.Equal(t, http.StatusOK, send(h, http.MethodGet, "/", "10.0.0.1:5000", "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(h, http.MethodGet, "/", "10.0.0.1:6000", "").Code,
+		"same IP from another port")
+	assert.Equal(t, http.StatusOK, send(h, http.MethodGet, "/", "10.0.0.2:5000", "").Code)
+
+	// A principal identifies the client wherever it connects from
+	assert.Equal(t, http.StatusOK, send(h, http.MethodGet, "/", "10.0.0.1:5000", "checkout").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(h, http.MethodGet, "/", "10.0.0.3:5000", "checkout").Code)
+	assert.Equal(t, http.StatusOK, send(h, http.MethodGet, "/", "10.0.0.3:5000", "reporting").Code)
+}
+
+func TestRateLimit_UnauthenticatedHeaders_DoNotPickTheBucket(t *testing.T) {
+	h := newLimitedHandler(RateLimitConfig{Default: Limit{PerSecond: 1, Burst: 1}}, newTestClock())
+
+	for i, key := range []string{"random-1", "random-2"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "10.0.0.1:5000"
+		req.Header.Set("X-API-Key", key)
+		req.Header.Set("Authorization", "Bearer "+key)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if i == 0 {
+			assert.Equal(t, http.StatusOK, rec.Code)
+		} else {
+			assert.Equal(t, http.StatusTooManyRequests, rec.Code, "a new key from the same IP shares its bucket")
+		}
+	}
+}
+
+func TestRateLimit_AfterAPIKeyAuth_LimitsPerPrincipal(t *testing.T) {
+	keys := NewStaticKeys(map[string]Principal{
+		"k1": {Name: "checkout", Scope: ScopeWrite},
+		"k2": {Name: "checkout", Scope: ScopeWrite},
+	})
+	limited := newLimitedHandler(RateLimitConfig{Default: Limit{PerSecond: 1, Burst: 1}}, newTestClock())
+	h := APIKeyAuth(keys, slog.New(slog.DiscardHandler))(limited)
+
+	status := func(remoteAddr, key string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("Authorization", "Bearer "+key)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, status("10.0.0.1:5000", "k1"))
+	assert.Equal(t, http.StatusTooManyRequests, status("10.0.0.2:5000", "k2"), "both keys are the same principal")
+}
+
+func TestRateLimit_RouteLimit_StricterThanDefault(t *testing.T) {
+	h := newLimitedHandler(RateLimitConfig{
+		Default: Limit{PerSecond: 1, Burst: 3},
+		Routes: []RouteLimit{
+			{Method: http.MethodPost, Pattern: "/api/v1/orders", Limit: Limit{PerSecond: 1, Burst: 1}},
+		},
+	}, newTestClock())
+	const client = "10.0.0.1:5000"
+
+	assert.Equal(t, http.StatusOK, send(h, http.MethodPost, "/api/v1/orders", client, "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(h, http.MethodPost, "/api/v1/orders/", client, "").Code,
+		"trailing slash is the same route")
+
+	// Other routes draw on the default buckets
+	for _, id := range []string{"a", "b", "c"} {
+		assert.Equal(t, http.StatusOK, send(h, http.MethodGet, "/api/v1/orders/"+id, client, "").Code)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, send(h, http.MethodPost, "/api/v1/orders/a/cancel", client, "").Code)
+}
+
+func TestRateLimit_RoutePattern_MatchesParameters(t *testing.T) {
+	h := newLimitedHandler(RateLimitConfig{
+		Routes: []RouteLimit{
+			{Method: http.MethodGet, Pattern: "/api/v1/orders/{id}", Limit: Limit{PerSecond: 1, Burst: 1}},
+		},
+	}, newTestClock())
+	const client = "10.0.0.1:5000"
+
+	assert.Equal(t, http.StatusOK, send(h, http.MethodGet, "/api/v1/orders/a", client, "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(h, http.MethodGet, "/api/v1/orders/b", client, "").Code)
+
+	// The default limit is disabled, so unmatched routes are unlimited
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusOK, send(h, http.MethodGet, "/api/v1/orders", client, "").Code)
+		assert.Equal(t, http.StatusOK, send(h, http.MethodGet, "/api/v1/orders/a/items", client, "").Code)
+	}
+}
+
+func TestRateLimit_Concurrent_AllowsExactlyBurst(t *testing.T) {
+	h := newLimitedHandler(RateLimitConfig{Default: Limit{PerSecond: 1, Burst: 10}}, newTestClock())
+
+	var allowed, limited atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			switch send(h, http.MethodPost, "/api/v1/orders", "10.0.0.1:5000", "").Code {
+			case http.StatusOK:
+				allowed.Add(1)
+			case http.StatusTooManyRequests:
+				limited.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(10), allowed.Load())
+	assert.Equal(t, int32(90), limited.Load())
+}
+
+func TestRateLimit_Sweep_DropsRefilledBuckets(t *testing.T) {
+	clock := newTestClock()
+	l := newRateLimiter(RateLimitConfig{Default: Limit{PerSecond: 1, Burst: 2}}, clock.Now)
+
+	l.def.take("ip:10.0.0.1")
+	clock.advance(sweepInterval - time.Second)
+	l.def.take("ip:10.0.0.2")
+	clock.advance(time.Second)
+	l.def.take("ip:10.0.0.3")
+
+	assert.Len(t, l.def.clients, 2, "only the bucket idle long enough to refill is dropped")
+	assert.NotContains(t, l.def.clients, "ip:10.0.0.1")
+}
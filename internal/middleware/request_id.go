@@ -19,24 +19,53 @@ import (
 	"net/http"
 
 	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
 )
 
-type contextKey string
+// HeaderRequestID carries the request ID on requests and responses.
+const HeaderRequestID = "X-Request-ID"
 
-const requestIDKey contextKey = "requestID"
+// maxRequestIDLen bounds client-supplied request IDs, which are copied
+// into logs and published events.
+const maxRequestIDLen = 128
 
-// RequestID returns a middleware that adds a request ID to the context
+// RequestID returns a middleware that adds a request ID to the context.
+// It keeps the client's X-Request-ID, or generates one when it is missing
+// or unusable, and echoes it in the response. The ID is stored with
+// messaging.WithRequestID, so events published while serving the request
+// carry it as their correlation ID.
 func RequestID() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			requestID := r.Header.Get("X-Request-ID")
-			if requestID == "" {
+			requestID := r.Header.Get(HeaderRequestID)
+			if !validRequestID(requestID) {
 				requestID = uuid.New().String()
 			}
 
-			w.Header().Set("X-Request-ID", requestID)
-			ctx := context.WithValue(r.Context(), requestIDKey, requestID)
+			w.Header().Set(HeaderRequestID, requestID)
+			ctx := messaging.WithRequestID(r.Context(), requestID)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// GetRequestID returns the request ID set by RequestID, or "" outside a
+// request.
+func GetRequestID(ctx context.Context) string {
+	id, _ := messaging.RequestID(ctx)
+	return id
+}
+
+// validRequestID reports whether id is non-empty, not too long, and only
+// printable ASCII.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveWithRequestID serves a request with the given X-Request-ID through
+// RequestID and returns the response and the ID the handler saw.
+func serveWithRequestID(header string) (*httptest.ResponseRecorder, string) {
+	var seen string
+	h := RequestID()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seen = GetRequestID(r.Context())
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil)
+	if header != "" {
+		req.Header.Set(HeaderRequestID, header)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec, seen
+}
+
+func TestRequestID_ClientHeader_PropagatedToContextAndResponse(t *testing.T) {
+	rec, seen := serveWithRequestID("req-abc")
+
+	assert.Equal(t, "req-abc", seen)
+	assert.Equal(t, "req-abc", rec.Header().Get(HeaderRequestID))
+}
+
+func TestRequestID_MissingOrInvalidHeader_GeneratesID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+	}{
+		{"missing", ""},
+		{"too_long", strings.Repeat("a", maxRequestIDLen+1)},
+		{"control_characters", "req\nforged log line"},
+		{"spaces", "req abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, seen := serveWithRequestID(tt.header)
+
+			_, err := uuid.Parse(seen)
+			require.NoError(t, err, "a UUID is generated")
+			assert.Equal(t, seen, rec.Header().Get(HeaderRequestID))
+		})
+	}
+}
+
+func TestGetRequestID_OutsideRequest_ReturnsEmpty(t *testing.T) {
+	assert.Empty(t, GetRequestID(httptest.NewRequest(http.MethodGet, "/", nil).Context()))
+}
+
+func TestLogging_LogsRequestAsJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	h := RequestID()(Logging(logger)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", nil)
+	req.Header.Set(HeaderRequestID, "req-abc")
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "request completed", line["msg"])
+	assert.Equal(t, http.MethodPost, line["method"])
+	assert.Equal(t, "/api/v1/orders", line["path"])
+	assert.EqualValues(t, http.StatusCreated, line["status"])
+	assert.Contains(t, line, "duration")
+	assert.Equal(t, "req-abc", line["request_id"])
+}
@@ -4,13 +4,18 @@ import (
 	"context"
 
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
 )
 
 // EventPublisherMock is a mock implementation of EventPublisher
 type EventPublisherMock struct {
 	PublishOrderCreatedFunc       func(ctx context.Context, order *domain.Order) error
-	PublishOrderUpdatedFunc       func(ctx context.Context, order *domain.Order) error
+	PublishOrderUpdatedFunc       func(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error
 	PublishOrderStatusChangedFunc func(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error
+	PublishOrderCancelledFunc     func(ctx context.Context, order *domain.Order, reason string) error
+	PublishOrderDeletedFunc       func(ctx context.Context, order *domain.Order) error
+	PublishOrderExpiredFunc       func(ctx context.Context, order *domain.Order) error
+	PublishOrderSnapshotFunc      func(ctx context.Context, order *domain.Order) error
 }
 
 // PublishOrderCreated delegates to PublishOrderCreatedFunc if set.
@@ -22,9 +27,9 @@ func (m *EventPublisherMock) PublishOrderCreated(ctx context.Context, order *dom
 }
 
 // PublishOrderUpdated delegates to PublishOrderUpdatedFunc if set.
-func (m *EventPublisherMock) PublishOrderUpdated(ctx context.Context, order *domain.Order) error {
+func (m *EventPublisherMock) PublishOrderUpdated(ctx context.Context, order *domain.Order, changes map[string]messaging.ChangePair) error {
 	if m.PublishOrderUpdatedFunc != nil {
-		return m.PublishOrderUpdatedFunc(ctx, order)
+		return m.PublishOrderUpdatedFunc(ctx, order, changes)
 	}
 	return nil
 }
@@ -36,3 +41,35 @@ func (m *EventPublisherMock) PublishOrderStatusChanged(ctx context.Context, orde
 	}
 	return nil
 }
+
+// PublishOrderCancelled delegates to PublishOrderCancelledFunc if set.
+func (m *EventPublisherMock) PublishOrderCancelled(ctx context.Context, order *domain.Order, reason string) error {
+	if m.PublishOrderCancelledFunc != nil {
+		return m.PublishOrderCancelledFunc(ctx, order, reason)
+	}
+	return nil
+}
+
+// PublishOrderDeleted delegates to PublishOrderDeletedFunc if set.
+func (m *EventPublisherMock) PublishOrderDeleted(ctx context.Context, order *domain.Order) error {
+	if m.PublishOrderDeletedFunc != nil {
+		return m.PublishOrderDeletedFunc(ctx, order)
+	}
+	return nil
+}
+
+// PublishOrderExpired delegates to PublishOrderExpiredFunc if set.
+func (m *EventPublisherMock) PublishOrderExpired(ctx context.Context, order *domain.Order) error {
+	if m.PublishOrderExpiredFunc != nil {
+		return m.PublishOrderExpiredFunc(ctx, order)
+	}
+	return nil
+}
+
+// PublishOrderSnapshot delegates to PublishOrderSnapshotFunc if set.
+func (m *EventPublisherMock) PublishOrderSnapshot(ctx context.Context, order *domain.Order) error {
+	if m.PublishOrderSnapshotFunc != nil {
+		return m.PublishOrderSnapshotFunc(ctx, order)
+	}
+	return nil
+}
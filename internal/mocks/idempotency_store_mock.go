@@ -0,0 +1,53 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/cache"
+)
+
+// IdempotencyStoreMock is a mock implementation of IdempotencyStore
+type IdempotencyStoreMock struct {
+	ReserveFunc  func(ctx context.Context, key, requestHash string, ttl time.Duration) (*cache.IdempotencyRecord, error)
+	CompleteFunc func(ctx context.Context, key string, rec cache.IdempotencyRecord, ttl time.Duration) error
+	ReleaseFunc  func(ctx context.Context, key string) error
+}
+
+// Reserve claims an idempotency key.
+func (m *IdempotencyStoreMock) Reserve(ctx context.Context, key, requestHash string, ttl time.Duration) (*cache.IdempotencyRecord, error) {
+	if m.ReserveFunc != nil {
+		return m.ReserveFunc(ctx, key, requestHash, ttl)
+	}
+	return nil, nil
+}
+
+// Complete records the order created for a key.
+func (m *IdempotencyStoreMock) Complete(ctx context.Context, key string, rec cache.IdempotencyRecord, ttl time.Duration) error {
+	if m.CompleteFunc != nil {
+		return m.CompleteFunc(ctx, key, rec, ttl)
+	}
+	return nil
+}
+
+// Release frees an idempotency key.
+func (m *IdempotencyStoreMock) Release(ctx context.Context, key string) error {
+	if m.ReleaseFunc != nil {
+		return m.ReleaseFunc(ctx, key)
+	}
+	return nil
+}
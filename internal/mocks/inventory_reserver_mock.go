@@ -0,0 +1,43 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+)
+
+// InventoryReserverMock is a mock implementation of InventoryReserver
+type InventoryReserverMock struct {
+	ReserveFunc func(ctx context.Context, lines []domain.OrderItem) error
+	ReleaseFunc func(ctx context.Context, lines []domain.OrderItem) error
+}
+
+// Reserve delegates to ReserveFunc if set.
+func (m *InventoryReserverMock) Reserve(ctx context.Context, lines []domain.OrderItem) error {
+	if m.ReserveFunc != nil {
+		return m.ReserveFunc(ctx, lines)
+	}
+	return nil
+}
+
+// Release delegates to ReleaseFunc if set.
+func (m *InventoryReserverMock) Release(ctx context.Context, lines []domain.OrderItem) error {
+	if m.ReleaseFunc != nil {
+		return m.ReleaseFunc(ctx, lines)
+	}
+	return nil
+}
@@ -16,6 +16,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
@@ -23,12 +24,21 @@ import (
 
 // OrderRepositoryMock is a mock implementation of OrderRepository
 type OrderRepositoryMock struct {
-	CreateFunc           func(ctx context.Context, order *domain.Order) error
-	FindByIDFunc         func(ctx context.Context, id string) (*domain.Order, error)
-	UpdateFunc           func(ctx context.Context, order *domain.Order) error
-	DeleteFunc           func(ctx context.Context, id string) error
-	ListFunc             func(ctx context.Context, opts repository.ListOptions) ([]*domain.Order, int64, error)
-	FindByCustomerIDFunc func(ctx context.Context, customerID string, opts repository.ListOptions) ([]*domain.Order, int64, error)
+	CreateFunc                   func(ctx context.Context, order *domain.Order) error
+	BulkCreateFunc               func(ctx context.Context, orders []*domain.Order) error
+	FindByIDFunc                 func(ctx context.Context, id string) (*domain.Order, error)
+	FindByIDIncludingDeletedFunc func(ctx context.Context, id string) (*domain.Order, error)
+	UpdateFunc                   func(ctx context.Context, order *domain.Order) error
+	UpdateStatusFunc             func(ctx context.Context, order *domain.Order, change domain.StatusChange) error
+	GetHistoryFunc               func(ctx context.Context, orderID string) ([]domain.StatusChange, error)
+	DeleteFunc                   func(ctx context.Context, order *domain.Order) error
+	RestoreFunc                  func(ctx context.Context, order *domain.Order) error
+	ListFunc                     func(ctx context.Context, opts repository.ListOptions) ([]*domain.Order, int64, error)
+	FindByCustomerIDFunc         func(ctx context.Context, customerID string, opts repository.ListOptions) ([]*domain.Order, int64, error)
+
+	ClaimPendingCreatedBeforeFunc func(ctx context.Context, cutoff time.Time, limit int) ([]*domain.Order, error)
+
+	LockCustomerAndCountOpenFunc func(ctx context.Context, customerID string) (int, error)
 }
 
 // Create delegates to CreateFunc if set.
@@ -39,6 +49,14 @@ func (m *OrderRepositoryMock) Create(ctx context.Context, order *domain.Order) e
 	return nil
 }
 
+// BulkCreate delegates to BulkCreateFunc if set.
+func (m *OrderRepositoryMock) BulkCreate(ctx context.Context, orders []*domain.Order) error {
+	if m.BulkCreateFunc != nil {
+		return m.BulkCreateFunc(ctx, orders)
+	}
+	return nil
+}
+
 // FindByID delegates to FindByIDFunc if set.
 func (m *OrderRepositoryMock) FindByID(ctx context.Context, id string) (*domain.Order, error) {
 	if m.FindByIDFunc != nil {
@@ -47,6 +65,14 @@ func (m *OrderRepositoryMock) FindByID(ctx context.Context, id string) (*domain.
 	return nil, nil
 }
 
+// FindByIDIncludingDeleted delegates to FindByIDIncludingDeletedFunc if set.
+func (m *OrderRepositoryMock) FindByIDIncludingDeleted(ctx context.Context, id string) (*domain.Order, error) {
+	if m.FindByIDIncludingDeletedFunc != nil {
+		return m.FindByIDIncludingDeletedFunc(ctx, id)
+	}
+	return nil, nil
+}
+
 // Update delegates to UpdateFunc if set.
 func (m *OrderRepositoryMock) Update(ctx context.Context, order *domain.Order) error {
 	if m.UpdateFunc != nil {
@@ -55,10 +81,35 @@ func (m *OrderRepositoryMock) Update(ctx context.Context, order *domain.Order) e
 	return nil
 }
 
+// UpdateStatus delegates to UpdateStatusFunc if set, and otherwise to
+// Update.
+func (m *OrderRepositoryMock) UpdateStatus(ctx context.Context, order *domain.Order, change domain.StatusChange) error {
+	if m.UpdateStatusFunc != nil {
+		return m.UpdateStatusFunc(ctx, order, change)
+	}
+	return m.Update(ctx, order)
+}
+
+// GetHistory delegates to GetHistoryFunc if set.
+func (m *OrderRepositoryMock) GetHistory(ctx context.Context, orderID string) ([]domain.StatusChange, error) {
+	if m.GetHistoryFunc != nil {
+		return m.GetHistoryFunc(ctx, orderID)
+	}
+	return nil, nil
+}
+
 // Delete delegates to DeleteFunc if set.
-func (m *OrderRepositoryMock) Delete(ctx context.Context, id string) error {
+func (m *OrderRepositoryMock) Delete(ctx context.Context, order *domain.Order) error {
 	if m.DeleteFunc != nil {
-		return m.DeleteFunc(ctx, id)
+		return m.DeleteFunc(ctx, order)
+	}
+	return nil
+}
+
+// Restore delegates to RestoreFunc if set.
+func (m *OrderRepositoryMock) Restore(ctx context.Context, order *domain.Order) error {
+	if m.RestoreFunc != nil {
+		return m.RestoreFunc(ctx, order)
 	}
 	return nil
 }
@@ -78,3 +129,19 @@ func (m *OrderRepositoryMock) FindByCustomerID(ctx context.Context, customerID s
 	}
 	return nil, 0, nil
 }
+
+// ClaimPendingCreatedBefore delegates to ClaimPendingCreatedBeforeFunc if set.
+func (m *OrderRepositoryMock) ClaimPendingCreatedBefore(ctx context.Context, cutoff time.Time, limit int) ([]*domain.Order, error) {
+	if m.ClaimPendingCreatedBeforeFunc != nil {
+		return m.ClaimPendingCreatedBeforeFunc(ctx, cutoff, limit)
+	}
+	return nil, nil
+}
+
+// LockCustomerAndCountOpen delegates to LockCustomerAndCountOpenFunc if set.
+func (m *OrderRepositoryMock) LockCustomerAndCountOpen(ctx context.Context, customerID string) (int, error) {
+	if m.LockCustomerAndCountOpenFunc != nil {
+		return m.LockCustomerAndCountOpenFunc(ctx, customerID)
+	}
+	return 0, nil
+}
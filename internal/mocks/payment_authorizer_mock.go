@@ -0,0 +1,43 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+)
+
+// PaymentAuthorizerMock is a mock implementation of PaymentAuthorizer
+type PaymentAuthorizerMock struct {
+	AuthorizeFunc func(ctx context.Context, order *domain.Order) (string, error)
+	VoidFunc      func(ctx context.Context, order *domain.Order, authorizationID string) error
+}
+
+// Authorize delegates to AuthorizeFunc if set.
+func (m *PaymentAuthorizerMock) Authorize(ctx context.Context, order *domain.Order) (string, error) {
+	if m.AuthorizeFunc != nil {
+		return m.AuthorizeFunc(ctx, order)
+	}
+	return "", nil
+}
+
+// Void delegates to VoidFunc if set.
+func (m *PaymentAuthorizerMock) Void(ctx context.Context, order *domain.Order, authorizationID string) error {
+	if m.VoidFunc != nil {
+		return m.VoidFunc(ctx, order, authorizationID)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocks
+
+import "context"
+
+// TransactorMock is a mock implementation of Transactor.
+// By default it calls fn directly without a real transaction.
+type TransactorMock struct {
+	WithinTxFunc func(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// WithinTx delegates to WithinTxFunc if set.
+func (m *TransactorMock) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if m.WithinTxFunc != nil {
+		return m.WithinTxFunc(ctx, fn)
+	}
+	return fn(ctx)
+}
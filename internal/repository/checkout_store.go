@@ -0,0 +1,37 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+)
+
+// CheckoutStore persists the progress of checkouts, so that one
+// interrupted by a crash can be resumed.
+type CheckoutStore interface {
+	// Save inserts checkout, or replaces the stored checkout with its ID.
+	Save(ctx context.Context, checkout *domain.Checkout) error
+
+	// FindByID retrieves a checkout by its ID, returning nil if there is
+	// none.
+	FindByID(ctx context.Context, id uuid.UUID) (*domain.Checkout, error)
+
+	// ListUnfinished returns up to limit checkouts whose step is not
+	// final, oldest first.
+	ListUnfinished(ctx context.Context, limit int) ([]*domain.Checkout, error)
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import "context"
+
+// Consistency is how up to date the data a read returns must be.
+type Consistency int
+
+const (
+	// Strong reads see every committed write, so they are served by the
+	// primary database. A read whose context sets no consistency is strong.
+	Strong Consistency = iota
+	// Eventual reads may miss recent writes, so that they can be served by
+	// a read replica.
+	Eventual
+)
+
+type consistencyKey struct{}
+
+// WithConsistency returns a copy of ctx with which repositories serve
+// reads at consistency c. A repository without replicas reads from the
+// primary whatever c is.
+func WithConsistency(ctx context.Context, c Consistency) context.Context {
+	return context.WithValue(ctx, consistencyKey{}, c)
+}
+
+// ConsistencyFrom returns the consistency set on ctx with WithConsistency,
+// and whether one was set. It returns Strong if none was.
+func ConsistencyFrom(ctx context.Context) (Consistency, bool) {
+	c, ok := ctx.Value(consistencyKey{}).(Consistency)
+	return c, ok
+}
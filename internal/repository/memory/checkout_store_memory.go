@@ -0,0 +1,81 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
+)
+
+var _ repository.CheckoutStore = (*CheckoutStore)(nil)
+
+// CheckoutStore implements repository.CheckoutStore in memory. It stores
+// and returns copies, so callers never share its state.
+type CheckoutStore struct {
+	mu        sync.Mutex
+	checkouts map[uuid.UUID]*domain.Checkout
+}
+
+// NewCheckoutStore creates an empty in-memory checkout store
+func NewCheckoutStore() *CheckoutStore {
+	return &CheckoutStore{checkouts: make(map[uuid.UUID]*domain.Checkout)}
+}
+
+func (s *CheckoutStore) Save(_ context.Context, checkout *domain.Checkout) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkouts[checkout.ID] = cloneCheckout(checkout)
+	return nil
+}
+
+func (s *CheckoutStore) FindByID(_ context.Context, id uuid.UUID) (*domain.Checkout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, ok := s.checkouts[id]
+	if !ok {
+		return nil, nil
+	}
+	return cloneCheckout(stored), nil
+}
+
+func (s *CheckoutStore) ListUnfinished(_ context.Context, limit int) ([]*domain.Checkout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkouts := []*domain.Checkout{}
+	for _, stored := range s.checkouts {
+		if !stored.Step.IsFinal() {
+			checkouts = append(checkouts, cloneCheckout(stored))
+		}
+	}
+	slices.SortFunc(checkouts, func(a, b *domain.Checkout) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), slices.Compare(a.ID[:], b.ID[:]))
+	})
+	return checkouts[:min(max(limit, 0), len(checkouts))], nil
+}
+
+// cloneCheckout returns a deep copy of checkout
+func cloneCheckout(checkout *domain.Checkout) *domain.Checkout {
+	c := *checkout
+	if checkout.Order != nil {
+		c.Order = clone(checkout.Order)
+	}
+	return &c
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCheckout(step domain.CheckoutStep, createdAt time.Time) *domain.Checkout {
+	order := newOrder("cust-1", createdAt)
+	order.ID = uuid.New()
+	return &domain.Checkout{ID: order.ID, Step: step, Order: order, CreatedAt: createdAt, UpdatedAt: createdAt}
+}
+
+func TestCheckoutStore_SaveAndFind_Copies(t *testing.T) {
+	ctx := context.Background()
+	store := NewCheckoutStore()
+	checkout := newCheckout(domain.CheckoutStarted, time.Now())
+	require.NoError(t, store.Save(ctx, checkout))
+
+	checkout.Step = domain.CheckoutReserved
+	checkout.Order.Items[0].Quantity = 9
+	got, err := store.FindByID(ctx, checkout.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutStarted, got.Step, "the stored checkout is not shared")
+	assert.Equal(t, 2, got.Order.Items[0].Quantity)
+
+	got.Order.Items[0].Quantity = 7
+	again, err := store.FindByID(ctx, checkout.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, again.Order.Items[0].Quantity)
+
+	missing, err := store.FindByID(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestCheckoutStore_ListUnfinished_OldestFirstSkippingFinal(t *testing.T) {
+	ctx := context.Background()
+	store := NewCheckoutStore()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	newer := newCheckout(domain.CheckoutAuthorized, base.Add(2*time.Minute))
+	older := newCheckout(domain.CheckoutStarted, base)
+	middle := newCheckout(domain.CheckoutReserved, base.Add(time.Minute))
+	for _, c := range []*domain.Checkout{
+		newer, older, middle,
+		newCheckout(domain.CheckoutCompleted, base.Add(-time.Hour)),
+		newCheckout(domain.CheckoutFailed, base.Add(-time.Hour)),
+	} {
+		require.NoError(t, store.Save(ctx, c))
+	}
+
+	got, err := store.ListUnfinished(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, []uuid.UUID{older.ID, middle.ID, newer.ID}, []uuid.UUID{got[0].ID, got[1].ID, got[2].ID})
+
+	got, err = store.ListUnfinished(ctx, 2)
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
+)
+
+var _ repository.OrderMetricsStore = (*OrderRepository)(nil)
+
+// SumByStatus aggregates the orders of r, so the order.metrics rollup
+// sees the orders stored through it.
+func (r *OrderRepository) SumByStatus(_ context.Context, from, to time.Time) ([]repository.StatusTotal, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	type group struct {
+		status   domain.OrderStatus
+		currency string
+	}
+	sums := make(map[group]*repository.StatusTotal)
+	for _, stored := range r.orders {
+		if stored.DeletedAt != nil || stored.CreatedAt.Before(from) || !stored.CreatedAt.Before(to) {
+			continue
+		}
+		g := group{stored.Status, stored.Total.Currency}
+		t, ok := sums[g]
+		if !ok {
+			t = &repository.StatusTotal{Status: g.status, Currency: g.currency}
+			sums[g] = t
+		}
+		t.Count++
+		t.TotalMinor += stored.Total.Amount
+	}
+
+	var totals []repository.StatusTotal
+	for _, t := range sums {
+		totals = append(totals, *t)
+	}
+	slices.SortFunc(totals, func(a, b repository.StatusTotal) int {
+		return cmp.Or(cmp.Compare(a.Status, b.Status), cmp.Compare(a.Currency, b.Currency))
+	})
+	return totals, nil
+}
+
+// ClaimWindow claims the window starting at start. A claim made in a
+// transaction is undone if the transaction fails.
+func (r *OrderRepository) ClaimWindow(ctx context.Context, start, _ time.Time) (bool, error) {
+	claimed := false
+	err := r.write(ctx, func() error {
+		if _, ok := r.windows[start.UnixNano()]; !ok {
+			r.windows[start.UnixNano()] = struct{}{}
+			claimed = true
+		}
+		return nil
+	})
+	return claimed, err
+}
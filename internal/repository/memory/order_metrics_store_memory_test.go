@@ -0,0 +1,98 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository_SumByStatus_SeededWindow(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository()
+	start := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	seed := func(createdAt time.Time, status domain.OrderStatus, total domain.Money) *domain.Order {
+		order := newOrder("cust-1", createdAt)
+		order.Status, order.Total = status, total
+		require.NoError(t, repo.Create(ctx, order))
+		return order
+	}
+	usd := func(minor int64) domain.Money { return domain.Money{Amount: minor, Currency: "USD"} }
+
+	seed(start, domain.OrderStatusPending, usd(1000)) // Start is inclusive
+	seed(start.Add(10*time.Minute), domain.OrderStatusPending, usd(2550))
+	seed(start.Add(20*time.Minute), domain.OrderStatusPending, domain.Money{Amount: 700, Currency: "EUR"})
+	seed(start.Add(30*time.Minute), domain.OrderStatusConfirmed, usd(4000))
+	seed(start.Add(-time.Nanosecond), domain.OrderStatusPending, usd(99)) // Before the window
+	seed(end, domain.OrderStatusConfirmed, usd(99))                       // End is exclusive
+	deleted := seed(start.Add(40*time.Minute), domain.OrderStatusCancelled, usd(99))
+	require.NoError(t, repo.Delete(ctx, deletedAt(deleted, end)))
+
+	totals, err := repo.SumByStatus(ctx, start, end)
+
+	require.NoError(t, err)
+	assert.Equal(t, []repository.StatusTotal{
+		{Status: domain.OrderStatusConfirmed, Currency: "USD", Count: 1, TotalMinor: 4000},
+		{Status: domain.OrderStatusPending, Currency: "EUR", Count: 1, TotalMinor: 700},
+		{Status: domain.OrderStatusPending, Currency: "USD", Count: 2, TotalMinor: 3550},
+	}, totals)
+}
+
+func TestOrderRepository_SumByStatus_EmptyWindow_ReturnsNone(t *testing.T) {
+	repo := NewOrderRepository()
+	require.NoError(t, repo.Create(context.Background(), newOrder("cust-1", time.Now())))
+
+	totals, err := repo.SumByStatus(context.Background(), time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))
+
+	require.NoError(t, err)
+	assert.Empty(t, totals)
+}
+
+func TestOrderRepository_ClaimWindow_OnlyOnceUnlessRolledBack(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository()
+	tx := NewTransactor(repo)
+	start := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	errPublish := errors.New("publish failed")
+
+	err := tx.WithinTx(ctx, func(ctx context.Context) error {
+		claimed, err := repo.ClaimWindow(ctx, start, end)
+		require.NoError(t, err)
+		assert.True(t, claimed)
+		return errPublish
+	})
+	require.ErrorIs(t, err, errPublish)
+
+	claimed, err := repo.ClaimWindow(ctx, start, end)
+	require.NoError(t, err)
+	assert.True(t, claimed, "the rolled back claim freed the window")
+
+	claimed, err = repo.ClaimWindow(ctx, start, end)
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	claimed, err = repo.ClaimWindow(ctx, end, end.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, claimed, "the next window")
+}
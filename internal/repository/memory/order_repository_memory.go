@@ -0,0 +1,381 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package memory implements the repository interfaces in memory, with the
+// semantics of the PostgreSQL implementation, for fast service tests and
+// local runs without a database.
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
+)
+
+var _ repository.OrderRepository = (*OrderRepository)(nil)
+
+// errNoTx is returned by methods whose locks only last as long as a
+// transaction when called without one.
+var errNoTx = errors.New("memory: must be called within a transaction")
+
+// OrderRepository implements repository.OrderRepository in memory. Like
+// the PostgreSQL repository it versions every change, soft-deletes, lists
+// newest first with keyset pagination, and returns copies, so callers
+// never share its state. Pair it with NewTransactor for transactions.
+type OrderRepository struct {
+	ids domain.IDGenerator
+
+	txMu sync.Mutex // Held by the open transaction, and by writes outside one
+
+	mu      sync.Mutex
+	orders  map[uuid.UUID]*domain.Order // Replaced, never changed, once stored
+	history map[uuid.UUID][]domain.StatusChange
+	windows map[int64]struct{} // Claimed metrics windows, by start in Unix nanoseconds
+}
+
+// Option configures an OrderRepository
+type Option func(*OrderRepository)
+
+// WithIDGenerator sets the generator of the IDs given to orders and items
+// stored without one. Defaults to domain.RandomIDs.
+func WithIDGenerator(g domain.IDGenerator) Option {
+	return func(r *OrderRepository) {
+		r.ids = g
+	}
+}
+
+// NewOrderRepository creates an empty in-memory order repository
+func NewOrderRepository(opts ...Option) *OrderRepository {
+	r := &OrderRepository{
+		ids:     domain.RandomIDs,
+		orders:  make(map[uuid.UUID]*domain.Order),
+		history: make(map[uuid.UUID][]domain.StatusChange),
+		windows: make(map[int64]struct{}),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// assignIDs gives order and its items that have no ID a generated one.
+func (r *OrderRepository) assignIDs(order *domain.Order) {
+	if order.ID == uuid.Nil {
+		order.ID = r.ids.NewID()
+	}
+	for i := range order.Items {
+		if order.Items[i].ID == uuid.Nil {
+			order.Items[i].ID = r.ids.NewID()
+		}
+	}
+}
+
+func (r *OrderRepository) Create(ctx context.Context, order *domain.Order) error {
+	return r.write(ctx, func() error {
+		r.assignIDs(order)
+		if _, ok := r.orders[order.ID]; ok {
+			return fmt.Errorf("memory: order %s already exists", order.ID)
+		}
+		order.Version = 1
+		order.EventSeq = 1
+		r.orders[order.ID] = clone(order)
+		return nil
+	})
+}
+
+func (r *OrderRepository) BulkCreate(ctx context.Context, orders []*domain.Order) error {
+	// Each Create joins this transaction, so one failure rolls back all
+	return r.withinTx(ctx, func(ctx context.Context) error {
+		for i, order := range orders {
+			if err := r.Create(ctx, order); err != nil {
+				return fmt.Errorf("create order %d of batch: %w", i, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (r *OrderRepository) FindByID(_ context.Context, id string) (*domain.Order, error) {
+	return r.find(id, false), nil
+}
+
+func (r *OrderRepository) FindByIDIncludingDeleted(_ context.Context, id string) (*domain.Order, error) {
+	return r.find(id, true), nil
+}
+
+// find returns a copy of the order with id, or nil if there is none
+func (r *OrderRepository) find(id string, includeDeleted bool) *domain.Order {
+	key, err := uuid.Parse(id)
+	if err != nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.orders[key]
+	if !ok || (stored.DeletedAt != nil && !includeDeleted) {
+		return nil
+	}
+	return read(stored)
+}
+
+func (r *OrderRepository) Update(ctx context.Context, order *domain.Order) error {
+	return r.write(ctx, func() error { return r.update(order) })
+}
+
+func (r *OrderRepository) UpdateStatus(ctx context.Context, order *domain.Order, change domain.StatusChange) error {
+	return r.write(ctx, func() error {
+		if err := r.update(order); err != nil {
+			return err
+		}
+		r.history[change.OrderID] = append(r.history[change.OrderID], change)
+		return nil
+	})
+}
+
+// update stores order if its version matches, and bumps its version and
+// event sequence. The currency and creation time are kept, as the
+// PostgreSQL repository keeps them. Callers hold r.mu.
+func (r *OrderRepository) update(order *domain.Order) error {
+	stored, ok := r.orders[order.ID]
+	if !ok {
+		return domain.ErrOrderNotFound
+	}
+	if stored.Version != order.Version || stored.DeletedAt != nil {
+		return domain.ErrVersionConflict
+	}
+
+	r.assignIDs(order)
+	next := clone(order)
+	next.Total.Currency = stored.Total.Currency
+	next.Version = stored.Version + 1
+	next.EventSeq = stored.EventSeq + 1
+	next.CreatedAt = stored.CreatedAt
+	next.DeletedAt = nil
+	r.orders[order.ID] = next
+
+	order.Version++
+	order.EventSeq++
+	return nil
+}
+
+func (r *OrderRepository) GetHistory(_ context.Context, orderID string) ([]domain.StatusChange, error) {
+	key, err := uuid.Parse(orderID)
+	if err != nil {
+		return nil, domain.ErrOrderNotFound
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Soft-deleted orders keep their history
+	if _, ok := r.orders[key]; !ok {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	history := append([]domain.StatusChange{}, r.history[key]...)
+	slices.SortStableFunc(history, func(a, b domain.StatusChange) int {
+		return a.OccurredAt.Compare(b.OccurredAt)
+	})
+	return history, nil
+}
+
+func (r *OrderRepository) Delete(ctx context.Context, order *domain.Order) error {
+	return r.write(ctx, func() error {
+		key := order.ID
+		stored, ok := r.orders[key]
+		if !ok || stored.DeletedAt != nil {
+			return domain.ErrOrderNotFound
+		}
+		next := clone(stored)
+		deletedAt := *order.DeletedAt
+		next.DeletedAt = &deletedAt
+		next.LastEventID = order.LastEventID
+		next.Version++
+		next.EventSeq++
+		r.orders[key] = next
+		return nil
+	})
+}
+
+func (r *OrderRepository) Restore(ctx context.Context, order *domain.Order) error {
+	return r.write(ctx, func() error {
+		key := order.ID
+		stored, ok := r.orders[key]
+		if !ok {
+			return domain.ErrOrderNotFound
+		}
+		if stored.DeletedAt == nil {
+			return domain.ErrOrderNotDeleted
+		}
+		next := clone(stored)
+		next.DeletedAt = nil
+		next.UpdatedAt = order.UpdatedAt
+		next.LastEventID = order.LastEventID
+		next.Version++
+		next.EventSeq++
+		r.orders[key] = next
+		return nil
+	})
+}
+
+func (r *OrderRepository) List(_ context.Context, opts repository.ListOptions) ([]*domain.Order, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var matched []*domain.Order
+	for _, stored := range r.orders {
+		if matches(stored, opts) {
+			matched = append(matched, stored)
+		}
+	}
+	total := int64(len(matched))
+	slices.SortFunc(matched, func(a, b *domain.Order) int {
+		return -compareKeys(a.CreatedAt, a.ID, b.CreatedAt, b.ID)
+	})
+
+	// Keyset pagination: orders strictly after the cursor in sort order
+	offset := opts.Offset
+	if after := opts.After; after != nil {
+		matched = slices.DeleteFunc(matched, func(o *domain.Order) bool {
+			return compareKeys(o.CreatedAt, o.ID, after.CreatedAt, after.ID) >= 0
+		})
+		offset = 0
+	}
+	matched = page(matched, offset, opts.Limit)
+
+	orders := make([]*domain.Order, len(matched))
+	for i, stored := range matched {
+		orders[i] = read(stored)
+	}
+	return orders, total, nil
+}
+
+func (r *OrderRepository) FindByCustomerID(ctx context.Context, customerID string, opts repository.ListOptions) ([]*domain.Order, int64, error) {
+	opts.CustomerID = &customerID
+	return r.List(ctx, opts)
+}
+
+// ClaimPendingCreatedBefore returns the oldest pending orders. The open
+// transaction excludes every other, so claims never overlap.
+func (r *OrderRepository) ClaimPendingCreatedBefore(_ context.Context, cutoff time.Time, limit int) ([]*domain.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var pending []*domain.Order
+	for _, stored := range r.orders {
+		if stored.Status == domain.OrderStatusPending && stored.CreatedAt.Before(cutoff) && stored.DeletedAt == nil {
+			pending = append(pending, stored)
+		}
+	}
+	slices.SortFunc(pending, func(a, b *domain.Order) int {
+		return compareKeys(a.CreatedAt, a.ID, b.CreatedAt, b.ID)
+	})
+	pending = page(pending, 0, limit)
+
+	orders := make([]*domain.Order, len(pending))
+	for i, stored := range pending {
+		orders[i] = read(stored)
+	}
+	return orders, nil
+}
+
+// LockCustomerAndCountOpen counts the customer's open orders. The open
+// transaction excludes every other, which locks the customer too.
+func (r *OrderRepository) LockCustomerAndCountOpen(ctx context.Context, customerID string) (int, error) {
+	if !r.inTx(ctx) {
+		return 0, errNoTx
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, stored := range r.orders {
+		if stored.CustomerID == customerID && stored.DeletedAt == nil && !stored.Status.IsTerminal() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// matches reports whether stored is live and matches every filter set in
+// opts
+func matches(stored *domain.Order, opts repository.ListOptions) bool {
+	switch {
+	case stored.DeletedAt != nil:
+		return false
+	case opts.CustomerID != nil && stored.CustomerID != *opts.CustomerID:
+		return false
+	case opts.Status != nil && stored.Status != *opts.Status:
+		return false
+	case opts.CreatedAfter != nil && !stored.CreatedAt.After(*opts.CreatedAfter):
+		return false
+	case opts.CreatedBefore != nil && !stored.CreatedAt.Before(*opts.CreatedBefore):
+		return false
+	case opts.MinTotal != nil && stored.Total.Amount < opts.MinTotal.Amount:
+		return false
+	case opts.MaxTotal != nil && stored.Total.Amount > opts.MaxTotal.Amount:
+		return false
+	}
+	if bound := cmp.Or(opts.MinTotal, opts.MaxTotal); bound != nil {
+		return stored.Total.Currency == bound.Currency
+	}
+	return true
+}
+
+// compareKeys orders (created_at, id) pairs as PostgreSQL orders them
+func compareKeys(aCreated time.Time, aID uuid.UUID, bCreated time.Time, bID uuid.UUID) int {
+	if c := aCreated.Compare(bCreated); c != 0 {
+		return c
+	}
+	return bytes.Compare(aID[:], bID[:])
+}
+
+// page returns orders from offset, at most limit of them
+func page(orders []*domain.Order, offset, limit int) []*domain.Order {
+	if offset >= len(orders) {
+		return nil
+	}
+	orders = orders[offset:]
+	return orders[:min(max(limit, 0), len(orders))]
+}
+
+// clone returns a deep copy of order
+func clone(order *domain.Order) *domain.Order {
+	c := *order
+	c.Items = slices.Clone(order.Items)
+	if order.DeletedAt != nil {
+		deletedAt := *order.DeletedAt
+		c.DeletedAt = &deletedAt
+	}
+	return &c
+}
+
+// read returns a copy of stored with its items priced in its currency, as
+// the PostgreSQL repository loads them
+func read(stored *domain.Order) *domain.Order {
+	order := clone(stored)
+	for i := range order.Items {
+		order.Items[i].Price.Currency = order.Total.Currency
+		// Stored orders were totalled when saved, so this cannot overflow
+		order.Items[i].Subtotal, _ = order.Items[i].CalculateSubtotal()
+	}
+	return order
+}
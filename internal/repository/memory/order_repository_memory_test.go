@@ -0,0 +1,310 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newOrder(customerID string, createdAt time.Time) *domain.Order {
+	return &domain.Order{
+		CustomerID: customerID,
+		Items: []domain.OrderItem{
+			{ProductID: "p-1", Name: "Widget", Quantity: 2, Price: domain.Money{Amount: 1000, Currency: "USD"}, Subtotal: domain.Money{Amount: 2000, Currency: "USD"}},
+		},
+		Status:    domain.OrderStatusPending,
+		Total:     domain.Money{Amount: 2000, Currency: "USD"},
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+// deletedAt marks order deleted at at, as the service does before calling
+// Delete.
+func deletedAt(order *domain.Order, at time.Time) *domain.Order {
+	order.DeletedAt = &at
+	return order
+}
+
+func TestOrderRepository_Create_AssignsIDsAndFirstVersion(t *testing.T) {
+	repo := NewOrderRepository()
+	order := newOrder("cust-1", time.Now())
+
+	require.NoError(t, repo.Create(context.Background(), order))
+
+	assert.NotEqual(t, uuid.Nil, order.ID)
+	assert.NotEqual(t, uuid.Nil, order.Items[0].ID)
+	assert.Equal(t, 1, order.Version)
+	assert.Equal(t, int64(1), order.EventSeq)
+	found, err := repo.FindByID(context.Background(), order.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, order, found)
+	assert.Error(t, repo.Create(context.Background(), order), "duplicate ID")
+}
+
+func TestOrderRepository_ReturnsCopies(t *testing.T) {
+	repo := NewOrderRepository()
+	order := newOrder("cust-1", time.Now())
+	require.NoError(t, repo.Create(context.Background(), order))
+
+	order.Items[0].Name = "Changed by the caller"
+	found, err := repo.FindByID(context.Background(), order.ID.String())
+	require.NoError(t, err)
+	found.Status = domain.OrderStatusConfirmed
+
+	again, err := repo.FindByID(context.Background(), order.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "Widget", again.Items[0].Name)
+	assert.Equal(t, domain.OrderStatusPending, again.Status)
+}
+
+func TestOrderRepository_Update_OptimisticLocking(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository()
+	order := newOrder("cust-1", time.Now())
+	require.NoError(t, repo.Create(ctx, order))
+	stale := *order
+
+	order.Status = domain.OrderStatusConfirmed
+	require.NoError(t, repo.Update(ctx, order))
+	assert.Equal(t, 2, order.Version)
+	assert.Equal(t, int64(2), order.EventSeq)
+
+	stale.Status = domain.OrderStatusCancelled
+	assert.ErrorIs(t, repo.Update(ctx, &stale), domain.ErrVersionConflict)
+
+	missing := newOrder("cust-1", time.Now())
+	missing.ID = uuid.New()
+	assert.ErrorIs(t, repo.Update(ctx, missing), domain.ErrOrderNotFound)
+
+	found, err := repo.FindByID(ctx, order.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusConfirmed, found.Status)
+	assert.Equal(t, 2, found.Version)
+}
+
+func TestOrderRepository_UpdateStatus_RecordsHistoryOnlyWhenStored(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository()
+	order := newOrder("cust-1", time.Now())
+	require.NoError(t, repo.Create(ctx, order))
+	change := domain.StatusChange{OrderID: order.ID, OldStatus: domain.OrderStatusPending, NewStatus: domain.OrderStatusConfirmed, OccurredAt: time.Now()}
+
+	order.Status = domain.OrderStatusConfirmed
+	require.NoError(t, repo.UpdateStatus(ctx, order, change))
+	order.Version = 1 // Stale
+	assert.ErrorIs(t, repo.UpdateStatus(ctx, order, change), domain.ErrVersionConflict)
+
+	history, err := repo.GetHistory(ctx, order.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, []domain.StatusChange{change}, history)
+	_, err = repo.GetHistory(ctx, uuid.NewString())
+	assert.ErrorIs(t, err, domain.ErrOrderNotFound)
+}
+
+func TestOrderRepository_DeleteRestore(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository()
+	order := newOrder("cust-1", time.Now())
+	require.NoError(t, repo.Create(ctx, order))
+	id := order.ID.String()
+
+	assert.ErrorIs(t, repo.Restore(ctx, order), domain.ErrOrderNotDeleted)
+	order.LastEventID = "deleted-event"
+	deleteTime := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, repo.Delete(ctx, deletedAt(order, deleteTime)))
+	assert.ErrorIs(t, repo.Delete(ctx, order), domain.ErrOrderNotFound)
+
+	found, err := repo.FindByID(ctx, id)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+	deleted, err := repo.FindByIDIncludingDeleted(ctx, id)
+	require.NoError(t, err)
+	require.NotNil(t, deleted.DeletedAt)
+	assert.Equal(t, deleteTime, *deleted.DeletedAt)
+	assert.Equal(t, 2, deleted.Version)
+	assert.Equal(t, "deleted-event", deleted.LastEventID)
+	assert.ErrorIs(t, repo.Update(ctx, deleted), domain.ErrVersionConflict, "deleted orders cannot be updated")
+
+	order.LastEventID = "restored-event"
+	order.UpdatedAt = deleteTime.Add(time.Hour)
+	require.NoError(t, repo.Restore(ctx, order))
+	restored, err := repo.FindByID(ctx, id)
+	require.NoError(t, err)
+	assert.Nil(t, restored.DeletedAt)
+	assert.Equal(t, deleteTime.Add(time.Hour), restored.UpdatedAt)
+	assert.Equal(t, 3, restored.Version)
+	assert.Equal(t, int64(3), restored.EventSeq)
+	assert.Equal(t, "restored-event", restored.LastEventID)
+	assert.ErrorIs(t, repo.Restore(ctx, &domain.Order{ID: uuid.New()}), domain.ErrOrderNotFound)
+}
+
+func TestOrderRepository_List_NewestFirstWithFilters(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository()
+	t0 := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	var created []*domain.Order
+	for i, customerID := range []string{"cust-1", "cust-2", "cust-1", "cust-1"} {
+		order := newOrder(customerID, t0.Add(time.Duration(i)*time.Minute))
+		order.Total.Amount = int64(1000 * (i + 1))
+		require.NoError(t, repo.Create(ctx, order))
+		created = append(created, order)
+	}
+	require.NoError(t, repo.Delete(ctx, deletedAt(created[3], t0)))
+
+	customerID := "cust-1"
+	orders, total, err := repo.List(ctx, repository.ListOptions{Limit: 10, CustomerID: &customerID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []uuid.UUID{created[2].ID, created[0].ID}, ids(orders))
+
+	after := t0.Add(30 * time.Second)
+	orders, _, err = repo.List(ctx, repository.ListOptions{Limit: 10, CreatedAfter: &after, MinTotal: &domain.Money{Amount: 2500, Currency: "USD"}})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{created[2].ID}, ids(orders))
+
+	orders, _, err = repo.List(ctx, repository.ListOptions{Limit: 10, MaxTotal: &domain.Money{Amount: 5000, Currency: "EUR"}})
+	require.NoError(t, err)
+	assert.Empty(t, orders, "only orders in the bound's currency match")
+}
+
+func TestOrderRepository_List_CursorWithEqualTimestamps_VisitsEachOnce(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository()
+	at := time.Now()
+	for range 7 {
+		require.NoError(t, repo.Create(ctx, newOrder("cust-1", at)))
+	}
+
+	seen := map[uuid.UUID]bool{}
+	opts := repository.ListOptions{Limit: 3}
+	for {
+		orders, total, err := repo.List(ctx, opts)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), total, "the total ignores the cursor")
+		for _, o := range orders {
+			assert.False(t, seen[o.ID], "order %s listed twice", o.ID)
+			seen[o.ID] = true
+		}
+		if len(orders) < opts.Limit {
+			break
+		}
+		cursor := domain.CursorAfter(orders[len(orders)-1])
+		opts.After = &cursor
+	}
+	assert.Len(t, seen, 7)
+}
+
+func TestOrderRepository_ClaimPendingCreatedBefore_OldestFirst(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository()
+	t0 := time.Now().Add(-time.Hour)
+	var created []*domain.Order
+	for i := range 4 {
+		order := newOrder("cust-1", t0.Add(time.Duration(i)*time.Minute))
+		require.NoError(t, repo.Create(ctx, order))
+		created = append(created, order)
+	}
+	created[0].Status = domain.OrderStatusConfirmed
+	require.NoError(t, repo.Update(ctx, created[0]))
+
+	claimed, err := repo.ClaimPendingCreatedBefore(ctx, t0.Add(150*time.Second), 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{created[1].ID, created[2].ID}, ids(claimed))
+}
+
+func TestTransactor_FailedTx_RolledBack(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository()
+	kept := newOrder("cust-1", time.Now())
+	require.NoError(t, repo.Create(ctx, kept))
+
+	var discarded *domain.Order
+	err := NewTransactor(repo).WithinTx(ctx, func(ctx context.Context) error {
+		discarded = newOrder("cust-1", time.Now())
+		require.NoError(t, repo.Create(ctx, discarded))
+		kept.Status = domain.OrderStatusConfirmed
+		require.NoError(t, repo.Update(ctx, kept))
+		return errors.New("boom")
+	})
+
+	require.EqualError(t, err, "boom")
+	found, err := repo.FindByID(ctx, discarded.ID.String())
+	require.NoError(t, err)
+	assert.Nil(t, found)
+	found, err = repo.FindByID(ctx, kept.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, domain.OrderStatusPending, found.Status)
+	assert.Equal(t, 1, found.Version)
+}
+
+func TestOrderRepository_BulkCreate_AllOrNothing(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository()
+	existing := newOrder("cust-1", time.Now())
+	require.NoError(t, repo.Create(ctx, existing))
+	duplicate := newOrder("cust-1", time.Now())
+	duplicate.ID = existing.ID
+
+	err := repo.BulkCreate(ctx, []*domain.Order{newOrder("cust-1", time.Now()), duplicate})
+
+	require.ErrorContains(t, err, "order 1 of batch")
+	_, total, err := repo.List(ctx, repository.ListOptions{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+}
+
+func TestOrderRepository_LockCustomerAndCountOpen(t *testing.T) {
+	ctx := context.Background()
+	repo := NewOrderRepository()
+	statuses := []domain.OrderStatus{domain.OrderStatusPending, domain.OrderStatusShipped, domain.OrderStatusDelivered, domain.OrderStatusCancelled}
+	for _, status := range statuses {
+		order := newOrder("cust-1", time.Now())
+		order.Status = status
+		require.NoError(t, repo.Create(ctx, order))
+	}
+	deleted := newOrder("cust-1", time.Now())
+	require.NoError(t, repo.Create(ctx, deleted))
+	require.NoError(t, repo.Delete(ctx, deletedAt(deleted, time.Now())))
+	require.NoError(t, repo.Create(ctx, newOrder("cust-2", time.Now())))
+
+	_, err := repo.LockCustomerAndCountOpen(ctx, "cust-1")
+	require.ErrorIs(t, err, errNoTx)
+
+	var open int
+	require.NoError(t, NewTransactor(repo).WithinTx(ctx, func(ctx context.Context) error {
+		open, err = repo.LockCustomerAndCountOpen(ctx, "cust-1")
+		return err
+	}))
+	assert.Equal(t, 2, open, "pending and shipped")
+}
+
+func ids(orders []*domain.Order) []uuid.UUID {
+	out := make([]uuid.UUID, len(orders))
+	for i, o := range orders {
+		out[i] = o.ID
+	}
+	return out
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"maps"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
+)
+
+type txKey struct{}
+
+// transactor implements Transactor over one in-memory repository
+type transactor struct {
+	repo *OrderRepository
+}
+
+// NewTransactor creates a transactor for repo. Its transactions run one
+// at a time, and writes to repo outside one wait for it, so a transaction
+// sees no concurrent change; a transaction that fails is rolled back.
+// Reads outside the transaction see its writes before it ends.
+func NewTransactor(repo *OrderRepository) repository.Transactor {
+	return &transactor{repo: repo}
+}
+
+func (t *transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return t.repo.withinTx(ctx, fn)
+}
+
+// inTx reports whether ctx carries a transaction of r
+func (r *OrderRepository) inTx(ctx context.Context) bool {
+	repo, _ := ctx.Value(txKey{}).(*OrderRepository)
+	return repo == r
+}
+
+// withinTx runs fn inside the transaction carried by ctx, starting one if
+// there is none, and restores the state from its start if fn fails.
+func (r *OrderRepository) withinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if r.inTx(ctx) {
+		return fn(ctx)
+	}
+	r.txMu.Lock()
+	defer r.txMu.Unlock()
+
+	// Stored orders are replaced rather than changed, so shallow copies
+	// keep the state
+	r.mu.Lock()
+	orders, history, windows := maps.Clone(r.orders), maps.Clone(r.history), maps.Clone(r.windows)
+	r.mu.Unlock()
+
+	if err := fn(context.WithValue(ctx, txKey{}, r)); err != nil {
+		r.mu.Lock()
+		r.orders, r.history, r.windows = orders, history, windows
+		r.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// write runs fn holding r.mu, waiting for the open transaction unless ctx
+// carries it
+func (r *OrderRepository) write(ctx context.Context, fn func() error) error {
+	if !r.inTx(ctx) {
+		r.txMu.Lock()
+		defer r.txMu.Unlock()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return fn()
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+)
+
+// StatusTotal is the number of orders in one status and currency, and the
+// sum of their totals.
+type StatusTotal struct {
+	Status     domain.OrderStatus
+	Currency   string
+	Count      int64
+	TotalMinor int64 // Sum of the orders' totals in minor units of Currency
+}
+
+// OrderMetricsStore aggregates orders for the periodic order.metrics
+// rollup, and records which windows have been rolled up.
+type OrderMetricsStore interface {
+	// SumByStatus returns, per status and currency, how many orders not
+	// soft-deleted were created in [from, to) and the sum of their totals,
+	// computed in one aggregate query. Only groups with orders are
+	// returned, ordered by status and currency.
+	SumByStatus(ctx context.Context, from, to time.Time) ([]StatusTotal, error)
+
+	// ClaimWindow records that the window [start, end) is being rolled up,
+	// reporting false if it already was. Call it within
+	// Transactor.WithinTx: a claim rolled back with its transaction frees
+	// the window, and a concurrent claim of it waits for the transaction
+	// to end.
+	ClaimWindow(ctx context.Context, start, end time.Time) (bool, error)
+}
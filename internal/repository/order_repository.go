@@ -17,6 +17,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
 )
@@ -24,34 +25,88 @@ import (
 // OrderRepository defines data access operations for orders
 type OrderRepository interface {
 	// Create inserts a new order into the database.
-	// The order.Version is set to 1 on creation.
+	// The order.Version and order.EventSeq are set to 1 on creation.
 	Create(ctx context.Context, order *domain.Order) error
 
-	// FindByID retrieves an order by its ID
+	// BulkCreate inserts orders in one transaction, setting each Version
+	// and EventSeq to 1. If any insert fails, none of the orders are stored.
+	BulkCreate(ctx context.Context, orders []*domain.Order) error
+
+	// FindByID retrieves an order by its ID. Soft-deleted orders are not
+	// returned.
 	FindByID(ctx context.Context, id string) (*domain.Order, error)
 
+	// FindByIDIncludingDeleted retrieves an order by its ID even if it has
+	// been soft-deleted.
+	FindByIDIncludingDeleted(ctx context.Context, id string) (*domain.Order, error)
+
 	// Update updates an existing order using optimistic locking.
 	// The update will only succeed if the order's version matches the database.
-	// On success, the order's version is incremented.
-	// Returns domain.ErrConcurrentModification if version mismatch.
+	// It stores order.UpdatedAt as given, so callers set it first.
+	// On success, the order's version and event sequence are incremented.
+	// Returns domain.ErrVersionConflict if version mismatch.
 	// Returns domain.ErrOrderNotFound if order doesn't exist.
 	Update(ctx context.Context, order *domain.Order) error
 
-	// Delete soft-deletes an order by setting deleted_at timestamp.
-	// Uses optimistic locking - requires order.Version to match.
-	// Returns domain.ErrConcurrentModification if version mismatch.
-	Delete(ctx context.Context, id string) error
+	// UpdateStatus updates order like Update and appends change to the
+	// order's status history in the same transaction, so the history only
+	// records transitions that were stored.
+	UpdateStatus(ctx context.Context, order *domain.Order, change domain.StatusChange) error
+
+	// GetHistory returns the status changes of an order, oldest first.
+	// Returns domain.ErrOrderNotFound if order doesn't exist.
+	GetHistory(ctx context.Context, orderID string) ([]domain.StatusChange, error)
+
+	// Delete soft-deletes order by storing its DeletedAt, which must be
+	// set, and its LastEventID, and increments its version and event
+	// sequence.
+	// Returns domain.ErrOrderNotFound if order doesn't exist or is already
+	// deleted.
+	Delete(ctx context.Context, order *domain.Order) error
 
-	// List returns paginated orders with optional status filter
+	// Restore clears deleted_at on soft-deleted order, stores its
+	// UpdatedAt and LastEventID, and increments its version and event
+	// sequence.
+	// Returns domain.ErrOrderNotDeleted if the order is not deleted.
+	// Returns domain.ErrOrderNotFound if order doesn't exist.
+	Restore(ctx context.Context, order *domain.Order) error
+
+	// List returns paginated orders matching every filter set in opts
 	List(ctx context.Context, opts ListOptions) ([]*domain.Order, int64, error)
 
 	// FindByCustomerID retrieves all orders for a customer
 	FindByCustomerID(ctx context.Context, customerID string, opts ListOptions) ([]*domain.Order, int64, error)
+
+	// ClaimPendingCreatedBefore locks and returns up to limit pending orders
+	// created before cutoff, oldest first, skipping orders locked by another
+	// transaction. Call it within Transactor.WithinTx: the locks are held
+	// until that transaction ends, so concurrent callers claim disjoint
+	// orders.
+	ClaimPendingCreatedBefore(ctx context.Context, cutoff time.Time, limit int) ([]*domain.Order, error)
+
+	// LockCustomerAndCountOpen locks the orders of customerID against
+	// another caller of this method and counts those that are neither in a
+	// terminal status nor deleted. Call it within Transactor.WithinTx: the
+	// lock is held until that transaction ends, so a concurrent caller for
+	// the same customer counts after any order this transaction creates.
+	LockCustomerAndCountOpen(ctx context.Context, customerID string) (int, error)
 }
 
-// ListOptions represents query options for listing orders
+// ListOptions represents query options for listing orders.
+// Filters that are set must all match.
 type ListOptions struct {
-	Limit  int
-	Offset int
-	Status *domain.OrderStatus
+	Limit      int
+	Offset     int
+	Status     *domain.OrderStatus
+	CustomerID *string
+	// CreatedAfter and CreatedBefore bound created_at, both exclusive
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// MinTotal and MaxTotal bound the total, both inclusive. Only orders in
+	// their currency match.
+	MinTotal *domain.Money
+	MaxTotal *domain.Money
+	// After, if set, returns only orders that sort after the cursor and
+	// Offset is ignored (keyset pagination)
+	After *domain.Cursor
 }
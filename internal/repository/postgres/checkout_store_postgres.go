@@ -0,0 +1,122 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
+)
+
+// checkoutStorePostgres implements repository.CheckoutStore using
+// PostgreSQL. The order being checked out is stored as JSON, since it only
+// reaches the orders table once the checkout completes.
+type checkoutStorePostgres struct {
+	pool *pgxpool.Pool
+}
+
+// NewCheckoutStore creates a new PostgreSQL checkout store
+func NewCheckoutStore(pool *pgxpool.Pool) repository.CheckoutStore {
+	return &checkoutStorePostgres{
+		pool: pool,
+	}
+}
+
+func (s *checkoutStorePostgres) Save(ctx context.Context, checkout *domain.Checkout) error {
+	order, err := json.Marshal(checkout.Order)
+	if err != nil {
+		return fmt.Errorf("encode checkout order: %w", err)
+	}
+	query := `
+		INSERT INTO checkouts (id, step, order_data, authorization_id, failure, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (id) DO UPDATE SET
+			step = EXCLUDED.step,
+			order_data = EXCLUDED.order_data,
+			authorization_id = EXCLUDED.authorization_id,
+			failure = EXCLUDED.failure,
+			updated_at = EXCLUDED.updated_at
+	`
+	_, err = conn(ctx, s.pool).Exec(ctx, query,
+		checkout.ID,
+		checkout.Step,
+		order,
+		checkout.AuthorizationID,
+		checkout.Failure,
+		checkout.CreatedAt,
+		checkout.UpdatedAt,
+	)
+	return err
+}
+
+func (s *checkoutStorePostgres) FindByID(ctx context.Context, id uuid.UUID) (*domain.Checkout, error) {
+	query := `
+		SELECT id, step, order_data, authorization_id, failure, created_at, updated_at
+		FROM checkouts
+		WHERE id = $1
+	`
+	checkout, err := scanCheckout(conn(ctx, s.pool).QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return checkout, err
+}
+
+func (s *checkoutStorePostgres) ListUnfinished(ctx context.Context, limit int) ([]*domain.Checkout, error) {
+	query := `
+		SELECT id, step, order_data, authorization_id, failure, created_at, updated_at
+		FROM checkouts
+		WHERE step NOT IN ($1, $2)
+		ORDER BY created_at, id
+		LIMIT $3
+	`
+	rows, err := conn(ctx, s.pool).Query(ctx, query, domain.CheckoutCompleted, domain.CheckoutFailed, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checkouts := []*domain.Checkout{}
+	for rows.Next() {
+		checkout, err := scanCheckout(rows)
+		if err != nil {
+			return nil, err
+		}
+		checkouts = append(checkouts, checkout)
+	}
+	return checkouts, rows.Err()
+}
+
+// scanCheckout scans a checkouts row selected in column order.
+func scanCheckout(row pgx.Row) (*domain.Checkout, error) {
+	var (
+		c     domain.Checkout
+		order []byte
+	)
+	if err := row.Scan(&c.ID, &c.Step, &order, &c.AuthorizationID, &c.Failure, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(order, &c.Order); err != nil {
+		return nil, fmt.Errorf("decode checkout order %s: %w", c.ID, err)
+	}
+	return &c, nil
+}
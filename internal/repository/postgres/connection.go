@@ -18,6 +18,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -30,6 +31,9 @@ type Config struct {
 	Password string `json:"-"` // #nosec G117 -- config field, not serialized
 	Database string
 	SSLMode  string
+	// StatementTimeout limits how long any statement may run; see
+	// SetStatementTimeout
+	StatementTimeout time.Duration
 }
 
 // NewPool creates a new PostgreSQL connection pool
@@ -37,7 +41,13 @@ func NewPool(ctx context.Context, cfg Config) (*pgxpool.Pool, error) {
 	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Database, cfg.SSLMode)
 
-	pool, err := pgxpool.New(ctx, dsn)
+	poolCfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database config: %w", err)
+	}
+	SetStatementTimeout(poolCfg, cfg.StatementTimeout)
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
@@ -0,0 +1,91 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// sqlStateQueryCanceled is the SQLSTATE of a statement cancelled by the
+// server, such as by statement_timeout
+const sqlStateQueryCanceled = "57014"
+
+// SetStatementTimeout makes the server cancel any statement on the pool's
+// connections that runs longer than d, as a backstop for callers whose
+// context has no deadline. Zero or less keeps the server's default.
+func SetStatementTimeout(cfg *pgxpool.Config, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	cfg.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(d.Milliseconds(), 10)
+}
+
+// deadlineErr reports a statement the server cancelled as
+// context.DeadlineExceeded, so callers handle it like a context deadline.
+// pgx already wraps the context's error when ctx ends mid-query.
+func deadlineErr(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == sqlStateQueryCanceled {
+		return fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+	}
+	return err
+}
+
+// deadlineQuerier passes every error of q through deadlineErr
+type deadlineQuerier struct {
+	q querier
+}
+
+func (d deadlineQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	tag, err := d.q.Exec(ctx, sql, args...)
+	return tag, deadlineErr(err)
+}
+
+func (d deadlineQuerier) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	rows, err := d.q.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, deadlineErr(err)
+	}
+	return deadlineRows{rows}, nil
+}
+
+func (d deadlineQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return deadlineRow{d.q.QueryRow(ctx, sql, args...)}
+}
+
+// deadlineRows reports a statement cancelled mid-stream through Err
+type deadlineRows struct {
+	pgx.Rows
+}
+
+func (r deadlineRows) Err() error {
+	return deadlineErr(r.Rows.Err())
+}
+
+type deadlineRow struct {
+	row pgx.Row
+}
+
+func (r deadlineRow) Scan(dest ...any) error {
+	return deadlineErr(r.row.Scan(dest...))
+}
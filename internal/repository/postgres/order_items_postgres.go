@@ -0,0 +1,103 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+)
+
+// replaceItems swaps the stored line items of an order for order.Items.
+// Must run in the same transaction as the orders row write.
+func replaceItems(ctx context.Context, q querier, order *domain.Order) error {
+	if _, err := q.Exec(ctx, `DELETE FROM order_items WHERE order_id = $1`, order.ID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO order_items (id, order_id, position, product_id, name, quantity, unit_price_minor)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	for i, item := range order.Items {
+		_, err := q.Exec(ctx, query,
+			item.ID,
+			order.ID,
+			i,
+			item.ProductID,
+			item.Name,
+			item.Quantity,
+			item.Price.Amount,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadItems fills in Items for each of orders with a single query
+func loadItems(ctx context.Context, q querier, orders []*domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*domain.Order, len(orders))
+	ids := make([]string, len(orders))
+	for i, o := range orders {
+		byID[o.ID] = o
+		ids[i] = o.ID.String()
+	}
+
+	query := `
+		SELECT order_id, id, product_id, name, quantity, unit_price_minor
+		FROM order_items
+		WHERE order_id = ANY($1::uuid[])
+		ORDER BY order_id, position
+	`
+
+	rows, err := q.Query(ctx, query, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID uuid.UUID
+		var item domain.OrderItem
+
+		err := rows.Scan(
+			&orderID,
+			&item.ID,
+			&item.ProductID,
+			&item.Name,
+			&item.Quantity,
+			&item.Price.Amount,
+		)
+		if err != nil {
+			return err
+		}
+		o := byID[orderID]
+		item.Price.Currency = o.Total.Currency
+		if item.Subtotal, err = item.CalculateSubtotal(); err != nil {
+			return err
+		}
+
+		o.Items = append(o.Items, item)
+	}
+
+	return rows.Err()
+}
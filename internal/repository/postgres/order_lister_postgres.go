@@ -0,0 +1,99 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/replay"
+)
+
+// orderListerPostgres implements replay.OrderLister using PostgreSQL
+type orderListerPostgres struct {
+	pool         *pgxpool.Pool
+	baseCurrency string
+}
+
+// NewOrderLister creates a PostgreSQL lister of changed orders for replays
+func NewOrderLister(pool *pgxpool.Pool, opts ...Option) replay.OrderLister {
+	return &orderListerPostgres{
+		pool:         pool,
+		baseCurrency: newOptions(opts).baseCurrency,
+	}
+}
+
+func (l *orderListerPostgres) ListChangedBetween(ctx context.Context, from, to time.Time, after *domain.Order, limit int) ([]*domain.Order, error) {
+	// Keyset pagination on (updated_at, id); the first page starts at from
+	afterUpdatedAt, afterID := from, uuid.Nil
+	if after != nil {
+		afterUpdatedAt, afterID = after.UpdatedAt, after.ID
+	}
+
+	query := `
+		SELECT id, customer_id, status, total_minor, COALESCE(currency, ''), version, event_seq, last_event_id, created_at, updated_at, deleted_at, cancel_reason
+		FROM orders
+		WHERE updated_at >= $1 AND updated_at < $2
+		  AND (updated_at, id) > ($3, $4)
+		ORDER BY updated_at, id
+		LIMIT $5
+	`
+
+	rows, err := conn(ctx, l.pool).Query(ctx, query, from, to, afterUpdatedAt, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*domain.Order
+	for rows.Next() {
+		var order domain.Order
+
+		err := rows.Scan(
+			&order.ID,
+			&order.CustomerID,
+			&order.Status,
+			&order.Total.Amount,
+			&order.Total.Currency,
+			&order.Version,
+			&order.EventSeq,
+			&order.LastEventID,
+			&order.CreatedAt,
+			&order.UpdatedAt,
+			&order.DeletedAt,
+			&order.CancelReason,
+		)
+		if err != nil {
+			return nil, err
+		}
+		order.Total.Currency = orDefault(order.Total.Currency, l.baseCurrency)
+
+		orders = append(orders, &order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := loadItems(ctx, conn(ctx, l.pool), orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
+)
+
+// orderMetricsStorePostgres implements repository.OrderMetricsStore using
+// PostgreSQL
+type orderMetricsStorePostgres struct {
+	pool         *pgxpool.Pool
+	baseCurrency string
+}
+
+// NewOrderMetricsStore creates a PostgreSQL store for the order.metrics
+// rollup. Orders stored without a currency are summed in the base
+// currency set with WithBaseCurrency.
+func NewOrderMetricsStore(pool *pgxpool.Pool, opts ...Option) repository.OrderMetricsStore {
+	return &orderMetricsStorePostgres{
+		pool:         pool,
+		baseCurrency: newOptions(opts).baseCurrency,
+	}
+}
+
+func (s *orderMetricsStorePostgres) SumByStatus(ctx context.Context, from, to time.Time) ([]repository.StatusTotal, error) {
+	query := `
+		SELECT status, COALESCE(NULLIF(currency, ''), $3) AS currency, COUNT(*), COALESCE(SUM(total_minor), 0)
+		FROM orders
+		WHERE created_at >= $1 AND created_at < $2 AND deleted_at IS NULL
+		GROUP BY status, 2
+		ORDER BY status, 2
+	`
+
+	rows, err := conn(ctx, s.pool).Query(ctx, query, from, to, s.baseCurrency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []repository.StatusTotal
+	for rows.Next() {
+		var t repository.StatusTotal
+		if err := rows.Scan(&t.Status, &t.Currency, &t.Count, &t.TotalMinor); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
+
+func (s *orderMetricsStorePostgres) ClaimWindow(ctx context.Context, start, end time.Time) (bool, error) {
+	query := `
+		INSERT INTO order_metrics_windows (window_start, window_end)
+		VALUES ($1, $2)
+		ON CONFLICT (window_start) DO NOTHING
+	`
+	tag, err := conn(ctx, s.pool).Exec(ctx, query, start, end)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
@@ -15,11 +15,14 @@
 package postgres
 
 import (
+	"cmp"
 	"context"
-	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
@@ -28,64 +31,153 @@ import (
 
 // orderRepositoryPostgres implements OrderRepository using PostgreSQL
 type orderRepositoryPostgres struct {
-	pool *pgxpool.Pool
+	pool         *pgxpool.Pool
+	baseCurrency string
+	ids          domain.IDGenerator
+}
+
+type options struct {
+	baseCurrency string
+	ids          domain.IDGenerator
+}
+
+// Option configures a repository or lister created by this package
+type Option func(*options)
+
+// WithBaseCurrency sets the currency reported for orders stored before
+// orders recorded one. Defaults to domain.DefaultCurrency.
+func WithBaseCurrency(code string) Option {
+	return func(o *options) {
+		o.baseCurrency = code
+	}
+}
+
+// WithIDGenerator sets the generator of the IDs given to orders and items
+// stored without one. Defaults to domain.RandomIDs.
+func WithIDGenerator(g domain.IDGenerator) Option {
+	return func(o *options) {
+		o.ids = g
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{baseCurrency: domain.DefaultCurrency, ids: domain.RandomIDs}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
 }
 
 // NewOrderRepository creates a new PostgreSQL order repository
-func NewOrderRepository(pool *pgxpool.Pool) repository.OrderRepository {
+func NewOrderRepository(pool *pgxpool.Pool, opts ...Option) repository.OrderRepository {
+	o := newOptions(opts)
 	return &orderRepositoryPostgres{
-		pool: pool,
+		pool:         pool,
+		baseCurrency: o.baseCurrency,
+		ids:          o.ids,
 	}
 }
 
-func (r *orderRepositoryPostgres) Create(ctx context.Context, order *domain.Order) error {
-	itemsJSON, err := json.Marshal(order.Items)
-	if err != nil {
-		return err
+// orDefault returns currency, or base for a row with no currency.
+func orDefault(currency, base string) string {
+	if currency == "" {
+		return base
 	}
+	return currency
+}
 
-	// Set initial version
+// assignIDs gives order and its items that have no ID a generated one.
+func (r *orderRepositoryPostgres) assignIDs(order *domain.Order) {
+	if order.ID == uuid.Nil {
+		order.ID = r.ids.NewID()
+	}
+	for i := range order.Items {
+		if order.Items[i].ID == uuid.Nil {
+			order.Items[i].ID = r.ids.NewID()
+		}
+	}
+}
+
+func (r *orderRepositoryPostgres) Create(ctx context.Context, order *domain.Order) error {
+	r.assignIDs(order)
+
+	// Set initial version and event sequence
 	order.Version = 1
+	order.EventSeq = 1
 
 	query := `
-		INSERT INTO orders (id, customer_id, items, status, total, version, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO orders (id, customer_id, status, total_minor, currency, version, event_seq, last_event_id, created_at, updated_at, cancel_reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 	`
 
-	_, err = r.pool.Exec(ctx, query,
-		order.ID,
-		order.CustomerID,
-		itemsJSON,
-		order.Status,
-		order.Total,
-		order.Version,
-		order.CreatedAt,
-		order.UpdatedAt,
-	)
+	// The order row and its items are written atomically
+	return withTx(ctx, r.pool, func(ctx context.Context) error {
+		q := conn(ctx, r.pool)
+		_, err := q.Exec(ctx, query,
+			order.ID,
+			order.CustomerID,
+			order.Status,
+			order.Total.Amount,
+			order.Total.Currency,
+			order.Version,
+			order.EventSeq,
+			order.LastEventID,
+			order.CreatedAt,
+			order.UpdatedAt,
+			order.CancelReason,
+		)
+		if err != nil {
+			return err
+		}
+		return replaceItems(ctx, q, order)
+	})
+}
 
-	return err
+func (r *orderRepositoryPostgres) BulkCreate(ctx context.Context, orders []*domain.Order) error {
+	// Each Create joins this transaction, so one failure rolls back all
+	return withTx(ctx, r.pool, func(ctx context.Context) error {
+		for i, order := range orders {
+			if err := r.Create(ctx, order); err != nil {
+				return fmt.Errorf("create order %d of batch: %w", i, err)
+			}
+		}
+		return nil
+	})
 }
 
 func (r *orderRepositoryPostgres) FindByID(ctx context.Context, id string) (*domain.Order, error) {
+	return r.findByID(ctx, id, false)
+}
+
+func (r *orderRepositoryPostgres) FindByIDIncludingDeleted(ctx context.Context, id string) (*domain.Order, error) {
+	return r.findByID(ctx, id, true)
+}
+
+func (r *orderRepositoryPostgres) findByID(ctx context.Context, id string, includeDeleted bool) (*domain.Order, error) {
 	query := `
-		SELECT id, customer_id, items, status, total, version, created_at, updated_at, deleted_at
+		SELECT id, customer_id, status, total_minor, COALESCE(currency, ''), version, event_seq, last_event_id, created_at, updated_at, deleted_at, cancel_reason
 		FROM orders
-		WHERE id = $1 AND deleted_at IS NULL
+		WHERE id = $1
 	`
+	if !includeDeleted {
+		query += " AND deleted_at IS NULL"
+	}
 
 	var order domain.Order
-	var itemsJSON []byte
 
-	err := r.pool.QueryRow(ctx, query, id).Scan(
+	err := conn(ctx, r.pool).QueryRow(ctx, query, id).Scan(
 		&order.ID,
 		&order.CustomerID,
-		&itemsJSON,
 		&order.Status,
-		&order.Total,
+		&order.Total.Amount,
+		&order.Total.Currency,
 		&order.Version,
+		&order.EventSeq,
+		&order.LastEventID,
 		&order.CreatedAt,
 		&order.UpdatedAt,
 		&order.DeletedAt,
+		&order.CancelReason,
 	)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -94,8 +186,9 @@ func (r *orderRepositoryPostgres) FindByID(ctx context.Context, id string) (*dom
 	if err != nil {
 		return nil, err
 	}
+	order.Total.Currency = orDefault(order.Total.Currency, r.baseCurrency)
 
-	if err := json.Unmarshal(itemsJSON, &order.Items); err != nil {
+	if err := loadItems(ctx, conn(ctx, r.pool), []*domain.Order{&order}); err != nil {
 		return nil, err
 	}
 
@@ -103,38 +196,49 @@ func (r *orderRepositoryPostgres) FindByID(ctx context.Context, id string) (*dom
 }
 
 func (r *orderRepositoryPostgres) Update(ctx context.Context, order *domain.Order) error {
-	itemsJSON, err := json.Marshal(order.Items)
-	if err != nil {
-		return err
-	}
-
 	// Optimistic locking: only update if version matches, then increment version
 	query := `
 		UPDATE orders
 		SET customer_id = $1,
-		    items = $2,
-		    status = $3,
-		    total = $4,
+		    status = $2,
+		    total_minor = $3,
 		    version = version + 1,
-		    updated_at = $5
-		WHERE id = $6 AND version = $7 AND deleted_at IS NULL
+		    event_seq = event_seq + 1,
+		    last_event_id = $4,
+		    updated_at = $5,
+		    cancel_reason = $6
+		WHERE id = $7 AND version = $8 AND deleted_at IS NULL
 	`
 
-	result, err := r.pool.Exec(ctx, query,
-		order.CustomerID,
-		itemsJSON,
-		order.Status,
-		order.Total,
-		time.Now(),
-		order.ID,
-		order.Version,
-	)
+	var rowsAffected int64
+	err := withTx(ctx, r.pool, func(ctx context.Context) error {
+		q := conn(ctx, r.pool)
+		result, err := q.Exec(ctx, query,
+			order.CustomerID,
+			order.Status,
+			order.Total.Amount,
+			order.LastEventID,
+			order.UpdatedAt,
+			order.CancelReason,
+			order.ID,
+			order.Version,
+		)
+		if err != nil {
+			return err
+		}
 
+		// Items are only replaced once the version check has passed
+		rowsAffected = result.RowsAffected()
+		if rowsAffected == 0 {
+			return nil
+		}
+		r.assignIDs(order)
+		return replaceItems(ctx, q, order)
+	})
 	if err != nil {
 		return err
 	}
 
-	rowsAffected := result.RowsAffected()
 	if rowsAffected == 0 {
 		// Check if order exists to distinguish between not found and version mismatch
 		exists, err := r.orderExists(ctx, order.ID.String())
@@ -144,24 +248,26 @@ func (r *orderRepositoryPostgres) Update(ctx context.Context, order *domain.Orde
 		if !exists {
 			return domain.ErrOrderNotFound
 		}
-		return domain.ErrConcurrentModification
+		return domain.ErrVersionConflict
 	}
 
-	// Increment version in the order object to reflect the new state
+	// Increment version and event sequence in the order object to reflect
+	// the new state
 	order.Version++
+	order.EventSeq++
 
 	return nil
 }
 
-func (r *orderRepositoryPostgres) Delete(ctx context.Context, id string) error {
-	// Soft delete - set deleted_at timestamp
+func (r *orderRepositoryPostgres) Delete(ctx context.Context, order *domain.Order) error {
+	// Soft delete - store the caller's deleted_at timestamp
 	query := `
 		UPDATE orders
-		SET deleted_at = $1, version = version + 1
-		WHERE id = $2 AND deleted_at IS NULL
+		SET deleted_at = $1, version = version + 1, event_seq = event_seq + 1, last_event_id = $2
+		WHERE id = $3 AND deleted_at IS NULL
 	`
 
-	result, err := r.pool.Exec(ctx, query, time.Now(), id)
+	result, err := conn(ctx, r.pool).Exec(ctx, query, order.DeletedAt, order.LastEventID, order.ID)
 	if err != nil {
 		return err
 	}
@@ -173,154 +279,211 @@ func (r *orderRepositoryPostgres) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
-func (r *orderRepositoryPostgres) List(ctx context.Context, opts repository.ListOptions) ([]*domain.Order, int64, error) {
-	// Build query with optional status filter
+func (r *orderRepositoryPostgres) Restore(ctx context.Context, order *domain.Order) error {
 	query := `
-		SELECT id, customer_id, items, status, total, version, created_at, updated_at, deleted_at
-		FROM orders
-		WHERE deleted_at IS NULL
+		UPDATE orders
+		SET deleted_at = NULL, updated_at = $1, version = version + 1, event_seq = event_seq + 1, last_event_id = $2
+		WHERE id = $3 AND deleted_at IS NOT NULL
 	`
-	countQuery := `SELECT COUNT(*) FROM orders WHERE deleted_at IS NULL`
 
-	args := []interface{}{}
-	argIndex := 1
+	result, err := conn(ctx, r.pool).Exec(ctx, query, order.UpdatedAt, order.LastEventID, order.ID)
+	if err != nil {
+		return err
+	}
 
-	if opts.Status != nil {
-		query += ` AND status = $` + string(rune('0'+argIndex))
-		countQuery += ` AND status = $1`
-		args = append(args, *opts.Status)
-		argIndex++
+	if result.RowsAffected() == 0 {
+		// Distinguish a missing order from one that was never deleted
+		exists, err := r.orderExists(ctx, order.ID.String())
+		if err != nil {
+			return err
+		}
+		if exists {
+			return domain.ErrOrderNotDeleted
+		}
+		return domain.ErrOrderNotFound
+	}
+
+	return nil
+}
+
+func (r *orderRepositoryPostgres) List(ctx context.Context, opts repository.ListOptions) ([]*domain.Order, int64, error) {
+	where, args := r.listFilter(opts)
+	countQuery := `SELECT COUNT(*) FROM orders WHERE ` + where
+	countArgs := args
+
+	// Keyset pagination: rows strictly after the cursor in sort order
+	offset := opts.Offset
+	if opts.After != nil {
+		args = append(args, opts.After.CreatedAt, opts.After.ID)
+		where += fmt.Sprintf(` AND (created_at, id) < ($%d, $%d)`, len(args)-1, len(args))
+		offset = 0
 	}
 
-	query += ` ORDER BY created_at DESC LIMIT $` + string(rune('0'+argIndex)) + ` OFFSET $` + string(rune('0'+argIndex+1))
-	args = append(args, opts.Limit, opts.Offset)
+	args = append(args, opts.Limit, offset)
+	query := `
+		SELECT id, customer_id, status, total_minor, COALESCE(currency, ''), version, event_seq, last_event_id, created_at, updated_at, deleted_at, cancel_reason
+		FROM orders
+		WHERE ` + where + fmt.Sprintf(`
+		ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`, len(args)-1, len(args))
 
 	// Get total count
 	var totalCount int64
-	countArgs := args[:len(args)-2] // exclude limit and offset
-	if len(countArgs) == 0 {
-		countArgs = nil
-	}
-	err := r.pool.QueryRow(ctx, countQuery, countArgs...).Scan(&totalCount)
+	err := conn(ctx, r.pool).QueryRow(ctx, countQuery, countArgs...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	// Get orders
-	rows, err := r.pool.Query(ctx, query, args...)
+	orders, err := r.queryOrders(ctx, query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
-	defer rows.Close()
-
-	var orders []*domain.Order
-	for rows.Next() {
-		var order domain.Order
-		var itemsJSON []byte
-
-		err := rows.Scan(
-			&order.ID,
-			&order.CustomerID,
-			&itemsJSON,
-			&order.Status,
-			&order.Total,
-			&order.Version,
-			&order.CreatedAt,
-			&order.UpdatedAt,
-			&order.DeletedAt,
-		)
-		if err != nil {
-			return nil, 0, err
-		}
-
-		if err := json.Unmarshal(itemsJSON, &order.Items); err != nil {
-			return nil, 0, err
-		}
-
-		orders = append(orders, &order)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, 0, err
-	}
 
 	return orders, totalCount, nil
 }
 
-func (r *orderRepositoryPostgres) FindByCustomerID(ctx context.Context, customerID string, opts repository.ListOptions) ([]*domain.Order, int64, error) {
+func (r *orderRepositoryPostgres) ClaimPendingCreatedBefore(ctx context.Context, cutoff time.Time, limit int) ([]*domain.Order, error) {
 	query := `
-		SELECT id, customer_id, items, status, total, version, created_at, updated_at, deleted_at
+		SELECT id, customer_id, status, total_minor, COALESCE(currency, ''), version, event_seq, last_event_id, created_at, updated_at, deleted_at, cancel_reason
 		FROM orders
-		WHERE customer_id = $1 AND deleted_at IS NULL
+		WHERE status = $1 AND created_at < $2 AND deleted_at IS NULL
+		ORDER BY created_at, id
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
 	`
-	countQuery := `SELECT COUNT(*) FROM orders WHERE customer_id = $1 AND deleted_at IS NULL`
+	return r.queryOrders(ctx, query, domain.OrderStatusPending, cutoff, limit)
+}
 
-	args := []interface{}{customerID}
-	argIndex := 2
+// customerLockSpace is the first key of the advisory locks taken by
+// LockCustomerAndCountOpen, keeping them apart from other advisory locks.
+const customerLockSpace = 0x6f726471 // "ordq"
 
-	if opts.Status != nil {
-		query += ` AND status = $` + string(rune('0'+argIndex))
-		countQuery += ` AND status = $2`
-		args = append(args, *opts.Status)
-		argIndex++
-	}
+// errNoTx is returned by methods whose locks only last as long as a
+// transaction when called without one.
+var errNoTx = errors.New("postgres: must be called within a transaction")
 
-	query += ` ORDER BY created_at DESC LIMIT $` + string(rune('0'+argIndex)) + ` OFFSET $` + string(rune('0'+argIndex+1))
-	args = append(args, opts.Limit, opts.Offset)
+// LockCustomerAndCountOpen takes a transaction-scoped advisory lock on the
+// customer, which unlike row locks also covers a customer with no orders
+// yet, then counts the customer's open orders
+func (r *orderRepositoryPostgres) LockCustomerAndCountOpen(ctx context.Context, customerID string) (int, error) {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); !ok {
+		return 0, errNoTx
+	}
+	q := conn(ctx, r.pool)
+	if _, err := q.Exec(ctx, `SELECT pg_advisory_xact_lock($1::integer, hashtext($2))`, customerLockSpace, customerID); err != nil {
+		return 0, err
+	}
 
-	// Get total count
-	var totalCount int64
-	countArgs := args[:len(args)-2]
-	err := r.pool.QueryRow(ctx, countQuery, countArgs...).Scan(&totalCount)
-	if err != nil {
-		return nil, 0, err
+	statuses := domain.TerminalStatuses()
+	terminal := make([]string, len(statuses))
+	for i, s := range statuses {
+		terminal[i] = string(s)
 	}
+	var count int
+	err := q.QueryRow(ctx, `
+		SELECT COUNT(*) FROM orders
+		WHERE customer_id = $1 AND deleted_at IS NULL AND status <> ALL($2)
+	`, customerID, terminal).Scan(&count)
+	return count, err
+}
 
-	// Get orders
-	rows, err := r.pool.Query(ctx, query, args...)
+// queryOrders runs a query selecting order columns in findByID's order and
+// returns the orders with their items
+func (r *orderRepositoryPostgres) queryOrders(ctx context.Context, query string, args ...any) ([]*domain.Order, error) {
+	rows, err := conn(ctx, r.pool).Query(ctx, query, args...)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer rows.Close()
 
 	var orders []*domain.Order
 	for rows.Next() {
 		var order domain.Order
-		var itemsJSON []byte
 
 		err := rows.Scan(
 			&order.ID,
 			&order.CustomerID,
-			&itemsJSON,
 			&order.Status,
-			&order.Total,
+			&order.Total.Amount,
+			&order.Total.Currency,
 			&order.Version,
+			&order.EventSeq,
+			&order.LastEventID,
 			&order.CreatedAt,
 			&order.UpdatedAt,
 			&order.DeletedAt,
+			&order.CancelReason,
 		)
 		if err != nil {
-			return nil, 0, err
-		}
-
-		if err := json.Unmarshal(itemsJSON, &order.Items); err != nil {
-			return nil, 0, err
+			return nil, err
 		}
+		order.Total.Currency = orDefault(order.Total.Currency, r.baseCurrency)
 
 		orders = append(orders, &order)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, 0, err
+		return nil, err
 	}
+	rows.Close()
 
-	return orders, totalCount, nil
+	if err := loadItems(ctx, conn(ctx, r.pool), orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *orderRepositoryPostgres) FindByCustomerID(ctx context.Context, customerID string, opts repository.ListOptions) ([]*domain.Order, int64, error) {
+	opts.CustomerID = &customerID
+	return r.List(ctx, opts)
+}
+
+// listFilter returns the WHERE condition selecting the live orders that
+// match the filters of opts, and its arguments. Each filter is on an
+// indexed column.
+func (r *orderRepositoryPostgres) listFilter(opts repository.ListOptions) (string, []any) {
+	conds := []string{"deleted_at IS NULL"}
+	var args []any
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if opts.CustomerID != nil {
+		add("customer_id = $%d", *opts.CustomerID)
+	}
+	if opts.Status != nil {
+		add("status = $%d", *opts.Status)
+	}
+	if opts.CreatedAfter != nil {
+		add("created_at > $%d", *opts.CreatedAfter)
+	}
+	if opts.CreatedBefore != nil {
+		add("created_at < $%d", *opts.CreatedBefore)
+	}
+	if opts.MinTotal != nil {
+		add("total_minor >= $%d", opts.MinTotal.Amount)
+	}
+	if opts.MaxTotal != nil {
+		add("total_minor <= $%d", opts.MaxTotal.Amount)
+	}
+	if bound := cmp.Or(opts.MinTotal, opts.MaxTotal); bound != nil {
+		// Orders stored before currencies were recorded are in the base
+		// currency
+		if bound.Currency == r.baseCurrency {
+			add("(currency = $%d OR currency IS NULL)", bound.Currency)
+		} else {
+			add("currency = $%d", bound.Currency)
+		}
+	}
+
+	return strings.Join(conds, " AND "), args
 }
 
 // orderExists checks if an order exists (including deleted ones for version conflict detection)
 func (r *orderRepositoryPostgres) orderExists(ctx context.Context, id string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM orders WHERE id = $1)`
 	var exists bool
-	err := r.pool.QueryRow(ctx, query, id).Scan(&exists)
+	err := conn(ctx, r.pool).QueryRow(ctx, query, id).Scan(&exists)
 	return exists, err
 }
@@ -0,0 +1,120 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/outbox"
+)
+
+// outboxStorePostgres implements outbox.Store using PostgreSQL
+type outboxStorePostgres struct {
+	pool *pgxpool.Pool
+}
+
+// NewOutboxStore creates a new PostgreSQL outbox store
+func NewOutboxStore(pool *pgxpool.Pool) outbox.Store {
+	return &outboxStorePostgres{
+		pool: pool,
+	}
+}
+
+func (s *outboxStorePostgres) Enqueue(ctx context.Context, rec outbox.Record) error {
+	query := `
+		INSERT INTO outbox (id, order_id, event_type, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	_, err := conn(ctx, s.pool).Exec(ctx, query,
+		rec.ID,
+		rec.OrderID,
+		rec.EventType,
+		rec.Payload,
+		rec.CreatedAt,
+	)
+
+	return err
+}
+
+func (s *outboxStorePostgres) FetchUnpublished(ctx context.Context, limit int) ([]outbox.Record, error) {
+	query := `
+		SELECT id, order_id, event_type, payload, created_at, seq
+		FROM outbox
+		WHERE published_at IS NULL
+		ORDER BY seq
+		LIMIT $1
+	`
+
+	rows, err := conn(ctx, s.pool).Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanRecords(rows)
+}
+
+// FetchCreatedBetween implements outbox.HistoryReader
+func (s *outboxStorePostgres) FetchCreatedBetween(ctx context.Context, from, to time.Time, afterSeq int64, limit int) ([]outbox.Record, error) {
+	query := `
+		SELECT id, order_id, event_type, payload, created_at, seq
+		FROM outbox
+		WHERE created_at >= $1 AND created_at < $2 AND seq > $3
+		ORDER BY seq
+		LIMIT $4
+	`
+
+	rows, err := conn(ctx, s.pool).Query(ctx, query, from, to, afterSeq, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanRecords(rows)
+}
+
+func scanRecords(rows pgx.Rows) ([]outbox.Record, error) {
+	defer rows.Close()
+
+	var records []outbox.Record
+	for rows.Next() {
+		var rec outbox.Record
+		if err := rows.Scan(&rec.ID, &rec.OrderID, &rec.EventType, &rec.Payload, &rec.CreatedAt, &rec.Seq); err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func (s *outboxStorePostgres) MarkPublished(ctx context.Context, id string) error {
+	query := `UPDATE outbox SET published_at = NOW() WHERE id = $1 AND published_at IS NULL`
+	_, err := conn(ctx, s.pool).Exec(ctx, query, id)
+	return err
+}
+
+// CountUnpublished implements outbox.BacklogCounter
+func (s *outboxStorePostgres) CountUnpublished(ctx context.Context) (int, error) {
+	query := `SELECT COUNT(*) FROM outbox WHERE published_at IS NULL`
+	var n int
+	err := conn(ctx, s.pool).QueryRow(ctx, query).Scan(&n)
+	return n, err
+}
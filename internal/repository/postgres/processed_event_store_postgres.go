@@ -0,0 +1,76 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/dedupe"
+)
+
+// ProcessedEventStore implements dedupe.Store and dedupe.Purger using
+// PostgreSQL. Marks expire after the TTL; PurgeExpired deletes them.
+type ProcessedEventStore interface {
+	dedupe.Store
+	dedupe.Purger
+}
+
+// processedEventStorePostgres implements ProcessedEventStore using PostgreSQL
+type processedEventStorePostgres struct {
+	pool *pgxpool.Pool
+	ttl  time.Duration
+}
+
+// NewProcessedEventStore creates a new PostgreSQL processed event store
+// whose marks expire after ttl
+func NewProcessedEventStore(pool *pgxpool.Pool, ttl time.Duration) ProcessedEventStore {
+	return &processedEventStorePostgres{
+		pool: pool,
+		ttl:  ttl,
+	}
+}
+
+func (s *processedEventStorePostgres) SeenBefore(ctx context.Context, id string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM processed_events WHERE event_id = $1 AND processed_at > $2)`
+	var seen bool
+	err := conn(ctx, s.pool).QueryRow(ctx, query, id, s.cutoff()).Scan(&seen)
+	return seen, err
+}
+
+func (s *processedEventStorePostgres) MarkSeen(ctx context.Context, id string) error {
+	query := `
+		INSERT INTO processed_events (event_id, processed_at)
+		VALUES ($1, NOW())
+		ON CONFLICT (event_id) DO UPDATE SET processed_at = EXCLUDED.processed_at
+	`
+	_, err := conn(ctx, s.pool).Exec(ctx, query, id)
+	return err
+}
+
+func (s *processedEventStorePostgres) PurgeExpired(ctx context.Context) (int, error) {
+	query := `DELETE FROM processed_events WHERE processed_at <= $1`
+	tag, err := conn(ctx, s.pool).Exec(ctx, query, s.cutoff())
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// cutoff is the time before which marks have expired.
+func (s *processedEventStorePostgres) cutoff() time.Time {
+	return time.Now().Add(-s.ttl)
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+)
+
+func (r *orderRepositoryPostgres) UpdateStatus(ctx context.Context, order *domain.Order, change domain.StatusChange) error {
+	// Update joins this transaction, so a version conflict also discards
+	// the history row
+	return withTx(ctx, r.pool, func(ctx context.Context) error {
+		if err := r.Update(ctx, order); err != nil {
+			return err
+		}
+		if err := insertStatusChange(ctx, conn(ctx, r.pool), change); err != nil {
+			return fmt.Errorf("record status change: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *orderRepositoryPostgres) GetHistory(ctx context.Context, orderID string) ([]domain.StatusChange, error) {
+	// Soft-deleted orders keep their history
+	exists, err := r.orderExists(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	query := `
+		SELECT order_id, old_status, new_status, changed_by, occurred_at
+		FROM order_status_history
+		WHERE order_id = $1
+		ORDER BY occurred_at, id
+	`
+	rows, err := conn(ctx, r.pool).Query(ctx, query, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []domain.StatusChange{}
+	for rows.Next() {
+		var c domain.StatusChange
+		if err := rows.Scan(&c.OrderID, &c.OldStatus, &c.NewStatus, &c.ChangedBy, &c.OccurredAt); err != nil {
+			return nil, err
+		}
+		history = append(history, c)
+	}
+	return history, rows.Err()
+}
+
+// insertStatusChange appends change to the status history of its order.
+// Must run in the same transaction as the orders row update.
+func insertStatusChange(ctx context.Context, q querier, change domain.StatusChange) error {
+	query := `
+		INSERT INTO order_status_history (order_id, old_status, new_status, changed_by, occurred_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := q.Exec(ctx, query,
+		change.OrderID,
+		change.OldStatus,
+		change.NewStatus,
+		change.ChangedBy,
+		change.OccurredAt,
+	)
+	return err
+}
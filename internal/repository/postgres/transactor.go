@@ -0,0 +1,80 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
+)
+
+type txKey struct{}
+
+// querier is the query API shared by pgxpool.Pool and pgx.Tx
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// conn returns the transaction carried by ctx, or pool if there is none,
+// reporting statements cancelled by the server as deadline errors
+func conn(ctx context.Context, pool *pgxpool.Pool) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return deadlineQuerier{tx}
+	}
+	return deadlineQuerier{pool}
+}
+
+// transactor implements Transactor using PostgreSQL transactions
+type transactor struct {
+	pool *pgxpool.Pool
+}
+
+// NewTransactor creates a new PostgreSQL transactor
+func NewTransactor(pool *pgxpool.Pool) repository.Transactor {
+	return &transactor{pool: pool}
+}
+
+func (t *transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// withTx runs fn inside the transaction carried by ctx, starting one if
+// there is none, so multi-statement writes stay atomic when called directly
+func withTx(ctx context.Context, pool *pgxpool.Pool, fn func(ctx context.Context) error) error {
+	return (&transactor{pool: pool}).WithinTx(ctx, fn)
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package replica routes the reads of an order repository that can
+// tolerate replication lag to a read replica.
+package replica
+
+import (
+	"context"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
+)
+
+// orderRepository sends writes and strong reads to the embedded primary,
+// and eventual reads to replica.
+type orderRepository struct {
+	repository.OrderRepository
+	replica repository.OrderRepository
+}
+
+// Split returns a repository that writes to primary and serves reads from
+// replica only when their context asks for repository.Eventual with
+// repository.WithConsistency. All other reads go to primary, so a read
+// after a write sees it unless its caller opted out. Locking reads, such
+// as ClaimPendingCreatedBefore, always go to primary.
+//
+// replica must not be used inside a transaction: a Postgres replica
+// repository reads through the transaction its context carries, which is
+// open on the primary.
+func Split(primary, replica repository.OrderRepository) repository.OrderRepository {
+	return &orderRepository{OrderRepository: primary, replica: replica}
+}
+
+// reader returns the repository serving the reads of ctx.
+func (r *orderRepository) reader(ctx context.Context) repository.OrderRepository {
+	if c, _ := repository.ConsistencyFrom(ctx); c == repository.Eventual {
+		return r.replica
+	}
+	return r.OrderRepository
+}
+
+func (r *orderRepository) FindByID(ctx context.Context, id string) (*domain.Order, error) {
+	return r.reader(ctx).FindByID(ctx, id)
+}
+
+func (r *orderRepository) FindByIDIncludingDeleted(ctx context.Context, id string) (*domain.Order, error) {
+	return r.reader(ctx).FindByIDIncludingDeleted(ctx, id)
+}
+
+func (r *orderRepository) GetHistory(ctx context.Context, orderID string) ([]domain.StatusChange, error) {
+	return r.reader(ctx).GetHistory(ctx, orderID)
+}
+
+func (r *orderRepository) List(ctx context.Context, opts repository.ListOptions) ([]*domain.Order, int64, error) {
+	return r.reader(ctx).List(ctx, opts)
+}
+
+func (r *orderRepository) FindByCustomerID(ctx context.Context, customerID string, opts repository.ListOptions) ([]*domain.Order, int64, error) {
+	return r.reader(ctx).FindByCustomerID(ctx, customerID, opts)
+}
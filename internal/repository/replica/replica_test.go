@@ -0,0 +1,149 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replica
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubRepo records the calls it serves under its name. Its other methods
+// are not used.
+type stubRepo struct {
+	repository.OrderRepository
+	name  string
+	calls *[]string
+}
+
+func (s stubRepo) record(method string) { *s.calls = append(*s.calls, s.name+"."+method) }
+
+func (s stubRepo) Create(context.Context, *domain.Order) error {
+	s.record("Create")
+	return nil
+}
+
+func (s stubRepo) FindByID(context.Context, string) (*domain.Order, error) {
+	s.record("FindByID")
+	return &domain.Order{}, nil
+}
+
+func (s stubRepo) FindByIDIncludingDeleted(context.Context, string) (*domain.Order, error) {
+	s.record("FindByIDIncludingDeleted")
+	return &domain.Order{}, nil
+}
+
+func (s stubRepo) GetHistory(context.Context, string) ([]domain.StatusChange, error) {
+	s.record("GetHistory")
+	return nil, nil
+}
+
+func (s stubRepo) List(context.Context, repository.ListOptions) ([]*domain.Order, int64, error) {
+	s.record("List")
+	return nil, 0, nil
+}
+
+func (s stubRepo) FindByCustomerID(context.Context, string, repository.ListOptions) ([]*domain.Order, int64, error) {
+	s.record("FindByCustomerID")
+	return nil, 0, nil
+}
+
+func (s stubRepo) ClaimPendingCreatedBefore(context.Context, time.Time, int) ([]*domain.Order, error) {
+	s.record("ClaimPendingCreatedBefore")
+	return nil, nil
+}
+
+// newSplit returns a split repository over stubs named primary and
+// replica, and the calls they serve.
+func newSplit() (repository.OrderRepository, *[]string) {
+	calls := &[]string{}
+	return Split(stubRepo{name: "primary", calls: calls}, stubRepo{name: "replica", calls: calls}), calls
+}
+
+func TestSplit_RoutesReadsByConsistency(t *testing.T) {
+	reads := []struct {
+		method string
+		call   func(ctx context.Context, repo repository.OrderRepository) error
+	}{
+		{"FindByID", func(ctx context.Context, repo repository.OrderRepository) error {
+			_, err := repo.FindByID(ctx, "id")
+			return err
+		}},
+		{"FindByIDIncludingDeleted", func(ctx context.Context, repo repository.OrderRepository) error {
+			_, err := repo.FindByIDIncludingDeleted(ctx, "id")
+			return err
+		}},
+		{"GetHistory", func(ctx context.Context, repo repository.OrderRepository) error {
+			_, err := repo.GetHistory(ctx, "id")
+			return err
+		}},
+		{"List", func(ctx context.Context, repo repository.OrderRepository) error {
+			_, _, err := repo.List(ctx, repository.ListOptions{})
+			return err
+		}},
+		{"FindByCustomerID", func(ctx context.Context, repo repository.OrderRepository) error {
+			_, _, err := repo.FindByCustomerID(ctx, "customer", repository.ListOptions{})
+			return err
+		}},
+	}
+	contexts := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"unset", context.Background(), "primary"},
+		{"strong", repository.WithConsistency(context.Background(), repository.Strong), "primary"},
+		{"eventual", repository.WithConsistency(context.Background(), repository.Eventual), "replica"},
+	}
+
+	for _, read := range reads {
+		for _, c := range contexts {
+			t.Run(read.method+"/"+c.name, func(t *testing.T) {
+				repo, calls := newSplit()
+
+				require.NoError(t, read.call(c.ctx, repo))
+
+				assert.Equal(t, []string{c.want + "." + read.method}, *calls)
+			})
+		}
+	}
+}
+
+func TestSplit_GetAfterCreate_ReadsPrimary(t *testing.T) {
+	repo, calls := newSplit()
+	ctx := context.Background()
+
+	require.NoError(t, repo.Create(ctx, &domain.Order{}))
+	_, err := repo.FindByID(ctx, "id")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"primary.Create", "primary.FindByID"}, *calls)
+}
+
+func TestSplit_EventualContext_WritesAndLockingReadsGoToPrimary(t *testing.T) {
+	repo, calls := newSplit()
+	ctx := repository.WithConsistency(context.Background(), repository.Eventual)
+
+	require.NoError(t, repo.Create(ctx, &domain.Order{}))
+	_, err := repo.ClaimPendingCreatedBefore(ctx, time.Now(), 10)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"primary.Create", "primary.ClaimPendingCreatedBefore"}, *calls)
+}
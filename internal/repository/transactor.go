@@ -0,0 +1,26 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import "context"
+
+// Transactor runs a unit of work inside a database transaction.
+type Transactor interface {
+	// WithinTx calls fn with a context carrying an open transaction.
+	// Repositories and stores called with that context join the transaction.
+	// The transaction commits if fn returns nil and rolls back otherwise.
+	// Nested calls join the outer transaction.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,226 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
+)
+
+const defaultCheckoutResumeBatch = 100
+
+// errCheckoutInterrupted is recorded as the failure of a checkout Resume
+// finds before its payment was authorized.
+var errCheckoutInterrupted = errors.New("checkout interrupted before payment was authorized")
+
+// CheckoutSaga checks out an order in steps: it reserves the order's
+// stock, authorizes its payment, then stores the order as confirmed and
+// publishes order.created. If a step fails, the steps before it are undone
+// in reverse, voiding the payment and releasing the stock, so a failed
+// checkout leaves no order and holds nothing.
+//
+// The progress of each checkout is saved after every step, so Resume can
+// pick up checkouts interrupted by a crash. One whose payment was
+// authorized is completed; an earlier one is undone, since its caller has
+// given up on it. A step that was running when the process died was not
+// recorded and is neither retried nor undone, so reservations and
+// authorizations should lapse if they are never used.
+type CheckoutSaga struct {
+	svc   *orderServiceImpl
+	store repository.CheckoutStore
+}
+
+// NewCheckoutSaga creates a saga that saves its progress to store, stores
+// checked out orders in repo and publishes their order.created events with
+// publisher. opts are those of NewOrderService: WithInventoryReserver and
+// WithPaymentAuthorizer set the steps, which default to doing nothing, and
+// WithTransactor, WithOrderQuota and the order defaults apply as they do
+// to CreateOrder.
+func NewCheckoutSaga(repo repository.OrderRepository, store repository.CheckoutStore, publisher EventPublisher, opts ...Option) *CheckoutSaga {
+	return &CheckoutSaga{
+		svc:   newOrderService(repo, nil, publisher, opts...),
+		store: store,
+	}
+}
+
+// Checkout checks out the order dto describes and returns it confirmed.
+// An invalid order is rejected before any step runs. Otherwise a failure
+// undoes the completed steps and returns an error wrapping
+// domain.ErrReservationFailed or domain.ErrPaymentFailed if a step failed,
+// or the error storing the order, such as a *domain.QuotaError.
+func (c *CheckoutSaga) Checkout(ctx context.Context, dto CreateOrderDTO) (*domain.Order, error) {
+	order, err := c.svc.newOrder(dto)
+	if err != nil {
+		return nil, err
+	}
+	now := c.svc.clock.Now()
+	checkout := &domain.Checkout{
+		ID:        order.ID,
+		Step:      domain.CheckoutStarted,
+		Order:     order,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := c.store.Save(ctx, checkout); err != nil {
+		return nil, fmt.Errorf("save checkout: %w", err)
+	}
+	return c.run(ctx, checkout)
+}
+
+// Resume finishes up to 100 checkouts left unfinished, oldest first, and
+// returns how many it finished, completed or undone. A checkout whose step
+// fails again is logged and left for the next call. Run it at startup, and
+// periodically if checkouts can be interrupted without the process dying.
+func (c *CheckoutSaga) Resume(ctx context.Context) (int, error) {
+	checkouts, err := c.store.ListUnfinished(ctx, defaultCheckoutResumeBatch)
+	if err != nil {
+		return 0, fmt.Errorf("list unfinished checkouts: %w", err)
+	}
+	finished := 0
+	for _, checkout := range checkouts {
+		c.resume(ctx, checkout)
+		if checkout.Step.IsFinal() {
+			finished++
+		}
+	}
+	return finished, nil
+}
+
+// resume continues checkout from the last step it saved.
+func (c *CheckoutSaga) resume(ctx context.Context, checkout *domain.Checkout) {
+	switch {
+	case checkout.Failure != "":
+		if err := c.compensate(ctx, checkout); err != nil {
+			c.svc.logger.Warn("checkout undo failed", slog.String("order_id", checkout.ID.String()), slog.String("error", err.Error()))
+		}
+	case checkout.Authorized():
+		// The order may have been stored just before the crash
+		stored, err := c.svc.repo.FindByIDIncludingDeleted(ctx, checkout.ID.String())
+		if err != nil {
+			c.svc.logger.Warn("checkout resume failed", slog.String("order_id", checkout.ID.String()), slog.String("error", err.Error()))
+			return
+		}
+		if stored != nil {
+			checkout.Order = stored
+			if err := c.advance(ctx, checkout, domain.CheckoutCompleted); err != nil {
+				c.svc.logger.Warn("checkout resume failed", slog.String("order_id", checkout.ID.String()), slog.String("error", err.Error()))
+			}
+			return
+		}
+		if _, err := c.run(ctx, checkout); err != nil {
+			c.svc.logger.Warn("resumed checkout failed", slog.String("order_id", checkout.ID.String()), slog.String("error", err.Error()))
+		}
+	default:
+		_ = c.fail(ctx, checkout, errCheckoutInterrupted)
+	}
+}
+
+// run completes the steps of checkout from the one it has reached,
+// undoing the completed ones if a step fails.
+func (c *CheckoutSaga) run(ctx context.Context, checkout *domain.Checkout) (*domain.Order, error) {
+	order := checkout.Order
+	if checkout.Step == domain.CheckoutStarted {
+		if err := c.svc.inventory.Reserve(ctx, order.Items); err != nil {
+			return nil, c.fail(ctx, checkout, fmt.Errorf("%w: %w", domain.ErrReservationFailed, err))
+		}
+		if err := c.advance(ctx, checkout, domain.CheckoutReserved); err != nil {
+			return nil, c.fail(ctx, checkout, err)
+		}
+	}
+	if checkout.Step == domain.CheckoutReserved {
+		id, err := c.svc.payments.Authorize(ctx, order)
+		if err != nil {
+			return nil, c.fail(ctx, checkout, fmt.Errorf("%w: %w", domain.ErrPaymentFailed, err))
+		}
+		checkout.AuthorizationID = id
+		if err := c.advance(ctx, checkout, domain.CheckoutAuthorized); err != nil {
+			return nil, c.fail(ctx, checkout, err)
+		}
+	}
+	if err := c.confirm(ctx, order); err != nil {
+		return nil, c.fail(ctx, checkout, err)
+	}
+	if err := c.advance(ctx, checkout, domain.CheckoutCompleted); err != nil {
+		// The order is stored, so resuming the checkout completes it
+		c.svc.logger.Warn("checkout completion not saved", slog.String("order_id", order.ID.String()), slog.String("error", err.Error()))
+	}
+	return order, nil
+}
+
+// confirm stores order as confirmed, holding its stock, and publishes
+// order.created, as CreateOrder does for a pending order.
+func (c *CheckoutSaga) confirm(ctx context.Context, order *domain.Order) error {
+	s := c.svc
+	order.Status = domain.OrderStatusConfirmed
+	err := s.saveAndPublish(ctx, order, messaging.EventOrderCreated,
+		s.withinQuota(order.CustomerID, func(ctx context.Context) error { return s.repo.Create(ctx, order) }),
+		func(ctx context.Context) error { return s.publisher.PublishOrderCreated(ctx, order) },
+	)
+	if err != nil {
+		order.Status = domain.OrderStatusPending
+	}
+	return err
+}
+
+// fail records that checkout failed with cause, undoes its completed
+// steps and returns cause. The failure is saved first, so that Resume
+// finishes undoing a checkout interrupted, or refused, while undoing it.
+func (c *CheckoutSaga) fail(ctx context.Context, checkout *domain.Checkout, cause error) error {
+	// The steps are undone even if the caller has gone
+	ctx = context.WithoutCancel(ctx)
+	checkout.Failure = cause.Error()
+	checkout.UpdatedAt = c.svc.clock.Now()
+	if err := c.store.Save(ctx, checkout); err != nil {
+		c.svc.logger.Warn("checkout failure not saved", slog.String("order_id", checkout.ID.String()), slog.String("error", err.Error()))
+	}
+	if err := c.compensate(ctx, checkout); err != nil {
+		c.svc.logger.Warn("checkout undo failed", slog.String("order_id", checkout.ID.String()), slog.String("error", err.Error()))
+	}
+	return cause
+}
+
+// compensate voids the payment and releases the stock checkout holds,
+// then marks it failed. Steps undone before an interruption are undone
+// again when it is resumed.
+func (c *CheckoutSaga) compensate(ctx context.Context, checkout *domain.Checkout) error {
+	if checkout.Authorized() {
+		if err := c.svc.payments.Void(ctx, checkout.Order, checkout.AuthorizationID); err != nil {
+			return fmt.Errorf("void payment: %w", err)
+		}
+	}
+	if checkout.Reserved() {
+		if err := c.svc.inventory.Release(ctx, checkout.Order.Items); err != nil {
+			return fmt.Errorf("release inventory: %w", err)
+		}
+	}
+	return c.advance(ctx, checkout, domain.CheckoutFailed)
+}
+
+// advance moves checkout to step and saves it. checkout is left at step
+// even if the save fails, so it reflects the steps completed.
+func (c *CheckoutSaga) advance(ctx context.Context, checkout *domain.Checkout, step domain.CheckoutStep) error {
+	checkout.Step = step
+	checkout.UpdatedAt = c.svc.clock.Now()
+	if err := c.store.Save(ctx, checkout); err != nil {
+		return fmt.Errorf("save checkout: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/memory"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/mocks"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
+	memrepo "github.com/sridharn-code-sandbox/go-ordersvc/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkoutSteps records the calls the saga makes to its steps, failing
+// those named in fail.
+type checkoutSteps struct {
+	calls []string
+	fail  map[string]error
+}
+
+func (r *checkoutSteps) call(name string) error {
+	r.calls = append(r.calls, name)
+	return r.fail[name]
+}
+
+func (r *checkoutSteps) options() []Option {
+	return []Option{
+		WithInventoryReserver(&mocks.InventoryReserverMock{
+			ReserveFunc: func(context.Context, []domain.OrderItem) error { return r.call("reserve") },
+			ReleaseFunc: func(context.Context, []domain.OrderItem) error { return r.call("release") },
+		}),
+		WithPaymentAuthorizer(&mocks.PaymentAuthorizerMock{
+			AuthorizeFunc: func(context.Context, *domain.Order) (string, error) {
+				if err := r.call("authorize"); err != nil {
+					return "", err
+				}
+				return "auth-1", nil
+			},
+			VoidFunc: func(_ context.Context, _ *domain.Order, id string) error { return r.call("void " + id) },
+		}),
+	}
+}
+
+type checkoutFixture struct {
+	saga   *CheckoutSaga
+	repo   *memrepo.OrderRepository
+	store  *memrepo.CheckoutStore
+	events *memory.Publisher
+	steps  *checkoutSteps
+}
+
+// newCheckoutFixture creates a saga over in-memory stores whose steps
+// fail as fail says, with the options opts returns for its repository.
+func newCheckoutFixture(fail map[string]error, opts func(*memrepo.OrderRepository) []Option) checkoutFixture {
+	f := checkoutFixture{
+		repo:   memrepo.NewOrderRepository(),
+		store:  memrepo.NewCheckoutStore(),
+		events: memory.New(),
+		steps:  &checkoutSteps{fail: fail},
+	}
+	sagaOpts := f.steps.options()
+	if opts != nil {
+		sagaOpts = append(sagaOpts, opts(f.repo)...)
+	}
+	f.saga = NewCheckoutSaga(f.repo, f.store, f.events, sagaOpts...)
+	return f
+}
+
+func checkoutDTO() CreateOrderDTO {
+	return CreateOrderDTO{
+		CustomerID: "cust-1",
+		Items:      []OrderItemDTO{{ProductID: "product-1", Name: "Test Product", Quantity: 2, Price: "10.00"}},
+	}
+}
+
+func TestCheckoutSaga_Checkout_ConfirmsOrderAndPublishesCreated(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(nil, nil)
+
+	order, err := f.saga.Checkout(ctx, checkoutDTO())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"reserve", "authorize"}, f.steps.calls)
+	assert.Equal(t, domain.OrderStatusConfirmed, order.Status)
+	stored, err := f.repo.FindByID(ctx, order.ID.String())
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, domain.OrderStatusConfirmed, stored.Status)
+	assert.Equal(t, domain.Money{Amount: 2000, Currency: "USD"}, stored.Total)
+
+	created := f.events.EventsOfType(messaging.EventOrderCreated)
+	require.Len(t, created, 1)
+	assert.Equal(t, order.ID.String(), created[0].OrderID)
+
+	checkout, err := f.store.FindByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutCompleted, checkout.Step)
+	assert.Equal(t, "auth-1", checkout.AuthorizationID)
+	assert.Empty(t, checkout.Failure)
+}
+
+func TestCheckoutSaga_Checkout_PaymentFails_ReleasesReservedInventory(t *testing.T) {
+	ctx := context.Background()
+	declined := errors.New("card declined")
+	f := newCheckoutFixture(map[string]error{"authorize": declined}, nil)
+
+	order, err := f.saga.Checkout(ctx, checkoutDTO())
+
+	require.ErrorIs(t, err, domain.ErrPaymentFailed)
+	assert.ErrorIs(t, err, declined)
+	assert.Nil(t, order)
+	assert.Equal(t, []string{"reserve", "authorize", "release"}, f.steps.calls,
+		"the reservation is released; there is no authorization to void")
+
+	orders, total, err := f.repo.List(ctx, repository.ListOptions{Limit: 10})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, orders, "no order is stored")
+	assert.Empty(t, f.events.Events(), "no event is published")
+
+	unfinished, err := f.store.ListUnfinished(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, unfinished)
+}
+
+func TestCheckoutSaga_Checkout_Failures(t *testing.T) {
+	errStock := errors.New("out of stock")
+	tests := []struct {
+		name      string
+		fail      map[string]error
+		opts      func(*memrepo.OrderRepository) []Option
+		wantErr   error
+		wantCalls []string
+	}{
+		{
+			name:      "reservation fails",
+			fail:      map[string]error{"reserve": errStock},
+			wantErr:   domain.ErrReservationFailed,
+			wantCalls: []string{"reserve"},
+		},
+		{
+			name: "order cannot be stored",
+			opts: func(repo *memrepo.OrderRepository) []Option {
+				// The customer already has the one open order allowed
+				existing := &domain.Order{CustomerID: "cust-1", Status: domain.OrderStatusPending, Total: domain.Money{Amount: 100, Currency: "USD"},
+					Items: []domain.OrderItem{{ProductID: "p-0", Name: "Other", Quantity: 1, Price: domain.Money{Amount: 100, Currency: "USD"}}}}
+				if err := repo.Create(context.Background(), existing); err != nil {
+					panic(err)
+				}
+				return []Option{WithOrderQuota(1, memrepo.NewTransactor(repo))}
+			},
+			wantErr:   domain.ErrQuotaExceeded,
+			wantCalls: []string{"reserve", "authorize", "void auth-1", "release"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newCheckoutFixture(tt.fail, tt.opts)
+
+			order, err := f.saga.Checkout(ctx, checkoutDTO())
+
+			require.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, order)
+			assert.Equal(t, tt.wantCalls, f.steps.calls)
+			assert.Empty(t, f.events.Events())
+			unfinished, err := f.store.ListUnfinished(ctx, 10)
+			require.NoError(t, err)
+			assert.Empty(t, unfinished)
+		})
+	}
+}
+
+func TestCheckoutSaga_Checkout_InvalidOrder_RunsNoStep(t *testing.T) {
+	f := newCheckoutFixture(nil, nil)
+
+	_, err := f.saga.Checkout(context.Background(), CreateOrderDTO{CustomerID: "cust-1"})
+
+	require.ErrorIs(t, err, domain.ErrNoItems)
+	assert.Empty(t, f.steps.calls)
+	unfinished, err := f.store.ListUnfinished(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, unfinished)
+}
+
+func TestCheckoutSaga_Checkout_UndoFails_ResumeFinishesIt(t *testing.T) {
+	ctx := context.Background()
+	f := newCheckoutFixture(map[string]error{"authorize": errors.New("declined"), "release": errors.New("inventory down")}, nil)
+
+	_, err := f.saga.Checkout(ctx, checkoutDTO())
+	require.ErrorIs(t, err, domain.ErrPaymentFailed)
+	unfinished, err := f.store.ListUnfinished(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, unfinished, 1, "the checkout is left for Resume")
+	assert.Contains(t, unfinished[0].Failure, "declined")
+
+	f.steps.fail = nil
+	f.steps.calls = nil
+	finished, err := f.saga.Resume(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, finished)
+	assert.Equal(t, []string{"release"}, f.steps.calls, "the payment is not authorized again")
+	checkout, err := f.store.FindByID(ctx, unfinished[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutFailed, checkout.Step)
+}
+
+func TestCheckoutSaga_Resume(t *testing.T) {
+	tests := []struct {
+		name        string
+		step        domain.CheckoutStep
+		failure     string
+		stored      bool // The order was stored before the crash
+		wantStep    domain.CheckoutStep
+		wantCalls   []string
+		wantEvents  int
+		wantFailure string
+	}{
+		{
+			name:        "started",
+			step:        domain.CheckoutStarted,
+			wantStep:    domain.CheckoutFailed,
+			wantFailure: errCheckoutInterrupted.Error(),
+		},
+		{
+			name:        "inventory reserved",
+			step:        domain.CheckoutReserved,
+			wantStep:    domain.CheckoutFailed,
+			wantCalls:   []string{"release"},
+			wantFailure: errCheckoutInterrupted.Error(),
+		},
+		{
+			name:       "payment authorized",
+			step:       domain.CheckoutAuthorized,
+			wantStep:   domain.CheckoutCompleted,
+			wantEvents: 1,
+		},
+		{
+			name:     "payment authorized and order stored",
+			step:     domain.CheckoutAuthorized,
+			stored:   true,
+			wantStep: domain.CheckoutCompleted,
+		},
+		{
+			name:        "undo interrupted",
+			step:        domain.CheckoutAuthorized,
+			failure:     "quota exceeded",
+			wantStep:    domain.CheckoutFailed,
+			wantCalls:   []string{"void auth-1", "release"},
+			wantFailure: "quota exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			f := newCheckoutFixture(nil, nil)
+			order, err := f.saga.svc.newOrder(checkoutDTO())
+			require.NoError(t, err)
+			if tt.stored {
+				order.Status = domain.OrderStatusConfirmed
+				require.NoError(t, f.repo.Create(ctx, order))
+			}
+			checkout := &domain.Checkout{ID: order.ID, Step: tt.step, Order: order, Failure: tt.failure, CreatedAt: time.Now()}
+			if tt.step == domain.CheckoutAuthorized {
+				checkout.AuthorizationID = "auth-1"
+			}
+			require.NoError(t, f.store.Save(ctx, checkout))
+
+			finished, err := f.saga.Resume(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, 1, finished)
+			assert.Equal(t, tt.wantCalls, f.steps.calls)
+			assert.Len(t, f.events.Events(), tt.wantEvents)
+			got, err := f.store.FindByID(ctx, order.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStep, got.Step)
+			assert.Equal(t, tt.wantFailure, got.Failure)
+			stored, err := f.repo.FindByID(ctx, order.ID.String())
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStep == domain.CheckoutCompleted, stored != nil)
+
+			finished, err = f.saga.Resume(ctx)
+			require.NoError(t, err)
+			assert.Zero(t, finished, "nothing is left to resume")
+		})
+	}
+}
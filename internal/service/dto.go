@@ -15,18 +15,34 @@
 // Package service implements business logic for order operations.
 package service
 
-import "github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+import (
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+)
 
 // CreateOrderDTO represents data for creating an order
 type CreateOrderDTO struct {
 	CustomerID string
-	Items      []domain.OrderItem
+	Currency   string // ISO 4217 code; empty uses the service's base currency
+	Items      []OrderItemDTO
 }
 
-// UpdateOrderDTO represents data for updating an order
+// UpdateOrderDTO represents data for updating an order. Nil or empty
+// fields are left unchanged.
 type UpdateOrderDTO struct {
-	Items  []domain.OrderItem
-	Status *domain.OrderStatus
+	CustomerID *string
+	Items      []OrderItemDTO
+	Status     *domain.OrderStatus
+}
+
+// OrderItemDTO represents an item of an order being created or updated
+type OrderItemDTO struct {
+	ProductID string
+	Name      string
+	Quantity  int
+	Price     string // Decimal unit price, e.g. "19.99"
+	Currency  string // ISO 4217 code of Price; empty means the order's currency
 }
 
 // ListOrdersRequest represents pagination and filtering options
@@ -35,4 +51,14 @@ type ListOrdersRequest struct {
 	PageSize   int
 	Status     *domain.OrderStatus
 	CustomerID *string
+	// CreatedAfter and CreatedBefore bound the creation time, both exclusive
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// MinTotal and MaxTotal bound the total, both inclusive, as decimal
+	// amounts in the base currency, e.g. "10.00". Orders in other
+	// currencies do not match a total bound.
+	MinTotal string
+	MaxTotal string
+	// Cursor, if set, is a next_cursor from a previous page; Page is ignored
+	Cursor string
 }
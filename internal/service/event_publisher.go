@@ -14,15 +14,9 @@
 
 package service
 
-import (
-	"context"
+import "github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
 
-	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
-)
-
-// EventPublisher publishes domain events to Kafka
-type EventPublisher interface {
-	PublishOrderCreated(ctx context.Context, order *domain.Order) error
-	PublishOrderUpdated(ctx context.Context, order *domain.Order) error
-	PublishOrderStatusChanged(ctx context.Context, order *domain.Order, oldStatus, newStatus domain.OrderStatus) error
-}
+// EventPublisher publishes domain events to Kafka.
+// It is the messaging.EventPublisher contract so any publisher implementation
+// can be injected into the service.
+type EventPublisher = messaging.EventPublisher
@@ -0,0 +1,196 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/cache"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/noop"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
+)
+
+const defaultExpiryBatchSize = 100
+
+// ExpiryActor is recorded as the ChangedBy of the orders the worker
+// expires.
+const ExpiryActor = "system:expiry"
+
+// ExpiryWorker moves orders left pending longer than a TTL to expired,
+// publishing an order.expired event for each.
+//
+// Each batch is claimed, saved and published in one transaction. Claimed
+// orders stay locked until it commits and other workers skip them, so
+// replicas can run the worker concurrently without expiring an order
+// twice. A failure rolls the batch back for the next scan to retry; with a
+// non-transactional publisher events may then be published more than once.
+type ExpiryWorker struct {
+	repo       repository.OrderRepository
+	transactor repository.Transactor
+	cache      cache.OrderCache
+	publisher  EventPublisher
+	ttl        time.Duration
+	interval   time.Duration
+	batchSize  int
+	clock      messaging.Clock
+	logger     *slog.Logger
+}
+
+// ExpiryOption configures optional ExpiryWorker settings
+type ExpiryOption func(*ExpiryWorker)
+
+// WithExpiryBatchSize sets how many orders are expired per transaction.
+// Defaults to 100.
+func WithExpiryBatchSize(n int) ExpiryOption {
+	return func(w *ExpiryWorker) {
+		if n > 0 {
+			w.batchSize = n
+		}
+	}
+}
+
+// WithExpiryClock sets the clock the TTL is measured against, which also
+// stamps the expiries and their events. Defaults to messaging.SystemClock.
+func WithExpiryClock(c messaging.Clock) ExpiryOption {
+	return func(w *ExpiryWorker) {
+		w.clock = c
+	}
+}
+
+// WithExpiryLogger sets where failed scans, the orders expired and cache
+// errors are logged. Defaults to logging nothing.
+func WithExpiryLogger(logger *slog.Logger) ExpiryOption {
+	return func(w *ExpiryWorker) {
+		w.logger = logger
+	}
+}
+
+// NewExpiryWorker creates a worker that every interval expires the orders
+// pending for longer than ttl. orderCache may be nil.
+func NewExpiryWorker(repo repository.OrderRepository, transactor repository.Transactor, orderCache cache.OrderCache, publisher EventPublisher, ttl, interval time.Duration, opts ...ExpiryOption) *ExpiryWorker {
+	w := &ExpiryWorker{
+		repo:       repo,
+		transactor: transactor,
+		cache:      orderCache,
+		publisher:  noop.OrNoop(publisher),
+		ttl:        ttl,
+		interval:   interval,
+		batchSize:  defaultExpiryBatchSize,
+		clock:      messaging.SystemClock{},
+		logger:     discardLogger,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Run expires orders every interval until ctx is cancelled.
+func (w *ExpiryWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := w.ExpireOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			w.logger.Warn("order expiry failed", slog.String("error", err.Error()))
+		}
+		if n > 0 {
+			w.logger.Info("expired pending orders", slog.Int("count", n))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExpireOnce expires, batch by batch, every order pending since before the
+// TTL, and returns how many it expired. It stops at the first failed batch.
+func (w *ExpiryWorker) ExpireOnce(ctx context.Context) (int, error) {
+	now := w.clock.Now()
+	total := 0
+	for {
+		expired, claimed, err := w.expireBatch(ctx, now)
+		if err != nil {
+			return total, fmt.Errorf("expire pending orders: %w", err)
+		}
+		total += len(expired)
+		w.invalidate(ctx, expired)
+
+		if claimed < w.batchSize {
+			return total, nil
+		}
+	}
+}
+
+// expireBatch claims up to batchSize orders pending for longer than the
+// TTL at now and expires them in one transaction. It returns the orders
+// it expired and how many it claimed; fewer than batchSize means none are
+// left.
+func (w *ExpiryWorker) expireBatch(ctx context.Context, now time.Time) ([]*domain.Order, int, error) {
+	var expired []*domain.Order
+	claimed := 0
+	err := w.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		orders, err := w.repo.ClaimPendingCreatedBefore(ctx, now.Add(-w.ttl), w.batchSize)
+		if err != nil {
+			return err
+		}
+		claimed = len(orders)
+		for _, order := range orders {
+			// The claim is by creation time; recheck, since only a pending
+			// order past its TTL may expire
+			if !order.IsExpired(now, w.ttl) {
+				continue
+			}
+			oldStatus := order.Status
+			if err := order.TransitionTo(domain.OrderStatusExpired, w.clock.Now()); err != nil {
+				return err
+			}
+			pubCtx, change := newStatusChange(domain.WithActor(ctx, ExpiryActor), order, oldStatus, order.UpdatedAt)
+			pubCtx = nextEvent(pubCtx, order)
+			if err := w.repo.UpdateStatus(ctx, order, change); err != nil {
+				return err
+			}
+			if err := w.publisher.PublishOrderExpired(pubCtx, order); err != nil {
+				return err
+			}
+			expired = append(expired, order)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return expired, claimed, nil
+}
+
+func (w *ExpiryWorker) invalidate(ctx context.Context, orders []*domain.Order) {
+	if w.cache == nil {
+		return
+	}
+	for _, order := range orders {
+		if err := w.cache.Delete(ctx, order.ID.String()); err != nil {
+			w.logger.Warn("cache delete failed", slog.String("order_id", order.ID.String()), slog.String("error", err.Error()))
+		}
+	}
+}
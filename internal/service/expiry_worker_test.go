@@ -0,0 +1,306 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/cache"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var expiryNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// txRecorder is a transactor that counts transactions and records which
+// calls ran inside one.
+type txRecorder struct {
+	txs int
+	in  bool
+}
+
+func (r *txRecorder) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	r.txs++
+	r.in = true
+	defer func() { r.in = false }()
+	return fn(ctx)
+}
+
+func newPendingOrders(n int) []*domain.Order {
+	orders := make([]*domain.Order, n)
+	for i := range orders {
+		orders[i] = &domain.Order{
+			ID:        uuid.New(),
+			Status:    domain.OrderStatusPending,
+			Version:   1,
+			CreatedAt: expiryNow.Add(-48 * time.Hour),
+		}
+	}
+	return orders
+}
+
+// fakeClock is a messaging.Clock that reads the time a test sets, so the
+// time can be moved on without sleeping.
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestExpiryWorker(repo *mocks.OrderRepositoryMock, tx *txRecorder, orderCache cache.OrderCache, pub *mocks.EventPublisherMock, opts ...ExpiryOption) *ExpiryWorker {
+	opts = append([]ExpiryOption{WithExpiryClock(&fakeClock{now: expiryNow})}, opts...)
+	return NewExpiryWorker(repo, tx, orderCache, pub, 24*time.Hour, time.Minute, opts...)
+}
+
+func TestExpiryWorker_ExpireOnce_ExpiresClaimedOrders(t *testing.T) {
+	claimed := newPendingOrders(2)
+	tx := &txRecorder{}
+	var gotCutoff time.Time
+	var gotLimit int
+	var updated []string
+	var events []domain.OrderStatus
+	var deleted []string
+
+	repo := &mocks.OrderRepositoryMock{
+		ClaimPendingCreatedBeforeFunc: func(_ context.Context, cutoff time.Time, limit int) ([]*domain.Order, error) {
+			assert.True(t, tx.in, "orders must be claimed in a transaction")
+			gotCutoff, gotLimit = cutoff, limit
+			return claimed, nil
+		},
+		UpdateFunc: func(_ context.Context, order *domain.Order) error {
+			assert.True(t, tx.in, "expiry must be saved in the claiming transaction")
+			assert.Equal(t, domain.OrderStatusExpired, order.Status)
+			updated = append(updated, order.ID.String())
+			return nil
+		},
+	}
+	pub := &mocks.EventPublisherMock{
+		PublishOrderExpiredFunc: func(_ context.Context, order *domain.Order) error {
+			assert.True(t, tx.in, "event must be published in the claiming transaction")
+			events = append(events, order.Status)
+			return nil
+		},
+	}
+	orderCache := &mocks.OrderCacheMock{
+		DeleteFunc: func(_ context.Context, id string) error {
+			deleted = append(deleted, id)
+			return nil
+		},
+	}
+	w := newTestExpiryWorker(repo, tx, orderCache, pub)
+
+	n, err := w.ExpireOnce(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, expiryNow.Add(-24*time.Hour), gotCutoff, "cutoff is now minus the TTL")
+	assert.Equal(t, defaultExpiryBatchSize, gotLimit)
+	assert.Equal(t, []string{claimed[0].ID.String(), claimed[1].ID.String()}, updated)
+	assert.Equal(t, []domain.OrderStatus{domain.OrderStatusExpired, domain.OrderStatusExpired}, events)
+	assert.Equal(t, updated, deleted, "expired orders must be evicted from the cache")
+	assert.Equal(t, 1, tx.txs)
+}
+
+func TestExpiryWorker_ExpireOnce_RecordsHistoryMatchingEvents(t *testing.T) {
+	claimed := newPendingOrders(2)
+	var history []domain.StatusChange
+	var occurred []time.Time
+	repo := &mocks.OrderRepositoryMock{
+		ClaimPendingCreatedBeforeFunc: func(context.Context, time.Time, int) ([]*domain.Order, error) {
+			return claimed, nil
+		},
+		UpdateStatusFunc: func(_ context.Context, _ *domain.Order, change domain.StatusChange) error {
+			history = append(history, change)
+			return nil
+		},
+	}
+	pub := &mocks.EventPublisherMock{
+		PublishOrderExpiredFunc: func(ctx context.Context, _ *domain.Order) error {
+			at, ok := messaging.OccurredAt(ctx)
+			require.True(t, ok, "the event time must be pinned to the change")
+			occurred = append(occurred, at)
+			return nil
+		},
+	}
+	w := newTestExpiryWorker(repo, &txRecorder{}, nil, pub)
+
+	_, err := w.ExpireOnce(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	for i, change := range history {
+		assert.Equal(t, claimed[i].ID, change.OrderID)
+		assert.Equal(t, domain.OrderStatusPending, change.OldStatus)
+		assert.Equal(t, domain.OrderStatusExpired, change.NewStatus)
+		assert.Equal(t, ExpiryActor, change.ChangedBy)
+		assert.Equal(t, expiryNow, change.OccurredAt)
+	}
+	assert.Equal(t, []time.Time{expiryNow, expiryNow}, occurred)
+}
+
+func TestExpiryWorker_ExpireOnce_CutoffFollowsClock(t *testing.T) {
+	clock := &fakeClock{now: expiryNow}
+	var cutoffs []time.Time
+	repo := &mocks.OrderRepositoryMock{
+		ClaimPendingCreatedBeforeFunc: func(_ context.Context, cutoff time.Time, _ int) ([]*domain.Order, error) {
+			cutoffs = append(cutoffs, cutoff)
+			return nil, nil
+		},
+	}
+	w := newTestExpiryWorker(repo, &txRecorder{}, nil, nil, WithExpiryClock(clock))
+
+	_, err := w.ExpireOnce(context.Background())
+	require.NoError(t, err)
+	clock.Advance(6 * time.Hour)
+	_, err = w.ExpireOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []time.Time{expiryNow.Add(-24 * time.Hour), expiryNow.Add(-18 * time.Hour)}, cutoffs)
+}
+
+func TestExpiryWorker_ExpireOnce_NothingToExpire_IsNoop(t *testing.T) {
+	tx := &txRecorder{}
+	repo := &mocks.OrderRepositoryMock{
+		ClaimPendingCreatedBeforeFunc: func(context.Context, time.Time, int) ([]*domain.Order, error) {
+			return nil, nil
+		},
+		UpdateFunc: func(context.Context, *domain.Order) error {
+			t.Fatal("no order should be updated")
+			return nil
+		},
+	}
+	pub := &mocks.EventPublisherMock{
+		PublishOrderExpiredFunc: func(context.Context, *domain.Order) error {
+			t.Fatal("no event should be published")
+			return nil
+		},
+	}
+	w := newTestExpiryWorker(repo, tx, nil, pub)
+
+	n, err := w.ExpireOnce(context.Background())
+
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Equal(t, 1, tx.txs)
+}
+
+func TestExpiryWorker_ExpireOnce_ClaimsInBatchesUntilShort(t *testing.T) {
+	batches := [][]*domain.Order{newPendingOrders(2), newPendingOrders(2), newPendingOrders(1)}
+	tx := &txRecorder{}
+	var limits []int
+	repo := &mocks.OrderRepositoryMock{
+		ClaimPendingCreatedBeforeFunc: func(_ context.Context, _ time.Time, limit int) ([]*domain.Order, error) {
+			limits = append(limits, limit)
+			batch := batches[0]
+			batches = batches[1:]
+			return batch, nil
+		},
+	}
+	w := newTestExpiryWorker(repo, tx, nil, &mocks.EventPublisherMock{}, WithExpiryBatchSize(2))
+
+	n, err := w.ExpireOnce(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, []int{2, 2, 2}, limits)
+	assert.Equal(t, 3, tx.txs, "one transaction per batch")
+}
+
+func TestExpiryWorker_ExpireOnce_PublishFailure_RollsBackBatch(t *testing.T) {
+	claimed := newPendingOrders(2)
+	repo := &mocks.OrderRepositoryMock{
+		ClaimPendingCreatedBeforeFunc: func(context.Context, time.Time, int) ([]*domain.Order, error) {
+			return claimed, nil
+		},
+	}
+	calls := 0
+	pub := &mocks.EventPublisherMock{
+		PublishOrderExpiredFunc: func(context.Context, *domain.Order) error {
+			calls++
+			if calls == 2 {
+				return errors.New("broker unavailable")
+			}
+			return nil
+		},
+	}
+	orderCache := &mocks.OrderCacheMock{
+		DeleteFunc: func(context.Context, string) error {
+			t.Fatal("a rolled back batch must not be evicted")
+			return nil
+		},
+	}
+	w := newTestExpiryWorker(repo, &txRecorder{}, orderCache, pub)
+
+	n, err := w.ExpireOnce(context.Background())
+
+	require.Error(t, err)
+	assert.Zero(t, n, "the failed batch is rolled back and not counted")
+}
+
+func TestExpiryWorker_ExpireOnce_OnlyEligibleOrdersEmitExpired(t *testing.T) {
+	claimed := newPendingOrders(3)
+	claimed[1].CreatedAt = expiryNow.Add(-24 * time.Hour) // Exactly the TTL: not yet expired
+	claimed[2].Status = domain.OrderStatusConfirmed       // Confirmed since it was claimed
+	var saved, expired []uuid.UUID
+	repo := &mocks.OrderRepositoryMock{
+		ClaimPendingCreatedBeforeFunc: func(context.Context, time.Time, int) ([]*domain.Order, error) {
+			return claimed, nil
+		},
+		UpdateFunc: func(_ context.Context, order *domain.Order) error {
+			saved = append(saved, order.ID)
+			return nil
+		},
+	}
+	pub := &mocks.EventPublisherMock{
+		PublishOrderExpiredFunc: func(_ context.Context, order *domain.Order) error {
+			expired = append(expired, order.ID)
+			return nil
+		},
+		PublishOrderStatusChangedFunc: func(context.Context, *domain.Order, domain.OrderStatus, domain.OrderStatus) error {
+			t.Fatal("an expiry publishes order.expired, not order.status_changed")
+			return nil
+		},
+	}
+	w := newTestExpiryWorker(repo, &txRecorder{}, nil, pub)
+
+	n, err := w.ExpireOnce(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []uuid.UUID{claimed[0].ID}, expired)
+	assert.Equal(t, expired, saved)
+	assert.Equal(t, domain.OrderStatusPending, claimed[1].Status)
+	assert.Equal(t, domain.OrderStatusConfirmed, claimed[2].Status)
+}
+
+func TestExpiryWorker_ExpireOnce_ClaimError_ReturnsError(t *testing.T) {
+	repo := &mocks.OrderRepositoryMock{
+		ClaimPendingCreatedBeforeFunc: func(context.Context, time.Time, int) ([]*domain.Order, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+	w := newTestExpiryWorker(repo, &txRecorder{}, nil, &mocks.EventPublisherMock{})
+
+	_, err := w.ExpireOnce(context.Background())
+
+	assert.ErrorContains(t, err, "connection refused")
+}
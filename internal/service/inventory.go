@@ -0,0 +1,46 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+)
+
+// InventoryReserver holds stock for the lines of an order. The service
+// reserves an order's lines before it is confirmed and releases them when a
+// confirmed order is cancelled, or when the confirmation fails to commit.
+type InventoryReserver interface {
+	// Reserve holds stock for lines, failing if any line cannot be held.
+	Reserve(ctx context.Context, lines []domain.OrderItem) error
+	// Release returns the stock held for lines.
+	Release(ctx context.Context, lines []domain.OrderItem) error
+}
+
+// noopReserver reserves nothing. It is the default InventoryReserver, for
+// deployments that do not track stock.
+type noopReserver struct{}
+
+func (noopReserver) Reserve(context.Context, []domain.OrderItem) error { return nil }
+func (noopReserver) Release(context.Context, []domain.OrderItem) error { return nil }
+
+// WithInventoryReserver reserves stock with r when orders are confirmed and
+// releases it when they are cancelled. Defaults to reserving nothing.
+func WithInventoryReserver(r InventoryReserver) Option {
+	return func(s *orderServiceImpl) {
+		s.inventory = r
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
+)
+
+// MetricsAggregator periodically publishes an order.metrics rollup: per
+// status, the number of orders created in a window and their revenue by
+// currency.
+//
+// Windows are interval long and aligned to multiples of it, so each order
+// falls in exactly one, and each run rolls up every window that ended
+// since the last. A window is claimed, aggregated and published in one
+// transaction, so replicas running the aggregator at once publish each
+// window only once; a failed publish rolls the claim back for the next run
+// to retry. Windows that ended while no aggregator was running are not
+// rolled up.
+type MetricsAggregator struct {
+	store      repository.OrderMetricsStore
+	transactor repository.Transactor
+	publisher  messaging.MetricsPublisher
+	interval   time.Duration
+	clock      messaging.Clock
+	logger     *slog.Logger
+
+	mu   sync.Mutex // Held by AggregateOnce
+	next time.Time  // Start of the first window not rolled up, zero before the first run
+}
+
+// MetricsOption configures optional MetricsAggregator settings
+type MetricsOption func(*MetricsAggregator)
+
+// WithMetricsClock sets the clock that decides which windows have ended.
+// Defaults to messaging.SystemClock.
+func WithMetricsClock(c messaging.Clock) MetricsOption {
+	return func(a *MetricsAggregator) {
+		a.clock = c
+	}
+}
+
+// WithMetricsLogger sets where failed runs and the windows published are
+// logged. Defaults to logging nothing.
+func WithMetricsLogger(logger *slog.Logger) MetricsOption {
+	return func(a *MetricsAggregator) {
+		a.logger = logger
+	}
+}
+
+// NewMetricsAggregator creates an aggregator that every interval publishes
+// the rollups of the windows that ended, each interval long.
+func NewMetricsAggregator(store repository.OrderMetricsStore, transactor repository.Transactor, publisher messaging.MetricsPublisher, interval time.Duration, opts ...MetricsOption) *MetricsAggregator {
+	a := &MetricsAggregator{
+		store:      store,
+		transactor: transactor,
+		publisher:  publisher,
+		interval:   interval,
+		clock:      messaging.SystemClock{},
+		logger:     discardLogger,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Run publishes rollups every interval until ctx is cancelled.
+func (a *MetricsAggregator) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := a.AggregateOnce(ctx); err != nil && ctx.Err() == nil {
+			a.logger.Warn("order metrics rollup failed", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// AggregateOnce rolls up, oldest first, every window that ended since the
+// last call, or only the last window to end on the first call, and returns
+// how many rollups it published. Windows another aggregator already
+// claimed are skipped. It stops at the first failed window, which the
+// next call retries.
+func (a *MetricsAggregator) AggregateOnce(ctx context.Context) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	end := a.clock.Now().Truncate(a.interval)
+	if a.next.IsZero() {
+		a.next = end.Add(-a.interval)
+	}
+	published := 0
+	for a.next.Before(end) {
+		start := a.next
+		ok, err := a.aggregate(ctx, start, start.Add(a.interval))
+		if err != nil {
+			return published, fmt.Errorf("order metrics window %s: %w", start.UTC().Format(time.RFC3339), err)
+		}
+		if ok {
+			published++
+			a.logger.Info("published order metrics", slog.Time("window_start", start), slog.Duration("window", a.interval))
+		}
+		a.next = start.Add(a.interval)
+	}
+	return published, nil
+}
+
+// aggregate claims, rolls up and publishes the window [start, end) in one
+// transaction, reporting false if it was already claimed.
+func (a *MetricsAggregator) aggregate(ctx context.Context, start, end time.Time) (bool, error) {
+	claimed := false
+	err := a.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if claimed, err = a.store.ClaimWindow(ctx, start, end); err != nil || !claimed {
+			return err
+		}
+		totals, err := a.store.SumByStatus(ctx, start, end)
+		if err != nil {
+			return err
+		}
+		evt := messaging.NewOrderMetricsEvent(start, end, statusMetrics(totals))
+		evt.OccurredAt = a.clock.Now()
+		return a.publisher.PublishOrderMetrics(ctx, evt)
+	})
+	if err != nil {
+		return false, err
+	}
+	return claimed, nil
+}
+
+// statusMetrics groups totals, ordered by status, by status.
+func statusMetrics(totals []repository.StatusTotal) []messaging.StatusMetrics {
+	var metrics []messaging.StatusMetrics
+	for _, t := range totals {
+		if len(metrics) == 0 || metrics[len(metrics)-1].Status != t.Status {
+			metrics = append(metrics, messaging.StatusMetrics{Status: t.Status})
+		}
+		m := &metrics[len(metrics)-1]
+		m.Count += t.Count
+		m.Revenue = append(m.Revenue, messaging.RevenueMetrics{
+			Currency:   t.Currency,
+			Total:      domain.Money{Amount: t.TotalMinor, Currency: t.Currency}.Float64(),
+			TotalMinor: t.TotalMinor,
+		})
+	}
+	return metrics
+}
@@ -0,0 +1,203 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	memrepo "github.com/sridharn-code-sandbox/go-ordersvc/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// metricsWindow is the window the aggregator's first run rolls up when its
+// clock reads metricsNow.
+var (
+	metricsWindow = time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	metricsNow    = metricsWindow.Add(time.Hour + 5*time.Minute)
+)
+
+// metricsRecorder records the rollups published to it, failing them with
+// err while it is set.
+type metricsRecorder struct {
+	mu     sync.Mutex
+	events []messaging.OrderMetricsEvent
+	err    error
+}
+
+func (r *metricsRecorder) PublishOrderMetrics(_ context.Context, evt messaging.OrderMetricsEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.events = append(r.events, evt)
+	return nil
+}
+
+// seedMetricsOrders stores orders created in and around metricsWindow.
+func seedMetricsOrders(t *testing.T, repo *memrepo.OrderRepository) {
+	t.Helper()
+	seed := func(offset time.Duration, status domain.OrderStatus, minor int64, currency string) {
+		order := &domain.Order{
+			CustomerID: "cust-1",
+			Status:     status,
+			Total:      domain.Money{Amount: minor, Currency: currency},
+			CreatedAt:  metricsWindow.Add(offset),
+			UpdatedAt:  metricsWindow.Add(offset),
+		}
+		require.NoError(t, repo.Create(context.Background(), order))
+	}
+	seed(0, domain.OrderStatusPending, 1000, "USD")
+	seed(15*time.Minute, domain.OrderStatusPending, 2550, "USD")
+	seed(30*time.Minute, domain.OrderStatusConfirmed, 4000, "USD")
+	seed(45*time.Minute, domain.OrderStatusConfirmed, 700, "EUR")
+	seed(-time.Minute, domain.OrderStatusPending, 99, "USD") // The window before
+	seed(time.Hour, domain.OrderStatusShipped, 99, "USD")    // The window after
+}
+
+func newTestMetricsAggregator(repo *memrepo.OrderRepository, pub *metricsRecorder, clock *fakeClock) *MetricsAggregator {
+	return NewMetricsAggregator(repo, memrepo.NewTransactor(repo), pub, time.Hour, WithMetricsClock(clock))
+}
+
+func TestMetricsAggregator_AggregateOnce_RollsUpSeededWindow(t *testing.T) {
+	repo := memrepo.NewOrderRepository()
+	seedMetricsOrders(t, repo)
+	pub := &metricsRecorder{}
+
+	n, err := newTestMetricsAggregator(repo, pub, &fakeClock{now: metricsNow}).AggregateOnce(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, pub.events, 1)
+	evt := pub.events[0]
+	assert.Equal(t, messaging.EventOrderMetrics, evt.EventType)
+	assert.Equal(t, metricsWindow, evt.WindowStart)
+	assert.Equal(t, metricsWindow.Add(time.Hour), evt.WindowEnd)
+	assert.Equal(t, metricsNow, evt.OccurredAt)
+	want := map[domain.OrderStatus]messaging.StatusMetrics{
+		domain.OrderStatusPending: {Status: domain.OrderStatusPending, Count: 2, Revenue: []messaging.RevenueMetrics{
+			{Currency: "USD", Total: 35.50, TotalMinor: 3550},
+		}},
+		domain.OrderStatusConfirmed: {Status: domain.OrderStatusConfirmed, Count: 2, Revenue: []messaging.RevenueMetrics{
+			{Currency: "EUR", Total: 7, TotalMinor: 700},
+			{Currency: "USD", Total: 40, TotalMinor: 4000},
+		}},
+	}
+	require.Len(t, evt.Statuses, len(domain.ValidStatuses()))
+	for _, got := range evt.Statuses {
+		if w, ok := want[got.Status]; ok {
+			assert.Equal(t, w, got)
+			continue
+		}
+		assert.Equal(t, messaging.StatusMetrics{Status: got.Status, Revenue: []messaging.RevenueMetrics{}}, got)
+	}
+}
+
+func TestMetricsAggregator_AggregateOnce_EmptyWindow_PublishesZeros(t *testing.T) {
+	repo := memrepo.NewOrderRepository()
+	pub := &metricsRecorder{}
+
+	n, err := newTestMetricsAggregator(repo, pub, &fakeClock{now: metricsNow}).AggregateOnce(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	require.Len(t, pub.events, 1)
+	require.Len(t, pub.events[0].Statuses, len(domain.ValidStatuses()))
+	for _, s := range pub.events[0].Statuses {
+		assert.Zero(t, s.Count, s.Status)
+		assert.Empty(t, s.Revenue, s.Status)
+	}
+}
+
+func TestMetricsAggregator_AggregateOnce_EachWindowOnce(t *testing.T) {
+	repo := memrepo.NewOrderRepository()
+	seedMetricsOrders(t, repo)
+	pub := &metricsRecorder{}
+	clock := &fakeClock{now: metricsNow}
+	agg := newTestMetricsAggregator(repo, pub, clock)
+	ctx := context.Background()
+
+	_, err := agg.AggregateOnce(ctx)
+	require.NoError(t, err)
+	n, err := agg.AggregateOnce(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n, "no window ended since the last run")
+
+	clock.Advance(2 * time.Hour)
+	n, err = agg.AggregateOnce(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n, "both windows that ended since")
+
+	require.Len(t, pub.events, 3)
+	for i, evt := range pub.events {
+		assert.Equal(t, metricsWindow.Add(time.Duration(i)*time.Hour), evt.WindowStart, "contiguous windows")
+	}
+	var shipped int64
+	for _, evt := range pub.events {
+		for _, s := range evt.Statuses {
+			if s.Status == domain.OrderStatusShipped {
+				shipped += s.Count
+			}
+		}
+	}
+	assert.Equal(t, int64(1), shipped, "the order on a window boundary is counted once")
+}
+
+func TestMetricsAggregator_ConcurrentAggregators_PublishEachWindowOnce(t *testing.T) {
+	repo := memrepo.NewOrderRepository()
+	seedMetricsOrders(t, repo)
+	pub := &metricsRecorder{}
+
+	var wg sync.WaitGroup
+	published := make([]int, 4)
+	for i := range published {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := newTestMetricsAggregator(repo, pub, &fakeClock{now: metricsNow}).AggregateOnce(context.Background())
+			assert.NoError(t, err)
+			published[i] = n
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, pub.events, 1)
+	assert.ElementsMatch(t, []int{1, 0, 0, 0}, published)
+}
+
+func TestMetricsAggregator_AggregateOnce_PublishFails_RetriedNextRun(t *testing.T) {
+	repo := memrepo.NewOrderRepository()
+	errBroker := errors.New("broker down")
+	pub := &metricsRecorder{err: errBroker}
+	agg := newTestMetricsAggregator(repo, pub, &fakeClock{now: metricsNow})
+
+	n, err := agg.AggregateOnce(context.Background())
+	require.ErrorIs(t, err, errBroker)
+	assert.Zero(t, n)
+
+	pub.err = nil
+	n, err = agg.AggregateOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "the failed publish released its claim")
+	require.Len(t, pub.events, 1)
+	assert.Equal(t, metricsWindow, pub.events[0].WindowStart)
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
 )
 
 // OrderService defines business logic operations for orders
@@ -25,18 +26,53 @@ type OrderService interface {
 	// CreateOrder creates a new order with validation
 	CreateOrder(ctx context.Context, dto CreateOrderDTO) (*domain.Order, error)
 
+	// CreateOrderIdempotent creates an order at most once per idempotency key.
+	// A repeat with the same key and request returns the original order with
+	// replayed set to true. An empty key behaves like CreateOrder.
+	CreateOrderIdempotent(ctx context.Context, key string, dto CreateOrderDTO) (order *domain.Order, replayed bool, err error)
+
+	// BulkCreate creates orders all-or-nothing in one transaction and
+	// publishes order.created for each once they are stored. A batch with
+	// invalid orders is rejected up front with a *domain.BulkError.
+	BulkCreate(ctx context.Context, orders []*domain.Order) error
+
 	// GetOrderByID retrieves an order by ID, checking cache first
 	GetOrderByID(ctx context.Context, id string) (*domain.Order, error)
 
+	// GetOrderByIDIncludingDeleted retrieves an order by ID even if it has
+	// been soft-deleted. It bypasses the cache.
+	GetOrderByIDIncludingDeleted(ctx context.Context, id string) (*domain.Order, error)
+
 	// UpdateOrder updates an existing order
 	UpdateOrder(ctx context.Context, id string, dto UpdateOrderDTO) (*domain.Order, error)
 
-	// DeleteOrder soft-deletes an order
+	// DeleteOrder soft-deletes an order and publishes order.deleted
 	DeleteOrder(ctx context.Context, id string) error
 
-	// ListOrders returns paginated orders with optional status filter
+	// RestoreOrder undoes a soft delete and publishes order.updated.
+	// Returns domain.ErrOrderNotDeleted if the order is not deleted.
+	RestoreOrder(ctx context.Context, id string) (*domain.Order, error)
+
+	// ListOrders returns paginated orders matching the filters of req.
+	// Invalid filter values return a *domain.FilterError.
 	ListOrders(ctx context.Context, req ListOrdersRequest) (*domain.PaginatedOrders, error)
 
 	// UpdateOrderStatus transitions order to new status with validation
 	UpdateOrderStatus(ctx context.Context, id string, newStatus domain.OrderStatus) (*domain.Order, error)
+
+	// GetOrderHistory returns the status changes of an order, oldest first.
+	// Returns domain.ErrOrderNotFound if the order doesn't exist.
+	GetOrderHistory(ctx context.Context, id string) ([]domain.StatusChange, error)
+
+	// GetOrderSnapshot returns the committed state of an order as an
+	// order.snapshot event (see messaging.NewSnapshotEvent). It bypasses
+	// the cache.
+	// Returns domain.ErrOrderNotFound if the order doesn't exist or is
+	// deleted, since a snapshot cannot express a deletion.
+	GetOrderSnapshot(ctx context.Context, id string) (messaging.OrderEvent, error)
+
+	// CancelOrder cancels an order, recording reason, and publishes
+	// order.cancelled carrying it.
+	// Returns a *domain.CancelError if the order has already shipped.
+	CancelOrder(ctx context.Context, id, reason string) (*domain.Order, error)
 }
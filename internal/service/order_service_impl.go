@@ -16,94 +16,397 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"log/slog"
+	"maps"
 	"math"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/cache"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/noop"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
 )
 
-const orderCacheTTL = 5 * time.Minute
+const (
+	orderCacheTTL         = 5 * time.Minute
+	defaultIdempotencyTTL = 24 * time.Hour
+)
 
 // orderServiceImpl implements OrderService
 type orderServiceImpl struct {
-	repo      repository.OrderRepository
-	cache     cache.OrderCache
-	publisher EventPublisher
+	repo       repository.OrderRepository
+	cache      cache.OrderCache
+	publisher  EventPublisher
+	transactor repository.Transactor
+
+	idempotency    cache.IdempotencyStore
+	idempotencyTTL time.Duration
+
+	baseCurrency string
+	clock        messaging.Clock
+	ids          domain.IDGenerator
+	inventory    InventoryReserver
+	payments     PaymentAuthorizer
+
+	quota   int // Open orders a customer may have; 0 for no limit
+	quotaTx repository.Transactor
+
+	logger *slog.Logger
 }
 
-// NewOrderService creates a new OrderService
-func NewOrderService(repo repository.OrderRepository, orderCache cache.OrderCache, publisher EventPublisher) OrderService {
-	return &orderServiceImpl{
-		repo:      repo,
-		cache:     orderCache,
-		publisher: publisher,
+// Option configures optional OrderService dependencies
+type Option func(*orderServiceImpl)
+
+// WithTransactor runs each order write and its event publish in one
+// transaction. Use it with a transactional publisher such as
+// outbox.Publisher: a publish failure then rolls back the write, so an
+// order is never committed without its event.
+func WithTransactor(t repository.Transactor) Option {
+	return func(s *orderServiceImpl) {
+		s.transactor = t
+	}
+}
+
+// WithIdempotencyStore enables idempotency keys for CreateOrderIdempotent.
+// Keys expire after ttl, or 24h if ttl is not positive.
+func WithIdempotencyStore(store cache.IdempotencyStore, ttl time.Duration) Option {
+	return func(s *orderServiceImpl) {
+		s.idempotency = store
+		s.idempotencyTTL = ttl
+		if ttl <= 0 {
+			s.idempotencyTTL = defaultIdempotencyTTL
+		}
+	}
+}
+
+// WithBaseCurrency sets the currency of orders created without one.
+// Defaults to domain.DefaultCurrency.
+func WithBaseCurrency(code string) Option {
+	return func(s *orderServiceImpl) {
+		s.baseCurrency = code
+	}
+}
+
+// WithClock sets the clock that stamps orders' CreatedAt and UpdatedAt,
+// their status changes and the OccurredAt of the events published for
+// them. Defaults to messaging.SystemClock.
+func WithClock(c messaging.Clock) Option {
+	return func(s *orderServiceImpl) {
+		s.clock = c
+	}
+}
+
+// WithIDGenerator sets the generator of the IDs of new orders and their
+// items, e.g. domain.TimeOrderedIDs for IDs that sort by creation time.
+// Defaults to domain.RandomIDs.
+func WithIDGenerator(g domain.IDGenerator) Option {
+	return func(s *orderServiceImpl) {
+		s.ids = g
+	}
+}
+
+// WithOrderQuota refuses to create an order for a customer who already
+// has limit open orders, ones neither in a terminal status nor deleted,
+// with a *domain.QuotaError. The count and the insert run in one
+// transaction of t under a per-customer lock, so concurrent creates cannot
+// both slip under the limit. A limit below 1 sets no quota.
+func WithOrderQuota(limit int, t repository.Transactor) Option {
+	return func(s *orderServiceImpl) {
+		s.quota = limit
+		s.quotaTx = t
 	}
 }
 
+// WithLogger sets where the service logs the failures it recovers from,
+// such as a cache error or an event that could not be published after its
+// order was saved. Any slog.Handler can back logger, to route the records
+// into another logging library. Defaults to logging nothing.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *orderServiceImpl) {
+		s.logger = logger
+	}
+}
+
+// discardLogger is the default logger of the service and its workers,
+// which drops every record.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// NewOrderService creates a new OrderService
+func NewOrderService(repo repository.OrderRepository, orderCache cache.OrderCache, publisher EventPublisher, opts ...Option) OrderService {
+	return newOrderService(repo, orderCache, publisher, opts...)
+}
+
+func newOrderService(repo repository.OrderRepository, orderCache cache.OrderCache, publisher EventPublisher, opts ...Option) *orderServiceImpl {
+	s := &orderServiceImpl{
+		repo:         repo,
+		cache:        orderCache,
+		publisher:    noop.OrNoop(publisher),
+		baseCurrency: domain.DefaultCurrency,
+		clock:        messaging.SystemClock{},
+		ids:          domain.RandomIDs,
+		inventory:    noopReserver{},
+		payments:     noopAuthorizer{},
+		logger:       discardLogger,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
 func (s *orderServiceImpl) CreateOrder(ctx context.Context, dto CreateOrderDTO) (*domain.Order, error) {
-	// Validate customer ID
-	if dto.CustomerID == "" {
-		return nil, domain.ErrInvalidCustomerID
+	order, err := s.newOrder(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save to repository, then publish event
+	err = s.saveAndPublish(ctx, order, messaging.EventOrderCreated,
+		s.withinQuota(order.CustomerID, func(ctx context.Context) error { return s.repo.Create(ctx, order) }),
+		func(ctx context.Context) error { return s.publisher.PublishOrderCreated(ctx, order) },
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	// Validate items
-	if len(dto.Items) == 0 {
-		return nil, domain.ErrNoItems
+	return order, nil
+}
+
+// newOrder builds and validates the pending order dto describes, with new
+// IDs for it and its items.
+func (s *orderServiceImpl) newOrder(dto CreateOrderDTO) (*domain.Order, error) {
+	currency := dto.Currency
+	if currency == "" {
+		currency = s.baseCurrency
+	}
+	if !domain.ValidCurrency(currency) {
+		return nil, domain.ErrInvalidCurrency
 	}
 
 	// Create order items with IDs and calculate subtotals
-	items := make([]domain.OrderItem, len(dto.Items))
-	for i, item := range dto.Items {
-		// Validate item
-		if err := item.Validate(); err != nil {
-			return nil, err
-		}
-
-		items[i] = domain.OrderItem{
-			ID:        uuid.New(),
-			ProductID: item.ProductID,
-			Name:      item.Name,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
-			Subtotal:  item.CalculateSubtotal(),
-		}
+	items, err := s.newOrderItems(dto.Items, currency)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create order
+	now := s.clock.Now()
 	order := &domain.Order{
-		ID:         uuid.New(),
+		ID:         s.ids.NewID(),
 		CustomerID: dto.CustomerID,
+		Total:      domain.Money{Currency: currency},
 		Items:      items,
 		Status:     domain.OrderStatusPending,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
+		CreatedAt:  now,
+		UpdatedAt:  now,
 	}
 
-	// Calculate total
-	order.Total = order.CalculateTotal()
+	// Calculate total, rejecting items priced in another currency
+	if _, err := order.RecalculateTotal(); err != nil {
+		return nil, err
+	}
 
-	// Validate order
+	// Validate order, reporting every invalid field
 	if err := order.Validate(); err != nil {
 		return nil, err
 	}
+	return order, nil
+}
 
-	// Save to repository
-	if err := s.repo.Create(ctx, order); err != nil {
-		return nil, err
+// CreateOrderIdempotent creates an order unless key was already used.
+// The key is reserved before the order is created, so concurrent retries
+// cannot both create one: the loser gets ErrIdempotencyKeyInFlight.
+// Reusing a key with a different request returns ErrIdempotencyKeyReused.
+func (s *orderServiceImpl) CreateOrderIdempotent(ctx context.Context, key string, dto CreateOrderDTO) (*domain.Order, bool, error) {
+	if key == "" || s.idempotency == nil {
+		order, err := s.CreateOrder(ctx, dto)
+		return order, false, err
 	}
 
-	// Publish event (warn + continue on failure)
-	if s.publisher != nil {
-		if err := s.publisher.PublishOrderCreated(ctx, order); err != nil {
-			slog.Warn("failed to publish order.created event", slog.String("order_id", order.ID.String()), slog.String("error", err.Error()))
+	hash, err := requestHash(dto)
+	if err != nil {
+		return nil, false, err
+	}
+
+	existing, err := s.idempotency.Reserve(ctx, key, hash, s.idempotencyTTL)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		switch {
+		case existing.RequestHash != hash:
+			return nil, false, domain.ErrIdempotencyKeyReused
+		case existing.OrderID == "":
+			return nil, false, domain.ErrIdempotencyKeyInFlight
 		}
+		order, err := s.GetOrderByID(ctx, existing.OrderID)
+		if err != nil {
+			return nil, false, err
+		}
+		return order, true, nil
 	}
 
-	return order, nil
+	order, err := s.CreateOrder(ctx, dto)
+	if err != nil {
+		// Free the key so the client can retry the failed request
+		if relErr := s.idempotency.Release(ctx, key); relErr != nil {
+			s.logger.Warn("idempotency key release failed", slog.String("error", relErr.Error()))
+		}
+		return nil, false, err
+	}
+
+	rec := cache.IdempotencyRecord{RequestHash: hash, OrderID: order.ID.String()}
+	if err := s.idempotency.Complete(ctx, key, rec, s.idempotencyTTL); err != nil {
+		// The order exists; retries see the key as in flight until it expires
+		s.logger.Warn("idempotency key completion failed",
+			slog.String("order_id", order.ID.String()),
+			slog.String("error", err.Error()))
+	}
+
+	return order, false, nil
+}
+
+// BulkCreate validates every order and completes it as CreateOrder would:
+// missing order and item IDs are generated, the status is set to pending,
+// the timestamps to now, a missing currency to the base currency, and the
+// subtotals and total are computed. If any order is invalid, nothing is
+// stored and the *domain.BulkError lists each invalid order by index.
+//
+// The orders are then stored in one transaction. Their events are published
+// once it commits or, with a transactor, inside the same transaction, where
+// a publish failure rolls back the whole batch. With an order quota, the
+// batch is rejected with a *domain.QuotaError, and nothing is stored, if it
+// would take a customer over the quota.
+func (s *orderServiceImpl) BulkCreate(ctx context.Context, orders []*domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	bulkErr := &domain.BulkError{}
+	now := s.clock.Now()
+	for i, order := range orders {
+		if err := s.prepareNewOrder(order, now); err != nil {
+			bulkErr.Failed = append(bulkErr.Failed, domain.BulkItemError{Index: i, Err: err})
+		}
+	}
+	if len(bulkErr.Failed) > 0 {
+		return bulkErr
+	}
+	ctx = messaging.WithOccurredAt(ctx, now)
+	customerIDs := make([]string, len(orders))
+	for i, order := range orders {
+		customerIDs[i] = order.CustomerID
+	}
+	save := s.withinQuotas(customerIDs, func(ctx context.Context) error { return s.repo.BulkCreate(ctx, orders) })
+
+	if s.transactor != nil {
+		return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+			if err := save(ctx); err != nil {
+				return err
+			}
+			for _, order := range orders {
+				if err := s.publisher.PublishOrderCreated(messaging.WithEventID(ctx, order.LastEventID), order); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
+	if err := save(ctx); err != nil {
+		return err
+	}
+	// Publish events (warn + continue on failure)
+	for _, order := range orders {
+		if err := s.publisher.PublishOrderCreated(messaging.WithEventID(ctx, order.LastEventID), order); err != nil {
+			s.logger.Warn("failed to publish "+messaging.EventOrderCreated+" event", slog.String("order_id", order.ID.String()), slog.String("error", err.Error()))
+		}
+	}
+	return nil
+}
+
+// prepareNewOrder completes order for BulkCreate as a newly created,
+// pending order stamped with now, and validates it. A nil order is
+// invalid, with ErrNilOrder.
+func (s *orderServiceImpl) prepareNewOrder(order *domain.Order, now time.Time) error {
+	if order == nil {
+		return domain.ValidationErrors{"order": domain.ErrNilOrder}
+	}
+	if order.ID == uuid.Nil {
+		order.ID = s.ids.NewID()
+	}
+	order.Status = domain.OrderStatusPending
+	order.CreatedAt, order.UpdatedAt = now, now
+	order.LastEventID = uuid.NewString() // The ID of its order.created event
+
+	// An order without a currency takes its items', or the base currency
+	if order.Total.Currency == "" {
+		order.Total.Currency = s.baseCurrency
+		if len(order.Items) > 0 && order.Items[0].Price.Currency != "" {
+			order.Total.Currency = order.Items[0].Price.Currency
+		}
+	}
+	for i := range order.Items {
+		if order.Items[i].ID == uuid.Nil {
+			order.Items[i].ID = s.ids.NewID()
+		}
+	}
+	// Computes the subtotals, rejecting items priced in another currency
+	if _, err := order.RecalculateTotal(); err != nil {
+		return err
+	}
+	return order.Validate()
+}
+
+// requestHash fingerprints a create request so a reused key can be told
+// apart from a genuine retry.
+func requestHash(dto CreateOrderDTO) (string, error) {
+	data, err := json.Marshal(dto)
+	if err != nil {
+		return "", fmt.Errorf("hash create request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// newOrderItems builds order items from dtos, with IDs and subtotals,
+// leaving their validation to domain.Order.Validate. Prices are parsed in
+// the item's currency, or currency if the item has none.
+func (s *orderServiceImpl) newOrderItems(dtos []OrderItemDTO, currency string) ([]domain.OrderItem, error) {
+	items := make([]domain.OrderItem, len(dtos))
+	for i, dto := range dtos {
+		itemCurrency := dto.Currency
+		if itemCurrency == "" {
+			itemCurrency = currency
+		}
+		if !domain.ValidCurrency(itemCurrency) {
+			return nil, domain.ErrInvalidCurrency
+		}
+		price, err := domain.ParseMoney(dto.Price, itemCurrency)
+		if err != nil {
+			return nil, err
+		}
+
+		item := domain.OrderItem{
+			ID:        s.ids.NewID(),
+			ProductID: dto.ProductID,
+			Name:      dto.Name,
+			Quantity:  dto.Quantity,
+			Price:     price,
+		}
+		if item.Subtotal, err = item.CalculateSubtotal(); err != nil {
+			return nil, err
+		}
+		items[i] = item
+	}
+	return items, nil
 }
 
 func (s *orderServiceImpl) GetOrderByID(ctx context.Context, id string) (*domain.Order, error) {
@@ -111,7 +414,7 @@ func (s *orderServiceImpl) GetOrderByID(ctx context.Context, id string) (*domain
 	if s.cache != nil {
 		cached, err := s.cache.Get(ctx, id)
 		if err != nil {
-			slog.Warn("cache get failed", slog.String("order_id", id), slog.String("error", err.Error()))
+			s.logger.Warn("cache get failed", slog.String("order_id", id), slog.String("error", err.Error()))
 		} else if cached != nil {
 			return cached, nil
 		}
@@ -129,7 +432,7 @@ func (s *orderServiceImpl) GetOrderByID(ctx context.Context, id string) (*domain
 	// Populate cache
 	if s.cache != nil {
 		if err := s.cache.Set(ctx, order, orderCacheTTL); err != nil {
-			slog.Warn("cache set failed", slog.String("order_id", id), slog.String("error", err.Error()))
+			s.logger.Warn("cache set failed", slog.String("order_id", id), slog.String("error", err.Error()))
 		}
 	}
 
@@ -149,47 +452,74 @@ func (s *orderServiceImpl) UpdateOrder(ctx context.Context, id string, dto Updat
 	if order == nil {
 		return nil, domain.ErrOrderNotFound
 	}
+	prev := *order
+	prev.Items = slices.Clone(order.Items)
+
+	// Update customer if provided
+	if dto.CustomerID != nil {
+		if *dto.CustomerID == "" {
+			return nil, domain.ErrInvalidCustomerID
+		}
+		order.CustomerID = *dto.CustomerID
+	}
 
 	// Update items if provided
 	if len(dto.Items) > 0 {
-		items := make([]domain.OrderItem, len(dto.Items))
-		for i, item := range dto.Items {
-			if err := item.Validate(); err != nil {
-				return nil, err
-			}
-
-			items[i] = domain.OrderItem{
-				ID:        uuid.New(),
-				ProductID: item.ProductID,
-				Name:      item.Name,
-				Quantity:  item.Quantity,
-				Price:     item.Price,
-				Subtotal:  item.CalculateSubtotal(),
-			}
+		items, err := s.newOrderItems(dto.Items, order.Total.Currency)
+		if err != nil {
+			return nil, err
 		}
 		order.Items = items
-		order.Total = order.CalculateTotal()
+		if _, err := order.RecalculateTotal(); err != nil {
+			return nil, err
+		}
+		if err := order.Validate(); err != nil {
+			return nil, err
+		}
 	}
 
 	// Update status if provided
+	now := s.clock.Now()
 	if dto.Status != nil {
-		if !order.Status.CanTransitionTo(*dto.Status) {
-			return nil, domain.ErrInvalidTransition
+		if err := order.TransitionTo(*dto.Status, now); err != nil {
+			return nil, err
 		}
-		order.Status = *dto.Status
 	}
 
-	order.UpdatedAt = time.Now()
-
-	// Save to repository
-	if err := s.repo.Update(ctx, order); err != nil {
+	// An update that changes nothing is neither saved nor published, so
+	// consumers only see order.updated for real edits. The version check of
+	// the save makes sure prev is still the stored state it replaces, so the
+	// changes are those made to it
+	changes := messaging.NewChanges(&prev, order)
+	if changes == nil {
+		return order, nil
+	}
+	order.UpdatedAt = now
+
+	// Save to repository, recording any status change, then publish event.
+	// A status change reserves or releases stock as UpdateOrderStatus does
+	publish := func(ctx context.Context) error { return s.publisher.PublishOrderUpdated(ctx, order, changes) }
+	if order.Status != prev.Status {
+		var change domain.StatusChange
+		ctx, change = newStatusChange(ctx, order, prev.Status, order.UpdatedAt)
+		err = s.saveTransition(ctx, order, prev.Status, messaging.EventOrderUpdated,
+			func(ctx context.Context) error { return s.repo.UpdateStatus(ctx, order, change) },
+			publish,
+		)
+	} else {
+		err = s.saveAndPublish(ctx, order, messaging.EventOrderUpdated,
+			func(ctx context.Context) error { return s.repo.Update(ctx, order) },
+			publish,
+		)
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	// Publish event (warn + continue on failure)
-	if s.publisher != nil {
-		if err := s.publisher.PublishOrderUpdated(ctx, order); err != nil {
-			slog.Warn("failed to publish order.updated event", slog.String("order_id", order.ID.String()), slog.String("error", err.Error()))
+	// Invalidate cache so reads see the update
+	if s.cache != nil {
+		if err := s.cache.Delete(ctx, id); err != nil {
+			s.logger.Warn("cache delete failed", slog.String("order_id", id), slog.String("error", err.Error()))
 		}
 	}
 
@@ -207,8 +537,98 @@ func (s *orderServiceImpl) DeleteOrder(ctx context.Context, id string) error {
 		return domain.ErrOrderNotFound
 	}
 
-	// Soft delete
-	return s.repo.Delete(ctx, id)
+	// Soft delete, then publish order.deleted with the stored deleted_at
+	// and version
+	err = s.saveAndPublish(ctx, order, messaging.EventOrderDeleted,
+		func(ctx context.Context) error {
+			now := s.clock.Now()
+			order.DeletedAt = &now
+			if err := s.repo.Delete(ctx, order); err != nil {
+				return err
+			}
+			return s.reload(ctx, order)
+		},
+		func(ctx context.Context) error { return s.publisher.PublishOrderDeleted(ctx, order) },
+	)
+	if err != nil {
+		return err
+	}
+
+	// Invalidate cache so reads stop returning the deleted order
+	if s.cache != nil {
+		if err := s.cache.Delete(ctx, id); err != nil {
+			s.logger.Warn("cache delete failed", slog.String("order_id", id), slog.String("error", err.Error()))
+		}
+	}
+
+	return nil
+}
+
+// reload replaces order with its stored state, including deleted_at and
+// the version the repository assigned.
+func (s *orderServiceImpl) reload(ctx context.Context, order *domain.Order) error {
+	stored, err := s.repo.FindByIDIncludingDeleted(ctx, order.ID.String())
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		return domain.ErrOrderNotFound
+	}
+	*order = *stored
+	return nil
+}
+
+func (s *orderServiceImpl) GetOrderByIDIncludingDeleted(ctx context.Context, id string) (*domain.Order, error) {
+	// The cache only holds live orders, so always read the repository
+	order, err := s.repo.FindByIDIncludingDeleted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order == nil {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	return order, nil
+}
+
+func (s *orderServiceImpl) RestoreOrder(ctx context.Context, id string) (*domain.Order, error) {
+	order, err := s.repo.FindByIDIncludingDeleted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order == nil {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	if order.DeletedAt == nil {
+		return nil, domain.ErrOrderNotDeleted
+	}
+
+	// Restore, then publish order.updated with the stored version
+	err = s.saveAndPublish(ctx, order, messaging.EventOrderUpdated,
+		func(ctx context.Context) error {
+			order.UpdatedAt = s.clock.Now()
+			if err := s.repo.Restore(ctx, order); err != nil {
+				return err
+			}
+			return s.reload(ctx, order)
+		},
+		func(ctx context.Context) error { return s.publisher.PublishOrderUpdated(ctx, order, nil) },
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate cache
+	if s.cache != nil {
+		if err := s.cache.Delete(ctx, id); err != nil {
+			s.logger.Warn("cache delete failed", slog.String("order_id", id), slog.String("error", err.Error()))
+		}
+	}
+
+	return order, nil
 }
 
 func (s *orderServiceImpl) ListOrders(ctx context.Context, req ListOrdersRequest) (*domain.PaginatedOrders, error) {
@@ -231,9 +651,31 @@ func (s *orderServiceImpl) ListOrders(ctx context.Context, req ListOrdersRequest
 
 	// Build list options
 	opts := repository.ListOptions{
-		Limit:  pageSize,
-		Offset: offset,
-		Status: req.Status,
+		Limit:         pageSize,
+		Offset:        offset,
+		Status:        req.Status,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+	}
+	if err := s.applyListFilters(&opts, req); err != nil {
+		return nil, err
+	}
+
+	// In cursor mode fetch one extra row to learn whether another page exists
+	if req.Cursor != "" {
+		cursor, err := domain.DecodeCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		opts.After = &cursor
+		opts.Offset = 0
+		opts.Limit = pageSize + 1
+	}
+
+	// Listings tolerate replication lag, so a replica may serve them
+	// unless the caller asked for strong consistency
+	if _, ok := repository.ConsistencyFrom(ctx); !ok {
+		ctx = repository.WithConsistency(ctx, repository.Eventual)
 	}
 
 	// Get orders from repository
@@ -250,6 +692,22 @@ func (s *orderServiceImpl) ListOrders(ctx context.Context, req ListOrdersRequest
 		return nil, err
 	}
 
+	// Determine whether there is a next page
+	hasMore := false
+	if opts.After != nil {
+		hasMore = len(orders) > pageSize
+		if hasMore {
+			orders = orders[:pageSize]
+		}
+	} else {
+		hasMore = int64(offset+len(orders)) < totalCount
+	}
+
+	var nextCursor string
+	if hasMore && len(orders) > 0 {
+		nextCursor = domain.CursorAfter(orders[len(orders)-1]).Encode()
+	}
+
 	// Calculate total pages
 	totalPages := int(math.Ceil(float64(totalCount) / float64(pageSize)))
 
@@ -259,9 +717,50 @@ func (s *orderServiceImpl) ListOrders(ctx context.Context, req ListOrdersRequest
 		PageSize:   pageSize,
 		TotalCount: totalCount,
 		TotalPages: totalPages,
+		NextCursor: nextCursor,
 	}, nil
 }
 
+// applyListFilters validates the filters of req and sets the total bounds
+// on opts. It returns a *domain.FilterError for the first invalid one.
+func (s *orderServiceImpl) applyListFilters(opts *repository.ListOptions, req ListOrdersRequest) error {
+	if req.Status != nil && !slices.Contains(domain.ValidStatuses(), *req.Status) {
+		return &domain.FilterError{Field: "status", Value: string(*req.Status), Reason: "is not an order status"}
+	}
+	if req.CreatedAfter != nil && req.CreatedBefore != nil && !req.CreatedAfter.Before(*req.CreatedBefore) {
+		return &domain.FilterError{
+			Field:  "created_after",
+			Value:  req.CreatedAfter.Format(time.RFC3339),
+			Reason: "must be before created_before",
+		}
+	}
+
+	var err error
+	if opts.MinTotal, err = s.parseTotalFilter("min_total", req.MinTotal); err != nil {
+		return err
+	}
+	if opts.MaxTotal, err = s.parseTotalFilter("max_total", req.MaxTotal); err != nil {
+		return err
+	}
+	if opts.MinTotal != nil && opts.MaxTotal != nil && opts.MinTotal.Amount > opts.MaxTotal.Amount {
+		return &domain.FilterError{Field: "min_total", Value: req.MinTotal, Reason: "must not exceed max_total"}
+	}
+	return nil
+}
+
+// parseTotalFilter parses the total bound named field, which is nil if
+// value is empty.
+func (s *orderServiceImpl) parseTotalFilter(field, value string) (*domain.Money, error) {
+	if value == "" {
+		return nil, nil
+	}
+	total, err := domain.ParseMoney(value, s.baseCurrency)
+	if err != nil || total.Amount < 0 {
+		return nil, &domain.FilterError{Field: field, Value: value, Reason: "is not a non-negative amount of " + s.baseCurrency}
+	}
+	return &total, nil
+}
+
 // UpdateOrderStatus transitions an order to a new status.
 // Uses optimistic locking - returns ErrConcurrentModification if the order
 // was modified by another process between read and write.
@@ -277,36 +776,225 @@ func (s *orderServiceImpl) UpdateOrderStatus(ctx context.Context, id string, new
 		return nil, domain.ErrOrderNotFound
 	}
 
-	// Validate status transition
-	if !order.Status.CanTransitionTo(newStatus) {
-		return nil, domain.ErrInvalidTransition
-	}
-
 	// Capture old status before mutation
 	oldStatus := order.Status
 
-	// Update status
-	order.Status = newStatus
-	order.UpdatedAt = time.Now()
+	// Validate and apply the transition before anything is persisted or
+	// published, so illegal transitions never emit an event
+	if err := order.TransitionTo(newStatus, s.clock.Now()); err != nil {
+		return nil, err
+	}
 
-	// Save to repository
-	if err := s.repo.Update(ctx, order); err != nil {
+	// Save to repository with the status change, then publish event.
+	// Cancellations get their own event type instead of status_changed, so
+	// consumers see only one
+	ctx, change := newStatusChange(ctx, order, oldStatus, order.UpdatedAt)
+	eventType := messaging.EventOrderStatusChanged
+	publish := func(ctx context.Context) error {
+		return s.publisher.PublishOrderStatusChanged(ctx, order, oldStatus, newStatus)
+	}
+	if newStatus == domain.OrderStatusCancelled {
+		eventType = messaging.EventOrderCancelled
+		publish = func(ctx context.Context) error {
+			return s.publisher.PublishOrderCancelled(ctx, order, "")
+		}
+	}
+	save := func(ctx context.Context) error { return s.repo.UpdateStatus(ctx, order, change) }
+	if err := s.saveTransition(ctx, order, oldStatus, eventType, save, publish); err != nil {
 		return nil, err
 	}
 
-	// Publish event (warn + continue on failure)
-	if s.publisher != nil {
-		if err := s.publisher.PublishOrderStatusChanged(ctx, order, oldStatus, newStatus); err != nil {
-			slog.Warn("failed to publish order.status_changed event", slog.String("order_id", order.ID.String()), slog.String("error", err.Error()))
+	// Invalidate cache
+	if s.cache != nil {
+		if err := s.cache.Delete(ctx, id); err != nil {
+			s.logger.Warn("cache delete failed", slog.String("order_id", id), slog.String("error", err.Error()))
+		}
+	}
+
+	return order, nil
+}
+
+// GetOrderHistory returns the status changes of an order, oldest first.
+func (s *orderServiceImpl) GetOrderHistory(ctx context.Context, id string) ([]domain.StatusChange, error) {
+	return s.repo.GetHistory(ctx, id)
+}
+
+// GetOrderSnapshot returns the committed state of an order as a snapshot
+// event.
+func (s *orderServiceImpl) GetOrderSnapshot(ctx context.Context, id string) (messaging.OrderEvent, error) {
+	// The cache may lag the database, so always read the repository
+	order, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return messaging.OrderEvent{}, err
+	}
+	if order == nil {
+		return messaging.OrderEvent{}, domain.ErrOrderNotFound
+	}
+	return messaging.NewSnapshotEvent(order), nil
+}
+
+// newStatusChange returns the change of order from oldStatus to its current
+// status, made at now by the actor on ctx, and ctx pinning that time as the
+// OccurredAt of the event published for it. Times are truncated to the
+// microsecond PostgreSQL stores, so the history row and the event match.
+func newStatusChange(ctx context.Context, order *domain.Order, oldStatus domain.OrderStatus, now time.Time) (context.Context, domain.StatusChange) {
+	change := domain.StatusChange{
+		OrderID:    order.ID,
+		OldStatus:  oldStatus,
+		NewStatus:  order.Status,
+		ChangedBy:  domain.ActorFromContext(ctx),
+		OccurredAt: now.UTC().Truncate(time.Microsecond),
+	}
+	return messaging.WithOccurredAt(ctx, change.OccurredAt), change
+}
+
+// nextEvent returns ctx pinning a new EventID for the event published for
+// a change of order, caused by the order's previous event unless ctx names
+// another cause. The new ID is recorded on order, for the save to store as
+// the cause of the order's next event.
+func nextEvent(ctx context.Context, order *domain.Order) context.Context {
+	if _, ok := messaging.CausationID(ctx); !ok {
+		ctx = messaging.WithCausationID(ctx, order.LastEventID)
+	}
+	order.LastEventID = uuid.NewString()
+	return messaging.WithEventID(ctx, order.LastEventID)
+}
+
+// saveTransition saves and publishes the move of order from oldStatus,
+// reserving its stock first if it is being confirmed. A failed reservation
+// rejects the transition before anything is saved or published, and a
+// reservation whose save fails is released again. Cancelling an order that
+// holds stock releases it once the cancellation is saved; a failed release
+// is logged, since the order is cancelled either way.
+func (s *orderServiceImpl) saveTransition(ctx context.Context, order *domain.Order, oldStatus domain.OrderStatus, eventType string, save, publish func(context.Context) error) error {
+	reserve := order.Status == domain.OrderStatusConfirmed
+	if reserve {
+		if err := s.inventory.Reserve(ctx, order.Items); err != nil {
+			return fmt.Errorf("%w: %w", domain.ErrReservationFailed, err)
+		}
+	}
+	if err := s.saveAndPublish(ctx, order, eventType, save, publish); err != nil {
+		if reserve {
+			s.release(ctx, order)
 		}
+		return err
+	}
+	if order.Status == domain.OrderStatusCancelled && holdsStock(oldStatus) {
+		s.release(ctx, order)
+	}
+	return nil
+}
+
+// holdsStock reports whether an order in status has its stock reserved.
+func holdsStock(status domain.OrderStatus) bool {
+	return status == domain.OrderStatusConfirmed || status == domain.OrderStatusProcessing
+}
+
+// release releases the stock held for order, logging any failure.
+func (s *orderServiceImpl) release(ctx context.Context, order *domain.Order) {
+	if err := s.inventory.Release(context.WithoutCancel(ctx), order.Items); err != nil {
+		s.logger.Warn("inventory release failed", slog.String("order_id", order.ID.String()), slog.String("error", err.Error()))
+	}
+}
+
+// CancelOrder cancels an order, recording reason on it and its event.
+func (s *orderServiceImpl) CancelOrder(ctx context.Context, id, reason string) (*domain.Order, error) {
+	order, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if order == nil {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	// Rejects shipped, delivered and already cancelled orders before
+	// anything is persisted or published
+	oldStatus := order.Status
+	if err := order.Cancel(reason, s.clock.Now()); err != nil {
+		return nil, err
+	}
+
+	ctx, change := newStatusChange(ctx, order, oldStatus, order.UpdatedAt)
+	err = s.saveTransition(ctx, order, oldStatus, messaging.EventOrderCancelled,
+		func(ctx context.Context) error { return s.repo.UpdateStatus(ctx, order, change) },
+		func(ctx context.Context) error { return s.publisher.PublishOrderCancelled(ctx, order, reason) },
+	)
+	if err != nil {
+		return nil, err
 	}
 
 	// Invalidate cache
 	if s.cache != nil {
 		if err := s.cache.Delete(ctx, id); err != nil {
-			slog.Warn("cache delete failed", slog.String("order_id", id), slog.String("error", err.Error()))
+			s.logger.Warn("cache delete failed", slog.String("order_id", id), slog.String("error", err.Error()))
 		}
 	}
 
 	return order, nil
 }
+
+// withinQuota returns save, which creates an order for customerID,
+// preceded by the order quota check in the same transaction, if a quota is
+// set.
+func (s *orderServiceImpl) withinQuota(customerID string, save func(context.Context) error) func(context.Context) error {
+	return s.withinQuotas([]string{customerID}, save)
+}
+
+// withinQuotas is withinQuota for save creating one order for each entry
+// of customerIDs. Each customer is locked and counted in turn, in sorted
+// order so that concurrent batches cannot deadlock, and save fails with a
+// *domain.QuotaError if the orders would take any of them over the quota.
+func (s *orderServiceImpl) withinQuotas(customerIDs []string, save func(context.Context) error) func(context.Context) error {
+	if s.quota < 1 {
+		return save
+	}
+	creating := make(map[string]int)
+	for _, id := range customerIDs {
+		creating[id]++
+	}
+	return func(ctx context.Context) error {
+		return s.quotaTx.WithinTx(ctx, func(ctx context.Context) error {
+			for _, id := range slices.Sorted(maps.Keys(creating)) {
+				open, err := s.repo.LockCustomerAndCountOpen(ctx, id)
+				if err != nil {
+					return err
+				}
+				if open+creating[id] > s.quota {
+					return &domain.QuotaError{CustomerID: id, Limit: s.quota}
+				}
+			}
+			return save(ctx)
+		})
+	}
+}
+
+// saveAndPublish persists an order change and then publishes its event.
+// With a transactor, both run in one transaction and a publish failure rolls
+// back the save. Otherwise publish failures are logged and never returned to
+// the caller (ADR-0006).
+func (s *orderServiceImpl) saveAndPublish(ctx context.Context, order *domain.Order, eventType string, save, publish func(context.Context) error) error {
+	// Events occur at the service's time unless the change already pinned it
+	if _, ok := messaging.OccurredAt(ctx); !ok {
+		ctx = messaging.WithOccurredAt(ctx, s.clock.Now())
+	}
+	ctx = nextEvent(ctx, order)
+	if s.transactor != nil {
+		return s.transactor.WithinTx(ctx, func(ctx context.Context) error {
+			if err := save(ctx); err != nil {
+				return err
+			}
+			return publish(ctx)
+		})
+	}
+
+	if err := save(ctx); err != nil {
+		return err
+	}
+
+	// Publish event (warn + continue on failure)
+	if err := publish(ctx); err != nil {
+		s.logger.Warn("failed to publish "+eventType+" event", slog.String("order_id", order.ID.String()), slog.String("error", err.Error()))
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+)
+
+// PaymentAuthorizer holds the payment for an order. CheckoutSaga
+// authorizes an order's payment before confirming it and voids the
+// authorization if the checkout fails after it.
+type PaymentAuthorizer interface {
+	// Authorize holds the order's total, returning an ID for the
+	// authorization.
+	Authorize(ctx context.Context, order *domain.Order) (authorizationID string, err error)
+	// Void releases the authorization. A resumed checkout may void the
+	// same authorization again.
+	Void(ctx context.Context, order *domain.Order, authorizationID string) error
+}
+
+// noopAuthorizer authorizes every payment. It is the default
+// PaymentAuthorizer, for deployments that take payment elsewhere.
+type noopAuthorizer struct{}
+
+func (noopAuthorizer) Authorize(context.Context, *domain.Order) (string, error) { return "", nil }
+func (noopAuthorizer) Void(context.Context, *domain.Order, string) error        { return nil }
+
+// WithPaymentAuthorizer authorizes payments with p when a CheckoutSaga
+// checks out an order. Defaults to authorizing every payment.
+func WithPaymentAuthorizer(p PaymentAuthorizer) Option {
+	return func(s *orderServiceImpl) {
+		s.payments = p
+	}
+}
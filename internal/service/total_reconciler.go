@@ -0,0 +1,218 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/cache"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging/noop"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
+)
+
+const defaultReconcileBatchSize = 100
+
+// ReconcileActor is the actor recorded on the changes the reconciler makes.
+const ReconcileActor = "system:reconcile"
+
+// TotalMismatch is an order whose stored Total is not the sum of its items.
+type TotalMismatch struct {
+	OrderID  string
+	Recorded domain.Money
+	Expected domain.Money
+	// Fixed reports whether Total was corrected to Expected. It is false in
+	// dry-run mode, and when the order changed during the scan.
+	Fixed bool
+}
+
+// ReconcileReport is the outcome of a ReconcileTotals run.
+type ReconcileReport struct {
+	Scanned    int
+	Mismatches []TotalMismatch
+	// Cursor is positioned after the last order the run finished with, or
+	// nil if it finished none. Pass it to WithReconcileFrom to resume a run
+	// that failed or was cancelled.
+	Cursor *domain.Cursor
+}
+
+// TotalReconciler finds orders whose Total does not match the sum of their
+// items, as left behind by past bugs, and optionally corrects them.
+//
+// Orders are scanned newest first, in batches, by the listing's keyset
+// order. A correction is saved with optimistic locking and published as an
+// order.updated event changing "total", in one transaction. Orders whose
+// items are priced in another currency cannot be summed and are skipped
+// with a warning.
+type TotalReconciler struct {
+	repo       repository.OrderRepository
+	transactor repository.Transactor
+	cache      cache.OrderCache
+	publisher  EventPublisher
+	batchSize  int
+	fix        bool
+	from       *domain.Cursor
+	clock      messaging.Clock
+	logger     *slog.Logger
+}
+
+// ReconcileOption configures optional TotalReconciler settings
+type ReconcileOption func(*TotalReconciler)
+
+// WithReconcileBatchSize sets how many orders are read per query.
+// Defaults to 100.
+func WithReconcileBatchSize(n int) ReconcileOption {
+	return func(r *TotalReconciler) {
+		if n > 0 {
+			r.batchSize = n
+		}
+	}
+}
+
+// WithReconcileFix makes ReconcileTotals correct the mismatches it finds.
+// By default it runs dry, only reporting them.
+func WithReconcileFix() ReconcileOption {
+	return func(r *TotalReconciler) {
+		r.fix = true
+	}
+}
+
+// WithReconcileFrom starts the scan after cursor, the Cursor of an earlier
+// run's report.
+func WithReconcileFrom(cursor domain.Cursor) ReconcileOption {
+	return func(r *TotalReconciler) {
+		r.from = &cursor
+	}
+}
+
+// WithReconcileClock sets the clock that stamps the corrections'
+// UpdatedAt. Defaults to messaging.SystemClock.
+func WithReconcileClock(c messaging.Clock) ReconcileOption {
+	return func(r *TotalReconciler) {
+		r.clock = c
+	}
+}
+
+// WithReconcileLogger sets where the orders that cannot be reconciled, and
+// cache errors, are logged. Defaults to logging nothing.
+func WithReconcileLogger(logger *slog.Logger) ReconcileOption {
+	return func(r *TotalReconciler) {
+		r.logger = logger
+	}
+}
+
+// NewTotalReconciler creates a reconciler over the orders in repo.
+// orderCache may be nil.
+func NewTotalReconciler(repo repository.OrderRepository, transactor repository.Transactor, orderCache cache.OrderCache, publisher EventPublisher, opts ...ReconcileOption) *TotalReconciler {
+	r := &TotalReconciler{
+		repo:       repo,
+		transactor: transactor,
+		cache:      orderCache,
+		publisher:  noop.OrNoop(publisher),
+		batchSize:  defaultReconcileBatchSize,
+		clock:      messaging.SystemClock{},
+		logger:     discardLogger,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// ReconcileTotals scans every order not deleted, reporting those whose
+// Total differs from the sum of their items and, with WithReconcileFix,
+// correcting them. It stops at the first error, returning the report so
+// far, whose Cursor resumes the scan.
+func (r *TotalReconciler) ReconcileTotals(ctx context.Context) (ReconcileReport, error) {
+	report := ReconcileReport{Cursor: r.from}
+	for {
+		orders, _, err := r.repo.List(ctx, repository.ListOptions{Limit: r.batchSize, After: report.Cursor})
+		if err != nil {
+			return report, fmt.Errorf("reconcile totals: %w", err)
+		}
+		for _, order := range orders {
+			if err := r.reconcile(ctx, order, &report); err != nil {
+				return report, fmt.Errorf("reconcile total of order %s: %w", order.ID, err)
+			}
+			report.Scanned++
+			cursor := domain.CursorAfter(order)
+			report.Cursor = &cursor
+		}
+		if len(orders) < r.batchSize {
+			return report, nil
+		}
+	}
+}
+
+// reconcile checks one order's total, adding it to report if it is wrong
+// and fixing it if enabled.
+func (r *TotalReconciler) reconcile(ctx context.Context, order *domain.Order, report *ReconcileReport) error {
+	expected, err := order.CalculateTotal()
+	if err != nil {
+		r.logger.Warn("cannot reconcile order total", slog.String("order_id", order.ID.String()), slog.String("error", err.Error()))
+		return nil
+	}
+	if expected.Amount == order.Total.Amount {
+		return nil
+	}
+
+	mismatch := TotalMismatch{OrderID: order.ID.String(), Recorded: order.Total, Expected: expected}
+	if r.fix {
+		err := r.correct(ctx, order)
+		switch {
+		case errors.Is(err, domain.ErrVersionConflict), errors.Is(err, domain.ErrOrderNotFound):
+			// Changed or deleted since it was read; the change saved its own total
+		case err != nil:
+			return err
+		default:
+			mismatch.Fixed = true
+		}
+	}
+	report.Mismatches = append(report.Mismatches, mismatch)
+	return nil
+}
+
+// correct saves order with its total recalculated and publishes the change.
+func (r *TotalReconciler) correct(ctx context.Context, order *domain.Order) error {
+	prev := *order
+	prev.Items = slices.Clone(order.Items)
+	if _, err := order.RecalculateTotal(); err != nil {
+		return err
+	}
+	changes := messaging.NewChanges(&prev, order)
+	order.UpdatedAt = r.clock.Now()
+	ctx = nextEvent(domain.WithActor(ctx, ReconcileActor), order)
+	err := r.transactor.WithinTx(ctx, func(ctx context.Context) error {
+		if err := r.repo.Update(ctx, order); err != nil {
+			return err
+		}
+		return r.publisher.PublishOrderUpdated(ctx, order, changes)
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cache != nil {
+		if err := r.cache.Delete(ctx, order.ID.String()); err != nil {
+			r.logger.Warn("cache delete failed", slog.String("order_id", order.ID.String()), slog.String("error", err.Error()))
+		}
+	}
+	return nil
+}
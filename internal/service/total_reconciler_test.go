@@ -0,0 +1,215 @@
+// Copyright 2026 go-ordersvc Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/mocks"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// orderWithTotal returns an order of two items summing to 25.00, created
+// age before expiryNow, with total recorded as its Total.
+func orderWithTotal(total int64, age time.Duration) *domain.Order {
+	return &domain.Order{
+		ID:         uuid.New(),
+		CustomerID: "cust-1",
+		Status:     domain.OrderStatusPending,
+		Items: []domain.OrderItem{
+			{ProductID: "p-1", Name: "Widget", Quantity: 2, Price: domain.Money{Amount: 1000, Currency: "USD"}},
+			{ProductID: "p-2", Name: "Gadget", Quantity: 1, Price: domain.Money{Amount: 500, Currency: "USD"}},
+		},
+		Total:     domain.Money{Amount: total, Currency: "USD"},
+		Version:   1,
+		CreatedAt: expiryNow.Add(-age),
+	}
+}
+
+// listing returns a List func paging through orders, which must be sorted
+// newest first, by keyset cursor, and records the calls' options.
+func listing(orders []*domain.Order, calls *[]repository.ListOptions) func(context.Context, repository.ListOptions) ([]*domain.Order, int64, error) {
+	return func(_ context.Context, opts repository.ListOptions) ([]*domain.Order, int64, error) {
+		*calls = append(*calls, opts)
+		start := 0
+		if opts.After != nil {
+			for start < len(orders) && !orders[start].CreatedAt.Before(opts.After.CreatedAt) {
+				start++
+			}
+		}
+		end := min(start+opts.Limit, len(orders))
+		return orders[start:end], int64(len(orders)), nil
+	}
+}
+
+func TestTotalReconciler_MatchingOrder_NoAction(t *testing.T) {
+	orders := []*domain.Order{orderWithTotal(2500, time.Hour)}
+	var calls []repository.ListOptions
+	repo := &mocks.OrderRepositoryMock{
+		ListFunc: listing(orders, &calls),
+		UpdateFunc: func(context.Context, *domain.Order) error {
+			t.Fatal("a matching order must not be saved")
+			return nil
+		},
+	}
+	tx := &txRecorder{}
+	r := NewTotalReconciler(repo, tx, nil, &mocks.EventPublisherMock{}, WithReconcileFix())
+
+	report, err := r.ReconcileTotals(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Scanned)
+	assert.Empty(t, report.Mismatches)
+	assert.Zero(t, tx.txs)
+}
+
+func TestTotalReconciler_MismatchedOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []ReconcileOption
+		wantFixed bool
+	}{
+		{"dry_run_reports_only", nil, false},
+		{"fix_corrects_and_publishes", []ReconcileOption{WithReconcileFix()}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := orderWithTotal(2000, time.Hour)
+			var calls []repository.ListOptions
+			var saved []domain.Money
+			var stamped []time.Time
+			var published []map[string]messaging.ChangePair
+			var evicted []string
+			tx := &txRecorder{}
+			repo := &mocks.OrderRepositoryMock{
+				ListFunc: listing([]*domain.Order{order}, &calls),
+				UpdateFunc: func(_ context.Context, o *domain.Order) error {
+					assert.True(t, tx.in, "the fix must be saved in a transaction")
+					saved = append(saved, o.Total)
+					stamped = append(stamped, o.UpdatedAt)
+					return nil
+				},
+			}
+			pub := &mocks.EventPublisherMock{
+				PublishOrderUpdatedFunc: func(ctx context.Context, _ *domain.Order, changed map[string]messaging.ChangePair) error {
+					assert.True(t, tx.in, "the event must be published with the fix")
+					assert.Equal(t, ReconcileActor, domain.ActorFromContext(ctx))
+					published = append(published, changed)
+					return nil
+				},
+			}
+			orderCache := &mocks.OrderCacheMock{
+				DeleteFunc: func(_ context.Context, id string) error {
+					evicted = append(evicted, id)
+					return nil
+				},
+			}
+			opts := append([]ReconcileOption{WithReconcileClock(&fakeClock{now: expiryNow})}, tt.opts...)
+			r := NewTotalReconciler(repo, tx, orderCache, pub, opts...)
+
+			report, err := r.ReconcileTotals(context.Background())
+
+			require.NoError(t, err)
+			want := TotalMismatch{
+				OrderID:  order.ID.String(),
+				Recorded: domain.Money{Amount: 2000, Currency: "USD"},
+				Expected: domain.Money{Amount: 2500, Currency: "USD"},
+				Fixed:    tt.wantFixed,
+			}
+			assert.Equal(t, []TotalMismatch{want}, report.Mismatches)
+			if !tt.wantFixed {
+				assert.Empty(t, saved)
+				assert.Empty(t, published)
+				assert.Equal(t, int64(2000), order.Total.Amount, "the order is untouched")
+				return
+			}
+			assert.Equal(t, []domain.Money{{Amount: 2500, Currency: "USD"}}, saved)
+			assert.Equal(t, []time.Time{expiryNow}, stamped, "UpdatedAt comes from the clock")
+			assert.Equal(t, []map[string]messaging.ChangePair{
+				{"total": {Old: []byte("2000"), New: []byte("2500")}},
+			}, published)
+			assert.Equal(t, []string{order.ID.String()}, evicted)
+		})
+	}
+}
+
+func TestTotalReconciler_ConcurrentlyChangedOrder_ReportedUnfixed(t *testing.T) {
+	order := orderWithTotal(2000, time.Hour)
+	var calls []repository.ListOptions
+	repo := &mocks.OrderRepositoryMock{
+		ListFunc: listing([]*domain.Order{order}, &calls),
+		UpdateFunc: func(context.Context, *domain.Order) error {
+			return domain.ErrVersionConflict
+		},
+	}
+	r := NewTotalReconciler(repo, &txRecorder{}, nil, &mocks.EventPublisherMock{}, WithReconcileFix())
+
+	report, err := r.ReconcileTotals(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, report.Mismatches, 1)
+	assert.False(t, report.Mismatches[0].Fixed)
+}
+
+func TestTotalReconciler_ScansInBatchesAndResumes(t *testing.T) {
+	orders := []*domain.Order{
+		orderWithTotal(2500, 1*time.Hour),
+		orderWithTotal(100, 2*time.Hour),
+		orderWithTotal(2500, 3*time.Hour),
+		orderWithTotal(200, 4*time.Hour),
+		orderWithTotal(2500, 5*time.Hour),
+	}
+	errDB := errors.New("connection reset")
+	var calls []repository.ListOptions
+	list := listing(orders, &calls)
+	repo := &mocks.OrderRepositoryMock{
+		ListFunc: func(ctx context.Context, opts repository.ListOptions) ([]*domain.Order, int64, error) {
+			if len(calls) == 2 {
+				calls = append(calls, opts)
+				return nil, 0, errDB
+			}
+			return list(ctx, opts)
+		},
+	}
+
+	report, err := NewTotalReconciler(repo, &txRecorder{}, nil, nil, WithReconcileBatchSize(2)).ReconcileTotals(context.Background())
+
+	require.ErrorIs(t, err, errDB)
+	assert.Equal(t, 4, report.Scanned)
+	require.NotNil(t, report.Cursor)
+	assert.Equal(t, domain.CursorAfter(orders[3]), *report.Cursor, "the cursor is after the last order finished")
+	assert.Len(t, report.Mismatches, 2)
+	for _, opts := range calls {
+		assert.Equal(t, 2, opts.Limit)
+	}
+
+	repo.ListFunc = list
+	resumed, err := NewTotalReconciler(repo, &txRecorder{}, nil, nil,
+		WithReconcileBatchSize(2), WithReconcileFrom(*report.Cursor)).ReconcileTotals(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, resumed.Scanned, "only the orders after the cursor")
+	assert.Empty(t, resumed.Mismatches)
+	assert.Equal(t, domain.CursorAfter(orders[4]), *resumed.Cursor)
+}
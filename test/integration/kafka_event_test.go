@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sridharn-code-sandbox/go-ordersvc/internal/domain"
 	"github.com/sridharn-code-sandbox/go-ordersvc/internal/messaging"
 	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
@@ -126,8 +127,9 @@ func TestKafka_CreateOrder_PublishesOrderCreatedEvent(t *testing.T) {
 	assert.Equal(t, messaging.EventOrderCreated, evt.EventType)
 	assert.Equal(t, created.ID, evt.OrderID)
 	assert.Equal(t, customerID, evt.CustomerID)
-	assert.Equal(t, "pending", evt.Status)
+	assert.Equal(t, domain.OrderStatusPending, evt.Status)
 	assert.Equal(t, 45.00, evt.Total)
+	assert.Equal(t, int64(4500), evt.TotalMinor)
 	assert.Empty(t, evt.OldStatus, "order.created should have no old_status")
 	assert.Empty(t, evt.NewStatus, "order.created should have no new_status")
 	assert.False(t, evt.OccurredAt.IsZero())
@@ -158,9 +160,9 @@ func TestKafka_UpdateOrderStatus_PublishesStatusChangedEvent(t *testing.T) {
 
 	assert.Equal(t, messaging.EventOrderStatusChanged, evt.EventType)
 	assert.Equal(t, created.ID, evt.OrderID)
-	assert.Equal(t, "confirmed", evt.Status)
-	assert.Equal(t, "pending", evt.OldStatus)
-	assert.Equal(t, "confirmed", evt.NewStatus)
+	assert.Equal(t, domain.OrderStatusConfirmed, evt.Status)
+	assert.Equal(t, domain.OrderStatusPending, evt.OldStatus)
+	assert.Equal(t, domain.OrderStatusConfirmed, evt.NewStatus)
 }
 
 func TestKafka_OrderLifecycle_ProducesOrderedEvents(t *testing.T) {
@@ -199,12 +201,12 @@ func TestKafka_OrderLifecycle_ProducesOrderedEvents(t *testing.T) {
 	assert.Equal(t, messaging.EventOrderCreated, events[0].EventType, "first event should be order.created")
 
 	assert.Equal(t, messaging.EventOrderStatusChanged, events[1].EventType, "second event should be status_changed")
-	assert.Equal(t, "pending", events[1].OldStatus)
-	assert.Equal(t, "confirmed", events[1].NewStatus)
+	assert.Equal(t, domain.OrderStatusPending, events[1].OldStatus)
+	assert.Equal(t, domain.OrderStatusConfirmed, events[1].NewStatus)
 
 	assert.Equal(t, messaging.EventOrderStatusChanged, events[2].EventType, "third event should be status_changed")
-	assert.Equal(t, "confirmed", events[2].OldStatus)
-	assert.Equal(t, "processing", events[2].NewStatus)
+	assert.Equal(t, domain.OrderStatusConfirmed, events[2].OldStatus)
+	assert.Equal(t, domain.OrderStatusProcessing, events[2].NewStatus)
 }
 
 func TestKafka_MessageKey_IsOrderID(t *testing.T) {
@@ -229,3 +231,73 @@ func TestKafka_MessageKey_IsOrderID(t *testing.T) {
 	assert.Equal(t, created.ID, string(msg.Key),
 		"Kafka message key must be order ID for per-order partition ordering (ADR-0006)")
 }
+
+func TestKafka_StatusHistory_MatchesStatusChangedEvents(t *testing.T) {
+	createReq := CreateOrderRequest{
+		CustomerID: uuid.New().String(),
+		Items: []OrderItem{
+			{ProductID: "history-1", Name: "History Test", Quantity: 1, Price: 15.00},
+		},
+	}
+	createResp, createBody := post(t, "/api/v1/orders", createReq)
+	require.Equal(t, http.StatusCreated, createResp.StatusCode)
+
+	var created OrderResponse
+	require.NoError(t, json.Unmarshal(createBody, &created))
+
+	for _, status := range []string{"confirmed", "processing"} {
+		statusResp, _ := patch(t, "/api/v1/orders/"+created.ID+"/status", UpdateStatusRequest{Status: status})
+		require.Equal(t, http.StatusOK, statusResp.StatusCode)
+	}
+
+	historyResp, historyBody := get(t, "/api/v1/orders/"+created.ID+"/history")
+	require.Equal(t, http.StatusOK, historyResp.StatusCode)
+	var history OrderHistoryResponse
+	require.NoError(t, json.Unmarshal(historyBody, &history))
+	require.Len(t, history.History, 2)
+
+	events := findEvents(t, 15*time.Second, func(e messaging.OrderEvent) bool {
+		return e.OrderID == created.ID && e.EventType == messaging.EventOrderStatusChanged
+	})
+	require.Len(t, events, 2)
+	for i, change := range history.History {
+		assert.Equal(t, string(events[i].OldStatus), change.OldStatus)
+		assert.Equal(t, string(events[i].NewStatus), change.NewStatus)
+		assert.True(t, events[i].OccurredAt.Equal(change.OccurredAt),
+			"history row %d occurred at %v, its event at %v", i, change.OccurredAt, events[i].OccurredAt)
+	}
+}
+
+func TestKafka_OrderEvents_CarryContiguousSequence(t *testing.T) {
+	createReq := CreateOrderRequest{
+		CustomerID: uuid.New().String(),
+		Items: []OrderItem{
+			{ProductID: "seq-1", Name: "Sequence Test", Quantity: 1, Price: 10.00},
+		},
+	}
+	createResp, createBody := post(t, "/api/v1/orders", createReq)
+	require.Equal(t, http.StatusCreated, createResp.StatusCode)
+
+	var created OrderResponse
+	require.NoError(t, json.Unmarshal(createBody, &created))
+
+	updateReq := map[string]interface{}{
+		"items": []OrderItem{{ProductID: "seq-2", Name: "Sequence Test", Quantity: 2, Price: 10.00}},
+	}
+	updateResp, _ := put(t, "/api/v1/orders/"+created.ID, updateReq)
+	require.Equal(t, http.StatusOK, updateResp.StatusCode)
+
+	statusResp, _ := patch(t, "/api/v1/orders/"+created.ID+"/status", UpdateStatusRequest{Status: "confirmed"})
+	require.Equal(t, http.StatusOK, statusResp.StatusCode)
+
+	events := findEvents(t, 15*time.Second, func(e messaging.OrderEvent) bool {
+		return e.OrderID == created.ID
+	})
+
+	require.Len(t, events, 3, "created, updated and status_changed")
+	assert.Equal(t, []string{messaging.EventOrderCreated, messaging.EventOrderUpdated, messaging.EventOrderStatusChanged},
+		[]string{events[0].EventType, events[1].EventType, events[2].EventType})
+	for i, evt := range events {
+		assert.Equal(t, int64(i+1), evt.Seq, "event %d (%s) is out of sequence", i, evt.EventType)
+	}
+}
@@ -19,8 +19,12 @@ package integration
 import (
 	"encoding/json"
 	"net/http"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -31,6 +35,7 @@ import (
 
 type CreateOrderRequest struct {
 	CustomerID string      `json:"customer_id"`
+	Currency   string      `json:"currency,omitempty"`
 	Items      []OrderItem `json:"items"`
 }
 
@@ -39,24 +44,28 @@ type OrderItem struct {
 	Name      string  `json:"name"`
 	Quantity  int     `json:"quantity"`
 	Price     float64 `json:"price"`
+	Currency  string  `json:"currency,omitempty"`
 }
 
 type OrderResponse struct {
 	ID         string `json:"id"`
 	CustomerID string `json:"customer_id"`
 	Items      []struct {
-		ID        string  `json:"id"`
-		ProductID string  `json:"product_id"`
-		Name      string  `json:"name"`
-		Quantity  int     `json:"quantity"`
-		Price     float64 `json:"price"`
-		Subtotal  float64 `json:"subtotal"`
+		ID        string `json:"id"`
+		ProductID string `json:"product_id"`
+		Name      string `json:"name"`
+		Quantity  int    `json:"quantity"`
+		Price     string `json:"price"`
+		Subtotal  string `json:"subtotal"`
 	} `json:"items"`
-	Status    string  `json:"status"`
-	Total     float64 `json:"total"`
-	Version   int     `json:"version"`
-	CreatedAt string  `json:"created_at"`
-	UpdatedAt string  `json:"updated_at"`
+	Status       string  `json:"status"`
+	Total        string  `json:"total"`
+	Currency     string  `json:"currency"`
+	Version      int     `json:"version"`
+	CreatedAt    string  `json:"created_at"`
+	UpdatedAt    string  `json:"updated_at"`
+	DeletedAt    *string `json:"deleted_at"`
+	CancelReason string  `json:"cancel_reason"`
 }
 
 type ListOrdersResponse struct {
@@ -70,9 +79,20 @@ type UpdateStatusRequest struct {
 	Status string `json:"status"`
 }
 
+type OrderHistoryResponse struct {
+	OrderID string `json:"order_id"`
+	History []struct {
+		OldStatus  string    `json:"old_status"`
+		NewStatus  string    `json:"new_status"`
+		ChangedBy  string    `json:"changed_by"`
+		OccurredAt time.Time `json:"occurred_at"`
+	} `json:"history"`
+}
+
 type ErrorResponse struct {
-	Error string `json:"error"`
-	Code  string `json:"code"`
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Details map[string]string `json:"details"`
 }
 
 // Health check tests
@@ -128,10 +148,72 @@ func TestCreateOrder_ValidRequest_Returns201WithLocation(t *testing.T) {
 	assert.Equal(t, req.CustomerID, order.CustomerID)
 	assert.Equal(t, "pending", order.Status)
 	assert.Equal(t, 1, order.Version, "Initial version should be 1 (ADR-0003)")
-	assert.Equal(t, 59.98, order.Total)
+	assert.Equal(t, "59.98", order.Total)
+	assert.Equal(t, "USD", order.Currency, "Defaults to the base currency")
+}
+
+func TestCreateOrder_WithCurrency_PersistsCurrency(t *testing.T) {
+	req := CreateOrderRequest{
+		CustomerID: uuid.New().String(),
+		Currency:   "EUR",
+		Items: []OrderItem{
+			{ProductID: "prod-1", Name: "Test Product", Quantity: 1, Price: 10.00, Currency: "EUR"},
+		},
+	}
+
+	resp, body := post(t, "/api/v1/orders", req)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	getResp, getBody := get(t, resp.Header.Get("Location"))
+	require.Equal(t, http.StatusOK, getResp.StatusCode)
+
+	var order OrderResponse
+	require.NoError(t, json.Unmarshal(getBody, &order))
+	assert.Equal(t, "EUR", order.Currency)
+}
+
+func TestCreateOrder_InvalidCurrency_Returns400(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      CreateOrderRequest
+		wantCode string
+	}{
+		{
+			name: "unknown_code",
+			req: CreateOrderRequest{
+				CustomerID: uuid.New().String(),
+				Currency:   "ABC",
+				Items:      []OrderItem{{ProductID: "prod-1", Name: "Test", Quantity: 1, Price: 10.00}},
+			},
+			wantCode: "INVALID_CURRENCY",
+		},
+		{
+			name: "mixed_item_currencies",
+			req: CreateOrderRequest{
+				CustomerID: uuid.New().String(),
+				Items: []OrderItem{
+					{ProductID: "prod-1", Name: "Test", Quantity: 1, Price: 10.00, Currency: "USD"},
+					{ProductID: "prod-2", Name: "Other", Quantity: 1, Price: 5.00, Currency: "GBP"},
+				},
+			},
+			wantCode: "CURRENCY_MISMATCH",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := post(t, "/api/v1/orders", tt.req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			var errResp ErrorResponse
+			require.NoError(t, json.Unmarshal(body, &errResp))
+			assert.Equal(t, tt.wantCode, errResp.Code)
+		})
+	}
 }
 
-func TestCreateOrder_MissingCustomerID_Returns400(t *testing.T) {
+func TestCreateOrder_MissingCustomerID_Returns422(t *testing.T) {
 	req := CreateOrderRequest{
 		CustomerID: "",
 		Items: []OrderItem{
@@ -141,15 +223,16 @@ func TestCreateOrder_MissingCustomerID_Returns400(t *testing.T) {
 
 	resp, body := post(t, "/api/v1/orders", req)
 
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
 
 	var errResp ErrorResponse
 	err := json.Unmarshal(body, &errResp)
 	require.NoError(t, err)
-	assert.Equal(t, "MISSING_CUSTOMER_ID", errResp.Code)
+	assert.Equal(t, "VALIDATION_FAILED", errResp.Code)
+	assert.Contains(t, errResp.Details, "customer_id")
 }
 
-func TestCreateOrder_EmptyItems_Returns400(t *testing.T) {
+func TestCreateOrder_EmptyItems_Returns422(t *testing.T) {
 	req := CreateOrderRequest{
 		CustomerID: uuid.New().String(),
 		Items:      []OrderItem{},
@@ -157,17 +240,49 @@ func TestCreateOrder_EmptyItems_Returns400(t *testing.T) {
 
 	resp, body := post(t, "/api/v1/orders", req)
 
-	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
 
 	var errResp ErrorResponse
 	err := json.Unmarshal(body, &errResp)
 	require.NoError(t, err)
-	assert.Equal(t, "MISSING_ITEMS", errResp.Code)
+	assert.Equal(t, "VALIDATION_FAILED", errResp.Code)
+	assert.Contains(t, errResp.Details, "items")
 }
 
 // GET /api/v1/orders/:id tests
 // ADR-0002 CONSTRAINT: GET by ID Must Return 404 for Missing Orders
 
+// Runs against a service started with the same ORDER_MAX_OPEN_PER_CUSTOMER
+func TestCreateOrder_ConcurrentCreatesPastQuota_ExactlyOneReturns429(t *testing.T) {
+	limit, err := strconv.Atoi(os.Getenv("ORDER_MAX_OPEN_PER_CUSTOMER"))
+	if err != nil || limit < 1 {
+		t.Skip("ORDER_MAX_OPEN_PER_CUSTOMER not set")
+	}
+	req := CreateOrderRequest{
+		CustomerID: uuid.New().String(),
+		Items:      []OrderItem{{ProductID: "prod-1", Name: "Test Product", Quantity: 1, Price: 10.00}},
+	}
+
+	var wg sync.WaitGroup
+	codes := make(chan int, limit+1)
+	for range limit + 1 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, _ := post(t, "/api/v1/orders", req)
+			codes <- resp.StatusCode
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	byCode := map[int]int{}
+	for code := range codes {
+		byCode[code]++
+	}
+	assert.Equal(t, map[int]int{http.StatusCreated: limit, http.StatusTooManyRequests: 1}, byCode)
+}
+
 func TestGetOrder_ExistingOrder_Returns200(t *testing.T) {
 	// First create an order
 	createReq := CreateOrderRequest{
@@ -195,6 +310,128 @@ func TestGetOrder_ExistingOrder_Returns200(t *testing.T) {
 	assert.Equal(t, createdOrder.CustomerID, order.CustomerID)
 }
 
+func TestUpdateOrder_ReplaceItems_PersistsItemsAndRecomputesTotal(t *testing.T) {
+	createReq := CreateOrderRequest{
+		CustomerID: uuid.New().String(),
+		Items: []OrderItem{
+			{ProductID: "prod-1", Name: "Test", Quantity: 1, Price: 19.99},
+		},
+	}
+	createResp, createBody := post(t, "/api/v1/orders", createReq)
+	require.Equal(t, http.StatusCreated, createResp.StatusCode)
+
+	var created OrderResponse
+	require.NoError(t, json.Unmarshal(createBody, &created))
+
+	updateReq := map[string]interface{}{
+		"items": []OrderItem{
+			{ProductID: "prod-2", Name: "Widget", Quantity: 3, Price: 5.00},
+			{ProductID: "prod-3", Name: "Gadget", Quantity: 2, Price: 7.50},
+		},
+	}
+	resp, _ := put(t, "/api/v1/orders/"+created.ID, updateReq)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Re-read to confirm the items were stored, not just echoed back
+	resp, body := get(t, "/api/v1/orders/"+created.ID)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var order OrderResponse
+	require.NoError(t, json.Unmarshal(body, &order))
+	require.Len(t, order.Items, 2)
+	assert.Equal(t, "prod-2", order.Items[0].ProductID)
+	assert.Equal(t, "15.00", order.Items[0].Subtotal)
+	assert.Equal(t, "prod-3", order.Items[1].ProductID)
+	assert.Equal(t, "15.00", order.Items[1].Subtotal)
+	assert.Equal(t, "30.00", order.Total)
+}
+
+func TestPatchOrder_CustomerOnly_LeavesItemsAndStatus(t *testing.T) {
+	createReq := CreateOrderRequest{
+		CustomerID: uuid.New().String(),
+		Items: []OrderItem{
+			{ProductID: "prod-1", Name: "Test", Quantity: 2, Price: 12.50},
+		},
+	}
+	createResp, createBody := post(t, "/api/v1/orders", createReq)
+	require.Equal(t, http.StatusCreated, createResp.StatusCode)
+
+	var created OrderResponse
+	require.NoError(t, json.Unmarshal(createBody, &created))
+
+	newCustomer := uuid.New().String()
+	resp, _ := patch(t, "/api/v1/orders/"+created.ID, map[string]string{"customer_id": newCustomer})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Re-read so a stale cached copy would fail the test
+	resp, body := get(t, "/api/v1/orders/"+created.ID)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var order OrderResponse
+	require.NoError(t, json.Unmarshal(body, &order))
+	assert.Equal(t, newCustomer, order.CustomerID)
+	require.Len(t, order.Items, 1)
+	assert.Equal(t, "prod-1", order.Items[0].ProductID)
+	assert.Equal(t, "25.00", order.Total)
+	assert.Equal(t, "pending", order.Status)
+	assert.Equal(t, 2, order.Version)
+}
+
+func TestPatchOrder_Cancel_Returns400(t *testing.T) {
+	createReq := CreateOrderRequest{
+		CustomerID: uuid.New().String(),
+		Items: []OrderItem{
+			{ProductID: "prod-1", Name: "Test", Quantity: 1, Price: 10.00},
+		},
+	}
+	createResp, createBody := post(t, "/api/v1/orders", createReq)
+	require.Equal(t, http.StatusCreated, createResp.StatusCode)
+
+	var created OrderResponse
+	require.NoError(t, json.Unmarshal(createBody, &created))
+
+	resp, body := patch(t, "/api/v1/orders/"+created.ID, map[string]string{"status": "cancelled"})
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	var errResp ErrorResponse
+	require.NoError(t, json.Unmarshal(body, &errResp))
+	assert.Equal(t, "INVALID_TRANSITION", errResp.Code)
+}
+
+func TestCreateOrder_DecimalPrices_TotalExact(t *testing.T) {
+	req := CreateOrderRequest{
+		CustomerID: uuid.New().String(),
+		Items: []OrderItem{
+			{ProductID: "prod-1", Name: "Dime", Quantity: 1, Price: 0.10},
+			{ProductID: "prod-2", Name: "Dime", Quantity: 1, Price: 0.10},
+			{ProductID: "prod-3", Name: "Dime", Quantity: 1, Price: 0.10},
+		},
+	}
+
+	resp, body := post(t, "/api/v1/orders", req)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	// Re-read so the total went through the database
+	getResp, getBody := get(t, resp.Header.Get("Location"))
+	require.Equal(t, http.StatusOK, getResp.StatusCode)
+
+	var order OrderResponse
+	require.NoError(t, json.Unmarshal(getBody, &order))
+	assert.Equal(t, "0.30", order.Total)
+	require.Len(t, order.Items, 3)
+	assert.Equal(t, "0.10", order.Items[0].Price)
+}
+
+func TestCreateOrder_SubCentPrice_Returns400(t *testing.T) {
+	resp, body := post(t, "/api/v1/orders", CreateOrderRequest{
+		CustomerID: uuid.New().String(),
+		Items:      []OrderItem{{ProductID: "prod-1", Name: "Test", Quantity: 1, Price: 10.005}},
+	})
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+	assert.Contains(t, string(body), "INVALID_AMOUNT")
+}
+
 func TestGetOrder_NonExistent_Returns404(t *testing.T) {
 	nonExistentID := uuid.New().String()
 
@@ -322,6 +559,52 @@ func TestUpdateOrderStatus_InvalidTransition_Returns400(t *testing.T) {
 	assert.Equal(t, "INVALID_TRANSITION", errResp.Code)
 }
 
+func TestCancelOrder_PendingOrder_Returns200WithReason(t *testing.T) {
+	createReq := CreateOrderRequest{
+		CustomerID: uuid.New().String(),
+		Items:      []OrderItem{{ProductID: "prod-cancel", Name: "To Cancel", Quantity: 1, Price: 5.00}},
+	}
+	createResp, createBody := post(t, "/api/v1/orders", createReq)
+	require.Equal(t, http.StatusCreated, createResp.StatusCode)
+	var createdOrder OrderResponse
+	require.NoError(t, json.Unmarshal(createBody, &createdOrder))
+
+	resp, body := post(t, "/api/v1/orders/"+createdOrder.ID+"/cancel", map[string]string{"reason": "out of stock"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var cancelled OrderResponse
+	require.NoError(t, json.Unmarshal(body, &cancelled))
+	assert.Equal(t, "cancelled", cancelled.Status)
+	assert.Equal(t, "out of stock", cancelled.CancelReason)
+
+	// The reason is persisted
+	_, body = get(t, "/api/v1/orders/"+createdOrder.ID)
+	var fetched OrderResponse
+	require.NoError(t, json.Unmarshal(body, &fetched))
+	assert.Equal(t, "out of stock", fetched.CancelReason)
+}
+
+func TestCancelOrder_ShippedOrder_Returns409(t *testing.T) {
+	createReq := CreateOrderRequest{
+		CustomerID: uuid.New().String(),
+		Items:      []OrderItem{{ProductID: "prod-cancel", Name: "Shipped", Quantity: 1, Price: 5.00}},
+	}
+	createResp, createBody := post(t, "/api/v1/orders", createReq)
+	require.Equal(t, http.StatusCreated, createResp.StatusCode)
+	var createdOrder OrderResponse
+	require.NoError(t, json.Unmarshal(createBody, &createdOrder))
+	for _, status := range []string{"confirmed", "processing", "shipped"} {
+		resp, _ := patch(t, "/api/v1/orders/"+createdOrder.ID+"/status", UpdateStatusRequest{Status: status})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	resp, body := post(t, "/api/v1/orders/"+createdOrder.ID+"/cancel", nil)
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	var errResp ErrorResponse
+	require.NoError(t, json.Unmarshal(body, &errResp))
+	assert.Equal(t, "ORDER_NOT_CANCELLABLE", errResp.Code)
+}
+
 func TestUpdateOrderStatus_NonExistentOrder_Returns404(t *testing.T) {
 	nonExistentID := uuid.New().String()
 	updateReq := UpdateStatusRequest{Status: "confirmed"}
@@ -376,6 +659,84 @@ func TestDeleteOrder_NonExistent_Returns404(t *testing.T) {
 	assert.Equal(t, "ORDER_NOT_FOUND", errResp.Code)
 }
 
+func TestDeleteOrder_ExcludedFromList_VisibleWithIncludeDeleted(t *testing.T) {
+	customerID := uuid.New().String()
+	var created []OrderResponse
+	for i := 0; i < 2; i++ {
+		req := CreateOrderRequest{
+			CustomerID: customerID,
+			Items:      []OrderItem{{ProductID: "prod-soft", Name: "Soft Delete", Quantity: 1, Price: 5.00}},
+		}
+		resp, body := post(t, "/api/v1/orders", req)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var order OrderResponse
+		require.NoError(t, json.Unmarshal(body, &order))
+		created = append(created, order)
+	}
+
+	resp, _ := delete(t, "/api/v1/orders/"+created[0].ID)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	// List skips the deleted order
+	resp, body := get(t, "/api/v1/orders?customer_id="+customerID)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var listResp ListOrdersResponse
+	require.NoError(t, json.Unmarshal(body, &listResp))
+	assert.Equal(t, int64(1), listResp.Total)
+	require.Len(t, listResp.Orders, 1)
+	assert.Equal(t, created[1].ID, listResp.Orders[0].ID)
+
+	// include_deleted returns it with deleted_at set
+	resp, body = get(t, "/api/v1/orders/"+created[0].ID+"?include_deleted=true")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var deleted OrderResponse
+	require.NoError(t, json.Unmarshal(body, &deleted))
+	assert.NotNil(t, deleted.DeletedAt)
+	assert.Equal(t, created[0].Version+1, deleted.Version)
+}
+
+func TestRestoreOrder_DeletedOrder_Returns200AndIsVisibleAgain(t *testing.T) {
+	createReq := CreateOrderRequest{
+		CustomerID: uuid.New().String(),
+		Items:      []OrderItem{{ProductID: "prod-restore", Name: "To Restore", Quantity: 1, Price: 5.00}},
+	}
+	createResp, createBody := post(t, "/api/v1/orders", createReq)
+	require.Equal(t, http.StatusCreated, createResp.StatusCode)
+	var createdOrder OrderResponse
+	require.NoError(t, json.Unmarshal(createBody, &createdOrder))
+
+	resp, _ := delete(t, "/api/v1/orders/"+createdOrder.ID)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	resp, body := post(t, "/api/v1/orders/"+createdOrder.ID+"/restore", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var restored OrderResponse
+	require.NoError(t, json.Unmarshal(body, &restored))
+	assert.Nil(t, restored.DeletedAt)
+	assert.Equal(t, createdOrder.Version+2, restored.Version)
+
+	getResp, _ := get(t, "/api/v1/orders/"+createdOrder.ID)
+	assert.Equal(t, http.StatusOK, getResp.StatusCode)
+}
+
+func TestRestoreOrder_NotDeleted_Returns409(t *testing.T) {
+	createReq := CreateOrderRequest{
+		CustomerID: uuid.New().String(),
+		Items:      []OrderItem{{ProductID: "prod-restore", Name: "Live Order", Quantity: 1, Price: 5.00}},
+	}
+	createResp, createBody := post(t, "/api/v1/orders", createReq)
+	require.Equal(t, http.StatusCreated, createResp.StatusCode)
+	var createdOrder OrderResponse
+	require.NoError(t, json.Unmarshal(createBody, &createdOrder))
+
+	resp, body := post(t, "/api/v1/orders/"+createdOrder.ID+"/restore", nil)
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	var errResp ErrorResponse
+	require.NoError(t, json.Unmarshal(body, &errResp))
+	assert.Equal(t, "ORDER_NOT_DELETED", errResp.Code)
+}
+
 // GET /api/v1/orders?customer_id= tests (ORD-100)
 
 func TestListOrders_CustomerIDFilter_ReturnsOnlyCustomerOrders(t *testing.T) {
@@ -474,6 +835,80 @@ func TestListOrders_CustomerIDWithNoOrders_ReturnsEmptyList(t *testing.T) {
 	assert.Empty(t, listResp.Orders)
 }
 
+// GET /api/v1/orders filter combinations
+
+func TestListOrders_CombinedFilters_ReturnsMatchingOrders(t *testing.T) {
+	customerID := uuid.New().String()
+	before := time.Now().UTC().Add(-time.Second).Format(time.RFC3339)
+	for _, price := range []float64{5.00, 25.00, 80.00} {
+		resp, _ := post(t, "/api/v1/orders", CreateOrderRequest{
+			CustomerID: customerID,
+			Items:      []OrderItem{{ProductID: "prod-filter", Name: "Filter Test", Quantity: 1, Price: price}},
+		})
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+	after := time.Now().UTC().Add(time.Minute).Format(time.RFC3339)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantTotal []string
+	}{
+		{name: "customer", query: "", wantTotal: []string{"80.00", "25.00", "5.00"}},
+		{name: "total_range", query: "&min_total=10&max_total=80.00", wantTotal: []string{"80.00", "25.00"}},
+		{name: "total_and_status", query: "&status=pending&min_total=25", wantTotal: []string{"80.00", "25.00"}},
+		{name: "created_window", query: "&created_after=" + before + "&created_before=" + after, wantTotal: []string{"80.00", "25.00", "5.00"}},
+		{name: "status_excludes_all", query: "&status=shipped", wantTotal: nil},
+		{name: "created_after_excludes_all", query: "&created_after=" + after, wantTotal: nil},
+		{name: "total_excludes_all", query: "&min_total=1000", wantTotal: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := get(t, "/api/v1/orders?customer_id="+customerID+tt.query)
+			require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+			var listResp ListOrdersResponse
+			require.NoError(t, json.Unmarshal(body, &listResp))
+
+			assert.Equal(t, int64(len(tt.wantTotal)), listResp.Total)
+			var totals []string
+			for _, order := range listResp.Orders {
+				assert.Equal(t, customerID, order.CustomerID)
+				totals = append(totals, order.Total)
+			}
+			assert.Equal(t, tt.wantTotal, totals)
+		})
+	}
+}
+
+func TestListOrders_InvalidFilter_Returns400(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantField string
+	}{
+		{name: "unknown_status", query: "status=lost", wantField: "status"},
+		{name: "malformed_created_after", query: "created_after=yesterday", wantField: "created_after"},
+		{name: "malformed_created_before", query: "created_before=2026-13-01", wantField: "created_before"},
+		{name: "malformed_min_total", query: "min_total=ten", wantField: "min_total"},
+		{name: "inverted_total_range", query: "min_total=50&max_total=10", wantField: "min_total"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := get(t, "/api/v1/orders?"+tt.query)
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			var errResp ErrorResponse
+			require.NoError(t, json.Unmarshal(body, &errResp))
+			assert.Equal(t, "INVALID_FILTER", errResp.Code)
+			assert.Equal(t, tt.wantField, errResp.Details["field"])
+		})
+	}
+}
+
 // Full lifecycle test
 
 func TestOrderLifecycle_FullFlow(t *testing.T) {
@@ -118,6 +118,10 @@ func get(t *testing.T, path string) (*http.Response, []byte) {
 	return doRequest(t, http.MethodGet, path, nil)
 }
 
+func put(t *testing.T, path string, body interface{}) (*http.Response, []byte) {
+	return doRequest(t, http.MethodPut, path, body)
+}
+
 func patch(t *testing.T, path string, body interface{}) (*http.Response, []byte) {
 	return doRequest(t, http.MethodPatch, path, body)
 }
@@ -24,7 +24,8 @@ export type OrderStatus =
   | "processing"
   | "shipped"
   | "delivered"
-  | "cancelled";
+  | "cancelled"
+  | "expired";
 
 export interface ListOrdersResponse {
   orders: Order[];
@@ -14,6 +14,7 @@ const STATUSES: OrderStatus[] = [
   "shipped",
   "delivered",
   "cancelled",
+  "expired",
 ];
 
 export default function OrderList() {
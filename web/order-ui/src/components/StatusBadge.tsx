@@ -7,6 +7,7 @@ const colors: Record<OrderStatus, string> = {
   shipped: "bg-purple-100 text-purple-800",
   delivered: "bg-green-100 text-green-800",
   cancelled: "bg-red-100 text-red-800",
+  expired: "bg-gray-100 text-gray-600",
 };
 
 export default function StatusBadge({ status }: { status: OrderStatus }) {